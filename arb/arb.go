@@ -0,0 +1,358 @@
+/*
+Package arb reads and writes [Application Resource Bundle] (ARB) files used by Flutter and Google tools.
+
+ARB messages are ICU MessageFormat messages, they are converted to and from MF2 with [FromICU] and [ToICU].
+The message description is stored in [catalog.Message.Description], other string attributes of
+the message and the "placeholders" object (as JSON) are stored in [catalog.Message.Metadata].
+
+Example:
+
+	{
+	  "@@locale": "en",
+	  "cartItems": "{count, plural, =0 {Cart is empty} one {# item} other {# items}}",
+	  "@cartItems": {
+	    "description": "Number of items in the cart",
+	    "placeholders": {"count": {"type": "int"}}
+	  }
+	}
+
+[Application Resource Bundle]: https://github.com/google/app-resource-bundle
+*/
+package arb
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"golang.org/x/text/language"
+
+	"go.expect.digital/mf2/catalog"
+	"go.expect.digital/mf2/parse"
+	"go.expect.digital/mf2/template"
+)
+
+const (
+	localeKey       = "@@locale"
+	descriptionKey  = "description"
+	placeholdersKey = "placeholders"
+)
+
+// Decode reads the ARB file and converts the messages to MF2. The options are applied to every compiled template.
+// The locale of the catalog is "@@locale", or [language.Und] if not set.
+func Decode(r io.Reader, options ...template.Option) (*catalog.Catalog, error) {
+	errorf := func(format string, args ...any) (*catalog.Catalog, error) {
+		return nil, fmt.Errorf("decode arb: "+format, args...)
+	}
+
+	var file map[string]json.RawMessage
+
+	if err := json.NewDecoder(r).Decode(&file); err != nil {
+		return errorf("%w", err)
+	}
+
+	locale := language.Und
+
+	if raw, ok := file[localeKey]; ok {
+		var s string
+
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return errorf("%s: %w", localeKey, err)
+		}
+
+		tag, err := language.Parse(s)
+		if err != nil {
+			return errorf("%s: %w", localeKey, err)
+		}
+
+		locale = tag
+	}
+
+	c := catalog.New(locale, options...)
+
+	ids := make([]string, 0, len(file))
+	for id := range file {
+		// "@@" global attributes and "@" message attributes
+		if !strings.HasPrefix(id, "@") {
+			ids = append(ids, id)
+		}
+	}
+
+	// the first invalid message in the ID order is reported
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		raw := file[id]
+
+		var text string
+
+		if err := json.Unmarshal(raw, &text); err != nil {
+			return errorf(`message "%s": %w`, id, err)
+		}
+
+		tree, err := FromICU(text)
+		if err != nil {
+			return errorf(`message "%s": %w`, id, err)
+		}
+
+		msg := catalog.Message{ID: id, Text: tree.String()}
+
+		if attributes, ok := file["@"+id]; ok {
+			if err := decodeAttributes(attributes, &msg); err != nil {
+				return errorf(`message "%s": %w`, id, err)
+			}
+		}
+
+		c.SetMessage(msg)
+	}
+
+	return c, nil
+}
+
+// decodeAttributes decodes the "@id" object of the message.
+func decodeAttributes(data []byte, msg *catalog.Message) error {
+	var attributes map[string]json.RawMessage
+
+	if err := json.Unmarshal(data, &attributes); err != nil {
+		return fmt.Errorf("attributes: %w", err)
+	}
+
+	for key, raw := range attributes {
+		var s string
+
+		switch {
+		case key == placeholdersKey:
+			var buf bytes.Buffer
+
+			if err := json.Compact(&buf, raw); err != nil {
+				return fmt.Errorf("%s: %w", key, err)
+			}
+
+			s = buf.String()
+		case json.Unmarshal(raw, &s) != nil:
+			continue // attributes other than strings are not supported
+		}
+
+		if key == descriptionKey {
+			msg.Description = s
+			continue
+		}
+
+		if msg.Metadata == nil {
+			msg.Metadata = make(map[string]string)
+		}
+
+		msg.Metadata[key] = s
+	}
+
+	return nil
+}
+
+// Encode converts the messages to ICU MessageFormat and writes the ARB file.
+// Messages are sorted by ID, each followed by its attributes.
+//
+// If the message has no "placeholders" metadata, placeholders are derived from the variables
+// of the message: :integer is "int", :number is "num", :date, :time and :datetime are "DateTime",
+// other variables are "String".
+func Encode(w io.Writer, c *catalog.Catalog) error {
+	errorf := func(format string, args ...any) error {
+		return fmt.Errorf("encode arb: "+format, args...)
+	}
+
+	file := object{{key: localeKey, value: c.Locale().String()}}
+
+	for _, id := range c.IDs() {
+		msg, _ := c.Message(id)
+
+		tree, err := parse.Parse(msg.Text)
+		if err != nil {
+			return errorf(`message "%s": %w`, id, err)
+		}
+
+		text, err := ToICU(tree)
+		if err != nil {
+			return errorf(`message "%s": %w`, id, err)
+		}
+
+		file = append(file, member{key: id, value: text})
+
+		attributes := object{}
+
+		if msg.Description != "" {
+			attributes = append(attributes, member{key: descriptionKey, value: msg.Description})
+		}
+
+		keys := make([]string, 0, len(msg.Metadata))
+		for key := range msg.Metadata {
+			if key != placeholdersKey {
+				keys = append(keys, key)
+			}
+		}
+
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			attributes = append(attributes, member{key: key, value: msg.Metadata[key]})
+		}
+
+		if raw, ok := msg.Metadata[placeholdersKey]; ok {
+			attributes = append(attributes, member{key: placeholdersKey, value: json.RawMessage(raw)})
+		} else if placeholders := derivePlaceholders(tree); len(placeholders) > 0 {
+			attributes = append(attributes, member{key: placeholdersKey, value: placeholders})
+		}
+
+		if len(attributes) > 0 {
+			file = append(file, member{key: "@" + id, value: attributes})
+		}
+	}
+
+	b, err := encodeJSON(file)
+	if err != nil {
+		return errorf("%w", err)
+	}
+
+	var buf bytes.Buffer
+
+	if err := json.Indent(&buf, b, "", "  "); err != nil {
+		return errorf("%w", err)
+	}
+
+	buf.WriteByte('\n')
+
+	if _, err := buf.WriteTo(w); err != nil {
+		return errorf("%w", err)
+	}
+
+	return nil
+}
+
+// derivePlaceholders returns the placeholders of the message variables in order of appearance.
+func derivePlaceholders(tree parse.AST) object {
+	var placeholders object
+
+	add := func(name, typ string) {
+		for i, p := range placeholders {
+			if p.key == name {
+				if typ != "String" {
+					placeholders[i].value = object{{key: "type", value: typ}}
+				}
+
+				return
+			}
+		}
+
+		placeholders = append(placeholders, member{key: name, value: object{{key: "type", value: typ}}})
+	}
+
+	var walk func(node parse.Node)
+
+	walk = func(node parse.Node) {
+		switch n := node.(type) {
+		case parse.SimpleMessage:
+			for _, part := range n {
+				walk(part)
+			}
+		case parse.ComplexMessage:
+			for _, decl := range n.Declarations {
+				walk(decl)
+			}
+
+			walk(n.ComplexBody)
+		case parse.InputDeclaration:
+			walk(parse.Expression(n))
+		case parse.QuotedPattern:
+			for _, part := range n {
+				walk(part)
+			}
+		case parse.Matcher:
+			for _, selector := range n.Selectors {
+				walk(selector)
+			}
+
+			for _, variant := range n.Variants {
+				walk(variant.QuotedPattern)
+			}
+		case parse.Expression:
+			v, ok := n.Operand.(parse.Variable)
+			if !ok {
+				return
+			}
+
+			typ := "String"
+
+			if f, ok := n.Annotation.(parse.Function); ok {
+				switch f.Identifier.String() {
+				case "integer":
+					typ = "int"
+				case "number":
+					typ = "num"
+				case "date", "time", "datetime":
+					typ = "DateTime"
+				}
+			}
+
+			add(string(v), typ)
+		}
+	}
+
+	walk(tree.Message)
+
+	return placeholders
+}
+
+// member is a single member of the JSON object.
+type member struct {
+	value any
+	key   string
+}
+
+// object is JSON object with members in order.
+type object []member
+
+// MarshalJSON implements [json.Marshaler].
+func (o object) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+
+	buf.WriteByte('{')
+
+	for i, m := range o {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		key, err := encodeJSON(m.key)
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := encodeJSON(m.value)
+		if err != nil {
+			return nil, err
+		}
+
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
+
+// encodeJSON encodes the value without HTML escaping, messages often contain HTML.
+func encodeJSON(v any) ([]byte, error) {
+	var buf bytes.Buffer
+
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+
+	if err := enc.Encode(v); err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
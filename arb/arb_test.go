@@ -0,0 +1,125 @@
+package arb
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"golang.org/x/text/language"
+)
+
+func TestDecodeEncode(t *testing.T) {
+	t.Parallel()
+
+	src := `{
+  "@@locale": "en",
+  "cartItems": "{count, plural, =0 {Cart is empty} one {# item} other {# items}}",
+  "@cartItems": {
+    "description": "Number of items in the cart",
+    "context": "cart",
+    "placeholders": {
+      "count": {
+        "type": "int"
+      }
+    }
+  },
+  "greeting": "Hello, <b>{name}</b>!"
+}
+`
+
+	c, err := Decode(strings.NewReader(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if c.Locale() != language.English {
+		t.Errorf("want '%s', got '%s'", language.English, c.Locale())
+	}
+
+	msg, _ := c.Message("cartItems")
+
+	if want := "Number of items in the cart"; msg.Description != want {
+		t.Errorf("want '%s', got '%s'", want, msg.Description)
+	}
+
+	if want := `{"count":{"type":"int"}}`; msg.Metadata["placeholders"] != want {
+		t.Errorf("want '%s', got '%s'", want, msg.Metadata["placeholders"])
+	}
+
+	got, err := c.Sprint("cartItems", map[string]any{"count": 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := "1 item"; want != got {
+		t.Errorf("want '%s', got '%s'", want, got)
+	}
+
+	got, err = c.Sprint("greeting", map[string]any{"name": "World"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := "Hello, <b>World</b>!"; want != got {
+		t.Errorf("want '%s', got '%s'", want, got)
+	}
+
+	var buf bytes.Buffer
+
+	if err := Encode(&buf, c); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{
+  "@@locale": "en",
+  "cartItems": "{count, plural, =0 {Cart is empty} one {{count, number} item} other {{count, number} items}}",
+  "@cartItems": {
+    "description": "Number of items in the cart",
+    "context": "cart",
+    "placeholders": {
+      "count": {
+        "type": "int"
+      }
+    }
+  },
+  "greeting": "Hello, <b>{name}</b>!",
+  "@greeting": {
+    "placeholders": {
+      "name": {
+        "type": "String"
+      }
+    }
+  }
+}
+`
+
+	if want != buf.String() {
+		t.Errorf("want '%s', got '%s'", want, buf.String())
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	t.Parallel()
+
+	// the first invalid message in the ID order is reported
+	src := `{"b": "{b", "a": "{a", "c": "{c"}`
+
+	for range 10 {
+		if _, err := Decode(strings.NewReader(src)); err == nil || !strings.Contains(err.Error(), `message "a"`) {
+			t.Errorf(`want error of message "a", got '%v'`, err)
+		}
+	}
+
+	for _, src := range []string{
+		`[]`,
+		`{"@@locale": 1}`,
+		`{"@@locale": "!"}`,
+		`{"msg": 1}`,
+		`{"msg": "{name"}`,
+		`{"msg": "text", "@msg": []}`,
+	} {
+		if _, err := Decode(strings.NewReader(src)); err == nil {
+			t.Errorf("%s: want error, got nil", src)
+		}
+	}
+}
@@ -0,0 +1,865 @@
+package arb
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"go.expect.digital/mf2"
+	"go.expect.digital/mf2/internal/literal"
+	"go.expect.digital/mf2/parse"
+)
+
+// ICU MessageFormat message parts.
+type (
+	icuPart any // string, icuArgument, icuPound or icuSelect
+
+	// icuArgument is a simple or formatted argument, e.g. "{name}" or "{count, number, integer}".
+	icuArgument struct {
+		name, typ, style string
+	}
+
+	// icuPound is "#" in a plural case, the plural argument.
+	icuPound struct {
+		name string
+	}
+
+	// icuSelect is a plural, selectordinal or select argument.
+	icuSelect struct {
+		name, typ string
+		cases     []icuCase
+	}
+
+	icuCase struct {
+		key   string
+		parts []icuPart
+	}
+)
+
+// icuParser parses ICU MessageFormat message.
+type icuParser struct {
+	input  []rune
+	plural []string // names of enclosing plural arguments, the last is used by "#"
+	pos    int
+}
+
+/*
+FromICU converts ICU MessageFormat message to MF2 message.
+
+Supported arguments:
+
+	{name}                       -> { $name }
+	{name, number}               -> { $name :number }
+	{name, number, integer}      -> { $name :integer }
+	{name, number, percent}      -> { $name :number style=percent }
+	{name, date[, style]}        -> { $name :date[ style=style] }
+	{name, time[, style]}        -> { $name :time[ style=style] }
+	{name, plural, ...}          -> .input { $name :number } .match { $name }
+	{name, selectordinal, ...}   -> .input { $name :number select=ordinal } .match { $name }
+	{name, select, ...}          -> .input { $name :string } .match { $name }
+
+Plural and select arguments can appear anywhere in the message and can be nested,
+they are converted to a single matcher with a variant for every combination of keys.
+*/
+func FromICU(s string) (parse.AST, error) {
+	p := &icuParser{input: []rune(s)}
+
+	parts, err := p.parseMessage(false)
+	if err != nil {
+		return parse.AST{}, fmt.Errorf("%w: %w", mf2.ErrSyntax, err)
+	}
+
+	if p.pos < len(p.input) {
+		return parse.AST{}, fmt.Errorf("%w: unexpected '%c' at %d", mf2.ErrSyntax, p.input[p.pos], p.pos)
+	}
+
+	var selectors []icuSelect
+
+	if err := collectSelectors(parts, &selectors); err != nil {
+		return parse.AST{}, err
+	}
+
+	if len(selectors) == 0 {
+		pattern, err := icuPattern(parts, nil)
+		if err != nil {
+			return parse.AST{}, err
+		}
+
+		if text, ok := pattern[0].(parse.Text); ok && strings.HasPrefix(string(text), ".") {
+			return parse.AST{Message: parse.ComplexMessage{ComplexBody: parse.QuotedPattern(pattern)}}, nil
+		}
+
+		return parse.AST{Message: parse.SimpleMessage(pattern)}, nil
+	}
+
+	msg := parse.ComplexMessage{}
+	matcher := parse.Matcher{}
+
+	for _, selector := range selectors {
+		msg.Declarations = append(msg.Declarations, parse.InputDeclaration{
+			Operand:    parse.Variable(selector.name),
+			Annotation: selectorFunction(selector.typ),
+		})
+
+		matcher.Selectors = append(matcher.Selectors, parse.Expression{Operand: parse.Variable(selector.name)})
+	}
+
+	// every combination of keys, the last key of every selector is "other"
+	for _, combination := range combinations(selectors) {
+		keys := make(map[string]string, len(selectors))
+		variant := parse.Variant{}
+
+		for i, selector := range selectors {
+			key := selector.cases[combination[i]].key
+			keys[selector.name] = key
+
+			variant.Keys = append(variant.Keys, variantKey(key))
+		}
+
+		pattern, err := icuPattern(parts, keys)
+		if err != nil {
+			return parse.AST{}, err
+		}
+
+		variant.QuotedPattern = pattern
+		matcher.Variants = append(matcher.Variants, variant)
+	}
+
+	msg.ComplexBody = matcher
+
+	return parse.AST{Message: msg}, nil
+}
+
+// combinations returns all combinations of case indexes of the selectors.
+func combinations(selectors []icuSelect) [][]int {
+	n := 1
+	for _, selector := range selectors {
+		n *= len(selector.cases)
+	}
+
+	result := make([][]int, n)
+
+	for i := range n {
+		combination := make([]int, len(selectors))
+		rest := i
+
+		for j := len(selectors) - 1; j >= 0; j-- {
+			combination[j] = rest % len(selectors[j].cases)
+			rest /= len(selectors[j].cases)
+		}
+
+		result[i] = combination
+	}
+
+	return result
+}
+
+// collectSelectors collects the plural and select arguments in order of appearance,
+// merging the cases of the arguments with the same name.
+func collectSelectors(parts []icuPart, selectors *[]icuSelect) error {
+	for _, part := range parts {
+		s, ok := part.(icuSelect)
+		if !ok {
+			continue
+		}
+
+		i := slices.IndexFunc(*selectors, func(v icuSelect) bool { return v.name == s.name })
+		if i == -1 {
+			*selectors = append(*selectors, icuSelect{name: s.name, typ: s.typ})
+			i = len(*selectors) - 1
+		}
+
+		if typ := (*selectors)[i].typ; typ != s.typ {
+			return fmt.Errorf(`%w: argument "%s" is both %s and %s`, mf2.ErrUnsupportedExpression, s.name, typ, s.typ)
+		}
+
+		for _, c := range s.cases {
+			// index again, nested arguments might have grown the slice
+			if cases := (*selectors)[i].cases; !slices.ContainsFunc(cases, func(v icuCase) bool { return v.key == c.key }) {
+				(*selectors)[i].cases = append(cases, icuCase{key: c.key})
+			}
+
+			if err := collectSelectors(c.parts, selectors); err != nil {
+				return err
+			}
+		}
+
+		// "other" is the last, it is the catch-all key
+		slices.SortStableFunc((*selectors)[i].cases, func(a, b icuCase) int {
+			return boolInt(a.key == "other") - boolInt(b.key == "other")
+		})
+	}
+
+	return nil
+}
+
+func boolInt(b bool) int {
+	if b {
+		return 1
+	}
+
+	return 0
+}
+
+// icuPattern converts the parts to MF2 pattern, keys select the cases of plural and select arguments.
+func icuPattern(parts []icuPart, keys map[string]string) ([]parse.PatternPart, error) {
+	var pattern []parse.PatternPart
+
+	addText := func(s string) {
+		if i := len(pattern) - 1; i >= 0 {
+			if text, ok := pattern[i].(parse.Text); ok {
+				pattern[i] = text + parse.Text(s)
+				return
+			}
+		}
+
+		pattern = append(pattern, parse.Text(s))
+	}
+
+	for _, part := range parts {
+		switch p := part.(type) {
+		case string:
+			addText(p)
+		case icuPound:
+			pattern = append(pattern, parse.Expression{Operand: parse.Variable(p.name)})
+		case icuArgument:
+			expr, err := p.expression()
+			if err != nil {
+				return nil, err
+			}
+
+			pattern = append(pattern, expr)
+		case icuSelect:
+			i := slices.IndexFunc(p.cases, func(c icuCase) bool { return c.key == keys[p.name] })
+			if i == -1 {
+				i = slices.IndexFunc(p.cases, func(c icuCase) bool { return c.key == "other" })
+			}
+
+			casePattern, err := icuPattern(p.cases[i].parts, keys)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, v := range casePattern {
+				if text, ok := v.(parse.Text); ok {
+					addText(string(text))
+				} else {
+					pattern = append(pattern, v)
+				}
+			}
+		}
+	}
+
+	if len(pattern) == 0 {
+		pattern = append(pattern, parse.Text(""))
+	}
+
+	return pattern, nil
+}
+
+// expression converts the simple or formatted argument to MF2 expression.
+func (a icuArgument) expression() (parse.Expression, error) {
+	expr := parse.Expression{Operand: parse.Variable(a.name)}
+
+	function := func(name string, options ...parse.Option) parse.Function {
+		return parse.Function{Identifier: parse.Identifier{Name: name}, Options: options}
+	}
+
+	style := func() []parse.Option {
+		if a.style == "" {
+			return nil
+		}
+
+		return []parse.Option{{Identifier: parse.Identifier{Name: "style"}, Value: parse.NameLiteral(a.style)}}
+	}
+
+	unsupported := fmt.Errorf(`%w: "{%s, %s, %s}"`, mf2.ErrUnsupportedExpression, a.name, a.typ, a.style)
+
+	switch a.typ {
+	default:
+		return parse.Expression{}, unsupported
+	case "":
+	case "number":
+		switch a.style {
+		default:
+			return parse.Expression{}, unsupported
+		case "":
+			expr.Annotation = function("number")
+		case "integer":
+			expr.Annotation = function("integer")
+		case "percent":
+			expr.Annotation = function("number", style()...)
+		}
+	case "date", "time":
+		switch a.style {
+		default:
+			return parse.Expression{}, unsupported
+		case "", "short", "medium", "long", "full":
+			expr.Annotation = function(a.typ, style()...)
+		}
+	}
+
+	return expr, nil
+}
+
+func selectorFunction(typ string) parse.Function {
+	switch typ {
+	default: // plural
+		return parse.Function{Identifier: parse.Identifier{Name: "number"}}
+	case "selectordinal":
+		return parse.Function{
+			Identifier: parse.Identifier{Name: "number"},
+			Options:    []parse.Option{{Identifier: parse.Identifier{Name: "select"}, Value: parse.NameLiteral("ordinal")}},
+		}
+	case "select":
+		return parse.Function{Identifier: parse.Identifier{Name: "string"}}
+	}
+}
+
+func variantKey(key string) parse.VariantKey { //nolint:ireturn
+	if key == "other" {
+		return parse.CatchAllKey{}
+	}
+
+	if s, ok := strings.CutPrefix(key, "="); ok {
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			return parse.NumberLiteral(f)
+		}
+	}
+
+	return parse.NameLiteral(key)
+}
+
+// ---------------------------------ICU parser---------------------------------
+
+func (p *icuParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("at %d: "+format, append([]any{p.pos}, args...)...)
+}
+
+func (p *icuParser) peek() (rune, bool) {
+	if p.pos >= len(p.input) {
+		return 0, false
+	}
+
+	return p.input[p.pos], true
+}
+
+func (p *icuParser) skipWhitespace() {
+	for r, ok := p.peek(); ok && unicode.IsSpace(r); r, ok = p.peek() {
+		p.pos++
+	}
+}
+
+// parseMessage parses text and arguments until the end of input or "}" of the nested message.
+func (p *icuParser) parseMessage(nested bool) ([]icuPart, error) {
+	var (
+		parts []icuPart
+		text  strings.Builder
+	)
+
+	flush := func() {
+		if text.Len() > 0 {
+			parts = append(parts, text.String())
+			text.Reset()
+		}
+	}
+
+	for {
+		r, ok := p.peek()
+		if !ok {
+			if nested {
+				return nil, p.errorf("unclosed message")
+			}
+
+			flush()
+
+			return parts, nil
+		}
+
+		switch {
+		default:
+			text.WriteRune(r)
+			p.pos++
+		case r == '}':
+			if !nested {
+				return nil, p.errorf("unexpected '}'")
+			}
+
+			flush()
+
+			return parts, nil
+		case r == '{':
+			flush()
+
+			arg, err := p.parseArgument()
+			if err != nil {
+				return nil, err
+			}
+
+			parts = append(parts, arg)
+		case r == '#' && len(p.plural) > 0:
+			flush()
+
+			parts = append(parts, icuPound{name: p.plural[len(p.plural)-1]})
+			p.pos++
+		case r == '\'':
+			text.WriteString(p.parseApostrophe())
+		}
+	}
+}
+
+// parseApostrophe parses the apostrophe, quoted text or doubled apostrophe.
+// The apostrophe starts the quoted text only before a special character.
+func (p *icuParser) parseApostrophe() string {
+	p.pos++ // '
+
+	r, ok := p.peek()
+
+	switch {
+	case !ok:
+		return "'"
+	case r == '\'':
+		p.pos++
+		return "'"
+	case r == '{' || r == '}' || r == '|' || r == '#' && len(p.plural) > 0:
+	default:
+		return "'"
+	}
+
+	var sb strings.Builder
+
+	for r, ok := p.peek(); ok; r, ok = p.peek() {
+		p.pos++
+
+		if r != '\'' {
+			sb.WriteRune(r)
+			continue
+		}
+
+		// doubled apostrophe inside quoted text
+		if next, ok := p.peek(); ok && next == '\'' {
+			sb.WriteRune('\'')
+			p.pos++
+
+			continue
+		}
+
+		break
+	}
+
+	return sb.String()
+}
+
+func (p *icuParser) parseIdentifier() string {
+	start := p.pos
+
+	for r, ok := p.peek(); ok && !unicode.IsSpace(r) && !strings.ContainsRune("{}#',", r); r, ok = p.peek() {
+		p.pos++
+	}
+
+	return string(p.input[start:p.pos])
+}
+
+// expect consumes the rune, surrounding whitespace is skipped.
+func (p *icuParser) expect(want rune) error {
+	if err := p.expectClose(want); err != nil {
+		return err
+	}
+
+	p.skipWhitespace()
+
+	return nil
+}
+
+// expectClose consumes the rune, preceding whitespace is skipped.
+// The whitespace after the closing brace of an argument is part of the text.
+func (p *icuParser) expectClose(want rune) error {
+	p.skipWhitespace()
+
+	if r, ok := p.peek(); !ok || r != want {
+		return p.errorf("want '%c'", want)
+	}
+
+	p.pos++
+
+	return nil
+}
+
+// parseArgument parses "{name}", "{name, type}", "{name, type, style}" or plural and select arguments.
+func (p *icuParser) parseArgument() (icuPart, error) {
+	if err := p.expect('{'); err != nil {
+		return nil, err
+	}
+
+	name := p.parseIdentifier()
+	if name == "" {
+		return nil, p.errorf("missing argument name")
+	}
+
+	p.skipWhitespace()
+
+	if r, _ := p.peek(); r == '}' {
+		p.pos++
+		return icuArgument{name: name}, nil
+	}
+
+	if err := p.expect(','); err != nil {
+		return nil, err
+	}
+
+	typ := p.parseIdentifier()
+
+	switch typ {
+	case "plural", "selectordinal", "select":
+		if err := p.expect(','); err != nil {
+			return nil, err
+		}
+
+		return p.parseSelect(name, typ)
+	}
+
+	p.skipWhitespace()
+
+	var style string
+
+	if r, _ := p.peek(); r == ',' {
+		p.pos++
+		p.skipWhitespace()
+
+		start := p.pos
+
+		for r, ok := p.peek(); ok && r != '}'; r, ok = p.peek() {
+			p.pos++
+		}
+
+		style = strings.TrimSpace(string(p.input[start:p.pos]))
+	}
+
+	if err := p.expectClose('}'); err != nil {
+		return nil, err
+	}
+
+	return icuArgument{name: name, typ: typ, style: style}, nil
+}
+
+// parseSelect parses the cases of the plural or select argument, e.g. "one {# item} other {# items}}".
+func (p *icuParser) parseSelect(name, typ string) (icuPart, error) {
+	s := icuSelect{name: name, typ: typ}
+
+	if typ != "select" {
+		p.plural = append(p.plural, name)
+		defer func() { p.plural = p.plural[:len(p.plural)-1] }()
+	}
+
+	for {
+		p.skipWhitespace()
+
+		if r, ok := p.peek(); !ok || r == '}' {
+			break
+		}
+
+		key := p.parseIdentifier()
+
+		switch {
+		case key == "":
+			return nil, p.errorf("missing %s key", typ)
+		case strings.HasPrefix(key, "offset:"):
+			return nil, fmt.Errorf(`%w: plural offset in "%s"`, mf2.ErrUnsupportedExpression, name)
+		case strings.HasPrefix(key, "=") && typ == "select":
+			return nil, p.errorf(`select key "%s"`, key)
+		}
+
+		if err := p.expect('{'); err != nil {
+			return nil, err
+		}
+
+		parts, err := p.parseMessage(true)
+		if err != nil {
+			return nil, err
+		}
+
+		p.pos++ // }
+
+		s.cases = append(s.cases, icuCase{key: key, parts: parts})
+	}
+
+	if err := p.expectClose('}'); err != nil {
+		return nil, err
+	}
+
+	if !slices.ContainsFunc(s.cases, func(c icuCase) bool { return c.key == "other" }) {
+		return nil, fmt.Errorf(`%w: missing "other" in "%s"`, mf2.ErrMissingFallbackVariant, name)
+	}
+
+	return s, nil
+}
+
+// ---------------------------------MF2 to ICU---------------------------------
+
+/*
+ToICU converts MF2 message to ICU MessageFormat message, see [FromICU] for the supported expressions.
+
+Matchers are converted to nested plural and select arguments, the first selector is the outermost.
+Selectors must be variables annotated with :number, :integer or :string.
+*/
+func ToICU(tree parse.AST) (string, error) {
+	c := &icuConverter{functions: make(map[string]parse.Function)}
+
+	switch m := tree.Message.(type) {
+	default:
+		return "", fmt.Errorf("unsupported message type %T", m)
+	case nil:
+		return "", nil
+	case parse.SimpleMessage:
+		return c.pattern(m, false)
+	case parse.ComplexMessage:
+		for _, decl := range m.Declarations {
+			d, ok := decl.(parse.InputDeclaration)
+			if !ok {
+				return "", fmt.Errorf("%w: %s", mf2.ErrUnsupportedStatement, decl)
+			}
+
+			if f, ok := d.Annotation.(parse.Function); ok {
+				v, _ := d.Operand.(parse.Variable)
+				c.functions[string(v)] = f
+			}
+		}
+
+		switch body := m.ComplexBody.(type) {
+		case parse.QuotedPattern:
+			return c.pattern(body, false)
+		case parse.Matcher:
+			return c.matcher(body.Selectors, body.Variants, false)
+		}
+	}
+
+	return "", errors.New("missing complex body")
+}
+
+type icuConverter struct {
+	functions map[string]parse.Function // variable -> function of .input declaration
+}
+
+// function returns the variable name and the function of the variable expression.
+func (c *icuConverter) function(expr parse.Expression) (string, parse.Function, bool, error) {
+	v, ok := expr.Operand.(parse.Variable)
+	if !ok {
+		return "", parse.Function{}, false, fmt.Errorf("%w: want variable: %s", mf2.ErrUnsupportedExpression, expr)
+	}
+
+	switch a := expr.Annotation.(type) {
+	default:
+		return "", parse.Function{}, false, fmt.Errorf("%w: %s", mf2.ErrUnsupportedExpression, expr)
+	case nil:
+		f, ok := c.functions[string(v)]
+		return string(v), f, ok, nil
+	case parse.Function:
+		return string(v), a, true, nil
+	}
+}
+
+func (c *icuConverter) pattern(pattern []parse.PatternPart, inPlural bool) (string, error) {
+	var sb strings.Builder
+
+	for _, part := range pattern {
+		switch p := part.(type) {
+		default:
+			return "", fmt.Errorf("%w: %s", mf2.ErrUnsupportedExpression, part)
+		case parse.Text:
+			sb.WriteString(icuText(string(p), inPlural))
+		case parse.Expression:
+			if l, ok := p.Operand.(parse.Literal); ok && p.Annotation == nil {
+				sb.WriteString(icuText(literal.Value(l), inPlural))
+				continue
+			}
+
+			arg, err := c.argument(p)
+			if err != nil {
+				return "", err
+			}
+
+			sb.WriteString(arg)
+		}
+	}
+
+	return sb.String(), nil
+}
+
+// argument converts the variable expression to ICU simple or formatted argument.
+func (c *icuConverter) argument(expr parse.Expression) (string, error) {
+	name, f, ok, err := c.function(expr)
+	if err != nil {
+		return "", err
+	}
+
+	if !ok {
+		return "{" + name + "}", nil
+	}
+
+	options := make(map[string]string, len(f.Options))
+
+	for _, o := range f.Options {
+		l, ok := o.Value.(parse.Literal)
+		if !ok {
+			return "", fmt.Errorf("%w: variable option: %s", mf2.ErrUnsupportedExpression, expr)
+		}
+
+		options[o.Identifier.String()] = literal.Value(l)
+	}
+
+	unsupported := fmt.Errorf("%w: %s", mf2.ErrUnsupportedExpression, expr)
+
+	switch f.Identifier.String() {
+	default:
+		return "", unsupported
+	case "string":
+		if len(options) > 0 {
+			return "", unsupported
+		}
+
+		return "{" + name + "}", nil
+	case "integer":
+		if len(options) > 0 {
+			return "", unsupported
+		}
+
+		return "{" + name + ", number, integer}", nil
+	case "number":
+		switch {
+		case len(options) == 0:
+			return "{" + name + ", number}", nil
+		case len(options) == 1 && options["style"] == "percent":
+			return "{" + name + ", number, percent}", nil
+		case len(options) == 1 && options["select"] != "":
+			return "{" + name + ", number}", nil
+		default:
+			return "", unsupported
+		}
+	case "date", "time":
+		switch {
+		case len(options) == 0:
+			return "{" + name + ", " + f.Identifier.Name + "}", nil
+		case len(options) == 1 && options["style"] != "":
+			return "{" + name + ", " + f.Identifier.Name + ", " + options["style"] + "}", nil
+		default:
+			return "", unsupported
+		}
+	}
+}
+
+// matcher converts the matcher to nested plural and select arguments.
+func (c *icuConverter) matcher(selectors []parse.Expression, variants []parse.Variant, inPlural bool) (string, error) {
+	if len(selectors) == 0 {
+		if len(variants) == 0 {
+			return "", fmt.Errorf("%w", mf2.ErrMissingFallbackVariant)
+		}
+
+		return c.pattern(variants[0].QuotedPattern, inPlural)
+	}
+
+	name, f, ok, err := c.function(selectors[0])
+	if err != nil {
+		return "", fmt.Errorf("selector: %w", err)
+	}
+
+	var typ string
+
+	switch {
+	case !ok:
+		return "", fmt.Errorf("%w: %s", mf2.ErrMissingSelectorAnnotation, selectors[0])
+	case f.Identifier.String() == "string":
+		typ = "select"
+	case f.Identifier.String() == "number" || f.Identifier.String() == "integer":
+		typ = "plural"
+
+		for _, o := range f.Options {
+			if o.Identifier.String() == "select" && o.Value.String() == "ordinal" {
+				typ = "selectordinal"
+			}
+		}
+
+		inPlural = true
+	default:
+		return "", fmt.Errorf("%w: selector %s", mf2.ErrUnsupportedExpression, selectors[0])
+	}
+
+	var keys []string
+
+	for _, variant := range variants {
+		if key := icuKey(variant.Keys[0], typ); key != "other" && !slices.Contains(keys, key) {
+			keys = append(keys, key)
+		}
+	}
+
+	keys = append(keys, "other")
+
+	var sb strings.Builder
+
+	sb.WriteString("{" + name + ", " + typ + ",")
+
+	for _, key := range keys {
+		// variants with the exact key are preferred over the catch-all key
+		var matched, fallback []parse.Variant
+
+		for _, variant := range variants {
+			rest := parse.Variant{Keys: variant.Keys[1:], QuotedPattern: variant.QuotedPattern}
+
+			switch icuKey(variant.Keys[0], typ) {
+			case key:
+				matched = append(matched, rest)
+			case "other":
+				fallback = append(fallback, rest)
+			}
+		}
+
+		s, err := c.matcher(selectors[1:], slices.Concat(matched, fallback), inPlural)
+		if err != nil {
+			return "", err
+		}
+
+		sb.WriteString(" " + key + " {" + s + "}")
+	}
+
+	sb.WriteString("}")
+
+	return sb.String(), nil
+}
+
+// icuKey converts the variant key to the plural or select key.
+func icuKey(key parse.VariantKey, typ string) string {
+	switch k := key.(type) {
+	default:
+		return "other"
+	case parse.NumberLiteral:
+		return "=" + k.String()
+	case parse.Literal:
+		s := literal.Value(k)
+
+		if _, err := strconv.ParseFloat(s, 64); err == nil && typ != "select" {
+			return "=" + s
+		}
+
+		return s
+	}
+}
+
+// icuText escapes special characters with apostrophes.
+func icuText(s string, inPlural bool) string {
+	var sb strings.Builder
+
+	for _, r := range s {
+		switch {
+		default:
+			sb.WriteRune(r)
+		case r == '\'':
+			sb.WriteString("''")
+		case r == '{' || r == '}' || r == '#' && inPlural:
+			sb.WriteString("'" + string(r) + "'")
+		}
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,177 @@
+package arb
+
+import (
+	"errors"
+	"testing"
+
+	"go.expect.digital/mf2"
+	"go.expect.digital/mf2/parse"
+)
+
+func TestFromICU(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		name, icu, want string
+	}{
+		{
+			name: "text",
+			icu:  "It''s '{escaped}' 'quoted",
+			want: `It's \{escaped\} 'quoted`,
+		},
+		{
+			name: "text starting with dot",
+			icu:  ".hidden",
+			want: "{{.hidden}}",
+		},
+		{
+			name: "arguments",
+			icu:  "{name} {n, number} {n, number, integer} {n, number, percent} {d, date} {d, time, short}",
+			want: "{ $name } { $n :number } { $n :integer } { $n :number style = percent } { $d :date } { $d :time style = short }",
+		},
+		{
+			name: "plural",
+			icu:  "You have {count, plural, =0 {no items} one {# item} other {# items}} in the cart.",
+			want: ".input { $count :number }\n" +
+				".match { $count }\n" +
+				"0 {{You have no items in the cart.}}\n" +
+				"one {{You have { $count } item in the cart.}}\n" +
+				"* {{You have { $count } items in the cart.}}",
+		},
+		{
+			name: "selectordinal",
+			icu:  "{n, selectordinal, one {#st} two {#nd} few {#rd} other {#th}}",
+			want: ".input { $n :number select = ordinal }\n" +
+				".match { $n }\n" +
+				"one {{{ $n }st}}\n" +
+				"two {{{ $n }nd}}\n" +
+				"few {{{ $n }rd}}\n" +
+				"* {{{ $n }th}}",
+		},
+		{
+			name: "nested",
+			icu:  "{gender, select, female {{n, plural, one {She has one '#'} other {She has #}}} other {They have #{n}}}",
+			want: ".input { $gender :string }\n" +
+				".input { $n :number }\n" +
+				".match { $gender } { $n }\n" +
+				"female one {{She has one #}}\n" +
+				"female * {{She has { $n }}}\n" +
+				"* one {{They have #{ $n }}}\n" +
+				"* * {{They have #{ $n }}}",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			tree, err := FromICU(test.icu)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := tree.String(); test.want != got {
+				t.Errorf("want '%s', got '%s'", test.want, got)
+			}
+
+			if _, err := parse.Parse(tree.String()); err != nil {
+				t.Errorf("want valid MF2, got '%s'", err)
+			}
+		})
+	}
+}
+
+func TestFromICUErrors(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		icu     string
+		wantErr error
+	}{
+		{icu: "{name", wantErr: mf2.ErrSyntax},
+		{icu: "name}", wantErr: mf2.ErrSyntax},
+		{icu: "{}", wantErr: mf2.ErrSyntax},
+		{icu: "{n, plural, one {#}", wantErr: mf2.ErrSyntax},
+		{icu: "{n, plural, one {#}}", wantErr: mf2.ErrMissingFallbackVariant},
+		{icu: "{n, plural, offset:1 other {#}}", wantErr: mf2.ErrUnsupportedExpression},
+		{icu: "{n, number, ::currency/EUR}", wantErr: mf2.ErrUnsupportedExpression},
+		{icu: "{n, spellout}", wantErr: mf2.ErrUnsupportedExpression},
+		{icu: "{n, plural, other {#}} {n, select, other {}}", wantErr: mf2.ErrUnsupportedExpression},
+	} {
+		if _, err := FromICU(test.icu); !errors.Is(err, test.wantErr) {
+			t.Errorf("%s: want '%s', got '%s'", test.icu, test.wantErr, err)
+		}
+	}
+}
+
+func TestToICU(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		name, mf2, want string
+	}{
+		{
+			name: "text",
+			mf2:  `It's \{escaped\} # { |literal| }`,
+			want: "It''s '{'escaped'}' # literal",
+		},
+		{
+			name: "arguments",
+			mf2:  "{ $name } { $name :string } { $n :number } { $n :integer } { $n :number style=percent } { $d :time style=short }",
+			want: "{name} {name} {n, number} {n, number, integer} {n, number, percent} {d, time, short}",
+		},
+		{
+			name: "plural",
+			mf2:  ".input { $count :number } .match { $count } 0 {{no items}} one {{{ $count } item #1}} * {{{ $count } items}}",
+			want: "{count, plural, =0 {no items} one {{count, number} item '#'1} other {{count, number} items}}",
+		},
+		{
+			name: "nested",
+			mf2: ".match { $gender :string } { $n :integer }\n" +
+				"female 1 {{She has one}}\n" +
+				"female * {{She has { $n }}}\n" +
+				"* * {{They have { $n }}}",
+			want: "{gender, select, female {{n, plural, =1 {She has one} other {She has {n}}}} other {{n, plural, other {They have {n}}}}}",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			tree, err := parse.Parse(test.mf2)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := ToICU(tree)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if test.want != got {
+				t.Errorf("want '%s', got '%s'", test.want, got)
+			}
+		})
+	}
+}
+
+func TestToICUErrors(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		mf2     string
+		wantErr error
+	}{
+		{mf2: ".local $x = { 1 } {{{ $x }}}", wantErr: mf2.ErrUnsupportedStatement},
+		{mf2: "{ #b }bold{ /b }", wantErr: mf2.ErrUnsupportedExpression},
+		{mf2: "{ $x :datetime }", wantErr: mf2.ErrUnsupportedExpression},
+		{mf2: "{ $x :number minimumFractionDigits=2 }", wantErr: mf2.ErrUnsupportedExpression},
+		{mf2: ".match { $x :datetime } * {{}}", wantErr: mf2.ErrUnsupportedExpression},
+	} {
+		tree, err := parse.Parse(test.mf2)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := ToICU(tree); !errors.Is(err, test.wantErr) {
+			t.Errorf("%s: want '%s', got '%s'", test.mf2, test.wantErr, err)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package builder
 import (
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
 
 	"go.expect.digital/mf2/parse"
@@ -34,6 +35,7 @@ func (b *Builder) MustBuild() string {
 	return s
 }
 
+// Text adds the text to the pattern, the special characters are escaped when built, e.g. "{" as `\{`.
 func (b *Builder) Text(s string) *Builder {
 	if b.err != nil {
 		return b
@@ -53,6 +55,8 @@ func (b *Builder) Text(s string) *Builder {
 		b.tree.Message = msg
 	case parse.ComplexMessage:
 		switch body := msg.ComplexBody.(type) {
+		case nil: // declarations only
+			msg.ComplexBody = parse.QuotedPattern{txt}
 		case parse.QuotedPattern:
 			body = append(body, txt)
 			msg.ComplexBody = body
@@ -74,15 +78,74 @@ func (b *Builder) Text(s string) *Builder {
 	return b
 }
 
-// Local adds local declaration to the builder.
-func (b *Builder) Local(v string, expr *Expression) *Builder {
+// RawText adds the text in the MF2 pattern syntax, e.g. `\{literal\}`, to the pattern.
+// The escape sequences are unescaped. The fragment that is not plain text, e.g. "{$var}",
+// or breaks out of the pattern, e.g. "}} {{", is an error, the fragments provided by the users
+// cannot inject placeholders or markup.
+func (b *Builder) RawText(s string) *Builder {
 	if b.err != nil {
 		return b
 	}
 
+	text, err := unescapeText(s)
+	if err != nil {
+		b.err = fmt.Errorf(`raw text "%s": %w`, s, err)
+		return b
+	}
+
+	return b.Text(text)
+}
+
+// unescapeText returns the unescaped text of the pattern fragment, the fragment must be text only.
+func unescapeText(s string) (string, error) {
+	// the quoted pattern is text regardless of the leading ".", the fragment must not close it
+	tree, err := parse.Parse("{{"+s+"}}", parse.WithoutValidation())
+	if err != nil {
+		return "", fmt.Errorf("want text: %w", err)
+	}
+
+	message, ok := tree.Message.(parse.ComplexMessage)
+	if !ok || len(message.Declarations) > 0 {
+		return "", errors.New("want text")
+	}
+
+	pattern, ok := message.ComplexBody.(parse.QuotedPattern)
+	if !ok {
+		return "", errors.New("want text")
+	}
+
+	var sb strings.Builder
+
+	for _, part := range pattern {
+		text, ok := part.(parse.Text)
+		if !ok {
+			return "", fmt.Errorf(`want text, got "%s"`, part)
+		}
+
+		sb.WriteString(string(text))
+	}
+
+	return sb.String(), nil
+}
+
+// Local adds local declaration to the builder. The options are added to the function
+// of the expression, and the attributes to the expression, e.g.
+//
+//	Local("n", Var("count").Func("number"), LiteralOption("minimumFractionDigits", 2), EmptyAttribute("internal"))
+func (b *Builder) Local(v string, expr *Expression, optionsAndAttributes ...OptsAndAttr) *Builder {
+	if b.err != nil {
+		return b
+	}
+
+	expression, err := expr.with(optionsAndAttributes)
+	if err != nil {
+		b.err = fmt.Errorf(`local declaration "%s": %w`, v, err)
+		return b
+	}
+
 	local := parse.LocalDeclaration{
-		Variable:   parse.Variable(v),
-		Expression: expr.expression,
+		Variable:   parse.Variable(strings.TrimPrefix(v, "$")),
+		Expression: expression,
 	}
 
 	switch msg := b.tree.Message.(type) {
@@ -104,13 +167,22 @@ func (b *Builder) Local(v string, expr *Expression) *Builder {
 	return b
 }
 
-// Input adds input declaration to the builder.
-func (b *Builder) Input(expr *Expression) *Builder {
+// Input adds input declaration to the builder. The options are added to the function
+// of the expression, and the attributes to the expression, e.g.
+//
+//	Input(Var("date").Func("datetime"), LiteralOption("dateStyle", "long"), LiteralAttribute("locale", "lv"))
+func (b *Builder) Input(expr *Expression, optionsAndAttributes ...OptsAndAttr) *Builder {
 	if b.err != nil {
 		return b
 	}
 
-	input := parse.InputDeclaration(expr.expression)
+	expression, err := expr.with(optionsAndAttributes)
+	if err != nil {
+		b.err = fmt.Errorf(`input declaration "%s": %w`, expr.expression, err)
+		return b
+	}
+
+	input := parse.InputDeclaration(expression)
 
 	switch msg := b.tree.Message.(type) {
 	default:
@@ -145,10 +217,9 @@ func (b *Builder) Expr(expr *Expression) *Builder {
 		b.tree.Message = msg
 	case parse.ComplexMessage:
 		switch body := msg.ComplexBody.(type) {
-		default:
-			b.tree.Message = parse.ComplexMessage{
-				ComplexBody: parse.QuotedPattern{expr.expression},
-			}
+		default: // declarations only
+			msg.ComplexBody = parse.QuotedPattern{expr.expression}
+			b.tree.Message = msg
 		case parse.QuotedPattern:
 			body = append(body, expr.expression)
 			msg.ComplexBody = body
@@ -199,8 +270,16 @@ func (b *Builder) Match(selector *Expression, selectors ...*Expression) *Builder
 			}
 			b.tree.Message = msg
 		case parse.QuotedPattern:
-			b.err = errors.New("match cannot be added after quoted pattern message")
-			return b
+			if len(body) > 0 {
+				b.err = errors.New("match cannot be added after quoted pattern message")
+				return b
+			}
+
+			// the empty pattern of the local declaration
+			msg.ComplexBody = parse.Matcher{
+				Selectors: parseSelectors,
+			}
+			b.tree.Message = msg
 		case parse.Matcher:
 			body.Selectors = parseSelectors
 			msg.ComplexBody = body
@@ -211,6 +290,43 @@ func (b *Builder) Match(selector *Expression, selectors ...*Expression) *Builder
 	return b
 }
 
+// Selector adds the selector to the matcher, e.g. Match(Var("count").Func("number")).Selector(Var("gender").Func("string")).
+// The selectors are added before the variants.
+func (b *Builder) Selector(selector *Expression) *Builder {
+	if b.err != nil {
+		return b
+	}
+
+	switch msg := b.tree.Message.(type) {
+	default:
+		return b.Match(selector)
+	case parse.SimpleMessage:
+		b.err = errors.New("selector cannot be added after simple message")
+	case parse.ComplexMessage:
+		switch body := msg.ComplexBody.(type) {
+		default:
+			return b.Match(selector)
+		case parse.QuotedPattern:
+			if len(body) == 0 { // the empty pattern of the local declaration
+				return b.Match(selector)
+			}
+
+			b.err = errors.New("selector cannot be added after quoted pattern message")
+		case parse.Matcher:
+			if len(body.Variants) > 0 {
+				b.err = fmt.Errorf(`add selector "%s" after variants`, selector.expression)
+				return b
+			}
+
+			body.Selectors = append(body.Selectors, selector.expression)
+			msg.ComplexBody = body
+			b.tree.Message = msg
+		}
+	}
+
+	return b
+}
+
 func (b *Builder) Keys(key any, keys ...any) *Builder {
 	if b.err != nil {
 		return b
@@ -271,7 +387,10 @@ func Var(name string) *Expression {
 	return Expr().Var(name)
 }
 
+// Var sets the variable operand of the expression, the name is with or without "$", e.g. "count" or "$count".
 func (e *Expression) Var(name string) *Expression {
+	name = strings.TrimPrefix(name, "$")
+
 	if len(name) == 0 {
 		panic("variable name cannot be empty")
 	}
@@ -454,7 +573,11 @@ func (e *Expression) Annotation(start AnnotationStart, reservedBody ...ReservedB
 	return e
 }
 
+// Func sets the function annotation of the expression, the name is with or without ":",
+// e.g. "number", ":number" or ":u:casing".
 func (e *Expression) Func(name string, option ...FuncOption) *Expression {
+	name = strings.TrimPrefix(name, ":")
+
 	if len(name) == 0 {
 		panic("function name cannot be empty")
 	}
@@ -484,6 +607,55 @@ func (e *Expression) Func(name string, option ...FuncOption) *Expression {
 	return e
 }
 
+// Options adds the options to the function of the expression, e.g. Var("n").Func("number").Options(...).
+func (e *Expression) Options(options ...FuncOption) *Expression {
+	f, ok := e.expression.Annotation.(parse.Function)
+	if !ok {
+		panic("options require function annotation")
+	}
+
+	for _, v := range options {
+		f.Options = append(f.Options, parse.Option(v))
+	}
+
+	e.expression.Annotation = f
+
+	return e
+}
+
+// with returns the copy of the expression with the options added to the function and the attributes added.
+func (e *Expression) with(optionsAndAttributes []OptsAndAttr) (parse.Expression, error) {
+	expression := e.expression
+
+	if len(optionsAndAttributes) == 0 {
+		return expression, nil
+	}
+
+	f, isFunc := expression.Annotation.(parse.Function)
+
+	f.Options = slices.Clone(f.Options)
+	expression.Attributes = slices.Clone(expression.Attributes)
+
+	for _, v := range optionsAndAttributes {
+		switch v := v.(type) {
+		case FuncOption:
+			if !isFunc {
+				return parse.Expression{}, fmt.Errorf(`option "%s" requires function annotation`, v.Identifier)
+			}
+
+			f.Options = append(f.Options, parse.Option(v))
+		case Attribute:
+			expression.Attributes = append(expression.Attributes, parse.Attribute(v))
+		}
+	}
+
+	if isFunc {
+		expression.Annotation = f
+	}
+
+	return expression, nil
+}
+
 // Attr adds attributes to the expression.
 func (e *Expression) Attr(attributes ...Attribute) *Expression {
 	for _, v := range attributes {
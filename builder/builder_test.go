@@ -1,6 +1,7 @@
 package builder
 
 import (
+	"fmt"
 	"runtime"
 	"testing"
 )
@@ -34,6 +35,16 @@ func Test_Builder(t *testing.T) {
 			NewBuilder().Text("{Hello}\\, {World}!"),
 			"\\{Hello\\}\\\\, \\{World\\}!",
 		},
+		{
+			"simple message, raw text",
+			NewBuilder().Text("{x} ").RawText(`\{y\} \\ z`),
+			`\{x\} \{y\} \\ z`,
+		},
+		{
+			"complex message, raw text",
+			NewBuilder().Local("x", Literal(1)).RawText(".ok"),
+			".local $x = { 1 }\n{{.ok}}",
+		},
 		{
 			"simple message, text with literal",
 			NewBuilder().
@@ -119,6 +130,23 @@ func Test_Builder(t *testing.T) {
 .input { $input @empty }
 .input { $input2 :upper }
 {{Beep}}`,
+		},
+		{
+			"complex message, declarations with options and attributes",
+			NewBuilder().
+				Input(Var("date").Func(":datetime"),
+					LiteralOption("dateStyle", "long"),
+					LiteralOption("timeStyle", "short"),
+					LiteralAttribute("locale", "lv")).
+				Local("$n", Var("count").Func("number").Options(LiteralOption("minimumFractionDigits", 2)),
+					VarOption("maximumFractionDigits", "max"),
+					EmptyAttribute("internal")).
+				Local("x", Var("y"), EmptyAttribute("empty")).
+				Text("{date}"),
+			`.input { $date :datetime dateStyle = long timeStyle = short @locale = lv }
+.local $n = { $count :number minimumFractionDigits = 2 maximumFractionDigits = $max @internal }
+.local $x = { $y @empty }
+{{\{date\}}}`,
 		},
 		{
 			"complex message, matcher with multiple keys",
@@ -148,6 +176,24 @@ func Test_Builder(t *testing.T) {
 				Keys("*", "*").Expr(Literal(1)),
 			".input { $i }\n.local $hostName = { $i }\n.match { $i } { $j }\n1 2 {{\\{first\\}}}\n2 0 {{second { $i }}}\n3 0 {{{ |\\\\a\\|| }}}\n* * {{{ 1 }}}",
 		},
+		{
+			"complex message, matcher with annotated selectors",
+			NewBuilder().
+				Match(Var("$count").Func(":number", LiteralOption("select", "ordinal"))).
+				Selector(Var("gender").Func(":u:casing", LiteralOption("style", "lower"))).
+				Keys("one", "*").Expr(Var("count")).Text("st").
+				Keys("*", "*").Expr(Var("count")).Text("th"),
+			".match { $count :number select = ordinal } { $gender :u:casing style = lower }\none * {{{ $count }st}}\n* * {{{ $count }th}}",
+		},
+		{
+			"complex message, selector step",
+			NewBuilder().
+				Local("n", Var("count").Func("integer")).
+				Selector(Var("n")).
+				Keys(0).Text("none").
+				Keys("*").Text("some"),
+			".local $n = { $count :integer }\n.match { $n }\n0 {{none}}\n* {{some}}",
+		},
 		{
 			"attributes",
 			NewBuilder().
@@ -206,7 +252,50 @@ func Test_Builder(t *testing.T) {
 	}
 }
 
+func Test_BuilderSelectorAfterVariants(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewBuilder().
+		Match(Var("a").Func("string")).
+		Keys("*").Text("any").
+		Selector(Var("b").Func("string")).
+		Build()
+	if err == nil {
+		t.Error("want error, got nil")
+	}
+}
+
+func Test_BuilderDeclarationOptionWithoutFunction(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewBuilder().
+		Input(Var("x"), LiteralOption("style", "long")).
+		Build()
+	if err == nil {
+		t.Error("want error, got nil")
+	}
+}
+
+func Test_BuilderRawTextInjection(t *testing.T) {
+	t.Parallel()
+
+	for _, raw := range []string{
+		"{$x}",
+		"{#b}",
+		"a } b",
+		"}} .match {$x :string} * {{",
+		"}}{{",
+		"\\q",
+	} {
+		if _, err := NewBuilder().Text("Hello, ").RawText(raw).Build(); err == nil {
+			t.Errorf("%s: want error, got nil", raw)
+		}
+	}
+}
+
 func BenchmarkBuildMatch(b *testing.B) {
+	b.ReportAllocs()
+
 	var s string
 
 	for range b.N {
@@ -228,6 +317,8 @@ func BenchmarkBuildMatch(b *testing.B) {
 }
 
 func BenchmarkBuildMarkup(b *testing.B) {
+	b.ReportAllocs()
+
 	var s string
 
 	for range b.N {
@@ -260,3 +351,54 @@ func BenchmarkBuildMarkup(b *testing.B) {
 
 	runtime.KeepAlive(s)
 }
+
+func BenchmarkBuildLarge(b *testing.B) {
+	b.ReportAllocs()
+
+	var s string
+
+	for range b.N {
+		builder := NewBuilder()
+
+		for i := range 50 {
+			builder.
+				Text("Lorem ipsum dolor sit amet ").
+				Expr(Var(fmt.Sprintf("var%d", i))).
+				Text(" consectetur ").
+				Expr(Literal(i).Func("number", LiteralOption("minimumFractionDigits", 2)))
+		}
+
+		s, _ = builder.Build()
+	}
+
+	runtime.KeepAlive(s)
+}
+
+func BenchmarkBuildDeepMatch(b *testing.B) {
+	b.ReportAllocs()
+
+	var s string
+
+	for range b.N {
+		builder := NewBuilder().
+			Input(Var("a").Func("number")).
+			Input(Var("b").Func("number")).
+			Input(Var("c").Func("string")).
+			Input(Var("d").Func("string")).
+			Match(Var("a"), Var("b"), Var("c"), Var("d"))
+
+		for _, a := range []any{"one", "*"} {
+			for _, b := range []any{"one", "*"} {
+				for _, c := range []any{"x", "*"} {
+					for _, d := range []any{"y", "*"} {
+						builder.Keys(a, b, c, d).Text("text ").Expr(Var("a"))
+					}
+				}
+			}
+		}
+
+		s, _ = builder.Build()
+	}
+
+	runtime.KeepAlive(s)
+}
@@ -0,0 +1,503 @@
+/*
+Package bundle stores MF2 catalogs of all locales of the application and
+formats messages in the best matching locale.
+
+The catalogs are loaded from a directory or any [fs.FS], e.g. [embed.FS], of JSON catalog files,
+see [catalog.Load]:
+
+	//go:embed locales
+	var locales embed.FS
+
+	b, err := bundle.LoadFS(locales, language.English)
+	if err != nil {
+		return err
+	}
+
+	s, err := b.Sprint(language.Latvian, "greeting", map[string]any{"name": "Jānis"})
+
+The catalogs of a locale are read and compiled on the first use of the locale.
+The message is looked up in the best matching locale, then in the default locale.
+
+In HTTP handlers, [Bundle.Middleware] negotiates the locale of the request:
+
+	http.Handle("/", b.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s, err := bundle.FromContext(r.Context()).Sprint("greeting", map[string]any{"name": "Jānis"})
+		// ...
+	})))
+
+Changed catalog files are reloaded with [Bundle.Reload], or periodically with [Bundle.Watch].
+*/
+package bundle
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"slices"
+	"sync"
+
+	"golang.org/x/text/language"
+
+	"go.expect.digital/mf2/catalog"
+	"go.expect.digital/mf2/template"
+)
+
+// Bundle is a collection of catalogs, one per locale.
+//
+// Bundle is safe for concurrent use.
+type Bundle struct {
+	catalogs map[language.Tag]*catalog.Catalog
+	// files are the catalog files of the locales not used yet.
+	files map[language.Tag][]string
+	fsys  fs.FS
+	// loaded is the last read state of the catalog files in fsys, see [Bundle.Reload].
+	loaded        *snapshot
+	matcher       language.Matcher
+	options       []template.Option
+	locales       []language.Tag // the default locale first
+	onFallback    func(f Fallback)
+	renderCache   *renderCache // nil if disabled, see [WithRenderCache]
+	localeCookie  *string      // nil for the default
+	defaultLocale language.Tag
+	mu            sync.RWMutex
+	reloadMu      sync.Mutex // serialises reloads
+	missingMessage,
+	missingLocale MissingPolicy
+}
+
+// Option is a bundle option.
+type Option func(b *Bundle)
+
+// WithTemplateOptions sets the options applied to every compiled template.
+func WithTemplateOptions(options ...template.Option) Option {
+	return func(b *Bundle) {
+		b.options = options
+	}
+}
+
+// New returns a new empty bundle. The default locale is used when no other locale matches.
+func New(defaultLocale language.Tag, options ...Option) *Bundle {
+	b := &Bundle{
+		defaultLocale: defaultLocale,
+		catalogs:      make(map[language.Tag]*catalog.Catalog),
+		files:         make(map[language.Tag][]string),
+		locales:       []language.Tag{defaultLocale},
+	}
+
+	for _, o := range options {
+		o(b)
+	}
+
+	b.matcher = language.NewMatcher(b.locales)
+
+	return b
+}
+
+// Load reads the JSON catalog files, "*.json", in the directory and its subdirectories, see [LoadFS].
+func Load(dir string, defaultLocale language.Tag, options ...Option) (*Bundle, error) {
+	b, err := LoadFS(os.DirFS(dir), defaultLocale, options...)
+	if err != nil {
+		return nil, fmt.Errorf(`load bundle "%s": %w`, dir, err)
+	}
+
+	return b, nil
+}
+
+// LoadFS reads the JSON catalog files, "*.json", in the file system, e.g. [embed.FS].
+//
+// Only the locales of the files are read, the messages of a locale are read, validated and compiled
+// on the first use of the locale, see [Bundle.Preload]. Catalog files of the same locale are merged,
+// duplicate message IDs are reported as errors.
+func LoadFS(fsys fs.FS, defaultLocale language.Tag, options ...Option) (*Bundle, error) {
+	snap, err := scan(fsys)
+	if err != nil {
+		return nil, fmt.Errorf("load bundle: %w", err)
+	}
+
+	b := New(defaultLocale, options...)
+	b.fsys = fsys
+	b.loaded = snap
+
+	for _, locale := range snap.locales {
+		b.files[locale] = snap.files[locale]
+		b.addLocale(locale)
+	}
+
+	return b, nil
+}
+
+// readLocale reads the locale of the JSON catalog file without reading the messages,
+// if the locale precedes the messages.
+func readLocale(fsys fs.FS, name string) (language.Tag, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return language.Und, err //nolint:wrapcheck
+	}
+
+	defer f.Close()
+
+	dec := json.NewDecoder(f)
+
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return language.Und, fmt.Errorf("%w: want JSON object", catalog.ErrInvalidFile)
+	}
+
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return language.Und, fmt.Errorf("%w: %w", catalog.ErrInvalidFile, err)
+		}
+
+		if key != "locale" {
+			if err := dec.Decode(&json.RawMessage{}); err != nil {
+				return language.Und, fmt.Errorf("%w: %w", catalog.ErrInvalidFile, err)
+			}
+
+			continue
+		}
+
+		var s string
+
+		if err := dec.Decode(&s); err != nil {
+			return language.Und, fmt.Errorf(`%w: "locale": %w`, catalog.ErrInvalidFile, err)
+		}
+
+		locale, err := language.Parse(s)
+		if err != nil {
+			return language.Und, fmt.Errorf(`%w: locale "%s": %w`, catalog.ErrInvalidFile, s, err)
+		}
+
+		return locale, nil
+	}
+
+	return language.Und, fmt.Errorf(`%w: missing "locale"`, catalog.ErrInvalidFile)
+}
+
+// loadLocale reads the catalog files of the locale, if not read yet. The caller must hold the lock.
+// If any of the files is invalid, the locale stays not read.
+func (b *Bundle) loadLocale(locale language.Tag) error {
+	files, ok := b.files[locale]
+	if !ok {
+		return nil
+	}
+
+	dst, err := b.readCatalog(locale, files)
+	if err != nil {
+		return err
+	}
+
+	b.catalogs[locale] = dst
+	delete(b.files, locale)
+
+	return nil
+}
+
+// readCatalog reads the catalog files of the locale into a new catalog.
+func (b *Bundle) readCatalog(locale language.Tag, files []string) (*catalog.Catalog, error) {
+	dst := catalog.New(locale, b.options...)
+
+	var errs []error
+
+	for _, name := range files {
+		if err := b.read(dst, name); err != nil {
+			errs = append(errs, fmt.Errorf(`file "%s": %w`, name, err))
+		}
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	return dst, nil
+}
+
+// read reads the JSON catalog file and adds its messages to dst.
+func (b *Bundle) read(dst *catalog.Catalog, name string) error {
+	f, err := b.fsys.Open(name)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	defer f.Close()
+
+	c, err := catalog.Load(f)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	for _, id := range c.IDs() {
+		if _, ok := dst.Message(id); ok {
+			return fmt.Errorf(`duplicate message "%s" in locale %s`, id, dst.Locale())
+		}
+
+		msg, _ := c.Message(id)
+		dst.SetMessage(msg)
+	}
+
+	return nil
+}
+
+// Preload reads, validates and compiles the catalogs of all locales, e.g. at startup or in tests.
+func (b *Bundle) Preload() error {
+	b.mu.Lock()
+
+	var errs []error
+
+	// the locales are loaded in the bundle order, the errors are reported in the same order
+	for _, locale := range b.locales {
+		if _, ok := b.files[locale]; ok {
+			errs = append(errs, b.loadLocale(locale))
+		}
+	}
+
+	catalogs := make([]*catalog.Catalog, 0, len(b.catalogs))
+
+	for _, locale := range b.locales {
+		if c, ok := b.catalogs[locale]; ok {
+			catalogs = append(catalogs, c)
+		}
+	}
+
+	b.mu.Unlock()
+
+	for _, c := range catalogs {
+		for _, id := range c.IDs() {
+			if _, err := c.Template(id); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("preload bundle: %w", err)
+	}
+
+	return nil
+}
+
+// Add adds the catalog, the catalog of the same locale is replaced.
+// The catalog's template options are used instead of [WithTemplateOptions].
+func (b *Bundle) Add(c *catalog.Catalog) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.addLocale(c.Locale())
+	b.catalogs[c.Locale()] = c
+	delete(b.files, c.Locale())
+
+	if b.renderCache != nil {
+		b.renderCache.clear()
+	}
+}
+
+// catalog returns the catalog of the locale, the catalog files of the locale are read on the first use.
+func (b *Bundle) catalog(locale language.Tag) (*catalog.Catalog, error) {
+	b.mu.RLock()
+	c := b.catalogs[locale]
+	_, pending := b.files[locale]
+	b.mu.RUnlock()
+
+	if !pending {
+		return c, nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err := b.loadLocale(locale); err != nil {
+		return nil, fmt.Errorf("load locale %s: %w", locale, err)
+	}
+
+	return b.catalogs[locale], nil
+}
+
+// addLocale adds the locale to the matcher, the caller must hold the lock.
+func (b *Bundle) addLocale(locale language.Tag) {
+	for _, v := range b.locales {
+		if v == locale {
+			return
+		}
+	}
+
+	b.locales = append(b.locales, locale)
+	b.matcher = language.NewMatcher(b.locales)
+}
+
+// DefaultLocale returns the default locale of the bundle.
+func (b *Bundle) DefaultLocale() language.Tag {
+	return b.defaultLocale
+}
+
+// Locales returns the locales of the bundle, the default locale first.
+func (b *Bundle) Locales() []language.Tag {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	return append([]language.Tag(nil), b.locales...)
+}
+
+// Catalog returns the catalog of the locale, or nil if the locale is missing or its catalog files are invalid.
+func (b *Bundle) Catalog(locale language.Tag) *catalog.Catalog {
+	c, _ := b.catalog(locale)
+
+	return c
+}
+
+// Match returns the best matching locale of the bundle for the locales in order of preference,
+// or the default locale if none matches.
+func (b *Bundle) Match(locales ...language.Tag) language.Tag {
+	locale, _ := b.match(locales...)
+
+	return locale
+}
+
+// match returns the best matching locale of the bundle, or the default locale and false if none matches.
+func (b *Bundle) match(locales ...language.Tag) (language.Tag, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	_, i, confidence := b.matcher.Match(locales...)
+	if confidence == language.No {
+		return b.defaultLocale, false
+	}
+
+	return b.locales[i], true
+}
+
+// Message returns the compiled template of the message in the best matching locale.
+// If the locale or the message is missing, the message is resolved by the policies,
+// see [WithMissingMessage] and [WithMissingLocale].
+func (b *Bundle) Message(locale language.Tag, id string) (*template.Template, error) {
+	errorf := func(format string, args ...any) (*template.Template, error) {
+		return nil, fmt.Errorf("bundle message: "+format, args...)
+	}
+
+	matched, ok := b.match(locale)
+	fallback := Fallback{ID: id, Locale: locale, Matched: matched, Policy: b.missingMessage}
+
+	if ok {
+		tmpl, found, err := b.lookup(matched, id)
+		if err != nil || found {
+			return tmpl, err
+		}
+	} else {
+		fallback.Matched = language.Und
+		fallback.Policy = b.missingLocale
+	}
+
+	switch fallback.Policy {
+	default: // FallbackToDefault
+		for _, used := range b.fallbackLocales(matched, ok) {
+			tmpl, found, err := b.lookup(used, id)
+			if err != nil {
+				return nil, err
+			}
+
+			if found {
+				fallback.Used = used
+				b.report(fallback)
+
+				return tmpl, nil
+			}
+		}
+
+		b.report(fallback)
+
+		return errorf(`%w "%s" in locale %s`, catalog.ErrMissingMessage, id, matched)
+	case ReturnError:
+		b.report(fallback)
+
+		if !ok {
+			return errorf("%w %s", ErrMissingLocale, locale)
+		}
+
+		return errorf(`%w "%s" in locale %s`, catalog.ErrMissingMessage, id, matched)
+	case ReturnID:
+		b.report(fallback)
+
+		return idTemplate(id)
+	}
+}
+
+// fallbackLocales returns the locales to look up the missing message in: the source locale
+// of the matched catalog, if the bundle has it, and the default locale. Only the default locale
+// if no locale is matched, ok is false.
+func (b *Bundle) fallbackLocales(matched language.Tag, ok bool) []language.Tag {
+	if !ok {
+		return []language.Tag{b.defaultLocale}
+	}
+
+	var locales []language.Tag
+
+	if c, _ := b.catalog(matched); c != nil {
+		source := c.SourceLocale()
+
+		b.mu.RLock()
+		has := slices.Contains(b.locales, source)
+		b.mu.RUnlock()
+
+		if has && source != matched && source != b.defaultLocale {
+			locales = append(locales, source)
+		}
+	}
+
+	if matched != b.defaultLocale {
+		locales = append(locales, b.defaultLocale)
+	}
+
+	return locales
+}
+
+// lookup returns the compiled template of the message in the locale, if found.
+func (b *Bundle) lookup(locale language.Tag, id string) (*template.Template, bool, error) {
+	c, err := b.catalog(locale)
+	if err != nil {
+		return nil, false, fmt.Errorf("bundle message: %w", err)
+	}
+
+	if c == nil {
+		return nil, false, nil
+	}
+
+	if _, ok := c.Message(id); !ok {
+		return nil, false, nil
+	}
+
+	tmpl, err := c.Template(id)
+	if err != nil {
+		return nil, false, fmt.Errorf("bundle message: %w", err)
+	}
+
+	return tmpl, true, nil
+}
+
+// Execute writes the formatted message in the best matching locale to the writer.
+func (b *Bundle) Execute(w io.Writer, locale language.Tag, id string, input map[string]any) error {
+	if b.renderCache != nil {
+		return b.executeCached(w, locale, id, input)
+	}
+
+	tmpl, err := b.Message(locale, id)
+	if err != nil {
+		return err
+	}
+
+	return tmpl.Execute(w, input) //nolint:wrapcheck
+}
+
+// Sprint returns the formatted message in the best matching locale.
+func (b *Bundle) Sprint(locale language.Tag, id string, input map[string]any) (string, error) {
+	if b.renderCache != nil {
+		return b.sprint(locale, id, input)
+	}
+
+	tmpl, err := b.Message(locale, id)
+	if err != nil {
+		return "", err
+	}
+
+	return tmpl.Sprint(input) //nolint:wrapcheck
+}
@@ -0,0 +1,152 @@
+package bundle
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"testing/fstest"
+
+	"golang.org/x/text/language"
+
+	"go.expect.digital/mf2/catalog"
+)
+
+func TestLoad(t *testing.T) {
+	t.Parallel()
+
+	b, err := Load("testdata", language.English)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, got := []language.Tag{language.English, language.Latvian}, b.Locales(); !slices.Equal(want, got) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+
+	for _, test := range []struct {
+		input  map[string]any
+		locale language.Tag
+		id     string
+		want   string
+	}{
+		{locale: language.Latvian, id: "greeting", input: map[string]any{"name": "Jānis"}, want: "Sveiki, Jānis!"},
+		{locale: language.Latvian, id: "apples", input: map[string]any{"count": 1}, want: "1 ābols"},
+		// negotiated
+		{locale: language.MustParse("lv-LV"), id: "apples", input: map[string]any{"count": 2}, want: "2 āboli"},
+		{locale: language.AmericanEnglish, id: "apples", input: map[string]any{"count": 1}, want: "1 apple"},
+		// fallback to the default locale
+		{locale: language.German, id: "greeting", input: map[string]any{"name": "Hans"}, want: "Hello, Hans!"},
+		{locale: language.Latvian, id: "farewell", input: map[string]any{"name": "Jānis"}, want: "Goodbye, Jānis!"},
+	} {
+		got, err := b.Sprint(test.locale, test.id, test.input)
+		if err != nil {
+			t.Error(err)
+		}
+
+		if test.want != got {
+			t.Errorf("want '%s', got '%s'", test.want, got)
+		}
+	}
+
+	if _, err := b.Message(language.Latvian, "missing"); !errors.Is(err, catalog.ErrMissingMessage) {
+		t.Errorf("want '%s', got '%s'", catalog.ErrMissingMessage, err)
+	}
+}
+
+func TestLoadDuplicate(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	file := `{"locale": "en", "messages": {"greeting": {"message": "Hello!"}}}`
+
+	for _, name := range []string{"a.json", "b.json"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(file), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	b, err := Load(dir, language.English)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := b.Message(language.English, "greeting"); err == nil {
+		t.Error("want duplicate message error, got nil")
+	}
+
+	if err := b.Preload(); err == nil {
+		t.Error("want duplicate message error, got nil")
+	}
+}
+
+func TestLoadFS(t *testing.T) {
+	t.Parallel()
+
+	fsys := fstest.MapFS{
+		"en.json":        {Data: []byte(`{"locale": "en", "messages": {"greeting": {"message": "Hello!"}}}`)},
+		"lv.json":        {Data: []byte(`{"messages": {"greeting": {"message": "Sveiki!"}}, "locale": "lv"}`)},
+		"de.json":        {Data: []byte(`{"locale": "de", "messages": {"greeting": {"message": "Hallo { $name"}}}`)},
+		"README.md":      {Data: []byte("not a catalog")},
+		"nested/fr.json": {Data: []byte(`{"locale": "fr", "messages": {"greeting": {"message": "Bonjour !"}}}`)},
+	}
+
+	b, err := LoadFS(fsys, language.English)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := len(b.Locales()); got != 4 {
+		t.Errorf("want 4 locales, got %d", got)
+	}
+
+	// read on the first use
+	if len(b.catalogs) != 0 {
+		t.Errorf("want no catalogs read, got %d", len(b.catalogs))
+	}
+
+	for locale, want := range map[language.Tag]string{
+		language.English: "Hello!",
+		language.Latvian: "Sveiki!",
+		language.French:  "Bonjour !",
+	} {
+		if got, err := b.Sprint(locale, "greeting", nil); err != nil || want != got {
+			t.Errorf("want '%s', got '%s' (%v)", want, got, err)
+		}
+	}
+
+	if len(b.catalogs) != 3 {
+		t.Errorf("want 3 catalogs read, got %d", len(b.catalogs))
+	}
+
+	if _, err := b.Message(language.German, "greeting"); !errors.Is(err, catalog.ErrInvalidFile) {
+		t.Errorf("want '%s', got '%s'", catalog.ErrInvalidFile, err)
+	}
+
+	if err := b.Preload(); !errors.Is(err, catalog.ErrInvalidFile) {
+		t.Errorf("want '%s', got '%s'", catalog.ErrInvalidFile, err)
+	}
+
+	if _, err := LoadFS(fstest.MapFS{"x.json": {Data: []byte(`{"messages": {}}`)}}, language.English); err == nil {
+		t.Error("want missing locale error, got nil")
+	}
+}
+
+func TestAdd(t *testing.T) {
+	t.Parallel()
+
+	c := catalog.New(language.Latvian)
+	c.Set("greeting", "Sveiki!")
+
+	b := New(language.English)
+	b.Add(c)
+
+	if got := b.Match(language.MustParse("lv-LV")); got != language.Latvian {
+		t.Errorf("want '%s', got '%s'", language.Latvian, got)
+	}
+
+	if got, _ := b.Sprint(language.Latvian, "greeting", nil); got != "Sveiki!" {
+		t.Errorf("want 'Sveiki!', got '%s'", got)
+	}
+}
@@ -0,0 +1,26 @@
+package bundletest
+
+import (
+	"golang.org/x/text/language"
+
+	"go.expect.digital/mf2/bundle"
+	"go.expect.digital/mf2/catalog"
+)
+
+// New returns the bundle of the default locale with a catalog per locale of the messages,
+// e.g. the fixture of the tests of the translation tools. Every call returns new catalogs.
+func New(defaultLocale language.Tag, messages map[language.Tag][]catalog.Message) *bundle.Bundle {
+	b := bundle.New(defaultLocale)
+
+	for locale, msgs := range messages {
+		c := catalog.New(locale)
+
+		for _, msg := range msgs {
+			c.SetMessage(msg)
+		}
+
+		b.Add(c)
+	}
+
+	return b
+}
@@ -0,0 +1,206 @@
+/*
+Package bundletest snapshot-tests the localization surface of an application: every message of a bundle
+is rendered in every locale with sample inputs and compared with golden files, one file per locale.
+
+Example:
+
+	func TestMessages(t *testing.T) {
+		b, _ := bundle.Load("locales", language.English)
+
+		bundletest.Golden(t, b, "testdata/golden", bundletest.Samples{
+			Defaults: map[string]any{"name": "Anna"},
+			Inputs: map[string][]map[string]any{
+				"apples": {{"count": 1}, {"count": 5}},
+			},
+		})
+	}
+
+Run the tests with the "-mf2.update" flag to write the golden files, e.g. "go test -run TestMessages -mf2.update",
+and review the changes of the files in the VCS.
+*/
+package bundletest
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+
+	"golang.org/x/text/language"
+
+	"go.expect.digital/mf2/bundle"
+	"go.expect.digital/mf2/internal/mapkeys"
+)
+
+var update = flag.Bool("mf2.update", false, "update the golden files of go.expect.digital/mf2/bundle/bundletest")
+
+// Samples are the sample inputs of the messages.
+type Samples struct {
+	// Defaults are the input values of all messages, the values of Inputs win.
+	Defaults map[string]any
+	// Inputs are the sample inputs by message ID, each message is rendered once per input.
+	// The messages without inputs are rendered once with Defaults.
+	Inputs map[string][]map[string]any
+}
+
+// inputs returns the inputs of the message.
+func (s Samples) inputs(id string) []map[string]any {
+	inputs := s.Inputs[id]
+	if len(inputs) == 0 {
+		return []map[string]any{maps.Clone(s.Defaults)}
+	}
+
+	r := make([]map[string]any, 0, len(inputs))
+
+	for _, input := range inputs {
+		m := maps.Clone(s.Defaults)
+		if m == nil {
+			m = make(map[string]any, len(input))
+		}
+
+		maps.Copy(m, input)
+		r = append(r, m)
+	}
+
+	return r
+}
+
+/*
+Render renders the messages of the default locale and of the locale in the locale, sorted by ID,
+with the sample inputs. The output is the golden file format:
+
+	[apples] count=1
+		1 apple
+	[apples] count=5
+		5 apples
+	[greeting] name=Anna
+		Hello, Anna!
+	[invalid]
+		{$name}
+		! execute template: ...
+
+Each rendered message starts with the message ID in brackets and the sorted input values of the message.
+Each line of the output is indented with a tab, the error, if any, is the last line starting with "!".
+*/
+func Render(b *bundle.Bundle, locale language.Tag, samples Samples) []byte {
+	var buf bytes.Buffer
+
+	for _, id := range messageIDs(b, locale) {
+		for _, input := range samples.inputs(id) {
+			buf.WriteString("[" + id + "]")
+
+			for _, k := range mapkeys.Sorted(input) {
+				fmt.Fprintf(&buf, " %s=%v", k, input[k])
+			}
+
+			buf.WriteByte('\n')
+
+			s, err := b.Sprint(locale, id, input)
+
+			for _, line := range strings.Split(s, "\n") {
+				buf.WriteString("\t" + line + "\n")
+			}
+
+			if err != nil {
+				buf.WriteString("\t! " + strings.ReplaceAll(err.Error(), "\n", " ") + "\n")
+			}
+		}
+	}
+
+	return buf.Bytes()
+}
+
+// messageIDs returns the sorted IDs of the messages of the default locale and of the locale.
+func messageIDs(b *bundle.Bundle, locale language.Tag) []string {
+	var ids []string
+
+	for _, l := range []language.Tag{b.DefaultLocale(), locale} {
+		if c := b.Catalog(l); c != nil {
+			ids = append(ids, c.IDs()...)
+		}
+	}
+
+	sort.Strings(ids)
+
+	return slices.Compact(ids)
+}
+
+// Golden renders the messages of each locale of the bundle, see [Render], and compares the output
+// with the golden file "<locale>.golden" in the directory, e.g. "testdata/golden/lv.golden".
+// The mismatches and the missing golden files are reported with t.Errorf.
+//
+// With the "-mf2.update" flag, the golden files are written instead.
+func Golden(t testing.TB, b *bundle.Bundle, dir string, samples Samples) {
+	t.Helper()
+
+	golden(t, b, dir, samples, *update)
+}
+
+func golden(t testing.TB, b *bundle.Bundle, dir string, samples Samples, update bool) {
+	t.Helper()
+
+	for _, locale := range b.Locales() {
+		name := filepath.Join(dir, locale.String()+".golden")
+		got := Render(b, locale, samples)
+
+		if update {
+			if err := os.MkdirAll(dir, 0o755); err != nil { //nolint:mnd
+				t.Fatal(err)
+			}
+
+			if err := os.WriteFile(name, got, 0o644); err != nil { //nolint:mnd,gosec
+				t.Fatal(err)
+			}
+
+			continue
+		}
+
+		want, err := os.ReadFile(name)
+		if errors.Is(err, fs.ErrNotExist) {
+			t.Errorf("missing golden file %s, run the test with -mf2.update to create it", name)
+			continue
+		}
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if n, w, g, ok := firstDiff(string(want), string(got)); !ok {
+			t.Errorf("%s: line %d: want '%s', got '%s', run the test with -mf2.update to update the file", name, n, w, g)
+		}
+	}
+}
+
+// firstDiff returns the line number and the first differing lines of the want and got text,
+// ok is true if the text is equal.
+func firstDiff(want, got string) (n int, wantLine, gotLine string, ok bool) {
+	if want == got {
+		return 0, "", "", true
+	}
+
+	wantLines, gotLines := strings.Split(want, "\n"), strings.Split(got, "\n")
+
+	for n = 0; n < len(wantLines) && n < len(gotLines); n++ {
+		if wantLines[n] != gotLines[n] {
+			break
+		}
+	}
+
+	if n < len(wantLines) {
+		wantLine = wantLines[n]
+	}
+
+	if n < len(gotLines) {
+		gotLine = gotLines[n]
+	}
+
+	return n + 1, wantLine, gotLine, false
+}
@@ -0,0 +1,109 @@
+package bundletest
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/text/language"
+
+	"go.expect.digital/mf2/catalog"
+)
+
+// messages are the messages of the tests by locale, see [New].
+var messages = map[language.Tag][]catalog.Message{
+	language.English: {
+		{ID: "apples", Text: ".input { $count :number }\n.match { $count }\none {{{ $count } apple}}\n* {{{ $count } apples}}"},
+		{ID: "greeting", Text: "Hello, { $name }!"},
+	},
+	language.Latvian: {
+		{ID: "apples", Text: ".input { $count :number }\n.match { $count }\none {{{ $count } ābols}}\n* {{{ $count } āboli}}"},
+	},
+}
+
+func TestRender(t *testing.T) {
+	t.Parallel()
+
+	got := Render(New(language.English, messages), language.Latvian, Samples{
+		Defaults: map[string]any{"name": "Anna"},
+		Inputs:   map[string][]map[string]any{"apples": {{"count": 1}, {"count": 5}}},
+	})
+
+	want := `[apples] count=1 name=Anna
+	1 ābols
+[apples] count=5 name=Anna
+	5 āboli
+[greeting] name=Anna
+	Hello, Anna!
+`
+
+	if want != string(got) {
+		t.Errorf("want '%s', got '%s'", want, got)
+	}
+
+	// the error is the last line
+	got = Render(New(language.English, messages), language.English, Samples{Inputs: map[string][]map[string]any{"apples": {{"count": 1}}}})
+
+	want = `[apples] count=1
+	1 apple
+[greeting]
+	Hello, {$name}!
+	! execute template: expression: unresolved variable "$name"
+`
+
+	if want != string(got) {
+		t.Errorf("want '%s', got '%s'", want, got)
+	}
+}
+
+// recorder records the errors of the test.
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestGolden(t *testing.T) {
+	t.Parallel()
+
+	dir := filepath.Join(t.TempDir(), "golden")
+	samples := Samples{Defaults: map[string]any{"name": "Anna", "count": 1}}
+
+	// missing golden files
+	r := &recorder{TB: t}
+
+	golden(r, New(language.English, messages), dir, samples, false)
+
+	if len(r.errors) != 2 { //nolint:mnd
+		t.Errorf("want 2 errors, got %v", r.errors)
+	}
+
+	golden(t, New(language.English, messages), dir, samples, true)
+
+	for _, name := range []string{"en.golden", "lv.golden"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Error(err)
+		}
+	}
+
+	golden(t, New(language.English, messages), dir, samples, false)
+
+	// the changed translation
+	b := New(language.English, messages)
+	b.Catalog(language.Latvian).Set("greeting", "Sveiki, { $name }!")
+
+	r = &recorder{TB: t}
+
+	golden(r, b, dir, samples, false)
+
+	want := filepath.Join(dir, "lv.golden") +
+		": line 4: want '\tHello, Anna!', got '\tSveiki, Anna!', run the test with -mf2.update to update the file"
+
+	if len(r.errors) != 1 || r.errors[0] != want {
+		t.Errorf("want '%s', got %v", want, r.errors)
+	}
+}
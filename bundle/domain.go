@@ -0,0 +1,86 @@
+package bundle
+
+import (
+	"io"
+
+	"golang.org/x/text/language"
+
+	"go.expect.digital/mf2/catalog"
+	"go.expect.digital/mf2/template"
+)
+
+// Domain is a namespace of hierarchical message IDs in the bundle, see [catalog.IDSeparator].
+// Features of a large application look up their messages by IDs relative to the domain,
+// while sharing one bundle:
+//
+//	cart := b.Domain("checkout.cart")
+//	s, err := cart.Sprint(locale, "title", nil) // "checkout.cart.title"
+type Domain struct {
+	bundle *Bundle
+	name   string
+}
+
+// Domain returns the domain of message IDs with the name as the prefix.
+func (b *Bundle) Domain(name string) *Domain {
+	return &Domain{bundle: b, name: name}
+}
+
+// Domain returns the nested domain, e.g. "checkout.cart" for "cart" in "checkout".
+func (d *Domain) Domain(name string) *Domain {
+	return &Domain{bundle: d.bundle, name: d.id(name)}
+}
+
+// Name returns the name of the domain.
+func (d *Domain) Name() string {
+	return d.name
+}
+
+// id returns the message ID in the bundle.
+func (d *Domain) id(id string) string {
+	if d.name == "" {
+		return id
+	}
+
+	return d.name + catalog.IDSeparator + id
+}
+
+// IDs returns sorted message IDs of the domain in the best matching locale, relative to the domain.
+func (d *Domain) IDs(locale language.Tag) []string {
+	c := d.bundle.Catalog(d.bundle.Match(locale))
+	if c == nil {
+		return nil
+	}
+
+	ids := c.Namespace(d.name)
+
+	result := make([]string, 0, len(ids))
+
+	for _, id := range ids {
+		if id == d.name {
+			continue
+		}
+
+		if d.name == "" {
+			result = append(result, id)
+		} else {
+			result = append(result, id[len(d.name)+len(catalog.IDSeparator):])
+		}
+	}
+
+	return result
+}
+
+// Message returns the compiled template of the message in the domain, see [Bundle.Message].
+func (d *Domain) Message(locale language.Tag, id string) (*template.Template, error) {
+	return d.bundle.Message(locale, d.id(id))
+}
+
+// Execute writes the formatted message in the domain to the writer, see [Bundle.Execute].
+func (d *Domain) Execute(w io.Writer, locale language.Tag, id string, input map[string]any) error {
+	return d.bundle.Execute(w, locale, d.id(id), input)
+}
+
+// Sprint returns the formatted message in the domain, see [Bundle.Sprint].
+func (d *Domain) Sprint(locale language.Tag, id string, input map[string]any) (string, error) {
+	return d.bundle.Sprint(locale, d.id(id), input)
+}
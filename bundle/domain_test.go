@@ -0,0 +1,44 @@
+package bundle
+
+import (
+	"slices"
+	"testing"
+
+	"golang.org/x/text/language"
+
+	"go.expect.digital/mf2/catalog"
+)
+
+func TestDomain(t *testing.T) {
+	t.Parallel()
+
+	c := catalog.New(language.English)
+	c.Set("checkout", "Checkout")
+	c.Set("checkout.cart.title", "Cart")
+	c.Set("checkout.cart.empty", "The cart is empty")
+	c.Set("checkout.total", "Total: { $total }")
+	c.Set("checkoutx", "Not in checkout")
+
+	b := New(language.English)
+	b.Add(c)
+
+	checkout := b.Domain("checkout")
+	cart := checkout.Domain("cart")
+
+	if cart.Name() != "checkout.cart" {
+		t.Errorf("want 'checkout.cart', got '%s'", cart.Name())
+	}
+
+	if got, _ := cart.Sprint(language.English, "title", nil); got != "Cart" {
+		t.Errorf("want 'Cart', got '%s'", got)
+	}
+
+	want := []string{"cart.empty", "cart.title", "total"}
+	if got := checkout.IDs(language.English); !slices.Equal(want, got) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+
+	if got := len(b.Domain("").IDs(language.English)); got != 5 {
+		t.Errorf("want 5 IDs, got %d", got)
+	}
+}
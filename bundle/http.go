@@ -0,0 +1,69 @@
+package bundle
+
+import (
+	"net/http"
+
+	"golang.org/x/text/language"
+)
+
+// defaultLocaleCookie is the default name of the cookie with the preferred locale.
+const defaultLocaleCookie = "lang"
+
+// WithLocaleCookie sets the name of the cookie with the preferred locale, see [Bundle.Middleware].
+// The default is "lang", the empty name disables the cookie.
+func WithLocaleCookie(name string) Option {
+	return func(b *Bundle) {
+		b.localeCookie = &name
+	}
+}
+
+// Middleware negotiates the locale of the HTTP request and stores the [Localizer] in the request context,
+// see [FromContext]. The locale of the cookie, see [WithLocaleCookie], is preferred over
+// the "Accept-Language" header. Invalid values are ignored.
+//
+// The response gets the "Content-Language" header of the negotiated locale.
+func (b *Bundle) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		l := b.Localizer(b.requestLocales(r)...)
+
+		w.Header().Add("Vary", "Accept-Language")
+
+		if b.cookieName() != "" {
+			w.Header().Add("Vary", "Cookie")
+		}
+
+		w.Header().Set("Content-Language", l.Locale().String())
+
+		next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), l)))
+	})
+}
+
+// cookieName returns the name of the cookie with the preferred locale, or empty if disabled.
+func (b *Bundle) cookieName() string {
+	if b.localeCookie == nil {
+		return defaultLocaleCookie
+	}
+
+	return *b.localeCookie
+}
+
+// requestLocales returns the preferred locales of the request.
+func (b *Bundle) requestLocales(r *http.Request) []language.Tag {
+	var locales []language.Tag
+
+	if name := b.cookieName(); name != "" {
+		if cookie, err := r.Cookie(name); err == nil {
+			if locale, err := language.Parse(cookie.Value); err == nil {
+				locales = append(locales, locale)
+			}
+		}
+	}
+
+	for _, v := range r.Header.Values("Accept-Language") {
+		if tags, _, err := language.ParseAcceptLanguage(v); err == nil {
+			locales = append(locales, tags...)
+		}
+	}
+
+	return locales
+}
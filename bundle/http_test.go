@@ -0,0 +1,77 @@
+package bundle
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"golang.org/x/text/language"
+
+	"go.expect.digital/mf2/catalog"
+)
+
+func TestMiddleware(t *testing.T) {
+	t.Parallel()
+
+	en := catalog.New(language.English)
+	en.Set("greeting", "Hello, { $name }!")
+
+	lv := catalog.New(language.Latvian)
+	lv.Set("greeting", "Sveiki, { $name }!")
+
+	b := New(language.English)
+	b.Add(en)
+	b.Add(lv)
+
+	handler := b.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s, err := FromContext(r.Context()).Sprint("greeting", map[string]any{"name": "MF2"})
+		if err != nil {
+			t.Error(err)
+		}
+
+		_, _ = io.WriteString(w, s)
+	}))
+
+	for _, test := range []struct {
+		acceptLanguage, cookie string
+		want, wantLanguage     string
+	}{
+		{want: "Hello, MF2!", wantLanguage: "en"},
+		{acceptLanguage: "de, lv;q=0.8", want: "Sveiki, MF2!", wantLanguage: "lv"},
+		{acceptLanguage: "lv", cookie: "en-GB", want: "Hello, MF2!", wantLanguage: "en"},
+		{acceptLanguage: "lv", cookie: "invalid_locale", want: "Sveiki, MF2!", wantLanguage: "lv"},
+	} {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+		if test.acceptLanguage != "" {
+			r.Header.Set("Accept-Language", test.acceptLanguage)
+		}
+
+		if test.cookie != "" {
+			r.AddCookie(&http.Cookie{Name: "lang", Value: test.cookie})
+		}
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+
+		if got := w.Body.String(); test.want != got {
+			t.Errorf("want '%s', got '%s'", test.want, got)
+		}
+
+		if got := w.Header().Get("Content-Language"); test.wantLanguage != got {
+			t.Errorf("want '%s', got '%s'", test.wantLanguage, got)
+		}
+	}
+}
+
+func TestFromContextMissing(t *testing.T) {
+	t.Parallel()
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	if _, err := FromContext(r.Context()).Sprint("greeting", nil); !errors.Is(err, ErrMissingLocalizer) {
+		t.Errorf("want '%s', got '%s'", ErrMissingLocalizer, err)
+	}
+}
@@ -0,0 +1,81 @@
+package bundle
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"golang.org/x/text/language"
+
+	"go.expect.digital/mf2/template"
+)
+
+// ErrMissingLocalizer occurs when the context does not contain the localizer, see [FromContext].
+var ErrMissingLocalizer = errors.New("missing localizer")
+
+// Localizer formats messages of the bundle in the negotiated locale, e.g. of the HTTP request.
+type Localizer struct {
+	bundle *Bundle
+	locale language.Tag
+}
+
+// Localizer returns the localizer for the best matching locale of the locales in order of preference.
+func (b *Bundle) Localizer(locales ...language.Tag) *Localizer {
+	return &Localizer{bundle: b, locale: b.Match(locales...)}
+}
+
+// Locale returns the negotiated locale, or [language.Und] for the nil localizer.
+func (l *Localizer) Locale() language.Tag {
+	if l == nil {
+		return language.Und
+	}
+
+	return l.locale
+}
+
+// Message returns the compiled template of the message, see [Bundle.Message].
+// The nil localizer returns [ErrMissingLocalizer].
+func (l *Localizer) Message(id string) (*template.Template, error) {
+	if l == nil {
+		return nil, ErrMissingLocalizer
+	}
+
+	return l.bundle.Message(l.locale, id)
+}
+
+// Execute writes the formatted message to the writer, see [Bundle.Execute].
+// The nil localizer returns [ErrMissingLocalizer].
+func (l *Localizer) Execute(w io.Writer, id string, input map[string]any) error {
+	if l == nil {
+		return ErrMissingLocalizer
+	}
+
+	return l.bundle.Execute(w, l.locale, id, input)
+}
+
+// Sprint returns the formatted message, see [Bundle.Sprint].
+// The nil localizer returns [ErrMissingLocalizer].
+func (l *Localizer) Sprint(id string, input map[string]any) (string, error) {
+	if l == nil {
+		return "", ErrMissingLocalizer
+	}
+
+	return l.bundle.Sprint(l.locale, id, input)
+}
+
+type localizerKey struct{}
+
+// NewContext returns the context with the localizer.
+func NewContext(ctx context.Context, l *Localizer) context.Context {
+	return context.WithValue(ctx, localizerKey{}, l)
+}
+
+// FromContext returns the localizer of the context, or nil. The nil localizer is safe to use,
+// its methods return [ErrMissingLocalizer]:
+//
+//	s, err := bundle.FromContext(r.Context()).Sprint("greeting", map[string]any{"name": name})
+func FromContext(ctx context.Context) *Localizer {
+	l, _ := ctx.Value(localizerKey{}).(*Localizer)
+
+	return l
+}
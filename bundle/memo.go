@@ -0,0 +1,162 @@
+package bundle
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/text/language"
+
+	"go.expect.digital/mf2/internal/lru"
+	"go.expect.digital/mf2/internal/mapkeys"
+)
+
+// WithRenderCache caches the formatted messages of [Bundle.Sprint] and [Bundle.Execute], at most size
+// results for at most ttl each, zero ttl for no expiry. It is intended for the high-traffic messages
+// whose inputs repeat heavily, e.g. status labels. The least recently used results are evicted first.
+//
+// The results are keyed by the message ID, the requested locale and the input. Only the inputs of strings,
+// booleans, numbers and [time.Time] values are cached, the messages with other input values, e.g. slices
+// or structs, and the failed formatting are not. The fallbacks of the cached messages are reported
+// on the first formatting only, see [WithFallbackHandler].
+//
+// [Bundle.Add] and [Bundle.Reload] clear the cache, the changes to the catalogs in the bundle
+// are formatted after the cached results expire.
+func WithRenderCache(size int, ttl time.Duration) Option {
+	return func(b *Bundle) {
+		b.renderCache = newRenderCache(size, ttl)
+	}
+}
+
+// renderCache is the LRU cache of the formatted messages.
+type renderCache struct {
+	results *lru.Cache[string, renderEntry]
+	now     func() time.Time
+	ttl     time.Duration
+}
+
+type renderEntry struct {
+	expires time.Time
+	result  string
+}
+
+func newRenderCache(size int, ttl time.Duration) *renderCache {
+	c := &renderCache{ttl: ttl, now: time.Now}
+	c.results = lru.New[string](size, c.expired)
+
+	return c
+}
+
+// expired reports whether the result is older than the ttl.
+func (c *renderCache) expired(entry renderEntry) bool {
+	return c.ttl > 0 && !c.now().Before(entry.expires)
+}
+
+// get returns the cached result, if not expired.
+func (c *renderCache) get(key string) (string, bool) {
+	entry, ok := c.results.Get(key)
+
+	return entry.result, ok
+}
+
+// put caches the result, the least recently used result is evicted if the cache is full.
+func (c *renderCache) put(key, result string) {
+	c.results.Add(key, renderEntry{result: result, expires: c.now().Add(c.ttl)})
+}
+
+// clear removes all cached results.
+func (c *renderCache) clear() {
+	c.results.Clear()
+}
+
+// RenderCacheStats returns the number of the render cache hits and misses, see [WithRenderCache],
+// e.g. to export the hit rate. The formatting of the inputs not cached is not counted.
+// Both are zero if the bundle has no render cache.
+func (b *Bundle) RenderCacheStats() (hits, misses uint64) {
+	if b.renderCache == nil {
+		return 0, 0
+	}
+
+	return b.renderCache.results.Stats()
+}
+
+// renderKey returns the cache key of the message, or false if the input is not cacheable.
+// The input values are sorted by name and written with their types, e.g. 1 and "1" differ,
+// the names and the strings are quoted.
+func renderKey(locale language.Tag, id string, input map[string]any) (string, bool) {
+	names := mapkeys.Sorted(input)
+
+	var sb strings.Builder
+
+	sb.WriteString(locale.String())
+	sb.WriteByte(0)
+	sb.WriteString(id)
+
+	for _, name := range names {
+		sb.WriteByte(0)
+		sb.WriteString(strconv.Quote(name))
+		sb.WriteByte('=')
+
+		switch v := input[name].(type) {
+		default:
+			return "", false
+		case nil:
+			sb.WriteString("nil")
+		case string:
+			sb.WriteString(strconv.Quote(v))
+		case bool:
+			sb.WriteString("b" + strconv.FormatBool(v))
+		case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+			fmt.Fprintf(&sb, "%T%v", v, v)
+		case time.Time:
+			sb.WriteString("t" + v.Format(time.RFC3339Nano) + " " + v.Location().String())
+		}
+	}
+
+	return sb.String(), true
+}
+
+// sprint returns the cached or formatted message.
+func (b *Bundle) sprint(locale language.Tag, id string, input map[string]any) (string, error) {
+	key, ok := renderKey(locale, id, input)
+	if !ok {
+		return b.format(locale, id, input)
+	}
+
+	if s, ok := b.renderCache.get(key); ok {
+		return s, nil
+	}
+
+	s, err := b.format(locale, id, input)
+	if err == nil {
+		b.renderCache.put(key, s)
+	}
+
+	return s, err
+}
+
+// format formats the message without the cache.
+func (b *Bundle) format(locale language.Tag, id string, input map[string]any) (string, error) {
+	tmpl, err := b.Message(locale, id)
+	if err != nil {
+		return "", err
+	}
+
+	return tmpl.Sprint(input) //nolint:wrapcheck
+}
+
+// executeCached writes the cached or formatted message to the writer.
+func (b *Bundle) executeCached(w io.Writer, locale language.Tag, id string, input map[string]any) error {
+	s, err := b.sprint(locale, id, input)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.WriteString(w, s); err != nil {
+		return fmt.Errorf("execute bundle message: %w", err)
+	}
+
+	return nil
+}
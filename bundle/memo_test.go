@@ -0,0 +1,114 @@
+package bundle
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/text/language"
+
+	"go.expect.digital/mf2/catalog"
+)
+
+func TestRenderCache(t *testing.T) {
+	t.Parallel()
+
+	c := catalog.New(language.English)
+	c.Set("status", "Status: { $status }")
+
+	b := New(language.English, WithRenderCache(2, time.Minute))
+	b.Add(c)
+
+	now := time.Now()
+	b.renderCache.now = func() time.Time { return now }
+
+	sprint := func(status any, want string) {
+		t.Helper()
+
+		got, err := b.Sprint(language.English, "status", map[string]any{"status": status})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if want != got {
+			t.Errorf("want '%s', got '%s'", want, got)
+		}
+	}
+
+	sprint("open", "Status: open")
+
+	// the changes to the catalog are not formatted until the cached result expires
+	c.Set("status", "State: { $status }")
+
+	sprint("open", "Status: open")
+	sprint("closed", "State: closed")
+
+	// the input is keyed with the type
+	sprint(1, "State: 1")
+
+	// the least recently used "open" is evicted
+	sprint("open", "State: open")
+
+	var sb strings.Builder
+
+	if err := b.Execute(&sb, language.English, "status", map[string]any{"status": "open"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if want, got := "State: open", sb.String(); want != got {
+		t.Errorf("want '%s', got '%s'", want, got)
+	}
+
+	c.Set("status", "{ $status }")
+
+	now = now.Add(time.Minute)
+
+	sprint("open", "open")
+
+	// not cacheable input
+	sprint(time.Second, "0:00:01")
+
+	c.Set("status", "Status: { $status }")
+
+	sprint(time.Second, "Status: 0:00:01")
+
+	// cleared on add
+	c.Set("status", "State: { $status }")
+	b.Add(c)
+
+	sprint("open", "State: open")
+
+	if hits, misses := b.RenderCacheStats(); hits != 2 || misses != 6 {
+		t.Errorf("want 2 hits and 6 misses, got %d and %d", hits, misses)
+	}
+}
+
+func TestRenderKey(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		a, b map[string]any
+		same bool
+	}{
+		{a: map[string]any{"x": 1, "y": "a"}, b: map[string]any{"y": "a", "x": 1}, same: true},
+		{a: map[string]any{"x": 1}, b: map[string]any{"x": "1"}},
+		{a: map[string]any{"x": 1}, b: map[string]any{"x": int64(1)}},
+		{a: map[string]any{"x": "a\x00y=sb"}, b: map[string]any{"x": "a", "y": "b"}},
+		{
+			a:    map[string]any{"t": time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)},
+			b:    map[string]any{"t": time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)},
+			same: true,
+		},
+	} {
+		a, _ := renderKey(language.English, "id", test.a)
+		b, _ := renderKey(language.English, "id", test.b)
+
+		if same := a == b; same != test.same {
+			t.Errorf("want same %t, got %t for %v and %v", test.same, same, test.a, test.b)
+		}
+	}
+
+	if _, ok := renderKey(language.English, "id", map[string]any{"x": []int{1}}); ok {
+		t.Error("want not cacheable slice")
+	}
+}
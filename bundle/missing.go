@@ -0,0 +1,166 @@
+package bundle
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+
+	"golang.org/x/text/language"
+
+	"go.expect.digital/mf2/parse"
+	"go.expect.digital/mf2/template"
+)
+
+// ErrMissingLocale occurs when none of the bundle locales matches the requested locale.
+var ErrMissingLocale = errors.New("missing locale")
+
+// MissingPolicy is the behaviour of the bundle when the locale or the message is missing.
+type MissingPolicy int
+
+const (
+	// FallbackToDefault returns the message of the default locale, the source locale of the application.
+	// The message of the source locale of the matched catalog, see [catalog.Catalog.SetSourceLocale],
+	// is preferred if the bundle has the locale.
+	FallbackToDefault MissingPolicy = iota
+	// ReturnError returns [catalog.ErrMissingMessage] or [ErrMissingLocale].
+	ReturnError
+	// ReturnID returns the message ID as the formatted message.
+	ReturnID
+)
+
+// String returns the name of the policy.
+func (p MissingPolicy) String() string {
+	switch p {
+	default:
+		return fmt.Sprintf("MissingPolicy(%d)", int(p))
+	case FallbackToDefault:
+		return "fallback"
+	case ReturnError:
+		return "error"
+	case ReturnID:
+		return "id"
+	}
+}
+
+// MarshalText implements [encoding.TextMarshaler].
+func (p MissingPolicy) MarshalText() ([]byte, error) {
+	return []byte(p.String()), nil
+}
+
+// WithMissingMessage sets the policy for messages missing in the matching locale.
+// The default is [FallbackToDefault].
+func WithMissingMessage(policy MissingPolicy) Option {
+	return func(b *Bundle) {
+		b.missingMessage = policy
+	}
+}
+
+// WithMissingLocale sets the policy for requested locales not matching any of the bundle locales.
+// The default is [FallbackToDefault].
+func WithMissingLocale(policy MissingPolicy) Option {
+	return func(b *Bundle) {
+		b.missingLocale = policy
+	}
+}
+
+// WithFallbackHandler sets the handler called on every missing locale or message, e.g. [Report.Add].
+func WithFallbackHandler(handler func(f Fallback)) Option {
+	return func(b *Bundle) {
+		b.onFallback = handler
+	}
+}
+
+// Fallback describes a message that is missing in the requested locale.
+type Fallback struct {
+	ID string `json:"id"`
+	// Locale is the requested locale.
+	Locale language.Tag `json:"locale"`
+	// Matched is the matching bundle locale, or [language.Und] if the locale is missing.
+	Matched language.Tag `json:"matched"`
+	// Used is the locale of the returned message, or [language.Und] if no message is returned.
+	Used   language.Tag  `json:"used"`
+	Policy MissingPolicy `json:"policy"`
+}
+
+// report calls the fallback handler, if any.
+func (b *Bundle) report(f Fallback) {
+	if b.onFallback != nil {
+		b.onFallback(f)
+	}
+}
+
+// idTemplate returns the template formatting the message ID as is.
+func idTemplate(id string) (*template.Template, error) {
+	// the quoted pattern keeps the ID as text, e.g. IDs starting with "."
+	tmpl, err := template.New().Parse("{{" + parse.Text(id).String() + "}}")
+	if err != nil {
+		return nil, fmt.Errorf("bundle message ID: %w", err)
+	}
+
+	return tmpl, nil
+}
+
+// Report collects the fallbacks of the bundle, e.g. for translation completeness dashboards.
+//
+//	report := bundle.NewReport()
+//	b, err := bundle.Load("locales", language.English, bundle.WithFallbackHandler(report.Add))
+//
+// Report is safe for concurrent use.
+type Report struct {
+	counts map[Fallback]int
+	mu     sync.Mutex
+}
+
+// ReportEntry is the fallback and the number of its occurrences.
+type ReportEntry struct {
+	Fallback
+
+	Count int `json:"count"`
+}
+
+// NewReport returns a new empty report.
+func NewReport() *Report {
+	return &Report{counts: make(map[Fallback]int)}
+}
+
+// Add adds the fallback to the report.
+func (r *Report) Add(f Fallback) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.counts[f]++
+}
+
+// Entries returns the fallbacks sorted by the requested locale and the message ID.
+func (r *Report) Entries() []ReportEntry {
+	r.mu.Lock()
+
+	entries := make([]ReportEntry, 0, len(r.counts))
+	for f, count := range r.counts {
+		entries = append(entries, ReportEntry{Fallback: f, Count: count})
+	}
+
+	r.mu.Unlock()
+
+	slices.SortFunc(entries, func(a, b ReportEntry) int {
+		return cmp.Or(
+			cmp.Compare(a.Locale.String(), b.Locale.String()),
+			cmp.Compare(a.ID, b.ID),
+			cmp.Compare(a.Matched.String(), b.Matched.String()),
+			cmp.Compare(a.Used.String(), b.Used.String()),
+			cmp.Compare(a.Policy, b.Policy),
+		)
+	})
+
+	return entries
+}
+
+// Reset removes all fallbacks from the report.
+func (r *Report) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	clear(r.counts)
+}
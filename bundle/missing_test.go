@@ -0,0 +1,151 @@
+package bundle
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"golang.org/x/text/language"
+
+	"go.expect.digital/mf2/catalog"
+)
+
+func TestMissingPolicies(t *testing.T) {
+	t.Parallel()
+
+	newBundle := func(options ...Option) *Bundle {
+		en := catalog.New(language.English)
+		en.Set("greeting", "Hello!")
+		en.Set("farewell", "Goodbye!")
+
+		lv := catalog.New(language.Latvian)
+		lv.Set("greeting", "Sveiki!")
+
+		b := New(language.English, options...)
+		b.Add(en)
+		b.Add(lv)
+
+		return b
+	}
+
+	for _, test := range []struct {
+		name    string
+		wantErr error
+		locale  language.Tag
+		id      string
+		want    string
+		options []Option
+	}{
+		{name: "fallback message", locale: language.Latvian, id: "farewell", want: "Goodbye!"},
+		{name: "fallback locale", locale: language.German, id: "greeting", want: "Hello!"},
+		{name: "missing everywhere", locale: language.Latvian, id: "x", wantErr: catalog.ErrMissingMessage},
+		{
+			name:    "error message",
+			locale:  language.Latvian,
+			id:      "farewell",
+			options: []Option{WithMissingMessage(ReturnError)},
+			wantErr: catalog.ErrMissingMessage,
+		},
+		{
+			name:    "error locale",
+			locale:  language.German,
+			id:      "greeting",
+			options: []Option{WithMissingLocale(ReturnError)},
+			wantErr: ErrMissingLocale,
+		},
+		{
+			name:    "error locale, fallback message",
+			locale:  language.Latvian,
+			id:      "farewell",
+			options: []Option{WithMissingLocale(ReturnError)},
+			want:    "Goodbye!",
+		},
+		{
+			name:    "id",
+			locale:  language.Latvian,
+			id:      ".checkout.{title}",
+			options: []Option{WithMissingMessage(ReturnID)},
+			want:    ".checkout.{title}",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := newBundle(test.options...).Sprint(test.locale, test.id, nil)
+			if !errors.Is(err, test.wantErr) {
+				t.Errorf("want '%v', got '%v'", test.wantErr, err)
+			}
+
+			if test.want != got {
+				t.Errorf("want '%s', got '%s'", test.want, got)
+			}
+		})
+	}
+}
+
+func TestFallbackSourceLocale(t *testing.T) {
+	t.Parallel()
+
+	en := catalog.New(language.English)
+	en.Set("greeting", "Hello!")
+	en.Set("farewell", "Goodbye!")
+
+	de := catalog.New(language.German)
+	de.Set("greeting", "Hallo!")
+	de.Set("farewell", "Tschüss!")
+
+	// translated from German
+	lb := catalog.New(language.MustParse("lb"))
+	lb.SetSourceLocale(language.German)
+	lb.Set("greeting", "Moien!")
+
+	b := New(language.English)
+	b.Add(en)
+	b.Add(de)
+	b.Add(lb)
+
+	if got, err := b.Sprint(language.MustParse("lb"), "farewell", nil); err != nil || got != "Tschüss!" {
+		t.Errorf("want 'Tschüss!', got '%s' (%v)", got, err)
+	}
+}
+
+func TestReport(t *testing.T) {
+	t.Parallel()
+
+	lv := catalog.New(language.Latvian)
+	lv.Set("greeting", "Sveiki!")
+
+	en := catalog.New(language.English)
+	en.Set("farewell", "Goodbye!")
+
+	report := NewReport()
+
+	b := New(language.English, WithFallbackHandler(report.Add))
+	b.Add(en)
+	b.Add(lv)
+
+	for range 2 {
+		_, _ = b.Sprint(language.Latvian, "farewell", nil)
+	}
+
+	_, _ = b.Sprint(language.Latvian, "greeting", nil) // found
+	_, _ = b.Sprint(language.German, "greeting", nil)
+
+	got, err := json.Marshal(report.Entries())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `[{"id":"greeting","locale":"de","matched":"und","used":"und","policy":"fallback","count":1},` +
+		`{"id":"farewell","locale":"lv","matched":"lv","used":"en","policy":"fallback","count":2}]`
+
+	if want != string(got) {
+		t.Errorf("want '%s', got '%s'", want, got)
+	}
+
+	report.Reset()
+
+	if entries := report.Entries(); len(entries) != 0 {
+		t.Errorf("want empty report, got %v", entries)
+	}
+}
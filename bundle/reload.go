@@ -0,0 +1,210 @@
+package bundle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"slices"
+	"time"
+
+	"golang.org/x/text/language"
+
+	"go.expect.digital/mf2/catalog"
+)
+
+// snapshot is the state of the catalog files in the file system.
+type snapshot struct {
+	files   map[language.Tag][]string // catalog files by locale
+	stamps  map[string]fileStamp      // by file name
+	locales []language.Tag            // in the order of the files
+}
+
+// fileStamp identifies the version of the file.
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// scan reads the locales and the stamps of the JSON catalog files in the file system.
+func scan(fsys fs.FS) (*snapshot, error) {
+	snap := &snapshot{
+		files:  make(map[language.Tag][]string),
+		stamps: make(map[string]fileStamp),
+	}
+
+	err := fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || path.Ext(name) != ".json" {
+			return err
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err //nolint:wrapcheck
+		}
+
+		locale, err := readLocale(fsys, name)
+		if err != nil {
+			return fmt.Errorf(`file "%s": %w`, name, err)
+		}
+
+		if _, ok := snap.files[locale]; !ok {
+			snap.locales = append(snap.locales, locale)
+		}
+
+		snap.files[locale] = append(snap.files[locale], name)
+		snap.stamps[name] = fileStamp{modTime: info.ModTime(), size: info.Size()}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	return snap, nil
+}
+
+// changed returns true if the catalog files of the locale differ in the snapshots.
+func (s *snapshot) changed(next *snapshot, locale language.Tag) bool {
+	files := s.files[locale]
+	if !slices.Equal(files, next.files[locale]) {
+		return true
+	}
+
+	for _, name := range files {
+		if s.stamps[name] != next.stamps[name] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Reload reads the changed catalog files of the bundle loaded by [Load] or [LoadFS].
+// Files are changed if added, removed, or their modification time or size differ.
+//
+// The changed locales in use are read and validated before the compiled templates are swapped,
+// other changed locales are read on the first use. If any of the files is invalid, the bundle
+// keeps the previous messages of all locales.
+func (b *Bundle) Reload() error {
+	if b.fsys == nil {
+		return nil
+	}
+
+	b.reloadMu.Lock()
+	defer b.reloadMu.Unlock()
+
+	next, err := scan(b.fsys)
+	if err != nil {
+		return fmt.Errorf("reload bundle: %w", err)
+	}
+
+	prev := b.loaded
+
+	var changed []language.Tag
+
+	for _, locale := range append(slices.Clone(prev.locales), next.locales...) {
+		if !slices.Contains(changed, locale) && prev.changed(next, locale) {
+			changed = append(changed, locale)
+		}
+	}
+
+	if len(changed) == 0 {
+		return nil
+	}
+
+	// read the changed locales in use, the bundle serves the previous messages meanwhile
+
+	catalogs := make(map[language.Tag]*catalog.Catalog)
+
+	var errs []error
+
+	for _, locale := range changed {
+		b.mu.RLock()
+		_, inUse := b.catalogs[locale]
+		_, pending := b.files[locale]
+		b.mu.RUnlock()
+
+		files, ok := next.files[locale]
+		if !ok || !inUse || pending {
+			continue
+		}
+
+		c, err := b.readCatalog(locale, files)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("locale %s: %w", locale, err))
+			continue
+		}
+
+		catalogs[locale] = c
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("reload bundle: %w", err)
+	}
+
+	// swap
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.renderCache != nil {
+		b.renderCache.clear()
+	}
+
+	for _, locale := range changed {
+		files, ok := next.files[locale]
+
+		switch c, read := catalogs[locale]; {
+		case !ok: // removed
+			delete(b.catalogs, locale)
+			delete(b.files, locale)
+			b.removeLocale(locale)
+		case read:
+			b.catalogs[locale] = c
+		default:
+			delete(b.catalogs, locale)
+			b.files[locale] = files
+			b.addLocale(locale)
+		}
+	}
+
+	b.loaded = next
+
+	return nil
+}
+
+// removeLocale removes the locale from the matcher, except the default locale.
+// The caller must hold the lock.
+func (b *Bundle) removeLocale(locale language.Tag) {
+	if locale == b.defaultLocale {
+		return
+	}
+
+	b.locales = slices.DeleteFunc(b.locales, func(v language.Tag) bool { return v == locale })
+	b.matcher = language.NewMatcher(b.locales)
+}
+
+// Watch polls the catalog files every interval and reloads the changed files until ctx is done,
+// see [Bundle.Reload]. It is intended for development and staging, translation fixes are rolled out
+// without restarting the service:
+//
+//	go b.Watch(ctx, time.Second, func(err error) { log.Print(err) })
+//
+// The reload errors are passed to onError, if not nil, on every poll until the files are fixed.
+func (b *Bundle) Watch(ctx context.Context, interval time.Duration, onError func(err error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := b.Reload(); err != nil && onError != nil {
+				onError(err)
+			}
+		}
+	}
+}
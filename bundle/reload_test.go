@@ -0,0 +1,94 @@
+package bundle
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"golang.org/x/text/language"
+)
+
+func TestReload(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+
+	file := func(locale, greeting string, modTime time.Time) *fstest.MapFile {
+		return &fstest.MapFile{
+			Data:    []byte(`{"locale": "` + locale + `", "messages": {"greeting": {"message": "` + greeting + `"}}}`),
+			ModTime: modTime,
+		}
+	}
+
+	fsys := fstest.MapFS{
+		"en.json": file("en", "Hello!", now),
+		"lv.json": file("lv", "Sveiki!", now),
+	}
+
+	b, err := LoadFS(fsys, language.English)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert := func(locale language.Tag, want string) {
+		t.Helper()
+
+		if got, err := b.Sprint(locale, "greeting", nil); err != nil || want != got {
+			t.Errorf("want '%s', got '%s' (%v)", want, got, err)
+		}
+	}
+
+	assert(language.Latvian, "Sveiki!")
+
+	// unchanged
+	if err := b.Reload(); err != nil {
+		t.Fatal(err)
+	}
+
+	// changed locale in use, invalid file keeps the previous messages
+	fsys["lv.json"] = file("lv", "Sveiki, { $name", now.Add(time.Second))
+
+	if err := b.Reload(); err == nil {
+		t.Error("want error, got nil")
+	}
+
+	assert(language.Latvian, "Sveiki!")
+
+	// changed locale in use
+	fsys["lv.json"] = file("lv", "Čau!", now.Add(2*time.Second))
+
+	if err := b.Reload(); err != nil {
+		t.Fatal(err)
+	}
+
+	assert(language.Latvian, "Čau!")
+
+	// added and removed locales
+	fsys["de.json"] = file("de", "Hallo!", now)
+	delete(fsys, "lv.json")
+
+	if err := b.Reload(); err != nil {
+		t.Fatal(err)
+	}
+
+	if want, got := []language.Tag{language.English, language.German}, b.Locales(); !slices.Equal(want, got) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+
+	assert(language.German, "Hallo!")
+	assert(language.Latvian, "Hello!")
+}
+
+func TestWatch(t *testing.T) {
+	t.Parallel()
+
+	b := New(language.English)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	// returns when ctx is done, a bundle without files has nothing to reload
+	b.Watch(ctx, time.Millisecond, func(err error) { t.Error(err) })
+}
@@ -0,0 +1,131 @@
+package catalog
+
+import (
+	"encoding/gob"
+	"fmt"
+	"io"
+
+	"golang.org/x/text/language"
+
+	"go.expect.digital/mf2/template"
+)
+
+// binaryCatalog is the binary catalog format, the messages are stored with their compiled ASTs.
+type binaryCatalog struct {
+	Locale       string
+	SourceLocale string
+	Messages     []binaryMessage
+}
+
+type binaryMessage struct {
+	Metadata    map[string]string
+	ID          string
+	Text        string
+	Description string
+	Notes       []string
+	Source      string
+	Status      Status
+	Locale      string
+	AST         []byte // see [parse.AST.MarshalBinary]
+}
+
+// SaveBinary writes the catalog in the binary catalog format with all messages compiled,
+// e.g. to pre-compile the catalogs at build time. Messages are sorted by ID.
+func (c *Catalog) SaveBinary(w io.Writer) error {
+	errorf := func(format string, args ...any) error {
+		return fmt.Errorf("save binary catalog: "+format, args...)
+	}
+
+	file := binaryCatalog{Locale: c.locale.String(), SourceLocale: localeString(c.SourceLocale())}
+
+	for _, id := range c.IDs() {
+		msg, ok := c.Message(id)
+		if !ok {
+			continue // deleted meanwhile
+		}
+
+		tmpl, err := c.Template(id)
+		if err != nil {
+			return errorf("%w", err)
+		}
+
+		tree, err := tmpl.MarshalBinary()
+		if err != nil {
+			return errorf(`message "%s": %w`, id, err)
+		}
+
+		file.Messages = append(file.Messages, binaryMessage{
+			ID:          id,
+			Text:        msg.Text,
+			Description: msg.Description,
+			Notes:       msg.Notes,
+			Metadata:    msg.Metadata,
+			Status:      msg.Status,
+			Source:      msg.Source,
+			Locale:      localeString(msg.Locale),
+			AST:         tree,
+		})
+	}
+
+	if err := gob.NewEncoder(w).Encode(file); err != nil {
+		return errorf("%w", err)
+	}
+
+	return nil
+}
+
+// LoadBinary reads the catalog written by [Catalog.SaveBinary]. The options are applied to every template.
+//
+// The compiled messages are decoded without parsing, invalid data is reported as [ErrInvalidFile].
+func LoadBinary(r io.Reader, options ...template.Option) (*Catalog, error) {
+	errorf := func(format string, args ...any) (*Catalog, error) {
+		return nil, fmt.Errorf("load binary catalog: %w: "+format, append([]any{ErrInvalidFile}, args...)...)
+	}
+
+	var file binaryCatalog
+
+	if err := gob.NewDecoder(r).Decode(&file); err != nil {
+		return errorf("%w", err)
+	}
+
+	locale, err := language.Parse(file.Locale)
+	if err != nil {
+		return errorf(`locale "%s": %w`, file.Locale, err)
+	}
+
+	c := New(locale, options...)
+
+	if c.sourceLocale, err = parseLocale(file.SourceLocale); err != nil {
+		return errorf(`source locale "%s": %w`, file.SourceLocale, err)
+	}
+
+	for _, msg := range file.Messages {
+		if msg.ID == "" {
+			return errorf("empty message ID")
+		}
+
+		message := Message{
+			ID:          msg.ID,
+			Text:        msg.Text,
+			Description: msg.Description,
+			Notes:       msg.Notes,
+			Metadata:    msg.Metadata,
+			Status:      msg.Status,
+			Source:      msg.Source,
+		}
+
+		if message.Locale, err = parseLocale(msg.Locale); err != nil {
+			return errorf(`message "%s": locale "%s": %w`, msg.ID, msg.Locale, err)
+		}
+
+		tmpl, err := template.New(c.templateOptions(message)...).ParseBinary(msg.AST)
+		if err != nil {
+			return errorf(`message "%s": %w`, msg.ID, err)
+		}
+
+		c.messages[msg.ID] = message
+		c.templates[msg.ID] = tmpl
+	}
+
+	return c, nil
+}
@@ -0,0 +1,58 @@
+package catalog
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"golang.org/x/text/language"
+)
+
+func TestLoadSaveBinary(t *testing.T) {
+	t.Parallel()
+
+	c := New(language.Latvian)
+	c.Set("apples", ".match { $count :number } one {{{ $count } ābols}} * {{{ $count } āboli}}")
+	c.SetMessage(Message{
+		ID:          "greeting",
+		Text:        "Sveiki, { $name }!",
+		Description: "Greeting on the home page",
+		Metadata:    map[string]string{"source": "home.go:12"},
+	})
+
+	var buf bytes.Buffer
+
+	if err := c.SaveBinary(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := LoadBinary(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got.Locale() != language.Latvian {
+		t.Errorf("want '%s', got '%s'", language.Latvian, got.Locale())
+	}
+
+	if msg, _ := got.Message("greeting"); msg.Description != "Greeting on the home page" || msg.Metadata["source"] != "home.go:12" {
+		t.Errorf("want message details, got %+v", msg)
+	}
+
+	for id, want := range map[string]string{"apples": "1 ābols", "greeting": "Sveiki, Jānis!"} {
+		if s, err := got.Sprint(id, map[string]any{"count": 1, "name": "Jānis"}); err != nil || want != s {
+			t.Errorf("want '%s', got '%s' (%v)", want, s, err)
+		}
+	}
+
+	// invalid message
+	c.Set("invalid", "{ $x")
+
+	if err := c.SaveBinary(&buf); err == nil {
+		t.Error("want error, got nil")
+	}
+
+	if _, err := LoadBinary(bytes.NewReader([]byte("invalid"))); !errors.Is(err, ErrInvalidFile) {
+		t.Errorf("want '%s', got '%v'", ErrInvalidFile, err)
+	}
+}
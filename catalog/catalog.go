@@ -0,0 +1,285 @@
+// Package catalog stores MF2 messages of a single locale by message ID.
+package catalog
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"golang.org/x/text/language"
+
+	"go.expect.digital/mf2/internal/mapkeys"
+	"go.expect.digital/mf2/template"
+)
+
+// ErrMissingMessage occurs when the catalog does not contain the message by ID.
+var ErrMissingMessage = errors.New("missing message")
+
+// Message is a single MF2 message in the catalog.
+type Message struct {
+	Metadata    map[string]string // arbitrary key-value pairs, e.g. source reference
+	ID          string
+	Text        string // MF2 formatted message
+	Description string // context for translators
+	// Notes are the additional notes for translators, e.g. the meaning of a term or a link to a screenshot.
+	Notes []string
+	// Source is the source message the translation is based on, see [Catalog.Merge].
+	Source string
+	// Status is the state of the translation, see [Catalog.Merge]. Empty if translated.
+	Status Status
+	// Locale is the locale the text is written in if other than the catalog locale,
+	// e.g. the untranslated message of the source catalog, see [Catalog.Merge].
+	// The message is formatted in its locale.
+	Locale language.Tag
+}
+
+// Catalog is a collection of MF2 messages of a single locale.
+// Messages are compiled to templates on first use.
+//
+// Catalog is safe for concurrent use.
+type Catalog struct {
+	messages  map[string]Message
+	templates map[string]*template.Template
+	options   []template.Option
+	locale    language.Tag
+	// sourceLocale is the locale of the source catalog the messages are translated from.
+	sourceLocale language.Tag
+	mu           sync.RWMutex
+}
+
+// New returns a new empty catalog. The options are applied to every compiled template.
+func New(locale language.Tag, options ...template.Option) *Catalog {
+	return &Catalog{
+		locale:    locale,
+		options:   options,
+		messages:  make(map[string]Message),
+		templates: make(map[string]*template.Template),
+	}
+}
+
+// Locale returns the locale of the catalog.
+func (c *Catalog) Locale() language.Tag {
+	return c.locale
+}
+
+// SourceLocale returns the locale of the source catalog the messages are translated from,
+// [language.Und] if not declared. See [Catalog.SetSourceLocale].
+func (c *Catalog) SourceLocale() language.Tag {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.sourceLocale
+}
+
+// SetSourceLocale declares the locale of the source catalog the messages are translated from,
+// e.g. the bundle falls back to the source locale when the message is missing.
+// [Catalog.Merge] sets it to the locale of the source catalog.
+func (c *Catalog) SetSourceLocale(locale language.Tag) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.sourceLocale = locale
+}
+
+// Set adds or replaces the message.
+func (c *Catalog) Set(id, text string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.messages[id] = Message{ID: id, Text: text}
+	delete(c.templates, id)
+}
+
+// SetMessage adds or replaces the message, including its description and metadata.
+func (c *Catalog) SetMessage(msg Message) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.messages[msg.ID] = msg
+	delete(c.templates, msg.ID)
+}
+
+// Description returns the description of the message for translators, empty if the message is missing.
+func (c *Catalog) Description(id string) string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.messages[id].Description
+}
+
+// SetDescription sets the description of the message for translators.
+func (c *Catalog) SetDescription(id, description string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	msg, ok := c.messages[id]
+	if !ok {
+		return fmt.Errorf(`set description: %w "%s"`, ErrMissingMessage, id)
+	}
+
+	msg.Description = description
+	c.messages[id] = msg
+
+	return nil
+}
+
+// Notes returns the notes of the message for translators, nil if the message is missing.
+func (c *Catalog) Notes(id string) []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return slices.Clone(c.messages[id].Notes)
+}
+
+// AddNote adds the note for translators to the message.
+func (c *Catalog) AddNote(id, note string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	msg, ok := c.messages[id]
+	if !ok {
+		return fmt.Errorf(`add note: %w "%s"`, ErrMissingMessage, id)
+	}
+
+	msg.Notes = append(slices.Clip(msg.Notes), note)
+	c.messages[id] = msg
+
+	return nil
+}
+
+// Delete removes the message.
+func (c *Catalog) Delete(id string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.messages, id)
+	delete(c.templates, id)
+}
+
+// Message returns the message by ID.
+func (c *Catalog) Message(id string) (Message, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	msg, ok := c.messages[id]
+
+	return msg, ok
+}
+
+// IDs returns sorted message IDs.
+func (c *Catalog) IDs() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return mapkeys.Sorted(c.messages)
+}
+
+// IDSeparator separates the namespaces of hierarchical message IDs, e.g. "checkout.cart.title".
+const IDSeparator = "."
+
+// Namespace returns sorted message IDs in the namespace, e.g. "checkout.cart.title" and "checkout.total"
+// in the "checkout" namespace. The message with the namespace ID is included, if any.
+func (c *Catalog) Namespace(namespace string) []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var ids []string
+
+	for id := range c.messages {
+		if InNamespace(id, namespace) {
+			ids = append(ids, id)
+		}
+	}
+
+	sort.Strings(ids)
+
+	return ids
+}
+
+// InNamespace returns true if the message ID is the namespace or belongs to the namespace.
+// All IDs belong to the empty namespace.
+func InNamespace(id, namespace string) bool {
+	if namespace == "" || id == namespace {
+		return true
+	}
+
+	return strings.HasPrefix(id, namespace) && strings.HasPrefix(id[len(namespace):], IDSeparator)
+}
+
+// Len returns the number of messages.
+func (c *Catalog) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return len(c.messages)
+}
+
+// Template returns the compiled template of the message.
+func (c *Catalog) Template(id string) (*template.Template, error) {
+	c.mu.RLock()
+	tmpl, ok := c.templates[id]
+	c.mu.RUnlock()
+
+	if ok {
+		return tmpl, nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if tmpl, ok := c.templates[id]; ok {
+		return tmpl, nil
+	}
+
+	msg, ok := c.messages[id]
+	if !ok {
+		return nil, fmt.Errorf(`%w "%s"`, ErrMissingMessage, id)
+	}
+
+	tmpl, err := template.New(c.templateOptions(msg)...).Parse(msg.Text)
+	if err != nil {
+		return nil, fmt.Errorf(`compile message "%s": %w`, id, err)
+	}
+
+	c.templates[id] = tmpl
+
+	return tmpl, nil
+}
+
+// templateOptions returns the options of the message template, the message is formatted in its locale.
+func (c *Catalog) templateOptions(msg Message) []template.Option {
+	locale := c.locale
+	if msg.Locale != language.Und {
+		locale = msg.Locale
+	}
+
+	return append([]template.Option{
+		template.WithLocale(locale),
+		template.WithSourceLocale(locale),
+		template.WithID(msg.ID),
+	}, c.options...)
+}
+
+// Execute writes the formatted message to the writer.
+func (c *Catalog) Execute(w io.Writer, id string, input map[string]any) error {
+	tmpl, err := c.Template(id)
+	if err != nil {
+		return err
+	}
+
+	return tmpl.Execute(w, input) //nolint:wrapcheck
+}
+
+// Sprint returns the formatted message.
+func (c *Catalog) Sprint(id string, input map[string]any) (string, error) {
+	tmpl, err := c.Template(id)
+	if err != nil {
+		return "", err
+	}
+
+	return tmpl.Sprint(input) //nolint:wrapcheck
+}
@@ -0,0 +1,123 @@
+package catalog
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"golang.org/x/text/language"
+
+	"go.expect.digital/mf2"
+)
+
+func TestCatalog(t *testing.T) {
+	t.Parallel()
+
+	c := New(language.Latvian)
+	c.Set("greeting", "Sveiki, { $name }!")
+	c.Set("apples", ".match { $count :number } one {{{ $count } ābols}} * {{{ $count } āboli}}")
+	c.Set("broken", "Hello { $name")
+
+	if want, got := []string{"apples", "broken", "greeting"}, c.IDs(); !slices.Equal(want, got) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+
+	for _, test := range []struct {
+		input map[string]any
+		id    string
+		want  string
+	}{
+		{id: "greeting", input: map[string]any{"name": "Jānis"}, want: "Sveiki, Jānis!"},
+		{id: "apples", input: map[string]any{"count": 1}, want: "1 ābols"},
+		{id: "apples", input: map[string]any{"count": 1.5}, want: "1,5 āboli"},
+	} {
+		got, err := c.Sprint(test.id, test.input)
+		if err != nil {
+			t.Error(err)
+		}
+
+		if test.want != got {
+			t.Errorf("want '%s', got '%s'", test.want, got)
+		}
+	}
+
+	if _, err := c.Sprint("missing", nil); !errors.Is(err, ErrMissingMessage) {
+		t.Errorf("want '%s', got '%s'", ErrMissingMessage, err)
+	}
+
+	if _, err := c.Sprint("broken", nil); !errors.Is(err, mf2.ErrSyntax) {
+		t.Errorf("want '%s', got '%s'", mf2.ErrSyntax, err)
+	}
+
+	// replacing the message invalidates the compiled template
+	c.Set("greeting", "Čau, { $name }!")
+
+	if got, _ := c.Sprint("greeting", map[string]any{"name": "Jānis"}); got != "Čau, Jānis!" {
+		t.Errorf("want 'Čau, Jānis!', got '%s'", got)
+	}
+}
+
+func TestDescriptionNotes(t *testing.T) {
+	t.Parallel()
+
+	c := New(language.Latvian)
+	c.Set("greeting", "Sveiki!")
+
+	if err := c.SetDescription("greeting", "Home page"); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, note := range []string{"Informal", "Shown once a day"} {
+		if err := c.AddNote("greeting", note); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if want, got := "Home page", c.Description("greeting"); want != got {
+		t.Errorf("want '%s', got '%s'", want, got)
+	}
+
+	notes := c.Notes("greeting")
+	if want := []string{"Informal", "Shown once a day"}; !slices.Equal(want, notes) {
+		t.Errorf("want %v, got %v", want, notes)
+	}
+
+	// the notes are a copy
+	notes[0] = "changed"
+
+	if got := c.Notes("greeting")[0]; got != "Informal" {
+		t.Errorf("want 'Informal', got '%s'", got)
+	}
+
+	if err := c.AddNote("missing", "note"); !errors.Is(err, ErrMissingMessage) {
+		t.Errorf("want '%s', got '%v'", ErrMissingMessage, err)
+	}
+
+	if err := c.SetDescription("missing", "description"); !errors.Is(err, ErrMissingMessage) {
+		t.Errorf("want '%s', got '%v'", ErrMissingMessage, err)
+	}
+}
+
+func TestNamespace(t *testing.T) {
+	t.Parallel()
+
+	c := New(language.English)
+
+	for _, id := range []string{"checkout", "checkout.cart.title", "checkout.total", "checkoutx", "home.title"} {
+		c.Set(id, id)
+	}
+
+	for _, test := range []struct {
+		namespace string
+		want      []string
+	}{
+		{namespace: "checkout", want: []string{"checkout", "checkout.cart.title", "checkout.total"}},
+		{namespace: "checkout.cart", want: []string{"checkout.cart.title"}},
+		{namespace: "missing"},
+		{namespace: "", want: c.IDs()},
+	} {
+		if got := c.Namespace(test.namespace); !slices.Equal(test.want, got) {
+			t.Errorf("%s: want %v, got %v", test.namespace, test.want, got)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package catalog
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"maps"
+)
+
+// ErrIDCollision occurs when two different messages have the same generated ID, see [GenerateID].
+var ErrIDCollision = errors.New("message ID collision")
+
+// MeaningKey is the metadata key of the meaning of the message with the generated ID, see [Catalog.AddGenerated].
+const MeaningKey = "meaning"
+
+// generatedIDLength is the length of the generated ID, 48 bits of the hash.
+const generatedIDLength = 8
+
+// GenerateID returns the stable ID of the message derived from the message and its meaning,
+// e.g. "Open" as a verb and "Open" as an adjective. The ID is the URL-safe base64 of the SHA-256 hash
+// of the canonical message, see [parse.Canonical], so the formatting and the order of the options
+// do not change the ID. The invalid message is hashed as is.
+func GenerateID(text, meaning string) string {
+	sum := sha256.Sum256([]byte(canonicalText(text) + "\x1f" + meaning))
+
+	return base64.RawURLEncoding.EncodeToString(sum[:])[:generatedIDLength]
+}
+
+// AddGenerated adds the message with the generated ID, see [GenerateID], and returns the added message.
+// The ID of the message is ignored, the non-empty meaning is stored in the metadata, see [MeaningKey].
+//
+// Adding the same message again replaces it, e.g. to update the description. A different message
+// with the same ID is not added and is reported as [ErrIDCollision], change the meaning to resolve it.
+func (c *Catalog) AddGenerated(msg Message, meaning string) (Message, error) {
+	msg.ID = GenerateID(msg.Text, meaning)
+
+	if meaning != "" {
+		msg.Metadata = maps.Clone(msg.Metadata)
+		if msg.Metadata == nil {
+			msg.Metadata = make(map[string]string, 1)
+		}
+
+		msg.Metadata[MeaningKey] = meaning
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if existing, ok := c.messages[msg.ID]; ok && !sameContent(existing, msg) {
+		return Message{}, fmt.Errorf(`add generated ID "%s": %w: "%s" and "%s"`, msg.ID, ErrIDCollision, existing.Text, msg.Text)
+	}
+
+	c.messages[msg.ID] = msg
+	delete(c.templates, msg.ID)
+
+	return msg, nil
+}
+
+// sameContent reports whether the messages have the same canonical text and meaning.
+func sameContent(a, b Message) bool {
+	return a.Metadata[MeaningKey] == b.Metadata[MeaningKey] && canonicalText(a.Text) == canonicalText(b.Text)
+}
@@ -0,0 +1,70 @@
+package catalog
+
+import (
+	"errors"
+	"testing"
+
+	"golang.org/x/text/language"
+)
+
+func TestGenerateID(t *testing.T) {
+	t.Parallel()
+
+	id := GenerateID("{ $n :number style=percent minimumFractionDigits=2 }", "")
+
+	if len(id) != generatedIDLength {
+		t.Errorf("want %d characters, got '%s'", generatedIDLength, id)
+	}
+
+	// the canonical message has the same ID
+	if got := GenerateID("{$n :number minimumFractionDigits=2 style=percent}", ""); id != got {
+		t.Errorf("want '%s', got '%s'", id, got)
+	}
+
+	if GenerateID("Open", "verb") == GenerateID("Open", "adjective") {
+		t.Error("want different IDs of different meanings")
+	}
+
+	if GenerateID("Open", "") == GenerateID("Close", "") {
+		t.Error("want different IDs of different messages")
+	}
+}
+
+func TestAddGenerated(t *testing.T) {
+	t.Parallel()
+
+	c := New(language.English)
+
+	verb, err := c.AddGenerated(Message{Text: "Open", Description: "Button"}, "verb")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if verb.ID != GenerateID("Open", "verb") || verb.Metadata[MeaningKey] != "verb" {
+		t.Errorf("want generated ID and meaning, got %+v", verb)
+	}
+
+	if _, err := c.AddGenerated(Message{Text: "Open"}, "adjective"); err != nil {
+		t.Fatal(err)
+	}
+
+	// the same message replaces the existing one
+	if _, err := c.AddGenerated(Message{Text: "Open", Description: "Toolbar button"}, "verb"); err != nil {
+		t.Fatal(err)
+	}
+
+	if want, got := "Toolbar button", c.Description(verb.ID); want != got {
+		t.Errorf("want '%s', got '%s'", want, got)
+	}
+
+	if c.Len() != 2 { //nolint:mnd
+		t.Errorf("want 2 messages, got %d", c.Len())
+	}
+
+	// a different message with the same ID
+	c.SetMessage(Message{ID: GenerateID("Save", ""), Text: "Save as"})
+
+	if _, err := c.AddGenerated(Message{Text: "Save"}, ""); !errors.Is(err, ErrIDCollision) {
+		t.Errorf("want '%s', got '%v'", ErrIDCollision, err)
+	}
+}
@@ -0,0 +1,206 @@
+package catalog
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"golang.org/x/text/language"
+
+	"go.expect.digital/mf2/internal/mapkeys"
+	"go.expect.digital/mf2/parse"
+	"go.expect.digital/mf2/template"
+)
+
+// ErrInvalidFile occurs when the catalog file does not conform to the JSON catalog format.
+var ErrInvalidFile = errors.New("invalid catalog file")
+
+// jsonCatalog is the JSON catalog format:
+//
+//	{
+//	  "locale": "lv",
+//	  "sourceLocale": "en",
+//	  "messages": {
+//	    "greeting": {
+//	      "message": "Sveiki, { $name }!",
+//	      "description": "Greeting on the home page",
+//	      "notes": ["Informal greeting", "Shown once a day"],
+//	      "metadata": {"source": "home.go:12"},
+//	      "status": "changed",
+//	      "sourceMessage": "Hello, { $name }!"
+//	    },
+//	    "farewell": {
+//	      "message": "Goodbye, { $name }!",
+//	      "status": "new",
+//	      "locale": "en"
+//	    }
+//	  }
+//	}
+//
+// The "locale" is a BCP 47 language tag. The optional "sourceLocale" is the locale of the source catalog,
+// see [Catalog.SetSourceLocale]. Each message requires the "message" in MF2 syntax, "description",
+// "notes", "metadata", "status", "sourceMessage" and "locale" of the message, see [Message.Locale], are optional.
+type jsonCatalog struct { //nolint:govet // field order defines the order in the file
+	Locale       string                 `json:"locale"`
+	SourceLocale string                 `json:"sourceLocale,omitempty"`
+	Messages     map[string]jsonMessage `json:"messages"`
+}
+
+type jsonMessage struct { //nolint:govet // field order defines the order in the file
+	Message     *string           `json:"message"`
+	Description string            `json:"description,omitempty"`
+	Notes       []string          `json:"notes,omitempty"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
+	Status      Status            `json:"status,omitempty"`
+	Source      string            `json:"sourceMessage,omitempty"`
+	Locale      string            `json:"locale,omitempty"`
+}
+
+// Load reads the catalog in the JSON catalog format. The options are applied to every compiled template.
+//
+// The file is validated before loading: unknown fields, a missing or invalid locale, and missing
+// or syntactically invalid messages are reported as [ErrInvalidFile], all invalid messages at once.
+func Load(r io.Reader, options ...template.Option) (*Catalog, error) {
+	errorf := func(format string, args ...any) (*Catalog, error) {
+		return nil, fmt.Errorf("load catalog: %w: "+format, append([]any{ErrInvalidFile}, args...)...)
+	}
+
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+
+	var file jsonCatalog
+
+	if err := dec.Decode(&file); err != nil {
+		return errorf("%w", err)
+	}
+
+	if dec.More() {
+		return errorf("unexpected data after catalog")
+	}
+
+	if file.Locale == "" {
+		return errorf(`missing "locale"`)
+	}
+
+	locale, err := language.Parse(file.Locale)
+	if err != nil {
+		return errorf(`locale "%s": %w`, file.Locale, err)
+	}
+
+	if file.Messages == nil {
+		return errorf(`missing "messages"`)
+	}
+
+	c := New(locale, options...)
+
+	if c.sourceLocale, err = parseLocale(file.SourceLocale); err != nil {
+		return errorf(`source locale "%s": %w`, file.SourceLocale, err)
+	}
+
+	var errs []error
+
+	for _, id := range mapkeys.Sorted(file.Messages) {
+		msg := file.Messages[id]
+
+		if err := validateMessage(id, msg); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		msgLocale, err := parseLocale(msg.Locale)
+		if err != nil {
+			errs = append(errs, fmt.Errorf(`message "%s": locale "%s": %w`, id, msg.Locale, err))
+			continue
+		}
+
+		c.messages[id] = Message{
+			ID:          id,
+			Text:        *msg.Message,
+			Description: msg.Description,
+			Notes:       msg.Notes,
+			Metadata:    msg.Metadata,
+			Status:      msg.Status,
+			Source:      msg.Source,
+			Locale:      msgLocale,
+		}
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return errorf("%w", err)
+	}
+
+	return c, nil
+}
+
+// validateMessage validates the message in the JSON catalog format.
+func validateMessage(id string, msg jsonMessage) error {
+	if id == "" {
+		return errors.New("empty message ID")
+	}
+
+	if msg.Message == nil {
+		return fmt.Errorf(`message "%s": missing "message"`, id)
+	}
+
+	if _, err := parse.Parse(*msg.Message); err != nil {
+		return fmt.Errorf(`message "%s": %w`, id, err)
+	}
+
+	return nil
+}
+
+// Save writes the catalog in the JSON catalog format. Messages are sorted by ID.
+func (c *Catalog) Save(w io.Writer) error {
+	c.mu.RLock()
+
+	file := jsonCatalog{
+		Locale:       c.locale.String(),
+		SourceLocale: localeString(c.sourceLocale),
+		Messages:     make(map[string]jsonMessage, len(c.messages)),
+	}
+
+	for id, msg := range c.messages {
+		text := msg.Text
+
+		file.Messages[id] = jsonMessage{
+			Message:     &text,
+			Description: msg.Description,
+			Notes:       msg.Notes,
+			Metadata:    msg.Metadata,
+			Status:      msg.Status,
+			Source:      msg.Source,
+			Locale:      localeString(msg.Locale),
+		}
+	}
+
+	c.mu.RUnlock()
+
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+
+	if err := enc.Encode(file); err != nil {
+		return fmt.Errorf("save catalog: %w", err)
+	}
+
+	return nil
+}
+
+// parseLocale parses the optional locale, the empty locale is [language.Und].
+func parseLocale(s string) (language.Tag, error) {
+	if s == "" {
+		return language.Und, nil
+	}
+
+	return language.Parse(s) //nolint:wrapcheck
+}
+
+// localeString returns the optional locale, empty for [language.Und].
+func localeString(locale language.Tag) string {
+	if locale == language.Und {
+		return ""
+	}
+
+	return locale.String()
+}
@@ -0,0 +1,132 @@
+package catalog
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"golang.org/x/text/language"
+
+	"go.expect.digital/mf2"
+)
+
+func TestLoadSave(t *testing.T) {
+	t.Parallel()
+
+	const file = `{
+  "locale": "lv",
+  "messages": {
+    "apples": {
+      "message": ".match { $count :number } one {{{ $count } ābols}} * {{{ $count } āboli}}"
+    },
+    "greeting": {
+      "message": "Sveiki, { $name }! <3",
+      "description": "Greeting on the home page",
+      "notes": [
+        "Informal greeting",
+        "Shown once a day"
+      ],
+      "metadata": {
+        "source": "home.go:12"
+      }
+    }
+  }
+}
+`
+
+	c, err := Load(strings.NewReader(file))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if c.Locale() != language.Latvian {
+		t.Errorf("want '%s', got '%s'", language.Latvian, c.Locale())
+	}
+
+	msg, _ := c.Message("greeting")
+	if msg.Description != "Greeting on the home page" || msg.Metadata["source"] != "home.go:12" || len(msg.Notes) != 2 {
+		t.Errorf("want description, notes and metadata, got %+v", msg)
+	}
+
+	if got, _ := c.Sprint("apples", map[string]any{"count": 1}); got != "1 ābols" {
+		t.Errorf("want '1 ābols', got '%s'", got)
+	}
+
+	var sb strings.Builder
+
+	if err := c.Save(&sb); err != nil {
+		t.Fatal(err)
+	}
+
+	if sb.String() != file {
+		t.Errorf("want '%s', got '%s'", file, sb.String())
+	}
+}
+
+func TestLoadSaveLocales(t *testing.T) {
+	t.Parallel()
+
+	const file = `{
+  "locale": "lv",
+  "sourceLocale": "de",
+  "messages": {
+    "total": {
+      "message": "{ 1234.5 :number }",
+      "status": "new",
+      "locale": "de"
+    }
+  }
+}
+`
+
+	c, err := Load(strings.NewReader(file))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := c.SourceLocale(); got != language.German {
+		t.Errorf("want '%s', got '%s'", language.German, got)
+	}
+
+	// untranslated message is formatted in its locale
+	if got, _ := c.Sprint("total", nil); got != "1.234,5" {
+		t.Errorf("want '1.234,5', got '%s'", got)
+	}
+
+	var sb strings.Builder
+
+	if err := c.Save(&sb); err != nil {
+		t.Fatal(err)
+	}
+
+	if sb.String() != file {
+		t.Errorf("want '%s', got '%s'", file, sb.String())
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		name, file string
+		wantErr    error
+	}{
+		{name: "not json", file: `locale: en`, wantErr: ErrInvalidFile},
+		{name: "trailing data", file: `{"locale": "en", "messages": {}} {}`, wantErr: ErrInvalidFile},
+		{name: "unknown field", file: `{"locale": "en", "messages": {}, "version": 1}`, wantErr: ErrInvalidFile},
+		{name: "missing locale", file: `{"messages": {}}`, wantErr: ErrInvalidFile},
+		{name: "invalid locale", file: `{"locale": "invalid-locale-", "messages": {}}`, wantErr: ErrInvalidFile},
+		{name: "missing messages", file: `{"locale": "en"}`, wantErr: ErrInvalidFile},
+		{name: "missing message", file: `{"locale": "en", "messages": {"a": {"description": "a"}}}`, wantErr: ErrInvalidFile},
+		{name: "empty ID", file: `{"locale": "en", "messages": {"": {"message": "a"}}}`, wantErr: ErrInvalidFile},
+		{name: "syntax error", file: `{"locale": "en", "messages": {"a": {"message": "{ $a"}}}`, wantErr: mf2.ErrSyntax},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			if _, err := Load(strings.NewReader(test.file)); !errors.Is(err, test.wantErr) {
+				t.Errorf("want '%s', got '%s'", test.wantErr, err)
+			}
+		})
+	}
+}
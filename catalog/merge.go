@@ -0,0 +1,113 @@
+package catalog
+
+import (
+	"maps"
+	"slices"
+
+	"golang.org/x/text/language"
+
+	"go.expect.digital/mf2/internal/mapkeys"
+)
+
+// Status is the state of the translated message relative to the source catalog.
+type Status string
+
+const (
+	// StatusNew is the message added from the source catalog, the text is the source message.
+	StatusNew Status = "new"
+	// StatusChanged is the message whose source message changed since the translation.
+	StatusChanged Status = "changed"
+	// StatusObsolete is the message missing in the source catalog.
+	StatusObsolete Status = "obsolete"
+)
+
+// MergeResult is the message IDs changed by [Catalog.Merge].
+type MergeResult struct {
+	New, Changed, Obsolete []string
+}
+
+// Merge merges the messages extracted from the source code, the source catalog, into the translated catalog:
+//
+//   - messages missing in the catalog are added with the source text and [StatusNew];
+//   - messages whose source message differs from [Message.Source] keep the translation and get [StatusChanged];
+//   - messages missing in the source catalog are kept and get [StatusObsolete].
+//
+// The description, the notes and the metadata are updated from the source catalog, [Message.Source] is set to
+// the source message. The new messages are formatted in the locale of the source catalog, see [Message.Locale],
+// and the source locale of the catalog is set, see [Catalog.SetSourceLocale]. The status is cleared by the translator when the message is translated,
+// e.g. with [Catalog.SetMessage]. Message IDs in the result are sorted.
+func (c *Catalog) Merge(source *Catalog) MergeResult {
+	var result MergeResult
+
+	if c == source {
+		return result
+	}
+
+	source.mu.RLock()
+	defer source.mu.RUnlock()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.sourceLocale == language.Und && source.locale != c.locale {
+		c.sourceLocale = source.locale
+	}
+
+	for _, id := range mapkeys.Sorted(source.messages) {
+		src := source.messages[id]
+
+		msg, ok := c.messages[id]
+		if !ok {
+			c.messages[id] = Message{
+				ID:          id,
+				Text:        src.Text,
+				Description: src.Description,
+				Notes:       slices.Clone(src.Notes),
+				Metadata:    maps.Clone(src.Metadata),
+				Source:      src.Text,
+				Status:      StatusNew,
+				Locale:      src.Locale,
+			}
+
+			// the untranslated message is formatted in the locale it is written in
+			if msg := c.messages[id]; msg.Locale == language.Und && source.locale != c.locale {
+				msg.Locale = source.locale
+				c.messages[id] = msg
+			}
+
+			result.New = append(result.New, id)
+
+			continue
+		}
+
+		switch {
+		case msg.Source != "" && msg.Source != src.Text:
+			msg.Status = StatusChanged
+			result.Changed = append(result.Changed, id)
+		case msg.Status == StatusObsolete: // back in the source catalog
+			msg.Status = ""
+		}
+
+		msg.Description = src.Description
+		msg.Notes = slices.Clone(src.Notes)
+		msg.Metadata = maps.Clone(src.Metadata)
+		msg.Source = src.Text
+		c.messages[id] = msg
+	}
+
+	for _, id := range mapkeys.Sorted(c.messages) {
+		if _, ok := source.messages[id]; ok {
+			continue
+		}
+
+		msg := c.messages[id]
+		if msg.Status != StatusObsolete {
+			msg.Status = StatusObsolete
+			c.messages[id] = msg
+		}
+
+		result.Obsolete = append(result.Obsolete, id)
+	}
+
+	return result
+}
@@ -0,0 +1,83 @@
+package catalog
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"golang.org/x/text/language"
+)
+
+func TestMerge(t *testing.T) {
+	t.Parallel()
+
+	source := New(language.English)
+	source.SetMessage(Message{ID: "greeting", Text: "Hello, { $name }!", Description: "Home page", Notes: []string{"Informal"}})
+	source.Set("farewell", "Goodbye, { $name }!")
+	source.Set("apples", "{ $count } apples")
+
+	translated := New(language.Latvian)
+	translated.SetMessage(Message{ID: "greeting", Text: "Sveiki, { $name }!", Source: "Hello, { $name }!"})
+	translated.SetMessage(Message{ID: "farewell", Text: "Ardievu!", Source: "Goodbye!"})
+	translated.SetMessage(Message{ID: "removed", Text: "Dzēsts"})
+
+	want := MergeResult{New: []string{"apples"}, Changed: []string{"farewell"}, Obsolete: []string{"removed"}}
+
+	if got := translated.Merge(source); !reflect.DeepEqual(want, got) {
+		t.Errorf("want %+v, got %+v", want, got)
+	}
+
+	for _, want := range []Message{
+		{ID: "apples", Text: "{ $count } apples", Source: "{ $count } apples", Status: StatusNew, Locale: language.English},
+		{ID: "farewell", Text: "Ardievu!", Source: "Goodbye, { $name }!", Status: StatusChanged},
+		{
+			ID:          "greeting",
+			Text:        "Sveiki, { $name }!",
+			Source:      "Hello, { $name }!",
+			Description: "Home page",
+			Notes:       []string{"Informal"},
+		},
+		{ID: "removed", Text: "Dzēsts", Status: StatusObsolete},
+	} {
+		if got, _ := translated.Message(want.ID); !reflect.DeepEqual(want, got) {
+			t.Errorf("want %+v, got %+v", want, got)
+		}
+	}
+
+	if got := translated.SourceLocale(); got != language.English {
+		t.Errorf("want '%s', got '%s'", language.English, got)
+	}
+
+	// merge is idempotent, except the changed messages stay changed until translated
+	want = MergeResult{Obsolete: []string{"removed"}}
+
+	if got := translated.Merge(source); !reflect.DeepEqual(want, got) {
+		t.Errorf("want %+v, got %+v", want, got)
+	}
+
+	if msg, _ := translated.Message("farewell"); msg.Status != StatusChanged {
+		t.Errorf("want '%s', got '%s'", StatusChanged, msg.Status)
+	}
+
+	// status and source message are saved
+	var sb strings.Builder
+
+	if err := translated.Save(&sb); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := Load(strings.NewReader(sb.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, got := mustMessage(translated, "farewell"), mustMessage(loaded, "farewell"); !reflect.DeepEqual(want, got) {
+		t.Errorf("want %+v, got %+v", want, got)
+	}
+}
+
+func mustMessage(c *Catalog, id string) Message {
+	msg, _ := c.Message(id)
+
+	return msg
+}
@@ -0,0 +1,32 @@
+package catalog
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+
+	"go.expect.digital/mf2/internal/jsonschema"
+)
+
+// Schema is the JSON Schema document of the JSON catalog format, see [Load].
+// Producers outside Go can validate their catalogs with it before handing them to Go services.
+//
+//go:embed schema.json
+var Schema string
+
+var schema = jsonschema.MustParse([]byte(Schema))
+
+// Validate validates the catalog in the JSON catalog format against [Schema] and then
+// the locales and the MF2 syntax of the messages, as [Load] does.
+// The violations are reported as [ErrInvalidFile].
+func Validate(data []byte) error {
+	if err := schema.Validate(data); err != nil {
+		return fmt.Errorf("validate catalog: %w: %w", ErrInvalidFile, err)
+	}
+
+	if _, err := Load(bytes.NewReader(data)); err != nil {
+		return fmt.Errorf("validate catalog: %w", err)
+	}
+
+	return nil
+}
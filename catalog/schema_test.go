@@ -0,0 +1,70 @@
+package catalog
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	const file = `{
+  "locale": "lv",
+  "sourceLocale": "en",
+  "messages": {
+    "greeting": {
+      "message": "Sveiki, { $name }!",
+      "description": "Greeting on the home page",
+      "metadata": {"source": "home.go:12"},
+      "status": "changed",
+      "sourceMessage": "Hello, { $name }!"
+    },
+    "farewell": {
+      "message": "Goodbye, { $name }!",
+      "status": "new",
+      "locale": "en"
+    }
+  }
+}`
+
+	if err := Validate([]byte(file)); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestValidateErrors(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		name, file, want string
+	}{
+		{name: "missing locale", file: `{"messages":{}}`, want: `/: missing property "locale"`},
+		{name: "unknown property", file: `{"locale":"lv","messages":{},"x":1}`, want: `/: unknown property "x"`},
+		{
+			name: "status",
+			file: `{"locale":"lv","messages":{"a":{"message":"a","status":"done"}}}`,
+			want: `/messages/a/status: want one of`,
+		},
+		{
+			name: "metadata",
+			file: `{"locale":"lv","messages":{"a/b":{"message":"a","metadata":{"line":12}}}}`,
+			want: `/messages/a~1b/metadata/line: want type string, got integer`,
+		},
+		{name: "syntax", file: `{"locale":"lv","messages":{"a":{"message":"{ $a"}}}`, want: `message "a"`},
+		{name: "locale", file: `{"locale":"lv-x","messages":{}}`, want: `locale "lv-x"`},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := Validate([]byte(test.file))
+			if !errors.Is(err, ErrInvalidFile) {
+				t.Errorf("want '%s', got '%v'", ErrInvalidFile, err)
+			}
+
+			if err == nil || !strings.Contains(err.Error(), test.want) {
+				t.Errorf("want error containing '%s', got '%v'", test.want, err)
+			}
+		})
+	}
+}
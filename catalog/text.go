@@ -0,0 +1,369 @@
+package catalog
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"golang.org/x/text/language"
+
+	"go.expect.digital/mf2/internal/mapkeys"
+	"go.expect.digital/mf2/parse"
+	"go.expect.digital/mf2/template"
+)
+
+const (
+	// textMetadataPrefix is the prefix of the metadata fields of the text catalog format.
+	textMetadataPrefix = "metadata."
+	// textNoteField is the repeatable field of the notes of the message.
+	textNoteField = "note"
+)
+
+// SaveText writes the catalog in the canonical text catalog format, see [LoadText].
+func (c *Catalog) SaveText(w io.Writer) error {
+	errorf := func(format string, args ...any) error {
+		return fmt.Errorf("save text catalog: "+format, args...)
+	}
+
+	bw := bufio.NewWriter(w)
+
+	writeField(bw, "locale", c.locale.String(), false)
+
+	if s := localeString(c.SourceLocale()); s != "" {
+		writeField(bw, "sourceLocale", s, false)
+	}
+
+	for _, id := range c.IDs() {
+		msg, ok := c.Message(id)
+		if !ok {
+			continue // deleted meanwhile
+		}
+
+		if strings.ContainsAny(id, "\r\n") {
+			return errorf(`message "%s": newline in message ID`, id)
+		}
+
+		bw.WriteString("\n[" + id + "]\n")
+
+		writeField(bw, "description", msg.Description, false)
+
+		for _, note := range msg.Notes {
+			writeField(bw, textNoteField, note, false)
+		}
+
+		writeField(bw, "status", string(msg.Status), false)
+		writeField(bw, "locale", localeString(msg.Locale), false)
+
+		for _, k := range mapkeys.Sorted(msg.Metadata) {
+			if strings.ContainsAny(k, ":\r\n") {
+				return errorf(`message "%s": invalid metadata key "%s"`, id, k)
+			}
+
+			writeField(bw, textMetadataPrefix+k, msg.Metadata[k], false)
+		}
+
+		if msg.Source != "" {
+			writeField(bw, "sourceMessage", canonicalText(msg.Source), true)
+		}
+
+		writeField(bw, "message", canonicalText(msg.Text), true)
+	}
+
+	if err := bw.Flush(); err != nil {
+		return errorf("%w", err)
+	}
+
+	return nil
+}
+
+// writeField writes the non-empty field of the text catalog format, the multi-line value
+// if the value must be multi-line or contains a newline.
+func writeField(w *bufio.Writer, name, value string, multiline bool) {
+	if value == "" && !multiline {
+		return
+	}
+
+	if !multiline && !strings.ContainsAny(value, "\r\n") {
+		w.WriteString(name + ": " + value + "\n")
+		return
+	}
+
+	w.WriteString(name + ":\n")
+
+	if value == "" {
+		return
+	}
+
+	for _, line := range strings.Split(value, "\n") {
+		w.WriteString("\t" + line + "\n")
+	}
+}
+
+// canonicalText returns the canonical MF2 text of the message, or the text if it is not valid.
+func canonicalText(text string) string {
+	tree, err := parse.Parse(text)
+	if err != nil {
+		return text
+	}
+
+	return parse.Canonical(tree).String()
+}
+
+/*
+LoadText reads the catalog in the text catalog format, a line-oriented format designed for minimal and reviewable VCS diffs:
+
+	# comment
+	locale: lv
+	sourceLocale: en
+
+	[farewell]
+	status: new
+	locale: en
+	message:
+		Goodbye, { $name }!
+
+	[greeting]
+	description: Greeting on the home page
+	note: Informal greeting
+	note: Shown once a day
+	status: changed
+	metadata.source: home.go:12
+	sourceMessage:
+		Hello, { $name }!
+	message:
+		.input { $name :string }
+		{{Sveiki, { $name }!}}
+
+The header has the "locale" and the optional "sourceLocale" of the catalog. Each message is a block
+starting with the message ID in brackets, the blocks are separated by empty lines. The fields
+have the same meaning as in the JSON catalog format, see [Load], each note is a separate "note" field
+and each metadata entry is a separate "metadata.<key>" field.
+
+The "message" and "sourceMessage" are always multi-line values: each line of the value is on
+a separate line indented with a tab. The other fields are single-line values after ": ",
+or multi-line values if the value contains a newline. The lines starting with "#" outside
+of the values are comments.
+
+[Catalog.SaveText] writes the canonical file: the messages are sorted by ID, the fields are in the order
+of the example, the metadata is sorted by key, and the messages are formatted canonically,
+see [parse.Canonical], so the same catalog is always the same file and changing a single variant
+of a message changes a single line.
+
+The file is validated as in [Load]: unknown fields, a missing or invalid locale, and missing
+or syntactically invalid messages are reported as [ErrInvalidFile] with the line number,
+all invalid messages at once. The options are applied to every compiled template.
+*/
+func LoadText(r io.Reader, options ...template.Option) (*Catalog, error) {
+	errorf := func(format string, args ...any) (*Catalog, error) {
+		return nil, fmt.Errorf("load text catalog: %w: "+format, append([]any{ErrInvalidFile}, args...)...)
+	}
+
+	blocks, err := readTextBlocks(r)
+	if err != nil {
+		return errorf("%w", err)
+	}
+
+	header := blocks[0]
+
+	if header.fields["locale"] == nil {
+		return errorf(`missing "locale"`)
+	}
+
+	locale, err := language.Parse(*header.fields["locale"])
+	if err != nil {
+		return errorf(`line %d: locale "%s": %w`, header.lines["locale"], *header.fields["locale"], err)
+	}
+
+	c := New(locale, options...)
+
+	if s := header.fields["sourceLocale"]; s != nil {
+		if c.sourceLocale, err = parseLocale(*s); err != nil {
+			return errorf(`line %d: source locale "%s": %w`, header.lines["sourceLocale"], *s, err)
+		}
+	}
+
+	var errs []error
+
+	for _, b := range blocks[1:] {
+		msg, err := b.message()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("line %d: %w", b.line, err))
+			continue
+		}
+
+		if _, ok := c.messages[msg.ID]; ok {
+			errs = append(errs, fmt.Errorf(`line %d: duplicate message "%s"`, b.line, b.id))
+			continue
+		}
+
+		c.messages[msg.ID] = msg
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return errorf("%w", err)
+	}
+
+	return c, nil
+}
+
+// textBlock is the header or a message block of the text catalog format.
+type textBlock struct {
+	fields map[string]*string
+	notes  []string
+	lines  map[string]int // line numbers of the fields
+	id     string
+	line   int
+}
+
+// message returns the message of the block.
+func (b *textBlock) message() (Message, error) {
+	msg := Message{ID: b.id, Notes: b.notes}
+
+	// the fields are read in the line order, the first invalid field is reported
+	names := mapkeys.Sorted(b.fields)
+	sort.SliceStable(names, func(i, j int) bool { return b.lines[names[i]] < b.lines[names[j]] })
+
+	for _, name := range names {
+		switch value := b.fields[name]; name {
+		default:
+			key, ok := strings.CutPrefix(name, textMetadataPrefix)
+			if !ok || key == "" {
+				return Message{}, fmt.Errorf(`message "%s": line %d: unknown field "%s"`, b.id, b.lines[name], name)
+			}
+
+			if msg.Metadata == nil {
+				msg.Metadata = make(map[string]string)
+			}
+
+			msg.Metadata[key] = *value
+		case "message":
+			msg.Text = *value
+		case "description":
+			msg.Description = *value
+		case "status":
+			msg.Status = Status(*value)
+		case "sourceMessage":
+			msg.Source = *value
+		case "locale":
+			var err error
+
+			if msg.Locale, err = parseLocale(*value); err != nil {
+				return Message{}, fmt.Errorf(`message "%s": line %d: locale "%s": %w`, b.id, b.lines[name], *value, err)
+			}
+		}
+	}
+
+	text := b.fields["message"]
+
+	if err := validateMessage(b.id, jsonMessage{Message: text}); err != nil {
+		return Message{}, err
+	}
+
+	return msg, nil
+}
+
+// set sets the value of the field, the notes are appended.
+func (b *textBlock) set(name, value string) {
+	if name == textNoteField {
+		b.notes = append(b.notes, value)
+		return
+	}
+
+	b.fields[name] = &value
+}
+
+// readTextBlocks returns the header and the message blocks of the text catalog format.
+func readTextBlocks(r io.Reader) ([]*textBlock, error) {
+	blocks := []*textBlock{{fields: make(map[string]*string), lines: make(map[string]int)}}
+
+	// the multi-line value being read
+	var (
+		value      *strings.Builder
+		valueName  string
+		valueLines int
+	)
+
+	flush := func() {
+		if value != nil {
+			blocks[len(blocks)-1].set(valueName, value.String())
+			value = nil
+		}
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1<<24) //nolint:mnd
+
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+		b := blocks[len(blocks)-1]
+
+		if value != nil {
+			if s, ok := strings.CutPrefix(line, "\t"); ok {
+				if valueLines > 0 {
+					value.WriteByte('\n')
+				}
+
+				value.WriteString(s)
+				valueLines++
+
+				continue
+			}
+
+			flush()
+		}
+
+		switch {
+		case line == "", strings.HasPrefix(line, "#"):
+			continue
+		case strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") && len(line) > 2:
+			blocks = append(blocks, &textBlock{
+				id:     line[1 : len(line)-1],
+				line:   n,
+				fields: make(map[string]*string),
+				lines:  make(map[string]int),
+			})
+
+			continue
+		case strings.HasPrefix(line, "\t"):
+			return nil, fmt.Errorf("line %d: unexpected indented line", n)
+		}
+
+		name, s, ok := strings.Cut(line, ":")
+		if !ok {
+			return nil, fmt.Errorf(`line %d: want "name: value", got "%s"`, n, line)
+		}
+
+		if _, ok := b.fields[name]; ok && name != textNoteField {
+			return nil, fmt.Errorf(`line %d: duplicate field "%s"`, n, name)
+		}
+
+		if b.id == "" && name != "locale" && name != "sourceLocale" {
+			return nil, fmt.Errorf(`line %d: unknown field "%s"`, n, name)
+		}
+
+		b.lines[name] = n
+
+		if s == "" {
+			value, valueName, valueLines = new(strings.Builder), name, 0
+			continue
+		}
+
+		s, ok = strings.CutPrefix(s, " ")
+		if !ok {
+			return nil, fmt.Errorf(`line %d: want "name: value", got "%s"`, n, line)
+		}
+
+		b.set(name, s)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	flush()
+
+	return blocks, nil
+}
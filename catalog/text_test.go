@@ -0,0 +1,162 @@
+package catalog
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"golang.org/x/text/language"
+)
+
+func TestLoadSaveText(t *testing.T) {
+	t.Parallel()
+
+	const file = `# Latvian translations
+locale: lv
+sourceLocale: en
+
+[apples]
+message:
+	.input { $count :number }
+	.match { $count }
+	one {{{ $count } ābols}}
+	* {{{ $count } āboli}}
+
+[farewell]
+status: new
+locale: en
+message:
+	Goodbye, { $name }!
+
+[greeting]
+description: Greeting on the home page
+note: Informal greeting
+note:
+	Shown once
+	a day
+status: changed
+metadata.line: 12
+metadata.source: home.go
+sourceMessage:
+	Hello, { $name }!
+message:
+	Sveiki, { $name }!
+
+[multiline]
+description:
+	first
+	second
+message:
+	first line
+	
+	third line
+`
+
+	c, err := LoadText(strings.NewReader(file))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if c.Locale() != language.Latvian || c.SourceLocale() != language.English {
+		t.Errorf("want 'lv' and 'en', got '%s' and '%s'", c.Locale(), c.SourceLocale())
+	}
+
+	greeting, _ := c.Message("greeting")
+
+	want := Message{
+		ID:          "greeting",
+		Text:        "Sveiki, { $name }!",
+		Description: "Greeting on the home page",
+		Notes:       []string{"Informal greeting", "Shown once\na day"},
+		Metadata:    map[string]string{"line": "12", "source": "home.go"},
+		Status:      StatusChanged,
+		Source:      "Hello, { $name }!",
+	}
+
+	if !reflect.DeepEqual(want, greeting) {
+		t.Errorf("want %+v, got %+v", want, greeting)
+	}
+
+	multiline, _ := c.Message("multiline")
+	if multiline.Text != "first line\n\nthird line" || multiline.Description != "first\nsecond" {
+		t.Errorf("want multi-line values, got %+v", multiline)
+	}
+
+	if farewell, _ := c.Message("farewell"); farewell.Locale != language.English || farewell.Status != StatusNew {
+		t.Errorf("want 'en' and 'new', got '%s' and '%s'", farewell.Locale, farewell.Status)
+	}
+
+	var buf bytes.Buffer
+
+	if err := c.SaveText(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	// the canonical file without comments
+	if want := strings.TrimPrefix(file, "# Latvian translations\n"); want != buf.String() {
+		t.Errorf("want '%s', got '%s'", want, buf.String())
+	}
+}
+
+func TestSaveTextCanonical(t *testing.T) {
+	t.Parallel()
+
+	c := New(language.English)
+	c.Set("b", "{$n :number style=percent minimumFractionDigits=2}")
+	c.Set("a", "Hello")
+
+	var buf bytes.Buffer
+
+	if err := c.SaveText(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `locale: en
+
+[a]
+message:
+	Hello
+
+[b]
+message:
+	{ $n :number minimumFractionDigits = 2 style = percent }
+`
+
+	if want != buf.String() {
+		t.Errorf("want '%s', got '%s'", want, buf.String())
+	}
+}
+
+func TestLoadTextErrors(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		name, file, want string
+	}{
+		{name: "missing locale", file: "[a]\nmessage:\n\ta\n", want: `missing "locale"`},
+		{name: "header field", file: "locale: lv\nstatus: new\n", want: `line 2: unknown field "status"`},
+		{name: "unknown field", file: "locale: lv\n\n[a]\ncolor: red\nmessage:\n\ta\n", want: `message "a": line 4: unknown field "color"`},
+		{name: "unknown fields", file: "locale: lv\n\n[a]\nsize: 1\ncolor: red\nmessage:\n\ta\n", want: `line 4: unknown field "size"`},
+		{name: "duplicate field", file: "locale: lv\n\n[a]\nmessage:\n\ta\nmessage:\n\tb\n", want: `line 6: duplicate field "message"`},
+		{name: "duplicate message", file: "locale: lv\n\n[a]\nmessage:\n\ta\n\n[a]\nmessage:\n\tb\n", want: `line 7: duplicate message "a"`},
+		{name: "missing message", file: "locale: lv\n\n[a]\ndescription: x\n", want: `message "a": missing "message"`},
+		{name: "syntax", file: "locale: lv\n\n[a]\nmessage:\n\t{ $a\n", want: `line 3: message "a"`},
+		{name: "indented", file: "locale: lv\n\tx\n", want: `line 2: unexpected indented line`},
+		{name: "separator", file: "locale: lv\n\n[a]\nmessage:x\n", want: `line 4: want "name: value"`},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := LoadText(strings.NewReader(test.file))
+			if !errors.Is(err, ErrInvalidFile) {
+				t.Errorf("want '%s', got '%v'", ErrInvalidFile, err)
+			}
+
+			if err == nil || !strings.Contains(err.Error(), test.want) {
+				t.Errorf("want error containing '%s', got '%v'", test.want, err)
+			}
+		})
+	}
+}
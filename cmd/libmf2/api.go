@@ -0,0 +1,157 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/text/language"
+
+	"go.expect.digital/mf2"
+	"go.expect.digital/mf2/datamodel"
+	"go.expect.digital/mf2/internal/multierr"
+	"go.expect.digital/mf2/parse"
+	"go.expect.digital/mf2/template"
+)
+
+// request is the JSON request of the library functions.
+type request struct {
+	Input   map[string]any `json:"input"`
+	Message *string        `json:"message"`
+	Locale  string         `json:"locale"`
+	Parts   bool           `json:"parts"`
+}
+
+// responseError is the error of the JSON response.
+type responseError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// errorResponse returns the JSON response of the failed request.
+func errorResponse(err error) string {
+	return encode(struct {
+		Error responseError `json:"error"`
+	}{
+		Error: responseError{Code: mf2.ErrorCode(err), Message: err.Error()},
+	})
+}
+
+// encode returns the JSON of the response, the HTML characters are not escaped.
+func encode(v any) string {
+	var sb strings.Builder
+
+	enc := json.NewEncoder(&sb)
+	enc.SetEscapeHTML(false)
+
+	if err := enc.Encode(v); err != nil {
+		return errorResponse(err)
+	}
+
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// decodeRequest returns the request with the required message.
+func decodeRequest(s string) (request, error) {
+	var req request
+
+	if err := json.Unmarshal([]byte(s), &req); err != nil {
+		return request{}, fmt.Errorf("decode request: %w", err)
+	}
+
+	if req.Message == nil {
+		return request{}, errors.New(`decode request: missing "message"`)
+	}
+
+	return req, nil
+}
+
+// parseMessage returns the canonical message and its JSON data model, see [mf2_parse].
+func parseMessage(s string) string {
+	req, err := decodeRequest(s)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	tree, err := parse.Parse(*req.Message)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	dataModel, err := datamodel.MarshalJSON(tree)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	return encode(struct {
+		Message   string          `json:"message"`
+		DataModel json.RawMessage `json:"dataModel"`
+	}{
+		Message:   parse.Canonical(tree).String(),
+		DataModel: dataModel,
+	})
+}
+
+// validateMessage returns the diagnostics of the message, see [mf2_validate].
+func validateMessage(s string) string {
+	req, err := decodeRequest(s)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	_, diagnostics := parse.Diagnose(*req.Message)
+
+	if diagnostics == nil {
+		diagnostics = []mf2.Diagnostic{}
+	}
+
+	response := struct {
+		Diagnostics []mf2.Diagnostic `json:"diagnostics"`
+	}{
+		Diagnostics: diagnostics,
+	}
+
+	return encode(response)
+}
+
+// formatMessage returns the formatted message, or the formatted parts, and the resolution errors,
+// see [mf2_format]. The default locale is American English.
+func formatMessage(s string) string {
+	req, err := decodeRequest(s)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	locale := language.AmericanEnglish
+
+	if req.Locale != "" {
+		if locale, err = language.Parse(req.Locale); err != nil {
+			return errorResponse(fmt.Errorf("locale: %w", err))
+		}
+	}
+
+	t, err := template.New(template.WithLocale(locale)).Parse(*req.Message)
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	response := struct {
+		Result any             `json:"result"`
+		Errors []responseError `json:"errors"`
+	}{
+		Errors: []responseError{},
+	}
+
+	if req.Parts {
+		response.Result, err = t.FormatToParts(req.Input)
+	} else {
+		response.Result, err = t.Sprint(req.Input)
+	}
+
+	for _, err := range multierr.Errors(err) {
+		response.Errors = append(response.Errors, responseError{Code: mf2.ErrorCode(err), Message: err.Error()})
+	}
+
+	return encode(response)
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestAPI(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		name, request, want string
+		f                   func(string) string
+	}{
+		{
+			name:    "format",
+			f:       formatMessage,
+			request: `{"message": "Sveiki, {$name}! <3", "locale": "lv", "input": {"name": "Jānis"}}`,
+			want:    `{"result":"Sveiki, Jānis! <3","errors":[]}`,
+		},
+		{
+			name:    "format number",
+			f:       formatMessage,
+			request: `{"message": ".input {$n :number} .match {$n} one {{one}} * {{{$n} other}}", "input": {"n": 1000}}`,
+			want:    `{"result":"1,000 other","errors":[]}`,
+		},
+		{
+			name:    "format parts",
+			f:       formatMessage,
+			request: `{"message": "{#b}x{/b}", "parts": true}`,
+			want: `{"result":[{"type":"markup","kind":"open","name":"b"},{"type":"text","value":"x"},` +
+				`{"type":"markup","kind":"close","name":"b"}],"errors":[]}`,
+		},
+		{
+			name:    "format errors",
+			f:       formatMessage,
+			request: `{"message": "Hello, {$name}!"}`,
+			want: `{"result":"Hello, {$name}!","errors":[{"code":"unresolved-variable",` +
+				`"message":"execute template: expression: unresolved variable \"$name\""}]}`,
+		},
+		{
+			name:    "format syntax error",
+			f:       formatMessage,
+			request: `{"message": "{"}`,
+			want:    `{"error":{"code":"syntax-error","message":"parse MF2: syntax error: unexpected eof in expression"}}`,
+		},
+		{
+			name:    "format locale",
+			f:       formatMessage,
+			request: `{"message": "", "locale": "-"}`,
+			want:    `{"error":{"code":"error","message":"locale: language: tag is not well-formed"}}`,
+		},
+		{
+			name:    "parse",
+			f:       parseMessage,
+			request: `{"message": "Hello, {$name}!"}`,
+			want: `{"message":"Hello, { $name }!","dataModel":{"type":"message","declarations":[],"pattern":` +
+				`["Hello, ",{"type":"expression","arg":{"type":"variable","name":"name"},"attributes":[]},"!"]}}`,
+		},
+		{
+			name:    "validate",
+			f:       validateMessage,
+			request: `{"message": "Hello, {$name"}`,
+			want: `{"diagnostics":[{"severity":"error","code":"syntax-error",` +
+				`"message":"parse MF2: syntax error: unexpected eof in expression","start":0,"end":13}]}`,
+		},
+		{
+			name:    "valid",
+			f:       validateMessage,
+			request: `{"message": "Hello!"}`,
+			want:    `{"diagnostics":[]}`,
+		},
+		{
+			name:    "missing message",
+			f:       validateMessage,
+			request: `{}`,
+			want:    `{"error":{"code":"error","message":"decode request: missing \"message\""}}`,
+		},
+		{
+			name:    "invalid request",
+			f:       parseMessage,
+			request: `[`,
+			want:    `{"error":{"code":"error","message":"decode request: unexpected end of JSON input"}}`,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := test.f(test.request); test.want != got {
+				t.Errorf("want '%s', got '%s'", test.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+/*
+Libmf2 is the C shared library of the MessageFormat 2 parser, validator and formatter for non-Go consumers,
+e.g. Python or Ruby services calling it through ctypes or FFI instead of running the mf2 command.
+
+Build the library and the C header with:
+
+	go build -buildmode=c-shared -o libmf2.so ./cmd/libmf2
+
+Each function takes the JSON request and returns the JSON response as a NUL-terminated UTF-8 string,
+the response must be released with mf2_free. The failed request has the response {"error": {...}}
+with the error code and message, e.g. "syntax-error", see [mf2.ErrorCode].
+
+	char* mf2_parse(char* request);    // {"message": "..."}
+	char* mf2_validate(char* request); // {"message": "..."}
+	char* mf2_format(char* request);   // {"message": "...", "locale": "lv", "input": {...}, "parts": false}
+	void mf2_free(char* response);
+
+The parse response is the canonical message and its JSON data model, see [datamodel.MarshalJSON]:
+
+	{"message": "Hello, { $name }!", "dataModel": {...}}
+
+The validate response is the diagnostics, empty for the valid message:
+
+	{"diagnostics": [{"severity": "error", "code": "syntax-error", "message": "...", "start": 0, "end": 7}]}
+
+The format response is the formatted message, or the formatted parts, and the resolution errors.
+The message is formatted also if it resolves with errors, as in [template.Template.Sprint]:
+
+	{"result": "Sveiki, Jānis!", "errors": []}
+
+Python example:
+
+	lib = ctypes.CDLL("./libmf2.so")
+	lib.mf2_format.restype = ctypes.c_void_p
+	ptr = lib.mf2_format(json.dumps({"message": "Hello, {$name}!", "input": {"name": "Ada"}}).encode())
+	response = json.loads(ctypes.string_at(ptr))
+	lib.mf2_free(ctypes.c_void_p(ptr))
+*/
+package main
+
+/*
+#include <stdlib.h>
+*/
+import "C"
+
+import "unsafe"
+
+//export mf2_parse
+func mf2_parse(request *C.char) *C.char { //nolint:revive,stylecheck
+	return C.CString(parseMessage(C.GoString(request)))
+}
+
+//export mf2_validate
+func mf2_validate(request *C.char) *C.char { //nolint:revive,stylecheck
+	return C.CString(validateMessage(C.GoString(request)))
+}
+
+//export mf2_format
+func mf2_format(request *C.char) *C.char { //nolint:revive,stylecheck
+	return C.CString(formatMessage(C.GoString(request)))
+}
+
+//export mf2_free
+func mf2_free(response *C.char) { //nolint:revive,stylecheck
+	C.free(unsafe.Pointer(response))
+}
+
+func main() {}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	"go.expect.digital/mf2/lsp"
+)
+
+// serveLSP runs the language server, the editor communicates with it over stdin and stdout.
+func serveLSP(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	flags := flag.NewFlagSet("lsp", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprint(stderr, "Usage: mf2 lsp\n\nRuns the MF2 language server on the standard input and output.\n")
+	}
+
+	if err := flags.Parse(args); err != nil || flags.NArg() > 0 {
+		return errUsage
+	}
+
+	if err := lsp.Serve(stdin, stdout); err != nil {
+		return fmt.Errorf("lsp: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,93 @@
+/*
+Mf2 is a command line tool for MessageFormat 2 messages.
+
+Usage:
+
+	mf2 <command> [flags] [file]
+
+The commands are:
+
+	render    format the message with the given arguments
+	lsp       run the language server on the standard input and output
+
+The message is read from the file, or from the standard input if the file is "-" or omitted.
+
+Example:
+
+	mf2 render --locale lv -a count=5 -a name=Jānis message.mf2
+*/
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+const usage = `Usage:
+
+	mf2 <command> [flags] [file]
+
+The commands are:
+
+	render    format the message with the given arguments
+	lsp       run the language server on the standard input and output
+
+Use "mf2 <command> -h" for more information about a command.
+`
+
+// errUsage is returned when the command line is invalid, the usage has already been printed.
+var errUsage = errors.New("invalid usage")
+
+// command runs the subcommand with the arguments following the command name.
+type command func(args []string, stdin io.Reader, stdout, stderr io.Writer) error
+
+var commands = map[string]command{
+	"render": render,
+	"lsp":    serveLSP,
+}
+
+func main() {
+	if err := run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr); err != nil {
+		if !errors.Is(err, errUsage) {
+			fmt.Fprintln(os.Stderr, "mf2:", err)
+		}
+
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return errUsage
+	}
+
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "mf2: unknown command \"%s\"\n\n%s", args[0], usage)
+		return errUsage
+	}
+
+	return cmd(args[1:], stdin, stdout, stderr)
+}
+
+// readMessage reads the message from the file, or stdin if the name is "-" or empty.
+func readMessage(name string, stdin io.Reader) (string, error) {
+	if name == "" || name == "-" {
+		b, err := io.ReadAll(stdin)
+		if err != nil {
+			return "", fmt.Errorf("read stdin: %w", err)
+		}
+
+		return string(b), nil
+	}
+
+	b, err := os.ReadFile(name)
+	if err != nil {
+		return "", fmt.Errorf("read message: %w", err)
+	}
+
+	return string(b), nil
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/text/language"
+
+	"go.expect.digital/mf2/template"
+)
+
+// arguments is the repeated "-a name=value" flag.
+type arguments map[string]any
+
+// String implements [flag.Value].
+func (a arguments) String() string {
+	return ""
+}
+
+// Set implements [flag.Value].
+func (a arguments) Set(s string) error {
+	name, value, ok := strings.Cut(s, "=")
+	if !ok || name == "" {
+		return fmt.Errorf(`want "name=value", got "%s"`, s)
+	}
+
+	a[name] = value
+
+	return nil
+}
+
+// render formats the message and prints the result.
+//
+// The argument values are strings, functions parse them as needed, e.g. :number parses "5" as a number.
+// The result is printed also if the message resolves with errors, the errors are returned.
+func render(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	var (
+		locale string
+		parts  bool
+		input  = arguments{}
+	)
+
+	flags := flag.NewFlagSet("render", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprint(stderr, "Usage: mf2 render [-locale tag] [-a name=value]... [-parts] [file]\n\n")
+		flags.PrintDefaults()
+	}
+
+	flags.StringVar(&locale, "locale", language.AmericanEnglish.String(), "BCP 47 `tag` of the locale")
+	flags.Var(input, "a", "argument as `name=value`, can be repeated")
+	flags.BoolVar(&parts, "parts", false, "print the formatted parts as JSON")
+
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+
+		return errUsage
+	}
+
+	if flags.NArg() > 1 {
+		flags.Usage()
+		return errUsage
+	}
+
+	tag, err := language.Parse(locale)
+	if err != nil {
+		return fmt.Errorf("render: locale: %w", err)
+	}
+
+	text, err := readMessage(flags.Arg(0), stdin)
+	if err != nil {
+		return fmt.Errorf("render: %w", err)
+	}
+
+	// the trailing newline of the file is not part of the message
+	text = strings.TrimSuffix(strings.TrimSuffix(text, "\n"), "\r")
+
+	t, err := template.New(template.WithLocale(tag)).Parse(text)
+	if err != nil {
+		return fmt.Errorf("render: %w", err)
+	}
+
+	if !parts {
+		s, err := t.Sprint(input)
+		fmt.Fprintln(stdout, s)
+
+		if err != nil {
+			return fmt.Errorf("render: %w", err)
+		}
+
+		return nil
+	}
+
+	result, err := t.FormatToParts(input)
+
+	enc := json.NewEncoder(stdout)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+
+	if encErr := enc.Encode(result); encErr != nil {
+		return fmt.Errorf("render: %w", encErr)
+	}
+
+	if err != nil {
+		return fmt.Errorf("render: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	t.Parallel()
+
+	file := filepath.Join(t.TempDir(), "message.mf2")
+
+	err := os.WriteFile(file, []byte(".input { $count :number }\n.match { $count }\none {{{ $name } has { $count } item}}\n* {{{ $name } has { $count } items}}\n"), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		name, stdin, want string
+		args              []string
+		wantErr           bool
+	}{
+		{
+			name: "file",
+			args: []string{"render", "--locale", "lv", "-a", "count=5", "-a", "name=Jānis", file},
+			want: "Jānis has 5 items\n",
+		},
+		{
+			name:  "stdin",
+			args:  []string{"render", "-a", "count=1", "-a", "name=John", "-"},
+			stdin: "{ $name } has { $count :number } item",
+			want:  "John has 1 item\n",
+		},
+		{
+			name:  "parts",
+			args:  []string{"render", "-parts", "-a", "name=John"},
+			stdin: "Hi { $name }",
+			want: `[
+  {
+    "type": "text",
+    "value": "Hi "
+  },
+  {
+    "type": "expression",
+    "source": "$name",
+    "value": "John"
+  }
+]
+`,
+		},
+		{
+			name:    "unresolved variable",
+			args:    []string{"render"},
+			stdin:   "Hi { $name }",
+			want:    "Hi {$name}\n",
+			wantErr: true,
+		},
+		{
+			name:    "syntax error",
+			args:    []string{"render"},
+			stdin:   "Hi { $name",
+			wantErr: true,
+		},
+		{
+			name:    "bad argument",
+			args:    []string{"render", "-a", "name"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown command",
+			args:    []string{"unknown"},
+			wantErr: true,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			var stdout, stderr bytes.Buffer
+
+			err := run(test.args, strings.NewReader(test.stdin), &stdout, &stderr)
+			if test.wantErr != (err != nil) {
+				t.Errorf("want error %t, got '%v'", test.wantErr, err)
+			}
+
+			if test.want != stdout.String() {
+				t.Errorf("want '%s', got '%s'", test.want, stdout.String())
+			}
+
+			if !test.wantErr && stderr.Len() > 0 {
+				t.Errorf("want empty stderr, got '%s'", stderr.String())
+			}
+
+			if errors.Is(err, errUsage) && stderr.Len() == 0 {
+				t.Error("want usage, got empty stderr")
+			}
+		})
+	}
+}
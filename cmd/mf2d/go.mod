@@ -0,0 +1,14 @@
+module go.expect.digital/mf2/cmd/mf2d
+
+go 1.22
+
+// the server is built with the library of the same commit
+replace go.expect.digital/mf2 => ../..
+
+require (
+	go.expect.digital/mf2 v0.0.0-00010101000000-000000000000
+	golang.org/x/net v0.26.0 // h2c, plaintext HTTP/2 is not in net/http of Go 1.22
+	golang.org/x/text v0.16.0
+)
+
+require golang.org/x/exp v0.0.0-20240613232115-7f521ea00fb8 // indirect
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// gRPC over HTTP/2, see https://github.com/grpc/grpc/blob/master/doc/PROTOCOL-HTTP2.md.
+//
+// Only the unary RPCs with uncompressed messages are implemented, which is all the service needs.
+
+// code is the gRPC status code, see https://grpc.github.io/grpc/core/md_doc_statuscodes.html.
+type code int
+
+const (
+	codeOK                code = 0
+	codeInvalidArgument   code = 3
+	codeResourceExhausted code = 8
+	codeUnimplemented     code = 12
+	codeInternal          code = 13
+)
+
+// statusError is the failed RPC with the gRPC status code.
+type statusError struct {
+	err  error
+	code code
+}
+
+func (e *statusError) Error() string { return e.err.Error() }
+
+func (e *statusError) Unwrap() error { return e.err }
+
+// invalidArgument returns the error with the status code INVALID_ARGUMENT.
+func invalidArgument(err error) error {
+	return &statusError{code: codeInvalidArgument, err: err}
+}
+
+// rpc is the unary RPC, it decodes the request message and returns the encoded response message.
+type rpc func(request []byte) ([]byte, error)
+
+// unary returns the RPC of the fn, the request is decoded with unmarshal and the response encoded with marshal.
+func unary[Req any, PReq interface {
+	*Req
+	unmarshal(b []byte) error
+}, Resp interface{ marshal() []byte }](fn func(req Req) (Resp, error),
+) rpc {
+	return func(b []byte) ([]byte, error) {
+		var req Req
+
+		if err := PReq(&req).unmarshal(b); err != nil {
+			return nil, invalidArgument(fmt.Errorf("decode request: %w", err))
+		}
+
+		resp, err := fn(req)
+		if err != nil {
+			return nil, err
+		}
+
+		return resp.marshal(), nil
+	}
+}
+
+// handler serves the RPCs by the path "/package.Service/Method".
+type handler struct {
+	rpcs map[string]rpc
+	// maxMessageSize is the maximum size of the request message in bytes.
+	maxMessageSize int
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "want POST", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if ct := r.Header.Get("Content-Type"); ct != "application/grpc" && !strings.HasPrefix(ct, "application/grpc+proto") {
+		http.Error(w, "want content type application/grpc", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/grpc")
+	w.Header().Set("Trailer", "Grpc-Status, Grpc-Message")
+
+	response, err := h.serve(r)
+	if err == nil {
+		var header [5]byte
+
+		binary.BigEndian.PutUint32(header[1:], uint32(len(response))) //nolint:gosec
+
+		_, _ = w.Write(header[:])
+		_, _ = w.Write(response)
+	}
+
+	writeStatus(w, err)
+}
+
+// serve calls the RPC of the request path and returns the encoded response message.
+func (h *handler) serve(r *http.Request) ([]byte, error) {
+	call, ok := h.rpcs[r.URL.Path]
+	if !ok {
+		return nil, &statusError{code: codeUnimplemented, err: fmt.Errorf(`unknown method "%s"`, r.URL.Path)}
+	}
+
+	request, err := readMessage(r.Body, h.maxMessageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	return call(request)
+}
+
+// readMessage reads the single length-prefixed message of the unary RPC.
+func readMessage(r io.Reader, maxSize int) ([]byte, error) {
+	var header [5]byte
+
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return nil, invalidArgument(fmt.Errorf("read message: %w", err))
+	}
+
+	if header[0] != 0 {
+		return nil, &statusError{code: codeUnimplemented, err: errors.New("compressed messages are not supported")}
+	}
+
+	size := binary.BigEndian.Uint32(header[1:])
+	if uint64(size) > uint64(maxSize) { //nolint:gosec
+		return nil, &statusError{
+			code: codeResourceExhausted,
+			err:  fmt.Errorf("message size %d exceeds the limit %d", size, maxSize),
+		}
+	}
+
+	b := make([]byte, size)
+
+	if _, err := io.ReadFull(r, b); err != nil {
+		return nil, invalidArgument(fmt.Errorf("read message: %w", err))
+	}
+
+	return b, nil
+}
+
+// writeStatus writes the gRPC status trailers of the err, the error without the status code is INTERNAL.
+func writeStatus(w http.ResponseWriter, err error) {
+	if err == nil {
+		w.Header().Set("Grpc-Status", strconv.Itoa(int(codeOK)))
+		return
+	}
+
+	status := &statusError{code: codeInternal, err: err}
+
+	errors.As(err, &status)
+
+	w.Header().Set("Grpc-Status", strconv.Itoa(int(status.code)))
+	w.Header().Set("Grpc-Message", percentEncode(err.Error()))
+}
+
+// percentEncode encodes the status message, the bytes other than the printable ASCII and "%" are "%XX".
+func percentEncode(s string) string {
+	var sb strings.Builder
+
+	for i := range len(s) {
+		if c := s[i]; c >= ' ' && c <= '~' && c != '%' {
+			sb.WriteByte(c)
+		} else {
+			fmt.Fprintf(&sb, "%%%02X", c)
+		}
+	}
+
+	return sb.String()
+}
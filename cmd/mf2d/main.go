@@ -0,0 +1,143 @@
+/*
+Mf2d is the gRPC server formatting, validating and converting MessageFormat 2 messages,
+to centralize the message formatting of the services in other languages behind a service boundary.
+
+Usage:
+
+	mf2d [-addr host:port] [-cert file -key file] [-max-message-size bytes]
+
+The service "mf2.service.v1.MessageFormat" is defined in mf2d.proto, generate the client from it:
+
+	rpc Format(FormatRequest) returns (FormatResponse);     // message, locale and arguments
+	rpc Validate(ValidateRequest) returns (ValidateResponse); // diagnostics of the message
+	rpc Convert(ConvertRequest) returns (ConvertResponse);    // MF2, ICU MessageFormat or JSON data model
+
+The command is the separate module, the library does not depend on golang.org/x/net of the server.
+Build it in the directory of the command:
+
+	cd cmd/mf2d && go build
+
+The server accepts the plaintext HTTP/2 connections, or TLS if the certificate and key are given.
+The invalid message or locale fails with the status INVALID_ARGUMENT, the message resolving with errors
+is formatted with the fallbacks and the errors are in the response, as in [template.Template.Sprint].
+
+Example:
+
+	mf2d -addr :50051
+	grpcurl -plaintext -import-path cmd/mf2d -proto mf2d.proto \
+		-d '{"message": "Hello, { $name }!", "locale": "lv", "arguments": {"name": {"string_value": "Jānis"}}}' \
+		localhost:50051 mf2.service.v1.MessageFormat/Format
+*/
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+)
+
+// errUsage is returned when the command line is invalid, the usage has already been printed.
+var errUsage = errors.New("invalid usage")
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := run(ctx, os.Args[1:], os.Stderr); err != nil {
+		if !errors.Is(err, errUsage) {
+			fmt.Fprintln(os.Stderr, "mf2d:", err)
+		}
+
+		os.Exit(1) //nolint:gocritic
+	}
+}
+
+// run serves the RPCs until the ctx is done, then waits for the pending RPCs to complete.
+func run(ctx context.Context, args []string, stderr io.Writer) error {
+	var (
+		addr, cert, key string
+		maxMessageSize  int
+	)
+
+	flags := flag.NewFlagSet("mf2d", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprint(stderr, "Usage: mf2d [-addr host:port] [-cert file -key file] [-max-message-size bytes]\n\n")
+		flags.PrintDefaults()
+	}
+
+	flags.StringVar(&addr, "addr", ":50051", "listen on the `host:port`")
+	flags.StringVar(&cert, "cert", "", "TLS certificate `file`")
+	flags.StringVar(&key, "key", "", "TLS private key `file`")
+	flags.IntVar(&maxMessageSize, "max-message-size", 4<<20, "maximum size of the request message in `bytes`") //nolint:mnd
+
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+
+		return errUsage
+	}
+
+	if flags.NArg() > 0 || (cert == "") != (key == "") {
+		flags.Usage()
+		return errUsage
+	}
+
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("listen: %w", err)
+	}
+
+	server := &http.Server{
+		Handler:           &handler{rpcs: rpcs, maxMessageSize: maxMessageSize},
+		ReadHeaderTimeout: 10 * time.Second, //nolint:mnd
+	}
+
+	if err = http2.ConfigureServer(server, nil); err != nil {
+		return fmt.Errorf("configure HTTP/2: %w", err)
+	}
+
+	// the gRPC clients without TLS speak HTTP/2 with prior knowledge, net/http serves it since Go 1.24 only
+	if cert == "" {
+		server.Handler = h2c.NewHandler(server.Handler, &http2.Server{})
+	}
+
+	fmt.Fprintln(stderr, "mf2d: listening on", ln.Addr())
+
+	done := make(chan error, 1)
+
+	go func() {
+		if cert == "" {
+			done <- server.Serve(ln)
+		} else {
+			done <- server.ServeTLS(ln, cert, key)
+		}
+	}()
+
+	select {
+	case err = <-done:
+		return fmt.Errorf("serve: %w", err)
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second) //nolint:mnd
+	defer cancel()
+
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("shutdown: %w", err)
+	}
+
+	return nil
+}
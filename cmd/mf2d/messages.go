@@ -0,0 +1,233 @@
+//nolint:mnd // protobuf field numbers
+package main
+
+import (
+	"fmt"
+
+	"go.expect.digital/mf2"
+	"go.expect.digital/mf2/internal/protowire"
+)
+
+// Messages of mf2d.proto. The server decodes the requests and encodes the responses only.
+
+// syntax is the Syntax enum of the converted message.
+type syntax uint64
+
+const (
+	syntaxMF2 syntax = iota
+	syntaxICU
+	syntaxJSON
+)
+
+// String returns the name of the syntax.
+func (s syntax) String() string {
+	switch s {
+	default:
+		return fmt.Sprintf("Syntax(%d)", uint64(s))
+	case syntaxMF2:
+		return "SYNTAX_MF2"
+	case syntaxICU:
+		return "SYNTAX_ICU"
+	case syntaxJSON:
+		return "SYNTAX_JSON"
+	}
+}
+
+type formatRequest struct {
+	Arguments map[string]any
+	Message   string
+	Locale    string
+}
+
+func (r *formatRequest) unmarshal(b []byte) error {
+	return protowire.Range(b, func(f protowire.Field) error {
+		var err error
+
+		switch f.Num {
+		case 1:
+			r.Message, err = f.String()
+		case 2:
+			r.Locale, err = f.String()
+		case 3:
+			var data []byte
+
+			if data, err = f.Bytes(); err != nil {
+				return err
+			}
+
+			if r.Arguments == nil {
+				r.Arguments = make(map[string]any)
+			}
+
+			err = unmarshalArgument(data, r.Arguments)
+		}
+
+		return err
+	})
+}
+
+// unmarshalArgument decodes the map entry of the arguments into the args.
+func unmarshalArgument(b []byte, args map[string]any) error {
+	var (
+		name  string
+		value any
+	)
+
+	err := protowire.Range(b, func(f protowire.Field) error {
+		var err error
+
+		switch f.Num {
+		case 1:
+			name, err = f.String()
+		case 2:
+			var data []byte
+
+			if data, err = f.Bytes(); err != nil {
+				return err
+			}
+
+			value, err = unmarshalValue(data)
+		}
+
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("argument: %w", err)
+	}
+
+	args[name] = value
+
+	return nil
+}
+
+// unmarshalValue decodes the Value message, the last field of the oneof wins.
+func unmarshalValue(b []byte) (any, error) {
+	var value any
+
+	err := protowire.Range(b, func(f protowire.Field) error {
+		var (
+			u   uint64
+			err error
+		)
+
+		switch f.Num {
+		case 1:
+			value, err = f.String()
+		case 2:
+			value, err = f.Double()
+		case 3:
+			u, err = f.Varint()
+			value = int64(u) //nolint:gosec
+		case 4:
+			u, err = f.Varint()
+			value = u != 0
+		}
+
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("value: %w", err)
+	}
+
+	return value, nil
+}
+
+type formatResponse struct {
+	Result string
+	Errors []responseError
+}
+
+func (r formatResponse) marshal() []byte {
+	b := protowire.AppendNonEmpty(nil, 1, r.Result)
+
+	for _, e := range r.Errors {
+		b = protowire.AppendBytes(b, 2, e.marshal())
+	}
+
+	return b
+}
+
+type responseError struct {
+	Code    string
+	Message string
+}
+
+func (e responseError) marshal() []byte {
+	return protowire.AppendNonEmpty(protowire.AppendNonEmpty(nil, 1, e.Code), 2, e.Message)
+}
+
+type validateRequest struct {
+	Message string
+}
+
+func (r *validateRequest) unmarshal(b []byte) error {
+	return protowire.Range(b, func(f protowire.Field) error {
+		var err error
+
+		if f.Num == 1 {
+			r.Message, err = f.String()
+		}
+
+		return err
+	})
+}
+
+type validateResponse struct {
+	Diagnostics []mf2.Diagnostic
+}
+
+func (r validateResponse) marshal() []byte {
+	var b []byte
+
+	for _, d := range r.Diagnostics {
+		b = protowire.AppendBytes(b, 1, marshalDiagnostic(d))
+	}
+
+	return b
+}
+
+// marshalDiagnostic encodes the Diagnostic message of the [mf2.Diagnostic], the related locations excluded.
+func marshalDiagnostic(d mf2.Diagnostic) []byte {
+	b := protowire.AppendNonEmpty(nil, 1, d.Severity.String())
+	b = protowire.AppendNonEmpty(b, 2, d.Code)
+	b = protowire.AppendNonEmpty(b, 3, d.Message)
+	b = protowire.AppendNonZero(b, 4, uint64(d.Span.Start)) //nolint:gosec
+
+	return protowire.AppendNonZero(b, 5, uint64(d.Span.End)) //nolint:gosec
+}
+
+type convertRequest struct {
+	Message string
+	From    syntax
+	To      syntax
+}
+
+func (r *convertRequest) unmarshal(b []byte) error {
+	return protowire.Range(b, func(f protowire.Field) error {
+		var (
+			u   uint64
+			err error
+		)
+
+		switch f.Num {
+		case 1:
+			r.Message, err = f.String()
+		case 2:
+			u, err = f.Varint()
+			r.From = syntax(u)
+		case 3:
+			u, err = f.Varint()
+			r.To = syntax(u)
+		}
+
+		return err
+	})
+}
+
+type convertResponse struct {
+	Message string
+}
+
+func (r convertResponse) marshal() []byte {
+	return protowire.AppendNonEmpty(nil, 1, r.Message)
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"fmt"
+
+	"golang.org/x/text/language"
+
+	"go.expect.digital/mf2"
+	"go.expect.digital/mf2/arb"
+	"go.expect.digital/mf2/datamodel"
+	"go.expect.digital/mf2/internal/multierr"
+	"go.expect.digital/mf2/parse"
+	"go.expect.digital/mf2/template"
+)
+
+// rpcs are the RPCs of the "mf2.service.v1.MessageFormat" service defined in mf2d.proto.
+var rpcs = map[string]rpc{
+	"/mf2.service.v1.MessageFormat/Format":   unary(format),
+	"/mf2.service.v1.MessageFormat/Validate": unary(validate),
+	"/mf2.service.v1.MessageFormat/Convert":  unary(convert),
+}
+
+// format returns the formatted message and the resolution errors. The message is formatted also
+// if it resolves with errors, as in [template.Template.Sprint]. The default locale is American English.
+func format(req formatRequest) (formatResponse, error) {
+	locale := language.AmericanEnglish
+
+	if req.Locale != "" {
+		var err error
+
+		if locale, err = language.Parse(req.Locale); err != nil {
+			return formatResponse{}, invalidArgument(fmt.Errorf("locale: %w", err))
+		}
+	}
+
+	t, err := template.New(template.WithLocale(locale)).Parse(req.Message)
+	if err != nil {
+		return formatResponse{}, invalidArgument(err)
+	}
+
+	var resp formatResponse
+
+	resp.Result, err = t.Sprint(req.Arguments)
+
+	for _, err := range multierr.Errors(err) {
+		resp.Errors = append(resp.Errors, responseError{Code: mf2.ErrorCode(err), Message: err.Error()})
+	}
+
+	return resp, nil
+}
+
+// validate returns the diagnostics of the message, see [parse.Diagnose].
+func validate(req validateRequest) (validateResponse, error) {
+	_, diagnostics := parse.Diagnose(req.Message)
+
+	return validateResponse{Diagnostics: diagnostics}, nil
+}
+
+// convert returns the message converted from one syntax to another, e.g. from ICU MessageFormat to MF2.
+func convert(req convertRequest) (convertResponse, error) {
+	var (
+		tree parse.AST
+		err  error
+	)
+
+	switch req.From {
+	default:
+		return convertResponse{}, invalidArgument(fmt.Errorf("unsupported syntax %s", req.From))
+	case syntaxMF2:
+		tree, err = parse.Parse(req.Message)
+	case syntaxICU:
+		tree, err = arb.FromICU(req.Message)
+	case syntaxJSON:
+		// the data model is not validated when unmarshaled
+		if tree, err = datamodel.UnmarshalJSON([]byte(req.Message)); err == nil {
+			tree, err = parse.Parse(tree.String())
+		}
+	}
+
+	if err != nil {
+		return convertResponse{}, invalidArgument(err)
+	}
+
+	var resp convertResponse
+
+	switch req.To {
+	default:
+		return convertResponse{}, invalidArgument(fmt.Errorf("unsupported syntax %s", req.To))
+	case syntaxMF2:
+		resp.Message = parse.Canonical(tree).String()
+	case syntaxICU:
+		resp.Message, err = arb.ToICU(tree)
+	case syntaxJSON:
+		var b []byte
+
+		b, err = datamodel.MarshalJSON(tree)
+		resp.Message = string(b)
+	}
+
+	if err != nil {
+		return convertResponse{}, invalidArgument(err)
+	}
+
+	return resp, nil
+}
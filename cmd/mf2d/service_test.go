@@ -0,0 +1,222 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"go.expect.digital/mf2/internal/protowire"
+)
+
+// call calls the RPC over HTTP/2 and returns the response message, the status code and message.
+func call(t *testing.T, server *httptest.Server, method string, request []byte) ([]byte, string, string) {
+	t.Helper()
+
+	var body bytes.Buffer
+
+	body.Write(binary.BigEndian.AppendUint32([]byte{0}, uint32(len(request)))) //nolint:gosec
+	body.Write(request)
+
+	req, err := http.NewRequest(http.MethodPost, server.URL+"/mf2.service.v1.MessageFormat/"+method, &body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req.Header.Set("Content-Type", "application/grpc")
+	req.Header.Set("Te", "trailers")
+
+	resp, err := server.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer resp.Body.Close()
+
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(b) >= 5 {
+		b = b[5:]
+	}
+
+	return b, resp.Trailer.Get("Grpc-Status"), resp.Trailer.Get("Grpc-Message")
+}
+
+// decode returns the varint and bytes fields of the proto message by the field number.
+func decode(t *testing.T, b []byte) map[int][]any {
+	t.Helper()
+
+	fields := make(map[int][]any)
+
+	err := protowire.Range(b, func(f protowire.Field) error {
+		switch f.Type { //nolint:exhaustive
+		case protowire.Varint:
+			v, _ := f.Varint()
+			fields[f.Num] = append(fields[f.Num], v)
+		case protowire.Bytes:
+			v, _ := f.Bytes()
+			fields[f.Num] = append(fields[f.Num], string(v))
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return fields
+}
+
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewUnstartedServer(&handler{rpcs: rpcs, maxMessageSize: 1024})
+	server.EnableHTTP2 = true
+	server.StartTLS()
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestFormat(t *testing.T) {
+	t.Parallel()
+
+	server := newTestServer(t)
+
+	value := func(num int, v []byte) []byte { return protowire.AppendBytes(nil, num, v) }
+	argument := func(name string, v []byte) []byte {
+		return protowire.AppendBytes(nil, 3, protowire.AppendBytes(protowire.AppendNonEmpty(nil, 1, name), 2, v))
+	}
+
+	request := protowire.AppendNonEmpty(nil, 1, "{$name} {$count :integer} {$price :number minimumFractionDigits=1} {$ok}")
+	request = protowire.AppendNonEmpty(request, 2, "lv")
+	request = append(request, argument("name", value(1, []byte("Jānis")))...)
+	request = append(request, argument("count", binary.AppendUvarint(protowire.AppendTag(nil, 3, protowire.Varint), 100))...)
+	request = append(request, argument("price",
+		binary.LittleEndian.AppendUint64(protowire.AppendTag(nil, 2, protowire.Fixed64), math.Float64bits(1.5)))...)
+	request = append(request, argument("ok", protowire.AppendNonZero(nil, 4, 1))...)
+
+	response, status, message := call(t, server, "Format", request)
+	if status != "0" {
+		t.Fatalf("want status 0, got '%s': %s", status, message)
+	}
+
+	got := decode(t, response)
+
+	if want := map[int][]any{1: {"Jānis 100 1,5 true"}}; !reflect.DeepEqual(want, got) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+
+	// resolution errors
+	response, status, _ = call(t, server, "Format", protowire.AppendNonEmpty(nil, 1, "Hello, {$name}!"))
+	if status != "0" {
+		t.Fatalf("want status 0, got '%s'", status)
+	}
+
+	got = decode(t, response)
+
+	if want := "Hello, {$name}!"; got[1][0] != want {
+		t.Errorf("want '%s', got '%s'", want, got[1][0])
+	}
+
+	if want, got := "unresolved-variable", decode(t, []byte(got[2][0].(string)))[1][0]; want != got {
+		t.Errorf("want '%s', got '%s'", want, got)
+	}
+
+	// syntax error
+	if _, status, _ = call(t, server, "Format", protowire.AppendNonEmpty(nil, 1, "{$name")); status != "3" {
+		t.Errorf("want status 3, got '%s'", status)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	server := newTestServer(t)
+
+	response, status, _ := call(t, server, "Validate", protowire.AppendNonEmpty(nil, 1, "Hello, {$name}!"))
+	if status != "0" || len(response) != 0 {
+		t.Errorf("want no diagnostics, got status '%s' and %v", status, response)
+	}
+
+	response, _, _ = call(t, server, "Validate", protowire.AppendNonEmpty(nil, 1, "{$name"))
+
+	got := decode(t, []byte(decode(t, response)[1][0].(string)))
+
+	if want := "syntax-error"; got[2][0] != want {
+		t.Errorf("want '%s', got '%s'", want, got[2][0])
+	}
+
+	if want := "error"; got[1][0] != want {
+		t.Errorf("want '%s', got '%s'", want, got[1][0])
+	}
+}
+
+func TestConvert(t *testing.T) {
+	t.Parallel()
+
+	server := newTestServer(t)
+
+	for _, test := range []struct {
+		message  string
+		from, to syntax
+		want     string
+		status   string
+	}{
+		{message: "Hello, {$name}!", from: syntaxMF2, to: syntaxMF2, want: "Hello, { $name }!", status: "0"},
+		{message: "Hello, {name}!", from: syntaxICU, to: syntaxMF2, want: "Hello, { $name }!", status: "0"},
+		{message: "Hello, { $name }!", from: syntaxMF2, to: syntaxICU, want: "Hello, {name}!", status: "0"},
+		{
+			message: `{"type":"message","declarations":[],"pattern":["Hello"]}`,
+			from:    syntaxJSON,
+			to:      syntaxMF2,
+			want:    "Hello",
+			status:  "0",
+		},
+		{message: "{$name", from: syntaxMF2, to: syntaxMF2, status: "3"},
+		{message: "Hello", from: syntaxMF2, to: 7, status: "3"},
+	} {
+		request := protowire.AppendNonEmpty(nil, 1, test.message)
+		request = protowire.AppendNonZero(request, 2, uint64(test.from))
+		request = protowire.AppendNonZero(request, 3, uint64(test.to))
+
+		response, status, message := call(t, server, "Convert", request)
+		if status != test.status {
+			t.Errorf("%s: want status '%s', got '%s': %s", test.message, test.status, status, message)
+			continue
+		}
+
+		if status != "0" {
+			continue
+		}
+
+		if got := decode(t, response)[1]; len(got) != 1 || got[0] != test.want {
+			t.Errorf("want '%s', got %v", test.want, got)
+		}
+	}
+}
+
+func TestHandler(t *testing.T) {
+	t.Parallel()
+
+	server := newTestServer(t)
+
+	if _, status, _ := call(t, server, "Unknown", nil); status != "12" {
+		t.Errorf("want status 12, got '%s'", status)
+	}
+
+	if _, status, _ := call(t, server, "Validate", make([]byte, 1025)); status != "8" {
+		t.Errorf("want status 8, got '%s'", status)
+	}
+
+	if want, got := "a%25b%C3%A9", percentEncode("a%bé"); want != got {
+		t.Errorf("want '%s', got '%s'", want, got)
+	}
+}
@@ -0,0 +1,901 @@
+package datamodel
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"go.expect.digital/mf2/parse"
+)
+
+// JSONOption configures [MarshalJSON].
+type JSONOption func(o *jsonOptions)
+
+type jsonOptions struct {
+	indent    string
+	omitEmpty bool
+}
+
+// WithIndent indents the JSON output, as JSON.stringify(model, null, indent) does.
+func WithIndent(indent string) JSONOption {
+	return func(o *jsonOptions) {
+		o.indent = indent
+	}
+}
+
+// WithOmitEmpty omits empty "options" and "attributes" arrays, which are optional in some implementations.
+func WithOmitEmpty() JSONOption {
+	return func(o *jsonOptions) {
+		o.omitEmpty = true
+	}
+}
+
+// MarshalJSON encodes the AST in the JSON representation of the MF2 data model,
+// the same representation produced by other implementations, e.g. ICU4X and messageformat.js.
+//
+// Object members are written in the order of the data model definition and text is not
+// HTML-escaped, so the output is byte-compatible with JSON.stringify of the same model.
+//
+// Example:
+//
+//	tree, _ := parse.Parse("Hello, { $name }!")
+//	b, _ := datamodel.MarshalJSON(tree)
+//
+//	fmt.Print(string(b))
+//	// {"type":"message","declarations":[],"pattern":["Hello, ",{"type":"expression","arg":{"type":"variable","name":"name"},"attributes":[]},"!"]}
+func MarshalJSON(tree parse.AST, options ...JSONOption) ([]byte, error) {
+	errorf := func(format string, args ...any) ([]byte, error) {
+		return nil, fmt.Errorf("marshal json: "+format, args...)
+	}
+
+	var opts jsonOptions
+
+	for _, o := range options {
+		o(&opts)
+	}
+
+	m := &jsonMarshaler{omitEmpty: opts.omitEmpty}
+
+	model, err := m.message(tree.Message)
+	if err != nil {
+		return errorf("%w", err)
+	}
+
+	b, err := encodeJSON(model)
+	if err != nil {
+		return errorf("%w", err)
+	}
+
+	if opts.indent == "" {
+		return b, nil
+	}
+
+	var buf bytes.Buffer
+
+	if err := json.Indent(&buf, b, "", opts.indent); err != nil {
+		return errorf("%w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJSON decodes the AST from the JSON representation of the MF2 data model.
+//
+// The data model does not distinguish between quoted, name and number literals,
+// literals are quoted only if the value is not a valid name or number literal.
+func UnmarshalJSON(data []byte) (parse.AST, error) {
+	var n jsonNode
+
+	if err := json.Unmarshal(data, &n); err != nil {
+		return parse.AST{}, fmt.Errorf("unmarshal json: %w", err)
+	}
+
+	msg, err := n.message()
+	if err != nil {
+		return parse.AST{}, fmt.Errorf("unmarshal json: %w", err)
+	}
+
+	return parse.AST{Message: msg}, nil
+}
+
+// -----------------------------------Marshal-----------------------------------
+
+// member is a single member of the JSON object.
+type member struct {
+	value any
+	key   string
+}
+
+// object is JSON object with members in order.
+type object []member
+
+// MarshalJSON implements [json.Marshaler].
+func (o object) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+
+	buf.WriteByte('{')
+
+	for i, m := range o {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		key, err := encodeJSON(m.key)
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := encodeJSON(m.value)
+		if err != nil {
+			return nil, err
+		}
+
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
+
+// encodeJSON encodes the value without HTML escaping.
+func encodeJSON(v any) ([]byte, error) {
+	var buf bytes.Buffer
+
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+
+	if err := enc.Encode(v); err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+type jsonMarshaler struct {
+	omitEmpty bool
+}
+
+// list appends the array member, empty optional arrays are omitted if configured.
+func (m *jsonMarshaler) list(o object, key string, values []any) object {
+	if len(values) == 0 && m.omitEmpty {
+		return o
+	}
+
+	if values == nil {
+		values = []any{}
+	}
+
+	return append(o, member{key: key, value: values})
+}
+
+func (m *jsonMarshaler) message(msg parse.Message) (object, error) {
+	var (
+		declarations []parse.Declaration
+		body         parse.ComplexBody
+	)
+
+	switch v := msg.(type) {
+	default:
+		return nil, fmt.Errorf("unsupported message %T", msg)
+	case nil:
+		body = parse.QuotedPattern{}
+	case parse.SimpleMessage:
+		body = parse.QuotedPattern(v)
+	case parse.ComplexMessage:
+		declarations, body = v.Declarations, v.ComplexBody
+	}
+
+	decls := make([]any, 0, len(declarations))
+
+	for _, decl := range declarations {
+		d, err := m.declaration(decl)
+		if err != nil {
+			return nil, err
+		}
+
+		decls = append(decls, d)
+	}
+
+	switch b := body.(type) {
+	default:
+		return nil, fmt.Errorf("unsupported complex body %T", body)
+	case parse.QuotedPattern:
+		pattern, err := m.pattern(b)
+		if err != nil {
+			return nil, err
+		}
+
+		return object{{key: "type", value: "message"}, {key: "declarations", value: decls}, {key: "pattern", value: pattern}}, nil
+	case parse.Matcher:
+		selectors := make([]any, 0, len(b.Selectors))
+
+		for _, selector := range b.Selectors {
+			expr, err := m.expression(selector)
+			if err != nil {
+				return nil, err
+			}
+
+			selectors = append(selectors, expr)
+		}
+
+		variants := make([]any, 0, len(b.Variants))
+
+		for _, variant := range b.Variants {
+			v, err := m.variant(variant)
+			if err != nil {
+				return nil, err
+			}
+
+			variants = append(variants, v)
+		}
+
+		return object{
+			{key: "type", value: "select"},
+			{key: "declarations", value: decls},
+			{key: "selectors", value: selectors},
+			{key: "variants", value: variants},
+		}, nil
+	}
+}
+
+func (m *jsonMarshaler) declaration(decl parse.Declaration) (object, error) {
+	switch d := decl.(type) {
+	default:
+		return nil, fmt.Errorf("unsupported declaration %T", decl)
+	case parse.InputDeclaration:
+		expr, err := m.expression(parse.Expression(d))
+		if err != nil {
+			return nil, err
+		}
+
+		v, _ := d.Operand.(parse.Variable)
+
+		return object{{key: "type", value: "input"}, {key: "name", value: string(v)}, {key: "value", value: expr}}, nil
+	case parse.LocalDeclaration:
+		expr, err := m.expression(d.Expression)
+		if err != nil {
+			return nil, err
+		}
+
+		return object{{key: "type", value: "local"}, {key: "name", value: string(d.Variable)}, {key: "value", value: expr}}, nil
+	case parse.ReservedStatement:
+		o := object{{key: "type", value: "unsupported-statement"}, {key: "keyword", value: d.Keyword}}
+
+		if len(d.ReservedBody) > 0 {
+			o = append(o, member{key: "body", value: reservedBody(d.ReservedBody)})
+		}
+
+		expressions := make([]any, 0, len(d.Expressions))
+
+		for _, e := range d.Expressions {
+			expr, err := m.expression(e)
+			if err != nil {
+				return nil, err
+			}
+
+			expressions = append(expressions, expr)
+		}
+
+		return append(o, member{key: "expressions", value: expressions}), nil
+	}
+}
+
+func (m *jsonMarshaler) variant(variant parse.Variant) (object, error) {
+	keys := make([]any, 0, len(variant.Keys))
+
+	for _, key := range variant.Keys {
+		switch k := key.(type) {
+		default:
+			return nil, fmt.Errorf("unsupported variant key %T", key)
+		case parse.CatchAllKey:
+			keys = append(keys, object{{key: "type", value: "*"}})
+		case parse.Literal:
+			keys = append(keys, literal(k))
+		}
+	}
+
+	pattern, err := m.pattern(variant.QuotedPattern)
+	if err != nil {
+		return nil, err
+	}
+
+	return object{{key: "keys", value: keys}, {key: "value", value: pattern}}, nil
+}
+
+func (m *jsonMarshaler) pattern(pattern []parse.PatternPart) ([]any, error) {
+	parts := make([]any, 0, len(pattern))
+
+	for _, part := range pattern {
+		switch p := part.(type) {
+		default:
+			return nil, fmt.Errorf("unsupported pattern part %T", part)
+		case parse.Text:
+			// consecutive text is a single string in the data model
+			if i := len(parts) - 1; i >= 0 {
+				if s, ok := parts[i].(string); ok {
+					parts[i] = s + string(p)
+					continue
+				}
+			}
+
+			parts = append(parts, string(p))
+		case parse.Expression:
+			expr, err := m.expression(p)
+			if err != nil {
+				return nil, err
+			}
+
+			parts = append(parts, expr)
+		case parse.Markup:
+			markup, err := m.markup(p)
+			if err != nil {
+				return nil, err
+			}
+
+			parts = append(parts, markup)
+		}
+	}
+
+	return parts, nil
+}
+
+func (m *jsonMarshaler) expression(expr parse.Expression) (object, error) {
+	o := object{{key: "type", value: "expression"}}
+
+	if expr.Operand != nil {
+		arg, err := value(expr.Operand)
+		if err != nil {
+			return nil, err
+		}
+
+		o = append(o, member{key: "arg", value: arg})
+	}
+
+	switch a := expr.Annotation.(type) {
+	default:
+		return nil, fmt.Errorf("unsupported annotation %T", a)
+	case nil:
+	case parse.Function:
+		options, err := m.options(a.Options)
+		if err != nil {
+			return nil, err
+		}
+
+		function := object{{key: "type", value: "function"}, {key: "name", value: a.Identifier.String()}}
+		o = append(o, member{key: "annotation", value: m.list(function, "options", options)})
+	case parse.PrivateUseAnnotation:
+		o = append(o, member{key: "annotation", value: unsupportedAnnotation(a.Start, a.ReservedBody)})
+	case parse.ReservedAnnotation:
+		o = append(o, member{key: "annotation", value: unsupportedAnnotation(a.Start, a.ReservedBody)})
+	}
+
+	attributes, err := m.attributes(expr.Attributes)
+	if err != nil {
+		return nil, err
+	}
+
+	return m.list(o, "attributes", attributes), nil
+}
+
+func (m *jsonMarshaler) markup(markup parse.Markup) (object, error) {
+	var kind string
+
+	switch markup.Typ {
+	default:
+		return nil, fmt.Errorf("unsupported markup type %d", markup.Typ)
+	case parse.Open:
+		kind = "open"
+	case parse.SelfClose:
+		kind = "standalone"
+	case parse.Close:
+		kind = "close"
+	}
+
+	options, err := m.options(markup.Options)
+	if err != nil {
+		return nil, err
+	}
+
+	attributes, err := m.attributes(markup.Attributes)
+	if err != nil {
+		return nil, err
+	}
+
+	o := object{{key: "type", value: "markup"}, {key: "kind", value: kind}, {key: "name", value: markup.Identifier.String()}}
+
+	return m.list(m.list(o, "options", options), "attributes", attributes), nil
+}
+
+func (m *jsonMarshaler) options(options []parse.Option) ([]any, error) {
+	r := make([]any, 0, len(options))
+
+	for _, option := range options {
+		v, err := value(option.Value)
+		if err != nil {
+			return nil, err
+		}
+
+		r = append(r, object{{key: "name", value: option.Identifier.String()}, {key: "value", value: v}})
+	}
+
+	return r, nil
+}
+
+func (m *jsonMarshaler) attributes(attributes []parse.Attribute) ([]any, error) {
+	r := make([]any, 0, len(attributes))
+
+	for _, attribute := range attributes {
+		o := object{{key: "name", value: attribute.Identifier.String()}}
+
+		if attribute.Value != nil {
+			v, err := value(attribute.Value)
+			if err != nil {
+				return nil, err
+			}
+
+			o = append(o, member{key: "value", value: v})
+		}
+
+		r = append(r, o)
+	}
+
+	return r, nil
+}
+
+func value(v parse.Value) (object, error) {
+	switch v := v.(type) {
+	default:
+		return nil, fmt.Errorf("unsupported value %T", v)
+	case parse.Variable:
+		return object{{key: "type", value: "variable"}, {key: "name", value: string(v)}}, nil
+	case parse.Literal:
+		return literal(v), nil
+	}
+}
+
+func literal(l parse.Literal) object {
+	var s string
+
+	switch v := l.(type) {
+	default:
+		s = v.String()
+	case parse.QuotedLiteral:
+		s = string(v)
+	case parse.NameLiteral:
+		s = string(v)
+	}
+
+	return object{{key: "type", value: "literal"}, {key: "value", value: s}}
+}
+
+func unsupportedAnnotation(start rune, body []parse.ReservedBody) object {
+	return object{{key: "type", value: "unsupported-annotation"}, {key: "source", value: string(start) + reservedBody(body)}}
+}
+
+// reservedBody returns the source of the reserved body.
+func reservedBody(body []parse.ReservedBody) string {
+	s := make([]string, 0, len(body))
+	for _, part := range body {
+		s = append(s, part.String())
+	}
+
+	return strings.Join(s, " ")
+}
+
+// ----------------------------------Unmarshal----------------------------------
+
+// jsonNode is any node of the JSON data model.
+type jsonNode struct {
+	Arg          *jsonNode         `json:"arg"`
+	Annotation   *jsonNode         `json:"annotation"`
+	Type         string            `json:"type"`
+	Name         string            `json:"name"`
+	Kind         string            `json:"kind"`
+	Keyword      string            `json:"keyword"`
+	Body         string            `json:"body"`
+	Source       string            `json:"source"`
+	Value        json.RawMessage   `json:"value"` // literal value, declaration expression or variant pattern
+	Declarations []jsonNode        `json:"declarations"`
+	Pattern      []json.RawMessage `json:"pattern"`
+	Selectors    []jsonNode        `json:"selectors"`
+	Variants     []jsonNode        `json:"variants"`
+	Keys         []jsonNode        `json:"keys"`
+	Options      []jsonNode        `json:"options"`
+	Attributes   []jsonNode        `json:"attributes"`
+	Expressions  []jsonNode        `json:"expressions"`
+}
+
+func (n *jsonNode) message() (parse.Message, error) { //nolint:ireturn
+	declarations := make([]parse.Declaration, 0, len(n.Declarations))
+
+	for _, d := range n.Declarations {
+		decl, err := d.declaration()
+		if err != nil {
+			return nil, err
+		}
+
+		declarations = append(declarations, decl)
+	}
+
+	if len(declarations) == 0 {
+		declarations = nil
+	}
+
+	switch n.Type {
+	default:
+		return nil, fmt.Errorf(`want message type "message" or "select", got "%s"`, n.Type)
+	case "message":
+		pattern, err := unmarshalJSONPattern(n.Pattern)
+		if err != nil {
+			return nil, err
+		}
+
+		// the simple message can not start with ".", it would be parsed as the complex message
+		if text, ok := firstText(pattern); len(declarations) > 0 || (ok && strings.HasPrefix(string(text), ".")) {
+			return parse.ComplexMessage{Declarations: declarations, ComplexBody: parse.QuotedPattern(pattern)}, nil
+		}
+
+		return parse.SimpleMessage(pattern), nil
+	case "select":
+		matcher := parse.Matcher{Selectors: make([]parse.Expression, 0, len(n.Selectors))}
+
+		for _, s := range n.Selectors {
+			selector, err := s.expression()
+			if err != nil {
+				return nil, err
+			}
+
+			matcher.Selectors = append(matcher.Selectors, selector)
+		}
+
+		for _, v := range n.Variants {
+			variant, err := v.variant()
+			if err != nil {
+				return nil, err
+			}
+
+			matcher.Variants = append(matcher.Variants, variant)
+		}
+
+		return parse.ComplexMessage{Declarations: declarations, ComplexBody: matcher}, nil
+	}
+}
+
+func firstText(pattern []parse.PatternPart) (parse.Text, bool) {
+	if len(pattern) == 0 {
+		return "", false
+	}
+
+	text, ok := pattern[0].(parse.Text)
+
+	return text, ok
+}
+
+func (n *jsonNode) declaration() (parse.Declaration, error) { //nolint:ireturn
+	switch n.Type {
+	default:
+		return nil, fmt.Errorf(`unsupported declaration type "%s"`, n.Type)
+	case "input", "local":
+		var value jsonNode
+
+		if err := json.Unmarshal(n.Value, &value); err != nil {
+			return nil, fmt.Errorf("%s declaration: %w", n.Type, err)
+		}
+
+		expr, err := value.expression()
+		if err != nil {
+			return nil, err
+		}
+
+		if n.Type == "local" {
+			return parse.LocalDeclaration{Variable: parse.Variable(n.Name), Expression: expr}, nil
+		}
+
+		if v, ok := expr.Operand.(parse.Variable); !ok || string(v) != n.Name {
+			return nil, fmt.Errorf(`input declaration "%s": want variable expression "$%s"`, n.Name, n.Name)
+		}
+
+		return parse.InputDeclaration(expr), nil
+	case "unsupported-statement":
+		expressions := make([]string, 0, len(n.Expressions))
+
+		for _, e := range n.Expressions {
+			expr, err := e.expression()
+			if err != nil {
+				return nil, err
+			}
+
+			expressions = append(expressions, expr.String())
+		}
+
+		// reserved body is parsed from the source
+		src := "." + n.Keyword + " " + n.Body + " " + strings.Join(expressions, " ") + " {{}}"
+
+		tree, err := parse.Parse(src)
+		if err != nil {
+			return nil, fmt.Errorf(`unsupported statement "%s": %w`, n.Keyword, err)
+		}
+
+		msg, ok := tree.Message.(parse.ComplexMessage)
+		if !ok || len(msg.Declarations) != 1 {
+			return nil, fmt.Errorf(`unsupported statement "%s": want single statement`, n.Keyword)
+		}
+
+		return msg.Declarations[0], nil
+	}
+}
+
+func (n *jsonNode) variant() (parse.Variant, error) {
+	var variant parse.Variant
+
+	for _, k := range n.Keys {
+		switch k.Type {
+		default:
+			return parse.Variant{}, fmt.Errorf(`want variant key type "literal" or "*", got "%s"`, k.Type)
+		case "*":
+			variant.Keys = append(variant.Keys, parse.CatchAllKey{})
+		case "literal":
+			l, err := k.literal()
+			if err != nil {
+				return parse.Variant{}, err
+			}
+
+			variant.Keys = append(variant.Keys, l)
+		}
+	}
+
+	var pattern []json.RawMessage
+
+	if err := json.Unmarshal(n.Value, &pattern); err != nil {
+		return parse.Variant{}, fmt.Errorf("variant value: %w", err)
+	}
+
+	parts, err := unmarshalJSONPattern(pattern)
+	if err != nil {
+		return parse.Variant{}, err
+	}
+
+	variant.QuotedPattern = parts
+
+	return variant, nil
+}
+
+func unmarshalJSONPattern(pattern []json.RawMessage) ([]parse.PatternPart, error) {
+	parts := make([]parse.PatternPart, 0, len(pattern))
+
+	for _, raw := range pattern {
+		var text string
+
+		if err := json.Unmarshal(raw, &text); err == nil {
+			parts = append(parts, parse.Text(text))
+			continue
+		}
+
+		var n jsonNode
+
+		if err := json.Unmarshal(raw, &n); err != nil {
+			return nil, fmt.Errorf("pattern: %w", err)
+		}
+
+		switch n.Type {
+		default:
+			return nil, fmt.Errorf(`want pattern part type "expression" or "markup", got "%s"`, n.Type)
+		case "expression":
+			expr, err := n.expression()
+			if err != nil {
+				return nil, err
+			}
+
+			parts = append(parts, expr)
+		case "markup":
+			markup, err := n.markup()
+			if err != nil {
+				return nil, err
+			}
+
+			parts = append(parts, markup)
+		}
+	}
+
+	return parts, nil
+}
+
+func (n *jsonNode) expression() (parse.Expression, error) {
+	if n.Type != "expression" {
+		return parse.Expression{}, fmt.Errorf(`want type "expression", got "%s"`, n.Type)
+	}
+
+	var (
+		expr parse.Expression
+		err  error
+	)
+
+	if n.Arg != nil {
+		if expr.Operand, err = n.Arg.value(); err != nil {
+			return parse.Expression{}, err
+		}
+	}
+
+	if n.Annotation != nil {
+		if expr.Annotation, err = n.Annotation.annotation(); err != nil {
+			return parse.Expression{}, err
+		}
+	}
+
+	if expr.Attributes, err = attributes(n.Attributes); err != nil {
+		return parse.Expression{}, err
+	}
+
+	return expr, nil
+}
+
+func (n *jsonNode) annotation() (parse.Annotation, error) { //nolint:ireturn
+	switch n.Type {
+	default:
+		return nil, fmt.Errorf(`want annotation type "function" or "unsupported-annotation", got "%s"`, n.Type)
+	case "function":
+		options, err := options(n.Options)
+		if err != nil {
+			return nil, err
+		}
+
+		return parse.Function{Identifier: identifier(n.Name), Options: options}, nil
+	case "unsupported-annotation":
+		// private-use and reserved annotations are parsed from the source
+		tree, err := parse.Parse("{" + n.Source + "}")
+		if err != nil {
+			return nil, fmt.Errorf(`unsupported annotation "%s": %w`, n.Source, err)
+		}
+
+		if msg, ok := tree.Message.(parse.SimpleMessage); ok && len(msg) == 1 {
+			if expr, ok := msg[0].(parse.Expression); ok && expr.Operand == nil && expr.Annotation != nil {
+				return expr.Annotation, nil
+			}
+		}
+
+		return nil, fmt.Errorf(`unsupported annotation "%s": want single annotation`, n.Source)
+	}
+}
+
+func (n *jsonNode) markup() (parse.Markup, error) {
+	markup := parse.Markup{Identifier: identifier(n.Name)}
+
+	switch n.Kind {
+	default:
+		return parse.Markup{}, fmt.Errorf(`want markup kind "open", "standalone" or "close", got "%s"`, n.Kind)
+	case "open":
+		markup.Typ = parse.Open
+	case "standalone":
+		markup.Typ = parse.SelfClose
+	case "close":
+		markup.Typ = parse.Close
+	}
+
+	var err error
+
+	if markup.Options, err = options(n.Options); err != nil {
+		return parse.Markup{}, err
+	}
+
+	if markup.Attributes, err = attributes(n.Attributes); err != nil {
+		return parse.Markup{}, err
+	}
+
+	return markup, nil
+}
+
+func options(nodes []jsonNode) ([]parse.Option, error) {
+	if len(nodes) == 0 {
+		return nil, nil
+	}
+
+	r := make([]parse.Option, 0, len(nodes))
+
+	for _, n := range nodes {
+		v, err := n.optionValue()
+		if err != nil {
+			return nil, err
+		}
+
+		if v == nil {
+			return nil, fmt.Errorf(`option "%s": missing value`, n.Name)
+		}
+
+		r = append(r, parse.Option{Identifier: identifier(n.Name), Value: v})
+	}
+
+	return r, nil
+}
+
+func attributes(nodes []jsonNode) ([]parse.Attribute, error) {
+	if len(nodes) == 0 {
+		return nil, nil
+	}
+
+	r := make([]parse.Attribute, 0, len(nodes))
+
+	for _, n := range nodes {
+		v, err := n.optionValue()
+		if err != nil {
+			return nil, err
+		}
+
+		r = append(r, parse.Attribute{Identifier: identifier(n.Name), Value: v})
+	}
+
+	return r, nil
+}
+
+// optionValue returns the value of the option or attribute, nil if not set.
+func (n *jsonNode) optionValue() (parse.Value, error) { //nolint:ireturn
+	if len(n.Value) == 0 {
+		return nil, nil
+	}
+
+	var value jsonNode
+
+	if err := json.Unmarshal(n.Value, &value); err != nil {
+		return nil, fmt.Errorf(`"%s" value: %w`, n.Name, err)
+	}
+
+	return value.value()
+}
+
+func (n *jsonNode) value() (parse.Value, error) { //nolint:ireturn
+	switch n.Type {
+	default:
+		return nil, fmt.Errorf(`want value type "literal" or "variable", got "%s"`, n.Type)
+	case "variable":
+		return parse.Variable(n.Name), nil
+	case "literal":
+		return n.literal()
+	}
+}
+
+// literal returns the literal, the type of which is the shortest MF2 representation of the value.
+func (n *jsonNode) literal() (parse.Literal, error) { //nolint:ireturn
+	var s string
+
+	if err := json.Unmarshal(n.Value, &s); err != nil {
+		return nil, fmt.Errorf("literal value: %w", err)
+	}
+
+	if s == "" || strings.ContainsAny(s, "{}|\\") {
+		return parse.QuotedLiteral(s), nil
+	}
+
+	tree, err := parse.Parse("{" + s + "}")
+	if err != nil {
+		return parse.QuotedLiteral(s), nil //nolint:nilerr // not a name or number literal
+	}
+
+	if msg, ok := tree.Message.(parse.SimpleMessage); ok && len(msg) == 1 {
+		if expr, ok := msg[0].(parse.Expression); ok && expr.Annotation == nil && len(expr.Attributes) == 0 {
+			switch l := expr.Operand.(type) {
+			case parse.NameLiteral:
+				if string(l) == s {
+					return l, nil
+				}
+			case parse.NumberLiteral:
+				if l.String() == s {
+					return l, nil
+				}
+			}
+		}
+	}
+
+	return parse.QuotedLiteral(s), nil
+}
+
+// identifier splits the name to namespace and name, e.g. "ns:name".
+func identifier(s string) parse.Identifier {
+	if namespace, name, ok := strings.Cut(s, ":"); ok {
+		return parse.Identifier{Namespace: namespace, Name: name}
+	}
+
+	return parse.Identifier{Name: s}
+}
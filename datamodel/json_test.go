@@ -0,0 +1,130 @@
+package datamodel
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"go.expect.digital/mf2/parse"
+)
+
+// TestJSONCompat tests that the JSON data model is byte-compatible with
+// the compact JSON.stringify output of other implementations, e.g. ICU4X and messageformat.js.
+func TestJSONCompat(t *testing.T) {
+	t.Parallel()
+
+	f, err := os.ReadFile("testdata/compat.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var fixtures []struct {
+		Src  string          `json:"src"`
+		Data json.RawMessage `json:"data"`
+	}
+
+	if err := json.Unmarshal(f, &fixtures); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, fixture := range fixtures {
+		t.Run(fixture.Src, func(t *testing.T) {
+			t.Parallel()
+
+			var want bytes.Buffer
+
+			if err := json.Compact(&want, fixture.Data); err != nil {
+				t.Fatal(err)
+			}
+
+			tree, err := parse.Parse(fixture.Src)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := MarshalJSON(tree)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if want.String() != string(got) {
+				t.Errorf("want '%s', got '%s'", want.String(), got)
+			}
+
+			// unmarshal and marshal again
+			tree, err = UnmarshalJSON(fixture.Data)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got, _ = MarshalJSON(tree); want.String() != string(got) {
+				t.Errorf("want '%s', got '%s'", want.String(), got)
+			}
+
+			if _, err := parse.Parse(tree.String()); err != nil {
+				t.Errorf("want valid message '%s', got %s", tree, err)
+			}
+		})
+	}
+}
+
+func TestMarshalJSONOptions(t *testing.T) {
+	t.Parallel()
+
+	tree, err := parse.Parse("{ $x :number } { #br /}")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{
+  "type": "message",
+  "declarations": [],
+  "pattern": [
+    {
+      "type": "expression",
+      "arg": {
+        "type": "variable",
+        "name": "x"
+      },
+      "annotation": {
+        "type": "function",
+        "name": "number"
+      }
+    },
+    " ",
+    {
+      "type": "markup",
+      "kind": "standalone",
+      "name": "br"
+    }
+  ]
+}`
+
+	got, err := MarshalJSON(tree, WithIndent("  "), WithOmitEmpty())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want != string(got) {
+		t.Errorf("want '%s', got '%s'", want, got)
+	}
+}
+
+func TestUnmarshalJSONErrors(t *testing.T) {
+	t.Parallel()
+
+	for _, data := range []string{
+		`[]`,
+		`{"type": "unknown"}`,
+		`{"type": "message", "pattern": [1]}`,
+		`{"type": "message", "pattern": [{"type": "unknown"}]}`,
+		`{"type": "message", "pattern": [{"type": "markup", "kind": "unknown", "name": "b"}]}`,
+		`{"type": "message", "declarations": [{"type": "input", "name": "x", "value": {"type": "expression"}}], "pattern": []}`,
+		`{"type": "select", "selectors": [], "variants": [{"keys": [{"type": "unknown"}], "value": []}]}`,
+	} {
+		if _, err := UnmarshalJSON([]byte(data)); err == nil {
+			t.Errorf("%s: want error, got nil", data)
+		}
+	}
+}
@@ -0,0 +1,1128 @@
+// Package datamodel converts MF2 messages to and from the serialized forms of the [MF2 data model].
+//
+// [MF2 data model]: https://github.com/unicode-org/message-format-wg/blob/main/spec/data-model/README.md
+//
+//nolint:mnd // protobuf field numbers
+package datamodel
+
+import (
+	"errors"
+	"fmt"
+	"unicode/utf8"
+
+	"go.expect.digital/mf2/internal/protowire"
+	"go.expect.digital/mf2/parse"
+)
+
+// MarshalProto encodes the AST in the Protocol Buffers wire format of the "mf2.datamodel.v1.Message"
+// message defined in mf2.proto.
+func MarshalProto(tree parse.AST) ([]byte, error) {
+	b, err := marshalMessage(tree.Message)
+	if err != nil {
+		return nil, fmt.Errorf("marshal proto: %w", err)
+	}
+
+	return b, nil
+}
+
+// UnmarshalProto decodes the AST from the Protocol Buffers wire format of the "mf2.datamodel.v1.Message"
+// message defined in mf2.proto. Unknown fields are ignored.
+//
+// The AST is not validated, use [parse.Parse] on the string representation to validate untrusted data.
+func UnmarshalProto(b []byte) (parse.AST, error) {
+	msg, err := unmarshalMessage(b)
+	if err != nil {
+		return parse.AST{}, fmt.Errorf("unmarshal proto: %w", err)
+	}
+
+	return parse.AST{Message: msg}, nil
+}
+
+// -----------------------------------Marshal-----------------------------------
+
+func marshalMessage(msg parse.Message) ([]byte, error) {
+	switch m := msg.(type) {
+	default:
+		return nil, fmt.Errorf("unsupported message %T", msg)
+	case nil:
+		return nil, nil
+	case parse.SimpleMessage:
+		pattern, err := marshalPattern(m)
+		if err != nil {
+			return nil, err
+		}
+
+		return protowire.AppendBytes(nil, 2, pattern), nil
+	case parse.ComplexMessage:
+		var b []byte
+
+		for _, decl := range m.Declarations {
+			d, err := marshalDeclaration(decl)
+			if err != nil {
+				return nil, err
+			}
+
+			b = protowire.AppendBytes(b, 1, d)
+		}
+
+		switch body := m.ComplexBody.(type) {
+		default:
+			return nil, fmt.Errorf("unsupported complex body %T", body)
+		case parse.QuotedPattern:
+			pattern, err := marshalPattern(body)
+			if err != nil {
+				return nil, err
+			}
+
+			b = protowire.AppendBytes(b, 2, pattern)
+			b = protowire.AppendVarint(b, 4, 1)
+		case parse.Matcher:
+			matcher, err := marshalMatcher(body)
+			if err != nil {
+				return nil, err
+			}
+
+			b = protowire.AppendBytes(b, 3, matcher)
+		}
+
+		return b, nil
+	}
+}
+
+func marshalDeclaration(decl parse.Declaration) ([]byte, error) {
+	switch d := decl.(type) {
+	default:
+		return nil, fmt.Errorf("unsupported declaration %T", decl)
+	case parse.InputDeclaration:
+		expr, err := marshalExpression(parse.Expression(d))
+		if err != nil {
+			return nil, err
+		}
+
+		return protowire.AppendBytes(nil, 1, expr), nil
+	case parse.LocalDeclaration:
+		expr, err := marshalExpression(d.Expression)
+		if err != nil {
+			return nil, err
+		}
+
+		local := protowire.AppendNonEmpty(nil, 1, string(d.Variable))
+		local = protowire.AppendBytes(local, 2, expr)
+
+		return protowire.AppendBytes(nil, 2, local), nil
+	case parse.ReservedStatement:
+		statement := protowire.AppendNonEmpty(nil, 1, d.Keyword)
+
+		for _, part := range d.ReservedBody {
+			body, err := marshalReservedBody(part)
+			if err != nil {
+				return nil, err
+			}
+
+			statement = protowire.AppendBytes(statement, 2, body)
+		}
+
+		for _, e := range d.Expressions {
+			expr, err := marshalExpression(e)
+			if err != nil {
+				return nil, err
+			}
+
+			statement = protowire.AppendBytes(statement, 3, expr)
+		}
+
+		return protowire.AppendBytes(nil, 3, statement), nil
+	}
+}
+
+func marshalReservedBody(body parse.ReservedBody) ([]byte, error) {
+	switch v := body.(type) {
+	default:
+		return nil, fmt.Errorf("unsupported reserved body %T", body)
+	case parse.ReservedText:
+		return protowire.AppendString(nil, 1, string(v)), nil
+	case parse.QuotedLiteral:
+		return protowire.AppendString(nil, 2, string(v)), nil
+	}
+}
+
+func marshalMatcher(m parse.Matcher) ([]byte, error) {
+	var b []byte
+
+	for _, selector := range m.Selectors {
+		expr, err := marshalExpression(selector)
+		if err != nil {
+			return nil, err
+		}
+
+		b = protowire.AppendBytes(b, 1, expr)
+	}
+
+	for _, variant := range m.Variants {
+		var v []byte
+
+		for _, key := range variant.Keys {
+			k, err := marshalVariantKey(key)
+			if err != nil {
+				return nil, err
+			}
+
+			v = protowire.AppendBytes(v, 1, k)
+		}
+
+		pattern, err := marshalPattern(variant.QuotedPattern)
+		if err != nil {
+			return nil, err
+		}
+
+		v = protowire.AppendBytes(v, 2, pattern)
+		b = protowire.AppendBytes(b, 2, v)
+	}
+
+	return b, nil
+}
+
+func marshalVariantKey(key parse.VariantKey) ([]byte, error) {
+	switch k := key.(type) {
+	default:
+		return nil, fmt.Errorf("unsupported variant key %T", key)
+	case parse.CatchAllKey:
+		return protowire.AppendBytes(nil, 2, nil), nil
+	case parse.Literal:
+		literal, err := marshalLiteral(k)
+		if err != nil {
+			return nil, err
+		}
+
+		return protowire.AppendBytes(nil, 1, literal), nil
+	}
+}
+
+func marshalPattern(pattern []parse.PatternPart) ([]byte, error) {
+	var b []byte
+
+	for _, part := range pattern {
+		var p []byte
+
+		switch v := part.(type) {
+		default:
+			return nil, fmt.Errorf("unsupported pattern part %T", part)
+		case parse.Text:
+			p = protowire.AppendString(nil, 1, string(v))
+		case parse.Expression:
+			expr, err := marshalExpression(v)
+			if err != nil {
+				return nil, err
+			}
+
+			p = protowire.AppendBytes(nil, 2, expr)
+		case parse.Markup:
+			markup, err := marshalMarkup(v)
+			if err != nil {
+				return nil, err
+			}
+
+			p = protowire.AppendBytes(nil, 3, markup)
+		}
+
+		b = protowire.AppendBytes(b, 1, p)
+	}
+
+	return b, nil
+}
+
+func marshalExpression(expr parse.Expression) ([]byte, error) {
+	var b []byte
+
+	if expr.Operand != nil {
+		operand, err := marshalValue(expr.Operand)
+		if err != nil {
+			return nil, err
+		}
+
+		b = protowire.AppendBytes(b, 1, operand)
+	}
+
+	switch a := expr.Annotation.(type) {
+	default:
+		return nil, fmt.Errorf("unsupported annotation %T", a)
+	case nil:
+	case parse.Function:
+		function, err := marshalFunction(a)
+		if err != nil {
+			return nil, err
+		}
+
+		b = protowire.AppendBytes(b, 2, function)
+	case parse.PrivateUseAnnotation:
+		annotation, err := marshalUnsupportedAnnotation(a.Start, a.ReservedBody)
+		if err != nil {
+			return nil, err
+		}
+
+		b = protowire.AppendBytes(b, 3, annotation)
+	case parse.ReservedAnnotation:
+		annotation, err := marshalUnsupportedAnnotation(a.Start, a.ReservedBody)
+		if err != nil {
+			return nil, err
+		}
+
+		b = protowire.AppendBytes(b, 4, annotation)
+	}
+
+	for _, attribute := range expr.Attributes {
+		attr, err := marshalOption(attribute.Identifier, attribute.Value)
+		if err != nil {
+			return nil, err
+		}
+
+		b = protowire.AppendBytes(b, 5, attr)
+	}
+
+	return b, nil
+}
+
+func marshalValue(value parse.Value) ([]byte, error) {
+	switch v := value.(type) {
+	default:
+		return nil, fmt.Errorf("unsupported value %T", value)
+	case parse.Variable:
+		return protowire.AppendString(nil, 2, string(v)), nil
+	case parse.Literal:
+		literal, err := marshalLiteral(v)
+		if err != nil {
+			return nil, err
+		}
+
+		return protowire.AppendBytes(nil, 1, literal), nil
+	}
+}
+
+func marshalLiteral(literal parse.Literal) ([]byte, error) {
+	switch l := literal.(type) {
+	default:
+		return nil, fmt.Errorf("unsupported literal %T", literal)
+	case parse.QuotedLiteral:
+		return protowire.AppendString(nil, 1, string(l)), nil
+	case parse.NameLiteral:
+		return protowire.AppendString(nil, 2, string(l)), nil
+	case parse.NumberLiteral:
+		return protowire.AppendDouble(nil, 3, float64(l)), nil
+	}
+}
+
+func marshalFunction(f parse.Function) ([]byte, error) {
+	b := protowire.AppendBytes(nil, 1, marshalIdentifier(f.Identifier))
+
+	for _, option := range f.Options {
+		opt, err := marshalOption(option.Identifier, option.Value)
+		if err != nil {
+			return nil, err
+		}
+
+		b = protowire.AppendBytes(b, 2, opt)
+	}
+
+	return b, nil
+}
+
+func marshalUnsupportedAnnotation(start rune, body []parse.ReservedBody) ([]byte, error) {
+	b := protowire.AppendString(nil, 1, string(start))
+
+	for _, part := range body {
+		p, err := marshalReservedBody(part)
+		if err != nil {
+			return nil, err
+		}
+
+		b = protowire.AppendBytes(b, 2, p)
+	}
+
+	return b, nil
+}
+
+func marshalIdentifier(id parse.Identifier) []byte {
+	return protowire.AppendNonEmpty(protowire.AppendNonEmpty(nil, 1, id.Namespace), 2, id.Name)
+}
+
+// marshalOption marshals option or attribute, both have the same fields.
+func marshalOption(id parse.Identifier, value parse.Value) ([]byte, error) {
+	b := protowire.AppendBytes(nil, 1, marshalIdentifier(id))
+
+	if value == nil {
+		return b, nil
+	}
+
+	v, err := marshalValue(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return protowire.AppendBytes(b, 2, v), nil
+}
+
+func marshalMarkup(markup parse.Markup) ([]byte, error) {
+	var b []byte
+
+	if markup.Typ != parse.Unspecified {
+		b = protowire.AppendVarint(b, 1, uint64(markup.Typ)) //nolint:gosec
+	}
+
+	b = protowire.AppendBytes(b, 2, marshalIdentifier(markup.Identifier))
+
+	for _, option := range markup.Options {
+		opt, err := marshalOption(option.Identifier, option.Value)
+		if err != nil {
+			return nil, err
+		}
+
+		b = protowire.AppendBytes(b, 3, opt)
+	}
+
+	for _, attribute := range markup.Attributes {
+		attr, err := marshalOption(attribute.Identifier, attribute.Value)
+		if err != nil {
+			return nil, err
+		}
+
+		b = protowire.AppendBytes(b, 4, attr)
+	}
+
+	return b, nil
+}
+
+// ----------------------------------Unmarshal----------------------------------
+
+func unmarshalMessage(b []byte) (parse.Message, error) { //nolint:ireturn
+	var (
+		declarations []parse.Declaration
+		body         parse.ComplexBody
+		quoted       bool
+	)
+
+	err := protowire.Range(b, func(f protowire.Field) error {
+		switch f.Num {
+		case 1:
+			data, err := f.Bytes()
+			if err != nil {
+				return err
+			}
+
+			decl, err := unmarshalDeclaration(data)
+			if err != nil {
+				return err
+			}
+
+			declarations = append(declarations, decl)
+		case 2:
+			data, err := f.Bytes()
+			if err != nil {
+				return err
+			}
+
+			pattern, err := unmarshalPattern(data)
+			if err != nil {
+				return err
+			}
+
+			body = parse.QuotedPattern(pattern)
+		case 3:
+			data, err := f.Bytes()
+			if err != nil {
+				return err
+			}
+
+			matcher, err := unmarshalMatcher(data)
+			if err != nil {
+				return err
+			}
+
+			body = matcher
+		case 4:
+			v, err := f.Varint()
+			if err != nil {
+				return err
+			}
+
+			quoted = v != 0
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if body == nil {
+		if len(declarations) > 0 {
+			return nil, errors.New("missing complex body")
+		}
+
+		return nil, nil
+	}
+
+	if pattern, ok := body.(parse.QuotedPattern); ok && !quoted && len(declarations) == 0 {
+		return parse.SimpleMessage(pattern), nil
+	}
+
+	return parse.ComplexMessage{Declarations: declarations, ComplexBody: body}, nil
+}
+
+func unmarshalDeclaration(b []byte) (parse.Declaration, error) { //nolint:ireturn
+	var decl parse.Declaration
+
+	err := protowire.Range(b, func(f protowire.Field) error {
+		if f.Num > 3 { // unknown field
+			return nil
+		}
+
+		data, err := f.Bytes()
+		if err != nil {
+			return err
+		}
+
+		switch f.Num {
+		case 1:
+			expr, err := unmarshalExpression(data)
+			if err != nil {
+				return err
+			}
+
+			decl = parse.InputDeclaration(expr)
+		case 2:
+			local, err := unmarshalLocalDeclaration(data)
+			if err != nil {
+				return err
+			}
+
+			decl = local
+		case 3:
+			statement, err := unmarshalReservedStatement(data)
+			if err != nil {
+				return err
+			}
+
+			decl = statement
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if decl == nil {
+		return nil, errors.New("missing declaration")
+	}
+
+	return decl, nil
+}
+
+func unmarshalLocalDeclaration(b []byte) (parse.LocalDeclaration, error) {
+	var local parse.LocalDeclaration
+
+	err := protowire.Range(b, func(f protowire.Field) error {
+		switch f.Num {
+		case 1:
+			v, err := f.String()
+			if err != nil {
+				return err
+			}
+
+			local.Variable = parse.Variable(v)
+		case 2:
+			data, err := f.Bytes()
+			if err != nil {
+				return err
+			}
+
+			if local.Expression, err = unmarshalExpression(data); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	return local, err
+}
+
+func unmarshalReservedStatement(b []byte) (parse.ReservedStatement, error) {
+	var statement parse.ReservedStatement
+
+	err := protowire.Range(b, func(f protowire.Field) error {
+		switch f.Num {
+		case 1:
+			keyword, err := f.String()
+			if err != nil {
+				return err
+			}
+
+			statement.Keyword = keyword
+		case 2:
+			data, err := f.Bytes()
+			if err != nil {
+				return err
+			}
+
+			body, err := unmarshalReservedBody(data)
+			if err != nil {
+				return err
+			}
+
+			statement.ReservedBody = append(statement.ReservedBody, body)
+		case 3:
+			data, err := f.Bytes()
+			if err != nil {
+				return err
+			}
+
+			expr, err := unmarshalExpression(data)
+			if err != nil {
+				return err
+			}
+
+			statement.Expressions = append(statement.Expressions, expr)
+		}
+
+		return nil
+	})
+
+	return statement, err
+}
+
+func unmarshalReservedBody(b []byte) (parse.ReservedBody, error) { //nolint:ireturn
+	var body parse.ReservedBody
+
+	err := protowire.Range(b, func(f protowire.Field) error {
+		switch f.Num {
+		case 1:
+			s, err := f.String()
+			if err != nil {
+				return err
+			}
+
+			body = parse.ReservedText(s)
+		case 2:
+			s, err := f.String()
+			if err != nil {
+				return err
+			}
+
+			body = parse.QuotedLiteral(s)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if body == nil {
+		return nil, errors.New("missing reserved body")
+	}
+
+	return body, nil
+}
+
+func unmarshalMatcher(b []byte) (parse.Matcher, error) {
+	var matcher parse.Matcher
+
+	err := protowire.Range(b, func(f protowire.Field) error {
+		if f.Num > 2 { // unknown field
+			return nil
+		}
+
+		data, err := f.Bytes()
+		if err != nil {
+			return err
+		}
+
+		switch f.Num {
+		case 1:
+			expr, err := unmarshalExpression(data)
+			if err != nil {
+				return err
+			}
+
+			matcher.Selectors = append(matcher.Selectors, expr)
+		case 2:
+			variant, err := unmarshalVariant(data)
+			if err != nil {
+				return err
+			}
+
+			matcher.Variants = append(matcher.Variants, variant)
+		}
+
+		return nil
+	})
+
+	return matcher, err
+}
+
+func unmarshalVariant(b []byte) (parse.Variant, error) {
+	var variant parse.Variant
+
+	err := protowire.Range(b, func(f protowire.Field) error {
+		if f.Num > 2 { // unknown field
+			return nil
+		}
+
+		data, err := f.Bytes()
+		if err != nil {
+			return err
+		}
+
+		switch f.Num {
+		case 1:
+			key, err := unmarshalVariantKey(data)
+			if err != nil {
+				return err
+			}
+
+			variant.Keys = append(variant.Keys, key)
+		case 2:
+			pattern, err := unmarshalPattern(data)
+			if err != nil {
+				return err
+			}
+
+			variant.QuotedPattern = pattern
+		}
+
+		return nil
+	})
+
+	return variant, err
+}
+
+func unmarshalVariantKey(b []byte) (parse.VariantKey, error) { //nolint:ireturn
+	var key parse.VariantKey
+
+	err := protowire.Range(b, func(f protowire.Field) error {
+		if f.Num > 2 { // unknown field
+			return nil
+		}
+
+		data, err := f.Bytes()
+		if err != nil {
+			return err
+		}
+
+		switch f.Num {
+		case 1:
+			literal, err := unmarshalLiteral(data)
+			if err != nil {
+				return err
+			}
+
+			key = literal
+		case 2:
+			key = parse.CatchAllKey{}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if key == nil {
+		return nil, errors.New("missing variant key")
+	}
+
+	return key, nil
+}
+
+func unmarshalPattern(b []byte) ([]parse.PatternPart, error) {
+	var pattern []parse.PatternPart
+
+	err := protowire.Range(b, func(f protowire.Field) error {
+		if f.Num != 1 {
+			return nil
+		}
+
+		data, err := f.Bytes()
+		if err != nil {
+			return err
+		}
+
+		part, err := unmarshalPatternPart(data)
+		if err != nil {
+			return err
+		}
+
+		pattern = append(pattern, part)
+
+		return nil
+	})
+
+	return pattern, err
+}
+
+func unmarshalPatternPart(b []byte) (parse.PatternPart, error) { //nolint:ireturn
+	var part parse.PatternPart
+
+	err := protowire.Range(b, func(f protowire.Field) error {
+		if f.Num > 3 { // unknown field
+			return nil
+		}
+
+		data, err := f.Bytes()
+		if err != nil {
+			return err
+		}
+
+		switch f.Num {
+		case 1:
+			if !utf8.Valid(data) {
+				return errors.New("text: invalid UTF-8")
+			}
+
+			part = parse.Text(data)
+		case 2:
+			expr, err := unmarshalExpression(data)
+			if err != nil {
+				return err
+			}
+
+			part = expr
+		case 3:
+			markup, err := unmarshalMarkup(data)
+			if err != nil {
+				return err
+			}
+
+			part = markup
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if part == nil {
+		return nil, errors.New("missing pattern part")
+	}
+
+	return part, nil
+}
+
+func unmarshalExpression(b []byte) (parse.Expression, error) {
+	var expr parse.Expression
+
+	err := protowire.Range(b, func(f protowire.Field) error {
+		if f.Num > 5 { // unknown field
+			return nil
+		}
+
+		data, err := f.Bytes()
+		if err != nil {
+			return err
+		}
+
+		switch f.Num {
+		case 1:
+			if expr.Operand, err = unmarshalValue(data); err != nil {
+				return err
+			}
+		case 2:
+			if expr.Annotation, err = unmarshalFunction(data); err != nil {
+				return err
+			}
+		case 3:
+			annotation, err := unmarshalUnsupportedAnnotation(data)
+			if err != nil {
+				return err
+			}
+
+			expr.Annotation = annotation
+		case 4:
+			annotation, err := unmarshalUnsupportedAnnotation(data)
+			if err != nil {
+				return err
+			}
+
+			expr.Annotation = parse.ReservedAnnotation(annotation)
+		case 5:
+			id, value, err := unmarshalOption(data)
+			if err != nil {
+				return err
+			}
+
+			expr.Attributes = append(expr.Attributes, parse.Attribute{Identifier: id, Value: value})
+		}
+
+		return nil
+	})
+
+	return expr, err
+}
+
+func unmarshalValue(b []byte) (parse.Value, error) { //nolint:ireturn
+	var value parse.Value
+
+	err := protowire.Range(b, func(f protowire.Field) error {
+		switch f.Num {
+		case 1:
+			data, err := f.Bytes()
+			if err != nil {
+				return err
+			}
+
+			if value, err = unmarshalLiteral(data); err != nil {
+				return err
+			}
+		case 2:
+			s, err := f.String()
+			if err != nil {
+				return err
+			}
+
+			value = parse.Variable(s)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if value == nil {
+		return nil, errors.New("missing value")
+	}
+
+	return value, nil
+}
+
+func unmarshalLiteral(b []byte) (parse.Literal, error) { //nolint:ireturn
+	var literal parse.Literal
+
+	err := protowire.Range(b, func(f protowire.Field) error {
+		switch f.Num {
+		case 1:
+			s, err := f.String()
+			if err != nil {
+				return err
+			}
+
+			literal = parse.QuotedLiteral(s)
+		case 2:
+			s, err := f.String()
+			if err != nil {
+				return err
+			}
+
+			literal = parse.NameLiteral(s)
+		case 3:
+			v, err := f.Double()
+			if err != nil {
+				return err
+			}
+
+			literal = parse.NumberLiteral(v)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if literal == nil {
+		return nil, errors.New("missing literal")
+	}
+
+	return literal, nil
+}
+
+func unmarshalFunction(b []byte) (parse.Function, error) {
+	var function parse.Function
+
+	err := protowire.Range(b, func(f protowire.Field) error {
+		if f.Num > 2 { // unknown field
+			return nil
+		}
+
+		data, err := f.Bytes()
+		if err != nil {
+			return err
+		}
+
+		switch f.Num {
+		case 1:
+			if function.Identifier, err = unmarshalIdentifier(data); err != nil {
+				return err
+			}
+		case 2:
+			id, value, err := unmarshalOption(data)
+			if err != nil {
+				return err
+			}
+
+			if value == nil {
+				return fmt.Errorf(`option "%s": missing value`, id)
+			}
+
+			function.Options = append(function.Options, parse.Option{Identifier: id, Value: value})
+		}
+
+		return nil
+	})
+
+	return function, err
+}
+
+func unmarshalUnsupportedAnnotation(b []byte) (parse.PrivateUseAnnotation, error) {
+	var annotation parse.PrivateUseAnnotation
+
+	err := protowire.Range(b, func(f protowire.Field) error {
+		switch f.Num {
+		case 1:
+			s, err := f.String()
+			if err != nil {
+				return err
+			}
+
+			if utf8.RuneCountInString(s) != 1 {
+				return fmt.Errorf(`want single character annotation start, got "%s"`, s)
+			}
+
+			annotation.Start, _ = utf8.DecodeRuneInString(s)
+		case 2:
+			data, err := f.Bytes()
+			if err != nil {
+				return err
+			}
+
+			body, err := unmarshalReservedBody(data)
+			if err != nil {
+				return err
+			}
+
+			annotation.ReservedBody = append(annotation.ReservedBody, body)
+		}
+
+		return nil
+	})
+
+	return annotation, err
+}
+
+func unmarshalIdentifier(b []byte) (parse.Identifier, error) {
+	var id parse.Identifier
+
+	err := protowire.Range(b, func(f protowire.Field) error {
+		switch f.Num {
+		case 1:
+			s, err := f.String()
+			if err != nil {
+				return err
+			}
+
+			id.Namespace = s
+		case 2:
+			s, err := f.String()
+			if err != nil {
+				return err
+			}
+
+			id.Name = s
+		}
+
+		return nil
+	})
+
+	return id, err
+}
+
+// unmarshalOption unmarshals option or attribute, both have the same fields.
+func unmarshalOption(b []byte) (parse.Identifier, parse.Value, error) {
+	var (
+		id    parse.Identifier
+		value parse.Value
+	)
+
+	err := protowire.Range(b, func(f protowire.Field) error {
+		if f.Num > 2 { // unknown field
+			return nil
+		}
+
+		data, err := f.Bytes()
+		if err != nil {
+			return err
+		}
+
+		switch f.Num {
+		case 1:
+			id, err = unmarshalIdentifier(data)
+		case 2:
+			value, err = unmarshalValue(data)
+		}
+
+		return err
+	})
+
+	return id, value, err
+}
+
+func unmarshalMarkup(b []byte) (parse.Markup, error) {
+	var markup parse.Markup
+
+	err := protowire.Range(b, func(f protowire.Field) error {
+		if f.Num == 1 {
+			v, err := f.Varint()
+			if err != nil {
+				return err
+			}
+
+			if v > uint64(parse.SelfClose) {
+				return fmt.Errorf("unsupported markup kind %d", v)
+			}
+
+			markup.Typ = parse.MarkupType(v)
+
+			return nil
+		}
+
+		if f.Num > 4 { // unknown field
+			return nil
+		}
+
+		data, err := f.Bytes()
+		if err != nil {
+			return err
+		}
+
+		switch f.Num {
+		case 2:
+			if markup.Identifier, err = unmarshalIdentifier(data); err != nil {
+				return err
+			}
+		case 3:
+			id, value, err := unmarshalOption(data)
+			if err != nil {
+				return err
+			}
+
+			if value == nil {
+				return fmt.Errorf(`option "%s": missing value`, id)
+			}
+
+			markup.Options = append(markup.Options, parse.Option{Identifier: id, Value: value})
+		case 4:
+			id, value, err := unmarshalOption(data)
+			if err != nil {
+				return err
+			}
+
+			markup.Attributes = append(markup.Attributes, parse.Attribute{Identifier: id, Value: value})
+		}
+
+		return nil
+	})
+
+	return markup, err
+}
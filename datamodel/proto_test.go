@@ -0,0 +1,121 @@
+package datamodel
+
+import (
+	"bytes"
+	"testing"
+
+	"go.expect.digital/mf2/parse"
+)
+
+func TestProtoRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		name, text string
+	}{
+		{name: "empty", text: ""},
+		{name: "simple message", text: `Hello, { $name }! \{escaped\}`},
+		{name: "quoted pattern", text: "{{Hello}}"},
+		{name: "empty quoted pattern", text: "{{}}"},
+		{name: "literals", text: "{ |quoted \\| literal| } { name } { 1.5 } { -0 }"},
+		{name: "function with options", text: "{ $n :ns:number style=percent min=$min @locale=lv }"},
+		{name: "unsupported annotations", text: "{ ^private |quoted| text } { !reserved }"},
+		{name: "markup", text: "{ #b class=x @a } bold { /b @a } { #img src=|a.png| /}"},
+		{
+			name: "declarations",
+			text: ".input { $n :number }\n.local $m = { $n :integer }\n.reserved |body| { $m }\n{{{ $m }}}",
+		},
+		{
+			name: "matcher",
+			text: ".match { $n :number } { $s :string }\n1 a {{one a}}\none * {{one}}\n* |b c| {{b c}}\n* * {{other}}",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			tree, err := parse.Parse(test.text)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			b, err := MarshalProto(tree)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := UnmarshalProto(b)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if tree.String() != got.String() {
+				t.Errorf("want '%s', got '%s'", tree, got)
+			}
+		})
+	}
+}
+
+func TestMarshalProto(t *testing.T) {
+	t.Parallel()
+
+	// Message{pattern: Pattern{parts: [PatternPart{text: "Hi "}, PatternPart{expression: Expression{operand: Value{variable: "x"}}}]}}
+	want := []byte{
+		0x12, 0x10, // Message.pattern
+		0x0a, 0x05, 0x0a, 0x03, 'H', 'i', ' ', // Pattern.parts, PatternPart.text
+		0x0a, 0x07, 0x12, 0x05, 0x0a, 0x03, 0x12, 0x01, 'x', // Pattern.parts, PatternPart.expression, Expression.operand, Value.variable
+	}
+
+	tree, err := parse.Parse("Hi { $x }")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := MarshalProto(tree)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(want, got) {
+		t.Errorf("want '%x', got '%x'", want, got)
+	}
+}
+
+func TestUnmarshalProto(t *testing.T) {
+	t.Parallel()
+
+	t.Run("unknown fields", func(t *testing.T) {
+		t.Parallel()
+
+		// Message{pattern: Pattern{parts: [PatternPart{text: "Hi"}]}}, followed by unknown varint and fixed32 fields
+		b := []byte{0x12, 0x06, 0x0a, 0x04, 0x0a, 0x02, 'H', 'i', 0x78, 0x01, 0x7d, 0x01, 0x02, 0x03, 0x04}
+
+		tree, err := UnmarshalProto(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if tree.String() != "Hi" {
+			t.Errorf("want 'Hi', got '%s'", tree)
+		}
+	})
+
+	for _, test := range []struct {
+		name string
+		b    []byte
+	}{
+		{name: "truncated tag", b: []byte{0x80}},
+		{name: "truncated length", b: []byte{0x12, 0x05, 0x0a}},
+		{name: "wrong wire type", b: []byte{0x10, 0x01}},
+		{name: "invalid UTF-8", b: []byte{0x12, 0x05, 0x0a, 0x03, 0x0a, 0x01, 0xff}},
+		{name: "missing pattern part", b: []byte{0x12, 0x02, 0x0a, 0x00}},
+		{name: "missing complex body", b: []byte{0x0a, 0x02, 0x0a, 0x00}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			if _, err := UnmarshalProto(test.b); err == nil {
+				t.Error("want error, got nil")
+			}
+		})
+	}
+}
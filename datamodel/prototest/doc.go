@@ -0,0 +1,7 @@
+// Package prototest tests the Protocol Buffers encoding of [datamodel.MarshalProto] and [datamodel.UnmarshalProto]
+// against google.golang.org/protobuf and the schema mf2.proto. It is the separate module, the library does not
+// depend on the reference implementation.
+//
+// The testdata are the pairs of the MF2 message, name.mf2, and the expected "mf2.datamodel.v1.Message"
+// in the text format, name.txtpb.
+package prototest
@@ -0,0 +1,17 @@
+module go.expect.digital/mf2/datamodel/prototest
+
+go 1.22
+
+// the encoding is tested with the library of the same commit
+replace go.expect.digital/mf2 => ../..
+
+require (
+	github.com/bufbuild/protocompile v0.14.1
+	go.expect.digital/mf2 v0.0.0-00010101000000-000000000000
+	google.golang.org/protobuf v1.34.2
+)
+
+require (
+	golang.org/x/sync v0.8.0 // indirect
+	golang.org/x/text v0.16.0 // indirect
+)
@@ -0,0 +1,103 @@
+package prototest
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bufbuild/protocompile"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	"go.expect.digital/mf2/datamodel"
+	"go.expect.digital/mf2/parse"
+)
+
+// messageDescriptor returns the descriptor of "mf2.datamodel.v1.Message" compiled from mf2.proto.
+func messageDescriptor(t *testing.T) protoreflect.MessageDescriptor {
+	t.Helper()
+
+	compiler := protocompile.Compiler{
+		Resolver: &protocompile.SourceResolver{ImportPaths: []string{".."}},
+	}
+
+	files, err := compiler.Compile(context.Background(), "mf2.proto")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return files[0].Messages().ByName("Message")
+}
+
+func TestProto(t *testing.T) {
+	t.Parallel()
+
+	descriptor := messageDescriptor(t)
+
+	names, err := filepath.Glob(filepath.Join("testdata", "*.txtpb"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range names {
+		name = strings.TrimSuffix(filepath.Base(name), ".txtpb")
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			text, err := os.ReadFile(filepath.Join("testdata", name+".mf2"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			txtpb, err := os.ReadFile(filepath.Join("testdata", name+".txtpb"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			want := dynamicpb.NewMessage(descriptor)
+			if err = prototext.Unmarshal(txtpb, want); err != nil {
+				t.Fatal(err)
+			}
+
+			tree, err := parse.Parse(string(text))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// the reference decodes the encoded message, the unknown fields are not equal
+			b, err := datamodel.MarshalProto(tree)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := dynamicpb.NewMessage(descriptor)
+			if err = proto.Unmarshal(b, got); err != nil {
+				t.Fatal(err)
+			}
+
+			if !proto.Equal(want, got) {
+				t.Errorf("want '%s', got '%s'", prototext.Format(want), prototext.Format(got))
+			}
+
+			// the message encoded by the reference is decoded
+			b, err = proto.Marshal(want)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			decoded, err := datamodel.UnmarshalProto(b)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if tree.String() != decoded.String() {
+				t.Errorf("want '%s', got '%s'", tree, decoded)
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package datamodel
+
+import (
+	_ "embed"
+	"fmt"
+
+	"go.expect.digital/mf2/internal/jsonschema"
+	"go.expect.digital/mf2/parse"
+)
+
+// Schema is the JSON Schema document of the JSON data model representation, see [MarshalJSON].
+// Producers outside Go can validate their output with it before handing it to [UnmarshalJSON].
+//
+//go:embed schema.json
+var Schema string
+
+var schema = jsonschema.MustParse([]byte(Schema))
+
+// Validate validates the JSON representation of the data model against [Schema], decodes it
+// and parses the string representation of the message, e.g. to reject invalid variable names.
+// All schema violations are reported at once with the JSON Pointer of the invalid value.
+func Validate(data []byte) error {
+	errorf := func(err error) error {
+		return fmt.Errorf("validate data model: %w", err)
+	}
+
+	if err := schema.Validate(data); err != nil {
+		return errorf(err)
+	}
+
+	tree, err := UnmarshalJSON(data)
+	if err != nil {
+		return errorf(err)
+	}
+
+	if _, err := parse.Parse(tree.String()); err != nil {
+		return errorf(err)
+	}
+
+	return nil
+}
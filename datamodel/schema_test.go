@@ -0,0 +1,83 @@
+package datamodel
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"go.expect.digital/mf2/parse"
+)
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	f, err := os.ReadFile("testdata/compat.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var fixtures []struct {
+		Src  string          `json:"src"`
+		Data json.RawMessage `json:"data"`
+	}
+
+	if err := json.Unmarshal(f, &fixtures); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, fixture := range fixtures {
+		if err := Validate(fixture.Data); err != nil {
+			t.Errorf("%s: %s", fixture.Src, err)
+		}
+
+		tree, err := parse.Parse(fixture.Src)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		b, err := MarshalJSON(tree, WithOmitEmpty())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := Validate(b); err != nil {
+			t.Errorf("%s: omit empty: %s", fixture.Src, err)
+		}
+	}
+}
+
+func TestValidateErrors(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		name, data, want string
+	}{
+		{name: "syntax", data: `{`, want: "unexpected EOF"},
+		{name: "type", data: `{"type":"messages","declarations":[],"pattern":[]}`, want: "want exactly one schema of oneOf to match"},
+		{
+			name: "unknown property",
+			data: `{"type":"message","declarations":[],"pattern":[{"type":"expression","args":{"type":"variable","name":"x"}}]}`,
+			want: "want exactly one schema of oneOf to match",
+		},
+		{
+			name: "no variants",
+			data: `{"type":"select","declarations":[],"selectors":[{"type":"expression"}],"variants":[]}`,
+			want: "want exactly one schema of oneOf to match",
+		},
+		{
+			name: "invalid name",
+			data: `{"type":"message","declarations":[],"pattern":[{"type":"expression","arg":{"type":"variable","name":"a b"}}]}`,
+			want: "validate data model",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := Validate([]byte(test.data))
+			if err == nil || !strings.Contains(err.Error(), test.want) {
+				t.Errorf("want error containing '%s', got '%v'", test.want, err)
+			}
+		})
+	}
+}
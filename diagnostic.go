@@ -0,0 +1,138 @@
+package mf2
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// Severity is the severity of the [Diagnostic], the values are the same as in the Language Server Protocol.
+type Severity int
+
+const (
+	// SeverityError reports the message that cannot be used, e.g. a syntax error.
+	SeverityError Severity = iota + 1
+	// SeverityWarning reports the message that works, but likely not as intended.
+	SeverityWarning
+	// SeverityInformation reports a fact about the message.
+	SeverityInformation
+	// SeverityHint suggests an improvement of the message.
+	SeverityHint
+)
+
+// String returns the name of the severity.
+func (s Severity) String() string {
+	switch s {
+	default:
+		return "unknown"
+	case SeverityError:
+		return "error"
+	case SeverityWarning:
+		return "warning"
+	case SeverityInformation:
+		return "information"
+	case SeverityHint:
+		return "hint"
+	}
+}
+
+// Span is the range of the message, the byte offsets from Start to End exclusive.
+type Span struct {
+	Start, End int
+}
+
+// RelatedInfo is the location related to the [Diagnostic], e.g. the first declaration of a duplicate.
+type RelatedInfo struct {
+	Message string
+	Span    Span
+}
+
+// Diagnostic is the problem of the message reported by the parser, validator, linter or any other tool.
+type Diagnostic struct {
+	// Code identifies the kind of the problem, e.g. "duplicate-declaration", see [ErrorCode].
+	Code    string
+	Message string
+	Related []RelatedInfo
+	// Span is the range of the problem in the message.
+	Span     Span
+	Severity Severity
+}
+
+// String returns the diagnostic as "severity [code] start-end: message".
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("%s [%s] %d-%d: %s", d.Severity, d.Code, d.Span.Start, d.Span.End, d.Message)
+}
+
+// MarshalJSON encodes the diagnostic as the flat JSON object with the severity name, e.g.
+// {"severity":"error","code":"syntax-error","message":"...","start":0,"end":5}.
+func (d Diagnostic) MarshalJSON() ([]byte, error) {
+	type related struct {
+		Message string `json:"message"`
+		Start   int    `json:"start"`
+		End     int    `json:"end"`
+	}
+
+	v := struct {
+		Severity string    `json:"severity"`
+		Code     string    `json:"code"`
+		Message  string    `json:"message"`
+		Start    int       `json:"start"`
+		End      int       `json:"end"`
+		Related  []related `json:"related,omitempty"`
+	}{
+		Severity: d.Severity.String(),
+		Code:     d.Code,
+		Message:  d.Message,
+		Start:    d.Span.Start,
+		End:      d.Span.End,
+	}
+
+	for _, r := range d.Related {
+		v.Related = append(v.Related, related{Message: r.Message, Start: r.Span.Start, End: r.Span.End})
+	}
+
+	return json.Marshal(v) //nolint:wrapcheck
+}
+
+// NewDiagnostic returns the error diagnostic of the err, the code is [ErrorCode] of the err.
+func NewDiagnostic(err error, span Span) Diagnostic {
+	return Diagnostic{
+		Severity: SeverityError,
+		Code:     ErrorCode(err),
+		Message:  err.Error(),
+		Span:     span,
+	}
+}
+
+// errorCodes are the codes of the MF2 errors, the error names of the specification.
+var errorCodes = []struct {
+	err  error
+	code string
+}{
+	// the data model and resolution errors are wrapped in the syntax error, the specific errors go first
+	{ErrDuplicateDeclaration, "duplicate-declaration"},
+	{ErrDuplicateOptionName, "duplicate-option-name"},
+	{ErrMissingFallbackVariant, "missing-fallback-variant"},
+	{ErrMissingSelectorAnnotation, "missing-selector-annotation"},
+	{ErrVariantKeyMismatch, "variant-key-mismatch"},
+	{ErrUnknownFunction, "unknown-function"},
+	{ErrUnresolvedVariable, "unresolved-variable"},
+	{ErrUnsupportedExpression, "unsupported-expression"},
+	{ErrUnsupportedStatement, "unsupported-statement"},
+	{ErrBadOperand, "bad-operand"},
+	{ErrBadOption, "bad-option"},
+	{ErrBadVariantKey, "bad-variant-key"},
+	{ErrSyntax, "syntax-error"},
+}
+
+// ErrorCode returns the code of the MF2 error wrapped in the err, e.g. "syntax-error" for [ErrSyntax],
+// or "error" for other errors.
+func ErrorCode(err error) string {
+	for _, e := range errorCodes {
+		if errors.Is(err, e.err) {
+			return e.code
+		}
+	}
+
+	return "error"
+}
@@ -0,0 +1,70 @@
+package mf2_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"go.expect.digital/mf2"
+	"go.expect.digital/mf2/parse"
+)
+
+func TestDiagnostic(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		in   string
+		want string // diagnostic string or empty
+	}{
+		{in: "Hello, { $name }!"},
+		{in: "{ $x", want: "error [syntax-error] 0-4: "},
+		{in: ".input {$x} .input {$x} {{}}", want: "error [duplicate-declaration] 0-28: "},
+		{in: ".match {$x :string} a {{a}}", want: "error [missing-fallback-variant] 0-27: "},
+	} {
+		_, diagnostics := parse.Diagnose(test.in)
+
+		switch {
+		case test.want == "" && len(diagnostics) > 0:
+			t.Errorf("%s: want no diagnostics, got %v", test.in, diagnostics)
+		case test.want == "":
+		case len(diagnostics) != 1:
+			t.Errorf("%s: want 1 diagnostic, got %v", test.in, diagnostics)
+		case diagnostics[0].String() != test.want+diagnostics[0].Message:
+			t.Errorf("%s: want '%s', got '%s'", test.in, test.want+diagnostics[0].Message, diagnostics[0])
+		}
+	}
+
+	if got := mf2.ErrorCode(fmt.Errorf("resolve: %w", mf2.ErrBadOption)); got != "bad-option" {
+		t.Errorf("want 'bad-option', got '%s'", got)
+	}
+
+	if got := mf2.ErrorCode(errors.New("custom")); got != "error" {
+		t.Errorf("want 'error', got '%s'", got)
+	}
+}
+
+func TestDiagnosticJSON(t *testing.T) {
+	t.Parallel()
+
+	d := mf2.Diagnostic{
+		Severity: mf2.SeverityWarning,
+		Code:     "duplicate-declaration",
+		Message:  "declared twice",
+		Span:     mf2.Span{Start: 12, End: 20},
+		Related:  []mf2.RelatedInfo{{Message: "first declaration", Span: mf2.Span{Start: 0, End: 8}}},
+	}
+
+	b, err := json.Marshal([]mf2.Diagnostic{d, {Severity: mf2.SeverityError, Code: "syntax-error"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `[{"severity":"warning","code":"duplicate-declaration","message":"declared twice","start":12,"end":20,` +
+		`"related":[{"message":"first declaration","start":0,"end":8}]},` +
+		`{"severity":"error","code":"syntax-error","message":"","start":0,"end":0}]`
+
+	if got := string(b); want != got {
+		t.Errorf("want '%s', got '%s'", want, got)
+	}
+}
@@ -0,0 +1,48 @@
+package mf2_test
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"testing"
+)
+
+// differential is the conformance JSON file with the output of another implementation.
+//
+// Generate the file with messageformat.js and run the differential test:
+//
+//	node testdata/messageformat.mjs .message-format-wg/test/tests > /tmp/messageformat.json
+//	go test -run TestDifferential -differential /tmp/messageformat.json
+var differential = flag.String("differential", "",
+	"conformance JSON `file` with the expected output of another implementation, e.g. messageformat.js")
+
+// TestDifferential compares the output with another implementation.
+// The test is skipped unless the -differential flag is set.
+func TestDifferential(t *testing.T) {
+	t.Parallel()
+
+	if *differential == "" {
+		t.Skip("differential conformance file is not set")
+	}
+
+	f, err := os.Open(*differential)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer f.Close()
+
+	var tests Tests
+
+	if err := json.NewDecoder(f).Decode(&tests); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range tests.Tests {
+		t.Run(test.Src, func(t *testing.T) {
+			t.Parallel()
+
+			run(t, test.Apply(tests.DefaultTestProperties))
+		})
+	}
+}
@@ -0,0 +1,13 @@
+package mf2
+
+import "golang.org/x/text/language"
+
+// Formattable is implemented by the operand values that format themselves in the placeholders
+// without a function, e.g. "{$price}" of the domain type Money, instead of registering
+// a function per type. The options are the request-scoped defaults of the function options,
+// e.g. "currency" or "measurementSystem", nil if none.
+//
+// The error is reported as [ErrBadOperand], and the placeholder is formatted as the fallback, e.g. "{$price}".
+type Formattable interface {
+	FormatMF2(locale language.Tag, options map[string]any) (string, error)
+}
@@ -0,0 +1,90 @@
+/*
+Package funcmap adapts MF2 messages for use in text/template and html/template.
+
+Example:
+
+	c := catalog.New(language.English)
+	c.Set("greeting", "Hello, { $name }!")
+
+	t := template.Must(template.New("page").Funcs(funcmap.New(c)).Parse(`{{ mf2 "greeting" "name" .Name }}`))
+
+The message arguments are passed either as a single map[string]any
+or as name and value pairs:
+
+	{{ mf2 "greeting" .Args }}
+	{{ mf2 "greeting" "name" .Name "count" .Count }}
+
+When used in html/template, the formatted message is escaped as any other string.
+*/
+package funcmap
+
+import (
+	"fmt"
+)
+
+// Source formats messages by ID, e.g. *catalog.Catalog.
+type Source interface {
+	Sprint(id string, input map[string]any) (string, error)
+}
+
+// FuncName is the name of the template function formatting MF2 messages.
+const FuncName = "mf2"
+
+// New returns a function map with the "mf2" function formatting messages from the source.
+// The function map can be passed to both text/template and html/template.
+func New(source Source) map[string]any {
+	return map[string]any{FuncName: Func(source)}
+}
+
+// Func returns the template function formatting messages from the source.
+func Func(source Source) func(id string, args ...any) (string, error) {
+	return func(id string, args ...any) (string, error) {
+		errorf := func(format string, args ...any) (string, error) {
+			return "", fmt.Errorf(`format message "%s": `+format, append([]any{id}, args...)...)
+		}
+
+		input, err := toInput(args)
+		if err != nil {
+			return errorf("%w", err)
+		}
+
+		s, err := source.Sprint(id, input)
+		if err != nil {
+			return errorf("%w", err)
+		}
+
+		return s, nil
+	}
+}
+
+// toInput converts template function arguments to the message input.
+func toInput(args []any) (map[string]any, error) {
+	switch len(args) {
+	case 0:
+		return nil, nil //nolint:nilnil
+	case 1:
+		input, ok := args[0].(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("want map[string]any argument, got %T", args[0])
+		}
+
+		return input, nil
+	}
+
+	if len(args)%2 != 0 {
+		return nil, fmt.Errorf("want name and value pairs, got %d arguments", len(args))
+	}
+
+	input := make(map[string]any, len(args)/2) //nolint:mnd
+
+	for i := 0; i < len(args); i += 2 {
+		name, ok := args[i].(string)
+		if !ok {
+			return nil, fmt.Errorf("want string argument name at %d, got %T", i, args[i])
+		}
+
+		input[name] = args[i+1]
+	}
+
+	return input, nil
+}
@@ -0,0 +1,88 @@
+package funcmap
+
+import (
+	htmltemplate "html/template"
+	"strings"
+	"testing"
+	texttemplate "text/template"
+
+	"golang.org/x/text/language"
+
+	"go.expect.digital/mf2/catalog"
+)
+
+func TestFuncMap(t *testing.T) {
+	t.Parallel()
+
+	c := catalog.New(language.English)
+	c.Set("greeting", "Hello, { $name }!")
+	c.Set("apples", ".match { $count :number } one {{one apple}} * {{{ $count } apples}}")
+
+	data := map[string]any{
+		"Name": "<World>",
+		"Args": map[string]any{"count": 3},
+	}
+
+	for _, test := range []struct {
+		name, text, want string
+	}{
+		{
+			name: "name and value pairs",
+			text: `{{ mf2 "greeting" "name" .Name }}`,
+			want: "Hello, <World>!",
+		},
+		{
+			name: "map",
+			text: `{{ mf2 "apples" .Args }}`,
+			want: "3 apples",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			tmpl := texttemplate.Must(texttemplate.New("").Funcs(New(c)).Parse(test.text))
+
+			var sb strings.Builder
+
+			if err := tmpl.Execute(&sb, data); err != nil {
+				t.Fatal(err)
+			}
+
+			if test.want != sb.String() {
+				t.Errorf("want '%s', got '%s'", test.want, sb.String())
+			}
+		})
+	}
+
+	t.Run("html/template escapes output", func(t *testing.T) {
+		t.Parallel()
+
+		tmpl := htmltemplate.Must(htmltemplate.New("").Funcs(New(c)).Parse(`<p>{{ mf2 "greeting" "name" .Name }}</p>`))
+
+		var sb strings.Builder
+
+		if err := tmpl.Execute(&sb, data); err != nil {
+			t.Fatal(err)
+		}
+
+		if want := "<p>Hello, &lt;World&gt;!</p>"; want != sb.String() {
+			t.Errorf("want '%s', got '%s'", want, sb.String())
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		t.Parallel()
+
+		for _, text := range []string{
+			`{{ mf2 "missing" }}`,
+			`{{ mf2 "greeting" "name" }}`,
+			`{{ mf2 "greeting" 1 2 }}`,
+		} {
+			tmpl := texttemplate.Must(texttemplate.New("").Funcs(New(c)).Parse(text))
+
+			if err := tmpl.Execute(new(strings.Builder), data); err == nil {
+				t.Errorf("%s: want error, got nil", text)
+			}
+		}
+	})
+}
@@ -0,0 +1,163 @@
+package goi18n
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"text/template/parse"
+
+	"go.expect.digital/mf2"
+	ast "go.expect.digital/mf2/parse"
+)
+
+// PluralCountVar is the name of the variable holding the plural count,
+// as in go-i18n templates "{{.PluralCount}}".
+const PluralCountVar = "PluralCount"
+
+// Convert converts go-i18n message to MF2 message.
+//
+// Template actions referencing a field "{{.Name}}" are converted to variable expressions "{ $Name }".
+// Other template actions (pipelines, functions, conditions) are not supported.
+//
+// Messages with plural forms are converted to a matcher on "$PluralCount :number":
+//
+//	.input { $PluralCount :number }
+//	.match { $PluralCount }
+//	one {{{ $PluralCount } apple}}
+//	* {{{ $PluralCount } apples}}
+func Convert(msg *Message) (ast.AST, error) {
+	errorf := func(format string, args ...any) (ast.AST, error) {
+		return ast.AST{}, fmt.Errorf(`convert go-i18n message "%s": `+format, append([]any{msg.ID}, args...)...)
+	}
+
+	forms := msg.pluralForms()
+
+	switch len(forms) {
+	case 0:
+		return ast.AST{}, nil
+	case 1:
+		if forms[0].key != "other" {
+			break
+		}
+
+		pattern, err := convertTemplate(forms[0].text, msg.LeftDelim, msg.RightDelim)
+		if err != nil {
+			return errorf("%w", err)
+		}
+
+		return ast.AST{Message: patternMessage(pattern)}, nil
+	}
+
+	if forms[len(forms)-1].key != "other" {
+		return errorf(`%w: missing plural form "other"`, mf2.ErrMissingFallbackVariant)
+	}
+
+	pluralCount := ast.Expression{Operand: ast.Variable(PluralCountVar)}
+	matcher := ast.Matcher{Selectors: []ast.Expression{pluralCount}}
+
+	for _, form := range forms {
+		pattern, err := convertTemplate(form.text, msg.LeftDelim, msg.RightDelim)
+		if err != nil {
+			return errorf("plural form %s: %w", form.key, err)
+		}
+
+		var key ast.VariantKey = ast.NameLiteral(form.key)
+		if form.key == "other" {
+			key = ast.CatchAllKey{}
+		}
+
+		matcher.Variants = append(matcher.Variants, ast.Variant{Keys: []ast.VariantKey{key}, QuotedPattern: pattern})
+	}
+
+	input := ast.InputDeclaration{
+		Operand:    ast.Variable(PluralCountVar),
+		Annotation: ast.Function{Identifier: ast.Identifier{Name: "number"}},
+	}
+
+	return ast.AST{
+		Message: ast.ComplexMessage{
+			Declarations: []ast.Declaration{input},
+			ComplexBody:  matcher,
+		},
+	}, nil
+}
+
+// patternMessage returns simple message, unless the pattern would be lexed as complex message.
+func patternMessage(pattern ast.QuotedPattern) ast.Message { //nolint:ireturn
+	if len(pattern) > 0 {
+		if text, ok := pattern[0].(ast.Text); ok && strings.HasPrefix(string(text), ".") {
+			return ast.ComplexMessage{ComplexBody: pattern}
+		}
+	}
+
+	return ast.SimpleMessage(pattern)
+}
+
+// convertTemplate converts go-i18n text/template to MF2 pattern.
+func convertTemplate(text, leftDelim, rightDelim string) (ast.QuotedPattern, error) {
+	if leftDelim == "" {
+		leftDelim = "{{"
+	}
+
+	if rightDelim == "" {
+		rightDelim = "}}"
+	}
+
+	// plain text, no template actions
+	if !strings.Contains(text, leftDelim) {
+		return ast.QuotedPattern{ast.Text(text)}, nil
+	}
+
+	// functions are not supported, skip the check to report them as unsupported
+	tree := parse.New("message")
+	tree.Mode = parse.SkipFuncCheck
+	trees := make(map[string]*parse.Tree)
+
+	if _, err := tree.Parse(text, leftDelim, rightDelim, trees); err != nil {
+		return nil, fmt.Errorf("%w: %w", mf2.ErrSyntax, err)
+	}
+
+	if len(trees) > 1 {
+		return nil, fmt.Errorf("%w: template definitions are not supported", mf2.ErrUnsupportedExpression)
+	}
+
+	pattern := make(ast.QuotedPattern, 0, len(tree.Root.Nodes))
+
+	for _, node := range tree.Root.Nodes {
+		switch n := node.(type) {
+		default:
+			return nil, fmt.Errorf(`%w: "%s"`, mf2.ErrUnsupportedExpression, node)
+		case *parse.TextNode:
+			pattern = append(pattern, ast.Text(n.Text))
+		case *parse.ActionNode:
+			name, err := fieldName(n)
+			if err != nil {
+				return nil, err
+			}
+
+			pattern = append(pattern, ast.Expression{Operand: ast.Variable(name)})
+		}
+	}
+
+	return pattern, nil
+}
+
+// fieldName returns the field name of the action "{{.Name}}".
+func fieldName(action *parse.ActionNode) (string, error) {
+	unsupported := fmt.Errorf(`%w: want field "{{.Name}}", got "%s"`, mf2.ErrUnsupportedExpression, action)
+
+	if len(action.Pipe.Decl) > 0 || len(action.Pipe.Cmds) != 1 || len(action.Pipe.Cmds[0].Args) != 1 {
+		return "", unsupported
+	}
+
+	field, ok := action.Pipe.Cmds[0].Args[0].(*parse.FieldNode)
+	if !ok || len(field.Ident) != 1 {
+		return "", unsupported
+	}
+
+	if field.Ident[0] == "" {
+		return "", errors.New("empty field name")
+	}
+
+	return field.Ident[0], nil
+}
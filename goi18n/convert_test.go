@@ -0,0 +1,85 @@
+package goi18n
+
+import (
+	"errors"
+	"testing"
+
+	"go.expect.digital/mf2"
+)
+
+func TestConvert(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		name string
+		msg  Message
+		want string
+	}{
+		{
+			name: "empty",
+			msg:  Message{ID: "empty"},
+			want: "",
+		},
+		{
+			name: "text",
+			msg:  Message{ID: "text", Other: "Hello, {World}!"},
+			want: `Hello, \{World\}!`,
+		},
+		{
+			name: "text starting with dot",
+			msg:  Message{ID: "dot", Other: ".hidden {{.Name}}"},
+			want: "{{.hidden { $Name }}}",
+		},
+		{
+			name: "variables",
+			msg:  Message{ID: "vars", Other: "{{.Name}} has {{ .Count }} cats"},
+			want: "{ $Name } has { $Count } cats",
+		},
+		{
+			name: "custom delimiters",
+			msg:  Message{ID: "delims", Other: "<<.Name>> {{.Name}}", LeftDelim: "<<", RightDelim: ">>"},
+			want: `{ $Name } \{\{.Name\}\}`,
+		},
+		{
+			name: "plural forms",
+			msg:  Message{ID: "cats", One: "{{.Name}} has one cat", Other: "{{.Name}} has {{.PluralCount}} cats"},
+			want: ".input { $PluralCount :number }\n" +
+				".match { $PluralCount }\n" +
+				"one {{{ $Name } has one cat}}\n" +
+				"* {{{ $Name } has { $PluralCount } cats}}",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			tree, err := Convert(&test.msg)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := tree.String(); test.want != got {
+				t.Errorf("want '%s', got '%s'", test.want, got)
+			}
+		})
+	}
+}
+
+func TestConvertErrors(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		msg     Message
+		wantErr error
+	}{
+		{msg: Message{One: "one cat"}, wantErr: mf2.ErrMissingFallbackVariant},
+		{msg: Message{Other: "{{.Name"}, wantErr: mf2.ErrSyntax},
+		{msg: Message{Other: "{{.Name | printf}}"}, wantErr: mf2.ErrUnsupportedExpression},
+		{msg: Message{Other: "{{.User.Name}}"}, wantErr: mf2.ErrUnsupportedExpression},
+		{msg: Message{Other: "{{if .Name}}x{{end}}"}, wantErr: mf2.ErrUnsupportedExpression},
+		{msg: Message{One: "{{len .}}", Other: "cats"}, wantErr: mf2.ErrUnsupportedExpression},
+	} {
+		if _, err := Convert(&test.msg); !errors.Is(err, test.wantErr) {
+			t.Errorf("%+v: want '%s', got '%s'", test.msg, test.wantErr, err)
+		}
+	}
+}
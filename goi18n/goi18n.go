@@ -0,0 +1,394 @@
+/*
+Package goi18n is an interop layer for [go-i18n] users.
+
+It reads go-i18n message files, converts go-i18n messages (including plural forms)
+to MF2 messages and provides [Bundle] and [Localizer] with the same shape as go-i18n,
+so call sites can switch to MF2 by replacing the import:
+
+	bundle := goi18n.NewBundle(language.English)
+	bundle.MustLoadMessageFile("active.es.json")
+
+	localizer := goi18n.NewLocalizer(bundle, "es")
+	s, err := localizer.Localize(&goi18n.LocalizeConfig{
+		MessageID:    "PersonCats",
+		TemplateData: map[string]any{"Name": "Nick"},
+		PluralCount:  2,
+	})
+
+Only JSON message files are supported out of the box. Other formats are registered
+with [Bundle.RegisterUnmarshalFunc], for example toml.Unmarshal.
+
+See [Convert] for the subset of go-i18n templates that can be converted.
+
+[go-i18n]: https://github.com/nicksnyder/go-i18n
+*/
+package goi18n
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"golang.org/x/text/language"
+
+	"go.expect.digital/mf2/catalog"
+	"go.expect.digital/mf2/internal/mapkeys"
+	"go.expect.digital/mf2/template"
+)
+
+// Message is a go-i18n message with plural forms.
+type Message struct {
+	// ID uniquely identifies the message.
+	ID string
+	// Hash uniquely identifies the content of the message that this message was translated from.
+	Hash string
+	// Description describes the message to give additional context to translators.
+	Description string
+	// LeftDelim is the left Go template delimiter, "{{" by default.
+	LeftDelim string
+	// RightDelim is the right Go template delimiter, "}}" by default.
+	RightDelim string
+
+	// Plural forms of the message.
+
+	Zero  string
+	One   string
+	Two   string
+	Few   string
+	Many  string
+	Other string
+}
+
+type pluralForm struct {
+	key, text string
+}
+
+// pluralForms returns the non-empty plural forms, "other" is always last.
+func (m *Message) pluralForms() []pluralForm {
+	var forms []pluralForm
+
+	for _, form := range []pluralForm{
+		{"zero", m.Zero},
+		{"one", m.One},
+		{"two", m.Two},
+		{"few", m.Few},
+		{"many", m.Many},
+		{"other", m.Other},
+	} {
+		if form.text != "" {
+			forms = append(forms, form)
+		}
+	}
+
+	return forms
+}
+
+// UnmarshalFunc unmarshals data in the message file format, e.g. json.Unmarshal.
+type UnmarshalFunc func(data []byte, v any) error
+
+// MessageFile is a parsed go-i18n message file.
+type MessageFile struct {
+	Path     string
+	Format   string
+	Messages []*Message
+	Tag      language.Tag
+}
+
+// Bundle stores converted messages of all languages.
+//
+// Bundle is safe for concurrent use.
+type Bundle struct {
+	catalogs        map[language.Tag]*catalog.Catalog
+	unmarshalFuncs  map[string]UnmarshalFunc
+	matcher         language.Matcher
+	options         []template.Option
+	tags            []language.Tag
+	defaultLanguage language.Tag
+	mu              sync.RWMutex
+}
+
+// NewBundle returns a new bundle. The default language is used when no other language matches.
+// The options are applied to every compiled template.
+func NewBundle(defaultLanguage language.Tag, options ...template.Option) *Bundle {
+	b := &Bundle{
+		defaultLanguage: defaultLanguage,
+		options:         options,
+		catalogs:        make(map[language.Tag]*catalog.Catalog),
+		unmarshalFuncs:  map[string]UnmarshalFunc{"json": json.Unmarshal},
+		tags:            []language.Tag{defaultLanguage},
+	}
+
+	b.matcher = language.NewMatcher(b.tags)
+
+	return b
+}
+
+// RegisterUnmarshalFunc registers the unmarshal function for the message file format,
+// e.g. "toml" or "yaml".
+func (b *Bundle) RegisterUnmarshalFunc(format string, unmarshalFunc UnmarshalFunc) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.unmarshalFuncs[format] = unmarshalFunc
+}
+
+// LoadMessageFile loads and parses the message file.
+// The language and the format are derived from the file name, e.g. "active.en-US.json".
+func (b *Bundle) LoadMessageFile(path string) (*MessageFile, error) {
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("load message file: %w", err)
+	}
+
+	return b.ParseMessageFileBytes(buf, path)
+}
+
+// MustLoadMessageFile is similar to [Bundle.LoadMessageFile] except it panics if an error happens.
+func (b *Bundle) MustLoadMessageFile(path string) {
+	if _, err := b.LoadMessageFile(path); err != nil {
+		panic(err)
+	}
+}
+
+// ParseMessageFileBytes parses the bytes of the message file and adds the messages to the bundle.
+// The path is used to derive the language and the format of the file.
+func (b *Bundle) ParseMessageFileBytes(buf []byte, path string) (*MessageFile, error) {
+	errorf := func(format string, args ...any) (*MessageFile, error) {
+		return nil, fmt.Errorf(`parse message file "%s": `+format, append([]any{path}, args...)...)
+	}
+
+	tag, format, err := parsePath(path)
+	if err != nil {
+		return errorf("%w", err)
+	}
+
+	b.mu.RLock()
+	unmarshal, ok := b.unmarshalFuncs[format]
+	b.mu.RUnlock()
+
+	if !ok {
+		return errorf(`unsupported format "%s"`, format)
+	}
+
+	var data any
+
+	if err := unmarshal(buf, &data); err != nil {
+		return errorf("%w", err)
+	}
+
+	messages, err := parseMessages(data, "")
+	if err != nil {
+		return errorf("%w", err)
+	}
+
+	if err := b.AddMessages(tag, messages...); err != nil {
+		return errorf("%w", err)
+	}
+
+	return &MessageFile{Path: path, Tag: tag, Format: format, Messages: messages}, nil
+}
+
+// AddMessages converts the messages to MF2 and adds them to the bundle.
+func (b *Bundle) AddMessages(tag language.Tag, messages ...*Message) error {
+	c := b.catalog(tag)
+
+	for _, msg := range messages {
+		tree, err := Convert(msg)
+		if err != nil {
+			return err
+		}
+
+		c.Set(msg.ID, tree.String())
+	}
+
+	return nil
+}
+
+// LanguageTags returns the languages of the bundle, the default language first.
+func (b *Bundle) LanguageTags() []language.Tag {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	return append([]language.Tag(nil), b.tags...)
+}
+
+// Catalog returns the catalog of converted messages in the language, or nil.
+func (b *Bundle) Catalog(tag language.Tag) *catalog.Catalog {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	return b.catalogs[tag]
+}
+
+// catalog returns the catalog of the language, the catalog is created if it does not exist.
+func (b *Bundle) catalog(tag language.Tag) *catalog.Catalog {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if c, ok := b.catalogs[tag]; ok {
+		return c
+	}
+
+	c := catalog.New(tag, b.options...)
+	b.catalogs[tag] = c
+
+	if tag != b.defaultLanguage {
+		b.tags = append(b.tags, tag)
+		b.matcher = language.NewMatcher(b.tags)
+	}
+
+	return c
+}
+
+// match returns the best matching language of the bundle.
+func (b *Bundle) match(tags ...language.Tag) language.Tag {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	_, i, _ := b.matcher.Match(tags...)
+
+	return b.tags[i]
+}
+
+// parsePath returns the language and the format of the message file,
+// e.g. "en-US" and "json" for "active.en-US.json".
+func parsePath(path string) (language.Tag, string, error) {
+	parts := strings.Split(filepath.Base(path), ".")
+	if len(parts) < 2 { //nolint:mnd
+		return language.Und, "", fmt.Errorf(`want file name "[name.]lang.format", got "%s"`, filepath.Base(path))
+	}
+
+	tag, err := language.Parse(parts[len(parts)-2])
+	if err != nil {
+		return language.Und, "", fmt.Errorf("parse language: %w", err)
+	}
+
+	return tag, parts[len(parts)-1], nil
+}
+
+// parseMessages parses unmarshaled message file data. Nested messages are
+// joined with the parent key, e.g. {"a": {"b": "text"}} is the message "a.b".
+func parseMessages(data any, prefix string) ([]*Message, error) {
+	m, ok := stringMap(data)
+	if !ok {
+		return nil, fmt.Errorf("want object of messages, got %T", data)
+	}
+
+	keys := mapkeys.Sorted(m)
+
+	var messages []*Message
+
+	for _, key := range keys {
+		id := prefix + key
+
+		if s, ok := m[key].(string); ok {
+			messages = append(messages, &Message{ID: id, Other: s})
+			continue
+		}
+
+		fields, ok := stringMap(m[key])
+		if !ok {
+			return nil, fmt.Errorf(`message "%s": want string or object, got %T`, id, m[key])
+		}
+
+		if !isMessage(fields) {
+			nested, err := parseMessages(fields, id+".")
+			if err != nil {
+				return nil, err
+			}
+
+			messages = append(messages, nested...)
+
+			continue
+		}
+
+		msg, err := newMessage(id, fields)
+		if err != nil {
+			return nil, err
+		}
+
+		messages = append(messages, msg)
+	}
+
+	return messages, nil
+}
+
+// isMessage reports whether the object is a message, otherwise it is a group of nested messages.
+func isMessage(fields map[string]any) bool {
+	for key, value := range fields {
+		if _, ok := value.(string); !ok {
+			continue
+		}
+
+		switch strings.ToLower(key) {
+		case "id", "hash", "description", "leftdelim", "rightdelim",
+			"zero", "one", "two", "few", "many", "other":
+			return true
+		}
+	}
+
+	return false
+}
+
+// newMessage returns the message from the object fields. Field names are case-insensitive.
+func newMessage(id string, fields map[string]any) (*Message, error) {
+	msg := &Message{ID: id}
+
+	// the fields are read in the sorted order, the same field wins on every load, e.g. of "id" and "ID"
+	for _, key := range mapkeys.Sorted(fields) {
+		value := fields[key]
+
+		s, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf(`message "%s": field "%s": want string, got %T`, id, key, value)
+		}
+
+		switch strings.ToLower(key) {
+		case "id":
+			msg.ID = s
+		case "hash":
+			msg.Hash = s
+		case "description":
+			msg.Description = s
+		case "leftdelim":
+			msg.LeftDelim = s
+		case "rightdelim":
+			msg.RightDelim = s
+		case "zero":
+			msg.Zero = s
+		case "one":
+			msg.One = s
+		case "two":
+			msg.Two = s
+		case "few":
+			msg.Few = s
+		case "many":
+			msg.Many = s
+		case "other":
+			msg.Other = s
+		}
+	}
+
+	return msg, nil
+}
+
+// stringMap converts the object to map with string keys.
+// Some YAML decoders produce map[any]any.
+func stringMap(v any) (map[string]any, bool) {
+	switch m := v.(type) {
+	default:
+		return nil, false
+	case map[string]any:
+		return m, true
+	case map[any]any:
+		r := make(map[string]any, len(m))
+		for k, v := range m {
+			r[fmt.Sprint(k)] = v
+		}
+
+		return r, true
+	}
+}
@@ -0,0 +1,148 @@
+package goi18n
+
+import (
+	"errors"
+	"testing"
+
+	"golang.org/x/text/language"
+
+	"go.expect.digital/mf2/catalog"
+)
+
+func TestLocalize(t *testing.T) {
+	t.Parallel()
+
+	bundle := NewBundle(language.English)
+
+	if _, err := bundle.ParseMessageFileBytes([]byte(`{
+  "HelloPerson": "Hello {{.Name}}",
+  "PersonCats": {
+    "description": "The number of cats a person has",
+    "one": "{{.Name}} has {{.PluralCount}} cat.",
+    "other": "{{.Name}} has {{.PluralCount}} cats."
+  },
+  "Nested": {"Greeting": "Hi"}
+}`), "active.en.json"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := bundle.ParseMessageFileBytes([]byte(`{
+  "HelloPerson": "Hola {{.Name}}",
+  "PersonCats": {"one": "{{.Name}} tiene {{.PluralCount}} gato.", "other": "{{.Name}} tiene {{.PluralCount}} gatos."}
+}`), "translate/active.es.json"); err != nil {
+		t.Fatal(err)
+	}
+
+	type person struct {
+		Name string
+	}
+
+	for _, test := range []struct {
+		name    string
+		langs   []string
+		config  LocalizeConfig
+		want    string
+		wantTag language.Tag
+	}{
+		{
+			name:    "simple",
+			langs:   []string{"es"},
+			config:  LocalizeConfig{MessageID: "HelloPerson", TemplateData: map[string]string{"Name": "Nick"}},
+			want:    "Hola Nick",
+			wantTag: language.Spanish,
+		},
+		{
+			name:    "accept-language",
+			langs:   []string{"fr-FR,es;q=0.9"},
+			config:  LocalizeConfig{MessageID: "HelloPerson", TemplateData: person{Name: "Nick"}},
+			want:    "Hola Nick",
+			wantTag: language.Spanish,
+		},
+		{
+			name:    "plural one",
+			langs:   []string{"es"},
+			config:  LocalizeConfig{MessageID: "PersonCats", TemplateData: map[string]any{"Name": "Nick"}, PluralCount: 1},
+			want:    "Nick tiene 1 gato.",
+			wantTag: language.Spanish,
+		},
+		{
+			name:    "plural other",
+			langs:   []string{"en"},
+			config:  LocalizeConfig{MessageID: "PersonCats", TemplateData: &person{Name: "Nick"}, PluralCount: 2},
+			want:    "Nick has 2 cats.",
+			wantTag: language.English,
+		},
+		{
+			name:    "nested",
+			langs:   []string{"en"},
+			config:  LocalizeConfig{MessageID: "Nested.Greeting"},
+			want:    "Hi",
+			wantTag: language.English,
+		},
+		{
+			name:    "fallback to default language",
+			langs:   []string{"es"},
+			config:  LocalizeConfig{MessageID: "Nested.Greeting"},
+			want:    "Hi",
+			wantTag: language.English,
+		},
+		{
+			name:  "default message",
+			langs: []string{"es"},
+			config: LocalizeConfig{
+				MessageID:      "Missing",
+				DefaultMessage: &Message{ID: "Missing", One: "one dog", Other: "{{.PluralCount}} dogs"},
+				PluralCount:    3,
+			},
+			want:    "3 dogs",
+			wantTag: language.English,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, tag, err := NewLocalizer(bundle, test.langs...).LocalizeWithTag(&test.config)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if test.want != got {
+				t.Errorf("want '%s', got '%s'", test.want, got)
+			}
+
+			if test.wantTag != tag {
+				t.Errorf("want '%s', got '%s'", test.wantTag, tag)
+			}
+		})
+	}
+
+	t.Run("missing message", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := NewLocalizer(bundle, "es").Localize(&LocalizeConfig{MessageID: "Missing"})
+		if !errors.Is(err, catalog.ErrMissingMessage) {
+			t.Errorf("want '%s', got '%s'", catalog.ErrMissingMessage, err)
+		}
+	})
+}
+
+func TestParseMessageFileBytesErrors(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		path, data string
+	}{
+		{path: "en.toml", data: `HelloPerson = "Hello"`},
+		{path: "json", data: `{}`},
+		{path: "active.xx-invalid-.json", data: `{}`},
+		{path: "en.json", data: `{`},
+		{path: "en.json", data: `["HelloPerson"]`},
+		{path: "en.json", data: `{"HelloPerson": 1}`},
+		{path: "en.json", data: `{"HelloPerson": {"other": 1}}`},
+		{path: "en.json", data: `{"HelloPerson": {"one": "only one"}}`},
+	} {
+		if _, err := NewBundle(language.English).ParseMessageFileBytes([]byte(test.data), test.path); err == nil {
+			t.Errorf("%s %s: want error, got nil", test.path, test.data)
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package goi18n
+
+import (
+	"fmt"
+	"reflect"
+
+	"golang.org/x/text/language"
+
+	"go.expect.digital/mf2/catalog"
+	"go.expect.digital/mf2/template"
+)
+
+// LocalizeConfig configures a call to [Localizer.Localize].
+type LocalizeConfig struct {
+	// TemplateData is the data passed to the message, a map with string keys or a struct.
+	TemplateData any
+	// PluralCount determines the plural form of the message, available as "$PluralCount".
+	PluralCount any
+	// DefaultMessage is used if the message is not found in the bundle.
+	DefaultMessage *Message
+	// MessageID is the ID of the message to localize.
+	MessageID string
+}
+
+// Localizer formats messages in the best matching language of the bundle.
+type Localizer struct {
+	bundle    *Bundle
+	languages []language.Tag
+}
+
+// NewLocalizer returns a new localizer for the languages in order of preference.
+// The languages are parsed as Accept-Language values, e.g. "en-US,en;q=0.9", invalid values are ignored.
+func NewLocalizer(bundle *Bundle, langs ...string) *Localizer {
+	var languages []language.Tag
+
+	for _, lang := range langs {
+		tags, _, err := language.ParseAcceptLanguage(lang)
+		if err != nil {
+			continue
+		}
+
+		languages = append(languages, tags...)
+	}
+
+	return &Localizer{bundle: bundle, languages: languages}
+}
+
+// Localize returns the formatted message.
+func (l *Localizer) Localize(lc *LocalizeConfig) (string, error) {
+	s, _, err := l.LocalizeWithTag(lc)
+
+	return s, err
+}
+
+// LocalizeMessage returns the formatted message.
+func (l *Localizer) LocalizeMessage(msg *Message) (string, error) {
+	return l.Localize(&LocalizeConfig{MessageID: msg.ID, DefaultMessage: msg})
+}
+
+// MustLocalize is similar to [Localizer.Localize] except it panics if an error happens.
+func (l *Localizer) MustLocalize(lc *LocalizeConfig) string {
+	s, err := l.Localize(lc)
+	if err != nil {
+		panic(err)
+	}
+
+	return s
+}
+
+// LocalizeWithTag returns the formatted message and its language.
+//
+// The message is looked up in the best matching language, then in the default language
+// of the bundle. If not found, the default message is formatted in the default language.
+func (l *Localizer) LocalizeWithTag(lc *LocalizeConfig) (string, language.Tag, error) {
+	errorf := func(tag language.Tag, format string, args ...any) (string, language.Tag, error) {
+		return "", tag, fmt.Errorf(`localize "%s": `+format, append([]any{lc.MessageID}, args...)...)
+	}
+
+	input, err := templateData(lc.TemplateData)
+	if err != nil {
+		return errorf(language.Und, "%w", err)
+	}
+
+	if _, ok := input[PluralCountVar]; !ok && lc.PluralCount != nil {
+		input[PluralCountVar] = lc.PluralCount
+	}
+
+	messageID := lc.MessageID
+	if messageID == "" && lc.DefaultMessage != nil {
+		messageID = lc.DefaultMessage.ID
+	}
+
+	for _, tag := range []language.Tag{l.bundle.match(l.languages...), l.bundle.defaultLanguage} {
+		c := l.bundle.Catalog(tag)
+		if c == nil {
+			continue
+		}
+
+		if _, ok := c.Message(messageID); !ok {
+			continue
+		}
+
+		s, err := c.Sprint(messageID, input)
+		if err != nil {
+			return errorf(tag, "%w", err)
+		}
+
+		return s, tag, nil
+	}
+
+	tag := l.bundle.defaultLanguage
+
+	if lc.DefaultMessage == nil {
+		return errorf(tag, "%w", catalog.ErrMissingMessage)
+	}
+
+	tree, err := Convert(lc.DefaultMessage)
+	if err != nil {
+		return errorf(tag, "%w", err)
+	}
+
+	options := append([]template.Option{template.WithLocale(tag)}, l.bundle.options...)
+
+	tmpl, err := template.New(options...).Parse(tree.String())
+	if err != nil {
+		return errorf(tag, "%w", err)
+	}
+
+	s, err := tmpl.Sprint(input)
+	if err != nil {
+		return errorf(tag, "%w", err)
+	}
+
+	return s, tag, nil
+}
+
+// templateData converts the template data to MF2 input.
+// Exported struct fields and map values with string keys are added by name.
+func templateData(data any) (map[string]any, error) {
+	input := make(map[string]any)
+
+	switch data := data.(type) {
+	case nil:
+		return input, nil
+	case map[string]any:
+		for k, v := range data {
+			input[k] = v
+		}
+
+		return input, nil
+	case map[string]string:
+		for k, v := range data {
+			input[k] = v
+		}
+
+		return input, nil
+	}
+
+	v := reflect.Indirect(reflect.ValueOf(data))
+
+	switch v.Kind() { //nolint:exhaustive
+	default:
+		return nil, fmt.Errorf("want template data map or struct, got %T", data)
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("want template data map with string keys, got %T", data)
+		}
+
+		iter := v.MapRange()
+		for iter.Next() {
+			input[iter.Key().String()] = iter.Value().Interface()
+		}
+	case reflect.Struct:
+		for i := range v.NumField() {
+			if field := v.Type().Field(i); field.IsExported() {
+				input[field.Name] = v.Field(i).Interface()
+			}
+		}
+	}
+
+	return input, nil
+}
@@ -0,0 +1,257 @@
+// Package jsonschema validates JSON documents against the JSON Schema documents of the module.
+//
+// Only the subset of JSON Schema 2020-12 used by the module's schemas is supported:
+// "type", "enum", "const", "properties", "required", "additionalProperties", "items",
+// "minItems", "minLength", "oneOf", "$defs" and the local "$ref", e.g. "#/$defs/expression".
+// The other keywords, e.g. "description", are ignored.
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"strings"
+
+	"go.expect.digital/mf2/internal/mapkeys"
+)
+
+// Schema is a parsed JSON Schema document.
+type Schema struct {
+	root map[string]any
+}
+
+// Parse parses the JSON Schema document.
+func Parse(data []byte) (*Schema, error) {
+	var root map[string]any
+
+	if err := json.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("parse JSON schema: %w", err)
+	}
+
+	return &Schema{root: root}, nil
+}
+
+// MustParse is like [Parse] but panics on error, e.g. for the embedded schemas.
+func MustParse(data []byte) *Schema {
+	s, err := Parse(data)
+	if err != nil {
+		panic(err)
+	}
+
+	return s
+}
+
+// Validate validates the JSON document, all violations are reported at once
+// with the JSON Pointer of the invalid value, e.g. "/messages/greeting: missing property "message"".
+func (s *Schema) Validate(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var doc any
+
+	if err := dec.Decode(&doc); err != nil {
+		return fmt.Errorf("validate JSON: %w", err)
+	}
+
+	if dec.More() {
+		return errors.New("validate JSON: unexpected data after document")
+	}
+
+	var errs []error
+
+	s.validate(s.root, doc, "", &errs)
+
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("validate JSON: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Schema) validate(schema map[string]any, v any, path string, errs *[]error) {
+	errorf := func(format string, args ...any) {
+		p := path
+		if p == "" {
+			p = "/"
+		}
+
+		*errs = append(*errs, fmt.Errorf("%s: "+format, append([]any{p}, args...)...))
+	}
+
+	if ref, ok := schema["$ref"].(string); ok {
+		target, err := s.resolve(ref)
+		if err != nil {
+			errorf("%s", err)
+			return
+		}
+
+		s.validate(target, v, path, errs)
+	}
+
+	if t, ok := schema["type"]; ok && !hasType(v, t) {
+		errorf("want type %v, got %s", t, typeOf(v))
+		return
+	}
+
+	if c, ok := schema["const"]; ok && !equal(c, v) {
+		errorf("want %v, got %v", c, v)
+	}
+
+	if enum, ok := schema["enum"].([]any); ok && !slices.ContainsFunc(enum, func(e any) bool { return equal(e, v) }) {
+		errorf("want one of %v, got %v", enum, v)
+	}
+
+	if oneOf, ok := schema["oneOf"].([]any); ok {
+		s.oneOf(oneOf, v, path, errorf)
+	}
+
+	switch v := v.(type) {
+	case string:
+		if n, ok := schema["minLength"].(float64); ok && float64(len([]rune(v))) < n {
+			errorf("want at least %v characters", n)
+		}
+	case []any:
+		if n, ok := schema["minItems"].(float64); ok && float64(len(v)) < n {
+			errorf("want at least %v items", n)
+		}
+
+		if items, ok := schema["items"].(map[string]any); ok {
+			for i, item := range v {
+				s.validate(items, item, fmt.Sprintf("%s/%d", path, i), errs)
+			}
+		}
+	case map[string]any:
+		s.object(schema, v, path, errs, errorf)
+	}
+}
+
+func (s *Schema) object(schema, v map[string]any, path string, errs *[]error, errorf func(string, ...any)) {
+	if required, ok := schema["required"].([]any); ok {
+		for _, name := range required {
+			if _, ok := v[name.(string)]; !ok { //nolint:forcetypeassert // required names are strings
+				errorf(`missing property "%s"`, name)
+			}
+		}
+	}
+
+	properties, _ := schema["properties"].(map[string]any)
+
+	for _, name := range mapkeys.Sorted(v) {
+		p := path + "/" + strings.NewReplacer("~", "~0", "/", "~1").Replace(name)
+
+		if property, ok := properties[name].(map[string]any); ok {
+			s.validate(property, v[name], p, errs)
+			continue
+		}
+
+		switch additional := schema["additionalProperties"].(type) {
+		case bool:
+			if !additional {
+				errorf(`unknown property "%s"`, name)
+			}
+		case map[string]any:
+			s.validate(additional, v[name], p, errs)
+		}
+	}
+}
+
+func (s *Schema) oneOf(oneOf []any, v any, path string, errorf func(string, ...any)) {
+	var matched int
+
+	for _, sub := range oneOf {
+		schema, _ := sub.(map[string]any)
+
+		var subErrs []error
+
+		s.validate(schema, v, path, &subErrs)
+
+		if len(subErrs) == 0 {
+			matched++
+		}
+	}
+
+	if matched != 1 {
+		errorf("want exactly one schema of oneOf to match, matched %d", matched)
+	}
+}
+
+// resolve returns the schema of the local reference, e.g. "#/$defs/expression".
+func (s *Schema) resolve(ref string) (map[string]any, error) {
+	if !strings.HasPrefix(ref, "#") {
+		return nil, fmt.Errorf(`unsupported reference "%s"`, ref)
+	}
+
+	var v any = s.root
+
+	for _, token := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+		if token == "" || token == "#" {
+			continue
+		}
+
+		m, ok := v.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf(`unresolved reference "%s"`, ref)
+		}
+
+		if v, ok = m[strings.NewReplacer("~1", "/", "~0", "~").Replace(token)]; !ok {
+			return nil, fmt.Errorf(`unresolved reference "%s"`, ref)
+		}
+	}
+
+	schema, ok := v.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf(`unresolved reference "%s"`, ref)
+	}
+
+	return schema, nil
+}
+
+// hasType reports whether the value has the type or one of the types.
+func hasType(v, t any) bool {
+	switch t := t.(type) {
+	case string:
+		return typeOf(v) == t || t == "number" && typeOf(v) == "integer"
+	case []any:
+		return slices.ContainsFunc(t, func(t any) bool { return hasType(v, t) })
+	}
+
+	return false
+}
+
+// typeOf returns the JSON Schema type of the decoded value.
+func typeOf(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	case json.Number:
+		if f, err := v.Float64(); err == nil && f == math.Trunc(f) {
+			return "integer"
+		}
+
+		return "number"
+	}
+
+	return fmt.Sprintf("%T", v)
+}
+
+// equal reports whether the schema value equals the document value, the numbers are compared by value.
+func equal(schema, v any) bool {
+	if n, ok := v.(json.Number); ok {
+		f, err := n.Float64()
+		return err == nil && schema == f
+	}
+
+	return reflect.DeepEqual(schema, v)
+}
@@ -0,0 +1,45 @@
+package jsonschema
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	schema := MustParse([]byte(`{
+  "type": "object",
+  "properties": {
+    "n": { "type": "integer" },
+    "f": { "type": ["number", "null"] },
+    "c": { "const": 1 },
+    "list": { "type": "array", "minItems": 1, "items": { "$ref": "#/$defs/item" } }
+  },
+  "$defs": {
+    "item": { "oneOf": [{ "type": "string", "minLength": 1 }, { "type": "boolean" }] }
+  }
+}`))
+
+	for _, test := range []struct {
+		doc, want string
+	}{
+		{doc: `{"n":1,"f":1.5,"c":1.0,"list":["a",true]}`},
+		{doc: `{"f":null}`},
+		{doc: `{"n":1.5}`, want: "/n: want type integer, got number"},
+		{doc: `{"c":2}`, want: "/c: want 1, got 2"},
+		{doc: `{"list":[]}`, want: "/list: want at least 1 items"},
+		{doc: `{"list":[""]}`, want: "/list/0: want exactly one schema of oneOf to match, matched 0"},
+		{doc: `[]`, want: "/: want type object, got array"},
+		{doc: `{} {}`, want: "unexpected data after document"},
+	} {
+		err := schema.Validate([]byte(test.doc))
+
+		switch {
+		case test.want == "" && err != nil:
+			t.Errorf("%s: want no error, got '%s'", test.doc, err)
+		case test.want != "" && (err == nil || !strings.Contains(err.Error(), test.want)):
+			t.Errorf("%s: want error containing '%s', got '%v'", test.doc, test.want, err)
+		}
+	}
+}
@@ -0,0 +1,16 @@
+// Package literal returns the values of the message literals for the converters to other formats.
+package literal
+
+import "go.expect.digital/mf2/parse"
+
+// Value returns the unquoted value of the literal.
+func Value(l parse.Literal) string {
+	switch v := l.(type) {
+	default:
+		return v.String()
+	case parse.QuotedLiteral:
+		return string(v)
+	case parse.NameLiteral:
+		return string(v)
+	}
+}
@@ -0,0 +1,24 @@
+package literal
+
+import (
+	"testing"
+
+	"go.expect.digital/mf2/parse"
+)
+
+func TestValue(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		literal parse.Literal
+		want    string
+	}{
+		{literal: parse.QuotedLiteral("a b|"), want: "a b|"},
+		{literal: parse.NameLiteral("name"), want: "name"},
+		{literal: parse.NumberLiteral(1.5), want: "1.5"},
+	} {
+		if got := Value(test.literal); test.want != got {
+			t.Errorf("want '%s', got '%s'", test.want, got)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+// Package lru is the least recently used cache of the module's caches: the parsed messages,
+// the templates of the Render function and the formatted messages of the bundle.
+package lru
+
+import (
+	"container/list"
+	"sync"
+)
+
+// Cache is the LRU cache of at most size values. It is safe for concurrent use.
+type Cache[K comparable, V any] struct {
+	entries map[K]*list.Element
+	lru     *list.List   // front is the most recently used
+	expired func(V) bool // nil if the values never expire
+	size    int
+	hits    uint64
+	misses  uint64
+	mu      sync.Mutex
+}
+
+type entry[K comparable, V any] struct {
+	key   K
+	value V
+}
+
+// New returns a new cache of at most size values, at least one. The expired reports whether
+// the cached value is stale, nil if the values never expire.
+func New[K comparable, V any](size int, expired func(V) bool) *Cache[K, V] {
+	return &Cache[K, V]{
+		size:    max(size, 1),
+		expired: expired,
+		entries: make(map[K]*list.Element),
+		lru:     list.New(),
+	}
+}
+
+// Get returns the cached value and marks it as the most recently used. The expired value is removed.
+func (c *Cache[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[key]
+	if !ok {
+		c.misses++
+
+		var zero V
+
+		return zero, false
+	}
+
+	entry := e.Value.(*entry[K, V]) //nolint:forcetypeassert
+
+	if c.expired != nil && c.expired(entry.value) {
+		c.lru.Remove(e)
+		delete(c.entries, key)
+
+		c.misses++
+
+		return entry.value, false
+	}
+
+	c.lru.MoveToFront(e)
+
+	c.hits++
+
+	return entry.value, true
+}
+
+// Add caches the value as the most recently used, the least recently used value is evicted if the cache is full.
+func (c *Cache[K, V]) Add(key K, value V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, ok := c.entries[key]; ok {
+		e.Value = &entry[K, V]{key: key, value: value}
+		c.lru.MoveToFront(e)
+
+		return
+	}
+
+	c.entries[key] = c.lru.PushFront(&entry[K, V]{key: key, value: value})
+
+	if c.lru.Len() > c.size {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(*entry[K, V]).key) //nolint:forcetypeassert
+	}
+}
+
+// Contains reports whether the key is cached, the key is not marked as used.
+func (c *Cache[K, V]) Contains(key K) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	_, ok := c.entries[key]
+
+	return ok
+}
+
+// Len returns the number of cached values.
+func (c *Cache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.lru.Len()
+}
+
+// Clear removes all cached values, the stats are kept.
+func (c *Cache[K, V]) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	clear(c.entries)
+	c.lru.Init()
+}
+
+// Stats returns the number of the hits and misses of [Cache.Get].
+func (c *Cache[K, V]) Stats() (hits, misses uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.hits, c.misses
+}
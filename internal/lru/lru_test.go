@@ -0,0 +1,69 @@
+package lru
+
+import "testing"
+
+func TestCache(t *testing.T) {
+	t.Parallel()
+
+	cache := New[string, int](2, nil)
+
+	cache.Add("a", 1)
+	cache.Add("b", 2)
+
+	if v, ok := cache.Get("a"); !ok || v != 1 {
+		t.Errorf("want 1, got %d, %t", v, ok)
+	}
+
+	// "b" is the least recently used
+	cache.Add("c", 3)
+
+	if cache.Contains("b") {
+		t.Error("want evicted 'b', got cached")
+	}
+
+	// replaces the value
+	cache.Add("a", 4)
+
+	if v, _ := cache.Get("a"); v != 4 {
+		t.Errorf("want 4, got %d", v)
+	}
+
+	if got := cache.Len(); got != 2 {
+		t.Errorf("want 2 cached values, got %d", got)
+	}
+
+	if hits, misses := cache.Stats(); hits != 2 || misses != 0 {
+		t.Errorf("want 2 hits and 0 misses, got %d and %d", hits, misses)
+	}
+
+	cache.Clear()
+
+	if _, ok := cache.Get("a"); ok || cache.Len() != 0 {
+		t.Error("want empty cache, got cached")
+	}
+}
+
+func TestCacheExpired(t *testing.T) {
+	t.Parallel()
+
+	cache := New[string, int](2, func(v int) bool { return v < 0 })
+
+	cache.Add("a", -1)
+	cache.Add("b", 1)
+
+	if _, ok := cache.Get("a"); ok {
+		t.Error("want expired 'a', got cached")
+	}
+
+	if cache.Contains("a") {
+		t.Error("want removed 'a', got cached")
+	}
+
+	if _, ok := cache.Get("b"); !ok {
+		t.Error("want cached 'b', got missing")
+	}
+
+	if hits, misses := cache.Stats(); hits != 1 || misses != 1 {
+		t.Errorf("want 1 hit and 1 miss, got %d and %d", hits, misses)
+	}
+}
@@ -0,0 +1,19 @@
+// Package mapkeys returns the keys of the maps in order, e.g. to iterate the maps deterministically.
+package mapkeys
+
+import (
+	"cmp"
+	"slices"
+)
+
+// Sorted returns the sorted keys of the map.
+func Sorted[K cmp.Ordered, V any](m map[K]V) []K {
+	keys := make([]K, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	slices.Sort(keys)
+
+	return keys
+}
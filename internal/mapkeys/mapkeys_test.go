@@ -0,0 +1,18 @@
+package mapkeys
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSorted(t *testing.T) {
+	t.Parallel()
+
+	if want, got := []string{"a", "b", "c"}, Sorted(map[string]int{"c": 3, "a": 1, "b": 2}); !slices.Equal(want, got) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+
+	if got := Sorted(map[string]int(nil)); len(got) != 0 {
+		t.Errorf("want no keys, got %v", got)
+	}
+}
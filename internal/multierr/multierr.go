@@ -0,0 +1,16 @@
+// Package multierr splits the errors joined by [errors.Join], e.g. the resolution errors of the template,
+// to report them one by one.
+package multierr
+
+// Errors returns the errors joined in the err, or the err, nil if the err is nil.
+func Errors(err error) []error {
+	if err == nil {
+		return nil
+	}
+
+	if joined, ok := err.(interface{ Unwrap() []error }); ok { //nolint:errorlint
+		return joined.Unwrap()
+	}
+
+	return []error{err}
+}
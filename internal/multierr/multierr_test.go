@@ -0,0 +1,28 @@
+package multierr
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestErrors(t *testing.T) {
+	t.Parallel()
+
+	a, b := errors.New("a"), errors.New("b")
+
+	for _, test := range []struct {
+		err  error
+		want []error
+	}{
+		{err: nil, want: nil},
+		{err: a, want: []error{a}},
+		{err: errors.Join(a, b), want: []error{a, b}},
+		{err: fmt.Errorf("%w, %w", a, b), want: []error{a, b}},
+	} {
+		if got := Errors(test.err); !reflect.DeepEqual(test.want, got) {
+			t.Errorf("want %v, got %v", test.want, got)
+		}
+	}
+}
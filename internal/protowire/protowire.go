@@ -0,0 +1,182 @@
+// Package protowire encodes and decodes the Protocol Buffers wire format of the module's messages,
+// see https://protobuf.dev/programming-guides/encoding/.
+//
+// The wire format is implemented without google.golang.org/protobuf to keep the module free of dependencies.
+// Only the wire types used by the module are supported: varint, 64-bit, length-delimited and 32-bit.
+package protowire
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"unicode/utf8"
+)
+
+// Type is the wire type of the field.
+type Type int
+
+const (
+	Varint  Type = 0
+	Fixed64 Type = 1
+	Bytes   Type = 2
+	Fixed32 Type = 5
+)
+
+// ErrTruncated occurs when the proto data ends unexpectedly.
+var ErrTruncated = errors.New("truncated data")
+
+// AppendTag appends the tag of the field.
+func AppendTag(b []byte, num int, typ Type) []byte {
+	return binary.AppendUvarint(b, uint64(num)<<3|uint64(typ)) //nolint:gosec
+}
+
+// AppendBytes appends the length-delimited field, also the empty one, e.g. the embedded message.
+func AppendBytes(b []byte, num int, v []byte) []byte {
+	b = AppendTag(b, num, Bytes)
+	b = binary.AppendUvarint(b, uint64(len(v)))
+
+	return append(b, v...)
+}
+
+// AppendString appends the string field, also the empty one, e.g. the member of oneof.
+func AppendString(b []byte, num int, s string) []byte {
+	return AppendBytes(b, num, []byte(s))
+}
+
+// AppendNonEmpty appends the non-empty string, proto3 does not encode default values of singular fields.
+func AppendNonEmpty(b []byte, num int, s string) []byte {
+	if s == "" {
+		return b
+	}
+
+	return AppendString(b, num, s)
+}
+
+// AppendVarint appends the varint field, also the zero one.
+func AppendVarint(b []byte, num int, v uint64) []byte {
+	return binary.AppendUvarint(AppendTag(b, num, Varint), v)
+}
+
+// AppendNonZero appends the non-zero varint, proto3 does not encode default values of singular fields.
+func AppendNonZero(b []byte, num int, v uint64) []byte {
+	if v == 0 {
+		return b
+	}
+
+	return AppendVarint(b, num, v)
+}
+
+// AppendDouble appends the double field.
+func AppendDouble(b []byte, num int, f float64) []byte {
+	return binary.LittleEndian.AppendUint64(AppendTag(b, num, Fixed64), math.Float64bits(f))
+}
+
+// Field is a single decoded field of the proto message.
+type Field struct {
+	data []byte // Bytes
+	Num  int
+	Type Type
+	u64  uint64 // Varint, Fixed64 and Fixed32
+}
+
+func (f Field) want(typ Type) error {
+	if f.Type != typ {
+		return fmt.Errorf("field %d: want wire type %d, got %d", f.Num, typ, f.Type)
+	}
+
+	return nil
+}
+
+// Bytes returns the value of the length-delimited field, e.g. the embedded message.
+func (f Field) Bytes() ([]byte, error) {
+	if err := f.want(Bytes); err != nil {
+		return nil, err
+	}
+
+	return f.data, nil
+}
+
+// String returns the value of the string field, it must be valid UTF-8.
+func (f Field) String() (string, error) {
+	if err := f.want(Bytes); err != nil {
+		return "", err
+	}
+
+	if !utf8.Valid(f.data) {
+		return "", fmt.Errorf("field %d: invalid UTF-8", f.Num)
+	}
+
+	return string(f.data), nil
+}
+
+// Varint returns the value of the varint field.
+func (f Field) Varint() (uint64, error) {
+	if err := f.want(Varint); err != nil {
+		return 0, err
+	}
+
+	return f.u64, nil
+}
+
+// Double returns the value of the double field.
+func (f Field) Double() (float64, error) {
+	if err := f.want(Fixed64); err != nil {
+		return 0, err
+	}
+
+	return math.Float64frombits(f.u64), nil
+}
+
+// Range calls fn for every field of the proto message in order.
+func Range(b []byte, fn func(f Field) error) error {
+	for len(b) > 0 {
+		tag, n := binary.Uvarint(b)
+		if n <= 0 {
+			return ErrTruncated
+		}
+
+		b = b[n:]
+		f := Field{Num: int(tag >> 3), Type: Type(tag & 7)} //nolint:gosec,mnd
+
+		if f.Num <= 0 {
+			return fmt.Errorf("invalid field number %d", f.Num)
+		}
+
+		switch f.Type {
+		default:
+			return fmt.Errorf("field %d: unsupported wire type %d", f.Num, f.Type)
+		case Varint:
+			if f.u64, n = binary.Uvarint(b); n <= 0 {
+				return ErrTruncated
+			}
+
+			b = b[n:]
+		case Fixed64:
+			if len(b) < 8 { //nolint:mnd
+				return ErrTruncated
+			}
+
+			f.u64, b = binary.LittleEndian.Uint64(b), b[8:]
+		case Fixed32:
+			if len(b) < 4 { //nolint:mnd
+				return ErrTruncated
+			}
+
+			f.u64, b = uint64(binary.LittleEndian.Uint32(b)), b[4:]
+		case Bytes:
+			size, n := binary.Uvarint(b)
+			if n <= 0 || size > uint64(len(b)-n) {
+				return ErrTruncated
+			}
+
+			f.data, b = b[n:n+int(size)], b[n+int(size):] //nolint:gosec
+		}
+
+		if err := fn(f); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,59 @@
+package protowire
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestRange(t *testing.T) {
+	t.Parallel()
+
+	b := AppendString(nil, 1, "text")
+	b = AppendNonEmpty(b, 2, "")
+	b = AppendVarint(b, 3, 0)
+	b = AppendNonZero(b, 4, 0)
+	b = AppendDouble(b, 5, 1.5)
+	b = AppendBytes(b, 6, AppendNonZero(nil, 1, 300))
+
+	var got []any
+
+	err := Range(b, func(f Field) error {
+		var (
+			v   any
+			err error
+		)
+
+		switch f.Num {
+		case 1:
+			v, err = f.String()
+		case 3:
+			v, err = f.Varint()
+		case 5:
+			v, err = f.Double()
+		case 6:
+			v, err = f.Bytes()
+		}
+
+		got = append(got, v)
+
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []any{"text", uint64(0), 1.5, []byte{0x08, 0xac, 0x02}}
+
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+
+	if err := Range(b[:len(b)-1], func(Field) error { return nil }); !errors.Is(err, ErrTruncated) {
+		t.Errorf("want '%s', got '%v'", ErrTruncated, err)
+	}
+
+	if _, err := (Field{Num: 1, Type: Varint}).String(); err == nil {
+		t.Error("want wire type error, got nil")
+	}
+}
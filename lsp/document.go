@@ -0,0 +1,370 @@
+package lsp
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/language"
+
+	"go.expect.digital/mf2"
+	"go.expect.digital/mf2/parse"
+	"go.expect.digital/mf2/template"
+)
+
+// tokenKind is the kind of the token in the message.
+type tokenKind int
+
+const (
+	tokenVariable tokenKind = iota // "$name"
+	tokenFunction                  // ":name"
+	tokenOption                    // "name" followed by "="
+	tokenKeyword                   // ".input", ".local", ".match"
+)
+
+// token is a variable, function, option or keyword in the code of the message.
+// The code is the declarations and selectors of the complex message, and the expressions.
+// Pattern text and literals are not tokenised.
+type token struct {
+	text string
+	// function is the function of the expression the option belongs to, e.g. ":number".
+	function   string
+	start, end int // byte offsets
+	kind       tokenKind
+	// declaration is true if the variable is declared by the token.
+	declaration bool
+}
+
+// tokenize returns the tokens of the message.
+//
+// The message is tokenised without parsing, the tokens are found also in invalid messages.
+func tokenize(text string) []token {
+	var (
+		tokens []token
+		// position in the message
+		inPattern    bool // quoted pattern of the complex message
+		inExpression bool
+		// the function of the current expression
+		function string
+	)
+
+	// the message is complex if it starts with a keyword or a quoted pattern
+	trimmed := strings.TrimLeftFunc(text, unicode.IsSpace)
+	complexMessage := strings.HasPrefix(trimmed, ".") || strings.HasPrefix(trimmed, "{{")
+	inPattern = !complexMessage
+
+	for i := 0; i < len(text); {
+		r, size := utf8.DecodeRuneInString(text[i:])
+
+		// pattern text
+		if inPattern && !inExpression {
+			switch {
+			case r == '\\':
+				_, next := utf8.DecodeRuneInString(text[i+size:])
+				i += size + next
+			case r == '{':
+				inExpression = true
+				function = ""
+				i += size
+			case r == '}' && complexMessage && strings.HasPrefix(text[i:], "}}"):
+				inPattern = false
+				i += len("}}")
+			default:
+				i += size
+			}
+
+			continue
+		}
+
+		switch {
+		default:
+			i += size
+		case r == '|': // quoted literal
+			i += size
+
+			for i < len(text) && text[i] != '|' {
+				if text[i] == '\\' {
+					i++
+				}
+
+				i++
+			}
+
+			i++
+		case r == '{' && !inExpression && strings.HasPrefix(text[i:], "{{"):
+			inPattern = true
+			i += len("{{")
+		case r == '{' && !inExpression:
+			inExpression = true
+			function = ""
+			i += size
+		case r == '}' && inExpression:
+			inExpression = false
+			i += size
+		case r == '@': // attribute
+			i += size
+
+			for i < len(text) {
+				next, n := utf8.DecodeRuneInString(text[i:])
+				if !isName(next) && next != ':' {
+					break
+				}
+
+				i += n
+			}
+		case r == '$', r == ':', r == '.' && !inExpression:
+			end := i + size
+			for end < len(text) {
+				next, n := utf8.DecodeRuneInString(text[end:])
+				if !isName(next) && (next != ':' || r != ':') {
+					break
+				}
+
+				end += n
+			}
+
+			t := token{text: text[i:end], start: i, end: end}
+
+			switch r {
+			case '$':
+				t.kind = tokenVariable
+				t.declaration = isDeclaration(tokens, inExpression)
+			case ':':
+				t.kind = tokenFunction
+				function = t.text
+			case '.':
+				t.kind = tokenKeyword
+			}
+
+			if end > i+size {
+				tokens = append(tokens, t)
+			}
+
+			i = end
+		case isNameStart(r) && inExpression:
+			end := i + size
+			for end < len(text) {
+				next, n := utf8.DecodeRuneInString(text[end:])
+				if !isName(next) && next != ':' {
+					break
+				}
+
+				end += n
+			}
+
+			if rest := strings.TrimLeftFunc(text[end:], unicode.IsSpace); function != "" && strings.HasPrefix(rest, "=") {
+				tokens = append(tokens, token{text: text[i:end], start: i, end: end, kind: tokenOption, function: function})
+			}
+
+			i = end
+		}
+	}
+
+	return tokens
+}
+
+// isDeclaration returns true if the variable following the tokens is declared,
+// i.e. ".input {$x}" or ".local $x = {...}".
+func isDeclaration(tokens []token, inExpression bool) bool {
+	if len(tokens) == 0 {
+		return false
+	}
+
+	last := tokens[len(tokens)-1]
+
+	return last.kind == tokenKeyword &&
+		(last.text == ".input" && inExpression || last.text == ".local" && !inExpression)
+}
+
+func isNameStart(r rune) bool {
+	return unicode.IsLetter(r) || r == '_'
+}
+
+func isName(r rune) bool {
+	return isNameStart(r) || unicode.IsDigit(r) || r == '-' || r == '.'
+}
+
+// tokenAt returns the token at the byte offset.
+func tokenAt(tokens []token, offset int) (token, bool) {
+	for _, t := range tokens {
+		if t.start <= offset && offset <= t.end {
+			return t, true
+		}
+	}
+
+	return token{}, false
+}
+
+// ---------------------------------positions---------------------------------
+
+// offsetOf converts the position to the byte offset in the text.
+func offsetOf(text string, pos position) int {
+	offset := 0
+
+	for range pos.Line {
+		i := strings.IndexByte(text[offset:], '\n')
+		if i == -1 {
+			return len(text)
+		}
+
+		offset += i + 1
+	}
+
+	for character := 0; character < pos.Character && offset < len(text); {
+		r, size := utf8.DecodeRuneInString(text[offset:])
+		if r == '\n' {
+			break
+		}
+
+		character += utf16Len(r)
+		offset += size
+	}
+
+	return offset
+}
+
+// positionOf converts the byte offset in the text to the position.
+func positionOf(text string, offset int) position {
+	var pos position
+
+	for _, r := range text[:min(offset, len(text))] {
+		if r == '\n' {
+			pos.Line++
+			pos.Character = 0
+
+			continue
+		}
+
+		pos.Character += utf16Len(r)
+	}
+
+	return pos
+}
+
+// utf16Len returns the number of UTF-16 code units of the rune.
+func utf16Len(r rune) int {
+	if r >= 0x10000 { //nolint:mnd // supplementary planes are encoded as surrogate pairs
+		return 2 //nolint:mnd
+	}
+
+	return 1
+}
+
+func rangeOf(text string, start, end int) textRange {
+	return textRange{Start: positionOf(text, start), End: positionOf(text, end)}
+}
+
+// ---------------------------------features---------------------------------
+
+// diagnose returns the syntax and data model errors of the message,
+// and warnings for the unreachable variants and the functions not in the default registry.
+func diagnose(text string) []diagnostic {
+	tree, found := parse.Diagnose(text)
+
+	// the locale of the document is unknown, the plural categories of any locale are possible
+	for _, v := range template.UnreachableVariants(tree, language.Und) {
+		// the variants have no position, the whole message is marked
+		found = append(found, v.Diagnostic(mf2.Span{End: len(text)}))
+	}
+
+	registry := template.NewRegistry()
+
+	for _, t := range tokenize(text) {
+		if t.kind != tokenFunction {
+			continue
+		}
+
+		if _, ok := registry[t.text[1:]]; !ok {
+			found = append(found, mf2.Diagnostic{
+				Severity: mf2.SeverityWarning,
+				Code:     "unknown-function",
+				Message:  `unknown function "` + t.text + `"`,
+				Span:     mf2.Span{Start: t.start, End: t.end},
+			})
+		}
+	}
+
+	diagnostics := make([]diagnostic, 0, len(found))
+
+	for _, d := range found {
+		diagnostics = append(diagnostics, diagnostic{
+			Range:    rangeOf(text, d.Span.Start, d.Span.End),
+			Severity: int(d.Severity),
+			Code:     d.Code,
+			Source:   "mf2",
+			Message:  d.Message,
+		})
+	}
+
+	return diagnostics
+}
+
+// hoverAt returns the documentation of the function or the option at the position.
+func hoverAt(text string, pos position) *hover {
+	t, ok := tokenAt(tokenize(text), offsetOf(text, pos))
+	if !ok {
+		return nil
+	}
+
+	var doc string
+
+	switch t.kind {
+	default:
+		return nil
+	case tokenFunction:
+		f, ok := functions[t.text[1:]]
+		if !ok {
+			return nil
+		}
+
+		doc = "```mf2\n" + t.text + "\n```\n\n" + f.doc
+	case tokenOption:
+		f, ok := functions[t.function[1:]]
+		if !ok {
+			return nil
+		}
+
+		if doc, ok = f.options[t.text]; !ok {
+			return nil
+		}
+
+		doc = "```mf2\n" + t.function + " " + t.text + "\n```\n\n" + doc
+	}
+
+	r := rangeOf(text, t.start, t.end)
+
+	return &hover{Range: &r, Contents: markupContent{Kind: "markdown", Value: doc}}
+}
+
+// declarationAt returns the range of the declaration of the variable at the position.
+func declarationAt(text string, pos position) (textRange, bool) {
+	tokens := tokenize(text)
+
+	t, ok := tokenAt(tokens, offsetOf(text, pos))
+	if !ok || t.kind != tokenVariable {
+		return textRange{}, false
+	}
+
+	for _, v := range tokens {
+		if v.kind == tokenVariable && v.declaration && v.text == t.text {
+			return rangeOf(text, v.start, v.end), true
+		}
+	}
+
+	return textRange{}, false
+}
+
+// format returns the edits to format the message, or no edits if the message is invalid.
+func format(text string) []textEdit {
+	tree, err := parse.Parse(text)
+	if err != nil {
+		return []textEdit{}
+	}
+
+	formatted := tree.String()
+	if formatted == text {
+		return []textEdit{}
+	}
+
+	return []textEdit{{Range: rangeOf(text, 0, len(text)), NewText: formatted}}
+}
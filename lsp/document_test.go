@@ -0,0 +1,205 @@
+package lsp
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	t.Parallel()
+
+	type tok struct {
+		text, function string
+		kind           tokenKind
+		declaration    bool
+	}
+
+	for _, test := range []struct {
+		name, text string
+		want       []tok
+	}{
+		{
+			name: "simple message",
+			text: `Hello, $name: { $name :string u:dir=ltr } { |$x :y| } \{ $z { #b a=b @c=d }`,
+			want: []tok{
+				{text: "$name", kind: tokenVariable},
+				{text: ":string", kind: tokenFunction},
+				{text: "u:dir", kind: tokenOption, function: ":string"},
+			},
+		},
+		{
+			name: "complex message",
+			text: ".input { $n :number minimumFractionDigits=1 }\n.local $x = { $n }\n.match { $x } * {{{ $n } $x }}",
+			want: []tok{
+				{text: ".input", kind: tokenKeyword},
+				{text: "$n", kind: tokenVariable, declaration: true},
+				{text: ":number", kind: tokenFunction},
+				{text: "minimumFractionDigits", kind: tokenOption, function: ":number"},
+				{text: ".local", kind: tokenKeyword},
+				{text: "$x", kind: tokenVariable, declaration: true},
+				{text: "$n", kind: tokenVariable},
+				{text: ".match", kind: tokenKeyword},
+				{text: "$x", kind: tokenVariable},
+				{text: "$n", kind: tokenVariable},
+			},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			var got []tok
+
+			for _, v := range tokenize(test.text) {
+				if test.text[v.start:v.end] != v.text {
+					t.Errorf("want '%s' at %d, got '%s'", v.text, v.start, test.text[v.start:v.end])
+				}
+
+				got = append(got, tok{text: v.text, function: v.function, kind: v.kind, declaration: v.declaration})
+			}
+
+			if !reflect.DeepEqual(test.want, got) {
+				t.Errorf("want %v, got %v", test.want, got)
+			}
+		})
+	}
+}
+
+func TestPosition(t *testing.T) {
+	t.Parallel()
+
+	text := "a😀b\nāc"
+
+	for _, test := range []struct {
+		pos    position
+		offset int
+	}{
+		{pos: position{Line: 0, Character: 0}, offset: 0},
+		{pos: position{Line: 0, Character: 1}, offset: 1},
+		{pos: position{Line: 0, Character: 3}, offset: 5}, // surrogate pair
+		{pos: position{Line: 0, Character: 4}, offset: 6},
+		{pos: position{Line: 1, Character: 1}, offset: 9},
+		{pos: position{Line: 1, Character: 2}, offset: 10},
+	} {
+		if got := offsetOf(text, test.pos); test.offset != got {
+			t.Errorf("%v: want %d, got %d", test.pos, test.offset, got)
+		}
+
+		if got := positionOf(text, test.offset); test.pos != got {
+			t.Errorf("%d: want %v, got %v", test.offset, test.pos, got)
+		}
+	}
+}
+
+func TestDiagnose(t *testing.T) {
+	t.Parallel()
+
+	got := diagnose("{ $x :unknown }\n{ $y")
+
+	want := []diagnostic{
+		{
+			Range:    textRange{End: position{Line: 1, Character: 4}},
+			Severity: severityError,
+			Code:     "syntax-error",
+			Source:   "mf2",
+			Message:  got[0].Message,
+		},
+		{
+			Range:    textRange{Start: position{Character: 5}, End: position{Character: 13}},
+			Severity: severityWarning,
+			Code:     "unknown-function",
+			Source:   "mf2",
+			Message:  `unknown function ":unknown"`,
+		},
+	}
+
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+
+	if got := diagnose("{ $x :number }"); len(got) != 0 {
+		t.Errorf("want no diagnostics, got %v", got)
+	}
+
+	got = diagnose(".match {$n :number} 1 {{1}} |1.0| {{1.0}} many {{many}} * {{other}}")
+
+	if len(got) != 1 || got[0].Message != `unreachable variant |1.0|: key "1.0" is shadowed by the equal key "1"` {
+		t.Errorf("want unreachable variant warning, got %v", got)
+	}
+}
+
+func TestHover(t *testing.T) {
+	t.Parallel()
+
+	text := "{ $x :number style=percent } { $y :unknown }"
+
+	for _, test := range []struct {
+		want string
+		pos  position
+	}{
+		{pos: position{Character: 7}, want: "```mf2\n:number\n```\n\n" + functions["number"].doc},
+		{pos: position{Character: 15}, want: "```mf2\n:number style\n```\n\n" + functions["number"].options["style"]},
+		{pos: position{Character: 3}},  // variable
+		{pos: position{Character: 37}}, // unknown function
+		{pos: position{Character: 27}}, // whitespace
+	} {
+		got := hoverAt(text, test.pos)
+
+		switch {
+		case test.want == "" && got != nil:
+			t.Errorf("%v: want nil, got '%s'", test.pos, got.Contents.Value)
+		case test.want != "" && got == nil:
+			t.Errorf("%v: want '%s', got nil", test.pos, test.want)
+		case got != nil && test.want != got.Contents.Value:
+			t.Errorf("%v: want '%s', got '%s'", test.pos, test.want, got.Contents.Value)
+		}
+	}
+}
+
+func TestDeclaration(t *testing.T) {
+	t.Parallel()
+
+	text := ".input { $n :number }\n.local $x = { $n }\n{{{ $x } { $y }}}"
+
+	for _, test := range []struct {
+		pos  position
+		want textRange
+		ok   bool
+	}{
+		{
+			pos:  position{Line: 2, Character: 5},
+			want: textRange{Start: position{Line: 1, Character: 7}, End: position{Line: 1, Character: 9}},
+			ok:   true,
+		},
+		{
+			pos:  position{Line: 1, Character: 15},
+			want: textRange{Start: position{Character: 9}, End: position{Character: 11}},
+			ok:   true,
+		},
+		{pos: position{Line: 2, Character: 12}}, // undeclared
+		{pos: position{Line: 2, Character: 1}},  // pattern
+	} {
+		got, ok := declarationAt(text, test.pos)
+		if test.ok != ok || test.want != got {
+			t.Errorf("%v: want %v %t, got %v %t", test.pos, test.want, test.ok, got, ok)
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	t.Parallel()
+
+	got := format(".input {$n :number}\n.match {$n}\none {{one}}\n* {{other}}")
+
+	want := []textEdit{{
+		Range:   textRange{End: position{Line: 3, Character: 11}},
+		NewText: ".input { $n :number }\n.match { $n }\none {{one}}\n* {{other}}",
+	}}
+
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+
+	if got := format("{ $x"); len(got) != 0 {
+		t.Errorf("want no edits for invalid message, got %v", got)
+	}
+}
@@ -0,0 +1,113 @@
+package lsp
+
+// function is the documentation of the function and its options.
+type function struct {
+	options map[string]string
+	doc     string
+}
+
+// functions documents the functions of the default registry, see [template.NewRegistry].
+var functions = map[string]function{
+	"string": {
+		doc: "Formats the operand as a string and selects by exact match of the string value. A list operand is formatted as a list, e.g. `a, b, and c`.",
+	},
+	"number": {
+		doc: "Formats the operand as a number and selects by plural category or exact value.",
+		options: map[string]string{
+			"select":                   "Selection: `plural` (default), `ordinal` or `exact`.",
+			"compactDisplay":           "Compact notation display: `short` (default) or `long`.",
+			"notation":                 "Notation: `standard` (default), `scientific`, `engineering` or `compact`.",
+			"numberingSystem":          "Numbering system, e.g. `latn` or `arab`.",
+			"signDisplay":              "Sign display: `auto` (default), `always`, `exceptZero`, `negative` or `never`.",
+			"style":                    "Style: `decimal` (default) or `percent`.",
+			"useGrouping":              "Grouping separators: `auto` (default), `always`, `never` or `min2`.",
+			"minimumIntegerDigits":     "Minimum number of integer digits, at least 1.",
+			"minimumFractionDigits":    "Minimum number of fraction digits.",
+			"maximumFractionDigits":    "Maximum number of fraction digits.",
+			"minimumSignificantDigits": "Minimum number of significant digits, at least 1.",
+			"maximumSignificantDigits": "Maximum number of significant digits.",
+			"minimumGroupingDigits":    "Minimum digits of the first group to use grouping, the locale default if not set.",
+			"skeleton":                 "ICU number skeleton expanded to the equivalent options, e.g. `|.00 group-off|`. The other options take precedence.",
+		},
+	},
+	"integer": {
+		doc: "Formats the operand as an integer and selects by plural category or exact value.",
+		options: map[string]string{
+			"select":               "Selection: `plural` (default), `ordinal` or `exact`.",
+			"numberingSystem":      "Numbering system, e.g. `latn` or `arab`.",
+			"signDisplay":          "Sign display: `auto` (default), `always`, `exceptZero`, `negative` or `never`.",
+			"style":                "Style: `decimal` (default) or `percent`.",
+			"useGrouping":          "Grouping separators: `auto` (default), `always`, `never` or `min2`.",
+			"minimumIntegerDigits": "Minimum number of integer digits, at least 1.",
+			"skeleton":             "ICU number skeleton expanded to the equivalent options, e.g. `|group-off|`. The other options take precedence.",
+		},
+	},
+	"boolean": {
+		doc: "Normalizes a boolean-ish operand (`true`/`false`, `yes`/`no`, `on`/`off`, `1`/`0`) and selects the `true` or `false` variant, e.g. `.match {$isAdmin :boolean}`.",
+	},
+	"percent": {
+		doc: "Formats the operand in percent points as a percentage, e.g. `45%` for 45, the same as `:number style=percent` for the ratio 0.45. The other options are the options of `:number`, except `style`.",
+		options: map[string]string{
+			"scale": "Multiplier of the operand to percent points, e.g. `100` for the ratio. Default is `1`.",
+		},
+	},
+	"range": {
+		doc: "Formats the range of numbers, e.g. `2–4`, with the locale range pattern, and selects by the CLDR plural category of the range, e.g. `few` for `2–4` in Russian. The operand is a two-element list, or the options `start` and `end`. The other options are the options of `:number`.",
+		options: map[string]string{
+			"start": "Start of the range, if there is no operand.",
+			"end":   "End of the range, if there is no operand.",
+		},
+	},
+	"count": {
+		doc: "Formats the length of the list or map operand as an integer and selects by plural category or exact value, e.g. `.match {$items :count} 0 {{No items}} one {{One item}} * {{{$items :count} items}}`. The options are the options of `:integer`.",
+	},
+	"casing": {
+		doc: "Transforms the case of the formatted operand for the locale, e.g. `:u:casing style=upper`.",
+		options: map[string]string{
+			"style": "Case: `upper`, `lower` or `title`.",
+		},
+	},
+	"upper": {
+		doc: "Transforms the formatted operand to upper case for the locale, the same as `:u:casing style=upper`.",
+	},
+	"lower": {
+		doc: "Transforms the formatted operand to lower case for the locale, the same as `:u:casing style=lower`.",
+	},
+	"date": {
+		doc: "Formats the date of the operand.",
+		options: map[string]string{
+			"style": "Style: `full`, `long`, `medium` or `short` (default).",
+		},
+	},
+	"time": {
+		doc: "Formats the time of the operand.",
+		options: map[string]string{
+			"style":     "Style: `full`, `long`, `medium` or `short` (default).",
+			"hourCycle": "Hour cycle: `h11`, `h12`, `h23` or `h24`, `h23` if not set.",
+			"hour12":    "12-hour clock: `true` or `false`, wins over `hourCycle`.",
+		},
+	},
+	"datetime": {
+		doc: "Formats the date and time of the operand, either with styles or with date and time fields.",
+		options: map[string]string{
+			"dateStyle":              "Date style: `full`, `long`, `medium` or `short`.",
+			"timeStyle":              "Time style: `full`, `long`, `medium` or `short`.",
+			"hourCycle":              "Hour cycle: `h11`, `h12`, `h23` or `h24`, `h23` if not set.",
+			"hour12":                 "12-hour clock: `true` or `false`, wins over `hourCycle`.",
+			"dayPeriod":              "Day period: `short` or `long`.",
+			"weekday":                "Weekday: `narrow`, `short` or `long`.",
+			"era":                    "Era: `narrow`, `short` or `long`, in the locales with the era names.",
+			"year":                   "Year: `numeric` or `2-digit`.",
+			"month":                  "Month: `numeric`, `2-digit`, `narrow`, `short` or `long`.",
+			"day":                    "Day: `numeric` or `2-digit`.",
+			"hour":                   "Hour: `numeric` or `2-digit`.",
+			"minute":                 "Minute: `numeric` or `2-digit`.",
+			"second":                 "Second: `numeric` or `2-digit`.",
+			"fractionalSecondDigits": "Number of fractional second digits: `1`, `2` or `3`.",
+			"timeZoneName": "Time zone name: `long`, `short`, `shortOffset`, `longOffset`, " +
+				"`shortGeneric` or `longGeneric`.",
+			"skeleton": "CLDR date-time skeleton formatted with the locale's best pattern, e.g. `|yMMMd jm|`, " +
+				"instead of the styles and fields.",
+		},
+	},
+}
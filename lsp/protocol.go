@@ -0,0 +1,165 @@
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/textproto"
+	"strconv"
+
+	"go.expect.digital/mf2"
+)
+
+// JSON-RPC 2.0 error codes.
+const (
+	codeParseError     = -32700
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+)
+
+// message is JSON-RPC 2.0 request, response or notification.
+// Notifications have no ID, responses have no method.
+type message struct {
+	ID     json.RawMessage `json:"id,omitempty"`
+	Params json.RawMessage `json:"params,omitempty"`
+	Result json.RawMessage `json:"result,omitempty"`
+	Error  *responseError  `json:"error,omitempty"`
+	RPC    string          `json:"jsonrpc"`
+	Method string          `json:"method,omitempty"`
+}
+
+type responseError struct {
+	Message string `json:"message"`
+	Code    int    `json:"code"`
+}
+
+func (e *responseError) Error() string {
+	return e.Message
+}
+
+// readMessage reads the message framed with the "Content-Length" header.
+func readMessage(r *bufio.Reader) ([]byte, error) {
+	header, err := textproto.NewReader(r).ReadMIMEHeader()
+	if err != nil {
+		return nil, fmt.Errorf("read header: %w", err)
+	}
+
+	length, err := strconv.Atoi(header.Get("Content-Length"))
+	if err != nil {
+		return nil, fmt.Errorf("read header: Content-Length: %w", err)
+	}
+
+	if length < 0 {
+		return nil, errors.New("read header: negative Content-Length")
+	}
+
+	b := make([]byte, length)
+
+	if _, err := io.ReadFull(r, b); err != nil {
+		return nil, fmt.Errorf("read content: %w", err)
+	}
+
+	return b, nil
+}
+
+// writeMessage writes the message framed with the "Content-Length" header.
+func writeMessage(w io.Writer, msg message) error {
+	msg.RPC = "2.0"
+
+	b, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("write message: %w", err)
+	}
+
+	if _, err := fmt.Fprintf(w, "Content-Length: %d\r\n\r\n%s", len(b), b); err != nil {
+		return fmt.Errorf("write message: %w", err)
+	}
+
+	return nil
+}
+
+// ---------------------------------LSP types---------------------------------
+
+// Position is zero-based line and character offset in UTF-16 code units.
+type position struct {
+	Line      int `json:"line"`
+	Character int `json:"character"`
+}
+
+type textRange struct {
+	Start position `json:"start"`
+	End   position `json:"end"`
+}
+
+type location struct {
+	URI   string    `json:"uri"`
+	Range textRange `json:"range"`
+}
+
+type textDocumentIdentifier struct {
+	URI string `json:"uri"`
+}
+
+type textDocumentPositionParams struct {
+	TextDocument textDocumentIdentifier `json:"textDocument"`
+	Position     position               `json:"position"`
+}
+
+type didOpenParams struct {
+	TextDocument struct {
+		URI  string `json:"uri"`
+		Text string `json:"text"`
+	} `json:"textDocument"`
+}
+
+type didChangeParams struct {
+	TextDocument   textDocumentIdentifier `json:"textDocument"`
+	ContentChanges []struct {
+		Text string `json:"text"`
+	} `json:"contentChanges"`
+}
+
+type didCloseParams struct {
+	TextDocument textDocumentIdentifier `json:"textDocument"`
+}
+
+type documentFormattingParams struct {
+	TextDocument textDocumentIdentifier `json:"textDocument"`
+}
+
+// Diagnostic severities, see [mf2.Severity].
+const (
+	severityError   = int(mf2.SeverityError)
+	severityWarning = int(mf2.SeverityWarning)
+)
+
+type diagnostic struct {
+	Code     string    `json:"code,omitempty"`
+	Source   string    `json:"source"`
+	Message  string    `json:"message"`
+	Range    textRange `json:"range"`
+	Severity int       `json:"severity"`
+}
+
+type publishDiagnosticsParams struct {
+	URI         string       `json:"uri"`
+	Diagnostics []diagnostic `json:"diagnostics"`
+}
+
+type markupContent struct {
+	Kind  string `json:"kind"`
+	Value string `json:"value"`
+}
+
+type hover struct {
+	Range    *textRange    `json:"range,omitempty"`
+	Contents markupContent `json:"contents"`
+}
+
+type textEdit struct {
+	NewText string    `json:"newText"`
+	Range   textRange `json:"range"`
+}
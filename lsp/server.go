@@ -0,0 +1,210 @@
+/*
+Package lsp implements the [Language Server Protocol] for MF2 messages.
+
+The server supports:
+
+  - diagnostics of syntax and data model errors, and unreachable variants
+  - hover for functions and their options of the default registry
+  - go to declaration of variables
+  - formatting of the message
+
+The documents are synchronised in full, every document is a single MF2 message.
+
+[Language Server Protocol]: https://microsoft.github.io/language-server-protocol/
+*/
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// server is the MF2 language server.
+type server struct {
+	w         io.Writer
+	documents map[string]string // URI -> text
+	shutdown  bool
+}
+
+// Serve reads the client messages from r and writes the server messages to w until the "exit" notification.
+func Serve(r io.Reader, w io.Writer) error {
+	s := &server{w: w, documents: make(map[string]string)}
+
+	return s.serve(bufio.NewReader(r))
+}
+
+func (s *server) serve(r *bufio.Reader) error {
+	for {
+		b, err := readMessage(r)
+		if err != nil {
+			return fmt.Errorf("serve: %w", err)
+		}
+
+		var msg message
+
+		if err := json.Unmarshal(b, &msg); err != nil {
+			if err := s.respond(nil, nil, &responseError{Code: codeParseError, Message: err.Error()}); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if msg.Method == "exit" {
+			if !s.shutdown {
+				return errors.New("serve: exit without shutdown")
+			}
+
+			return nil
+		}
+
+		result, err := s.handle(msg)
+
+		var respErr *responseError
+
+		switch {
+		case errors.As(err, &respErr):
+		case err != nil:
+			return fmt.Errorf("serve: %w", err)
+		}
+
+		// notifications have no response
+		if msg.ID == nil {
+			continue
+		}
+
+		if err := s.respond(msg.ID, result, respErr); err != nil {
+			return fmt.Errorf("serve: %w", err)
+		}
+	}
+}
+
+func (s *server) respond(id json.RawMessage, result any, respErr *responseError) error {
+	msg := message{ID: id, Error: respErr}
+
+	if id == nil {
+		msg.ID = json.RawMessage("null")
+	}
+
+	if respErr == nil {
+		b, err := json.Marshal(result)
+		if err != nil {
+			return fmt.Errorf("respond: %w", err)
+		}
+
+		msg.Result = b
+	}
+
+	return writeMessage(s.w, msg)
+}
+
+func (s *server) notify(method string, params any) error {
+	b, err := json.Marshal(params)
+	if err != nil {
+		return fmt.Errorf("notify: %w", err)
+	}
+
+	return writeMessage(s.w, message{Method: method, Params: b})
+}
+
+// handle handles the request or notification and returns the result.
+// The error is either *responseError for the client or the error of writing a notification.
+func (s *server) handle(msg message) (any, error) {
+	decode := func(v any) error {
+		if err := json.Unmarshal(msg.Params, v); err != nil {
+			return &responseError{Code: codeInvalidParams, Message: err.Error()}
+		}
+
+		return nil
+	}
+
+	switch msg.Method {
+	default:
+		return nil, &responseError{Code: codeMethodNotFound, Message: fmt.Sprintf(`method "%s" not found`, msg.Method)}
+	case "":
+		return nil, &responseError{Code: codeInvalidRequest, Message: "missing method"}
+	case "initialize":
+		return map[string]any{
+			"capabilities": map[string]any{
+				"textDocumentSync":           1, // full
+				"hoverProvider":              true,
+				"definitionProvider":         true,
+				"declarationProvider":        true,
+				"documentFormattingProvider": true,
+			},
+			"serverInfo": map[string]string{"name": "mf2"},
+		}, nil
+	case "initialized", "$/cancelRequest", "$/setTrace", "workspace/didChangeConfiguration":
+		return nil, nil
+	case "shutdown":
+		s.shutdown = true
+		return nil, nil
+	case "textDocument/didOpen":
+		var params didOpenParams
+		if err := decode(&params); err != nil {
+			return nil, err
+		}
+
+		return nil, s.update(params.TextDocument.URI, params.TextDocument.Text)
+	case "textDocument/didChange":
+		var params didChangeParams
+		if err := decode(&params); err != nil {
+			return nil, err
+		}
+
+		if n := len(params.ContentChanges); n > 0 {
+			return nil, s.update(params.TextDocument.URI, params.ContentChanges[n-1].Text)
+		}
+
+		return nil, nil
+	case "textDocument/didClose":
+		var params didCloseParams
+		if err := decode(&params); err != nil {
+			return nil, err
+		}
+
+		delete(s.documents, params.TextDocument.URI)
+
+		return nil, nil
+	case "textDocument/hover":
+		var params textDocumentPositionParams
+		if err := decode(&params); err != nil {
+			return nil, err
+		}
+
+		if h := hoverAt(s.documents[params.TextDocument.URI], params.Position); h != nil {
+			return h, nil
+		}
+
+		return nil, nil
+	case "textDocument/definition", "textDocument/declaration":
+		var params textDocumentPositionParams
+		if err := decode(&params); err != nil {
+			return nil, err
+		}
+
+		r, ok := declarationAt(s.documents[params.TextDocument.URI], params.Position)
+		if !ok {
+			return nil, nil
+		}
+
+		return location{URI: params.TextDocument.URI, Range: r}, nil
+	case "textDocument/formatting":
+		var params documentFormattingParams
+		if err := decode(&params); err != nil {
+			return nil, err
+		}
+
+		return format(s.documents[params.TextDocument.URI]), nil
+	}
+}
+
+// update stores the document text and publishes the diagnostics.
+func (s *server) update(uri, text string) error {
+	s.documents[uri] = text
+
+	return s.notify("textDocument/publishDiagnostics", publishDiagnosticsParams{URI: uri, Diagnostics: diagnose(text)})
+}
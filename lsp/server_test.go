@@ -0,0 +1,113 @@
+package lsp
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+func TestServe(t *testing.T) {
+	t.Parallel()
+
+	var in, out bytes.Buffer
+
+	send := func(id int, method string, params any) {
+		msg := map[string]any{"jsonrpc": "2.0", "method": method, "params": params}
+		if id > 0 {
+			msg["id"] = id
+		}
+
+		b, err := json.Marshal(msg)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		fmt.Fprintf(&in, "Content-Length: %d\r\n\r\n%s", len(b), b)
+	}
+
+	const uri = "file:///message.mf2"
+
+	doc := map[string]string{"uri": uri}
+
+	send(1, "initialize", map[string]any{})
+	send(0, "initialized", map[string]any{})
+	send(0, "textDocument/didOpen", map[string]any{"textDocument": map[string]string{"uri": uri, "text": "{ $x"}})
+	send(0, "textDocument/didChange", map[string]any{
+		"textDocument":   doc,
+		"contentChanges": []map[string]string{{"text": ".input {$x :number}\n{{{ $x }}}"}},
+	})
+	send(2, "textDocument/hover", map[string]any{"textDocument": doc, "position": position{Line: 0, Character: 12}})
+	send(3, "textDocument/definition", map[string]any{"textDocument": doc, "position": position{Line: 1, Character: 4}})
+	send(4, "textDocument/formatting", map[string]any{"textDocument": doc})
+	send(5, "unknown", nil)
+	send(6, "shutdown", nil)
+	send(0, "exit", nil)
+
+	if err := Serve(&in, &out); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []message
+
+	r := bufio.NewReader(&out)
+
+	for r.Buffered() > 0 || out.Len() > 0 {
+		b, err := readMessage(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var msg message
+
+		if err := json.Unmarshal(b, &msg); err != nil {
+			t.Fatal(err)
+		}
+
+		got = append(got, msg)
+	}
+
+	// initialize, 2x diagnostics, hover, definition, formatting, unknown, shutdown
+	if len(got) != 8 {
+		t.Fatalf("want 8 messages, got %d", len(got))
+	}
+
+	var diagnostics publishDiagnosticsParams
+
+	if err := json.Unmarshal(got[1].Params, &diagnostics); err != nil {
+		t.Fatal(err)
+	}
+
+	if got[1].Method != "textDocument/publishDiagnostics" || len(diagnostics.Diagnostics) != 1 {
+		t.Errorf("want syntax error diagnostic, got %s", got[1].Params)
+	}
+
+	if err := json.Unmarshal(got[2].Params, &diagnostics); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(diagnostics.Diagnostics) != 0 {
+		t.Errorf("want no diagnostics, got %s", got[2].Params)
+	}
+
+	for i, want := range []string{
+		`{"range":{"start":{"line":0,"character":11},"end":{"line":0,"character":18}},` +
+			`"contents":{"kind":"markdown","value":"` + "```mf2\\n:number\\n```\\n\\n" + functions["number"].doc + `"}}`,
+		`{"uri":"file:///message.mf2","range":{"start":{"line":0,"character":8},"end":{"line":0,"character":10}}}`,
+		`[{"newText":".input { $x :number }\n{{{ $x }}}","range":{"start":{"line":0,"character":0},` +
+			`"end":{"line":1,"character":10}}}]`,
+	} {
+		if msg := got[3+i]; want != string(msg.Result) {
+			t.Errorf("want '%s', got '%s'", want, msg.Result)
+		}
+	}
+
+	if got[6].Error == nil || got[6].Error.Code != codeMethodNotFound {
+		t.Errorf("want method not found, got %v", got[6].Error)
+	}
+
+	if string(got[7].ID) != "6" || string(got[7].Result) != "null" {
+		t.Errorf("want shutdown response, got %v", got[7])
+	}
+}
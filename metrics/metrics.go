@@ -0,0 +1,286 @@
+// Package metrics collects the metrics of MF2 templates and exports them with [expvar]
+// or in the Prometheus text exposition format, without the dependency on the Prometheus client.
+//
+// The [Collector] implements [template.Metrics], the templates report to it with [template.WithMetrics].
+// The other metrics backends implement [template.Metrics] the same way, e.g. with the Prometheus client.
+//
+//	c := metrics.New()
+//
+//	b := bundle.New(language.English,
+//		bundle.WithTemplateOptions(template.WithMetrics(c)),
+//		bundle.WithRenderCache(1000, time.Minute))
+//
+//	c.AddCache("render", b.RenderCacheStats)
+//
+//	c.Publish("mf2")           // expvar, served at /debug/vars
+//	http.Handle("/metrics", c) // Prometheus
+package metrics
+
+import (
+	"expvar"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.expect.digital/mf2/internal/mapkeys"
+	"go.expect.digital/mf2/template"
+)
+
+// latencyBuckets are the upper bounds of the execution latency histogram buckets.
+var latencyBuckets = []time.Duration{
+	10 * time.Microsecond,
+	50 * time.Microsecond,
+	100 * time.Microsecond,
+	500 * time.Microsecond,
+	time.Millisecond,
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	100 * time.Millisecond,
+}
+
+// Collector counts the parses, executions and fallbacks of the templates, and the hits and misses
+// of the caches. It is safe for concurrent use.
+type Collector struct {
+	fallbacks       map[string]uint64
+	caches          map[string]func() (hits, misses uint64)
+	latencyBuckets  []atomic.Uint64 // cumulative, as in Prometheus
+	parses          atomic.Uint64
+	parseErrors     atomic.Uint64
+	executions      atomic.Uint64
+	executionErrors atomic.Uint64
+	latencySum      atomic.Int64
+	mu              sync.Mutex
+}
+
+var _ template.Metrics = (*Collector)(nil)
+
+// New returns a new collector.
+func New() *Collector {
+	return &Collector{
+		fallbacks:      make(map[string]uint64),
+		caches:         make(map[string]func() (hits, misses uint64)),
+		latencyBuckets: make([]atomic.Uint64, len(latencyBuckets)),
+	}
+}
+
+// Parsed implements [template.Metrics].
+func (c *Collector) Parsed(_ time.Duration, err error) {
+	c.parses.Add(1)
+
+	if err != nil {
+		c.parseErrors.Add(1)
+	}
+}
+
+// Executed implements [template.Metrics].
+func (c *Collector) Executed(duration time.Duration, err error) {
+	c.executions.Add(1)
+	c.latencySum.Add(int64(duration))
+
+	if err != nil {
+		c.executionErrors.Add(1)
+	}
+
+	for i, le := range latencyBuckets {
+		if duration <= le {
+			c.latencyBuckets[i].Add(1)
+		}
+	}
+}
+
+// Fallback implements [template.Metrics].
+func (c *Collector) Fallback(code string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.fallbacks[code]++
+}
+
+// AddCache adds the cache by the name, e.g. "parse", the stats are read on each export,
+// e.g. [parse.Cache.Stats] or [bundle.Bundle.RenderCacheStats].
+//
+// [parse.Cache.Stats]: https://pkg.go.dev/go.expect.digital/mf2/parse#Cache.Stats
+// [bundle.Bundle.RenderCacheStats]: https://pkg.go.dev/go.expect.digital/mf2/bundle#Bundle.RenderCacheStats
+func (c *Collector) AddCache(name string, stats func() (hits, misses uint64)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.caches[name] = stats
+}
+
+// Bucket is the bucket of the [Histogram], the number of observations less than or equal to the bound.
+type Bucket struct {
+	UpperBound time.Duration `json:"upperBound"`
+	Count      uint64        `json:"count"`
+}
+
+// Histogram is the distribution of the durations, the buckets are cumulative.
+type Histogram struct {
+	Buckets []Bucket      `json:"buckets"`
+	Count   uint64        `json:"count"`
+	Sum     time.Duration `json:"sum"`
+}
+
+// CacheStats are the hits and misses of the cache, and the hit rate from 0 to 1.
+type CacheStats struct {
+	Hits    uint64  `json:"hits"`
+	Misses  uint64  `json:"misses"`
+	HitRate float64 `json:"hitRate"`
+}
+
+// Snapshot are the metrics at the moment.
+type Snapshot struct {
+	Fallbacks       map[string]uint64     `json:"fallbacks"`
+	Caches          map[string]CacheStats `json:"caches"`
+	ExecuteLatency  Histogram             `json:"executeLatency"`
+	Parses          uint64                `json:"parses"`
+	ParseErrors     uint64                `json:"parseErrors"`
+	Executions      uint64                `json:"executions"`
+	ExecutionErrors uint64                `json:"executionErrors"`
+}
+
+// Snapshot returns the current metrics.
+func (c *Collector) Snapshot() Snapshot {
+	s := Snapshot{
+		ExecuteLatency: Histogram{
+			Buckets: make([]Bucket, len(latencyBuckets)),
+			Sum:     time.Duration(c.latencySum.Load()),
+		},
+		Caches: make(map[string]CacheStats),
+	}
+
+	for i, le := range latencyBuckets {
+		s.ExecuteLatency.Buckets[i] = Bucket{UpperBound: le, Count: c.latencyBuckets[i].Load()}
+	}
+
+	// loaded after the buckets, the executions are counted before the buckets,
+	// so that the count is not less than any bucket
+	s.Parses = c.parses.Load()
+	s.ParseErrors = c.parseErrors.Load()
+	s.Executions = c.executions.Load()
+	s.ExecutionErrors = c.executionErrors.Load()
+	s.ExecuteLatency.Count = s.Executions
+
+	c.mu.Lock()
+
+	s.Fallbacks = make(map[string]uint64, len(c.fallbacks))
+
+	for code, n := range c.fallbacks {
+		s.Fallbacks[code] = n
+	}
+
+	caches := make(map[string]func() (uint64, uint64), len(c.caches))
+
+	for name, stats := range c.caches {
+		caches[name] = stats
+	}
+
+	c.mu.Unlock()
+
+	// the stats are read outside the lock, the caches have their own
+	for name, stats := range caches {
+		hits, misses := stats()
+		cache := CacheStats{Hits: hits, Misses: misses}
+
+		if hits+misses > 0 {
+			cache.HitRate = float64(hits) / float64(hits+misses)
+		}
+
+		s.Caches[name] = cache
+	}
+
+	return s
+}
+
+// Publish publishes the snapshot of the metrics in [expvar] by the name, e.g. "mf2".
+// Like [expvar.Publish], it panics if the name is already registered.
+func (c *Collector) Publish(name string) {
+	expvar.Publish(name, expvar.Func(func() any { return c.Snapshot() }))
+}
+
+// ServeHTTP writes the metrics in the Prometheus text exposition format.
+func (c *Collector) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+
+	_ = c.WritePrometheus(w)
+}
+
+// WritePrometheus writes the metrics in the Prometheus text exposition format, the names have the prefix "mf2_".
+func (c *Collector) WritePrometheus(w io.Writer) error {
+	s := c.Snapshot()
+
+	var sb strings.Builder
+
+	counter := func(name, help string, v uint64) {
+		fmt.Fprintf(&sb, "# HELP %s %s\n# TYPE %s counter\n%s %d\n", name, help, name, name, v)
+	}
+
+	counter("mf2_parses_total", "Number of parsed messages.", s.Parses)
+	counter("mf2_parse_errors_total", "Number of messages failed to parse.", s.ParseErrors)
+	counter("mf2_executions_total", "Number of executed templates.", s.Executions)
+	counter("mf2_execution_errors_total", "Number of templates executed with errors.", s.ExecutionErrors)
+
+	sb.WriteString("# HELP mf2_execute_duration_seconds Latency of the template executions.\n")
+	sb.WriteString("# TYPE mf2_execute_duration_seconds histogram\n")
+
+	for _, b := range s.ExecuteLatency.Buckets {
+		fmt.Fprintf(&sb, "mf2_execute_duration_seconds_bucket{le=\"%s\"} %d\n", formatFloat(b.UpperBound.Seconds()), b.Count)
+	}
+
+	fmt.Fprintf(&sb, "mf2_execute_duration_seconds_bucket{le=\"+Inf\"} %d\n", s.ExecuteLatency.Count)
+	fmt.Fprintf(&sb, "mf2_execute_duration_seconds_sum %s\n", formatFloat(s.ExecuteLatency.Sum.Seconds()))
+	fmt.Fprintf(&sb, "mf2_execute_duration_seconds_count %d\n", s.ExecuteLatency.Count)
+
+	sb.WriteString("# HELP mf2_fallbacks_total Number of expressions resolved to the fallback by the error code.\n")
+	sb.WriteString("# TYPE mf2_fallbacks_total counter\n")
+
+	for _, code := range mapkeys.Sorted(s.Fallbacks) {
+		fmt.Fprintf(&sb, "mf2_fallbacks_total{code=\"%s\"} %d\n", escapeLabel(code), s.Fallbacks[code])
+	}
+
+	names := mapkeys.Sorted(s.Caches)
+
+	for _, metric := range []struct {
+		name, help, typ string
+		value           func(CacheStats) string
+	}{
+		{
+			name: "mf2_cache_hits_total", help: "Number of cache hits.", typ: "counter",
+			value: func(s CacheStats) string { return strconv.FormatUint(s.Hits, 10) },
+		},
+		{
+			name: "mf2_cache_misses_total", help: "Number of cache misses.", typ: "counter",
+			value: func(s CacheStats) string { return strconv.FormatUint(s.Misses, 10) },
+		},
+		{
+			name: "mf2_cache_hit_ratio", help: "Ratio of the cache hits to the lookups.", typ: "gauge",
+			value: func(s CacheStats) string { return formatFloat(s.HitRate) },
+		},
+	} {
+		fmt.Fprintf(&sb, "# HELP %s %s\n# TYPE %s %s\n", metric.name, metric.help, metric.name, metric.typ)
+
+		for _, name := range names {
+			fmt.Fprintf(&sb, "%s{cache=\"%s\"} %s\n", metric.name, escapeLabel(name), metric.value(s.Caches[name]))
+		}
+	}
+
+	if _, err := io.WriteString(w, sb.String()); err != nil {
+		return fmt.Errorf("write prometheus metrics: %w", err)
+	}
+
+	return nil
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+// escapeLabel escapes the label value of the Prometheus text format.
+func escapeLabel(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s)
+}
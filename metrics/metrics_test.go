@@ -0,0 +1,109 @@
+package metrics
+
+import (
+	"encoding/json"
+	"expvar"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go.expect.digital/mf2/parse"
+	"go.expect.digital/mf2/template"
+)
+
+func TestCollector(t *testing.T) {
+	t.Parallel()
+
+	c := New()
+	cache := parse.NewCache(10)
+
+	c.AddCache("parse", cache.Stats)
+
+	options := []template.Option{template.WithMetrics(c), template.WithParseCache(cache)}
+
+	if _, err := template.New(options...).Parse("{ $x"); err == nil {
+		t.Fatal("want parse error, got nil")
+	}
+
+	for range 2 {
+		tmpl, err := template.New(options...).Parse("Hello, { $name }!")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := tmpl.Sprint(nil); err == nil {
+			t.Fatal("want unresolved variable, got nil")
+		}
+	}
+
+	c.Executed(time.Millisecond, nil)
+
+	s := c.Snapshot()
+
+	if s.Parses != 3 || s.ParseErrors != 1 {
+		t.Errorf("want 3 parses and 1 parse error, got %d and %d", s.Parses, s.ParseErrors)
+	}
+
+	if s.Executions != 3 || s.ExecutionErrors != 2 || s.ExecuteLatency.Count != 3 {
+		t.Errorf("want 3 executions and 2 execution errors, got %d and %d", s.Executions, s.ExecutionErrors)
+	}
+
+	// all executions take less than 100ms
+	if got := s.ExecuteLatency.Buckets[len(latencyBuckets)-1].Count; got != 3 {
+		t.Errorf("want 3 executions in the last bucket, got %d", got)
+	}
+
+	if got := s.Fallbacks["unresolved-variable"]; got != 2 {
+		t.Errorf("want 2 unresolved variable fallbacks, got %d", got)
+	}
+
+	if want, got := (CacheStats{Hits: 1, Misses: 2, HitRate: 1. / 3}), s.Caches["parse"]; want != got {
+		t.Errorf("want %+v, got %+v", want, got)
+	}
+
+	w := httptest.NewRecorder()
+	c.ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+
+	for _, want := range []string{
+		"# TYPE mf2_parses_total counter\nmf2_parses_total 3\n",
+		"mf2_execution_errors_total 2\n",
+		"mf2_execute_duration_seconds_bucket{le=\"0.001\"} ",
+		"mf2_execute_duration_seconds_bucket{le=\"+Inf\"} 3\n",
+		"mf2_execute_duration_seconds_count 3\n",
+		"mf2_fallbacks_total{code=\"unresolved-variable\"} 2\n",
+		"mf2_cache_hits_total{cache=\"parse\"} 1\n",
+		"mf2_cache_misses_total{cache=\"parse\"} 2\n",
+		"# TYPE mf2_cache_hit_ratio gauge\nmf2_cache_hit_ratio{cache=\"parse\"} 0.3333333333333333\n",
+	} {
+		if !strings.Contains(w.Body.String(), want) {
+			t.Errorf("want '%s' in '%s'", want, w.Body.String())
+		}
+	}
+}
+
+func TestPublish(t *testing.T) {
+	t.Parallel()
+
+	c := New()
+	c.Parsed(0, nil)
+	c.Publish("mf2_test")
+
+	var s Snapshot
+
+	if err := json.Unmarshal([]byte(expvar.Get("mf2_test").String()), &s); err != nil {
+		t.Fatal(err)
+	}
+
+	if s.Parses != 1 {
+		t.Errorf("want 1 parse, got %d", s.Parses)
+	}
+}
+
+func TestEscapeLabel(t *testing.T) {
+	t.Parallel()
+
+	if want, got := `a\\b\"c\nd`, escapeLabel("a\\b\"c\nd"); want != got {
+		t.Errorf("want '%s', got '%s'", want, got)
+	}
+}
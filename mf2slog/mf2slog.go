@@ -0,0 +1,107 @@
+// Package mf2slog routes the fallback output of MF2 templates and the missing messages of bundles
+// to [log/slog] with consistent attribute names, see the Key constants.
+//
+// One line sets up the logging of the bundle:
+//
+//	b, err := bundle.Load("locales", language.English, mf2slog.BundleOptions(slog.Default())...)
+//
+// The record of the fallback output, e.g. the unresolved variable, is:
+//
+//	level=WARN msg="mf2: fallback output" message_id=greeting locale=lv expression="{ $name }"
+//	error_code=unresolved-variable error="expression: unresolved variable \"$name\""
+package mf2slog
+
+import (
+	"context"
+	"log/slog"
+
+	"golang.org/x/text/language"
+
+	"go.expect.digital/mf2/bundle"
+	"go.expect.digital/mf2/template"
+)
+
+// The attribute keys of the log records.
+const (
+	// KeyMessageID is the message ID, see [template.WithID].
+	KeyMessageID = "message_id"
+	// KeyLocale is the locale of the execution, or the requested locale of the missing message.
+	KeyLocale = "locale"
+	// KeyErrorCode is the MF2 error code, e.g. "unresolved-variable",
+	// or "missing-message" and "missing-locale" of the bundle.
+	KeyErrorCode = "error_code"
+	// KeyExpression is the expression resolved to the fallback, e.g. "{ $name }".
+	KeyExpression = "expression"
+	// KeyError is the resolution error.
+	KeyError = "error"
+	// KeyMatchedLocale is the bundle locale matching the requested locale of the missing message.
+	KeyMatchedLocale = "matched_locale"
+	// KeyUsedLocale is the locale of the message used instead of the missing message.
+	KeyUsedLocale = "used_locale"
+	// KeyPolicy is the missing policy of the bundle, e.g. "fallback".
+	KeyPolicy = "policy"
+)
+
+// Error codes of the missing messages and locales of the bundle.
+const (
+	CodeMissingMessage = "missing-message"
+	CodeMissingLocale  = "missing-locale"
+)
+
+// TemplateOption returns the template option logging the fallback output of the template as a warning,
+// see [template.WithErrorHandler].
+func TemplateOption(logger *slog.Logger) template.Option {
+	return template.WithErrorHandler(func(ctx context.Context, event template.FallbackEvent) {
+		if !logger.Enabled(ctx, slog.LevelWarn) {
+			return
+		}
+
+		logger.LogAttrs(ctx, slog.LevelWarn, "mf2: fallback output",
+			slog.String(KeyMessageID, event.ID),
+			slog.String(KeyLocale, event.Locale.String()),
+			slog.String(KeyExpression, event.Expression),
+			slog.String(KeyErrorCode, event.Code),
+			slog.Any(KeyError, event.Err),
+		)
+	})
+}
+
+// FallbackHandler returns the bundle fallback handler logging the missing messages and locales
+// as warnings, see [bundle.WithFallbackHandler].
+func FallbackHandler(logger *slog.Logger) func(f bundle.Fallback) {
+	return func(f bundle.Fallback) {
+		ctx := context.Background()
+
+		if !logger.Enabled(ctx, slog.LevelWarn) {
+			return
+		}
+
+		code := CodeMissingMessage
+		if f.Matched == language.Und {
+			code = CodeMissingLocale
+		}
+
+		logger.LogAttrs(ctx, slog.LevelWarn, "mf2: missing message",
+			slog.String(KeyMessageID, f.ID),
+			slog.String(KeyLocale, f.Locale.String()),
+			slog.String(KeyErrorCode, code),
+			slog.String(KeyMatchedLocale, f.Matched.String()),
+			slog.String(KeyUsedLocale, f.Used.String()),
+			slog.String(KeyPolicy, f.Policy.String()),
+		)
+	}
+}
+
+// BundleOptions returns the bundle options logging the fallback output of the templates and
+// the missing messages and locales. The template options are applied to every compiled template
+// together with [TemplateOption], they replace the options of [bundle.WithTemplateOptions].
+func BundleOptions(logger *slog.Logger, templateOptions ...template.Option) []bundle.Option {
+	options := make([]template.Option, 0, len(templateOptions)+1)
+	options = append(options, templateOptions...)
+	options = append(options, TemplateOption(logger))
+
+	return []bundle.Option{
+		bundle.WithTemplateOptions(options...),
+		bundle.WithFallbackHandler(FallbackHandler(logger)),
+	}
+}
@@ -0,0 +1,65 @@
+package mf2slog
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"golang.org/x/text/language"
+
+	"go.expect.digital/mf2/bundle"
+)
+
+func TestBundleOptions(t *testing.T) {
+	t.Parallel()
+
+	removeTime := func(_ []string, a slog.Attr) slog.Attr {
+		if a.Key == slog.TimeKey {
+			return slog.Attr{}
+		}
+
+		return a
+	}
+
+	var buf bytes.Buffer
+
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{ReplaceAttr: removeTime}))
+
+	fsys := fstest.MapFS{
+		"en.json": {Data: []byte(`{"locale": "en", "messages": {` +
+			`"greeting": {"message": "Hello, { $name }!"}, "bye": {"message": "Bye!"}}}`)},
+		"lv.json": {Data: []byte(`{"locale": "lv", "messages": {"greeting": {"message": "Sveiki, { $name }!"}}}`)},
+	}
+
+	b, err := bundle.LoadFS(fsys, language.English, BundleOptions(logger)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, _ = b.Sprint(language.Latvian, "greeting", nil)
+	_, _ = b.Sprint(language.Latvian, "bye", nil)
+	_, _ = b.Sprint(language.Japanese, "bye", nil)
+
+	want := []string{
+		`level=WARN msg="mf2: fallback output" message_id=greeting locale=lv expression="{ $name }" ` +
+			`error_code=unresolved-variable error="expression: unresolved variable \"$name\""`,
+		`level=WARN msg="mf2: missing message" message_id=bye locale=lv error_code=missing-message ` +
+			`matched_locale=lv used_locale=en policy=fallback`,
+		`level=WARN msg="mf2: missing message" message_id=bye locale=ja error_code=missing-locale ` +
+			`matched_locale=und used_locale=en policy=fallback`,
+	}
+
+	got := strings.Split(strings.TrimSpace(buf.String()), "\n")
+
+	if len(want) != len(got) {
+		t.Fatalf("want %d records, got %d: %s", len(want), len(got), buf.String())
+	}
+
+	for i := range want {
+		if want[i] != got[i] {
+			t.Errorf("want '%s', got '%s'", want[i], got[i])
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package parse
+
+import "sync"
+
+// slabSize is the number of elements in a slab of the [Arena].
+const slabSize = 1024
+
+// Arena allocates the slices of the parsed ASTs - patterns, options, attributes, variants, keys and
+// declarations - from the shared slabs, and reuses the token buffer of the parser, to reduce the number
+// of allocations and of the heap objects when parsing and holding very large catalogs in memory.
+//
+// The slabs are freed as a unit when none of the ASTs allocated from them is referenced, e.g. when
+// the catalog parsed with the arena is discarded. A single AST keeps its slabs alive, use one arena
+// per catalog, not per process:
+//
+//	arena := parse.NewArena()
+//	c := catalog.New(language.English, template.WithParseOptions(parse.WithArena(arena)))
+//
+// The arena is safe for concurrent use, the parses sharing the arena are serialized.
+// The slices of the AST have no spare capacity, appending to them does not overwrite the slab.
+type Arena struct {
+	parts        slab[PatternPart]
+	options      slab[Option]
+	attributes   slab[Attribute]
+	variants     slab[Variant]
+	keys         slab[VariantKey]
+	expressions  slab[Expression]
+	declarations slab[Declaration]
+	reserved     slab[ReservedBody]
+	// items is the token buffer reused by the parses.
+	items []item
+	// pattern is the pattern buffer reused by the parses, the patterns are not nested.
+	pattern []PatternPart
+	mu      sync.Mutex
+}
+
+// NewArena returns a new empty arena.
+func NewArena() *Arena {
+	return &Arena{}
+}
+
+// WithArena allocates the AST from the arena, see [Arena].
+func WithArena(arena *Arena) ParseOption {
+	return func(p *parser) {
+		p.arena = arena
+	}
+}
+
+// acquire locks the arena for the parse and lends its buffers to the parser.
+// The returned function returns the buffers and unlocks the arena.
+func (a *Arena) acquire(p *parser) func() {
+	a.mu.Lock()
+
+	p.items = a.items[:0]
+
+	return func() {
+		// the tokens are not kept, they reference the input
+		clear(p.items)
+		a.items = p.items[:0]
+
+		a.mu.Unlock()
+	}
+}
+
+// patternBuffer returns the empty pattern buffer of the arena, or nil without the arena.
+func (p *parser) patternBuffer() []PatternPart {
+	if p.arena == nil {
+		return nil
+	}
+
+	return p.arena.pattern[:0]
+}
+
+// ownPattern returns the pattern allocated from the arena and keeps the grown pattern buffer.
+func (p *parser) ownPattern(pattern []PatternPart) []PatternPart {
+	if p.arena == nil {
+		return pattern
+	}
+
+	owned := p.arena.parts.copy(pattern)
+
+	clear(pattern)
+	p.arena.pattern = pattern[:0]
+
+	return owned
+}
+
+// ownFunction returns the function with the options allocated from the arena.
+func (p *parser) ownFunction(function Function) Function {
+	if p.arena != nil {
+		function.Options = p.arena.options.copy(function.Options)
+	}
+
+	return function
+}
+
+// ownMarkup returns the markup with the options and attributes allocated from the arena.
+func (p *parser) ownMarkup(markup Markup) Markup {
+	if p.arena != nil {
+		markup.Options = p.arena.options.copy(markup.Options)
+		markup.Attributes = p.arena.attributes.copy(markup.Attributes)
+	}
+
+	return markup
+}
+
+// ownReservedBody returns the reserved body allocated from the arena.
+func (p *parser) ownReservedBody(body []ReservedBody) []ReservedBody {
+	if p.arena == nil {
+		return body
+	}
+
+	return p.arena.reserved.copy(body)
+}
+
+// slab allocates the slices of T from the chunks of [slabSize] elements.
+type slab[T any] struct {
+	chunk []T
+}
+
+// copy returns the copy of the src allocated from the slab, or nil if the src is empty.
+// The capacity of the copy is its length. The large slices are allocated separately.
+func (s *slab[T]) copy(src []T) []T {
+	if len(src) == 0 {
+		return nil
+	}
+
+	if len(src) > slabSize/8 { //nolint:mnd
+		return append([]T(nil), src...)
+	}
+
+	if cap(s.chunk)-len(s.chunk) < len(src) {
+		s.chunk = make([]T, 0, slabSize)
+	}
+
+	start := len(s.chunk)
+	s.chunk = append(s.chunk, src...)
+
+	return s.chunk[start:len(s.chunk):len(s.chunk)]
+}
@@ -0,0 +1,135 @@
+package parse
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestArena(t *testing.T) {
+	t.Parallel()
+
+	arena := NewArena()
+
+	for _, in := range []string{
+		"",
+		"Hello, World!",
+		"Hello, { $name :string u:locale=lv @attr=|x| @empty }!",
+		"{#bold size=2 @a=b}text{/bold}{#img/}",
+		"{ $x ^reserved |body| @a }",
+		".local $x = { 1 :number minimumFractionDigits=2 } {{{ $x }}}",
+		".input { $n :number } .reserved |body| { $n } .match { $n } { $x } 1 one {{one}} * * {{other { $n }}}",
+	} {
+		want, err := Parse(in, WithSpecVersion(SpecDraft2024))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := Parse(in, WithArena(arena))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !reflect.DeepEqual(want, got) {
+			t.Errorf("want %#v, got %#v", want, got)
+		}
+	}
+
+	// the syntax error is reported as without the arena
+	if _, err := Parse("{ $x", WithArena(arena)); err == nil {
+		t.Error("want syntax error, got nil")
+	}
+
+	// the slices of the AST do not share the capacity of the slab
+	tree, err := Parse("{ $a } { $b }", WithArena(arena))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	next, err := Parse("{ $c }", WithArena(arena))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pattern := tree.Message.(SimpleMessage) //nolint:forcetypeassert
+	_ = append(pattern, Text("x"))
+
+	if want, got := "{ $c }", next.String(); want != got {
+		t.Errorf("want '%s', got '%s'", want, got)
+	}
+}
+
+func TestArenaConcurrent(t *testing.T) {
+	t.Parallel()
+
+	arena := NewArena()
+
+	var wg sync.WaitGroup
+
+	for range 8 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for range 100 {
+				tree, err := Parse("Hello, { $name :string }!", WithArena(arena))
+				if err != nil {
+					t.Error(err)
+					return
+				}
+
+				if want, got := "Hello, { $name :string }!", tree.String(); want != got {
+					t.Errorf("want '%s', got '%s'", want, got)
+					return
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+}
+
+//nolint:paralleltest // testing.AllocsPerRun panics in parallel tests
+func TestArenaAllocs(t *testing.T) {
+	const in = ".input { $n :number } .match { $n } 1 {{one { $n }}} 2 {{two}} * {{other { $n } and { $n }}}"
+
+	arena := NewArena()
+
+	parse := func(options ...ParseOption) func() {
+		return func() {
+			if _, err := Parse(in, options...); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	without := testing.AllocsPerRun(100, parse())
+	with := testing.AllocsPerRun(100, parse(WithArena(arena)))
+
+	if with >= without {
+		t.Errorf("want fewer allocations with the arena, got %v with and %v without", with, without)
+	}
+}
+
+func BenchmarkArena(b *testing.B) {
+	const in = ".input { $n :number } .match { $n } 1 {{one { $n }}} 2 {{two}} * {{other { $n } and { $n }}}"
+
+	b.Run("without", func(b *testing.B) {
+		b.ReportAllocs()
+
+		for range b.N {
+			_, _ = Parse(in)
+		}
+	})
+
+	b.Run("with", func(b *testing.B) {
+		b.ReportAllocs()
+
+		arena := NewArena()
+
+		for range b.N {
+			_, _ = Parse(in, WithArena(arena))
+		}
+	})
+}
@@ -2,6 +2,7 @@ package parse
 
 import (
 	"fmt"
+	"io"
 	"strconv"
 	"strings"
 )
@@ -26,7 +27,23 @@ Example:
 
 	fmt.Print(ast) // Hello, { $variable } World!
 */
-func (a AST) String() string { return a.Message.String() }
+func (a AST) String() string {
+	if a.Message == nil {
+		return ""
+	}
+
+	return a.Message.String()
+}
+
+// WriteTo writes the MF2 formatted message to w. It implements [io.WriterTo].
+func (a AST) WriteTo(w io.Writer) (int64, error) {
+	n, err := io.WriteString(w, a.String())
+	if err != nil {
+		return int64(n), fmt.Errorf("write AST: %w", err)
+	}
+
+	return int64(n), nil
+}
 
 // --------------------------------Interfaces----------------------------------
 //
@@ -37,6 +54,8 @@ func (a AST) String() string { return a.Message.String() }
 // Node is the interface implemented by all AST nodes.
 type Node interface {
 	node()
+	// write writes MF2 formatted string of the node to sb, the nodes are serialised into a single builder.
+	write(sb *strings.Builder)
 
 	fmt.Stringer
 }
@@ -98,8 +117,10 @@ type ReservedBody interface {
 type SimpleMessage []PatternPart
 
 // String returns MF2 formatted string.
-func (m SimpleMessage) String() string {
-	return sliceToString(m, "")
+func (m SimpleMessage) String() string { return nodeString(m) }
+
+func (m SimpleMessage) write(sb *strings.Builder) {
+	writeSlice(sb, m, "")
 }
 
 func (m SimpleMessage) node()    {}
@@ -108,15 +129,21 @@ func (m SimpleMessage) message() {}
 type ComplexMessage struct {
 	ComplexBody  ComplexBody   // Matcher or QuotedPattern
 	Declarations []Declaration // Optional: InputDeclaration, LocalDeclaration or ReservedStatement
+	Span         Span          // Optional, see WithPositions
 }
 
 // String returns MF2 formatted string.
-func (m ComplexMessage) String() string {
-	if len(m.Declarations) == 0 {
-		return m.ComplexBody.String()
+func (m ComplexMessage) String() string { return nodeString(m) }
+
+func (m ComplexMessage) write(sb *strings.Builder) {
+	if len(m.Declarations) > 0 {
+		writeSlice(sb, m.Declarations, "\n")
+		sb.WriteByte('\n')
 	}
 
-	return sliceToString(m.Declarations, "\n") + "\n" + m.ComplexBody.String()
+	if m.ComplexBody != nil {
+		m.ComplexBody.write(sb)
+	}
 }
 
 func (m ComplexMessage) node()    {}
@@ -127,15 +154,10 @@ func (m ComplexMessage) message() {}
 type Text string
 
 // String returns MF2 formatted string.
-func (t Text) String() string {
-	// text-escape = backslash ( backslash / "{" / "}" )
-	r := strings.NewReplacer(
-		`\`, `\\`,
-		`{`, `\{`,
-		`}`, `\}`,
-	)
+func (t Text) String() string { return nodeString(t) }
 
-	return r.Replace(string(t))
+func (t Text) write(sb *strings.Builder) {
+	_, _ = textEscaper.WriteString(sb, string(t))
 }
 
 func (Text) node()        {}
@@ -147,29 +169,36 @@ type Expression struct {
 	Operand    Value       // Literal or Variable
 	Annotation Annotation  // Function, PrivateUseAnnotation or ReservedAnnotation
 	Attributes []Attribute // Optional
+	Span       Span        // Optional, see WithPositions
 }
 
 // String returns MF2 formatted string.
-func (e Expression) String() string {
-	var s []string
+func (e Expression) String() string { return nodeString(e) }
+
+func (e Expression) write(sb *strings.Builder) {
+	if e.Operand == nil && e.Annotation == nil && len(e.Attributes) == 0 {
+		sb.WriteString("{}")
+		return
+	}
+
+	sb.WriteByte('{')
 
 	if e.Operand != nil {
-		s = append(s, e.Operand.String())
+		sb.WriteByte(' ')
+		e.Operand.write(sb)
 	}
 
 	if e.Annotation != nil {
-		s = append(s, e.Annotation.String())
+		sb.WriteByte(' ')
+		e.Annotation.write(sb)
 	}
 
 	if len(e.Attributes) > 0 {
-		s = append(s, sliceToString(e.Attributes, " "))
+		sb.WriteByte(' ')
+		writeSlice(sb, e.Attributes, " ")
 	}
 
-	if len(s) == 0 {
-		return "{}"
-	}
-
-	return "{ " + strings.Join(s, " ") + " }"
+	sb.WriteString(" }")
 }
 
 func (Expression) node()        {}
@@ -180,14 +209,12 @@ func (Expression) patternPart() {}
 type QuotedLiteral string
 
 // String returns MF2 formatted string.
-func (l QuotedLiteral) String() string {
-	// quoted-escape = backslash ( backslash / "|" )
-	r := strings.NewReplacer(
-		`\`, `\\`,
-		`|`, `\|`,
-	)
+func (l QuotedLiteral) String() string { return nodeString(l) }
 
-	return "|" + r.Replace(string(l)) + "|"
+func (l QuotedLiteral) write(sb *strings.Builder) {
+	sb.WriteByte('|')
+	_, _ = quotedEscaper.WriteString(sb, string(l))
+	sb.WriteByte('|')
 }
 
 func (QuotedLiteral) node()         {}
@@ -203,6 +230,8 @@ func (l NameLiteral) String() string {
 	return string(l)
 }
 
+func (l NameLiteral) write(sb *strings.Builder) { sb.WriteString(string(l)) }
+
 func (NameLiteral) node()       {}
 func (NameLiteral) literal()    {}
 func (NameLiteral) value()      {}
@@ -213,6 +242,12 @@ type NumberLiteral float64
 // String returns MF2 formatted string.
 func (l NumberLiteral) String() string { return strconv.FormatFloat(float64(l), 'f', -1, 64) }
 
+func (l NumberLiteral) write(sb *strings.Builder) {
+	var buf [32]byte
+
+	sb.Write(strconv.AppendFloat(buf[:0], float64(l), 'f', -1, 64))
+}
+
 func (NumberLiteral) node()       {}
 func (NumberLiteral) literal()    {}
 func (NumberLiteral) value()      {}
@@ -223,15 +258,20 @@ func (NumberLiteral) variantKey() {}
 type Function struct {
 	Identifier Identifier
 	Options    []Option // Optional
+	Span       Span     // Optional, see WithPositions
 }
 
 // String returns MF2 formatted string.
-func (f Function) String() string {
-	if len(f.Options) == 0 {
-		return ":" + f.Identifier.String()
-	}
+func (f Function) String() string { return nodeString(f) }
+
+func (f Function) write(sb *strings.Builder) {
+	sb.WriteByte(':')
+	f.Identifier.write(sb)
 
-	return ":" + f.Identifier.String() + " " + sliceToString(f.Options, " ")
+	if len(f.Options) > 0 {
+		sb.WriteByte(' ')
+		writeSlice(sb, f.Options, " ")
+	}
 }
 
 func (Function) node()       {}
@@ -239,17 +279,28 @@ func (Function) annotation() {}
 
 type PrivateUseAnnotation struct {
 	ReservedBody []ReservedBody // QuotedLiteral or ReservedText
+	Span         Span           // Optional, see WithPositions
 	Start        rune
 }
 
 // String returns MF2 formatted string.
-func (p PrivateUseAnnotation) String() string {
-	body := sliceToString(p.ReservedBody, " ")
-	if len(body) > 0 {
-		return string(p.Start) + " " + body
+func (p PrivateUseAnnotation) String() string { return nodeString(p) }
+
+func (p PrivateUseAnnotation) write(sb *strings.Builder) {
+	sb.WriteRune(p.Start)
+
+	if len(p.ReservedBody) == 0 {
+		return
 	}
 
-	return string(p.Start)
+	var body strings.Builder
+
+	writeSlice(&body, p.ReservedBody, " ")
+
+	if body.Len() > 0 {
+		sb.WriteByte(' ')
+		sb.WriteString(body.String())
+	}
 }
 
 func (PrivateUseAnnotation) node()       {}
@@ -262,6 +313,8 @@ func (p ReservedAnnotation) String() string {
 	return PrivateUseAnnotation(p).String()
 }
 
+func (p ReservedAnnotation) write(sb *strings.Builder) { PrivateUseAnnotation(p).write(sb) }
+
 func (ReservedAnnotation) node()       {}
 func (ReservedAnnotation) annotation() {}
 
@@ -270,8 +323,11 @@ func (ReservedAnnotation) annotation() {}
 type InputDeclaration Expression // Only VariableExpression, i.e. operand is type Variable.
 
 // String returns MF2 formatted string.
-func (d InputDeclaration) String() string {
-	return input + " " + Expression(d).String()
+func (d InputDeclaration) String() string { return nodeString(d) }
+
+func (d InputDeclaration) write(sb *strings.Builder) {
+	sb.WriteString(input + " ")
+	Expression(d).write(sb)
 }
 
 func (InputDeclaration) node()        {}
@@ -280,11 +336,17 @@ func (InputDeclaration) declaration() {}
 type LocalDeclaration struct {
 	Variable   Variable
 	Expression Expression
+	Span       Span // Optional, see WithPositions
 }
 
 // String returns MF2 formatted string.
-func (d LocalDeclaration) String() string {
-	return local + " " + d.Variable.String() + " = " + d.Expression.String()
+func (d LocalDeclaration) String() string { return nodeString(d) }
+
+func (d LocalDeclaration) write(sb *strings.Builder) {
+	sb.WriteString(local + " ")
+	d.Variable.write(sb)
+	sb.WriteString(" = ")
+	d.Expression.write(sb)
 }
 
 func (LocalDeclaration) node()        {}
@@ -294,15 +356,23 @@ type ReservedStatement struct {
 	Keyword      string
 	ReservedBody []ReservedBody // QuotedLiteral or ReservedText
 	Expressions  []Expression   // At least one
+	Span         Span           // Optional, see WithPositions
 }
 
 // String returns MF2 formatted string.
-func (s ReservedStatement) String() string {
+func (s ReservedStatement) String() string { return nodeString(s) }
+
+func (s ReservedStatement) write(sb *strings.Builder) {
+	sb.WriteByte('.')
+	sb.WriteString(s.Keyword)
+	sb.WriteByte(' ')
+
 	if len(s.ReservedBody) > 0 {
-		return "." + s.Keyword + " " + sliceToString(s.ReservedBody, " ") + " " + sliceToString(s.Expressions, " ")
+		writeSlice(sb, s.ReservedBody, " ")
+		sb.WriteByte(' ')
 	}
 
-	return "." + s.Keyword + " " + sliceToString(s.Expressions, " ")
+	writeSlice(sb, s.Expressions, " ")
 }
 
 func (ReservedStatement) node()        {}
@@ -318,6 +388,8 @@ func (k CatchAllKey) String() string {
 	return catchAllSymbol
 }
 
+func (k CatchAllKey) write(sb *strings.Builder) { sb.WriteString(catchAllSymbol) }
+
 func (CatchAllKey) node()       {}
 func (CatchAllKey) variantKey() {}
 
@@ -326,8 +398,12 @@ func (CatchAllKey) variantKey() {}
 type QuotedPattern []PatternPart
 
 // String returns MF2 formatted string.
-func (p QuotedPattern) String() string {
-	return "{{" + sliceToString(p, "") + "}}"
+func (p QuotedPattern) String() string { return nodeString(p) }
+
+func (p QuotedPattern) write(sb *strings.Builder) {
+	sb.WriteString("{{")
+	writeSlice(sb, p, "")
+	sb.WriteString("}}")
 }
 
 func (QuotedPattern) node()        {}
@@ -336,14 +412,17 @@ func (QuotedPattern) complexBody() {}
 type Matcher struct {
 	Selectors []Expression // At least one
 	Variants  []Variant    // At least one
+	Span      Span         // Optional, see WithPositions
 }
 
 // String returns MF2 formatted string.
-func (m Matcher) String() string {
-	selectors := sliceToString(m.Selectors, " ")
-	variants := sliceToString(m.Variants, "\n")
+func (m Matcher) String() string { return nodeString(m) }
 
-	return match + " " + selectors + "\n" + variants
+func (m Matcher) write(sb *strings.Builder) {
+	sb.WriteString(match + " ")
+	writeSlice(sb, m.Selectors, " ")
+	sb.WriteByte('\n')
+	writeSlice(sb, m.Variants, "\n")
 }
 
 func (Matcher) node()        {}
@@ -358,19 +437,21 @@ func (v Variable) String() string {
 	return string(variablePrefix) + string(v)
 }
 
+func (v Variable) write(sb *strings.Builder) {
+	sb.WriteByte(variablePrefix)
+	sb.WriteString(string(v))
+}
+
 func (Variable) node()  {}
 func (Variable) value() {}
 
 type ReservedText string
 
 // String returns MF2 formatted string.
-func (t ReservedText) String() string {
-	return strings.NewReplacer(
-		`\`, `\\`,
-		`{`, `\{`,
-		`}`, `\}`,
-		`|`, `\|`,
-	).Replace(string(t))
+func (t ReservedText) String() string { return nodeString(t) }
+
+func (t ReservedText) write(sb *strings.Builder) {
+	_, _ = reservedEscaper.WriteString(sb, string(t))
 }
 
 func (ReservedText) node()         {}
@@ -392,16 +473,30 @@ func (i Identifier) String() string {
 	return i.Namespace + ":" + i.Name
 }
 
+func (i Identifier) write(sb *strings.Builder) {
+	if i.Namespace != "" {
+		sb.WriteString(i.Namespace)
+		sb.WriteByte(':')
+	}
+
+	sb.WriteString(i.Name)
+}
+
 type Variant struct {
 	Node
 
 	Keys          []VariantKey // At least one: Literal or CatchAllKey
 	QuotedPattern QuotedPattern
+	Span          Span // Optional, see WithPositions
 }
 
 // String returns MF2 formatted string.
-func (v Variant) String() string {
-	return sliceToString(v.Keys, " ") + " " + v.QuotedPattern.String()
+func (v Variant) String() string { return nodeString(v) }
+
+func (v Variant) write(sb *strings.Builder) {
+	writeSlice(sb, v.Keys, " ")
+	sb.WriteByte(' ')
+	v.QuotedPattern.write(sb)
 }
 
 type Option struct {
@@ -409,11 +504,16 @@ type Option struct {
 
 	Value      Value // Literal or Variable
 	Identifier Identifier
+	Span       Span // Optional, see WithPositions
 }
 
 // String returns MF2 formatted string.
-func (o Option) String() string {
-	return o.Identifier.String() + " = " + o.Value.String()
+func (o Option) String() string { return nodeString(o) }
+
+func (o Option) write(sb *strings.Builder) {
+	o.Identifier.write(sb)
+	sb.WriteString(" = ")
+	o.Value.write(sb)
 }
 
 type MarkupType int
@@ -431,46 +531,40 @@ type Markup struct {
 	Identifier Identifier
 	Options    []Option    // Optional. Options for Identifier, only allowed when markup-open.
 	Attributes []Attribute // Optional
+	Span       Span        // Optional, see WithPositions
 	Typ        MarkupType
 }
 
 // String returns MF2 formatted string.
-func (m Markup) String() string {
+func (m Markup) String() string { return nodeString(m) }
+
+func (m Markup) write(sb *strings.Builder) {
 	switch m.Typ {
 	default:
-		return ""
-	case Open:
-		s := "{ #" + m.Identifier.String()
-
-		if len(m.Options) > 0 {
-			s += " " + sliceToString(m.Options, " ")
-		}
-
-		if len(m.Attributes) > 0 {
-			s += " " + sliceToString(m.Attributes, " ")
-		}
-
-		return s + " }"
+		return
+	case Open, SelfClose:
+		sb.WriteString("{ #")
 	case Close:
-		s := "{ /" + m.Identifier.String()
-
-		if len(m.Attributes) > 0 {
-			s += " " + sliceToString(m.Attributes, " ")
-		}
+		sb.WriteString("{ /")
+	}
 
-		return s + " }"
-	case SelfClose:
-		s := "{ #" + m.Identifier.String()
+	m.Identifier.write(sb)
 
-		if len(m.Options) > 0 {
-			s += " " + sliceToString(m.Options, " ")
-		}
+	// options are allowed only in markup-open and markup-standalone
+	if len(m.Options) > 0 && m.Typ != Close {
+		sb.WriteByte(' ')
+		writeSlice(sb, m.Options, " ")
+	}
 
-		if len(m.Attributes) > 0 {
-			s += " " + sliceToString(m.Attributes, " ")
-		}
+	if len(m.Attributes) > 0 {
+		sb.WriteByte(' ')
+		writeSlice(sb, m.Attributes, " ")
+	}
 
-		return s + " /}"
+	if m.Typ == SelfClose {
+		sb.WriteString(" /}")
+	} else {
+		sb.WriteString(" }")
 	}
 }
 
@@ -479,15 +573,20 @@ type Attribute struct {
 
 	Value      Value // Optional: Literal or Variable
 	Identifier Identifier
+	Span       Span // Optional, see WithPositions
 }
 
 // String returns MF2 formatted string.
-func (a Attribute) String() string {
-	if a.Value == nil {
-		return "@" + a.Identifier.String()
-	}
+func (a Attribute) String() string { return nodeString(a) }
+
+func (a Attribute) write(sb *strings.Builder) {
+	sb.WriteByte('@')
+	a.Identifier.write(sb)
 
-	return "@" + a.Identifier.String() + " = " + a.Value.String()
+	if a.Value != nil {
+		sb.WriteString(" = ")
+		a.Value.write(sb)
+	}
 }
 
 // ---------------------------------Constants---------------------------------
@@ -502,16 +601,30 @@ const (
 
 // ---------------------------------Helpers---------------------------------
 
-// sliceToString converts a slice of Nodes to a string, separated by sep.
-func sliceToString[T Node](s []T, sep string) string {
-	if len(s) == 0 {
-		return ""
-	}
+var (
+	// text-escape = backslash ( backslash / "{" / "}" )
+	textEscaper = strings.NewReplacer(`\`, `\\`, `{`, `\{`, `}`, `\}`)
+	// quoted-escape = backslash ( backslash / "|" )
+	quotedEscaper   = strings.NewReplacer(`\`, `\\`, `|`, `\|`)
+	reservedEscaper = strings.NewReplacer(`\`, `\\`, `{`, `\{`, `}`, `\}`, `|`, `\|`)
+)
 
-	r := s[0].String()
-	for _, v := range s[1:] {
-		r += sep + v.String()
-	}
+// nodeString returns MF2 formatted string of the node.
+func nodeString(n Node) string {
+	var sb strings.Builder
 
-	return r
+	n.write(&sb)
+
+	return sb.String()
+}
+
+// writeSlice writes the nodes to sb, separated by sep.
+func writeSlice[T Node](sb *strings.Builder, s []T, sep string) {
+	for i, v := range s {
+		if i > 0 {
+			sb.WriteString(sep)
+		}
+
+		v.write(sb)
+	}
 }
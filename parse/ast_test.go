@@ -1,6 +1,9 @@
 package parse
 
-import "testing"
+import (
+	"strings"
+	"testing"
+)
 
 func TestExpression_String(t *testing.T) {
 	t.Parallel()
@@ -68,6 +71,28 @@ func TestMarkup_String(t *testing.T) {
 	}
 }
 
+func TestAST_WriteTo(t *testing.T) {
+	t.Parallel()
+
+	want := ".local $x = { |a\\|b| :string }\n.match { $x }\n* {{\\{{ $x }\\}}}"
+
+	tree, err := Parse(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var sb strings.Builder
+
+	n, err := tree.WriteTo(&sb)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := sb.String(); got != want || n != int64(len(want)) {
+		t.Errorf("want '%s', got '%s' (%d bytes)", want, got, n)
+	}
+}
+
 func BenchmarkComplexMessage_String(b *testing.B) {
 	//nolint:dupword
 	tree, err := Parse(".match {$foo :number} {$bar :number} one one {{one one}} one * {{one other}} * * {{other}}")
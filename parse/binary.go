@@ -0,0 +1,510 @@
+package parse
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// binaryMagic starts the binary encoding of the AST, the last byte is the format version.
+const binaryMagic = "MF2\x01"
+
+// node tags of the binary encoding, 0 is the nil node.
+const (
+	tagNil byte = iota
+	tagSimpleMessage
+	tagComplexMessage
+	tagText
+	tagExpression
+	tagQuotedLiteral
+	tagNameLiteral
+	tagNumberLiteral
+	tagFunction
+	tagPrivateUseAnnotation
+	tagReservedAnnotation
+	tagInputDeclaration
+	tagLocalDeclaration
+	tagReservedStatement
+	tagCatchAllKey
+	tagQuotedPattern
+	tagMatcher
+	tagVariable
+	tagReservedText
+	tagMarkup
+)
+
+var errInvalidBinary = errors.New("invalid binary AST")
+
+// MarshalBinary encodes the AST in the compact binary format. It implements [encoding.BinaryMarshaler].
+//
+// The binary AST is decoded without parsing, e.g. to load the messages compiled at build time.
+func (a AST) MarshalBinary() ([]byte, error) {
+	e := encoder{buf: []byte(binaryMagic)}
+	e.node(a.Message)
+
+	return e.buf, nil
+}
+
+// UnmarshalBinary decodes the AST encoded by [AST.MarshalBinary].
+// It implements [encoding.BinaryUnmarshaler].
+//
+// The decoded message is validated as by [Parse], e.g. the matcher without the fallback variant is rejected.
+func (a *AST) UnmarshalBinary(data []byte) error {
+	if len(data) < len(binaryMagic) || string(data[:len(binaryMagic)]) != binaryMagic {
+		return fmt.Errorf("unmarshal AST: %w: unknown format", errInvalidBinary)
+	}
+
+	d := decoder{data: data[len(binaryMagic):]}
+
+	message, err := decodeAs[Message](&d, true)
+	if err == nil && len(d.data) > 0 {
+		err = fmt.Errorf("%w: %d unexpected trailing bytes", errInvalidBinary, len(d.data))
+	}
+
+	// the data is not necessarily encoded by MarshalBinary, validate as the parser does
+	if err == nil {
+		if err = validate(message); err != nil {
+			err = fmt.Errorf("%w: %w", errInvalidBinary, err)
+		}
+	}
+
+	if err != nil {
+		return fmt.Errorf("unmarshal AST: %w", err)
+	}
+
+	a.Message = message
+
+	return nil
+}
+
+type encoder struct {
+	buf []byte
+}
+
+func (e *encoder) uint(n uint64) { e.buf = binary.AppendUvarint(e.buf, n) }
+
+func (e *encoder) string(s string) {
+	e.uint(uint64(len(s)))
+	e.buf = append(e.buf, s...)
+}
+
+func (e *encoder) identifier(i Identifier) {
+	e.string(i.Namespace)
+	e.string(i.Name)
+}
+
+func encodeSlice[T any](e *encoder, s []T, f func(T)) {
+	e.uint(uint64(len(s)))
+
+	for _, v := range s {
+		f(v)
+	}
+}
+
+func (e *encoder) node(node Node) {
+	switch v := node.(type) {
+	default:
+		e.buf = append(e.buf, tagNil)
+	case SimpleMessage:
+		e.buf = append(e.buf, tagSimpleMessage)
+		encodeSlice(e, v, e.patternPart)
+	case ComplexMessage:
+		e.buf = append(e.buf, tagComplexMessage)
+		encodeSlice(e, v.Declarations, e.declaration)
+		e.node(v.ComplexBody)
+	case Text:
+		e.buf = append(e.buf, tagText)
+		e.string(string(v))
+	case Expression:
+		e.buf = append(e.buf, tagExpression)
+		e.expression(v)
+	case QuotedLiteral:
+		e.buf = append(e.buf, tagQuotedLiteral)
+		e.string(string(v))
+	case NameLiteral:
+		e.buf = append(e.buf, tagNameLiteral)
+		e.string(string(v))
+	case NumberLiteral:
+		e.buf = append(e.buf, tagNumberLiteral)
+		e.buf = binary.LittleEndian.AppendUint64(e.buf, math.Float64bits(float64(v)))
+	case Function:
+		e.buf = append(e.buf, tagFunction)
+		e.identifier(v.Identifier)
+		encodeSlice(e, v.Options, e.option)
+	case PrivateUseAnnotation:
+		e.buf = append(e.buf, tagPrivateUseAnnotation)
+		e.uint(uint64(v.Start))
+		encodeSlice(e, v.ReservedBody, e.reservedBody)
+	case ReservedAnnotation:
+		e.buf = append(e.buf, tagReservedAnnotation)
+		e.uint(uint64(v.Start))
+		encodeSlice(e, v.ReservedBody, e.reservedBody)
+	case InputDeclaration:
+		e.buf = append(e.buf, tagInputDeclaration)
+		e.expression(Expression(v))
+	case LocalDeclaration:
+		e.buf = append(e.buf, tagLocalDeclaration)
+		e.string(string(v.Variable))
+		e.expression(v.Expression)
+	case ReservedStatement:
+		e.buf = append(e.buf, tagReservedStatement)
+		e.string(v.Keyword)
+		encodeSlice(e, v.ReservedBody, e.reservedBody)
+		encodeSlice(e, v.Expressions, e.expression)
+	case CatchAllKey:
+		e.buf = append(e.buf, tagCatchAllKey)
+	case QuotedPattern:
+		e.buf = append(e.buf, tagQuotedPattern)
+		encodeSlice(e, v, e.patternPart)
+	case Matcher:
+		e.buf = append(e.buf, tagMatcher)
+		encodeSlice(e, v.Selectors, e.expression)
+		encodeSlice(e, v.Variants, func(variant Variant) {
+			encodeSlice(e, variant.Keys, e.variantKey)
+			encodeSlice(e, variant.QuotedPattern, e.patternPart)
+		})
+	case Variable:
+		e.buf = append(e.buf, tagVariable)
+		e.string(string(v))
+	case ReservedText:
+		e.buf = append(e.buf, tagReservedText)
+		e.string(string(v))
+	case Markup:
+		e.buf = append(e.buf, tagMarkup, byte(v.Typ))
+		e.identifier(v.Identifier)
+		encodeSlice(e, v.Options, e.option)
+		encodeSlice(e, v.Attributes, e.attribute)
+	}
+}
+
+// typed wrappers of node, the slices are encoded with encodeSlice.
+
+func (e *encoder) patternPart(p PatternPart)   { e.node(p) }
+func (e *encoder) declaration(d Declaration)   { e.node(d) }
+func (e *encoder) reservedBody(b ReservedBody) { e.node(b) }
+func (e *encoder) variantKey(k VariantKey)     { e.node(k) }
+
+func (e *encoder) expression(expr Expression) {
+	e.node(expr.Operand)
+	e.node(expr.Annotation)
+	encodeSlice(e, expr.Attributes, e.attribute)
+}
+
+func (e *encoder) option(o Option) {
+	e.identifier(o.Identifier)
+	e.node(o.Value)
+}
+
+func (e *encoder) attribute(a Attribute) {
+	e.identifier(a.Identifier)
+	e.node(a.Value)
+}
+
+type decoder struct {
+	data []byte
+}
+
+func (d *decoder) byte() (byte, error) {
+	if len(d.data) == 0 {
+		return 0, fmt.Errorf("%w: unexpected end", errInvalidBinary)
+	}
+
+	b := d.data[0]
+	d.data = d.data[1:]
+
+	return b, nil
+}
+
+func (d *decoder) uint() (uint64, error) {
+	n, size := binary.Uvarint(d.data)
+	if size <= 0 {
+		return 0, fmt.Errorf("%w: invalid varint", errInvalidBinary)
+	}
+
+	d.data = d.data[size:]
+
+	return n, nil
+}
+
+// len decodes the length of a slice or a string, each element takes at least one byte.
+func (d *decoder) len() (int, error) {
+	n, err := d.uint()
+	if err != nil {
+		return 0, err
+	}
+
+	if n > uint64(len(d.data)) {
+		return 0, fmt.Errorf("%w: length %d exceeds data", errInvalidBinary, n)
+	}
+
+	return int(n), nil
+}
+
+func (d *decoder) string() (string, error) {
+	n, err := d.len()
+	if err != nil {
+		return "", err
+	}
+
+	s := string(d.data[:n])
+	d.data = d.data[n:]
+
+	return s, nil
+}
+
+func (d *decoder) identifier() (Identifier, error) {
+	namespace, err := d.string()
+	if err != nil {
+		return Identifier{}, err
+	}
+
+	name, err := d.string()
+	if err != nil {
+		return Identifier{}, err
+	}
+
+	return Identifier{Namespace: namespace, Name: name}, nil
+}
+
+func decodeSlice[T any](d *decoder, f func() (T, error)) ([]T, error) {
+	n, err := d.len()
+	if err != nil || n == 0 {
+		return nil, err
+	}
+
+	s := make([]T, n)
+
+	for i := range s {
+		if s[i], err = f(); err != nil {
+			return nil, err
+		}
+	}
+
+	return s, nil
+}
+
+// decodeAs decodes the node of type T, the nil node is allowed only if optional.
+func decodeAs[T Node](d *decoder, optional bool) (T, error) {
+	var zero T
+
+	node, err := d.node()
+	if err != nil {
+		return zero, err
+	}
+
+	if node == nil {
+		if optional {
+			return zero, nil
+		}
+
+		return zero, fmt.Errorf("%w: missing %T", errInvalidBinary, zero)
+	}
+
+	v, ok := node.(T)
+	if !ok {
+		return zero, fmt.Errorf("%w: unexpected %T", errInvalidBinary, node)
+	}
+
+	return v, nil
+}
+
+func (d *decoder) patternPart() (PatternPart, error) { return decodeAs[PatternPart](d, false) }
+func (d *decoder) declaration() (Declaration, error) { return decodeAs[Declaration](d, false) }
+func (d *decoder) reservedBody() (ReservedBody, error) {
+	return decodeAs[ReservedBody](d, false)
+}
+func (d *decoder) variantKey() (VariantKey, error) { return decodeAs[VariantKey](d, false) }
+
+func (d *decoder) expression() (Expression, error) {
+	var (
+		expr Expression
+		err  error
+	)
+
+	if expr.Operand, err = decodeAs[Value](d, true); err != nil {
+		return expr, err
+	}
+
+	if expr.Annotation, err = decodeAs[Annotation](d, true); err != nil {
+		return expr, err
+	}
+
+	expr.Attributes, err = decodeSlice(d, d.attribute)
+
+	return expr, err
+}
+
+func (d *decoder) option() (Option, error) {
+	identifier, err := d.identifier()
+	if err != nil {
+		return Option{}, err
+	}
+
+	value, err := decodeAs[Value](d, false)
+
+	return Option{Identifier: identifier, Value: value}, err
+}
+
+func (d *decoder) attribute() (Attribute, error) {
+	identifier, err := d.identifier()
+	if err != nil {
+		return Attribute{}, err
+	}
+
+	value, err := decodeAs[Value](d, true)
+
+	return Attribute{Identifier: identifier, Value: value}, err
+}
+
+func (d *decoder) variant() (Variant, error) {
+	keys, err := decodeSlice(d, d.variantKey)
+	if err != nil {
+		return Variant{}, err
+	}
+
+	pattern, err := decodeSlice(d, d.patternPart)
+
+	return Variant{Keys: keys, QuotedPattern: pattern}, err
+}
+
+func (d *decoder) annotation() (PrivateUseAnnotation, error) {
+	start, err := d.uint()
+	if err != nil {
+		return PrivateUseAnnotation{}, err
+	}
+
+	if start > math.MaxInt32 {
+		return PrivateUseAnnotation{}, fmt.Errorf("%w: invalid annotation start", errInvalidBinary)
+	}
+
+	body, err := decodeSlice(d, d.reservedBody)
+
+	return PrivateUseAnnotation{Start: rune(start), ReservedBody: body}, err
+}
+
+//nolint:cyclop,gocognit,gocyclo,funlen
+func (d *decoder) node() (Node, error) {
+	tag, err := d.byte()
+	if err != nil {
+		return nil, err
+	}
+
+	switch tag {
+	default:
+		return nil, fmt.Errorf("%w: unknown node tag %d", errInvalidBinary, tag)
+	case tagNil:
+		return nil, nil //nolint:nilnil
+	case tagSimpleMessage:
+		parts, err := decodeSlice(d, d.patternPart)
+		return SimpleMessage(parts), err
+	case tagComplexMessage:
+		declarations, err := decodeSlice(d, d.declaration)
+		if err != nil {
+			return nil, err
+		}
+
+		body, err := decodeAs[ComplexBody](d, false)
+
+		return ComplexMessage{Declarations: declarations, ComplexBody: body}, err
+	case tagText:
+		s, err := d.string()
+		return Text(s), err
+	case tagExpression:
+		return d.expression()
+	case tagQuotedLiteral:
+		s, err := d.string()
+		return QuotedLiteral(s), err
+	case tagNameLiteral:
+		s, err := d.string()
+		return NameLiteral(s), err
+	case tagNumberLiteral:
+		if len(d.data) < 8 { //nolint:mnd
+			return nil, fmt.Errorf("%w: unexpected end", errInvalidBinary)
+		}
+
+		bits := binary.LittleEndian.Uint64(d.data)
+		d.data = d.data[8:]
+
+		return NumberLiteral(math.Float64frombits(bits)), nil
+	case tagFunction:
+		identifier, err := d.identifier()
+		if err != nil {
+			return nil, err
+		}
+
+		options, err := decodeSlice(d, d.option)
+
+		return Function{Identifier: identifier, Options: options}, err
+	case tagPrivateUseAnnotation:
+		return d.annotation()
+	case tagReservedAnnotation:
+		annotation, err := d.annotation()
+		return ReservedAnnotation(annotation), err
+	case tagInputDeclaration:
+		expr, err := d.expression()
+		return InputDeclaration(expr), err
+	case tagLocalDeclaration:
+		variable, err := d.string()
+		if err != nil {
+			return nil, err
+		}
+
+		expr, err := d.expression()
+
+		return LocalDeclaration{Variable: Variable(variable), Expression: expr}, err
+	case tagReservedStatement:
+		keyword, err := d.string()
+		if err != nil {
+			return nil, err
+		}
+
+		body, err := decodeSlice(d, d.reservedBody)
+		if err != nil {
+			return nil, err
+		}
+
+		expressions, err := decodeSlice(d, d.expression)
+
+		return ReservedStatement{Keyword: keyword, ReservedBody: body, Expressions: expressions}, err
+	case tagCatchAllKey:
+		return CatchAllKey{}, nil
+	case tagQuotedPattern:
+		parts, err := decodeSlice(d, d.patternPart)
+		return QuotedPattern(parts), err
+	case tagMatcher:
+		selectors, err := decodeSlice(d, d.expression)
+		if err != nil {
+			return nil, err
+		}
+
+		variants, err := decodeSlice(d, d.variant)
+
+		return Matcher{Selectors: selectors, Variants: variants}, err
+	case tagVariable:
+		s, err := d.string()
+		return Variable(s), err
+	case tagReservedText:
+		s, err := d.string()
+		return ReservedText(s), err
+	case tagMarkup:
+		typ, err := d.byte()
+		if err != nil {
+			return nil, err
+		}
+
+		identifier, err := d.identifier()
+		if err != nil {
+			return nil, err
+		}
+
+		options, err := decodeSlice(d, d.option)
+		if err != nil {
+			return nil, err
+		}
+
+		attributes, err := decodeSlice(d, d.attribute)
+
+		return Markup{Typ: MarkupType(typ), Identifier: identifier, Options: options, Attributes: attributes}, err
+	}
+}
@@ -0,0 +1,144 @@
+package parse
+
+import (
+	"errors"
+	"testing"
+
+	"go.expect.digital/mf2"
+)
+
+func TestBinary(t *testing.T) {
+	t.Parallel()
+
+	for _, in := range []string{
+		"",
+		"Hello, { $name }!",
+		"{ |quoted| } { name } { -1.5e3 } { $n :number minimumFractionDigits=2 style=$style }",
+		"{ $x :ns:func u:id=1 @attr @a=|b| }",
+		"{#link href=|x| @y}text{/link} {#br/}",
+		".input { $n :number } .local $x = { $n :integer } {{{ $x }}}",
+		".match { $n :number } { $s :string } 1 |a b| {{one}} * * {{{ $n }}}",
+		"{ $x ^private } { ! reserved |x| }",
+		".reserved |x| { $x } { $y } {{ }}",
+	} {
+		t.Run(in, func(t *testing.T) {
+			t.Parallel()
+
+			want, err := Parse(in, WithoutValidation())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			data, err := want.MarshalBinary()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got AST
+
+			if err := got.UnmarshalBinary(data); err != nil {
+				t.Fatal(err)
+			}
+
+			if want.String() != got.String() {
+				t.Errorf("want '%s', got '%s'", want, got)
+			}
+
+			if changes := Diff(want, got); len(changes) != 0 {
+				t.Errorf("want no changes, got %v", changes)
+			}
+
+			// truncated data is invalid
+			if len(data) > len(binaryMagic) {
+				if err := got.UnmarshalBinary(data[:len(data)-1]); !errors.Is(err, errInvalidBinary) {
+					t.Errorf("want '%s', got '%v'", errInvalidBinary, err)
+				}
+			}
+		})
+	}
+
+	for _, data := range [][]byte{nil, []byte("MF2"), []byte(binaryMagic + "\xff"), []byte(binaryMagic + "\x01\xff")} {
+		var ast AST
+
+		if err := ast.UnmarshalBinary(data); !errors.Is(err, errInvalidBinary) {
+			t.Errorf("%q: want '%s', got '%v'", data, errInvalidBinary, err)
+		}
+	}
+}
+
+func TestUnmarshalBinaryInvalid(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		wantErr error
+		message Message
+		name    string
+	}{
+		{
+			name:    "literal input operand",
+			message: ComplexMessage{Declarations: []Declaration{InputDeclaration{Operand: NameLiteral("x")}}, ComplexBody: QuotedPattern{}},
+			wantErr: mf2.ErrBadOperand,
+		},
+		{
+			name: "duplicate declaration",
+			message: ComplexMessage{
+				Declarations: []Declaration{
+					InputDeclaration{Operand: Variable("x")},
+					LocalDeclaration{Variable: "x", Expression: Expression{Operand: NameLiteral("y")}},
+				},
+				ComplexBody: QuotedPattern{},
+			},
+			wantErr: mf2.ErrDuplicateDeclaration,
+		},
+		{
+			name: "self reference",
+			message: ComplexMessage{
+				Declarations: []Declaration{LocalDeclaration{Variable: "x", Expression: Expression{Operand: Variable("x")}}},
+				ComplexBody:  QuotedPattern{},
+			},
+			wantErr: mf2.ErrDuplicateDeclaration,
+		},
+		{
+			name: "missing fallback variant",
+			message: ComplexMessage{ComplexBody: Matcher{
+				Selectors: []Expression{{Operand: Variable("x")}},
+				Variants:  []Variant{{Keys: []VariantKey{NameLiteral("a")}}},
+			}},
+			wantErr: mf2.ErrMissingFallbackVariant,
+		},
+		{
+			name: "variant key mismatch",
+			message: ComplexMessage{ComplexBody: Matcher{
+				Selectors: []Expression{{Operand: Variable("x")}},
+				Variants:  []Variant{{Keys: []VariantKey{CatchAllKey{}, CatchAllKey{}}}},
+			}},
+			wantErr: mf2.ErrVariantKeyMismatch,
+		},
+		{
+			name:    "missing complex body",
+			message: ComplexMessage{Declarations: []Declaration{InputDeclaration{Operand: Variable("x")}}},
+			wantErr: errInvalidBinary,
+		},
+		{
+			name:    "empty expression",
+			message: SimpleMessage{Expression{}},
+			wantErr: errInvalidBinary,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			data, err := AST{Message: test.message}.MarshalBinary()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got AST
+
+			err = got.UnmarshalBinary(data)
+			if !errors.Is(err, errInvalidBinary) || !errors.Is(err, test.wantErr) {
+				t.Errorf("want '%s', got '%v'", test.wantErr, err)
+			}
+		})
+	}
+}
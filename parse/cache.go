@@ -0,0 +1,54 @@
+package parse
+
+import "go.expect.digital/mf2/internal/lru"
+
+// Cache is a LRU cache of parsed messages. Repeated parsing of identical messages,
+// e.g. loaded from a database per request, returns the cached AST.
+//
+// The cached AST is shared and must not be modified.
+//
+// Cache is safe for concurrent use.
+type Cache struct {
+	results *lru.Cache[string, cacheEntry]
+	options []ParseOption
+}
+
+// cacheEntry is the result of [Parse].
+type cacheEntry struct {
+	err error
+	ast AST
+}
+
+// NewCache returns a new cache of at most size messages, at least one.
+// The messages are parsed with the options, the same options apply to all cached messages.
+func NewCache(size int, options ...ParseOption) *Cache {
+	return &Cache{
+		results: lru.New[string, cacheEntry](size, nil),
+		options: options,
+	}
+}
+
+// Parse returns the cached result of [Parse], or parses the input and caches the result.
+// Syntax errors are cached too.
+func (c *Cache) Parse(input string) (AST, error) {
+	if entry, ok := c.results.Get(input); ok {
+		return entry.ast, entry.err
+	}
+
+	// parse outside the lock, concurrent misses of the same input are parsed more than once
+	tree, err := Parse(input, c.options...)
+
+	c.results.Add(input, cacheEntry{ast: tree, err: err})
+
+	return tree, err
+}
+
+// Len returns the number of cached messages.
+func (c *Cache) Len() int {
+	return c.results.Len()
+}
+
+// Stats returns the number of the cache hits and misses of [Cache.Parse], e.g. to export the hit rate.
+func (c *Cache) Stats() (hits, misses uint64) {
+	return c.results.Stats()
+}
@@ -0,0 +1,69 @@
+package parse
+
+import (
+	"errors"
+	"testing"
+
+	"go.expect.digital/mf2"
+)
+
+func TestCache(t *testing.T) {
+	t.Parallel()
+
+	cache := NewCache(2)
+
+	for _, test := range []struct {
+		in      string
+		wantErr error
+		wantLen int
+	}{
+		{in: "Hello, { $name }!", wantLen: 1},
+		{in: "Hello, { $name }!", wantLen: 1},
+		{in: "{ $x", wantErr: mf2.ErrSyntax, wantLen: 2},
+		{in: "{ $x", wantErr: mf2.ErrSyntax, wantLen: 2},
+		{in: "Bye!", wantLen: 2}, // evicts "Hello, { $name }!"
+		{in: "Hello, { $name }!", wantLen: 2},
+	} {
+		tree, err := cache.Parse(test.in)
+		if !errors.Is(err, test.wantErr) {
+			t.Errorf("%s: want error '%v', got '%v'", test.in, test.wantErr, err)
+		}
+
+		if want, _ := Parse(test.in); want.String() != tree.String() {
+			t.Errorf("%s: want '%s', got '%s'", test.in, want, tree)
+		}
+
+		if got := cache.Len(); test.wantLen != got {
+			t.Errorf("%s: want %d cached messages, got %d", test.in, test.wantLen, got)
+		}
+	}
+
+	// the least recently used "{ $x" is evicted, "Bye!" stays
+	cache.results.Add("Bye!", cacheEntry{err: errors.New("cached")})
+
+	if _, err := cache.Parse("Bye!"); err == nil {
+		t.Error("want cached message, got parsed")
+	}
+
+	if cache.results.Contains("{ $x") {
+		t.Error("want evicted message, got cached")
+	}
+
+	if hits, misses := cache.Stats(); hits != 3 || misses != 4 {
+		t.Errorf("want 3 hits and 4 misses, got %d and %d", hits, misses)
+	}
+}
+
+func TestCacheOptions(t *testing.T) {
+	t.Parallel()
+
+	in := ".input {$foo} .input {$foo} {{ }}"
+
+	if _, err := NewCache(1).Parse(in); err == nil {
+		t.Error("want validation error, got nil")
+	}
+
+	if _, err := NewCache(1, WithoutValidation()).Parse(in); err != nil {
+		t.Errorf("want no error, got '%s'", err)
+	}
+}
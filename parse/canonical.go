@@ -0,0 +1,119 @@
+package parse
+
+import (
+	"slices"
+	"strings"
+)
+
+// Canonical returns a copy of the AST with the options and attributes sorted by identifier.
+// The string representation of the canonical AST is deterministic regardless of the authoring order,
+// e.g. for content hashing and VCS diffs. The given AST is not modified.
+//
+// Example:
+//
+//	ast, _ := Parse("{ $n :number style=percent minimumFractionDigits=2 }")
+//	fmt.Print(Canonical(ast)) // { $n :number minimumFractionDigits = 2 style = percent }
+func Canonical(ast AST) AST {
+	switch message := ast.Message.(type) {
+	case SimpleMessage:
+		return AST{Message: SimpleMessage(canonicalPattern(message))}
+	case ComplexMessage:
+		declarations := make([]Declaration, len(message.Declarations))
+
+		for i, declaration := range message.Declarations {
+			switch d := declaration.(type) {
+			default:
+				declarations[i] = d
+			case InputDeclaration:
+				declarations[i] = InputDeclaration(canonicalExpression(Expression(d)))
+			case LocalDeclaration:
+				declarations[i] = LocalDeclaration{Variable: d.Variable, Expression: canonicalExpression(d.Expression)}
+			case ReservedStatement:
+				d.Expressions = canonicalExpressions(d.Expressions)
+				declarations[i] = d
+			}
+		}
+
+		message.Declarations = declarations
+
+		switch body := message.ComplexBody.(type) {
+		case QuotedPattern:
+			message.ComplexBody = QuotedPattern(canonicalPattern(body))
+		case Matcher:
+			variants := make([]Variant, len(body.Variants))
+
+			for i, variant := range body.Variants {
+				variant.QuotedPattern = canonicalPattern(variant.QuotedPattern)
+				variants[i] = variant
+			}
+
+			message.ComplexBody = Matcher{Selectors: canonicalExpressions(body.Selectors), Variants: variants}
+		}
+
+		return AST{Message: message}
+	}
+
+	return ast
+}
+
+func canonicalPattern(pattern []PatternPart) []PatternPart {
+	if pattern == nil {
+		return nil
+	}
+
+	parts := make([]PatternPart, len(pattern))
+
+	for i, part := range pattern {
+		switch v := part.(type) {
+		default:
+			parts[i] = v
+		case Expression:
+			parts[i] = canonicalExpression(v)
+		case Markup:
+			v.Options = sortedByIdentifier(v.Options, func(o Option) Identifier { return o.Identifier })
+			v.Attributes = sortedByIdentifier(v.Attributes, func(a Attribute) Identifier { return a.Identifier })
+			parts[i] = v
+		}
+	}
+
+	return parts
+}
+
+func canonicalExpressions(expressions []Expression) []Expression {
+	if expressions == nil {
+		return nil
+	}
+
+	s := make([]Expression, len(expressions))
+	for i, expr := range expressions {
+		s[i] = canonicalExpression(expr)
+	}
+
+	return s
+}
+
+func canonicalExpression(expr Expression) Expression {
+	if f, ok := expr.Annotation.(Function); ok {
+		f.Options = sortedByIdentifier(f.Options, func(o Option) Identifier { return o.Identifier })
+		expr.Annotation = f
+	}
+
+	expr.Attributes = sortedByIdentifier(expr.Attributes, func(a Attribute) Identifier { return a.Identifier })
+
+	return expr
+}
+
+// sortedByIdentifier returns a sorted copy of s, the order of the equal identifiers is kept.
+func sortedByIdentifier[T any](s []T, identifier func(T) Identifier) []T {
+	if len(s) < 2 { //nolint:mnd
+		return s
+	}
+
+	sorted := slices.Clone(s)
+
+	slices.SortStableFunc(sorted, func(a, b T) int {
+		return strings.Compare(identifier(a).String(), identifier(b).String())
+	})
+
+	return sorted
+}
@@ -0,0 +1,47 @@
+package parse
+
+import "testing"
+
+func TestCanonical(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		in, want string
+	}{
+		{
+			in:   "{ $n :number style=percent minimumFractionDigits=2 @b @a=1 }",
+			want: "{ $n :number minimumFractionDigits = 2 style = percent @a = 1 @b }",
+		},
+		{
+			in:   "{#link z=1 href=|x| @y @x}text{/link}",
+			want: "{ #link href = |x| z = 1 @x @y }text{ /link }",
+		},
+		{
+			in: ".input { $n :number style=percent minimumFractionDigits=2 } .local $x = { $n :u:y b=1 a=2 }" +
+				" .match { $x :string u:b=1 a=2 } * {{{ $n :integer z=1 y=2 }}}",
+			want: ".input { $n :number minimumFractionDigits = 2 style = percent }\n" +
+				".local $x = { $n :u:y a = 2 b = 1 }\n" +
+				".match { $x :string a = 2 u:b = 1 }\n" +
+				"* {{{ $n :integer y = 2 z = 1 }}}",
+		},
+	} {
+		t.Run(test.in, func(t *testing.T) {
+			t.Parallel()
+
+			ast, err := Parse(test.in, WithoutValidation())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			original := ast.String()
+
+			if got := Canonical(ast).String(); test.want != got {
+				t.Errorf("want '%s', got '%s'", test.want, got)
+			}
+
+			if got := ast.String(); original != got {
+				t.Errorf("want unmodified '%s', got '%s'", original, got)
+			}
+		})
+	}
+}
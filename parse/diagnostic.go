@@ -0,0 +1,14 @@
+package parse
+
+import "go.expect.digital/mf2"
+
+// Diagnose parses the input and returns the AST and the diagnostics of the syntax and data model errors.
+// The parser does not report the position of the error, the span of the diagnostic is the whole input.
+func Diagnose(input string, options ...ParseOption) (AST, []mf2.Diagnostic) {
+	tree, err := Parse(input, options...)
+	if err != nil {
+		return tree, []mf2.Diagnostic{mf2.NewDiagnostic(err, mf2.Span{End: len(input)})}
+	}
+
+	return tree, nil
+}
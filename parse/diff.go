@@ -0,0 +1,409 @@
+package parse
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ChangeKind is the kind of the change found by [Diff].
+type ChangeKind int
+
+const (
+	// TextChanged is the changed text of the pattern, the placeholders are written as "{}".
+	TextChanged ChangeKind = iota + 1
+	// VariableRenamed is the changed variable of the operand or the local declaration.
+	VariableRenamed
+	// FunctionChanged is the changed function of the expression.
+	FunctionChanged
+	// OptionChanged is the added, removed or changed option of the function or markup.
+	OptionChanged
+	// PlaceholderAdded is the expression or markup added to the pattern.
+	PlaceholderAdded
+	// PlaceholderRemoved is the expression or markup removed from the pattern.
+	PlaceholderRemoved
+	// PlaceholderChanged is the changed placeholder that has no more specific kind, e.g. markup.
+	PlaceholderChanged
+	// VariantAdded is the variant with the keys not found in the message before the change.
+	VariantAdded
+	// VariantRemoved is the variant with the keys not found in the message after the change.
+	VariantRemoved
+	// SelectorAdded is the selector added to the matcher.
+	SelectorAdded
+	// SelectorRemoved is the selector removed from the matcher.
+	SelectorRemoved
+	// DeclarationAdded is the declaration added to the message.
+	DeclarationAdded
+	// DeclarationRemoved is the declaration removed from the message.
+	DeclarationRemoved
+)
+
+// String returns the name of the change kind, e.g. "text changed".
+func (k ChangeKind) String() string {
+	switch k {
+	default:
+		return "ChangeKind(" + strconv.Itoa(int(k)) + ")"
+	case TextChanged:
+		return "text changed"
+	case VariableRenamed:
+		return "variable renamed"
+	case FunctionChanged:
+		return "function changed"
+	case OptionChanged:
+		return "option changed"
+	case PlaceholderAdded:
+		return "placeholder added"
+	case PlaceholderRemoved:
+		return "placeholder removed"
+	case PlaceholderChanged:
+		return "placeholder changed"
+	case VariantAdded:
+		return "variant added"
+	case VariantRemoved:
+		return "variant removed"
+	case SelectorAdded:
+		return "selector added"
+	case SelectorRemoved:
+		return "selector removed"
+	case DeclarationAdded:
+		return "declaration added"
+	case DeclarationRemoved:
+		return "declaration removed"
+	}
+}
+
+// Change is a semantic change between two messages.
+type Change struct {
+	// Path is the location of the change in the message after the change, before the change for removals.
+	// The path elements are the same as in [Walk].
+	Path Path
+	// Old is the MF2 formatted old value, empty if added.
+	Old string
+	// New is the MF2 formatted new value, empty if removed.
+	New  string
+	Kind ChangeKind
+}
+
+// String returns the change in the form "path: kind: old -> new".
+func (c Change) String() string {
+	return c.Path.String() + ": " + c.Kind.String() + ": " + strconv.Quote(c.Old) + " -> " + strconv.Quote(c.New)
+}
+
+// Diff returns the semantic changes between the message before and after the change,
+// e.g. to decide whether the existing translations of the message remain valid.
+//
+// The variants are matched by their keys, the declarations, selectors and placeholders by their position.
+func Diff(before, after AST) []Change {
+	var d differ
+
+	oldDecls, oldMatcher, oldPattern := messageParts(before.Message)
+	newDecls, newMatcher, newPattern := messageParts(after.Message)
+
+	d.declarations(oldDecls, newDecls)
+
+	switch {
+	case oldMatcher == nil && newMatcher == nil:
+		prefix := Path{"pattern"}
+		if _, ok := after.Message.(ComplexMessage); ok {
+			prefix = Path{"body", "pattern"}
+		}
+
+		d.pattern(prefix, oldPattern, newPattern)
+	default:
+		d.matcher(oldMatcher, newMatcher, oldPattern, newPattern)
+	}
+
+	return d.changes
+}
+
+// messageParts returns the declarations and either the matcher or the pattern of the message.
+func messageParts(message Message) ([]Declaration, *Matcher, []PatternPart) {
+	switch m := message.(type) {
+	default:
+		return nil, nil, nil
+	case SimpleMessage:
+		return nil, nil, m
+	case ComplexMessage:
+		switch body := m.ComplexBody.(type) {
+		default:
+			return m.Declarations, nil, nil
+		case QuotedPattern:
+			return m.Declarations, nil, body
+		case Matcher:
+			return m.Declarations, &body, nil
+		}
+	}
+}
+
+type differ struct {
+	changes []Change
+}
+
+func (d *differ) add(kind ChangeKind, path Path, before, after string) {
+	d.changes = append(d.changes, Change{Kind: kind, Path: append(Path(nil), path...), Old: before, New: after})
+}
+
+func (d *differ) declarations(before, after []Declaration) {
+	for i := range max(len(before), len(after)) {
+		path := Path{"declarations[" + strconv.Itoa(i) + "]"}
+
+		switch {
+		case i >= len(after):
+			d.add(DeclarationRemoved, path, before[i].String(), "")
+		case i >= len(before):
+			d.add(DeclarationAdded, path, "", after[i].String())
+		default:
+			d.declaration(path, before[i], after[i])
+		}
+	}
+}
+
+func (d *differ) declaration(path Path, before, after Declaration) {
+	switch o := before.(type) {
+	case InputDeclaration:
+		if n, ok := after.(InputDeclaration); ok {
+			d.expression(sub(path, "expression"), Expression(o), Expression(n))
+			return
+		}
+	case LocalDeclaration:
+		if n, ok := after.(LocalDeclaration); ok {
+			if o.Variable != n.Variable {
+				d.add(VariableRenamed, sub(path, "variable"), o.Variable.String(), n.Variable.String())
+			}
+
+			d.expression(sub(path, "expression"), o.Expression, n.Expression)
+
+			return
+		}
+	}
+
+	if before.String() != after.String() {
+		d.add(DeclarationRemoved, path, before.String(), "")
+		d.add(DeclarationAdded, path, "", after.String())
+	}
+}
+
+// matcher compares the matchers, nil matcher is a single variant with no keys and the pattern.
+func (d *differ) matcher(before, after *Matcher, oldPattern, newPattern []PatternPart) {
+	oldVariants, newVariants := variantsOf(before, oldPattern), variantsOf(after, newPattern)
+
+	var oldSelectors, newSelectors []Expression
+	if before != nil {
+		oldSelectors = before.Selectors
+	}
+
+	if after != nil {
+		newSelectors = after.Selectors
+	}
+
+	for i := range max(len(oldSelectors), len(newSelectors)) {
+		path := Path{"body", "selectors[" + strconv.Itoa(i) + "]"}
+
+		switch {
+		case i >= len(newSelectors):
+			d.add(SelectorRemoved, path, oldSelectors[i].String(), "")
+		case i >= len(oldSelectors):
+			d.add(SelectorAdded, path, "", newSelectors[i].String())
+		default:
+			d.expression(path, oldSelectors[i], newSelectors[i])
+		}
+	}
+
+	oldByKeys := make(map[string]int, len(oldVariants))
+	for i, v := range oldVariants {
+		oldByKeys[variantKeys(v)] = i
+	}
+
+	newKeys := make(map[string]struct{}, len(newVariants))
+
+	for i, v := range newVariants {
+		keys := variantKeys(v)
+		newKeys[keys] = struct{}{}
+		path := variantPath(after, i)
+
+		j, ok := oldByKeys[keys]
+		if !ok {
+			d.add(VariantAdded, path, "", v.String())
+			continue
+		}
+
+		d.pattern(sub(path, "pattern"), oldVariants[j].QuotedPattern, v.QuotedPattern)
+	}
+
+	for i, v := range oldVariants {
+		if _, ok := newKeys[variantKeys(v)]; !ok {
+			d.add(VariantRemoved, variantPath(before, i), v.String(), "")
+		}
+	}
+}
+
+func variantsOf(matcher *Matcher, pattern []PatternPart) []Variant {
+	if matcher == nil {
+		return []Variant{{QuotedPattern: pattern}}
+	}
+
+	return matcher.Variants
+}
+
+func variantKeys(variant Variant) string {
+	var sb strings.Builder
+
+	writeSlice(&sb, variant.Keys, " ")
+
+	return sb.String()
+}
+
+func variantPath(matcher *Matcher, i int) Path {
+	if matcher == nil {
+		return Path{"body"}
+	}
+
+	return Path{"body", "variants[" + strconv.Itoa(i) + "]"}
+}
+
+// pattern compares the text and the placeholders of the patterns.
+func (d *differ) pattern(path Path, before, after []PatternPart) {
+	if oldText, newText := patternText(before), patternText(after); oldText != newText {
+		d.add(TextChanged, path, oldText, newText)
+	}
+
+	oldPlaceholders, newPlaceholders := placeholders(before), placeholders(after)
+
+	for i := range max(len(oldPlaceholders), len(newPlaceholders)) {
+		switch {
+		case i >= len(newPlaceholders):
+			p := oldPlaceholders[i]
+			d.add(PlaceholderRemoved, partPath(path, p.index), p.part.String(), "")
+		case i >= len(oldPlaceholders):
+			p := newPlaceholders[i]
+			d.add(PlaceholderAdded, partPath(path, p.index), "", p.part.String())
+		default:
+			d.placeholder(partPath(path, newPlaceholders[i].index), oldPlaceholders[i].part, newPlaceholders[i].part)
+		}
+	}
+}
+
+func (d *differ) placeholder(path Path, before, after PatternPart) {
+	if o, ok := before.(Expression); ok {
+		if n, ok := after.(Expression); ok {
+			d.expression(path, o, n)
+			return
+		}
+	}
+
+	if o, ok := before.(Markup); ok {
+		if n, ok := after.(Markup); ok && o.Typ == n.Typ && o.Identifier == n.Identifier {
+			d.options(path, o.Options, n.Options)
+			return
+		}
+	}
+
+	if before.String() != after.String() {
+		d.add(PlaceholderChanged, path, before.String(), after.String())
+	}
+}
+
+func (d *differ) expression(path Path, before, after Expression) {
+	oldVar, oldIsVar := before.Operand.(Variable)
+	newVar, newIsVar := after.Operand.(Variable)
+
+	switch {
+	case oldIsVar && newIsVar:
+		if oldVar != newVar {
+			d.add(VariableRenamed, sub(path, "operand"), oldVar.String(), newVar.String())
+		}
+	case before.Operand == nil && after.Operand == nil:
+	case before.Operand == nil || after.Operand == nil || before.Operand.String() != after.Operand.String():
+		d.add(PlaceholderChanged, path, before.String(), after.String())
+		return
+	}
+
+	oldFunc, oldIsFunc := before.Annotation.(Function)
+	newFunc, newIsFunc := after.Annotation.(Function)
+
+	switch {
+	case oldIsFunc && newIsFunc && oldFunc.Identifier == newFunc.Identifier:
+		d.options(sub(path, "annotation"), oldFunc.Options, newFunc.Options)
+	case before.Annotation == nil && after.Annotation == nil:
+	case before.Annotation == nil || after.Annotation == nil || before.Annotation.String() != after.Annotation.String():
+		d.add(FunctionChanged, sub(path, "annotation"), nodeOrEmpty(before.Annotation), nodeOrEmpty(after.Annotation))
+	}
+}
+
+// options compares the options by their names.
+func (d *differ) options(path Path, before, after []Option) {
+	oldByName := make(map[Identifier]Option, len(before))
+	for _, o := range before {
+		oldByName[o.Identifier] = o
+	}
+
+	newNames := make(map[Identifier]struct{}, len(after))
+
+	for i, n := range after {
+		newNames[n.Identifier] = struct{}{}
+		optionPath := sub(path, "options["+strconv.Itoa(i)+"]")
+
+		switch o, ok := oldByName[n.Identifier]; {
+		case !ok:
+			d.add(OptionChanged, optionPath, "", n.String())
+		case o.Value.String() != n.Value.String():
+			d.add(OptionChanged, optionPath, o.String(), n.String())
+		}
+	}
+
+	for i, o := range before {
+		if _, ok := newNames[o.Identifier]; !ok {
+			d.add(OptionChanged, sub(path, "options["+strconv.Itoa(i)+"]"), o.String(), "")
+		}
+	}
+}
+
+type placeholder struct {
+	part  PatternPart
+	index int
+}
+
+func placeholders(pattern []PatternPart) []placeholder {
+	var s []placeholder
+
+	for i, part := range pattern {
+		if _, ok := part.(Text); !ok {
+			s = append(s, placeholder{part: part, index: i})
+		}
+	}
+
+	return s
+}
+
+func partPath(path Path, i int) Path {
+	last := path[len(path)-1] + "[" + strconv.Itoa(i) + "]"
+
+	return append(path[:len(path)-1:len(path)-1], last)
+}
+
+// patternText returns the text of the pattern with the placeholders written as "{}".
+func patternText(pattern []PatternPart) string {
+	var sb strings.Builder
+
+	for _, part := range pattern {
+		if text, ok := part.(Text); ok {
+			sb.WriteString(string(text))
+		} else {
+			sb.WriteString("{}")
+		}
+	}
+
+	return sb.String()
+}
+
+// sub returns the path of the child element, the path is not modified.
+func sub(path Path, elem string) Path {
+	return append(path[:len(path):len(path)], elem)
+}
+
+func nodeOrEmpty(node Node) string {
+	if node == nil {
+		return ""
+	}
+
+	return node.String()
+}
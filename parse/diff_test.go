@@ -0,0 +1,93 @@
+package parse
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		name, before, after string
+		want                []string
+	}{
+		{name: "equal", before: "Hello, { $name }!", after: "Hello, { $name }!"},
+		{
+			name:   "text changed",
+			before: "Hello, { $name }!",
+			after:  "Hi, { $name }!",
+			want:   []string{`pattern: text changed: "Hello, {}!" -> "Hi, {}!"`},
+		},
+		{
+			name:   "variable renamed",
+			before: "Hello, { $name }!",
+			after:  "Hello, { $user }!",
+			want:   []string{`pattern[1].operand: variable renamed: "$name" -> "$user"`},
+		},
+		{
+			name:   "option changed",
+			before: "{ $n :number style=percent minimumFractionDigits=1 }",
+			after:  "{ $n :number style=decimal useGrouping=false }",
+			want: []string{
+				`pattern[0].annotation.options[0]: option changed: "style = percent" -> "style = decimal"`,
+				`pattern[0].annotation.options[1]: option changed: "" -> "useGrouping = false"`,
+				`pattern[0].annotation.options[1]: option changed: "minimumFractionDigits = 1" -> ""`,
+			},
+		},
+		{
+			name:   "function and placeholders",
+			before: "{ $n :number } { $m }",
+			after:  "{ $n :integer } { $m } {#b/}",
+			want: []string{
+				`pattern: text changed: "{} {}" -> "{} {} {}"`,
+				`pattern[0].annotation: function changed: ":number" -> ":integer"`,
+				`pattern[4]: placeholder added: "" -> "{ #b /}"`,
+			},
+		},
+		{
+			name:   "variants",
+			before: ".input { $n :number } .match { $n } one {{one}} * {{other}}",
+			after:  ".input { $n :number } .local $x = { $n } .match { $n } few {{few}} * {{others}}",
+			want: []string{
+				`declarations[1]: declaration added: "" -> ".local $x = { $n }"`,
+				`body.variants[0]: variant added: "" -> "few {{few}}"`,
+				`body.variants[1].pattern: text changed: "other" -> "others"`,
+				`body.variants[0]: variant removed: "one {{one}}" -> ""`,
+			},
+		},
+		{
+			name:   "selector added",
+			before: "Hello!",
+			after:  ".match { $n :number } * {{Hello!}}",
+			want: []string{
+				`body.selectors[0]: selector added: "" -> "{ $n :number }"`,
+				`body.variants[0]: variant added: "" -> "* {{Hello!}}"`,
+				`body: variant removed: " {{Hello!}}" -> ""`,
+			},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			before, err := Parse(test.before)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			after, err := Parse(test.after)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got []string
+			for _, change := range Diff(before, after) {
+				got = append(got, change.String())
+			}
+
+			if !slices.Equal(test.want, got) {
+				t.Errorf("want %q, got %q", test.want, got)
+			}
+		})
+	}
+}
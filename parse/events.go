@@ -0,0 +1,156 @@
+package parse
+
+import "errors"
+
+// EventKind is the kind of the parse [Event].
+type EventKind int
+
+const (
+	// EventMessageStart starts the message, the node is nil.
+	EventMessageStart EventKind = iota
+	// EventMessageEnd ends the message, the node is nil.
+	EventMessageEnd
+	// EventDeclarationStart starts the input or local declaration, or the reserved statement, the node is nil.
+	EventDeclarationStart
+	// EventDeclarationEnd ends the declaration, the node is [InputDeclaration], [LocalDeclaration]
+	// or [ReservedStatement].
+	EventDeclarationEnd
+	// EventVariantStart starts the variant of the matcher, the node is [Variant] without the pattern.
+	EventVariantStart
+	// EventVariantEnd ends the variant, the node is [Variant] without the pattern.
+	EventVariantEnd
+	// EventExpressionStart starts the expression in the declaration, selector or pattern, the node is nil.
+	EventExpressionStart
+	// EventExpressionEnd ends the expression, the node is [Expression].
+	EventExpressionEnd
+	// EventText is the text of the pattern, the node is [Text].
+	EventText
+	// EventMarkup is the markup of the pattern, the node is [Markup].
+	EventMarkup
+)
+
+// String returns the name of the event kind.
+func (k EventKind) String() string {
+	switch k {
+	default:
+		return "unknown"
+	case EventMessageStart:
+		return "message start"
+	case EventMessageEnd:
+		return "message end"
+	case EventDeclarationStart:
+		return "declaration start"
+	case EventDeclarationEnd:
+		return "declaration end"
+	case EventVariantStart:
+		return "variant start"
+	case EventVariantEnd:
+		return "variant end"
+	case EventExpressionStart:
+		return "expression start"
+	case EventExpressionEnd:
+		return "expression end"
+	case EventText:
+		return "text"
+	case EventMarkup:
+		return "markup"
+	}
+}
+
+// Event is the parse event emitted by [Events].
+type Event struct {
+	// Node is the parsed node of the event, see [EventKind].
+	Node Node
+	Kind EventKind
+}
+
+/*
+Events parses the input and calls the handler for every event in the order of the input,
+without building the [AST]: the patterns, declarations and variants are not retained.
+The handler error stops the parsing and is returned as is.
+
+The events of the message ".local $x = {1} .match {$x :number} one {{One}} * {{Other}}":
+
+	message start
+	declaration start
+	expression start
+	expression end       { 1 }
+	declaration end      .local $x = { 1 }
+	expression start
+	expression end       { $x :number }
+	variant start        one
+	text                 One
+	variant end          one
+	variant start        *
+	text                 Other
+	variant end          *
+	message end
+
+The syntax and data model errors are the same as of [Parse], the events before the error are emitted.
+*/
+func Events(input string, handler func(Event) error, options ...ParseOption) error {
+	p, err := newParser(input, options...)
+	if err != nil {
+		return err
+	}
+
+	p.handler = handler
+
+	if err := p.emit(EventMessageStart, nil); err != nil {
+		return err
+	}
+
+	if _, err := p.parseMessage(); err != nil {
+		if p.handlerErr != nil {
+			return p.handlerErr
+		}
+
+		return err
+	}
+
+	return p.emit(EventMessageEnd, nil)
+}
+
+// errHandler wraps the handler error to stop the parsing, [Events] returns the original error.
+var errHandler = errors.New("event handler")
+
+// emit calls the event handler, if any.
+func (p *parser) emit(kind EventKind, node Node) error {
+	if p.handler == nil {
+		return nil
+	}
+
+	if err := p.handler(Event{Kind: kind, Node: node}); err != nil {
+		p.handlerErr = err
+		return errHandler
+	}
+
+	return nil
+}
+
+// streaming reports whether the parsed nodes are emitted as events instead of retained.
+func (p *parser) streaming() bool {
+	return p.handler != nil
+}
+
+// addPart emits the pattern part, or appends it to the pattern if not streaming.
+func (p *parser) addPart(pattern *[]PatternPart, kind EventKind, part PatternPart) error {
+	if p.streaming() {
+		return p.emit(kind, part)
+	}
+
+	*pattern = append(*pattern, part)
+
+	return nil
+}
+
+// addDeclaration emits the declaration, or appends it to the message if not streaming.
+func (p *parser) addDeclaration(message *ComplexMessage, declaration Declaration) error {
+	if p.streaming() {
+		return p.emit(EventDeclarationEnd, declaration)
+	}
+
+	message.Declarations = append(message.Declarations, declaration)
+
+	return nil
+}
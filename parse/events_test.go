@@ -0,0 +1,103 @@
+package parse
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"go.expect.digital/mf2"
+)
+
+func TestEvents(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		name, in string
+		want     []string
+	}{
+		{
+			name: "simple message",
+			in:   "Hello, { $name :string }!{#b/}",
+			want: []string{
+				"message start",
+				"text Hello, ",
+				"expression start",
+				"expression end { $name :string }",
+				"text !",
+				"markup { #b /}",
+				"message end",
+			},
+		},
+		{
+			name: "complex message",
+			in:   ".local $x = {1} .match {$x :number} one {{One}} * {{Other}}",
+			want: []string{
+				"message start",
+				"declaration start",
+				"expression start",
+				"expression end { 1 }",
+				"declaration end .local $x = { 1 }",
+				"expression start",
+				"expression end { $x :number }",
+				"variant start one {{}}",
+				"text One",
+				"variant end one {{}}",
+				"variant start * {{}}",
+				"text Other",
+				"variant end * {{}}",
+				"message end",
+			},
+		},
+		{
+			name: "empty",
+			want: []string{"message start", "message end"},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			var got []string
+
+			err := Events(test.in, func(e Event) error {
+				if e.Node == nil {
+					got = append(got, e.Kind.String())
+				} else {
+					got = append(got, e.Kind.String()+" "+e.Node.String())
+				}
+
+				return nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if want, got := strings.Join(test.want, "\n"), strings.Join(got, "\n"); want != got {
+				t.Errorf("want '%s', got '%s'", want, got)
+			}
+		})
+	}
+}
+
+func TestEventsError(t *testing.T) {
+	t.Parallel()
+
+	stop := errors.New("stop")
+
+	var count int
+
+	err := Events("{$a} {$b}", func(e Event) error {
+		if e.Kind == EventExpressionEnd {
+			count++
+			return stop
+		}
+
+		return nil
+	})
+	if !errors.Is(err, stop) || count != 1 {
+		t.Errorf("want '%s' after 1 expression, got '%v' after %d", stop, err, count)
+	}
+
+	if err := Events("{$a", func(Event) error { return nil }); !errors.Is(err, mf2.ErrSyntax) {
+		t.Errorf("want '%s', got '%v'", mf2.ErrSyntax, err)
+	}
+}
@@ -0,0 +1,77 @@
+package parse
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+type fingerprint struct {
+	structureOnly bool
+}
+
+// FingerprintOption is an option of [Fingerprint].
+type FingerprintOption func(*fingerprint)
+
+// StructureOnly makes [Fingerprint] ignore the text of the patterns, only the declarations,
+// selectors, variant keys, placeholders and markup are hashed.
+func StructureOnly() FingerprintOption {
+	return func(f *fingerprint) {
+		f.structureOnly = true
+	}
+}
+
+// Fingerprint returns the stable hex encoded SHA-256 hash of the canonical message, see [Canonical].
+// The order of options and attributes and the insignificant whitespace do not change the fingerprint.
+//
+// Store the fingerprint of the source message with the translation to detect when the translation
+// becomes stale, e.g. use [StructureOnly] to ignore the typo fixes in the source message.
+func Fingerprint(ast AST, options ...FingerprintOption) string {
+	var f fingerprint
+
+	for _, o := range options {
+		o(&f)
+	}
+
+	canonical := Canonical(ast)
+
+	if f.structureOnly {
+		canonical = withoutText(canonical)
+	}
+
+	sum := sha256.Sum256([]byte(canonical.String()))
+
+	return hex.EncodeToString(sum[:])
+}
+
+// withoutText returns the AST with text removed from the patterns. The patterns of the AST are copied.
+func withoutText(ast AST) AST {
+	switch message := ast.Message.(type) {
+	case SimpleMessage:
+		ast.Message = SimpleMessage(patternWithoutText(message))
+	case ComplexMessage:
+		switch body := message.ComplexBody.(type) {
+		case QuotedPattern:
+			message.ComplexBody = QuotedPattern(patternWithoutText(body))
+		case Matcher:
+			for i, variant := range body.Variants {
+				body.Variants[i].QuotedPattern = patternWithoutText(variant.QuotedPattern)
+			}
+		}
+
+		ast.Message = message
+	}
+
+	return ast
+}
+
+func patternWithoutText(pattern []PatternPart) []PatternPart {
+	var parts []PatternPart
+
+	for _, part := range pattern {
+		if _, ok := part.(Text); !ok {
+			parts = append(parts, part)
+		}
+	}
+
+	return parts
+}
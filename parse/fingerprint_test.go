@@ -0,0 +1,64 @@
+package parse
+
+import "testing"
+
+func TestFingerprint(t *testing.T) {
+	t.Parallel()
+
+	fingerprint := func(in string, options ...FingerprintOption) string {
+		t.Helper()
+
+		ast, err := Parse(in)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return Fingerprint(ast, options...)
+	}
+
+	for _, test := range []struct {
+		name      string
+		a, b      string
+		options   []FingerprintOption
+		wantEqual bool
+	}{
+		{name: "whitespace", a: "Hi, {$n :number style=percent}", b: "Hi, { $n :number  style = percent }", wantEqual: true},
+		{name: "option order", a: "{$n :number a=1 b=2 @x @y}", b: "{$n :number b=2 a=1 @y @x}", wantEqual: true},
+		{name: "text", a: "Hello, { $name }!", b: "Hi, { $name }!"},
+		{name: "option", a: "{ $n :number a=1 }", b: "{ $n :number a=2 }"},
+		{
+			name:      "structure only, text",
+			a:         ".match {$n :number} one {{one {$n}}} * {{{$n} others}}",
+			b:         ".match {$n :number} one {{1 {$n}}} * {{{$n} items}}",
+			options:   []FingerprintOption{StructureOnly()},
+			wantEqual: true,
+		},
+		{
+			name:    "structure only, placeholder",
+			a:       "Hello, { $name }!",
+			b:       "Hello, { $user }!",
+			options: []FingerprintOption{StructureOnly()},
+		},
+		{
+			name:    "structure only, variant",
+			a:       ".match {$n :number} one {{one}} * {{other}}",
+			b:       ".match {$n :number} few {{one}} * {{other}}",
+			options: []FingerprintOption{StructureOnly()},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			a, b := fingerprint(test.a, test.options...), fingerprint(test.b, test.options...)
+
+			if test.wantEqual != (a == b) {
+				t.Errorf("want equal %t, got '%s' and '%s'", test.wantEqual, a, b)
+			}
+		})
+	}
+
+	// stable, the hash of the canonical message
+	if want, got := "334d016f755cd6dc58c53a86e183882f8ec14f52fb05345887c8a5edd42c87b7", fingerprint("Hello!"); want != got {
+		t.Errorf("want '%s', got '%s'", want, got)
+	}
+}
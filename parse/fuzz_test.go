@@ -0,0 +1,103 @@
+package parse
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// FuzzParse tests that the valid message survives the round trip, see [RoundTrip].
+func FuzzParse(f *testing.F) {
+	for _, seed := range []string{
+		"",
+		"Hello, World!",
+		`Hello, \{ \} \\ { $name }`,
+		"{ |quoted \\| literal| } { name } { 1.2e-3 }",
+		"{ $x :number minimumFractionDigits=2 style=percent @attr=value }",
+		"{ :u:function u:option=$y @u:id=1 }",
+		"{ #b href=|x| @translate=no }bold{ /b } { #br /}",
+		"{ !reserved |body| } { ^private text }",
+		".input { $n :number } .local $x = { $n :integer } {{{ $x }}}",
+		".input { $n :number } .match { $n } 0 {{zero}} one {{one}} * {{other}}",
+		".match { $a :string } { $b :string } a b {{ab}} * * {{other}}",
+		".reserved { $x } {{}}",
+		"{{.dot}}",
+		// regressions
+		"{ !",
+		".reserved",
+	} {
+		f.Add(seed)
+	}
+
+	// the regression corpus, see parsetest.Corpus
+	names, err := filepath.Glob("parsetest/corpus/*.mf2")
+	if err != nil {
+		f.Fatal(err)
+	}
+
+	for _, name := range names {
+		b, err := os.ReadFile(name)
+		if err != nil {
+			f.Fatal(err)
+		}
+
+		f.Add(string(b))
+	}
+
+	f.Fuzz(func(t *testing.T, s string) {
+		if err := RoundTrip(s); errors.Is(err, ErrRoundTrip) {
+			t.Errorf("'%s': %s", s, err)
+		}
+	})
+}
+
+// FuzzUnmarshalBinary tests that the decoded binary AST is valid, i.e. it survives the binary round trip.
+func FuzzUnmarshalBinary(f *testing.F) {
+	for _, seed := range []string{
+		"Hello, { $name }!",
+		"{ $x :number minimumFractionDigits=2 @attr=value } { #b href=|x| }bold{ /b }",
+		".input { $n :number } .local $x = { $n :integer } {{{ $x }}}",
+		".input { $n :number } .match { $n } 0 {{zero}} one {{one}} * {{other}}",
+		".reserved { $x } {{}}",
+	} {
+		ast, err := Parse(seed)
+		if err != nil {
+			f.Fatal(err)
+		}
+
+		data, err := ast.MarshalBinary()
+		if err != nil {
+			f.Fatal(err)
+		}
+
+		f.Add(data)
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var want AST
+
+		if err := want.UnmarshalBinary(data); err != nil {
+			if !errors.Is(err, errInvalidBinary) {
+				t.Errorf("want '%s', got '%s'", errInvalidBinary, err)
+			}
+
+			return
+		}
+
+		b, err := want.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var got AST
+
+		if err := got.UnmarshalBinary(b); err != nil {
+			t.Fatalf("'%s': %s", want, err)
+		}
+
+		if changes := Diff(want, got); len(changes) != 0 {
+			t.Errorf("want no changes, got %v", changes)
+		}
+	})
+}
@@ -1,7 +1,6 @@
 package parse
 
 import (
-	"encoding/json"
 	"fmt"
 	"strings"
 	"unicode/utf8"
@@ -117,6 +116,8 @@ type item struct {
 	err error
 	val string
 	typ itemType
+	// pos is the byte offset of the item in the input, the item ends where the next starts.
+	pos int
 }
 
 func (i item) String() string {
@@ -149,12 +150,20 @@ func lex(input string) *lexer {
 
 // lexer is a lexical analyzer for MessageFormat2.
 //
+// Item values are slices of the input, only values with escaped characters are copied.
+//
 // See ".message-format-wg/spec/message.abnf".
 type lexer struct {
-	input     string
-	item      item
+	input string
+	item  item
+	// sb holds the unescaped value of the current item if escaped is true.
+	sb        strings.Builder
 	prevType  itemType // prev non-whitespace
 	pos, line int
+	// start is the position of the not yet copied value of the current item.
+	start int
+
+	escaped,
 
 	isFunction,
 	isMarkup,
@@ -200,10 +209,50 @@ func (l *lexer) backup() {
 	l.pos -= n
 }
 
+// acceptFunc advances the position while runes satisfy f.
+func (l *lexer) acceptFunc(f func(rune) bool) {
+	for {
+		if r := l.next(); r == eof || !f(r) {
+			if r != eof {
+				l.backup()
+			}
+
+			return
+		}
+	}
+}
+
+// startValue starts the value of the item at the current position.
+func (l *lexer) startValue() {
+	l.start = l.pos
+	l.escaped = false
+	l.sb.Reset()
+}
+
+// escape replaces the input from the escape sequence at pos up to the current position with s.
+func (l *lexer) escape(pos int, s string) {
+	l.sb.WriteString(l.input[l.start:pos])
+	l.sb.WriteString(s)
+	l.start = l.pos
+	l.escaped = true
+}
+
+// value returns the value of the item ending at the position end.
+func (l *lexer) value(end int) string {
+	if !l.escaped {
+		return l.input[l.start:end]
+	}
+
+	l.sb.WriteString(l.input[l.start:end])
+
+	return l.sb.String()
+}
+
 // nextItem returns the next item in the input string.
 func (l *lexer) nextItem() item {
 	l.emitItem(mk(itemEOF, ""))
 
+	pos := l.pos
 	state := lexPattern
 
 	// Sorted by children first - expression can be inside pattern but pattern
@@ -219,6 +268,8 @@ func (l *lexer) nextItem() item {
 
 	for {
 		if state := state(l); state == nil {
+			l.item.pos = pos
+
 			return l.item
 		}
 	}
@@ -245,7 +296,7 @@ type stateFn func(*lexer) stateFn
 
 // lexPattern is the state function for lexing patterns.
 func lexPattern(l *lexer) stateFn {
-	var s string
+	l.startValue()
 
 	for {
 		r := l.next()
@@ -256,14 +307,16 @@ func lexPattern(l *lexer) stateFn {
 			l.backup()
 			l.isPattern = false
 
-			return l.emitItem(mk(itemText, s))
+			return l.emitItem(mk(itemText, l.value(l.pos)))
 		case r == '\\':
+			pos := l.pos - 1
+
 			next := l.next()
 			if !isEscapedChar(next) {
 				return l.emitErrorf("unexpected escaped char in pattern: %s", string(next))
 			}
 
-			s += string(next)
+			l.escape(pos, string(next))
 		case r == '{':
 			if l.peek() == '{' { // complex message without declarations
 				l.backup()
@@ -272,17 +325,13 @@ func lexPattern(l *lexer) stateFn {
 
 			l.backup()
 
-			if len(s) > 0 {
+			if s := l.value(l.pos); len(s) > 0 {
 				l.isExpression = true
 
 				return l.emitItem(mk(itemText, s))
 			}
 
 			return lexExpr(l)
-		case !l.isComplexMessage && len(s) == 0 && r == '.':
-			l.backup()
-
-			return lexComplexMessage(l)
 		case r == '}':
 			if l.peek() != '}' { // pattern end in complex message?
 				return l.emitErrorf("unescaped } in pattern")
@@ -291,16 +340,16 @@ func lexPattern(l *lexer) stateFn {
 			l.backup()
 			l.isPattern = false
 
-			if len(s) > 0 {
+			if s := l.value(l.pos); len(s) > 0 {
 				return l.emitItem(mk(itemText, s))
 			}
 
 			return lexComplexMessage(l)
-		case !l.isComplexMessage && len(s) == 0 && isSimpleStart(r),
-			isText(r) && (l.isComplexMessage || len(s) >= 1):
-			s += string(r)
+		// Simple message never starts with ".", otherwise it is lexed as complex message.
+		// Any text char is allowed after the start, including text following expressions.
+		case isText(r):
 		case r == eof:
-			if len(s) > 0 {
+			if s := l.value(l.pos); len(s) > 0 {
 				return l.emitItem(mk(itemText, s))
 			}
 
@@ -432,11 +481,11 @@ func lexExpr(l *lexer) stateFn {
 	case isReservedStart(v):
 		l.isReservedBody = true
 
-		return l.emitItem(mk(itemReservedStart, string(v)))
+		return l.emitItem(mk(itemReservedStart, l.input[l.pos-1:l.pos]))
 	case isPrivateStart(v):
 		l.isReservedBody = true
 
-		return l.emitItem(mk(itemPrivateStart, string(v)))
+		return l.emitItem(mk(itemPrivateStart, l.input[l.pos-1:l.pos]))
 	case v == '=':
 		return l.emitItem(mk(itemOperator, "="))
 	}
@@ -444,12 +493,12 @@ func lexExpr(l *lexer) stateFn {
 
 // lexQuotedLiteral is the state function for lexing quoted literals.
 func lexQuotedLiteral(l *lexer) stateFn {
-	var s string
-
 	if r := l.next(); r != '|' {
 		return l.emitErrorf(`unexpected opening character in quoted literal: "%s"`, string(r))
 	}
 
+	l.startValue()
+
 	for {
 		r := l.next()
 
@@ -457,17 +506,17 @@ func lexQuotedLiteral(l *lexer) stateFn {
 		default:
 			return l.emitErrorf(`unknown character in quoted literal: "%s"`, string(r))
 		case isQuoted(r):
-			s += string(r)
 		case r == '|': // closing
-			return l.emitItem(mk(itemQuotedLiteral, s))
+			return l.emitItem(mk(itemQuotedLiteral, l.value(l.pos-1)))
 		case r == '\\':
+			pos := l.pos - 1
 			next := l.next()
 
 			switch next {
 			default:
 				return l.emitErrorf(`unexpected escaped character in quoted literal: "%s"`, string(r))
 			case '\\', '|':
-				s += string(next)
+				l.escape(pos, string(next))
 			case eof:
 				return l.emitErrorf("unexpected eof in quoted literal")
 			}
@@ -477,17 +526,20 @@ func lexQuotedLiteral(l *lexer) stateFn {
 
 // lexUnquotedOrNumberLiteral is the state function for lexing names.
 func lexUnquotedOrNumberLiteral(l *lexer) stateFn {
-	var s string
+	start := l.pos
 
-	for r := l.next(); isName(r) || r == '+'; r = l.next() {
-		s += string(r)
-	}
+	l.acceptFunc(func(r rune) bool { return isName(r) || r == '+' })
 
-	l.backup()
+	s := l.input[start:l.pos]
 
-	var number float64
+	// not a literal, e.g. the unterminated quoted literal `{"}`, the lexer must not stall on it
+	if s == "" {
+		r, _ := utf8.DecodeRuneInString(l.input[l.pos:])
+
+		return l.emitErrorf(`unexpected character in expression: "%s"`, string(r))
+	}
 
-	if err := json.Unmarshal([]byte(s), &number); err == nil {
+	if isNumberLiteral(s) {
 		return l.emitItem(mk(itemNumberLiteral, s))
 	}
 
@@ -501,66 +553,55 @@ func lexUnquotedOrNumberLiteral(l *lexer) stateFn {
 
 // lexLiteral is the state function for lexing variables.
 func lexVariable(l *lexer) stateFn {
-	var s string
-
 	if r := l.next(); r != variablePrefix {
 		return l.emitErrorf(`invalid variable prefix "%s"`, string(r))
 	}
 
-	for r := l.next(); isName(r); r = l.next() {
-		s += string(r)
-	}
+	start := l.pos
 
-	l.backup()
+	l.acceptFunc(isName)
 
-	return l.emitItem(mk(itemVariable, s))
+	return l.emitItem(mk(itemVariable, l.input[start:l.pos]))
 }
 
 // lexLiteral is the state function for reserved keywords.
 func lexReservedKeyword(l *lexer) stateFn {
-	var s string
-
 	if r := l.next(); r != '.' {
 		return l.emitErrorf(`invalid reserved keyword prefix "%s"`, string(r))
 	}
 
-	for r := l.next(); isName(r); r = l.next() {
-		s += string(r)
-	}
+	start := l.pos
 
-	l.backup()
+	l.acceptFunc(isName)
+
+	s := l.input[start:l.pos]
+	if s == "" {
+		return l.emitErrorf("missing reserved keyword name")
+	}
 
 	return l.emitItem(mk(itemReservedKeyword, s))
 }
 
 // lexWhitespace is the state function for lexing whitespace.
 func lexWhitespace(l *lexer) stateFn {
-	var ws []rune
+	start := l.pos
 
-	for {
-		v := l.next()
+	l.acceptFunc(isWhitespace)
 
-		switch {
-		default:
-			l.backup()
-			return l.emitItem(mk(itemWhitespace, string(ws)))
-		case v == eof:
-			return l.emitItem(mk(itemWhitespace, string(ws)))
-		case isWhitespace(v):
-			ws = append(ws, v)
-		}
-	}
+	return l.emitItem(mk(itemWhitespace, l.input[start:l.pos]))
 }
 
 // lexIdentifier is the state function for lexing identifiers.
 func lexIdentifier(l *lexer) stateFn {
 	var (
-		s   string
 		ns  bool
 		typ itemType
 	)
 
+	start := l.pos
+
 	for {
+		s := l.input[start:l.pos]
 		r := l.next()
 
 		switch {
@@ -580,34 +621,32 @@ func lexIdentifier(l *lexer) stateFn {
 			switch r {
 			default:
 				typ = itemOption
-				s += string(r)
 			case ':':
 				l.isFunction = true
 				typ = itemFunction
+				start = l.pos
 			case '#':
 				l.isMarkup = true
 				typ = itemMarkupOpen
+				start = l.pos
 			case '/':
 				l.isMarkup = true
 				typ = itemMarkupClose
+				start = l.pos
 			case '@':
 				l.isFunction = false
 				typ = itemAttribute
+				start = l.pos
 			}
 		case isName(r):
-			s += string(r)
 		case len(s) > 0 && r == ':':
 			if ns {
 				return l.emitErrorf("namespace already defined in identifier: %s", s)
 			}
 
 			ns = true
-			s += string(r)
 		case r == eof:
 			return l.emitErrorf("unexpected eof in identifier")
-
-		case len(s) == 0 && isNameStart(r):
-			s = string(r)
 		}
 	}
 }
@@ -620,46 +659,98 @@ func lexIdentifier(l *lexer) stateFn {
 //	escaped-char       = backslash ( backslash / "{" / "|" / "}" )
 //	quoted             = "|" *(quoted-char / escaped-char) "|"
 func lexReservedBody(l *lexer) stateFn {
-	var s string
+	l.startValue()
 
 	for {
 		switch v := l.next(); {
+		default: // not allowed character is skipped
+			l.escape(l.pos-utf8.RuneLen(v), "")
+		case v == eof:
+			return l.emitErrorf("unexpected EOF in reserved body")
 		case v == '{', v == '}', v == '@':
 			l.backup()
 			l.isReservedBody = false
 
-			if s == "" {
-				return lexExpr(l)
+			if s := l.value(l.pos); s != "" {
+				return l.emitItem(mk(itemReservedText, s))
 			}
 
-			return l.emitItem(mk(itemReservedText, s))
+			return lexExpr(l)
 		case isWhitespace(v):
 			l.backup()
 
-			if s == "" {
-				return lexWhitespace(l)
+			if s := l.value(l.pos); s != "" {
+				return l.emitItem(mk(itemReservedText, s))
 			}
 
-			return l.emitItem(mk(itemReservedText, s))
+			return lexWhitespace(l)
 		case v == '|':
 			l.backup()
 			return lexQuotedLiteral(l)
 		case v == '\\': // escaped character
+			pos := l.pos - 1
 			next := l.next()
 
 			if !isEscapedChar(next) {
 				return l.emitErrorf("unexpected escaped character in reserved body: %s", string(v))
 			}
 
-			s += string(next)
+			l.escape(pos, string(next))
 		case isReserved(v):
-			s += string(v)
 		}
 	}
 }
 
 // helpers
 
+// isNumberLiteral returns true if s is a number literal.
+//
+// ABNF:
+//
+//	number-literal = ["-"] (%x30 / (%x31-39 *DIGIT)) ["." 1*DIGIT] [%i"e" ["-" / "+"] 1*DIGIT]
+func isNumberLiteral(s string) bool {
+	digits := func() int {
+		n := 0
+		for n < len(s) && '0' <= s[n] && s[n] <= '9' {
+			n++
+		}
+
+		s = s[n:]
+
+		return n
+	}
+
+	s = strings.TrimPrefix(s, "-")
+
+	if strings.HasPrefix(s, "0") {
+		s = s[1:]
+	} else if digits() == 0 {
+		return false
+	}
+
+	if strings.HasPrefix(s, ".") {
+		s = s[1:]
+
+		if digits() == 0 {
+			return false
+		}
+	}
+
+	if strings.HasPrefix(s, "e") || strings.HasPrefix(s, "E") {
+		s = s[1:]
+
+		if strings.HasPrefix(s, "-") || strings.HasPrefix(s, "+") {
+			s = s[1:]
+		}
+
+		if digits() == 0 {
+			return false
+		}
+	}
+
+	return s == ""
+}
+
 // isAlpha returns true if r is alphabetic character.
 func isAlpha(r rune) bool {
 	return ('a' <= r && r <= 'z') || ('A' <= r && r <= 'Z')
@@ -761,15 +852,6 @@ func isEscapedChar(r rune) bool {
 	return r == '\\' || r == '{' || r == '|' || r == '}'
 }
 
-// isSimpleStart returns true if r is simple start character.
-//
-// ABNF:
-//
-//	simple-start-char = content-char / s / "@" / "|"
-func isSimpleStart(r rune) bool {
-	return isContent(r) || isWhitespace(r) || r == '@' || r == '|'
-}
-
 // isText returns true if r is text character.
 //
 // ABNF:
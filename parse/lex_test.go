@@ -1,6 +1,7 @@
 package parse
 
 import (
+	"slices"
 	"testing"
 )
 
@@ -106,6 +107,18 @@ func Test_lex(t *testing.T) {
 				mk(itemEOF, ""),
 			},
 		},
+		{
+			name:  "text starting with dot after variable",
+			input: "Hello, {$guest}.",
+			want: []item{
+				mk(itemText, "Hello, "),
+				mk(itemExpressionOpen, "{"),
+				mk(itemVariable, "guest"),
+				mk(itemExpressionClose, "}"),
+				mk(itemText, "."),
+				mk(itemEOF, ""),
+			},
+		},
 		{
 			name:  "empty quoted literal",
 			input: "{||}",
@@ -545,6 +558,31 @@ func Test_lex(t *testing.T) {
 	}
 }
 
+func Test_lexPositions(t *testing.T) {
+	t.Parallel()
+
+	const input = ".local $x = {|a\\\\b| :f}\n{{ \\{ }}"
+
+	l := lex(input)
+
+	var got []int
+
+	for itm := l.nextItem(); itm.typ != itemEOF; itm = l.nextItem() {
+		if itm.typ == itemError {
+			t.Fatal(itm.err)
+		}
+
+		got = append(got, itm.pos)
+	}
+
+	// every item starts where the previous ends
+	want := []int{0, 6, 7, 9, 10, 11, 12, 13, 19, 20, 22, 23, 24, 26, 30}
+
+	if !slices.Equal(want, got) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+}
+
 func assertItems(t *testing.T, want []item, l *lexer) {
 	t.Helper()
 
@@ -563,6 +601,8 @@ func assertItems(t *testing.T, want []item, l *lexer) {
 			return
 		}
 
+		got.pos = 0 // see Test_lexPositions
+
 		if wantItem != got {
 			t.Errorf(`want '%v', got '%v'`, wantItem, got)
 
@@ -0,0 +1,68 @@
+package parse
+
+import "fmt"
+
+// DefaultMaxTokens is the default maximum number of tokens of the message, see [WithMaxTokens].
+// It is far above the messages written by hand, it bounds the memory of the parser for the untrusted input.
+const DefaultMaxTokens = 100_000
+
+// WithMaxTokens sets the maximum number of tokens of the message, [DefaultMaxTokens] by default.
+// The message with more tokens is a syntax error. The limit bounds the memory of the parser,
+// every token is kept until the message is parsed. Zero or less is unlimited.
+func WithMaxTokens(n int) ParseOption {
+	return func(p *parser) {
+		p.maxTokens = n
+	}
+}
+
+// WithMaxDepth sets the maximum nesting depth of the open markup, e.g. 2 for "{#b}{#i}text{/i}{/b}",
+// unlimited by default. The deeper nested message is a syntax error. The parser does not recurse
+// into the markup, the limit protects the consumers walking the markup recursively, e.g. rendering
+// it as a tree. Zero or less is unlimited.
+func WithMaxDepth(depth int) ParseOption {
+	return func(p *parser) {
+		p.maxDepth = depth
+	}
+}
+
+// nest tracks the markup nesting on the explicit stack of the open markup identifiers, only with [WithMaxDepth].
+// The close markup closes the nearest open markup of the same identifier and the markup nested in it,
+// the close markup without the open one is ignored. The open markup is counted by identifier,
+// the close markup without the open one is found in constant time, the markup is popped once.
+func (p *parser) nest(markup Markup) error {
+	if p.maxDepth <= 0 {
+		return nil
+	}
+
+	switch markup.Typ {
+	case Open:
+		p.markup = append(p.markup, markup.Identifier)
+
+		if p.open == nil {
+			p.open = make(map[Identifier]int)
+		}
+
+		p.open[markup.Identifier]++
+
+		if len(p.markup) > p.maxDepth {
+			return fmt.Errorf("markup nesting depth exceeds %d", p.maxDepth)
+		}
+	case Close:
+		if p.open[markup.Identifier] == 0 {
+			return nil
+		}
+
+		for {
+			last := p.markup[len(p.markup)-1]
+			p.markup = p.markup[:len(p.markup)-1]
+			p.open[last]--
+
+			if last == markup.Identifier {
+				break
+			}
+		}
+	case SelfClose: // noop
+	}
+
+	return nil
+}
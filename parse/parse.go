@@ -1,12 +1,14 @@
 package parse
 
 import (
-	"encoding/json"
 	"errors"
 	"fmt"
 	"slices"
+	"strconv"
 	"strings"
 
+	"golang.org/x/text/unicode/norm"
+
 	"go.expect.digital/mf2"
 )
 
@@ -16,10 +18,44 @@ type parser struct {
 	lexer            *lexer
 	items            []item
 	variables        []Variable
-	pos              int
+	// handler receives the parse events, see [Events].
+	handler func(Event) error
+	// handlerErr is the error returned by the handler, it stops the parsing.
+	handlerErr error
+	// markup is the stack of the open markup of the pattern, see [WithMaxDepth].
+	markup []Identifier
+	// open is the number of the open markup in the stack by identifier.
+	open map[Identifier]int
+	// arena allocates the AST, nil if not set, see [WithArena].
+	arena *Arena
+	// lines are the offsets of the line starts of the input, see [WithPositions].
+	lines          []int
+	version        SpecVersion
+	pos            int
+	maxTokens      int
+	maxDepth       int
+	skipValidation bool
+	positions      bool
+}
+
+// ParseOption is an option of [Parse].
+type ParseOption func(p *parser)
+
+// WithoutValidation skips the data model validation of the message: duplicate declarations,
+// variant key mismatch and missing fallback variant. Syntax errors are reported regardless.
+//
+// Use it only for trusted messages validated beforehand, e.g. catalogs generated at build time.
+func WithoutValidation() ParseOption {
+	return func(p *parser) {
+		p.skipValidation = true
+	}
 }
 
 func (p *parser) duplicateVariable(variable Variable) error {
+	if p.skipValidation {
+		return nil
+	}
+
 	if slices.Contains(p.variables, variable) {
 		return fmt.Errorf("%w: %s", mf2.ErrDuplicateDeclaration, variable)
 	}
@@ -28,7 +64,7 @@ func (p *parser) duplicateVariable(variable Variable) error {
 }
 
 func (p *parser) declareVariable(variable Variable) {
-	if !slices.Contains(p.variables, variable) {
+	if !p.skipValidation && !slices.Contains(p.variables, variable) {
 		p.variables = append(p.variables, variable)
 	}
 }
@@ -73,8 +109,11 @@ func (p *parser) current() item {
 }
 
 func (p *parser) collect() error {
-	// sanity check, avoid infinite loop
-	for range 1000 {
+	for {
+		if p.maxTokens > 0 && len(p.items) == p.maxTokens {
+			return fmt.Errorf("more than %d tokens", p.maxTokens)
+		}
+
 		itm := p.lexer.nextItem()
 		if itm.typ == itemError {
 			return itm.err
@@ -86,8 +125,6 @@ func (p *parser) collect() error {
 			return nil
 		}
 	}
-
-	return errors.New("too many tokens. infinite loop?")
 }
 
 // isComplexMessage returns true if first token is one of the complex message tokens.
@@ -103,6 +140,12 @@ func (p *parser) isComplexMessage() bool {
 /*
 Parse parses the input string and returns an AST tree of MessageFormat2.
 
+The grammar has no recursive productions, the call depth of the parser is bounded regardless
+of the input, and the markup, which nests only by convention, is not parsed recursively.
+The worst-case time and memory are O(n) of the input length n, except the validation of the
+duplicate declarations, O(d²) of the declared variables d. The number of tokens is limited
+by [DefaultMaxTokens], see [WithMaxTokens] and [WithMaxDepth] for the untrusted input.
+
 Examples:
 
 	mf2.Parse("Hello World!")
@@ -146,24 +189,57 @@ Examples:
 		},
 	}
 */
-func Parse(input string) (AST, error) {
-	errorf := func(format string, err error) (AST, error) {
+func Parse(input string, options ...ParseOption) (AST, error) {
+	p, err := newParser(input, options...)
+	if err != nil {
+		return AST{}, err
+	}
+
+	message, err := p.parseMessage()
+	if err != nil {
+		return AST{}, err
+	}
+
+	return AST{Message: message}, nil
+}
+
+// newParser returns the parser of the input with the options applied.
+func newParser(input string, options ...ParseOption) (*parser, error) {
+	p := &parser{lexer: lex(input), pos: -1, version: SpecDraft2024, maxTokens: DefaultMaxTokens}
+
+	for _, o := range options {
+		o(p)
+	}
+
+	if err := p.version.validate(); err != nil {
+		return nil, fmt.Errorf("parse MF2: %w", err)
+	}
+
+	return p, nil
+}
+
+// parseMessage parses the whole input, the message is nil if the input is empty.
+func (p *parser) parseMessage() (Message, error) {
+	errorf := func(format string, err error) (Message, error) {
 		// TODO(jhorsts): improve error handling, add MF2 syntax error as early as possible.
 		if errors.Is(err, mf2.ErrDuplicateDeclaration) {
-			return AST{}, fmt.Errorf("parse MF2: "+format, err)
+			return nil, fmt.Errorf("parse MF2: "+format, err)
 		}
 
 		// fallback to syntax error unless one of MF2 errors is returned
-		return AST{}, fmt.Errorf("parse MF2: %w: "+format, mf2.ErrSyntax, err)
+		return nil, fmt.Errorf("parse MF2: %w: "+format, mf2.ErrSyntax, err)
+	}
+
+	if p.arena != nil {
+		defer p.arena.acquire(p)()
 	}
 
-	p := &parser{lexer: lex(input), pos: -1}
 	if err := p.collect(); err != nil {
 		return errorf("%w", err)
 	}
 
 	if len(p.items) == 1 && p.items[0].typ == itemEOF {
-		return AST{}, nil
+		return nil, nil //nolint:nilnil
 	}
 
 	parse := func() (Message, error) { return p.parseSimpleMessage() }
@@ -180,7 +256,7 @@ func Parse(input string) (AST, error) {
 		return errorf("%w", unexpectedErr(itm, itemEOF))
 	}
 
-	return AST{Message: message}, nil
+	return message, nil
 }
 
 // ------------------------------Message------------------------------
@@ -197,6 +273,8 @@ func (p *parser) parseSimpleMessage() (SimpleMessage, error) {
 func (p *parser) parseComplexMessage() (ComplexMessage, error) {
 	var message ComplexMessage
 
+	start := p.items[p.pos+1].pos
+
 	errorf := func(format string, args ...any) (ComplexMessage, error) {
 		return ComplexMessage{}, fmt.Errorf("complex message: "+format, args...)
 	}
@@ -210,26 +288,48 @@ declarationsLoop:
 			p.backup()
 			break declarationsLoop
 		case itemInputKeyword:
+			if err := p.emit(EventDeclarationStart, nil); err != nil {
+				return errorf("%w", err)
+			}
+
 			declaration, err := p.parseInputDeclaration()
 			if err != nil {
 				return errorf("%w", err)
 			}
 
-			message.Declarations = append(message.Declarations, declaration)
+			if err := p.addDeclaration(&message, declaration); err != nil {
+				return errorf("%w", err)
+			}
 		case itemLocalKeyword:
+			if err := p.emit(EventDeclarationStart, nil); err != nil {
+				return errorf("%w", err)
+			}
+
 			declaration, err := p.parseLocalDeclaration()
 			if err != nil {
 				return errorf("%w", err)
 			}
 
-			message.Declarations = append(message.Declarations, declaration)
+			if err := p.addDeclaration(&message, declaration); err != nil {
+				return errorf("%w", err)
+			}
 		case itemReservedKeyword:
+			if err := p.reserved("reserved statement"); err != nil {
+				return errorf("%w", err)
+			}
+
+			if err := p.emit(EventDeclarationStart, nil); err != nil {
+				return errorf("%w", err)
+			}
+
 			declaration, err := p.parseReservedStatement()
 			if err != nil {
 				return errorf("%w", err)
 			}
 
-			message.Declarations = append(message.Declarations, declaration)
+			if err := p.addDeclaration(&message, declaration); err != nil {
+				return errorf("%w", err)
+			}
 		}
 	}
 
@@ -262,6 +362,12 @@ declarationsLoop:
 		return errorf("missing complex body")
 	}
 
+	if p.arena != nil {
+		message.Declarations = p.arena.declarations.copy(message.Declarations)
+	}
+
+	message.Span = p.spanFrom(start)
+
 	return message, nil
 }
 
@@ -269,12 +375,16 @@ declarationsLoop:
 
 // parsePattern parses a slice of pattern parts.
 func (p *parser) parsePattern() ([]PatternPart, error) {
-	var pattern []PatternPart
+	pattern := p.patternBuffer()
 
 	errorf := func(format string, args ...any) ([]PatternPart, error) {
 		return nil, fmt.Errorf("pattern: "+format, args...)
 	}
 
+	// the markup does not span the patterns
+	p.markup = p.markup[:0]
+	clear(p.open)
+
 	// Loop until the end, or closing pattern quote, if parsing complex message.
 	for {
 		switch itm := p.next(); itm.typ {
@@ -285,18 +395,31 @@ func (p *parser) parsePattern() ([]PatternPart, error) {
 			continue
 		case itemQuotedPatternClose, itemEOF:
 			p.backup()
-			return pattern, nil
+			return p.ownPattern(pattern), nil
 		case itemText:
-			pattern = append(pattern, Text(itm.val))
+			if err := p.addPart(&pattern, EventText, Text(itm.val)); err != nil {
+				return errorf("%w", err)
+			}
 		case itemExpressionOpen:
 			// markup?
 			if typ := p.peekNonWS().typ; typ == itemMarkupOpen || typ == itemMarkupClose {
+				start := p.start()
+
 				markup, err := p.parseMarkup()
 				if err != nil {
 					return errorf("%w", err)
 				}
 
-				pattern = append(pattern, markup)
+				markup = p.ownMarkup(markup)
+				markup.Span = p.spanFrom(start)
+
+				if err := p.nest(markup); err != nil {
+					return errorf("%w", err)
+				}
+
+				if err := p.addPart(&pattern, EventMarkup, markup); err != nil {
+					return errorf("%w", err)
+				}
 
 				continue
 			}
@@ -306,7 +429,9 @@ func (p *parser) parsePattern() ([]PatternPart, error) {
 				return errorf("%w", err)
 			}
 
-			pattern = append(pattern, expression)
+			if !p.streaming() {
+				pattern = append(pattern, expression)
+			}
 		}
 	}
 }
@@ -402,6 +527,27 @@ optionsLoop:
 // ------------------------------Expression------------------------------
 
 func (p *parser) parseExpression() (Expression, error) {
+	if err := p.emit(EventExpressionStart, nil); err != nil {
+		return Expression{}, fmt.Errorf("expression: %w", err)
+	}
+
+	start := p.start()
+
+	expr, err := p.parseExpressionBody()
+	if err != nil {
+		return Expression{}, err
+	}
+
+	expr.Span = p.spanFrom(start)
+
+	if err := p.emit(EventExpressionEnd, expr); err != nil {
+		return Expression{}, fmt.Errorf("expression: %w", err)
+	}
+
+	return expr, nil
+}
+
+func (p *parser) parseExpressionBody() (Expression, error) {
 	var (
 		expr Expression
 		err  error
@@ -421,12 +567,12 @@ func (p *parser) parseExpression() (Expression, error) {
 
 		return errorf("%w: %w", mf2.ErrBadOperand, err)
 	case itemVariable:
-		variable := Variable(itm.val)
+		variable := Variable(norm.NFC.String(itm.val))
 
 		switch p.declaration {
 		case "local":
 			// .local $foo = {$foo}
-			if variable == p.reservedVariable {
+			if !p.skipValidation && variable == p.reservedVariable {
 				return errorf("%w: %s", mf2.ErrDuplicateDeclaration, variable)
 			}
 		case "input":
@@ -473,10 +619,18 @@ func (p *parser) parseExpression() (Expression, error) {
 			return errorf("%w", err)
 		}
 	case itemReservedStart:
+		if err = p.reserved("reserved annotation"); err != nil {
+			return errorf("%w", err)
+		}
+
 		if expr.Annotation, err = p.parseReservedAnnotation(); err != nil {
 			return errorf("%w", err)
 		}
 	case itemPrivateStart:
+		if err = p.reserved("private use annotation"); err != nil {
+			return errorf("%w", err)
+		}
+
 		if expr.Annotation, err = p.parsePrivateUseAnnotation(); err != nil {
 			return errorf("%w", err)
 		}
@@ -515,10 +669,17 @@ func (p *parser) parseExpression() (Expression, error) {
 // ------------------------------Annotation------------------------------
 
 func (p *parser) parseFunction() (Function, error) {
+	start := p.start()
 	function := Function{Identifier: p.parseIdentifier()}
 
+	end := func() (Function, error) {
+		function.Span = p.spanFrom(start)
+
+		return p.ownFunction(function), nil
+	}
+
 	if p.peekNonWS().typ == itemExpressionClose {
-		return function, nil
+		return end()
 	}
 
 	errorf := func(format string, args ...any) (Function, error) {
@@ -546,16 +707,17 @@ func (p *parser) parseFunction() (Function, error) {
 			p.backup()
 			p.backup() // whitespace
 
-			return function, nil
+			return end()
 		}
 
 		if p.peekNonWS().typ == itemExpressionClose {
-			return function, nil
+			return end()
 		}
 	}
 }
 
 func (p *parser) parsePrivateUseAnnotation() (PrivateUseAnnotation, error) {
+	start := p.start()
 	annotation := PrivateUseAnnotation{Start: rune(p.current().val[0])}
 	errorf := func(format string, args ...any) (PrivateUseAnnotation, error) {
 		return PrivateUseAnnotation{}, fmt.Errorf("private use annotation: "+format, args...)
@@ -571,6 +733,8 @@ func (p *parser) parsePrivateUseAnnotation() (PrivateUseAnnotation, error) {
 	case itemExpressionClose:
 		p.backup()
 
+		annotation.Span = p.spanFrom(start)
+
 		return annotation, nil
 	}
 
@@ -580,10 +744,13 @@ func (p *parser) parsePrivateUseAnnotation() (PrivateUseAnnotation, error) {
 		return errorf("%w", err)
 	}
 
+	annotation.Span = p.spanFrom(start)
+
 	return annotation, nil
 }
 
 func (p *parser) parseReservedAnnotation() (ReservedAnnotation, error) {
+	start := p.start()
 	annotation := ReservedAnnotation{Start: rune(p.current().val[0])}
 	errorf := func(format string, args ...any) (ReservedAnnotation, error) {
 		return ReservedAnnotation{}, fmt.Errorf("reserved annotation: "+format, args...)
@@ -598,6 +765,8 @@ func (p *parser) parseReservedAnnotation() (ReservedAnnotation, error) {
 	case itemExpressionClose:
 		p.backup()
 
+		annotation.Span = p.spanFrom(start)
+
 		return annotation, nil
 	}
 
@@ -607,6 +776,8 @@ func (p *parser) parseReservedAnnotation() (ReservedAnnotation, error) {
 		return errorf("%w", err)
 	}
 
+	annotation.Span = p.spanFrom(start)
+
 	return annotation, nil
 }
 
@@ -627,11 +798,11 @@ func (p *parser) parseReservedBody() ([]ReservedBody, error) {
 			p.backup()
 			p.backup()
 
-			return parts, nil
+			return p.ownReservedBody(parts), nil
 		case itemExpressionClose:
 			p.backup()
 
-			return parts, nil
+			return p.ownReservedBody(parts), nil
 		}
 	}
 }
@@ -643,6 +814,8 @@ func (p *parser) parseLocalDeclaration() (LocalDeclaration, error) {
 		return LocalDeclaration{}, fmt.Errorf("local declaration: %w", err)
 	}
 
+	start := p.start()
+
 	p.declaration = "local"
 	defer func() { p.declaration = "" }()
 
@@ -655,7 +828,7 @@ func (p *parser) parseLocalDeclaration() (LocalDeclaration, error) {
 		return errorf(unexpectedErr(next, itemVariable))
 	}
 
-	variable := Variable(next.val)
+	variable := Variable(norm.NFC.String(next.val))
 	if err := p.duplicateVariable(variable); err != nil {
 		return errorf(err)
 	}
@@ -681,6 +854,7 @@ func (p *parser) parseLocalDeclaration() (LocalDeclaration, error) {
 	}
 
 	declaration.Expression = expression
+	declaration.Span = p.spanFrom(start)
 
 	return declaration, nil
 }
@@ -690,6 +864,8 @@ func (p *parser) parseInputDeclaration() (InputDeclaration, error) {
 		return InputDeclaration{}, fmt.Errorf("input declaration: "+format, args...)
 	}
 
+	start := p.start()
+
 	p.declaration = "input"
 	defer func() { p.declaration = "" }()
 
@@ -703,10 +879,14 @@ func (p *parser) parseInputDeclaration() (InputDeclaration, error) {
 		return errorf("%w", err)
 	}
 
+	// the span of the declaration includes the keyword
+	expression.Span = p.spanFrom(start)
+
 	return InputDeclaration(expression), nil
 }
 
 func (p *parser) parseReservedStatement() (ReservedStatement, error) {
+	start := p.start()
 	statement := ReservedStatement{Keyword: p.current().val}
 	errorf := func(format string, args ...any) (ReservedStatement, error) {
 		return ReservedStatement{}, fmt.Errorf("reserved statement: "+format, args...)
@@ -719,7 +899,19 @@ func (p *parser) parseReservedStatement() (ReservedStatement, error) {
 			return errorf("%w", unexpectedErr(itm, itemReservedText, itemQuotedLiteral, itemExpressionOpen))
 		case itemReservedKeyword, itemInputKeyword, itemLocalKeyword, // Another declaration
 			itemQuotedPatternOpen, itemMatchKeyword: // End of declarations
+			if len(statement.Expressions) == 0 {
+				return errorf("%w", unexpectedErr(itm, itemExpressionOpen))
+			}
+
 			p.backup()
+
+			statement.Span = p.spanFrom(start)
+			statement.ReservedBody = p.ownReservedBody(statement.ReservedBody)
+
+			if p.arena != nil {
+				statement.Expressions = p.arena.expressions.copy(statement.Expressions)
+			}
+
 			return statement, nil
 		// Non-ending tokens
 		case itemReservedText:
@@ -755,6 +947,8 @@ func isFallback(keys []VariantKey) bool {
 func (p *parser) parseMatcher() (Matcher, error) {
 	var matcher Matcher
 
+	start := p.start()
+
 	errorf := func(format string, args ...any) (Matcher, error) {
 		return Matcher{}, fmt.Errorf("matcher: "+format, args...)
 	}
@@ -770,11 +964,26 @@ selectorsLoop:
 		case itemEOF:
 			return errorf("%w", unexpectedErr(itm))
 		case itemExpressionOpen:
+			if p.version.since(SpecLDML46) {
+				return errorf("selector expression is not allowed in %s, want variable", p.version)
+			}
+
 			selector, err := p.parseExpression()
 			if err != nil {
 				return errorf("%w", err)
 			}
 
+			matcher.Selectors = append(matcher.Selectors, selector)
+		case itemVariable:
+			if !p.version.since(SpecLDML46) {
+				return errorf("selector variable is not allowed in %s, want expression", p.version)
+			}
+
+			selector, err := p.parseSelectorVariable(itm)
+			if err != nil {
+				return errorf("%w", err)
+			}
+
 			matcher.Selectors = append(matcher.Selectors, selector)
 		}
 	}
@@ -789,6 +998,17 @@ selectorsLoop:
 		case itemEOF:
 			p.backup()
 
+			matcher.Span = p.spanFrom(start)
+
+			if p.arena != nil {
+				matcher.Selectors = p.arena.expressions.copy(matcher.Selectors)
+				matcher.Variants = p.arena.variants.copy(matcher.Variants)
+			}
+
+			if p.skipValidation {
+				return matcher, nil
+			}
+
 			// fallback variant is required
 			for i := range matcher.Variants {
 				if isFallback(matcher.Variants[i].Keys) {
@@ -798,15 +1018,21 @@ selectorsLoop:
 
 			return errorf("%w", mf2.ErrMissingFallbackVariant)
 		case itemCatchAllKey, itemNumberLiteral, itemQuotedLiteral, itemUnquotedLiteral:
+			start := p.start()
+
 			keys, err := p.parseVariantKeys()
 			if err != nil {
 				return errorf("%w", err)
 			}
 
-			if len(keys) != len(matcher.Selectors) {
+			if !p.skipValidation && len(keys) != len(matcher.Selectors) {
 				return errorf("%w: %d selectors and %d keys", mf2.ErrVariantKeyMismatch, len(matcher.Selectors), len(keys))
 			}
 
+			if err := p.emit(EventVariantStart, Variant{Keys: keys}); err != nil {
+				return errorf("%w", err)
+			}
+
 			pattern, err := p.parsePattern()
 			if err != nil {
 				return errorf("%w", err)
@@ -816,11 +1042,41 @@ selectorsLoop:
 				return errorf("variant pattern: %w", unexpectedErr(itm, itemExpressionClose))
 			}
 
-			matcher.Variants = append(matcher.Variants, Variant{Keys: keys, QuotedPattern: QuotedPattern(pattern)})
+			variant := Variant{Keys: keys, QuotedPattern: QuotedPattern(pattern), Span: p.spanFrom(start)}
+
+			if err := p.emit(EventVariantEnd, variant); err != nil {
+				return errorf("%w", err)
+			}
+
+			// the keys are kept for the validation of the fallback variant, the patterns are streamed
+			matcher.Variants = append(matcher.Variants, variant)
 		}
 	}
 }
 
+// parseSelectorVariable parses the selector of LDML 46 and later, ".match $x", as the expression
+// with the variable operand. The selector is annotated by the declaration of the variable.
+func (p *parser) parseSelectorVariable(itm item) (Expression, error) {
+	if p.items[p.pos-1].typ != itemWhitespace {
+		return Expression{}, fmt.Errorf("missing whitespace before selector %s", itm.val)
+	}
+
+	if err := p.emit(EventExpressionStart, nil); err != nil {
+		return Expression{}, fmt.Errorf("expression: %w", err)
+	}
+
+	variable := Variable(norm.NFC.String(itm.val))
+	p.declareVariable(variable)
+
+	expr := Expression{Operand: variable, Span: p.spanFrom(p.start())}
+
+	if err := p.emit(EventExpressionEnd, expr); err != nil {
+		return Expression{}, fmt.Errorf("expression: %w", err)
+	}
+
+	return expr, nil
+}
+
 func (p *parser) parseVariantKeys() ([]VariantKey, error) {
 	var (
 		keys   []VariantKey
@@ -856,15 +1112,28 @@ func (p *parser) parseVariantKeys() ([]VariantKey, error) {
 				return errorf("%w", err)
 			}
 
+			// keys are NFC normalized, they are compared with the normalized selector values
+			switch v := literal.(type) {
+			case QuotedLiteral:
+				literal = QuotedLiteral(norm.NFC.String(string(v)))
+			case NameLiteral:
+				literal = NameLiteral(norm.NFC.String(string(v)))
+			}
+
 			keys = append(keys, literal)
 			spaced = false
 		}
 	}
 
+	if p.arena != nil {
+		keys = p.arena.keys.copy(keys)
+	}
+
 	return keys, nil
 }
 
 func (p *parser) parseOption() (Option, error) {
+	start := p.start()
 	option := Option{Identifier: p.parseIdentifier()}
 	errorf := func(format string, args ...any) (Option, error) {
 		return Option{}, fmt.Errorf("option: "+format, args...)
@@ -881,8 +1150,8 @@ func (p *parser) parseOption() (Option, error) {
 		err := unexpectedErr(next, itemVariable, itemQuotedLiteral, itemUnquotedLiteral, itemNumberLiteral)
 		return errorf("%w", err)
 	case itemVariable:
-		variable := Variable(next.val)
-		if variable == p.reservedVariable {
+		variable := Variable(norm.NFC.String(next.val))
+		if !p.skipValidation && variable == p.reservedVariable {
 			return errorf("%w: %s", mf2.ErrDuplicateDeclaration, variable)
 		}
 
@@ -895,6 +1164,8 @@ func (p *parser) parseOption() (Option, error) {
 		}
 	}
 
+	option.Span = p.spanFrom(start)
+
 	return option, nil
 }
 
@@ -925,13 +1196,18 @@ func (p *parser) parseAttributes() ([]Attribute, error) {
 		attributes = append(attributes, attribute)
 
 		if p.peekNonWS().typ == itemExpressionClose {
+			if p.arena != nil {
+				attributes = p.arena.attributes.copy(attributes)
+			}
+
 			return attributes, nil
 		}
 	}
 }
 
 func (p *parser) parseAttribute() (Attribute, error) {
-	attribute := Attribute{Identifier: p.parseIdentifier()}
+	start := p.start()
+	attribute := Attribute{Identifier: p.parseIdentifier(), Span: p.spanFrom(start)}
 	errorf := func(format string, args ...any) (Attribute, error) {
 		return Attribute{}, fmt.Errorf("attribute: "+format, args...)
 	}
@@ -951,7 +1227,7 @@ func (p *parser) parseAttribute() (Attribute, error) {
 	default:
 		return errorf("%w", unexpectedErr(itm, itemVariable, itemQuotedLiteral, itemUnquotedLiteral, itemNumberLiteral))
 	case itemVariable:
-		variable := Variable(itm.val)
+		variable := Variable(norm.NFC.String(itm.val))
 		p.declareVariable(variable)
 		attribute.Value = variable
 	case itemQuotedLiteral, itemUnquotedLiteral, itemNumberLiteral:
@@ -960,6 +1236,8 @@ func (p *parser) parseAttribute() (Attribute, error) {
 		}
 	}
 
+	attribute.Span = p.spanFrom(start)
+
 	return attribute, nil
 }
 
@@ -969,8 +1247,8 @@ func (p *parser) parseLiteral() (Literal, error) {
 		err := unexpectedErr(itm, itemNumberLiteral, itemQuotedLiteral, itemUnquotedLiteral)
 		return nil, fmt.Errorf("literal: %w", err)
 	case itemNumberLiteral:
-		var num float64
-		if err := json.Unmarshal([]byte(itm.val), &num); err != nil {
+		num, err := strconv.ParseFloat(itm.val, 64)
+		if err != nil {
 			return nil, fmt.Errorf("number literal: %w", err)
 		}
 
@@ -982,14 +1260,14 @@ func (p *parser) parseLiteral() (Literal, error) {
 	}
 }
 
+// parseIdentifier parses the identifier, the names are NFC normalized to compare them as the spec requires.
 func (p *parser) parseIdentifier() Identifier {
-	split := strings.Split(p.current().val, ":") // namespace:name
-
-	if len(split) == 1 {
-		return Identifier{Name: split[0]}
+	namespace, name, ok := strings.Cut(norm.NFC.String(p.current().val), ":") // namespace:name
+	if !ok {
+		return Identifier{Name: namespace}
 	}
 
-	return Identifier{Namespace: split[0], Name: split[1]}
+	return Identifier{Namespace: namespace, Name: name}
 }
 
 // UnexpectedTokenError is returned when parser encounters unexpected token.
@@ -1,8 +1,14 @@
 package parse
 
 import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
 	"strings"
 	"testing"
+
+	"go.expect.digital/mf2"
 )
 
 func TestParseSimpleMessage(t *testing.T) {
@@ -52,6 +58,15 @@ func TestParseSimpleMessage(t *testing.T) {
 				Expression{Operand: Variable("variable")},
 			},
 		},
+		{
+			name:  "text starting with dot after expression",
+			input: "Hello, { $variable }.",
+			want: SimpleMessage{
+				Text("Hello, "),
+				Expression{Operand: Variable("variable")},
+				Text("."),
+			},
+		},
 		{
 			name:  "variable expression with annotation",
 			input: "Hello, { $variable :function }  World!",
@@ -780,6 +795,48 @@ func TestParseErrors(t *testing.T) {
 	}
 }
 
+func TestParseWithoutValidation(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		in, wantErr string
+	}{
+		{
+			in:      ".input {$foo} .input {$foo} {{ }}",
+			wantErr: "duplicate declaration",
+		},
+		{
+			in:      ".local $foo = {$foo} {{ }}",
+			wantErr: "duplicate declaration",
+		},
+		{
+			in:      ".match {$foo :x} {$bar :x} 1 {{foo}} * {{bar}}",
+			wantErr: "variant key mismatch",
+		},
+		{
+			in:      ".match {$foo :x} 1 {{foo}}",
+			wantErr: "missing fallback variant",
+		},
+	} {
+		t.Run(test.in, func(t *testing.T) {
+			t.Parallel()
+
+			if _, err := Parse(test.in); err == nil || !strings.Contains(err.Error(), test.wantErr) {
+				t.Errorf("want '%s', got '%s'", test.wantErr, err)
+			}
+
+			if _, err := Parse(test.in, WithoutValidation()); err != nil {
+				t.Errorf("want no error, got '%s'", err)
+			}
+		})
+	}
+
+	// syntax errors are reported regardless
+	if _, err := Parse("{ $foo", WithoutValidation()); err == nil {
+		t.Error("want syntax error, got nil")
+	}
+}
+
 // helpers
 
 // requireEqualMF2String compares two strings, but ignores whitespace, tabs, and newlines.
@@ -796,3 +853,178 @@ func requireEqualMF2String(t *testing.T, want, got string) {
 		t.Errorf("want '%s', got '%s'", want, got)
 	}
 }
+
+// benchmarkMessages are messages of different shapes for benchmarks.
+func benchmarkMessages() []struct{ name, text string } {
+	var large strings.Builder
+
+	for i := range 50 {
+		fmt.Fprintf(&large, "Lorem ipsum dolor sit amet { $var%d } consectetur { |literal %d| :string } ", i, i)
+	}
+
+	// 4 selectors with 2 keys each, 16 variants
+	deep := ".input { $a :number } .input { $b :number } .input { $c :string } .input { $d :string }\n.match { $a } { $b } { $c } { $d }\n"
+
+	for _, a := range []string{"one", "*"} {
+		for _, b := range []string{"one", "*"} {
+			for _, c := range []string{"x", "*"} {
+				for _, d := range []string{"y", "*"} {
+					deep += fmt.Sprintf("%s %s %s %s {{%s %s { $a } { $b }}}\n", a, b, c, d, c, d)
+				}
+			}
+		}
+	}
+
+	return []struct{ name, text string }{
+		{name: "small", text: "Hello, { $name }!"},
+		{name: "large", text: large.String()},
+		{name: "deep matcher", text: deep},
+		{
+			name: "many options",
+			text: "{ $n :number minimumIntegerDigits=2 minimumFractionDigits=1 maximumFractionDigits=3 " +
+				"signDisplay=always useGrouping=always notation=standard style=decimal select=plural @attr=1 }",
+		},
+		{name: "markup", text: "{ #a href=|https://example.com| }link{ /a } { #b }bold{ /b } { #br /}"},
+	}
+}
+
+func BenchmarkParse(b *testing.B) {
+	for _, message := range benchmarkMessages() {
+		if _, err := Parse(message.text); err != nil {
+			b.Fatal(err)
+		}
+
+		b.Run(message.name, func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(len(message.text)))
+
+			var tree AST
+
+			for range b.N {
+				tree, _ = Parse(message.text)
+			}
+
+			_ = tree
+		})
+	}
+}
+
+func BenchmarkAST_String(b *testing.B) {
+	for _, message := range benchmarkMessages() {
+		tree, err := Parse(message.text)
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		b.Run(message.name, func(b *testing.B) {
+			b.ReportAllocs()
+
+			var s string
+
+			for range b.N {
+				s = tree.String()
+			}
+
+			_ = s
+		})
+	}
+}
+
+func TestParseSpecVersion(t *testing.T) {
+	t.Parallel()
+
+	draft := []SpecVersion{SpecDraft2024}
+	ldml := []SpecVersion{SpecLDML46, SpecLDML47}
+
+	for _, test := range []struct {
+		in string
+		// valid are the versions the message is valid in, the message is a syntax error in the others.
+		valid []SpecVersion
+	}{
+		{in: "{ $foo ^bar }", valid: draft},
+		{in: "{ $foo &bar }", valid: draft},
+		{in: ".reserved |body| { $foo } {{ }}", valid: draft},
+		{in: ".input {$n :number}\n.match {$n}\none {{one}}\n* {{other}}", valid: draft},
+		{in: ".match {$n :number} {$m :number}\none * {{one}}\n* * {{other}}", valid: draft},
+		{in: ".input {$n :number}\n.match $n\none {{one}}\n* {{other}}", valid: ldml},
+		{in: ".input {$n :number} .local $m = {$n}\n.match $n $m\none * {{one}}\n* * {{other}}", valid: ldml},
+		{in: ".input {$n :number}\n.match$n\none {{one}}\n* {{other}}"},
+		{in: "Hello, { $name }!", valid: specVersions},
+		{in: ".input {$n :number} {{{$n}}}", valid: specVersions},
+	} {
+		t.Run(test.in, func(t *testing.T) {
+			t.Parallel()
+
+			for _, version := range specVersions {
+				_, err := Parse(test.in, WithSpecVersion(version))
+
+				switch {
+				case slices.Contains(test.valid, version) && err != nil:
+					t.Errorf("%s: want no error, got '%s'", version, err)
+				case !slices.Contains(test.valid, version) && !errors.Is(err, mf2.ErrSyntax):
+					t.Errorf("%s: want '%s', got '%v'", version, mf2.ErrSyntax, err)
+				}
+			}
+		})
+	}
+
+	if _, err := Parse("Hello!", WithSpecVersion("LDML44")); err == nil {
+		t.Error("want unsupported version error, got nil")
+	}
+}
+
+func TestParseLimits(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		name    string
+		in      string
+		options []ParseOption
+		wantErr bool
+	}{
+		{name: "default tokens", in: strings.Repeat("{$a}", 10_000)},
+		{name: "default depth", in: strings.Repeat("{#a}", 1000)},
+		{name: "max tokens", in: strings.Repeat("{$a}", 400), options: []ParseOption{WithMaxTokens(2000)}},
+		{name: "tokens exceeded", in: strings.Repeat("{$a}", 400), options: []ParseOption{WithMaxTokens(1000)}, wantErr: true},
+		{name: "unlimited tokens", in: strings.Repeat("{$a}", 10_000), options: []ParseOption{WithMaxTokens(0)}},
+		{name: "default tokens exceeded", in: strings.Repeat("{$a}", 50_000), wantErr: true},
+		// the lexer must not stall on the unterminated quoted literal
+		{name: "unterminated literal", in: `{"`, wantErr: true},
+		{name: "unterminated literal in option", in: `{$x "}`, wantErr: true},
+		{name: "unterminated literal in declaration", in: `.local $x = {"} {{}}`, wantErr: true},
+		{name: "depth", in: "{#a}{#b}{/b}{#c}{/c}{/a}", options: []ParseOption{WithMaxDepth(2)}},
+		{name: "depth exceeded", in: "{#a}{#b}{#c}", options: []ParseOption{WithMaxDepth(2)}, wantErr: true},
+		{name: "close nested", in: "{#a}{#b}{/a}{#c}{#d}", options: []ParseOption{WithMaxDepth(2)}},
+		{name: "unmatched close", in: "{/x}{#a}{#b}", options: []ParseOption{WithMaxDepth(2)}},
+		{name: "self-closing", in: "{#a}{#b/}{#c/}", options: []ParseOption{WithMaxDepth(1)}},
+		{
+			name:    "per pattern",
+			in:      ".match {$x :string} a {{{#a}{#b}}} * {{{#c}{#d}}}",
+			options: []ParseOption{WithMaxDepth(2)},
+		},
+		{
+			name:    "unlimited depth",
+			in:      strings.Repeat("{#a}", 1000),
+			options: []ParseOption{WithMaxTokens(0), WithMaxDepth(0)},
+		},
+		{
+			// the unmatched close markup is found in constant time, quadratic time would time out
+			name:    "deep unmatched close",
+			in:      strings.Repeat("{#a}", 200_000) + strings.Repeat("{/b}", 200_000),
+			options: []ParseOption{WithMaxTokens(0), WithMaxDepth(math.MaxInt)},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := Parse(test.in, test.options...)
+			if test.wantErr != (err != nil) {
+				t.Errorf("want error %t, got '%v'", test.wantErr, err)
+			}
+
+			if err != nil && !errors.Is(err, mf2.ErrSyntax) {
+				t.Errorf("want '%s', got '%s'", mf2.ErrSyntax, err)
+			}
+		})
+	}
+}
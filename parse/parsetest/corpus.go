@@ -0,0 +1,103 @@
+package parsetest
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"slices"
+	"sync"
+	"testing"
+)
+
+// builtin is the regression corpus of known-tricky messages, one message per file.
+//
+//go:embed corpus/*.mf2
+var builtin embed.FS
+
+var (
+	registered []string
+	mu         sync.Mutex
+)
+
+// Register adds the messages to the regression corpus, e.g. the messages of the user-reported
+// parse bugs. Register in init or TestMain so the corpus is complete before the tests run.
+func Register(messages ...string) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	registered = append(registered, messages...)
+}
+
+// RegisterFS adds the messages in the files of the file system matching the patterns to the regression
+// corpus, see [fs.Glob], each file is a single message, e.g. "*.mf2".
+func RegisterFS(fsys fs.FS, patterns ...string) error {
+	messages, err := readFS(fsys, patterns...)
+	if err != nil {
+		return fmt.Errorf("register corpus: %w", err)
+	}
+
+	Register(messages...)
+
+	return nil
+}
+
+// Corpus returns the built-in regression corpus followed by the registered messages, see [Register].
+// The built-in corpus covers the escaped pipes and braces, nested markup, bidi text, reserved syntax
+// and the leading dot of the text.
+func Corpus() []string {
+	messages, err := readFS(builtin, "corpus/*.mf2")
+	if err != nil {
+		panic(err) // the embedded corpus is always readable
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	return append(messages, registered...)
+}
+
+// Seed adds the regression corpus to the seed corpus of the fuzz test, see [Corpus].
+//
+// Example:
+//
+//	func FuzzMessages(f *testing.F) {
+//		parsetest.Seed(f)
+//
+//		f.Fuzz(func(t *testing.T, s string) { ... })
+//	}
+func Seed(f *testing.F) {
+	f.Helper()
+
+	for _, message := range Corpus() {
+		f.Add(message)
+	}
+}
+
+// readFS returns the messages in the files matching the patterns, sorted by file name.
+func readFS(fsys fs.FS, patterns ...string) ([]string, error) {
+	var names []string
+
+	for _, pattern := range patterns {
+		matches, err := fs.Glob(fsys, pattern)
+		if err != nil {
+			return nil, err //nolint:wrapcheck
+		}
+
+		names = append(names, matches...)
+	}
+
+	slices.Sort(names)
+
+	messages := make([]string, 0, len(names))
+
+	for _, name := range slices.Compact(names) {
+		b, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return nil, err //nolint:wrapcheck
+		}
+
+		messages = append(messages, string(b))
+	}
+
+	return messages, nil
+}
@@ -0,0 +1,48 @@
+package parsetest
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"testing/fstest"
+
+	"go.expect.digital/mf2/parse"
+)
+
+func TestCorpus(t *testing.T) {
+	t.Parallel()
+
+	RoundTrip(t, Corpus()...)
+}
+
+func TestRegister(t *testing.T) {
+	t.Parallel()
+
+	Register("{ |registered| }")
+
+	err := RegisterFS(fstest.MapFS{
+		"b.mf2": {Data: []byte("{ $b }")},
+		"a.mf2": {Data: []byte("{ $a }")},
+	}, "*.mf2", "a.mf2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	corpus := Corpus()
+
+	want := []string{"{ |registered| }", "{ $a }", "{ $b }"}
+
+	if got := corpus[len(corpus)-len(want):]; !slices.Equal(want, got) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+}
+
+func FuzzCorpus(f *testing.F) {
+	Seed(f)
+
+	f.Fuzz(func(t *testing.T, s string) {
+		if err := parse.RoundTrip(s); errors.Is(err, parse.ErrRoundTrip) {
+			t.Errorf("'%s': %s", s, err)
+		}
+	})
+}
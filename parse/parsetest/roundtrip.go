@@ -0,0 +1,66 @@
+// Package parsetest checks that a corpus of messages survives the formatting, see [parse.RoundTrip],
+// e.g. to verify the messages of the application before formatting the catalogs canonically in CI.
+//
+// The regression corpus, see [Corpus], collects the known-tricky messages. The corpus is checked
+// by the tests and seeds the fuzz tests of the module, register more with [Register] and [RegisterFS].
+//
+// Example:
+//
+//	func TestRoundTrip(t *testing.T) {
+//		parsetest.RoundTrip(t, "Hello, { $name }!", "{{.dot}}")
+//		parsetest.RoundTripFS(t, os.DirFS("messages"), "*.mf2", "*/*.mf2")
+//		parsetest.RoundTrip(t, parsetest.Corpus()...)
+//	}
+package parsetest
+
+import (
+	"io/fs"
+	"testing"
+
+	"go.expect.digital/mf2/parse"
+)
+
+// RoundTrip checks each message of the corpus in a subtest, the invalid messages and
+// the messages not surviving the round trip are reported with t.Errorf.
+func RoundTrip(t *testing.T, corpus ...string) {
+	t.Helper()
+
+	for _, message := range corpus {
+		t.Run(message, func(t *testing.T) {
+			t.Helper()
+
+			if err := parse.RoundTrip(message); err != nil {
+				t.Errorf("'%s': %s", message, err)
+			}
+		})
+	}
+}
+
+// RoundTripFS checks the messages in the files of the file system matching the patterns,
+// see [fs.Glob], each file is a single message, e.g. "*.mf2". Each file is checked in a subtest
+// named by the file name.
+func RoundTripFS(t *testing.T, fsys fs.FS, patterns ...string) {
+	t.Helper()
+
+	for _, pattern := range patterns {
+		names, err := fs.Glob(fsys, pattern)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, name := range names {
+			t.Run(name, func(t *testing.T) {
+				t.Helper()
+
+				b, err := fs.ReadFile(fsys, name)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if err := parse.RoundTrip(string(b)); err != nil {
+					t.Errorf("%s: %s", name, err)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,28 @@
+package parsetest
+
+import (
+	"testing"
+	"testing/fstest"
+)
+
+func TestRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	RoundTrip(t,
+		"Hello, { $name }!",
+		"{ $x :number style=percent minimumFractionDigits=2 @attr=value }",
+		".input { $n :number } .match { $n } one {{one}} * {{other}}",
+		"{{.dot}}",
+	)
+}
+
+func TestRoundTripFS(t *testing.T) {
+	t.Parallel()
+
+	RoundTripFS(t, fstest.MapFS{
+		"greeting.mf2":      {Data: []byte("Hello, { $name }!")},
+		"plural/apples.mf2": {Data: []byte(".input { $n :number }\n.match { $n }\none {{one}}\n* {{other}}")},
+		"plural/readme.txt": {Data: []byte("{ invalid")},
+		"markup/escape.mf2": {Data: []byte(`{#b}\{braces\}{/b}`)},
+	}, "*.mf2", "*/*.mf2")
+}
@@ -0,0 +1,93 @@
+package parse
+
+import (
+	"sort"
+	"strings"
+
+	"go.expect.digital/mf2"
+)
+
+// Position is the location in the input of [Parse].
+type Position struct {
+	Offset int // byte offset, starting at 0
+	Line   int // line number, starting at 1
+	Column int // byte offset in the line, starting at 1
+}
+
+// IsValid reports whether the position is set, see [WithPositions].
+func (p Position) IsValid() bool { return p.Line > 0 }
+
+// Span is the location of the AST node in the input of [Parse], the End is exclusive.
+type Span struct {
+	Start, End Position
+}
+
+// IsValid reports whether the span is set, see [WithPositions].
+func (s Span) IsValid() bool { return s.Start.IsValid() }
+
+// Offsets returns the byte offsets of the span, e.g. for the span of [mf2.Diagnostic].
+func (s Span) Offsets() mf2.Span {
+	return mf2.Span{Start: s.Start.Offset, End: s.End.Offset}
+}
+
+// WithPositions sets the Span of the AST nodes to their location in the input, e.g. to map
+// the node back to the source in an editor. Without the option the spans are zero.
+//
+// The span is set on the struct nodes: [ComplexMessage], [Expression], [InputDeclaration],
+// [LocalDeclaration], [ReservedStatement], [Function], [PrivateUseAnnotation], [ReservedAnnotation],
+// [Markup], [Option], [Attribute], [Matcher] and [Variant]. The span of [InputDeclaration] includes
+// the keyword. The values, keys, texts, identifiers and patterns are located by the span of the node
+// they belong to.
+func WithPositions() ParseOption {
+	return func(p *parser) {
+		p.positions = true
+	}
+}
+
+// start returns the start offset of the current item.
+func (p *parser) start() int {
+	return p.items[p.pos].pos
+}
+
+// spanFrom returns the span from the start offset to the end of the current item,
+// the trailing whitespace excluded. It is zero without [WithPositions].
+func (p *parser) spanFrom(start int) Span {
+	if !p.positions {
+		return Span{}
+	}
+
+	i := p.pos
+	for i > 0 && p.items[i].typ == itemWhitespace {
+		i--
+	}
+
+	// the items cover the input, the item ends where the next starts
+	end := len(p.lexer.input)
+	if i+1 < len(p.items) {
+		end = p.items[i+1].pos
+	}
+
+	return Span{Start: p.position(start), End: p.position(end)}
+}
+
+// position returns the position of the byte offset in the input.
+func (p *parser) position(offset int) Position {
+	if p.lines == nil {
+		p.lines = []int{0}
+
+		for i := 0; ; {
+			n := strings.IndexByte(p.lexer.input[i:], '\n')
+			if n < 0 {
+				break
+			}
+
+			i += n + 1
+			p.lines = append(p.lines, i)
+		}
+	}
+
+	// the last line starting at or before the offset
+	line := sort.Search(len(p.lines), func(i int) bool { return p.lines[i] > offset }) - 1
+
+	return Position{Offset: offset, Line: line + 1, Column: offset - p.lines[line] + 1}
+}
@@ -0,0 +1,84 @@
+package parse
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWithPositions(t *testing.T) {
+	t.Parallel()
+
+	const input = ".input {$n :number minimumFractionDigits=2}\n" +
+		".local $x = {|a| @a=b}\n" +
+		".match {$n} {$x}\n" +
+		"1 a {{one {#b}x{/b}}}\n" +
+		"* * {{other { $n ^private }}}\n"
+
+	tree, err := Parse(input, WithPositions())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	message := tree.Message.(ComplexMessage)                   //nolint:forcetypeassert
+	input0 := message.Declarations[0].(InputDeclaration)       //nolint:forcetypeassert
+	local := message.Declarations[1].(LocalDeclaration)        //nolint:forcetypeassert
+	matcher := message.ComplexBody.(Matcher)                   //nolint:forcetypeassert
+	function := input0.Annotation.(Function)                   //nolint:forcetypeassert
+	other := matcher.Variants[1].QuotedPattern[1].(Expression) //nolint:forcetypeassert
+
+	for _, test := range []struct {
+		span Span
+		want string
+	}{
+		{message.Span, input[:len(input)-1]},
+		{input0.Span, ".input {$n :number minimumFractionDigits=2}"},
+		{function.Span, ":number minimumFractionDigits=2"},
+		{function.Options[0].Span, "minimumFractionDigits=2"},
+		{local.Span, ".local $x = {|a| @a=b}"},
+		{local.Expression.Span, "{|a| @a=b}"},
+		{local.Expression.Attributes[0].Span, "@a=b"},
+		{matcher.Span, ".match {$n} {$x}\n1 a {{one {#b}x{/b}}}\n* * {{other { $n ^private }}}"},
+		{matcher.Selectors[1].Span, "{$x}"},
+		{matcher.Variants[0].Span, "1 a {{one {#b}x{/b}}}"},
+		{matcher.Variants[0].QuotedPattern[1].(Markup).Span, "{#b}"}, //nolint:forcetypeassert
+		{matcher.Variants[0].QuotedPattern[3].(Markup).Span, "{/b}"}, //nolint:forcetypeassert
+		{other.Span, "{ $n ^private }"},
+		{other.Annotation.(PrivateUseAnnotation).Span, "^private"}, //nolint:forcetypeassert
+	} {
+		if got := input[test.span.Start.Offset:test.span.End.Offset]; test.want != got {
+			t.Errorf("want '%s', got '%s'", test.want, got)
+		}
+	}
+
+	if want, got := (Position{Offset: 118, Line: 5, Column: 13}), other.Span.Start; want != got {
+		t.Errorf("want %+v, got %+v", want, got)
+	}
+
+	// without the option the spans are zero
+	tree, err = Parse(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if span := tree.Message.(ComplexMessage).Span; span.IsValid() { //nolint:forcetypeassert
+		t.Errorf("want zero span, got %+v", span)
+	}
+}
+
+func TestWithPositionsSimpleMessage(t *testing.T) {
+	t.Parallel()
+
+	tree, err := Parse("Hello, { $name :string }!", WithPositions())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := Span{
+		Start: Position{Offset: 7, Line: 1, Column: 8},
+		End:   Position{Offset: 24, Line: 1, Column: 25},
+	}
+
+	if got := tree.Message.(SimpleMessage)[1].(Expression).Span; !reflect.DeepEqual(want, got) { //nolint:forcetypeassert
+		t.Errorf("want %+v, got %+v", want, got)
+	}
+}
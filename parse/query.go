@@ -0,0 +1,289 @@
+package parse
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Path is the location of the node in the AST, e.g. "variants[1].pattern[0].annotation".
+type Path []string
+
+// String returns the path elements joined by ".".
+func (p Path) String() string { return strings.Join(p, ".") }
+
+// Match is the node found by [Find] or [Query], and its path.
+type Match[T Node] struct {
+	Node T
+	Path Path
+}
+
+// Walk traverses the AST in depth-first order, calling f for each node and its path.
+// If f returns false, the children of the node are skipped.
+//
+// The path is reused by the traversal, f must copy it to retain it.
+func Walk(ast AST, f func(node Node, path Path) bool) {
+	if ast.Message == nil {
+		return
+	}
+
+	w := walker{f: f}
+	w.walk(ast.Message, "")
+}
+
+// Find returns the nodes of type T matching pred, all nodes of type T if pred is nil.
+//
+// Example:
+//
+//	// all :number functions
+//	matches := Find(ast, func(f Function) bool { return f.Identifier.Name == "number" })
+func Find[T Node](ast AST, pred func(T) bool) []Match[T] {
+	var matches []Match[T]
+
+	Walk(ast, func(node Node, path Path) bool {
+		if v, ok := node.(T); ok && (pred == nil || pred(v)) {
+			matches = append(matches, Match[T]{Node: v, Path: append(Path(nil), path...)})
+		}
+
+		return true
+	})
+
+	return matches
+}
+
+type walker struct {
+	f    func(node Node, path Path) bool
+	path Path
+}
+
+// walk visits the node and its children, name is the path element of the node.
+func (w *walker) walk(node Node, name string) {
+	if name != "" {
+		w.path = append(w.path, name)
+		defer func() { w.path = w.path[:len(w.path)-1] }()
+	}
+
+	if !w.f(node, w.path) {
+		return
+	}
+
+	switch v := node.(type) {
+	case SimpleMessage:
+		walkSlice(w, "pattern", v)
+	case ComplexMessage:
+		walkSlice(w, "declarations", v.Declarations)
+
+		if v.ComplexBody != nil {
+			w.walk(v.ComplexBody, "body")
+		}
+	case QuotedPattern:
+		walkSlice(w, "pattern", v)
+	case Matcher:
+		walkSlice(w, "selectors", v.Selectors)
+		walkSlice(w, "variants", v.Variants)
+	case Variant:
+		walkSlice(w, "keys", v.Keys)
+		walkSlice(w, "pattern", v.QuotedPattern)
+	case InputDeclaration:
+		w.walk(Expression(v), "expression")
+	case LocalDeclaration:
+		w.walk(v.Variable, "variable")
+		w.walk(v.Expression, "expression")
+	case ReservedStatement:
+		walkSlice(w, "expressions", v.Expressions)
+	case Expression:
+		if v.Operand != nil {
+			w.walk(v.Operand, "operand")
+		}
+
+		if v.Annotation != nil {
+			w.walk(v.Annotation, "annotation")
+		}
+
+		walkSlice(w, "attributes", v.Attributes)
+	case Function:
+		walkSlice(w, "options", v.Options)
+	case Markup:
+		walkSlice(w, "options", v.Options)
+		walkSlice(w, "attributes", v.Attributes)
+	case Option:
+		w.walk(v.Value, "value")
+	case Attribute:
+		if v.Value != nil {
+			w.walk(v.Value, "value")
+		}
+	}
+}
+
+func walkSlice[T Node](w *walker, name string, nodes []T) {
+	for i, node := range nodes {
+		w.walk(node, name+"["+strconv.Itoa(i)+"]")
+	}
+}
+
+// Query returns the nodes matching the selector.
+//
+// The selector is the node type optionally followed by the filters "[key=value]", all must match.
+// The node types are "*" (any), "text", "expression", "variable", "literal", "function", "option",
+// "attribute", "markup", "variant", "input", "local" and "reserved".
+// The filter keys are:
+//   - "function" – the function name of the expression;
+//   - "name" – the name of the variable, function, option, attribute, markup or declared variable;
+//   - "value" – the value of the literal, option or attribute.
+//
+// Example:
+//
+//	matches, err := Query(ast, "expression[function=number]")
+func Query(ast AST, selector string) ([]Match[Node], error) {
+	typ, filters, err := parseSelector(selector)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	return Find(ast, func(node Node) bool {
+		if typ != "*" && typ != nodeType(node) {
+			return false
+		}
+
+		for _, filter := range filters {
+			if v, ok := nodeAttr(node, filter[0]); !ok || v != filter[1] {
+				return false
+			}
+		}
+
+		return true
+	}), nil
+}
+
+// parseSelector parses the selector of [Query] into the node type and the key-value filters.
+func parseSelector(selector string) (string, [][2]string, error) {
+	typ, rest, _ := strings.Cut(selector, "[")
+	if rest != "" {
+		rest = "[" + rest
+	}
+
+	typ = strings.TrimSpace(typ)
+	if typ == "" {
+		return "", nil, fmt.Errorf(`selector "%s": missing node type`, selector)
+	}
+
+	var filters [][2]string
+
+	for rest != "" {
+		end := strings.IndexByte(rest, ']')
+		if rest[0] != '[' || end == -1 {
+			return "", nil, fmt.Errorf(`selector "%s": want filter "[key=value]"`, selector)
+		}
+
+		key, value, ok := strings.Cut(rest[1:end], "=")
+		if !ok {
+			return "", nil, fmt.Errorf(`selector "%s": want filter "[key=value]"`, selector)
+		}
+
+		filters = append(filters, [2]string{strings.TrimSpace(key), strings.TrimSpace(value)})
+		rest = rest[end+1:]
+	}
+
+	return typ, filters, nil
+}
+
+// nodeType returns the node type name used by [Query].
+func nodeType(node Node) string {
+	switch node.(type) {
+	default:
+		return ""
+	case Text:
+		return "text"
+	case Expression:
+		return "expression"
+	case Variable:
+		return "variable"
+	case QuotedLiteral, NameLiteral, NumberLiteral:
+		return "literal"
+	case Function:
+		return "function"
+	case Option:
+		return "option"
+	case Attribute:
+		return "attribute"
+	case Markup:
+		return "markup"
+	case Variant:
+		return "variant"
+	case InputDeclaration:
+		return "input"
+	case LocalDeclaration:
+		return "local"
+	case ReservedStatement:
+		return "reserved"
+	}
+}
+
+// nodeAttr returns the value of the [Query] filter key of the node.
+func nodeAttr(node Node, key string) (string, bool) {
+	switch key {
+	case "function":
+		var expr Expression
+
+		switch v := node.(type) {
+		case Expression:
+			expr = v
+		case InputDeclaration:
+			expr = Expression(v)
+		case LocalDeclaration:
+			expr = v.Expression
+		default:
+			return "", false
+		}
+
+		if f, ok := expr.Annotation.(Function); ok {
+			return f.Identifier.String(), true
+		}
+	case "name":
+		switch v := node.(type) {
+		case Variable:
+			return string(v), true
+		case Function:
+			return v.Identifier.String(), true
+		case Option:
+			return v.Identifier.String(), true
+		case Attribute:
+			return v.Identifier.String(), true
+		case Markup:
+			return v.Identifier.String(), true
+		case InputDeclaration:
+			if variable, ok := v.Operand.(Variable); ok {
+				return string(variable), true
+			}
+		case LocalDeclaration:
+			return string(v.Variable), true
+		}
+	case "value":
+		switch v := node.(type) {
+		case QuotedLiteral:
+			return string(v), true
+		case NameLiteral:
+			return string(v), true
+		case NumberLiteral:
+			return v.String(), true
+		case Option:
+			return nodeValue(v.Value)
+		case Attribute:
+			return nodeValue(v.Value)
+		}
+	}
+
+	return "", false
+}
+
+// nodeValue returns the literal value, or the variable name with the "$" prefix.
+func nodeValue(value Value) (string, bool) {
+	switch v := value.(type) {
+	default:
+		return "", false
+	case Variable:
+		return v.String(), true
+	case Literal:
+		return nodeAttr(v, "value")
+	}
+}
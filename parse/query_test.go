@@ -0,0 +1,96 @@
+package parse
+
+import (
+	"slices"
+	"testing"
+)
+
+const queryMessage = ".input { $count :number } .local $x = { |a| :string @attr=1 }" +
+	" .match { $count } { $x }" +
+	" 1 a {{one { $count :number style=percent }}} * * {{{ #b }other{ /b }}}"
+
+func TestFind(t *testing.T) {
+	t.Parallel()
+
+	ast, err := Parse(queryMessage)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	matches := Find(ast, func(f Function) bool { return f.Identifier.Name == "number" })
+
+	want := []string{
+		"declarations[0].expression.annotation",
+		"body.variants[0].pattern[1].annotation",
+	}
+
+	if got := paths(matches); !slices.Equal(want, got) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+
+	if got := len(Find[Variable](ast, nil)); got != 5 {
+		t.Errorf("want 5 variables, got %d", got)
+	}
+}
+
+func TestQuery(t *testing.T) {
+	t.Parallel()
+
+	ast, err := Parse(queryMessage)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		selector string
+		want     []string
+	}{
+		{
+			selector: "expression[function=number]",
+			want:     []string{"declarations[0].expression", "body.variants[0].pattern[1]"},
+		},
+		{
+			selector: "option[name=style][value=percent]",
+			want:     []string{"body.variants[0].pattern[1].annotation.options[0]"},
+		},
+		{selector: "local[name=x]", want: []string{"declarations[1]"}},
+		{selector: "attribute[value=1]", want: []string{"declarations[1].expression.attributes[0]"}},
+		{selector: "markup", want: []string{"body.variants[1].pattern[0]", "body.variants[1].pattern[2]"}},
+		{selector: "variant", want: []string{"body.variants[0]", "body.variants[1]"}},
+		{selector: "* [name=count]", want: []string{
+			"declarations[0]",
+			"declarations[0].expression.operand",
+			"body.selectors[0].operand",
+			"body.variants[0].pattern[1].operand",
+		}},
+	} {
+		t.Run(test.selector, func(t *testing.T) {
+			t.Parallel()
+
+			matches, err := Query(ast, test.selector)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := paths(matches); !slices.Equal(test.want, got) {
+				t.Errorf("want %v, got %v", test.want, got)
+			}
+		})
+	}
+
+	for _, selector := range []string{"", "[name=x]", "option[name]", "option[name=x"} {
+		if _, err := Query(ast, selector); err == nil {
+			t.Errorf("%s: want error, got nil", selector)
+		}
+	}
+}
+
+func paths[T Node](matches []Match[T]) []string {
+	s := make([]string, 0, len(matches))
+
+	for _, m := range matches {
+		s = append(s, m.Path.String())
+	}
+
+	return s
+}
@@ -0,0 +1,46 @@
+package parse
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrRoundTrip occurs when the message does not survive the round trip, see [RoundTrip].
+var ErrRoundTrip = errors.New("round trip")
+
+// RoundTrip checks that the valid message survives the formatting: the string representation
+// of the message is parsed to the same message, and the canonical message, see [Canonical],
+// is parsed to the same canonical message. The violations are reported as [ErrRoundTrip].
+//
+// The parse error of the input is returned as is, the invalid input does not have a string representation.
+//
+// See also package parse/parsetest to check the corpus of messages in tests.
+func RoundTrip(input string, options ...ParseOption) error {
+	errorf := func(format string, args ...any) error {
+		return fmt.Errorf("%w: "+format, append([]any{ErrRoundTrip}, args...)...)
+	}
+
+	tree, err := Parse(input, options...)
+	if err != nil {
+		return err
+	}
+
+	for _, tree := range []AST{tree, Canonical(tree)} {
+		want := tree.String()
+
+		got, err := Parse(want, options...)
+		if err != nil {
+			return errorf("parse '%s': %w", want, err)
+		}
+
+		if want != got.String() {
+			return errorf("want '%s', got '%s'", want, got.String())
+		}
+
+		if c := Canonical(got).String(); c != Canonical(tree).String() {
+			return errorf("canonical: want '%s', got '%s'", Canonical(tree).String(), c)
+		}
+	}
+
+	return nil
+}
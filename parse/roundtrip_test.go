@@ -0,0 +1,25 @@
+package parse
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	for _, input := range []string{
+		"Hello, { $name }!",
+		"{ $x :number style=percent minimumFractionDigits=2 }",
+		".local $x = { 1 } .match { $x :number } 1 {{one}} * {{other}}",
+		`{{.dot \{ \}}}`,
+	} {
+		if err := RoundTrip(input); err != nil {
+			t.Errorf("'%s': %s", input, err)
+		}
+	}
+
+	if err := RoundTrip("{ $x"); err == nil || errors.Is(err, ErrRoundTrip) {
+		t.Errorf("want parse error, got '%v'", err)
+	}
+}
@@ -0,0 +1,76 @@
+package parse
+
+// MessageStats are the counts of the message parts, e.g. to estimate the translation effort.
+type MessageStats struct {
+	// Expressions is the number of expressions in the patterns, declarations and selectors.
+	Expressions int
+	// Variants is the number of the matcher variants.
+	Variants int
+	// Selectors is the number of the matcher selectors.
+	Selectors int
+	// Declarations is the number of input and local declarations, and reserved statements.
+	Declarations int
+	// Markup is the number of open, close and standalone markup placeholders.
+	Markup int
+	// MaxNesting is the maximum depth of the open markup, e.g. 2 for "{#b}{#i}text{/i}{/b}".
+	MaxNesting int
+}
+
+// Stats returns the statistics of the message.
+func Stats(ast AST) MessageStats {
+	var stats MessageStats
+
+	switch message := ast.Message.(type) {
+	case SimpleMessage:
+		stats.pattern(message)
+	case ComplexMessage:
+		stats.Declarations = len(message.Declarations)
+
+		for _, declaration := range message.Declarations {
+			switch declaration.(type) {
+			case InputDeclaration, LocalDeclaration:
+				stats.Expressions++
+			case ReservedStatement:
+				// expressions of the reserved statements are not counted, the statements are unsupported
+			}
+		}
+
+		switch body := message.ComplexBody.(type) {
+		case QuotedPattern:
+			stats.pattern(body)
+		case Matcher:
+			stats.Selectors = len(body.Selectors)
+			stats.Variants = len(body.Variants)
+			stats.Expressions += len(body.Selectors)
+
+			for _, variant := range body.Variants {
+				stats.pattern(variant.QuotedPattern)
+			}
+		}
+	}
+
+	return stats
+}
+
+// pattern counts the expressions and markup of the pattern.
+func (s *MessageStats) pattern(pattern []PatternPart) {
+	var depth int
+
+	for _, part := range pattern {
+		switch v := part.(type) {
+		case Expression:
+			s.Expressions++
+		case Markup:
+			s.Markup++
+
+			switch v.Typ {
+			case Open:
+				depth++
+				s.MaxNesting = max(s.MaxNesting, depth)
+			case Close:
+				depth = max(0, depth-1)
+			case Unspecified, SelfClose:
+			}
+		}
+	}
+}
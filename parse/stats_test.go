@@ -0,0 +1,37 @@
+package parse
+
+import "testing"
+
+func TestStats(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		in   string
+		want MessageStats
+	}{
+		{in: "Hello, World!", want: MessageStats{}},
+		{in: "Hello, { $name }!", want: MessageStats{Expressions: 1}},
+		{
+			in:   "{#b}{#i}{ $name }{/i}{/b} {#br/}",
+			want: MessageStats{Expressions: 1, Markup: 5, MaxNesting: 2},
+		},
+		{
+			in: ".input { $count :number } .local $x = { $y } .match { $count } { $x :string }" +
+				" 1 a {{one { $count }}} * * {{other}}",
+			want: MessageStats{Expressions: 5, Variants: 2, Selectors: 2, Declarations: 2},
+		},
+	} {
+		t.Run(test.in, func(t *testing.T) {
+			t.Parallel()
+
+			ast, err := Parse(test.in)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := Stats(ast); test.want != got {
+				t.Errorf("want '%+v', got '%+v'", test.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,216 @@
+package parse
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"go.expect.digital/mf2"
+)
+
+// validator checks the data model of the message not built by the parser, e.g. the decoded binary AST.
+// It reports the errors [Parse] reports, the variables are declared in the same order.
+type validator struct {
+	variables []Variable
+	reserved  Variable // reserved cannot be re-declared within the declaration expression
+}
+
+// validate returns error if the message breaks the data model: duplicate declarations, variant key mismatch,
+// missing fallback variant or the nodes the parser never produces, e.g. the complex message without body.
+func validate(message Message) error {
+	var v validator
+
+	switch message := message.(type) {
+	default:
+		return fmt.Errorf("unexpected message %T", message)
+	case nil:
+		return nil
+	case SimpleMessage:
+		return v.pattern(message)
+	case ComplexMessage:
+		return v.complexMessage(message)
+	}
+}
+
+func (v *validator) declare(variable Variable) {
+	if !slices.Contains(v.variables, variable) {
+		v.variables = append(v.variables, variable)
+	}
+}
+
+// redeclare declares the variable of the declaration, it must not be declared or used before.
+func (v *validator) redeclare(variable Variable) error {
+	if variable == "" {
+		return errors.New("declaration without variable")
+	}
+
+	if slices.Contains(v.variables, variable) {
+		return fmt.Errorf("%w: %s", mf2.ErrDuplicateDeclaration, variable)
+	}
+
+	v.declare(variable)
+	v.reserved = variable
+
+	return nil
+}
+
+func (v *validator) complexMessage(message ComplexMessage) error {
+	for _, declaration := range message.Declarations {
+		if err := v.declaration(declaration); err != nil {
+			return err
+		}
+	}
+
+	switch body := message.ComplexBody.(type) {
+	default:
+		return fmt.Errorf("unexpected complex body %T", body)
+	case nil:
+		return errors.New("complex message without body")
+	case QuotedPattern:
+		return v.pattern(body)
+	case Matcher:
+		return v.matcher(body)
+	}
+}
+
+func (v *validator) declaration(declaration Declaration) error {
+	defer func() { v.reserved = "" }()
+
+	switch d := declaration.(type) {
+	default:
+		return fmt.Errorf("unexpected declaration %T", d)
+	case InputDeclaration:
+		// .input {$foo} .input {$foo}
+		variable, ok := d.Operand.(Variable)
+		if !ok {
+			return fmt.Errorf("%w: input declaration operand %T, want variable", mf2.ErrBadOperand, d.Operand)
+		}
+
+		if err := v.redeclare(variable); err != nil {
+			return err
+		}
+
+		return v.expression(Expression(d))
+	case LocalDeclaration:
+		// .local $foo = {$foo}
+		if err := v.redeclare(d.Variable); err != nil {
+			return err
+		}
+
+		if d.Expression.Operand == Value(d.Variable) {
+			return fmt.Errorf("%w: %s", mf2.ErrDuplicateDeclaration, d.Variable)
+		}
+
+		return v.expression(d.Expression)
+	case ReservedStatement:
+		if len(d.Expressions) == 0 {
+			return errors.New("reserved statement without expressions")
+		}
+
+		for _, expr := range d.Expressions {
+			if err := v.expression(expr); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+}
+
+func (v *validator) matcher(matcher Matcher) error {
+	if len(matcher.Selectors) == 0 {
+		return errors.New("matcher without selectors")
+	}
+
+	for _, selector := range matcher.Selectors {
+		if err := v.expression(selector); err != nil {
+			return err
+		}
+	}
+
+	var fallback bool
+
+	for _, variant := range matcher.Variants {
+		if len(variant.Keys) != len(matcher.Selectors) {
+			return fmt.Errorf("%w: %d selectors and %d keys", mf2.ErrVariantKeyMismatch,
+				len(matcher.Selectors), len(variant.Keys))
+		}
+
+		fallback = fallback || isFallback(variant.Keys)
+
+		if err := v.pattern(variant.QuotedPattern); err != nil {
+			return err
+		}
+	}
+
+	if !fallback {
+		return mf2.ErrMissingFallbackVariant
+	}
+
+	return nil
+}
+
+func (v *validator) pattern(pattern []PatternPart) error {
+	for _, part := range pattern {
+		switch part := part.(type) {
+		default:
+			return fmt.Errorf("unexpected pattern part %T", part)
+		case Text:
+		case Expression:
+			if err := v.expression(part); err != nil {
+				return err
+			}
+		case Markup:
+			if err := v.options(part.Options); err != nil {
+				return err
+			}
+
+			v.attributes(part.Attributes)
+		}
+	}
+
+	return nil
+}
+
+func (v *validator) expression(expr Expression) error {
+	switch operand := expr.Operand.(type) {
+	case nil:
+		if expr.Annotation == nil {
+			return errors.New("expression without operand and annotation")
+		}
+	case Variable:
+		v.declare(operand)
+	}
+
+	if function, ok := expr.Annotation.(Function); ok {
+		if err := v.options(function.Options); err != nil {
+			return err
+		}
+	}
+
+	v.attributes(expr.Attributes)
+
+	return nil
+}
+
+func (v *validator) options(options []Option) error {
+	for _, option := range options {
+		if variable, ok := option.Value.(Variable); ok {
+			if variable == v.reserved {
+				return fmt.Errorf("%w: %s", mf2.ErrDuplicateDeclaration, variable)
+			}
+
+			v.declare(variable)
+		}
+	}
+
+	return nil
+}
+
+func (v *validator) attributes(attributes []Attribute) {
+	for _, attribute := range attributes {
+		if variable, ok := attribute.Value.(Variable); ok {
+			v.declare(variable)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package parse
+
+import (
+	"fmt"
+	"slices"
+)
+
+// SpecVersion is the version of the MessageFormat 2.0 specification.
+type SpecVersion string
+
+const (
+	// SpecDraft2024 is the 2024 draft grammar with reserved and private-use annotations,
+	// reserved statements and the expression selectors, ".match {$x :number}". It is the default version.
+	SpecDraft2024 SpecVersion = "2024-draft"
+	// SpecLDML46 is the grammar of LDML 46, the reserved and private-use annotations
+	// and reserved statements are removed. The selectors are the declared variables, ".match $x".
+	SpecLDML46 SpecVersion = "LDML46"
+	// SpecLDML47 is the grammar of LDML 47, the final MessageFormat 2.0.
+	SpecLDML47 SpecVersion = "LDML47"
+)
+
+// specVersions are the supported versions from the oldest to the latest.
+var specVersions = []SpecVersion{SpecDraft2024, SpecLDML46, SpecLDML47}
+
+// WithSpecVersion sets the specification version of the grammar, [SpecDraft2024] by default.
+// Use it to parse the stored messages written for a specific version.
+func WithSpecVersion(version SpecVersion) ParseOption {
+	return func(p *parser) {
+		p.version = version
+	}
+}
+
+// validate returns error if the version is not supported.
+func (v SpecVersion) validate() error {
+	if !slices.Contains(specVersions, v) {
+		return fmt.Errorf(`unsupported spec version "%s", want one of %v`, v, specVersions)
+	}
+
+	return nil
+}
+
+// since reports whether the version is the same or later than the other.
+func (v SpecVersion) since(other SpecVersion) bool {
+	return slices.Index(specVersions, v) >= slices.Index(specVersions, other)
+}
+
+// reserved returns error if the reserved syntax is removed in the spec version of the parser.
+func (p *parser) reserved(what string) error {
+	if p.version.since(SpecLDML46) {
+		return fmt.Errorf("%s is not allowed in %s", what, p.version)
+	}
+
+	return nil
+}
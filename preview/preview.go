@@ -0,0 +1,194 @@
+// Package preview provides the HTTP handler formatting MF2 messages for the translator preview UIs.
+//
+// The client posts the message, locale and arguments as JSON:
+//
+//	{"message": "Sveiki, { $name }!", "locale": "lv", "args": {"name": "Jānis"}}
+//
+// The response is the formatted message and parts, the diagnostics of the message and the resolution errors:
+//
+//	{
+//	  "result": "Sveiki, Jānis!",
+//	  "parts": [{"type": "text", "value": "Sveiki, "}, ...],
+//	  "diagnostics": [],
+//	  "errors": []
+//	}
+//
+// The message with syntax or data model errors is not formatted, the result is empty and the diagnostics
+// report the errors. The invalid request fails with "400 Bad Request" and the error, e.g.
+//
+//	{"error": {"code": "bad-request", "message": "locale: language: tag is not well-formed"}}
+package preview
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"golang.org/x/text/language"
+
+	"go.expect.digital/mf2"
+	"go.expect.digital/mf2/internal/multierr"
+	"go.expect.digital/mf2/parse"
+	"go.expect.digital/mf2/template"
+)
+
+// defaultMaxRequestSize is the default maximum size of the request body in bytes.
+const defaultMaxRequestSize = 1 << 20
+
+// request is the JSON request of the preview.
+type request struct {
+	Args    map[string]any `json:"args"`
+	Message *string        `json:"message"`
+	Locale  string         `json:"locale"`
+}
+
+// responseError is the error in the JSON response.
+type responseError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// response is the JSON response of the preview.
+type response struct {
+	Result      string           `json:"result"`
+	Parts       []template.Part  `json:"parts"`
+	Diagnostics []mf2.Diagnostic `json:"diagnostics"`
+	Errors      []responseError  `json:"errors"`
+}
+
+// Option is the option of the [Handler].
+type Option func(h *handler)
+
+// WithTemplateOptions sets the options of the template formatting the message, e.g. the custom functions
+// with [template.WithFunc]. The locale of the request overrides the locale of the options.
+func WithTemplateOptions(options ...template.Option) Option {
+	return func(h *handler) {
+		h.templateOptions = append(h.templateOptions, options...)
+	}
+}
+
+// WithMaxRequestSize sets the maximum size of the request body in bytes, the default is 1 MiB.
+func WithMaxRequestSize(size int64) Option {
+	return func(h *handler) {
+		h.maxRequestSize = size
+	}
+}
+
+// handler formats the messages of the preview requests.
+type handler struct {
+	templateOptions []template.Option
+	maxRequestSize  int64
+}
+
+// Handler returns the HTTP handler formatting the message of the POST request, see the package documentation.
+func Handler(options ...Option) http.Handler {
+	h := &handler{maxRequestSize: defaultMaxRequestSize}
+
+	for _, o := range options {
+		o(h)
+	}
+
+	return h
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeError(w, http.StatusMethodNotAllowed, errors.New("want POST"))
+
+		return
+	}
+
+	var req request
+
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, h.maxRequestSize)).Decode(&req); err != nil {
+		status := http.StatusBadRequest
+
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			status = http.StatusRequestEntityTooLarge
+		}
+
+		writeError(w, status, fmt.Errorf("decode request: %w", err))
+
+		return
+	}
+
+	if req.Message == nil {
+		writeError(w, http.StatusBadRequest, errors.New(`decode request: missing "message"`))
+		return
+	}
+
+	options := slices.Clip(h.templateOptions)
+
+	if req.Locale != "" {
+		locale, err := language.Parse(req.Locale)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("locale: %w", err))
+			return
+		}
+
+		options = append(options, template.WithLocale(locale))
+	}
+
+	writeJSON(w, http.StatusOK, preview(*req.Message, req.Args, options))
+}
+
+// preview returns the formatted message, its parts, diagnostics and resolution errors.
+func preview(message string, args map[string]any, options []template.Option) response {
+	resp := response{
+		Parts:       []template.Part{},
+		Diagnostics: []mf2.Diagnostic{},
+		Errors:      []responseError{},
+	}
+
+	if _, diagnostics := parse.Diagnose(message); diagnostics != nil {
+		resp.Diagnostics = diagnostics
+	}
+
+	t, err := template.New(options...).Parse(message)
+	if err != nil {
+		// the diagnostics report the error of the message
+		return resp
+	}
+
+	resp.Result, err = t.Sprint(args)
+
+	for _, err := range multierr.Errors(err) {
+		resp.Errors = append(resp.Errors, responseError{Code: mf2.ErrorCode(err), Message: err.Error()})
+	}
+
+	// the resolution errors are the same as of the formatted message
+	if parts, _ := t.FormatToParts(args); parts != nil {
+		resp.Parts = parts
+	}
+
+	return resp
+}
+
+// writeError writes the JSON response of the failed request, the code is the status text in kebab case,
+// e.g. "bad-request".
+func writeError(w http.ResponseWriter, status int, err error) {
+	code := strings.ToLower(strings.ReplaceAll(http.StatusText(status), " ", "-"))
+
+	writeJSON(w, status, struct {
+		Error responseError `json:"error"`
+	}{
+		Error: responseError{Code: code, Message: err.Error()},
+	})
+}
+
+// writeJSON writes the JSON response, the HTML characters are not escaped.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+
+	_ = enc.Encode(v)
+}
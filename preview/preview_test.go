@@ -0,0 +1,109 @@
+package preview
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"golang.org/x/text/language"
+
+	"go.expect.digital/mf2/template"
+)
+
+func TestHandler(t *testing.T) {
+	t.Parallel()
+
+	h := Handler(
+		WithTemplateOptions(template.WithLocale(language.Latvian)),
+		WithMaxRequestSize(256),
+	)
+
+	for _, test := range []struct {
+		name, method, body string
+		status             int
+		want               string
+	}{
+		{
+			name:   "formatted",
+			body:   `{"message": "Sveiki, { $name }!", "args": {"name": "Jānis"}}`,
+			status: http.StatusOK,
+			want: `{"result":"Sveiki, Jānis!","parts":[{"type":"text","value":"Sveiki, "},` +
+				`{"type":"expression","source":"$name","value":"Jānis"},{"type":"text","value":"!"}],` +
+				`"diagnostics":[],"errors":[]}`,
+		},
+		{
+			name:   "locale",
+			body:   `{"message": "{ 1.5 :number }", "locale": "en"}`,
+			status: http.StatusOK,
+			want: `{"result":"1.5","parts":[{"type":"expression","source":"1.5","value":"1.5"}],` +
+				`"diagnostics":[],"errors":[]}`,
+		},
+		{
+			name:   "resolution error",
+			body:   `{"message": "Hello, { $name }!"}`,
+			status: http.StatusOK,
+			want: `{"result":"Hello, {$name}!","parts":[{"type":"text","value":"Hello, "},` +
+				`{"type":"expression","source":"$name","value":"{$name}"},{"type":"text","value":"!"}],` +
+				`"diagnostics":[],"errors":[{"code":"unresolved-variable",` +
+				`"message":"execute template: expression: unresolved variable \"$name\""}]}`,
+		},
+		{
+			name:   "syntax error",
+			body:   `{"message": "Hello, { $name"}`,
+			status: http.StatusOK,
+			want: `{"result":"","parts":[],"diagnostics":[{"severity":"error","code":"syntax-error",` +
+				`"message":"parse MF2: syntax error: unexpected eof in expression","start":0,"end":14}],"errors":[]}`,
+		},
+		{
+			name:   "bad locale",
+			body:   `{"message": "", "locale": "x"}`,
+			status: http.StatusBadRequest,
+			want:   `{"error":{"code":"bad-request","message":"locale: language: tag is not well-formed"}}`,
+		},
+		{
+			name:   "missing message",
+			body:   `{}`,
+			status: http.StatusBadRequest,
+			want:   `{"error":{"code":"bad-request","message":"decode request: missing \"message\""}}`,
+		},
+		{
+			name:   "too large",
+			body:   `{"message": "` + strings.Repeat("a", 256) + `"}`,
+			status: http.StatusRequestEntityTooLarge,
+			want: `{"error":{"code":"request-entity-too-large",` +
+				`"message":"decode request: http: request body too large"}}`,
+		},
+		{
+			name:   "method",
+			method: http.MethodGet,
+			status: http.StatusMethodNotAllowed,
+			want:   `{"error":{"code":"method-not-allowed","message":"want POST"}}`,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			method := test.method
+			if method == "" {
+				method = http.MethodPost
+			}
+
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest(method, "/", strings.NewReader(test.body)))
+
+			if w.Code != test.status {
+				t.Errorf("want status %d, got %d", test.status, w.Code)
+			}
+
+			if !json.Valid(w.Body.Bytes()) {
+				t.Fatalf("want JSON, got '%s'", w.Body.String())
+			}
+
+			if got := strings.TrimSpace(w.Body.String()); test.want != got {
+				t.Errorf("want '%s', got '%s'", test.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,235 @@
+/*
+Package sheet exports the messages of a bundle to spreadsheets for translators and imports the translated
+spreadsheets back, as CSV, see [EncodeCSV], or as Excel workbooks, see [EncodeXLSX].
+
+Each [Row] has the columns "ID", "Source", "Translation" and "Comment". The rows are the entries of
+package [tms], the placeholders are protected as numbered tokens, e.g. "Hello, {0}!", and each variant
+of a matcher is a separate row with the ID "id#keys", e.g. "apples#one".
+
+The rows are validated on import: the rows translated from an outdated source text and the translations
+longer than the length limit of the message are reported, see [ErrSourceChanged] and [ErrTooLong],
+as are the broken placeholders, see [tms.Import].
+
+Example:
+
+	rows, err := sheet.Export(b, language.Latvian)
+	...
+	err = sheet.EncodeXLSX(w, rows)
+	...
+	rows, err = sheet.DecodeXLSX(r, size) // the spreadsheet of the translator
+	...
+	c, err := sheet.Import(b, language.Latvian, rows)
+*/
+package sheet
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/language"
+
+	"go.expect.digital/mf2/bundle"
+	"go.expect.digital/mf2/catalog"
+	"go.expect.digital/mf2/tms"
+)
+
+var (
+	// ErrSourceChanged occurs when the source text of the row differs from the source message,
+	// e.g. the source message was changed after the export.
+	ErrSourceChanged = errors.New("source changed")
+	// ErrTooLong occurs when the translation is longer than the length limit of the message, see [tms.MaxLengthKey].
+	ErrTooLong = errors.New("translation too long")
+)
+
+// header is the header row of the spreadsheet, the columns of [Row].
+var header = []string{"ID", "Source", "Translation", "Comment"}
+
+// Row is a row of the spreadsheet.
+type Row struct {
+	// ID is the message ID, or "id#keys" for the variant of a matcher, e.g. "apples#one".
+	ID string
+	// Source is the protected pattern of the source message.
+	Source string
+	// Translation is the protected translation, empty if not translated.
+	Translation string
+	// Comment is the description and the notes of the message and the length limit, if any.
+	Comment string
+}
+
+// Export returns the rows of the messages of the default locale of the bundle, sorted by ID,
+// with the translations of the locale, see [tms.Export].
+func Export(b *bundle.Bundle, locale language.Tag) ([]Row, error) {
+	entries, err := tms.Export(b, locale)
+	if err != nil {
+		return nil, fmt.Errorf("export rows: %w", err)
+	}
+
+	rows := make([]Row, 0, len(entries))
+
+	for _, e := range entries {
+		rows = append(rows, Row{ID: e.Key, Source: e.Source, Translation: e.Target, Comment: comment(e)})
+	}
+
+	return rows, nil
+}
+
+// Import validates the rows and sets the translations in the catalog of the locale, see [tms.Import].
+// The rows without the translation are skipped, the rows without the source text are not checked
+// for [ErrSourceChanged]. The length of the translation is the number of characters,
+// the placeholder tokens included.
+//
+// The invalid rows are reported together, the other rows are imported.
+func Import(b *bundle.Bundle, locale language.Tag, rows []Row) (*catalog.Catalog, error) {
+	errorf := func(err error) error {
+		return fmt.Errorf("import rows: %w", err)
+	}
+
+	exported, err := tms.Export(b, locale)
+	if err != nil {
+		return nil, errorf(err)
+	}
+
+	current := make(map[string]tms.Entry, len(exported))
+	for _, e := range exported {
+		current[e.Key] = e
+	}
+
+	var errs []error
+
+	entries := make([]tms.Entry, 0, len(rows))
+
+	for _, row := range rows {
+		if row.Translation == "" {
+			continue
+		}
+
+		if e, ok := current[row.ID]; ok {
+			if row.Source != "" && row.Source != e.Source {
+				errs = append(errs, fmt.Errorf(`row "%s": %w: want "%s", got "%s"`, row.ID, ErrSourceChanged, e.Source, row.Source))
+				continue
+			}
+
+			if n := utf8.RuneCountInString(row.Translation); e.MaxLength > 0 && n > e.MaxLength {
+				errs = append(errs, fmt.Errorf(`row "%s": %w: %d characters, limit %d`, row.ID, ErrTooLong, n, e.MaxLength))
+				continue
+			}
+		}
+
+		entries = append(entries, tms.Entry{Key: row.ID, Source: row.Source, Target: row.Translation})
+	}
+
+	c, err := tms.Import(b, locale, entries)
+	if err != nil && c == nil {
+		return nil, errorf(err)
+	}
+
+	if err := errors.Join(append(errs, err)...); err != nil {
+		return c, errorf(err)
+	}
+
+	return c, nil
+}
+
+// comment returns the comment of the entry, the context and the length limit, e.g. "Home page (max. 30 characters)".
+func comment(e tms.Entry) string {
+	if e.MaxLength <= 0 {
+		return e.Context
+	}
+
+	limit := fmt.Sprintf("(max. %d characters)", e.MaxLength)
+
+	if e.Context == "" {
+		return limit
+	}
+
+	return e.Context + " " + limit
+}
+
+// EncodeCSV writes the rows as CSV with the header "ID,Source,Translation,Comment".
+func EncodeCSV(w io.Writer, rows []Row) error {
+	errorf := func(err error) error {
+		return fmt.Errorf("encode CSV: %w", err)
+	}
+
+	cw := csv.NewWriter(w)
+
+	if err := cw.Write(header); err != nil {
+		return errorf(err)
+	}
+
+	for _, row := range rows {
+		if err := cw.Write([]string{row.ID, row.Source, row.Translation, row.Comment}); err != nil {
+			return errorf(err)
+		}
+	}
+
+	cw.Flush()
+
+	if err := cw.Error(); err != nil {
+		return errorf(err)
+	}
+
+	return nil
+}
+
+// DecodeCSV reads the rows of the CSV file with the header row, see [EncodeCSV].
+func DecodeCSV(r io.Reader) ([]Row, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+
+	records, err := cr.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("decode CSV: %w", err)
+	}
+
+	rows, err := decodeRecords(records)
+	if err != nil {
+		return nil, fmt.Errorf("decode CSV: %w", err)
+	}
+
+	return rows, nil
+}
+
+// decodeRecords returns the rows of the records with the header row. The columns are matched by name
+// in any order, case-insensitive, the "ID" and "Translation" columns are required, the unknown columns
+// are ignored. The empty records are skipped.
+func decodeRecords(records [][]string) ([]Row, error) {
+	if len(records) == 0 {
+		return nil, errors.New("missing header")
+	}
+
+	columns := make(map[string]int, len(records[0]))
+	for i, name := range records[0] {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	for _, name := range []string{"ID", "Translation"} {
+		if _, ok := columns[strings.ToLower(name)]; !ok {
+			return nil, fmt.Errorf(`missing column "%s"`, name)
+		}
+	}
+
+	var rows []Row
+
+	for _, record := range records[1:] {
+		field := func(name string) string {
+			if i, ok := columns[strings.ToLower(name)]; ok && i < len(record) {
+				return record[i]
+			}
+
+			return ""
+		}
+
+		row := Row{ID: field("ID"), Source: field("Source"), Translation: field("Translation"), Comment: field("Comment")}
+
+		if row != (Row{}) {
+			rows = append(rows, row)
+		}
+	}
+
+	return rows, nil
+}
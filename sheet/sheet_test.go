@@ -0,0 +1,139 @@
+package sheet
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"golang.org/x/text/language"
+
+	"go.expect.digital/mf2/bundle/bundletest"
+	"go.expect.digital/mf2/catalog"
+	"go.expect.digital/mf2/tms"
+)
+
+// messages are the messages of the tests by locale, see [bundletest.New].
+var messages = map[language.Tag][]catalog.Message{
+	language.English: {
+		{
+			ID:          "greeting",
+			Text:        "Hello, { $name }!",
+			Description: "Home page",
+			Metadata:    map[string]string{tms.MaxLengthKey: "20"},
+		},
+		{ID: "apples", Text: ".input { $count :number }\n.match { $count }\none {{{ $count } apple}}\n* {{{ $count } apples}}"},
+	},
+}
+
+func TestExport(t *testing.T) {
+	t.Parallel()
+
+	got, err := Export(bundletest.New(language.English, messages), language.Latvian)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Row{
+		{ID: "apples#one", Source: "{0} apple"},
+		{ID: "apples#*", Source: "{0} apples"},
+		{ID: "greeting", Source: "Hello, {0}!", Comment: "Home page (max. 20 characters)"},
+	}
+
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("want %+v, got %+v", want, got)
+	}
+}
+
+func TestImport(t *testing.T) {
+	t.Parallel()
+
+	b := bundletest.New(language.English, messages)
+
+	c, err := Import(b, language.Latvian, []Row{
+		{ID: "apples#one", Source: "{0} apple", Translation: "{0} ābols"},
+		{ID: "apples#*", Source: "{0} apples", Translation: "{0} āboli"},
+		{ID: "greeting", Translation: "Sveiki, {0}!"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for id, want := range map[string]string{
+		"apples":   ".input { $count :number }\n.match { $count }\none {{{ $count } ābols}}\n* {{{ $count } āboli}}",
+		"greeting": "Sveiki, { $name }!",
+	} {
+		msg, _ := c.Message(id)
+		if want != msg.Text {
+			t.Errorf("want '%s', got '%s'", want, msg.Text)
+		}
+	}
+}
+
+func TestImportErrors(t *testing.T) {
+	t.Parallel()
+
+	b := bundletest.New(language.English, messages)
+
+	c, err := Import(b, language.Latvian, []Row{
+		{ID: "apples#one", Source: "{0} apples", Translation: "{0} ābols"},
+		{ID: "apples#*", Source: "{0} apples", Translation: "{0} āboli"},
+		{ID: "greeting", Translation: "Labdien, dārgais lietotāj {0}!"},
+	})
+
+	for _, want := range []error{ErrSourceChanged, ErrTooLong} {
+		if !errors.Is(err, want) {
+			t.Errorf("want '%s', got '%v'", want, err)
+		}
+	}
+
+	if _, err = Import(b, language.Latvian, []Row{{ID: "greeting", Translation: "Sveiki, {1}!"}}); err == nil ||
+		!strings.Contains(err.Error(), `unknown placeholder "{1}"`) {
+		t.Errorf("want unknown placeholder, got '%v'", err)
+	}
+
+	// the valid rows are imported
+	if msg, _ := c.Message("apples"); !strings.Contains(msg.Text, "āboli") {
+		t.Errorf("want 'āboli', got '%s'", msg.Text)
+	}
+}
+
+func TestCSV(t *testing.T) {
+	t.Parallel()
+
+	rows := []Row{
+		{ID: "greeting", Source: "Hello, {0}!", Translation: "Sveiki, {0}!", Comment: "Home page"},
+		{ID: "lines", Source: "a,\n\"b\""},
+	}
+
+	var buf bytes.Buffer
+
+	if err := EncodeCSV(&buf, rows); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := DecodeCSV(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(rows, got) {
+		t.Errorf("want %+v, got %+v", rows, got)
+	}
+
+	// the columns are matched by name
+	got, err = DecodeCSV(strings.NewReader("translation,id,notes\nSveiki!,greeting,x\n,,\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []Row{{ID: "greeting", Translation: "Sveiki!"}}; !reflect.DeepEqual(want, got) {
+		t.Errorf("want %+v, got %+v", want, got)
+	}
+
+	if _, err := DecodeCSV(strings.NewReader("ID,Source\n")); err == nil ||
+		!strings.Contains(err.Error(), `missing column "Translation"`) {
+		t.Errorf(`want missing column "Translation", got '%v'`, err)
+	}
+}
@@ -0,0 +1,345 @@
+package sheet
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// xlsxFiles are the static parts of the workbook written by [EncodeXLSX].
+var xlsxFiles = []struct{ name, content string }{
+	{
+		name: "[Content_Types].xml",
+		content: `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+			`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+			`<Default Extension="xml" ContentType="application/xml"/>` +
+			`<Override PartName="/xl/workbook.xml" ` +
+			`ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+			`<Override PartName="/xl/worksheets/sheet1.xml" ` +
+			`ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+			`</Types>`,
+	},
+	{
+		name: "_rels/.rels",
+		content: `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" ` +
+			`Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" ` +
+			`Target="xl/workbook.xml"/>` +
+			`</Relationships>`,
+	},
+	{
+		name: "xl/workbook.xml",
+		content: `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" ` +
+			`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+			`<sheets><sheet name="Translations" sheetId="1" r:id="rId1"/></sheets>` +
+			`</workbook>`,
+	},
+	{
+		name: "xl/_rels/workbook.xml.rels",
+		content: `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" ` +
+			`Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" ` +
+			`Target="worksheets/sheet1.xml"/>` +
+			`</Relationships>`,
+	},
+}
+
+// EncodeXLSX writes the rows as an Excel workbook with a single sheet and the header row
+// "ID", "Source", "Translation" and "Comment". The cells are inline strings.
+func EncodeXLSX(w io.Writer, rows []Row) error {
+	errorf := func(err error) error {
+		return fmt.Errorf("encode XLSX: %w", err)
+	}
+
+	zw := zip.NewWriter(w)
+
+	for _, f := range xlsxFiles {
+		fw, err := zw.Create(f.name)
+		if err != nil {
+			return errorf(err)
+		}
+
+		if _, err := io.WriteString(fw, xml.Header+f.content); err != nil {
+			return errorf(err)
+		}
+	}
+
+	fw, err := zw.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return errorf(err)
+	}
+
+	sheet := xlsxWorksheet{Rows: make([]xlsxRow, 0, len(rows)+1)}
+
+	for i, record := range append([][]string{header}, records(rows)...) {
+		row := xlsxRow{N: i + 1, Cells: make([]xlsxCell, 0, len(record))}
+
+		for j, s := range record {
+			if s == "" {
+				continue
+			}
+
+			row.Cells = append(row.Cells, xlsxCell{
+				Ref:    cellRef(j, i+1),
+				Type:   "inlineStr",
+				Inline: &xlsxString{Text: xlsxText{Space: "preserve", Value: s}},
+			})
+		}
+
+		sheet.Rows = append(sheet.Rows, row)
+	}
+
+	if _, err := io.WriteString(fw, xml.Header); err != nil {
+		return errorf(err)
+	}
+
+	if err := xml.NewEncoder(fw).Encode(sheet); err != nil {
+		return errorf(err)
+	}
+
+	if err := zw.Close(); err != nil {
+		return errorf(err)
+	}
+
+	return nil
+}
+
+// DecodeXLSX reads the rows of the first sheet of the Excel workbook with the header row,
+// the columns are matched by name as in [DecodeCSV]. The workbooks saved by spreadsheet
+// applications, with the shared strings, are supported.
+func DecodeXLSX(r io.ReaderAt, size int64) ([]Row, error) {
+	errorf := func(err error) ([]Row, error) {
+		return nil, fmt.Errorf("decode XLSX: %w", err)
+	}
+
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return errorf(err)
+	}
+
+	name, err := firstSheet(zr)
+	if err != nil {
+		return errorf(err)
+	}
+
+	var shared xlsxSharedStrings
+
+	if err := decodeXML(zr, "xl/sharedStrings.xml", &shared); err != nil && !errors.Is(err, errMissingPart) {
+		return errorf(err)
+	}
+
+	var sheet xlsxWorksheet
+
+	if err := decodeXML(zr, name, &sheet); err != nil {
+		return errorf(err)
+	}
+
+	var records [][]string
+
+	for _, row := range sheet.Rows {
+		var record []string
+
+		for i, cell := range row.Cells {
+			col := i
+			if cell.Ref != "" {
+				col = cellColumn(cell.Ref)
+			}
+
+			if col < 0 {
+				return errorf(fmt.Errorf(`invalid cell reference "%s"`, cell.Ref))
+			}
+
+			value, err := cell.value(shared.Items)
+			if err != nil {
+				return errorf(fmt.Errorf("cell %s: %w", cell.Ref, err))
+			}
+
+			for len(record) <= col {
+				record = append(record, "")
+			}
+
+			record[col] = value
+		}
+
+		records = append(records, record)
+	}
+
+	rows, err := decodeRecords(records)
+	if err != nil {
+		return errorf(err)
+	}
+
+	return rows, nil
+}
+
+// records returns the records of the rows.
+func records(rows []Row) [][]string {
+	r := make([][]string, 0, len(rows))
+	for _, row := range rows {
+		r = append(r, []string{row.ID, row.Source, row.Translation, row.Comment})
+	}
+
+	return r
+}
+
+// cellRef returns the A1 reference of the cell, the column is zero-based, e.g. "C2" for 2 and 2.
+func cellRef(col, row int) string {
+	var name string
+
+	for col++; col > 0; col = (col - 1) / 26 { //nolint:mnd
+		name = string(rune('A'+(col-1)%26)) + name //nolint:mnd
+	}
+
+	return name + strconv.Itoa(row)
+}
+
+// cellColumn returns the zero-based column of the A1 reference, e.g. 2 for "C2", -1 if invalid.
+func cellColumn(ref string) int {
+	col := 0
+
+	for _, r := range ref {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+
+		col = col*26 + int(r-'A'+1) //nolint:mnd
+	}
+
+	return col - 1
+}
+
+// errMissingPart occurs when the part of the workbook is missing.
+var errMissingPart = errors.New("missing part")
+
+// decodeXML decodes the XML part of the workbook.
+func decodeXML(zr *zip.Reader, name string, v any) error {
+	f, err := zr.Open(name)
+	if err != nil {
+		return fmt.Errorf(`%w "%s"`, errMissingPart, name)
+	}
+
+	defer f.Close()
+
+	if err := xml.NewDecoder(f).Decode(v); err != nil {
+		return fmt.Errorf(`part "%s": %w`, name, err)
+	}
+
+	return nil
+}
+
+// firstSheet returns the name of the part of the first sheet of the workbook.
+func firstSheet(zr *zip.Reader) (string, error) {
+	var workbook struct {
+		Sheets []struct {
+			ID string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+		} `xml:"sheets>sheet"`
+	}
+
+	if err := decodeXML(zr, "xl/workbook.xml", &workbook); err != nil {
+		return "", err
+	}
+
+	if len(workbook.Sheets) == 0 {
+		return "", errors.New("no sheets")
+	}
+
+	var rels struct {
+		Relationships []struct {
+			ID     string `xml:"Id,attr"`
+			Target string `xml:"Target,attr"`
+		} `xml:"Relationship"`
+	}
+
+	if err := decodeXML(zr, "xl/_rels/workbook.xml.rels", &rels); err != nil {
+		return "", err
+	}
+
+	for _, rel := range rels.Relationships {
+		if rel.ID != workbook.Sheets[0].ID {
+			continue
+		}
+
+		// the target is relative to "xl/" or absolute in the package
+		if strings.HasPrefix(rel.Target, "/") {
+			return strings.TrimPrefix(rel.Target, "/"), nil
+		}
+
+		return path.Join("xl", rel.Target), nil
+	}
+
+	return "", fmt.Errorf(`missing relationship "%s" of the first sheet`, workbook.Sheets[0].ID)
+}
+
+type xlsxWorksheet struct {
+	XMLName xml.Name  `xml:"http://schemas.openxmlformats.org/spreadsheetml/2006/main worksheet"`
+	Rows    []xlsxRow `xml:"sheetData>row"`
+}
+
+type xlsxRow struct {
+	Cells []xlsxCell `xml:"c"`
+	N     int        `xml:"r,attr,omitempty"`
+}
+
+type xlsxCell struct {
+	Inline *xlsxString `xml:"is,omitempty"`
+	Ref    string      `xml:"r,attr,omitempty"`
+	Type   string      `xml:"t,attr,omitempty"`
+	Value  string      `xml:"v,omitempty"`
+}
+
+// value returns the text of the cell, the shared strings are the items of the shared string table.
+func (c xlsxCell) value(shared []xlsxString) (string, error) {
+	switch c.Type {
+	case "inlineStr":
+		if c.Inline == nil {
+			return "", nil
+		}
+
+		return c.Inline.String(), nil
+	case "s":
+		i, err := strconv.Atoi(c.Value)
+		if err != nil || i < 0 || i >= len(shared) {
+			return "", fmt.Errorf(`invalid shared string "%s"`, c.Value)
+		}
+
+		return shared[i].String(), nil
+	default: // numbers, booleans and formula strings
+		return c.Value, nil
+	}
+}
+
+// xlsxString is the rich text, the plain text or the runs of the formatted text.
+type xlsxString struct {
+	Text xlsxText  `xml:"t"`
+	Runs []xlsxRun `xml:"r"`
+}
+
+type xlsxRun struct {
+	Text xlsxText `xml:"t"`
+}
+
+func (s xlsxString) String() string {
+	var sb strings.Builder
+
+	sb.WriteString(s.Text.Value)
+
+	for _, r := range s.Runs {
+		sb.WriteString(r.Text.Value)
+	}
+
+	return sb.String()
+}
+
+type xlsxText struct {
+	Space string `xml:"http://www.w3.org/XML/1998/namespace space,attr,omitempty"`
+	Value string `xml:",chardata"`
+}
+
+type xlsxSharedStrings struct {
+	Items []xlsxString `xml:"si"`
+}
@@ -0,0 +1,92 @@
+package sheet
+
+import (
+	"archive/zip"
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestXLSX(t *testing.T) {
+	t.Parallel()
+
+	rows := []Row{
+		{ID: "greeting", Source: "Hello, {0}! <b>", Translation: " Sveiki, {0}! ", Comment: "Home page"},
+		{ID: "lines", Source: "a\n\"b\" & c"},
+	}
+
+	var buf bytes.Buffer
+
+	if err := EncodeXLSX(&buf, rows); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := DecodeXLSX(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(rows, got) {
+		t.Errorf("want %+v, got %+v", rows, got)
+	}
+}
+
+// TestDecodeXLSXSharedStrings tests the workbook as saved by spreadsheet applications.
+func TestDecodeXLSXSharedStrings(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	zw := zip.NewWriter(&buf)
+
+	for name, content := range map[string]string{
+		"xl/workbook.xml": `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" ` +
+			`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+			`<sheets><sheet name="Sheet1" sheetId="1" r:id="rId3"/></sheets></workbook>`,
+		"xl/_rels/workbook.xml.rels": `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId3" Target="/xl/worksheets/data.xml"/></Relationships>`,
+		"xl/sharedStrings.xml": `<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+			`<si><t>ID</t></si><si><t>Translation</t></si><si><t>greeting</t></si>` +
+			`<si><r><t>Sveiki, </t></r><r><rPr><b/></rPr><t>{0}</t></r></si></sst>`,
+		"xl/worksheets/data.xml": `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>` +
+			`<row r="1"><c r="A1" t="s"><v>0</v></c><c r="C1" t="s"><v>1</v></c></row>` +
+			`<row r="2"><c r="A2" t="s"><v>2</v></c><c r="C2" t="s"><v>3</v></c><c r="AA2"><v>12</v></c></row>` +
+			`</sheetData></worksheet>`,
+	} {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := w.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := DecodeXLSX(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []Row{{ID: "greeting", Translation: "Sveiki, {0}"}}; !reflect.DeepEqual(want, got) {
+		t.Errorf("want %+v, got %+v", want, got)
+	}
+}
+
+func TestCellRef(t *testing.T) {
+	t.Parallel()
+
+	for col, want := range map[int]string{0: "A1", 25: "Z1", 26: "AA1", 701: "ZZ1", 702: "AAA1"} {
+		if got := cellRef(col, 1); want != got {
+			t.Errorf("want '%s', got '%s'", want, got)
+		}
+
+		if got := cellColumn(want); col != got {
+			t.Errorf("want %d, got %d", col, got)
+		}
+	}
+}
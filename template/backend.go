@@ -0,0 +1,282 @@
+package template
+
+import (
+	"math"
+	"strings"
+	"time"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/number"
+)
+
+// NumberFormat is the resolved number format of :number, :integer, :percent and :range.
+type NumberFormat struct {
+	// Style is "decimal" or "percent", the percent value is a ratio, e.g. 0.45 is "45%".
+	Style string
+	// MinimumIntegerDigits is the minimum number of integer digits, padded with zeros.
+	MinimumIntegerDigits int
+	// MinimumFractionDigits is the minimum number of fraction digits, padded with zeros.
+	MinimumFractionDigits int
+	// MaximumFractionDigits is the maximum number of fraction digits, the value is rounded.
+	MaximumFractionDigits int
+	// MaximumSignificantDigits is the maximum number of significant digits, -1 if not limited.
+	MaximumSignificantDigits int
+	// Grouping reports whether the integer digits are grouped, e.g. "1,234".
+	Grouping bool
+}
+
+// NumberFormatter formats the numbers of the built-in functions, see [WithNumberFormatter].
+// The sign display and the selection are applied by the functions.
+type NumberFormatter interface {
+	FormatNumber(value float64, format NumberFormat, locale language.Tag) string
+}
+
+// DateTimeFormat is the resolved format of :datetime, :date and :time.
+type DateTimeFormat struct {
+	// DateStyle is the date style (full, long, medium, short), empty to omit the date.
+	DateStyle string
+	// TimeStyle is the time style (full, long, medium, short), empty to omit the time.
+	TimeStyle string
+	// HourCycle is the hour cycle (h11, h12, h23, h24).
+	HourCycle string
+	// Era is the representation of the era (long, short, narrow), empty to omit the era.
+	Era string
+	// TimeZoneName is the representation of the time zone name, empty to omit the name.
+	TimeZoneName string
+	// FractionalSecondDigits is the number of fractional seconds (0, 1, 2, 3).
+	FractionalSecondDigits int
+	// Skeleton is the CLDR date-time skeleton, e.g. "yMMMd Hm", the styles are empty if set.
+	Skeleton string
+}
+
+// DateTimeFormatter formats the dates and times of the built-in functions, see [WithDateTimeFormatter].
+// The value is in the time zone of the expression.
+type DateTimeFormatter interface {
+	FormatDateTime(value time.Time, format DateTimeFormat, locale language.Tag) string
+}
+
+// ListFormat is the resolved list format.
+type ListFormat struct {
+	// Type is "conjunction" ("a, b, and c") or "disjunction" ("a, b, or c").
+	Type string
+}
+
+// ListFormatter formats the lists of the built-in functions, e.g. a slice operand of :string,
+// see [WithListFormatter].
+type ListFormatter interface {
+	FormatList(items []string, format ListFormat, locale language.Tag) string
+}
+
+// XText is the default formatting backend, the numbers are formatted by golang.org/x/text.
+// Replace it with [WithNumberFormatter], [WithDateTimeFormatter] or [WithListFormatter],
+// e.g. to use ICU or custom CLDR data.
+type XText struct{}
+
+var (
+	_ NumberFormatter   = XText{}
+	_ DateTimeFormatter = XText{}
+	_ ListFormatter     = XText{}
+)
+
+// FormatNumber formats the number with golang.org/x/text/number.
+func (XText) FormatNumber(value float64, format NumberFormat, locale language.Tag) string {
+	return printer(locale).Sprint(xtextNumber(value, format))
+}
+
+// xtextNumber returns the x/text number formatter of the value.
+func xtextNumber(value float64, format NumberFormat) number.Formatter {
+	options := []number.Option{
+		number.MinFractionDigits(format.MinimumFractionDigits),
+		number.MaxFractionDigits(format.MaximumFractionDigits),
+		number.MinIntegerDigits(format.MinimumIntegerDigits),
+		number.Precision(format.MaximumSignificantDigits),
+	}
+
+	if !format.Grouping {
+		options = append(options, number.NoSeparator())
+	}
+
+	value = roundFraction(value, format)
+
+	if format.Style == "percent" {
+		return number.Percent(value, options...)
+	}
+
+	return number.Decimal(value, options...)
+}
+
+// roundFraction returns the value rounded half away from zero to the maximum fraction digits of the
+// formatted number. x/text rounds up across a power of ten wrongly, e.g. 0.96 to one digit is "0.1".
+func roundFraction(value float64, format NumberFormat) float64 {
+	if format.MaximumSignificantDigits > 0 || format.MaximumFractionDigits < 0 {
+		return value
+	}
+
+	digits := format.MaximumFractionDigits
+	if format.Style == "percent" {
+		digits += 2 // the ratio, e.g. 0.007 is 0.7%
+	}
+
+	scale := math.Pow10(digits)
+
+	// the large values have no fraction digits, the scaled value might overflow
+	if rounded := math.Round(value*scale) / scale; !math.IsInf(rounded, 0) && !math.IsNaN(rounded) {
+		return rounded
+	}
+
+	return value
+}
+
+// FormatDateTime formats the date and time with the Go layouts of the styles.
+func (XText) FormatDateTime(value time.Time, format DateTimeFormat, locale language.Tag) string {
+	if format.Skeleton != "" {
+		// the skeleton is validated by :datetime
+		pattern, _ := skeletonPattern(format.Skeleton, format.HourCycle, locale)
+
+		return formatPattern(value, pattern, locale)
+	}
+
+	var dateLayout, timeLayout string
+
+	switch format.DateStyle {
+	case "full":
+		dateLayout = "Monday, 02 January 2006"
+	case "long":
+		dateLayout = "02 January 2006"
+	case "medium":
+		dateLayout = "02 Jan 2006"
+	case "short":
+		dateLayout = "02/01/06"
+	}
+
+	switch format.TimeStyle {
+	case "full":
+		timeLayout = "15:04:05 MST"
+	case "long":
+		timeLayout = "15:04:05 -0700"
+	case "medium":
+		timeLayout = "15:04:05"
+	case "short":
+		timeLayout = "15:04"
+	}
+
+	var zone string
+
+	if format.TimeZoneName != "" {
+		timeLayout, _, _ = strings.Cut(timeLayout, " ")
+		zone = " " + timeZoneName(value, format.TimeZoneName, locale)
+	}
+
+	date := value.Format(dateLayout)
+	if format.Era != "" {
+		date = withEraYear(value).Format(dateLayout) + " " + eraName(value, format.Era, locale)
+	}
+
+	fraction := fractionalSeconds(value, format.FractionalSecondDigits, locale)
+
+	switch {
+	case timeLayout == "":
+		return date + zone
+	case dateLayout == "":
+		return formatClock(value, timeLayout, format.HourCycle, fraction) + zone
+	default:
+		return date + " " + formatClock(value, timeLayout, format.HourCycle, fraction) + zone
+	}
+}
+
+// listWords are the words joining the last two list items by language and list type, the default is English.
+var listWords = map[string][2]string{
+	"de": {"und", "oder"},
+	"en": {"and", "or"},
+	"es": {"y", "o"},
+	"fr": {"et", "ou"},
+	"lv": {"un", "vai"},
+}
+
+// FormatList joins the items with commas and the word of the list type, e.g. "a, b, and c" in English
+// and "a, b und c" in German.
+func (XText) FormatList(items []string, format ListFormat, locale language.Tag) string {
+	base, _ := locale.Base()
+
+	words, ok := listWords[base.String()]
+	if !ok {
+		words = listWords["en"]
+	}
+
+	word := words[0]
+	if format.Type == "disjunction" {
+		word = words[1]
+	}
+
+	switch n := len(items); n {
+	case 0:
+		return ""
+	case 1:
+		return items[0]
+	case 2: //nolint:mnd
+		return items[0] + " " + word + " " + items[1]
+	default:
+		last := " " + word + " " + items[n-1]
+
+		// the serial comma
+		if base.String() == "en" {
+			last = "," + last
+		}
+
+		return strings.Join(items[:n-1], ", ") + last
+	}
+}
+
+// WithNumberFormatter sets the number formatting backend of :number, :integer, :percent, :range and :count.
+// The default is [XText].
+//
+// The option replaces the functions, the functions of [WithFuncs] set after it win.
+func WithNumberFormatter(formatter NumberFormatter) Option {
+	number := func(operand *ResolvedValue, options Options, locale language.Tag) (*ResolvedValue, error) {
+		return formatNumber(operand, options, locale, formatter)
+	}
+
+	return func(t *Template) {
+		t.setFunc("number", number)
+		t.setFunc("integer", func(operand *ResolvedValue, options Options, locale language.Tag) (*ResolvedValue, error) {
+			return formatInteger(operand, options, locale, number)
+		})
+		t.setFunc("percent", func(operand *ResolvedValue, options Options, locale language.Tag) (*ResolvedValue, error) {
+			return formatPercent(operand, options, locale, number)
+		})
+		t.setFunc("range", func(operand *ResolvedValue, options Options, locale language.Tag) (*ResolvedValue, error) {
+			return formatRange(operand, options, locale, number)
+		})
+		t.setFunc("count", func(operand *ResolvedValue, options Options, locale language.Tag) (*ResolvedValue, error) {
+			return formatCount(operand, options, locale, number)
+		})
+	}
+}
+
+// WithDateTimeFormatter sets the date and time formatting backend of :datetime, :date and :time.
+// The default is [XText].
+//
+// The option replaces the functions, the functions of [WithFuncs] set after it win.
+func WithDateTimeFormatter(formatter DateTimeFormatter) Option {
+	return func(t *Template) {
+		t.datetimeFormatter = formatter
+		t.setFunc("datetime", t.datetimeFunc())
+		t.setFunc("date", func(operand *ResolvedValue, options Options, locale language.Tag) (*ResolvedValue, error) {
+			return formatDate(operand, options, locale, formatter)
+		})
+		t.setFunc("time", func(operand *ResolvedValue, options Options, locale language.Tag) (*ResolvedValue, error) {
+			return formatTime(operand, options, locale, formatter)
+		})
+	}
+}
+
+// WithListFormatter sets the list formatting backend of :string. The default is [XText].
+//
+// The option replaces the function, the functions of [WithFuncs] set after it win.
+func WithListFormatter(formatter ListFormatter) Option {
+	return func(t *Template) {
+		t.setFunc("string", func(operand *ResolvedValue, options Options, locale language.Tag) (*ResolvedValue, error) {
+			return formatString(operand, options, locale, formatter)
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package template
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/text/language"
+)
+
+type testBackend struct{}
+
+func (testBackend) FormatNumber(value float64, format NumberFormat, locale language.Tag) string {
+	return fmt.Sprintf("%s:%s:%v:%d", locale, format.Style, value, format.MaximumFractionDigits)
+}
+
+func (testBackend) FormatDateTime(value time.Time, format DateTimeFormat, locale language.Tag) string {
+	return fmt.Sprintf("%s:%s:%s:%s", locale, format.DateStyle, format.TimeStyle, value.Format(time.DateOnly))
+}
+
+func (testBackend) FormatList(items []string, format ListFormat, locale language.Tag) string {
+	return fmt.Sprintf("%s:%s:%s", locale, format.Type, strings.Join(items, "+"))
+}
+
+func TestBackend(t *testing.T) {
+	t.Parallel()
+
+	input := map[string]any{
+		"n":     1.5,
+		"d":     time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC),
+		"items": []string{"a", "b"},
+	}
+
+	for _, test := range []struct {
+		in, want string
+		options  []Option
+	}{
+		{in: "{ $n :number }", want: "1,5"},
+		{in: "{ $n :number }", options: []Option{WithNumberFormatter(testBackend{})}, want: "lv:decimal:1.5:3"},
+		{in: "{ $n :integer }", options: []Option{WithNumberFormatter(testBackend{})}, want: "lv:decimal:1.5:0"},
+		{in: "{ $n :percent }", options: []Option{WithNumberFormatter(testBackend{})}, want: "lv:percent:0.015:0"},
+		{in: "{ $d :date }", options: []Option{WithDateTimeFormatter(testBackend{})}, want: "lv:short::2021-01-02"},
+		{in: "{ $d :time }", options: []Option{WithDateTimeFormatter(testBackend{})}, want: "lv::short:2021-01-02"},
+		{
+			in:      "{ $d :datetime }",
+			options: []Option{WithDateTimeDefaults("long", ""), WithDateTimeFormatter(testBackend{})},
+			want:    "lv:long::2021-01-02",
+		},
+		{
+			in:      "{ $d :datetime }",
+			options: []Option{WithDateTimeFormatter(testBackend{}), WithDateTimeDefaults("", "full")},
+			want:    "lv::full:2021-01-02",
+		},
+		{in: "{ $items :string }", want: "a un b"},
+		{in: "{ $items :string }", options: []Option{WithListFormatter(testBackend{})}, want: "lv:conjunction:a+b"},
+	} {
+		t.Run(test.in, func(t *testing.T) {
+			t.Parallel()
+
+			options := append([]Option{WithLocale(language.Latvian)}, test.options...)
+
+			got, err := MustParse(test.in, options...).Sprint(input)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if test.want != got {
+				t.Errorf("want '%s', got '%s'", test.want, got)
+			}
+		})
+	}
+}
+
+func TestXTextFormatList(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		locale language.Tag
+		format ListFormat
+		want   string
+		items  []string
+	}{
+		{locale: language.English, want: ""},
+		{locale: language.English, items: []string{"a"}, want: "a"},
+		{locale: language.English, items: []string{"a", "b"}, want: "a and b"},
+		{locale: language.English, items: []string{"a", "b", "c"}, want: "a, b, and c"},
+		{locale: language.English, format: ListFormat{Type: "disjunction"}, items: []string{"a", "b", "c"}, want: "a, b, or c"},
+		{locale: language.German, items: []string{"a", "b", "c"}, want: "a, b und c"},
+		{locale: language.Japanese, items: []string{"a", "b"}, want: "a and b"},
+	} {
+		if got := (XText{}).FormatList(test.items, test.format, test.locale); test.want != got {
+			t.Errorf("want '%s', got '%s'", test.want, got)
+		}
+	}
+}
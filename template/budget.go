@@ -0,0 +1,81 @@
+package template
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"golang.org/x/text/language"
+)
+
+// ErrFuncBudget is returned when the function does not return within its time budget, see [WithFuncBudget].
+var ErrFuncBudget = errors.New("function time budget exceeded")
+
+// WithFuncBudget limits the time of the function calls, e.g. the custom functions calling remote services.
+// The budget applies to the named functions, or to all functions without names. The expression
+// of the function not returning in time resolves to the fallback representation with [ErrFuncBudget].
+// The execution context, see [Template.ExecuteContext], cancels the budgeted calls too.
+//
+// The function keeps running in its goroutine after the budget expires, its result is discarded.
+// The budget covers the call, not the formatting of the returned value.
+func WithFuncBudget(budget time.Duration, names ...string) Option {
+	return func(t *Template) {
+		if len(names) == 0 {
+			t.funcBudget = budget
+			return
+		}
+
+		if t.funcBudgets == nil {
+			t.funcBudgets = make(map[string]time.Duration, len(names))
+		}
+
+		for _, name := range names {
+			t.funcBudgets[name] = budget
+		}
+	}
+}
+
+// budget returns the time budget of the function, zero if not limited.
+func (t *Template) budget(name string) time.Duration {
+	if budget, ok := t.funcBudgets[name]; ok {
+		return budget
+	}
+
+	return t.funcBudget
+}
+
+// callBudget calls the function within the time budget.
+func (e *executer) callBudget(
+	f Func,
+	operand *ResolvedValue,
+	options Options,
+	locale language.Tag,
+	budget time.Duration,
+) (*ResolvedValue, error) {
+	if err := e.ctx.Err(); err != nil {
+		return nil, fmt.Errorf("%w %s: %w", ErrFuncBudget, budget, err)
+	}
+
+	ctx, cancel := context.WithTimeout(e.ctx, budget)
+	defer cancel()
+
+	type result struct {
+		value *ResolvedValue
+		err   error
+	}
+
+	done := make(chan result, 1) // the abandoned call must not block
+
+	go func() {
+		value, err := f(operand, options, locale)
+		done <- result{value: value, err: err}
+	}()
+
+	select {
+	case r := <-done:
+		return r.value, r.err
+	case <-ctx.Done():
+		return nil, fmt.Errorf("%w %s: %w", ErrFuncBudget, budget, ctx.Err())
+	}
+}
@@ -0,0 +1,85 @@
+package template
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"golang.org/x/text/language"
+)
+
+func TestFuncBudget(t *testing.T) {
+	t.Parallel()
+
+	slow := func(operand *ResolvedValue, _ Options, _ language.Tag) (*ResolvedValue, error) {
+		time.Sleep(time.Second)
+		return operand, nil
+	}
+
+	for _, test := range []struct {
+		ctx     context.Context //nolint:containedctx
+		wantErr error
+		name    string
+		want    string
+		options []Option
+	}{
+		{
+			name:    "named",
+			options: []Option{WithFuncBudget(time.Millisecond, "slow")},
+			want:    "{$x} b",
+			wantErr: ErrFuncBudget,
+		},
+		{
+			name:    "all",
+			options: []Option{WithFuncBudget(time.Millisecond)},
+			want:    "{$x} b",
+			wantErr: ErrFuncBudget,
+		},
+		{
+			name:    "other",
+			options: []Option{WithFuncBudget(time.Hour), WithFuncBudget(time.Millisecond, "slow")},
+			want:    "{$x} b",
+			wantErr: ErrFuncBudget,
+		},
+		{
+			name:    "context",
+			ctx:     canceledContext(),
+			options: []Option{WithFuncBudget(time.Hour)},
+			want:    "{$x} {$y}",
+			wantErr: context.Canceled,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			options := append([]Option{WithFunc("slow", slow)}, test.options...)
+
+			tmpl, err := New(options...).Parse("{ $x :slow } { $y :string }")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			ctx := test.ctx
+			if ctx == nil {
+				ctx = context.Background()
+			}
+
+			got, err := tmpl.SprintContext(ctx, map[string]any{"x": "a", "y": "b"})
+			if !errors.Is(err, test.wantErr) {
+				t.Errorf("want '%v', got '%v'", test.wantErr, err)
+			}
+
+			if test.want != got {
+				t.Errorf("want '%s', got '%s'", test.want, got)
+			}
+		})
+	}
+}
+
+func canceledContext() context.Context {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	return ctx
+}
@@ -0,0 +1,72 @@
+package template
+
+import "context"
+
+// Values are request-scoped defaults of the registry function options, e.g. the user's
+// "timeZone" for :datetime or "currency" for :number. The message options take precedence.
+//
+// A function receives only the values of the options it declares: the options of the default
+// registry functions, e.g. "timeZone" for :datetime but not for :number, and the options of the
+// custom functions declared by [WithFuncOptions]. The functions without options, e.g. :string,
+// receive none.
+type Values map[string]any
+
+// builtinOptions are the options of the default registry functions the [Values] apply to.
+// The options not implemented by the function are not declared, e.g. "numberingSystem" of :datetime.
+var builtinOptions = map[string][]string{
+	"casing":   {"style"},
+	"count":    numberOptionNames,
+	"date":     {"style", "timeZone"},
+	"datetime": {"dateStyle", "timeStyle", "era", "fractionalSecondDigits", "timeZoneName", "timeZone", "hourCycle", "hour12"},
+	"integer":  numberOptionNames,
+	"number":   numberOptionNames,
+	"percent":  percentOptionNames,
+	"range":    numberOptionNames,
+	"time":     {"style", "timeZone", "hourCycle", "hour12"},
+}
+
+// WithFuncOptions declares the options of the custom function the request-scoped [Values] apply to,
+// e.g. WithFuncOptions("unit", "measurementSystem").
+func WithFuncOptions(name string, options ...string) Option {
+	return func(t *Template) {
+		if t.funcOptions == nil {
+			t.funcOptions = make(map[string][]string)
+		}
+
+		t.funcOptions[name] = options
+	}
+}
+
+// declaredOptions returns the options of the function the request-scoped [Values] apply to.
+func (t *Template) declaredOptions(name string) []string {
+	if options, ok := t.funcOptions[name]; ok {
+		return options
+	}
+
+	return builtinOptions[name]
+}
+
+type valuesKey struct{}
+
+// WithValues returns a copy of ctx carrying the values, see [Template.ExecuteContext].
+// The values are merged with the values already in ctx, the given ones take precedence.
+func WithValues(ctx context.Context, values Values) context.Context {
+	merged := make(Values, len(values))
+
+	for k, v := range ValuesFromContext(ctx) {
+		merged[k] = v
+	}
+
+	for k, v := range values {
+		merged[k] = v
+	}
+
+	return context.WithValue(ctx, valuesKey{}, merged)
+}
+
+// ValuesFromContext returns the values stored in ctx by [WithValues], or nil.
+func ValuesFromContext(ctx context.Context) Values {
+	values, _ := ctx.Value(valuesKey{}).(Values)
+
+	return values
+}
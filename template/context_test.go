@@ -0,0 +1,58 @@
+package template
+
+import (
+	"context"
+	"testing"
+
+	"golang.org/x/text/language"
+)
+
+func TestExecuteContext(t *testing.T) {
+	t.Parallel()
+
+	ctx := WithValues(context.Background(), Values{"timeZone": "UTC", "measurementSystem": "metric", "style": "decimal"})
+	ctx = WithValues(ctx, Values{"timeZone": "EET"})
+
+	system := func(_ *ResolvedValue, options Options, _ language.Tag) (*ResolvedValue, error) {
+		v, err := options.GetString("measurementSystem", "")
+		if err != nil {
+			return nil, err
+		}
+
+		return NewResolvedValue(v), nil
+	}
+
+	for _, test := range []struct {
+		name, in, want string
+	}{
+		{name: "value", in: "{ $d :datetime timeStyle=long }", want: "05:04:05 +0200"},
+		{name: "option wins", in: "{ $d :datetime timeStyle=long timeZone=UTC }", want: "03:04:05 +0000"},
+		{name: "custom function", in: "{ :system }", want: "metric"},
+		{name: "undeclared options", in: "{ :undeclared }", want: ""},
+		{name: "no options", in: "{ |text| :string }", want: "text"},
+		{name: "other options", in: "{ 1 :number }", want: "1"},
+		{name: "other function options", in: "{ 45 :percent } { 1 :boolean }", want: "45% true"},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			template, err := New(
+				WithFunc("system", system),
+				WithFunc("undeclared", system),
+				WithFuncOptions("system", "measurementSystem"),
+			).Parse(test.in)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := template.SprintContext(ctx, map[string]any{"d": testDate})
+			if err != nil {
+				t.Error(err)
+			}
+
+			if test.want != got {
+				t.Errorf("want '%s', got '%s'", test.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package template
+
+import (
+	"strconv"
+	"time"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/number"
+)
+
+// WithoutDurationFormatting formats [time.Duration] operands with Go's default format, e.g. "1h5m0s",
+// instead of the localized "1:05:00".
+func WithoutDurationFormatting() Option {
+	return func(t *Template) {
+		t.rawDurations = true
+	}
+}
+
+// formatDuration formats the duration as hours, minutes and seconds, e.g. "1:05:00" for 1h5m.
+// Hours are not wrapped at 24 and fractional seconds are formatted with the locale's decimal separator,
+// e.g. "0:00:01,5" for 1.5s in "lv".
+func formatDuration(d time.Duration, locale language.Tag) string {
+	var sign string
+
+	if d < 0 {
+		sign = "-"
+		d = -d
+	}
+
+	hours := d / time.Hour
+	minutes := d % time.Hour / time.Minute
+	seconds := (d % time.Minute).Seconds()
+
+	s := sign + strconv.FormatInt(int64(hours), 10) + ":"
+
+	if minutes < 10 { //nolint:mnd
+		s += "0"
+	}
+
+	s += strconv.FormatInt(int64(minutes), 10) + ":"
+
+	return s + printer(locale).Sprint(number.Decimal(seconds,
+		number.MinIntegerDigits(2), number.MaxFractionDigits(9), number.NoSeparator())) //nolint:mnd
+}
@@ -0,0 +1,49 @@
+package template
+
+import (
+	"testing"
+	"time"
+
+	"golang.org/x/text/language"
+)
+
+func TestDuration(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		locale  language.Tag
+		in      string
+		want    string
+		options []Option
+		input   time.Duration
+	}{
+		{in: "{ $d }", input: time.Hour + 5*time.Minute, want: "1:05:00"},
+		{in: "{ $d :string }", input: 90 * time.Second, want: "0:01:30"},
+		{in: "{ $d }", input: 26*time.Hour + 1500*time.Millisecond, want: "26:00:01.5"},
+		{in: "{ $d }", input: -1500 * time.Millisecond, locale: language.Latvian, want: "-0:00:01,5"},
+		{in: "{ $d }", input: time.Hour + 5*time.Minute, options: []Option{WithoutDurationFormatting()}, want: "1h5m0s"},
+	} {
+		t.Run(test.want, func(t *testing.T) {
+			t.Parallel()
+
+			locale := test.locale
+			if locale == language.Und {
+				locale = language.English
+			}
+
+			template, err := New(append(test.options, WithLocale(locale))...).Parse(test.in)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := template.Sprint(map[string]any{"d": test.input})
+			if err != nil {
+				t.Error(err)
+			}
+
+			if test.want != got {
+				t.Errorf("want '%s', got '%s'", test.want, got)
+			}
+		})
+	}
+}
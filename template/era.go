@@ -0,0 +1,103 @@
+package template
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/number"
+)
+
+// eraNames are the Gregorian era names, BC and AD, by width. A subset of CLDR, x/text does not provide them.
+type eraNames struct {
+	long, short, narrow [2]string
+}
+
+var eras = map[string]eraNames{
+	"en": {
+		long:   [2]string{"Before Christ", "Anno Domini"},
+		short:  [2]string{"BC", "AD"},
+		narrow: [2]string{"B", "A"},
+	},
+	"de": {
+		long:   [2]string{"v. Chr.", "n. Chr."},
+		short:  [2]string{"v. Chr.", "n. Chr."},
+		narrow: [2]string{"v. Chr.", "n. Chr."},
+	},
+	"es": {
+		long:   [2]string{"antes de Cristo", "después de Cristo"},
+		short:  [2]string{"a. C.", "d. C."},
+		narrow: [2]string{"a. C.", "d. C."},
+	},
+	"fr": {
+		long:   [2]string{"avant Jésus-Christ", "après Jésus-Christ"},
+		short:  [2]string{"av. J.-C.", "ap. J.-C."},
+		narrow: [2]string{"av. J.-C.", "ap. J.-C."},
+	},
+	"lv": {
+		long:   [2]string{"pirms mūsu ēras", "mūsu ērā"},
+		short:  [2]string{"p.m.ē.", "m.ē."},
+		narrow: [2]string{"p.m.ē.", "m.ē."},
+	},
+	"ru": {
+		long:   [2]string{"до Рождества Христова", "от Рождества Христова"},
+		short:  [2]string{"до н. э.", "н. э."},
+		narrow: [2]string{"до н.э.", "н.э."},
+	},
+}
+
+// localeEras returns the era names of the locale, false if the locale has none.
+func localeEras(locale language.Tag) (eraNames, bool) {
+	base, _ := locale.Base()
+	names, ok := eras[base.String()]
+
+	return names, ok
+}
+
+// eraName returns the locale's name of the era of the time, empty if the locale has no era names.
+func eraName(t time.Time, width string, locale language.Tag) string {
+	names, ok := localeEras(locale)
+	if !ok {
+		return ""
+	}
+
+	var era int // BC
+	if t.Year() > 0 {
+		era = 1 // AD
+	}
+
+	switch width {
+	case "long":
+		return names.long[era]
+	case "narrow":
+		return names.narrow[era]
+	default:
+		return names.short[era]
+	}
+}
+
+// withEraYear returns the time with the year of the era, e.g. 1 for 1 BC, the year 0 in Go.
+func withEraYear(t time.Time) time.Time {
+	if y := t.Year(); y <= 0 {
+		return t.AddDate(1-2*y, 0, 0)
+	}
+
+	return t
+}
+
+// fractionalSeconds returns the fraction of the seconds truncated to the digits as in ECMA-402,
+// with the locale's decimal separator, e.g. ",12" for 5.129s in "lv". It is empty for 0 digits.
+func fractionalSeconds(t time.Time, digits int, locale language.Tag) string {
+	if digits == 0 {
+		return ""
+	}
+
+	// the separator of "0.5", x/text does not provide it separately
+	half := printer(locale).Sprint(number.Decimal(0.5)) //nolint:mnd
+	separator := strings.TrimSuffix(strings.TrimPrefix(half, "0"), "5")
+
+	ns := strconv.Itoa(t.Nanosecond() + 1e9)[1:] // zero padded to 9 digits
+
+	return separator + ns[:digits]
+}
@@ -0,0 +1,202 @@
+package template
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"golang.org/x/text/language"
+)
+
+// ErrMaxOutput is returned when the formatted message exceeds the limit, see [WithMaxOutput].
+var ErrMaxOutput = errors.New("max output exceeded")
+
+// ErrorPolicy defines the result of the execution when the message fails to resolve.
+type ErrorPolicy int
+
+const (
+	// ReportErrors returns the output with the failed expressions in the fallback representation
+	// together with the error. It is the default.
+	ReportErrors ErrorPolicy = iota
+	// IgnoreErrors returns the output with the failed expressions in the fallback representation,
+	// the resolution errors are not returned. The errors are still logged, see [WithLogger].
+	IgnoreErrors
+	// StrictErrors returns only the error, nothing is written on the resolution error.
+	StrictErrors
+)
+
+// MarkupRenderer returns the string of the markup placeholder when the template is formatted to a string,
+// e.g. "<a href=...>" for the open "link" markup. Without the renderer the markup formats to an empty string.
+type MarkupRenderer func(markup *MarkupPart) string
+
+// VariableResolver returns the value of the variable missing in the input, false if there is no such variable.
+type VariableResolver func(name string) (any, bool)
+
+// executeOptions are the options of a single execution.
+type executeOptions struct {
+	funcs       Registry
+	markup      MarkupRenderer
+	resolver    VariableResolver
+	locale      *language.Tag
+	errorPolicy ErrorPolicy
+	maxOutput   int
+}
+
+// ExecuteOption is an option of a single execution, e.g. [Template.Execute].
+// It customizes the execution without cloning the template.
+type ExecuteOption func(o *executeOptions)
+
+// InLocale formats the message in the locale instead of the template locale.
+func InLocale(locale language.Tag) ExecuteOption {
+	return func(o *executeOptions) {
+		o.locale = &locale
+	}
+}
+
+// WithErrorPolicy sets the result of the execution on the resolution errors, see [ErrorPolicy].
+func WithErrorPolicy(policy ErrorPolicy) ExecuteOption {
+	return func(o *executeOptions) {
+		o.errorPolicy = policy
+	}
+}
+
+// WithExtraFuncs adds the functions to the template registry for the execution,
+// the functions replace the registered functions of the same name.
+func WithExtraFuncs(reg Registry) ExecuteOption {
+	return func(o *executeOptions) {
+		if o.funcs == nil {
+			o.funcs = make(Registry, len(reg))
+		}
+
+		for k, f := range reg {
+			o.funcs[k] = f
+		}
+	}
+}
+
+// WithMarkupRenderer renders the markup placeholders when formatting to a string, see [MarkupRenderer].
+func WithMarkupRenderer(renderer MarkupRenderer) ExecuteOption {
+	return func(o *executeOptions) {
+		o.markup = renderer
+	}
+}
+
+// WithVariableResolver resolves the variables missing in the input, e.g. to fetch the values lazily
+// from the request context or a database. The resolver is called at most once per variable and execution.
+func WithVariableResolver(resolver VariableResolver) ExecuteOption {
+	return func(o *executeOptions) {
+		o.resolver = resolver
+	}
+}
+
+// WithMaxOutput limits the size of the formatted message in bytes, e.g. to render the messages
+// of untrusted authors. The execution aborts with [ErrMaxOutput] and writes nothing when the limit
+// would be exceeded, regardless of the [ErrorPolicy]. Zero or less is no limit, the default.
+//
+// The limit applies to [Template.Execute] and [Template.Sprint], not to [Template.FormatToParts].
+func WithMaxOutput(bytes int) ExecuteOption {
+	return func(o *executeOptions) {
+		o.maxOutput = bytes
+	}
+}
+
+// limitWriter writes at most n bytes, the write exceeding the limit is rejected with [ErrMaxOutput].
+type limitWriter struct {
+	w io.Writer
+	n int
+}
+
+func (l *limitWriter) Write(p []byte) (int, error) {
+	if len(p) > l.n {
+		return 0, fmt.Errorf("%w: %d bytes", ErrMaxOutput, l.n)
+	}
+
+	n, err := l.w.Write(p)
+	l.n -= n
+
+	return n, err //nolint:wrapcheck
+}
+
+// apply sets the execution options of the executer.
+func (e *executer) apply(options []ExecuteOption) {
+	var o executeOptions
+
+	for _, f := range options {
+		f(&o)
+	}
+
+	e.locale = e.template.locale
+	if o.locale != nil {
+		e.locale = *o.locale
+	}
+
+	e.registry = e.template.funcs
+
+	if len(o.funcs) > 0 {
+		e.registry = make(Registry, len(e.template.funcs)+len(o.funcs))
+
+		for k, f := range e.template.funcs {
+			e.registry[k] = f
+		}
+
+		for k, f := range o.funcs {
+			e.registry[k] = f
+		}
+	}
+
+	e.markup = o.markup
+	e.resolver = o.resolver
+	e.errorPolicy = o.errorPolicy
+
+	if o.maxOutput > 0 {
+		e.w = &limitWriter{w: e.w, n: o.maxOutput}
+	}
+}
+
+// result returns the resolution error according to the error policy.
+// Exceeding the output limit is always an error, see [WithMaxOutput].
+func (e *executer) result(err error) error {
+	if e.errorPolicy == IgnoreErrors && !errors.Is(err, ErrMaxOutput) {
+		return nil
+	}
+
+	return err
+}
+
+// resolveMissing resolves the variable missing in the input with the dotted path, see [WithDottedPaths],
+// the [VariableResolver], or the default value, see [WithDefaults]. The resolved value is cached in the variables.
+func (e *executer) resolveMissing(name string) (*ResolvedValue, bool, error) {
+	if r, ok, err := e.resolvePath(name); ok || err != nil {
+		return r, ok, err
+	}
+
+	var (
+		value any
+		ok    bool
+	)
+
+	if e.resolver != nil && !e.unresolved[name] {
+		if value, ok = e.resolver(name); !ok {
+			if e.unresolved == nil {
+				e.unresolved = make(map[string]bool)
+			}
+
+			e.unresolved[name] = true
+		}
+	}
+
+	if !ok {
+		if value, ok = e.template.defaults[name]; !ok {
+			return nil, false, nil
+		}
+	}
+
+	r, err := e.newInput(value)
+	if err != nil {
+		return nil, true, fmt.Errorf(`resolve "%s": %w`, name, err)
+	}
+
+	e.variables[name] = r
+
+	return r, true, nil
+}
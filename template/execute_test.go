@@ -0,0 +1,184 @@
+package template
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"golang.org/x/text/language"
+
+	"go.expect.digital/mf2"
+)
+
+func TestExecuteOptions(t *testing.T) {
+	t.Parallel()
+
+	tmpl, err := New(WithLocale(language.English)).Parse("{#b}{ $n :number }{/b} { $x :shout }")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	shout := func(operand *ResolvedValue, _ Options, _ language.Tag) (*ResolvedValue, error) {
+		return NewResolvedValue(strings.ToUpper(operand.String())), nil
+	}
+
+	markup := func(part *MarkupPart) string {
+		if part.Kind == "close" {
+			return "</" + part.Name + ">"
+		}
+
+		return "<" + part.Name + ">"
+	}
+
+	input := map[string]any{"n": 1000.5, "x": "hi"}
+
+	for _, test := range []struct {
+		wantErr error
+		name    string
+		want    string
+		options []ExecuteOption
+	}{
+		{name: "default", want: "1,000.5 {$x}", wantErr: mf2.ErrUnknownFunction},
+		{
+			name:    "locale",
+			options: []ExecuteOption{InLocale(language.Latvian)},
+			want:    "1\u00a0000,5 {$x}",
+			wantErr: mf2.ErrUnknownFunction,
+		},
+		{name: "funcs", options: []ExecuteOption{WithExtraFuncs(Registry{"shout": shout})}, want: "1,000.5 HI"},
+		{name: "ignore errors", options: []ExecuteOption{WithErrorPolicy(IgnoreErrors)}, want: "1,000.5 {$x}"},
+		{name: "strict errors", options: []ExecuteOption{WithErrorPolicy(StrictErrors)}, wantErr: mf2.ErrUnknownFunction},
+		{
+			name:    "markup",
+			options: []ExecuteOption{WithMarkupRenderer(markup), WithExtraFuncs(Registry{"shout": shout})},
+			want:    "<b>1,000.5</b> HI",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := tmpl.Sprint(input, test.options...)
+			if !errors.Is(err, test.wantErr) {
+				t.Errorf("want '%v', got '%v'", test.wantErr, err)
+			}
+
+			if test.want != got {
+				t.Errorf("want '%s', got '%s'", test.want, got)
+			}
+		})
+	}
+
+	// the template is not modified
+	if got, _ := tmpl.Sprint(input); got != "1,000.5 {$x}" {
+		t.Errorf("want '1,000.5 {$x}', got '%s'", got)
+	}
+
+	if parts, err := tmpl.FormatToParts(input, WithErrorPolicy(StrictErrors)); err == nil || parts != nil {
+		t.Errorf("want error and no parts, got %v (%v)", parts, err)
+	}
+}
+
+func TestVariableResolver(t *testing.T) {
+	t.Parallel()
+
+	tmpl, err := New().Parse(".input { $count :integer } {{{ $name } has { $count } items, { $name } { $missing } { $missing }}}")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var calls []string
+
+	resolver := func(name string) (any, bool) {
+		calls = append(calls, name)
+
+		switch name {
+		case "name":
+			return "Jānis", true
+		case "count":
+			return 1000, true
+		}
+
+		return nil, false
+	}
+
+	got, err := tmpl.Sprint(map[string]any{"name": "input"}, WithVariableResolver(resolver))
+	if !errors.Is(err, mf2.ErrUnresolvedVariable) {
+		t.Errorf("want '%s', got '%v'", mf2.ErrUnresolvedVariable, err)
+	}
+
+	if want := "input has 1,000 items, input {$missing} {$missing}"; want != got {
+		t.Errorf("want '%s', got '%s'", want, got)
+	}
+
+	// the input wins, the resolved and unresolved variables are not resolved again
+	if want := []string{"count", "missing"}; !slices.Equal(want, calls) {
+		t.Errorf("want %v, got %v", want, calls)
+	}
+}
+
+func TestDefaults(t *testing.T) {
+	t.Parallel()
+
+	tmpl, err := New(WithDefaults(map[string]any{"name": "guest", "count": 0})).
+		Parse(".match { $count :number } 0 {{Hello, { $name }!}} * {{Hello, { $name }, { $count } new messages!}}")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		input   map[string]any
+		options []ExecuteOption
+		want    string
+	}{
+		{want: "Hello, guest!"},
+		{input: map[string]any{"name": "Jānis", "count": 3}, want: "Hello, Jānis, 3 new messages!"},
+		{
+			options: []ExecuteOption{WithVariableResolver(func(name string) (any, bool) { return "Anna", name == "name" })},
+			want:    "Hello, Anna!",
+		},
+	} {
+		got, err := tmpl.Sprint(test.input, test.options...)
+		if err != nil {
+			t.Error(err)
+		}
+
+		if test.want != got {
+			t.Errorf("want '%s', got '%s'", test.want, got)
+		}
+	}
+}
+
+func TestMaxOutput(t *testing.T) {
+	t.Parallel()
+
+	tmpl, err := New().Parse(".match { $n :number } one {{{ $s }!}} * {{{ $s } { $s }!}}")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		input   map[string]any
+		wantErr error
+		want    string
+		options []ExecuteOption
+	}{
+		{input: map[string]any{"n": 1, "s": "abc"}, options: []ExecuteOption{WithMaxOutput(4)}, want: "abc!"},
+		{input: map[string]any{"n": 2, "s": "abc"}, options: []ExecuteOption{WithMaxOutput(4)}, wantErr: ErrMaxOutput},
+		{
+			input:   map[string]any{"n": 2, "s": strings.Repeat("a", 100)},
+			options: []ExecuteOption{WithMaxOutput(10), WithErrorPolicy(IgnoreErrors)},
+			wantErr: ErrMaxOutput,
+		},
+		{input: map[string]any{"n": 2, "s": "abc"}, options: []ExecuteOption{WithMaxOutput(0)}, want: "abc abc!"},
+	} {
+		got, err := tmpl.Sprint(test.input, test.options...)
+		if !errors.Is(err, test.wantErr) {
+			t.Errorf("want '%v', got '%v'", test.wantErr, err)
+		}
+
+		if test.want != got {
+			t.Errorf("want '%s', got '%s'", test.want, got)
+		}
+	}
+}
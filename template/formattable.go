@@ -0,0 +1,25 @@
+package template
+
+import (
+	"fmt"
+	"maps"
+
+	"go.expect.digital/mf2"
+)
+
+// formatFormattable formats the operand of the placeholder without a function with the execution locale,
+// the request-scoped values are the options, see [WithValues].
+func (e *executer) formatFormattable(f mf2.Formattable) (*ResolvedValue, error) {
+	var options map[string]any
+
+	if len(e.values) > 0 {
+		options = maps.Clone(map[string]any(e.values))
+	}
+
+	s, err := f.FormatMF2(e.locale, options)
+	if err != nil {
+		return nil, fmt.Errorf("format %T: %w: %w", f, mf2.ErrBadOperand, err)
+	}
+
+	return NewResolvedValue(f, WithFormat(func() string { return s })), nil
+}
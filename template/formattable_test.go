@@ -0,0 +1,77 @@
+package template
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"golang.org/x/text/language"
+
+	"go.expect.digital/mf2"
+)
+
+// testTemperature is the Formattable operand, the options "unit" and "fail" are used by the tests.
+type testTemperature float64
+
+func (c testTemperature) FormatMF2(locale language.Tag, options map[string]any) (string, error) {
+	if options["fail"] != nil {
+		return "", errors.New("failed")
+	}
+
+	if options["unit"] == "fahrenheit" {
+		return fmt.Sprintf("%.0f°F", float64(c)*9/5+32), nil //nolint:mnd
+	}
+
+	if locale == language.Latvian {
+		return fmt.Sprintf("%.0f °C", float64(c)), nil
+	}
+
+	return fmt.Sprintf("%.0f°C", float64(c)), nil
+}
+
+func TestFormattable(t *testing.T) {
+	t.Parallel()
+
+	var _ mf2.Formattable = testTemperature(0)
+
+	for _, test := range []struct {
+		values  Values
+		locale  language.Tag
+		name    string
+		in      string
+		want    string
+		wantErr error
+	}{
+		{name: "format", in: "It is {$t}.", want: "It is 20°C."},
+		{name: "locale", in: "It is {$t}.", locale: language.Latvian, want: "It is 20 °C."},
+		{name: "options", in: "It is {$t}.", values: Values{"unit": "fahrenheit"}, want: "It is 68°F."},
+		{name: "local", in: ".local $x = {$t} {{It is {$x}.}}", want: "It is 20°C."},
+		{name: "error", in: "It is {$t}.", values: Values{"fail": true}, want: "It is {$t}.", wantErr: mf2.ErrBadOperand},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			locale := test.locale
+			if locale == language.Und {
+				locale = language.English
+			}
+
+			template, err := New(WithLocale(locale)).Parse(test.in)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			ctx := WithValues(context.Background(), test.values)
+
+			got, err := template.SprintContext(ctx, map[string]any{"t": testTemperature(20)})
+			if !errors.Is(err, test.wantErr) {
+				t.Errorf("want error '%v', got '%v'", test.wantErr, err)
+			}
+
+			if test.want != got {
+				t.Errorf("want '%s', got '%s'", test.want, got)
+			}
+		})
+	}
+}
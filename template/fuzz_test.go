@@ -0,0 +1,91 @@
+package template
+
+import (
+	"sort"
+	"strings"
+	"testing"
+
+	"golang.org/x/text/language"
+)
+
+// FuzzExecute tests that the functions of the default registry do not panic
+// with any operand and option values.
+func FuzzExecute(f *testing.F) {
+	registry := NewRegistry()
+
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	options := []string{
+		"select", "style", "signDisplay", "useGrouping", "notation", "compactDisplay", "numberingSystem",
+		"minimumIntegerDigits", "minimumFractionDigits", "maximumFractionDigits",
+		"minimumSignificantDigits", "maximumSignificantDigits",
+		"dateStyle", "timeStyle", "hourCycle", "dayPeriod", "weekday", "era", "year", "month", "day",
+		"hour", "minute", "second", "fractionalSecondDigits", "timeZoneName",
+	}
+
+	f.Add(uint8(0), "hello", 1.5, uint8(0), "x", uint8(1), "y", "en")
+	f.Add(uint8(1), "2006-01-02T15:04:05Z", 0.0, uint8(13), "full", uint8(14), "short", "lv")
+	f.Add(uint8(3), "-12.5", -3.0, uint8(8), "2", uint8(9), "1", "ar")
+	f.Add(uint8(4), "", 1e308, uint8(0), "ordinal", uint8(1), "percent", "und")
+
+	f.Fuzz(func(t *testing.T, fn uint8, s string, n float64, opt1 uint8, val1 string, opt2 uint8, val2, locale string) {
+		name := names[int(fn)%len(names)]
+		o1, o2 := options[int(opt1)%len(options)], options[int(opt2)%len(options)]
+
+		tag, err := language.Parse(locale)
+		if err != nil {
+			tag = language.Und
+		}
+
+		quote := strings.NewReplacer(`\`, `\\`, `|`, `\|`)
+
+		src := ".input { $s :" + name + " " + o1 + "=|" + quote.Replace(val1) + "| } " +
+			".local $m = { $n :" + name + " " + o2 + "=|" + quote.Replace(val2) + "| } " +
+			".match { $s } { $m } one two {{{ $s } { $m }}} * * {{{ $s }}}"
+
+		template, err := New(WithLocale(tag)).Parse(src)
+		if err != nil {
+			return // option name and value combinations that do not parse are not interesting
+		}
+
+		// formatting errors are expected, panics are not
+		_, _ = template.Sprint(map[string]any{"s": s, "n": n})
+		_, _ = template.FormatToParts(map[string]any{"s": s, "n": n})
+	})
+}
+
+// FuzzParseBinary tests that the template parsed from any binary data does not panic on execution.
+func FuzzParseBinary(f *testing.F) {
+	for _, seed := range []string{
+		"Hello, { $name }!",
+		".input { $n :number } .local $x = { $n :integer } {{{ $x }}}",
+		".input { $n :number } .match { $n } 0 {{zero}} one {{one}} * {{other}}",
+	} {
+		template, err := New().Parse(seed)
+		if err != nil {
+			f.Fatal(err)
+		}
+
+		data, err := template.MarshalBinary()
+		if err != nil {
+			f.Fatal(err)
+		}
+
+		f.Add(data)
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		template, err := New().ParseBinary(data)
+		if err != nil {
+			return
+		}
+
+		// formatting errors are expected, panics are not
+		_, _ = template.Sprint(map[string]any{"name": "World", "n": 1})
+	})
+}
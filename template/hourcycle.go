@@ -0,0 +1,91 @@
+package template
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"golang.org/x/text/language"
+)
+
+// hour12Regions are the regions preferring the 12-hour clock, a subset of CLDR timeData.
+var hour12Regions = map[string]struct{}{
+	"AS": {}, "AU": {}, "BD": {}, "CA": {}, "EG": {}, "GU": {}, "IN": {}, "KR": {}, "MP": {}, "NZ": {},
+	"PH": {}, "PK": {}, "PR": {}, "SA": {}, "TW": {}, "UM": {}, "US": {}, "VI": {},
+}
+
+// defaultHourCycle returns the preferred hour cycle of the locale's region, "h12" or "h23",
+// the hour cycle of "j" in the date-time skeleton, see [skeletonPattern].
+func defaultHourCycle(locale language.Tag) string {
+	region, _ := locale.Region()
+
+	if _, ok := hour12Regions[region.String()]; ok {
+		return "h12"
+	}
+
+	return "h23"
+}
+
+// parseHourCycle returns the hour cycle of the "hourCycle" and "hour12" options, empty if none.
+// As in ECMA-402, "hour12" wins over "hourCycle".
+func parseHourCycle(options Options) (string, error) {
+	hourCycles := oneOf("h11", "h12", "h23", "h24")
+
+	hourCycle, err := options.GetString("hourCycle", "", hourCycles)
+	if err != nil {
+		return "", err
+	}
+
+	if _, ok := options["hour12"]; !ok {
+		return hourCycle, nil
+	}
+
+	is12, err := options.GetBool("hour12", false)
+	if err != nil {
+		return "", err
+	}
+
+	if is12 {
+		return "h12", nil
+	}
+
+	return "h23", nil
+}
+
+// formatClock formats the time with the layout of the time style, e.g. "15:04:05 MST",
+// the fraction of the seconds, if any, follows the clock. The hour is formatted in the hour cycle:
+//   - h11: 0-11 with the day period, e.g. "0:30 AM";
+//   - h12: 1-12 with the day period, e.g. "12:30 AM";
+//   - h23: 00-23, e.g. "00:30", the default of the time styles;
+//   - h24: 01-24, e.g. "24:30".
+func formatClock(t time.Time, layout, hourCycle, fraction string) string {
+	clock, zone, _ := strings.Cut(strings.TrimPrefix(layout, "15"), " ")
+
+	var sb strings.Builder
+
+	hour := t.Hour()
+
+	switch hourCycle {
+	case "h11":
+		fmt.Fprintf(&sb, "%d", hour%12) //nolint:mnd
+	case "h12":
+		fmt.Fprintf(&sb, "%d", (hour+11)%12+1) //nolint:mnd
+	case "h24":
+		fmt.Fprintf(&sb, "%02d", (hour+23)%24+1) //nolint:mnd
+	default: // h23
+		fmt.Fprintf(&sb, "%02d", hour)
+	}
+
+	sb.WriteString(t.Format(clock))
+	sb.WriteString(fraction)
+
+	if hourCycle == "h11" || hourCycle == "h12" {
+		sb.WriteString(t.Format(" PM"))
+	}
+
+	if zone != "" {
+		sb.WriteString(t.Format(" " + zone))
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,136 @@
+package template
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+
+	"golang.org/x/text/unicode/norm"
+
+	"go.expect.digital/mf2/internal/mapkeys"
+)
+
+// structField is the exported field of the struct input, see [Template.Execute].
+type structField struct {
+	name  string
+	index []int
+}
+
+// structFields caches the []structField by the reflect.Type of the struct.
+var structFields sync.Map
+
+// fieldsOf returns the variables of the struct type, the field names or the names in `mf2:"name"` tags.
+// The fields of the embedded structs are promoted, the fields tagged `mf2:"-"` are skipped.
+func fieldsOf(typ reflect.Type) []structField {
+	if v, ok := structFields.Load(typ); ok {
+		return v.([]structField) //nolint:forcetypeassert
+	}
+
+	var fields []structField
+
+	for _, f := range reflect.VisibleFields(typ) {
+		if !f.IsExported() || f.Anonymous && f.Type.Kind() == reflect.Struct {
+			continue
+		}
+
+		name := f.Name
+
+		if tag, ok := f.Tag.Lookup("mf2"); ok {
+			if tag == "-" {
+				continue
+			}
+
+			if tag, _, _ = strings.Cut(tag, ","); tag != "" {
+				name = tag
+			}
+		}
+
+		fields = append(fields, structField{name: name, index: f.Index})
+	}
+
+	v, _ := structFields.LoadOrStore(typ, fields)
+
+	return v.([]structField) //nolint:forcetypeassert
+}
+
+// eachInput calls f with every variable of the input. The input is nil, map[string]any,
+// a map with string keys, a struct or a pointer to a struct.
+//
+// The variable names are compared NFC normalized, the map keys equal when normalized, e.g. "e\u0301"
+// and "\u00e9", are passed in the sorted order, the last one wins regardless of the map order.
+func eachInput(input any, f func(name string, value any) error) error {
+	switch input := input.(type) {
+	case nil:
+		return nil
+	case map[string]any:
+		if !normalKeys(input) {
+			for _, k := range mapkeys.Sorted(input) {
+				if err := f(k, input[k]); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		}
+
+		for k, v := range input {
+			if err := f(k, v); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	v := reflect.ValueOf(input)
+
+	if v.Kind() == reflect.Pointer && v.Type().Elem().Kind() == reflect.Struct {
+		if v.IsNil() {
+			return nil
+		}
+
+		v = v.Elem()
+	}
+
+	switch {
+	default:
+		return fmt.Errorf("unsupported input type %T", input)
+	case v.Kind() == reflect.Map && v.Type().Key().Kind() == reflect.String:
+		keys := v.MapKeys()
+
+		slices.SortFunc(keys, func(a, b reflect.Value) int { return strings.Compare(a.String(), b.String()) })
+
+		for _, k := range keys {
+			if err := f(k.String(), v.MapIndex(k).Interface()); err != nil {
+				return err
+			}
+		}
+	case v.Kind() == reflect.Struct:
+		for _, field := range fieldsOf(v.Type()) {
+			// the embedded pointer to a struct is nil
+			fv, err := v.FieldByIndexErr(field.index)
+			if err != nil {
+				continue
+			}
+
+			if err := f(field.name, fv.Interface()); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// normalKeys reports whether all keys are NFC normalized, none of them are equal when normalized.
+func normalKeys(m map[string]any) bool {
+	for k := range m {
+		if !norm.NFC.IsNormalString(k) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,84 @@
+package template
+
+import (
+	"testing"
+)
+
+func TestStructInput(t *testing.T) {
+	t.Parallel()
+
+	type Base struct {
+		ID int
+	}
+
+	type Input struct {
+		*Base
+		Name     string
+		Count    int    `mf2:"count"`
+		Password string `mf2:"-"`
+		secret   string
+	}
+
+	tmpl, err := New().Parse("{ $ID } { $Name } { $count } { $Password } { $secret }")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		name  string
+		input any
+		want  string
+	}{
+		{
+			name:  "struct",
+			input: Input{Base: &Base{ID: 7}, Name: "Jānis", Count: 1000, Password: "x", secret: "y"},
+			want:  "7 Jānis 1,000 {$Password} {$secret}",
+		},
+		{
+			name:  "pointer",
+			input: &Input{Name: "Jānis", Count: 1},
+			want:  "{$ID} Jānis 1 {$Password} {$secret}",
+		},
+		{
+			name:  "map",
+			input: map[string]string{"Name": "Jānis", "secret": "y"},
+			want:  "{$ID} Jānis {$count} {$Password} y",
+		},
+		{
+			name:  "nil pointer",
+			input: (*Input)(nil),
+			want:  "{$ID} {$Name} {$count} {$Password} {$secret}",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, _ := tmpl.Sprint(test.input)
+			if test.want != got {
+				t.Errorf("want '%s', got '%s'", test.want, got)
+			}
+		})
+	}
+
+	if _, err := tmpl.Sprint(1); err == nil {
+		t.Error("want unsupported input error, got nil")
+	}
+}
+
+func TestInputNormalizedKeys(t *testing.T) {
+	t.Parallel()
+
+	tmpl, err := New().Parse("{ $\u00e9 }")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the keys equal when normalized are set in the sorted order, the last wins
+	input := map[string]any{"e\u0301": "decomposed", "\u00e9": "composed"}
+
+	for range 10 {
+		if got, err := tmpl.Sprint(input); err != nil || got != "composed" {
+			t.Fatalf("want 'composed', got '%s' (%v)", got, err)
+		}
+	}
+}
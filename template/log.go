@@ -0,0 +1,109 @@
+package template
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"golang.org/x/text/language"
+
+	"go.expect.digital/mf2"
+	ast "go.expect.digital/mf2/parse"
+)
+
+// WithLogger logs a warning to the logger whenever the template produces fallback output,
+// e.g. "{$name}" for an unresolved variable or the catch-all variant for a failed selector.
+//
+// The record has the attributes "id", "locale", "expression", "code" and "error",
+// where "code" is the MF2 error code, e.g. "unresolved-variable".
+func WithLogger(logger *slog.Logger) Option {
+	return func(t *Template) {
+		t.logger = logger
+	}
+}
+
+// WithID sets the message ID of the template, it identifies the message in the log records.
+// The catalog templates have the message ID set.
+func WithID(id string) Option {
+	return func(t *Template) {
+		t.id = id
+	}
+}
+
+// FallbackEvent is the expression or selector resolved to the fallback representation, see [WithErrorHandler].
+type FallbackEvent struct {
+	// Err is the resolution error.
+	Err error
+	// ID is the message ID, see [WithID].
+	ID string
+	// Expression is the failed expression, e.g. "{ $name }".
+	Expression string
+	// Code is the MF2 error code, e.g. "unresolved-variable".
+	Code string
+	// Locale is the locale of the execution.
+	Locale language.Tag
+}
+
+// WithErrorHandler calls the handler whenever the template produces fallback output, e.g. to route
+// the resolution errors to the logging or tracing backend. The handler is called with the context
+// of the execution, see [Template.ExecuteContext], concurrently by the concurrent executions.
+func WithErrorHandler(handler func(ctx context.Context, event FallbackEvent)) Option {
+	return func(t *Template) {
+		t.onError = handler
+	}
+}
+
+// warn logs the resolution error of the expression, if the template has a logger,
+// reports the fallback to the metrics, see [WithMetrics], and calls the error handler, see [WithErrorHandler].
+func (e *executer) warn(expr ast.Expression, err error) {
+	if e.template.metrics != nil {
+		e.template.metrics.Fallback(errorCode(err))
+	}
+
+	if e.template.onError != nil {
+		e.template.onError(e.ctx, FallbackEvent{
+			Err:        err,
+			ID:         e.template.id,
+			Expression: expr.String(),
+			Code:       errorCode(err),
+			Locale:     e.locale,
+		})
+	}
+
+	logger := e.template.logger
+	if logger == nil || !logger.Enabled(e.ctx, slog.LevelWarn) {
+		return
+	}
+
+	logger.LogAttrs(e.ctx, slog.LevelWarn, "mf2: fallback output",
+		slog.String("id", e.template.id),
+		slog.String("locale", e.locale.String()),
+		slog.String("expression", expr.String()),
+		slog.String("code", errorCode(err)),
+		slog.Any("error", err),
+	)
+}
+
+// errorCode returns the MF2 error code of the resolution error.
+//
+// See ".message-format-wg/spec/errors.md".
+func errorCode(err error) string {
+	for _, v := range []struct {
+		err  error
+		code string
+	}{
+		{mf2.ErrUnresolvedVariable, "unresolved-variable"},
+		{mf2.ErrUnknownFunction, "unknown-function"},
+		{mf2.ErrUnsupportedExpression, "unsupported-expression"},
+		{mf2.ErrUnsupportedStatement, "unsupported-statement"},
+		{mf2.ErrBadOperand, "bad-operand"},
+		{mf2.ErrBadOption, "bad-option"},
+		{mf2.ErrBadVariantKey, "bad-variant-key"},
+	} {
+		if errors.Is(err, v.err) {
+			return v.code
+		}
+	}
+
+	return "message-function-error"
+}
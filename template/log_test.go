@@ -0,0 +1,98 @@
+package template
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"golang.org/x/text/language"
+
+	"go.expect.digital/mf2"
+)
+
+func TestWithLogger(t *testing.T) {
+	t.Parallel()
+
+	removeTime := func(_ []string, a slog.Attr) slog.Attr {
+		if a.Key == slog.TimeKey {
+			return slog.Attr{}
+		}
+
+		return a
+	}
+
+	for _, test := range []struct {
+		name, in, want string
+	}{
+		{
+			name: "unresolved variable",
+			in:   "Hello, { $name }!",
+			want: `level=WARN msg="mf2: fallback output" id=greeting locale=en-US expression="{ $name }" ` +
+				`code=unresolved-variable error="expression: unresolved variable \"$name\""`,
+		},
+		{
+			name: "unknown function",
+			in:   ".match { $count :foo } one {{one}} * {{other}}",
+			want: `level=WARN msg="mf2: fallback output" id=greeting locale=en-US expression="{ $count :foo }" ` +
+				`code=unknown-function error="unknown function \"foo\""`,
+		},
+		{name: "no fallback", in: "Hello, World!"},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+
+			logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{ReplaceAttr: removeTime}))
+
+			template, err := New(WithLogger(logger), WithID("greeting")).Parse(test.in)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, _ = template.Sprint(map[string]any{"count": 1})
+
+			if got := strings.TrimSpace(buf.String()); test.want != got {
+				t.Errorf("want '%s', got '%s'", test.want, got)
+			}
+		})
+	}
+}
+
+func TestWithErrorHandler(t *testing.T) {
+	t.Parallel()
+
+	var events []FallbackEvent
+
+	handler := func(_ context.Context, event FallbackEvent) {
+		events = append(events, event)
+	}
+
+	template, err := New(WithErrorHandler(handler), WithID("greeting"), WithLocale(language.Latvian)).
+		Parse("Hello, { $name }!")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, _ = template.Sprint(nil)
+
+	if len(events) != 1 {
+		t.Fatalf("want 1 event, got %d", len(events))
+	}
+
+	got := events[0]
+
+	if !errors.Is(got.Err, mf2.ErrUnresolvedVariable) {
+		t.Errorf("want unresolved variable, got '%v'", got.Err)
+	}
+
+	got.Err = nil
+
+	want := FallbackEvent{ID: "greeting", Expression: "{ $name }", Code: "unresolved-variable", Locale: language.Latvian}
+	if want != got {
+		t.Errorf("want %+v, got %+v", want, got)
+	}
+}
@@ -0,0 +1,41 @@
+package template
+
+import (
+	"time"
+)
+
+// Metrics receives the events of the templates, e.g. to count the parses and the fallbacks,
+// and to alert when the rate of the unresolved variables suddenly increases. The methods are called
+// concurrently by all templates sharing the metrics.
+type Metrics interface {
+	// Parsed is called after the message is parsed by [Template.Parse], err is the parse error, if any.
+	Parsed(duration time.Duration, err error)
+	// Executed is called after the template is executed, e.g. by [Template.Execute] or
+	// [Template.FormatToParts], err is the returned error, if any.
+	Executed(duration time.Duration, err error)
+	// Fallback is called for each expression or selector resolved to the fallback representation
+	// with the MF2 error code, e.g. "unresolved-variable".
+	Fallback(code string)
+}
+
+// WithMetrics reports the parses, the executions and the fallbacks of the template to the metrics.
+func WithMetrics(metrics Metrics) Option {
+	return func(t *Template) {
+		t.metrics = metrics
+	}
+}
+
+// parsed reports the parse started at start, if the template has metrics.
+func (t *Template) parsed(start time.Time, err error) {
+	if t.metrics != nil {
+		t.metrics.Parsed(time.Since(start), err)
+	}
+}
+
+// executed reports the execution started at start with the returned error, if the template has metrics.
+// It is deferred by the executing methods.
+func (t *Template) executed(start time.Time, err *error) {
+	if t.metrics != nil {
+		t.metrics.Executed(time.Since(start), *err)
+	}
+}
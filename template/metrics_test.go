@@ -0,0 +1,77 @@
+package template
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+type testMetrics struct {
+	fallbacks  map[string]int
+	parses     int
+	parseErrs  int
+	executions int
+	execErrs   int
+	mu         sync.Mutex
+}
+
+func (m *testMetrics) Parsed(_ time.Duration, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.parses++
+
+	if err != nil {
+		m.parseErrs++
+	}
+}
+
+func (m *testMetrics) Executed(_ time.Duration, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.executions++
+
+	if err != nil {
+		m.execErrs++
+	}
+}
+
+func (m *testMetrics) Fallback(code string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.fallbacks[code]++
+}
+
+func TestMetrics(t *testing.T) {
+	t.Parallel()
+
+	metrics := &testMetrics{fallbacks: make(map[string]int)}
+
+	if _, err := New(WithMetrics(metrics)).Parse("{ $x"); err == nil {
+		t.Error("want syntax error, got nil")
+	}
+
+	tmpl, err := New(WithMetrics(metrics)).Parse("{ $name } { $x :unknown }")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, _ = tmpl.Sprint(map[string]any{"x": 1})
+	_, _ = tmpl.Sprint(map[string]any{"name": "a", "x": 1})
+	_, _ = tmpl.FormatToParts(map[string]any{"name": "a", "x": 1})
+
+	want := &testMetrics{
+		parses:     2,
+		parseErrs:  1,
+		executions: 3,
+		execErrs:   3,
+		fallbacks:  map[string]int{"unresolved-variable": 1, "unknown-function": 3},
+	}
+
+	if !reflect.DeepEqual(want, metrics) {
+		t.Errorf("want %+v, got %+v", want, metrics)
+	}
+}
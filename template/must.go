@@ -0,0 +1,53 @@
+package template
+
+import (
+	"io"
+	"slices"
+)
+
+// The execution variants differ only in the result on the resolution errors, e.g. a missing variable:
+//   - [Template.Execute] and [Template.Sprint] return the output with the failed expressions
+//     in the fallback representation together with the error, see [ErrorPolicy];
+//   - [Template.TryExecute] and [Template.TrySprint] return only the error, nothing is written;
+//   - [Template.MustSprint] panics.
+//
+// The parse errors are handled the same way by [Template.Parse], [MustParse] and [Must].
+
+// Must is a helper that wraps a call to a function returning (*Template, error) and panics if the error is non-nil.
+// It is intended for use in variable initializations such as
+//
+//	var t = template.Must(template.New(template.WithLocale(language.English)).Parse("Hello, { $name }!"))
+func Must(t *Template, err error) *Template {
+	if err != nil {
+		panic(err)
+	}
+
+	return t
+}
+
+// MustParse returns the new template of the parsed input, it panics if the input is not a valid message.
+func MustParse(input string, options ...Option) *Template {
+	return Must(New(options...).Parse(input))
+}
+
+// MustSprint is like [Template.Sprint], but panics on any error, including the resolution errors.
+func (t *Template) MustSprint(input any, options ...ExecuteOption) string {
+	s, err := t.TrySprint(input, options...)
+	if err != nil {
+		panic(err)
+	}
+
+	return s
+}
+
+// TryExecute is like [Template.Execute], but nothing is written on any error,
+// the same as [WithErrorPolicy] of [StrictErrors].
+func (t *Template) TryExecute(w io.Writer, input any, options ...ExecuteOption) error {
+	return t.Execute(w, input, slices.Concat(options, []ExecuteOption{WithErrorPolicy(StrictErrors)})...)
+}
+
+// TrySprint is like [Template.Sprint], but returns an empty string on any error,
+// the same as [WithErrorPolicy] of [StrictErrors].
+func (t *Template) TrySprint(input any, options ...ExecuteOption) (string, error) {
+	return t.Sprint(input, slices.Concat(options, []ExecuteOption{WithErrorPolicy(StrictErrors)})...)
+}
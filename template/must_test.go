@@ -0,0 +1,67 @@
+package template
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"go.expect.digital/mf2"
+)
+
+func TestMust(t *testing.T) {
+	t.Parallel()
+
+	tmpl := MustParse("Hello, { $name }!")
+
+	if got := tmpl.MustSprint(map[string]any{"name": "World"}); got != "Hello, World!" {
+		t.Errorf("want 'Hello, World!', got '%s'", got)
+	}
+
+	panics := func(name string, f func()) {
+		t.Helper()
+
+		defer func() {
+			if recover() == nil {
+				t.Errorf("%s: want panic, got none", name)
+			}
+		}()
+
+		f()
+	}
+
+	panics("MustParse", func() { MustParse("{ $name") })
+	panics("Must", func() { Must(New().Parse("{ $name")) })
+	panics("MustSprint", func() { tmpl.MustSprint(nil) })
+}
+
+func TestTry(t *testing.T) {
+	t.Parallel()
+
+	tmpl := MustParse("Hello, { $name }!")
+
+	var buf bytes.Buffer
+
+	if err := tmpl.TryExecute(&buf, nil); !errors.Is(err, mf2.ErrUnresolvedVariable) || buf.Len() > 0 {
+		t.Errorf("want '%s' and no output, got '%v' and '%s'", mf2.ErrUnresolvedVariable, err, buf.String())
+	}
+
+	if got, err := tmpl.TrySprint(nil, WithErrorPolicy(IgnoreErrors)); err == nil || got != "" {
+		t.Errorf("want error and no output, got '%v' and '%s'", err, got)
+	}
+
+	if got, err := tmpl.TrySprint(map[string]any{"name": "World"}); err != nil || got != "Hello, World!" {
+		t.Errorf("want 'Hello, World!', got '%s' (%v)", got, err)
+	}
+
+	// the strict error policy is not appended to the options of the caller
+	options := make([]ExecuteOption, 1, 2)
+	options[0] = WithErrorPolicy(IgnoreErrors)
+
+	if _, err := tmpl.TrySprint(nil, options...); err == nil {
+		t.Error("want error, got nil")
+	}
+
+	if options[:2][1] != nil {
+		t.Error("want unmodified options, got the appended option")
+	}
+}
@@ -0,0 +1,220 @@
+package template
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+
+	"go.expect.digital/mf2"
+	ast "go.expect.digital/mf2/parse"
+)
+
+// maxTypedOptions is the maximum number of the typed options cached per expression, e.g. for the
+// functions parsing the options differently, the other typed options are parsed on every call.
+const maxTypedOptions = 16
+
+// compiledOptions are the function options with the literal values resolved when the template is parsed,
+// only the variable values are resolved on every execution.
+type compiledOptions struct {
+	// static are the literal options, shared by all executions and never handed out to the functions,
+	// the functions get the copies.
+	static Options
+	// err is the duplicate option name error, reported on execution.
+	err error
+	// typed are the options parsed by the built-in functions, see [cachedOptions].
+	typed map[typedKey]any
+	// names are the sorted names of the static options.
+	names []string
+	// dynamic are the options with the variable values.
+	dynamic []ast.Option
+	mu      sync.Mutex
+}
+
+// expressionPlan are the compiled options of the functions by the place of the expression in the message,
+// nil for the expressions without options. The options of the variants are planned with the variants,
+// see [plannedVariant].
+type expressionPlan struct {
+	declarations []*compiledOptions
+	selectors    []*compiledOptions
+	// pattern are the options by the pattern part of the message without matcher.
+	pattern []*compiledOptions
+}
+
+// newExpressionPlan resolves the literal options of the functions in the message.
+func newExpressionPlan(tree ast.AST) *expressionPlan {
+	plan := new(expressionPlan)
+
+	switch message := tree.Message.(type) {
+	case ast.SimpleMessage:
+		plan.pattern = compilePattern(message)
+	case ast.ComplexMessage:
+		plan.declarations = make([]*compiledOptions, len(message.Declarations))
+
+		for i, declaration := range message.Declarations {
+			switch d := declaration.(type) {
+			case ast.InputDeclaration:
+				plan.declarations[i] = compileExpression(ast.Expression(d))
+			case ast.LocalDeclaration:
+				plan.declarations[i] = compileExpression(d.Expression)
+			}
+		}
+
+		switch body := message.ComplexBody.(type) {
+		case ast.QuotedPattern:
+			plan.pattern = compilePattern(body)
+		case ast.Matcher:
+			plan.selectors = make([]*compiledOptions, len(body.Selectors))
+
+			for i, selector := range body.Selectors {
+				plan.selectors[i] = compileExpression(selector)
+			}
+		}
+	}
+
+	return plan
+}
+
+// compilePattern resolves the literal options of the expressions by the pattern part.
+func compilePattern(pattern []ast.PatternPart) []*compiledOptions {
+	compiled := make([]*compiledOptions, len(pattern))
+
+	for i, part := range pattern {
+		if expr, ok := part.(ast.Expression); ok {
+			compiled[i] = compileExpression(expr)
+		}
+	}
+
+	return compiled
+}
+
+// compileExpression resolves the literal options of the function, nil if the function has no options.
+func compileExpression(expr ast.Expression) *compiledOptions {
+	function, ok := expr.Annotation.(ast.Function)
+	if !ok || len(function.Options) == 0 {
+		return nil
+	}
+
+	c := &compiledOptions{static: make(Options, len(function.Options))}
+
+	for _, opt := range function.Options {
+		name := opt.Identifier.String()
+		if _, ok := c.static[name]; ok || containsOption(c.dynamic, name) {
+			c.err = fmt.Errorf(`%w "%s"`, mf2.ErrDuplicateOptionName, name)
+			break
+		}
+
+		switch v := opt.Value.(type) {
+		default:
+			c.dynamic = append(c.dynamic, opt)
+			continue
+		case ast.QuotedLiteral:
+			c.static[name] = NewResolvedValue(string(v))
+		case ast.NameLiteral:
+			c.static[name] = NewResolvedValue(string(v))
+		case ast.NumberLiteral:
+			c.static[name] = NewResolvedValue(float64(v))
+		}
+
+		c.names = append(c.names, name)
+	}
+
+	sort.Strings(c.names)
+
+	return c
+}
+
+// optionsAt returns the compiled options at the index, nil if there are none.
+func optionsAt(compiled []*compiledOptions, i int) *compiledOptions {
+	if i < len(compiled) {
+		return compiled[i]
+	}
+
+	return nil
+}
+
+func containsOption(options []ast.Option, name string) bool {
+	for _, opt := range options {
+		if opt.Identifier.String() == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// typedKey is the key of the typed options of the expression, see [cachedOptions].
+type typedKey struct {
+	// parser is the parsing of the options, e.g. the function and the styles the parsing depends on.
+	parser any
+	// literal is the bitmask of the compiled options by the index of the sorted name.
+	literal uint64
+}
+
+// cachedOptions returns the options parsed by parse, cached per expression. The options are cached
+// only if all of them are the literal options of the expression, the options with the variable values
+// or added by the function, e.g. "maximumFractionDigits" of :integer, are parsed on every call.
+// The errors are not cached. The caller gets the copy of the cached options.
+func cachedOptions[T any](options Options, parser any, parse func() (*T, error)) (*T, error) {
+	compiled, key, ok := typedOptionsKey(options, parser)
+	if !ok {
+		return parse()
+	}
+
+	compiled.mu.Lock()
+	typed, ok := compiled.typed[key].(*T)
+	compiled.mu.Unlock()
+
+	if !ok {
+		var err error
+
+		if typed, err = parse(); err != nil {
+			return nil, err
+		}
+
+		compiled.mu.Lock()
+
+		if compiled.typed == nil {
+			compiled.typed = make(map[typedKey]any)
+		}
+
+		if len(compiled.typed) < maxTypedOptions {
+			compiled.typed[key] = typed
+		}
+
+		compiled.mu.Unlock()
+	}
+
+	v := *typed
+
+	return &v, nil
+}
+
+// typedOptionsKey returns the compiled options of the literal options and the key of the typed options,
+// false if any of the options is not the literal option of the expression.
+func typedOptionsKey(options Options, parser any) (*compiledOptions, typedKey, bool) {
+	var compiled *compiledOptions
+
+	key := typedKey{parser: parser}
+
+	for name, v := range options {
+		if v.literal == nil || compiled != nil && compiled != v.literal {
+			return nil, typedKey{}, false
+		}
+
+		// the function may have moved or replaced the literal value
+		i, ok := slices.BinarySearch(v.literal.names, name)
+		if !ok || i >= 64 || v.literal.static[name].value != v.value {
+			return nil, typedKey{}, false
+		}
+
+		compiled = v.literal
+		key.literal |= 1 << i
+	}
+
+	if compiled == nil {
+		return nil, typedKey{}, false
+	}
+
+	return compiled, key, true
+}
@@ -0,0 +1,118 @@
+package template
+
+import (
+	"errors"
+	"testing"
+
+	"golang.org/x/text/language"
+
+	"go.expect.digital/mf2"
+)
+
+func TestCompileOptions(t *testing.T) {
+	t.Parallel()
+
+	template, err := New().Parse("{ $n :number minimumFractionDigits=2 style=$style } { $m :integer }")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	compiled := template.expressions.pattern
+
+	if compiled[0] == nil || compiled[2] != nil {
+		t.Fatalf("want compiled options of the first expression only, got %v", compiled)
+	}
+
+	if got := compiled[0].static["minimumFractionDigits"].value; got != 2.0 {
+		t.Errorf("want 2, got %v", got)
+	}
+
+	if got := len(compiled[0].dynamic); got != 1 {
+		t.Errorf("want 1 dynamic option, got %d", got)
+	}
+
+	for style, want := range map[string]string{"decimal": "0.50 1", "percent": "50.00% 1"} {
+		if got, err := template.Sprint(map[string]any{"n": 0.5, "m": 1, "style": style}); err != nil || want != got {
+			t.Errorf("want '%s', got '%s' (%v)", want, got, err)
+		}
+	}
+
+	for _, in := range []string{
+		"{ $n :number style=percent style=decimal }",
+		"{ $n :number style=$style style=decimal }",
+		"{ $n :number style=decimal style=$style }",
+	} {
+		template, err := New().Parse(in)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := template.Sprint(map[string]any{"n": 1, "style": "percent"}); !errors.Is(err, mf2.ErrDuplicateOptionName) {
+			t.Errorf("%s: want '%s', got '%v'", in, mf2.ErrDuplicateOptionName, err)
+		}
+	}
+}
+
+func TestCachedOptions(t *testing.T) {
+	t.Parallel()
+
+	template, err := New().Parse(".match { $n :integer signDisplay=always } " +
+		"one {{{ $n :number minimumFractionDigits=2 }}} * {{{ $n :number signDisplay=auto minimumFractionDigits=$digits }}}")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		input map[string]any
+		want  string
+	}{
+		{input: map[string]any{"n": 1}, want: "1.00"},
+		{input: map[string]any{"n": 1}, want: "1.00"},
+		{input: map[string]any{"n": 2, "digits": 1}, want: "2.0"},
+		{input: map[string]any{"n": 2, "digits": 3}, want: "2.000"},
+		{input: map[string]any{"n": 2, "digits": 1}, want: "2.0"},
+	} {
+		if got, err := template.Sprint(test.input); err != nil || test.want != got {
+			t.Errorf("want '%s', got '%s' (%v)", test.want, got, err)
+		}
+	}
+
+	// only the literal options are cached, not with "maximumFractionDigits" added by :integer
+	// or the variable option
+	for _, test := range []struct {
+		compiled *compiledOptions
+		want     int
+	}{
+		{compiled: template.expressions.selectors[0], want: 0},
+		{compiled: template.plan.variants[0].options[0], want: 1},
+		{compiled: template.plan.variants[1].options[0], want: 0},
+	} {
+		if got := len(test.compiled.typed); test.want != got {
+			t.Errorf("want %d cached options, got %d", test.want, got)
+		}
+	}
+}
+
+func TestCompileOptionsCopied(t *testing.T) {
+	t.Parallel()
+
+	// the function modifies the option value
+	modify := func(_ *ResolvedValue, options Options, _ language.Tag) (*ResolvedValue, error) {
+		label := options["label"].String()
+
+		NewResolvedValue(options["label"], WithFormat(func() string { return "modified" }))
+
+		return NewResolvedValue(label), nil
+	}
+
+	template, err := New(WithFunc("modify", modify)).Parse("{ :modify label=original }")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for range 2 {
+		if got, err := template.Sprint(nil); err != nil || got != "original" {
+			t.Errorf("want 'original', got '%s' (%v)", got, err)
+		}
+	}
+}
@@ -0,0 +1,246 @@
+package template
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	ast "go.expect.digital/mf2/parse"
+)
+
+// Part is a formatted part of the message, one of [*TextPart], [*ExpressionPart] or [*MarkupPart].
+type Part interface {
+	part()
+}
+
+// TextPart is the literal text of the pattern.
+type TextPart struct {
+	Value string
+}
+
+// ExpressionPart is the formatted expression of the pattern.
+type ExpressionPart struct {
+	// Attributes are the expression attributes, e.g. {"u:id": "price"} for "@u:id=price",
+	// the value is empty for the attribute without a value, e.g. "@translate".
+	Attributes map[string]string
+	// Source is the operand or the annotation of the expression, e.g. "$count" or ":randName".
+	Source string
+	// Value is the formatted value, or the fallback representation if the expression failed to resolve.
+	Value string
+}
+
+// MarkupPart is the markup placeholder of the pattern, e.g. "{#link href=$url}".
+// Clients map the markup to UI components, e.g. the open and close parts of "link" wrap the link text.
+type MarkupPart struct {
+	// Options are the resolved option values, only the open and standalone markup has options.
+	Options map[string]any
+	// Attributes are the markup attributes, see [ExpressionPart.Attributes].
+	Attributes map[string]string
+	// Kind is one of "open", "close" or "standalone".
+	Kind string
+	// Name is the identifier of the markup, e.g. "link" or "html:b".
+	Name string
+}
+
+func (*TextPart) part()       {}
+func (*ExpressionPart) part() {}
+func (*MarkupPart) part()     {}
+
+// MarshalJSON implements [json.Marshaler].
+func (p *TextPart) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct { //nolint:wrapcheck
+		Type  string `json:"type"`
+		Value string `json:"value"`
+	}{
+		Type:  "text",
+		Value: p.Value,
+	})
+}
+
+// MarshalJSON implements [json.Marshaler].
+func (p *ExpressionPart) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct { //nolint:wrapcheck
+		Type       string            `json:"type"`
+		Source     string            `json:"source"`
+		Value      string            `json:"value"`
+		Attributes map[string]string `json:"attributes,omitempty"`
+	}{
+		Type:       "expression",
+		Source:     p.Source,
+		Value:      p.Value,
+		Attributes: p.Attributes,
+	})
+}
+
+// FormatToParts formats the template to parts instead of a string.
+// Unlike [Template.Execute], which drops markup, the markup placeholders are [*MarkupPart] parts.
+//
+// On resolution errors the parts are returned together with the error,
+// the failed expressions are formatted with the fallback representation.
+func (t *Template) FormatToParts(input any, options ...ExecuteOption) ([]Part, error) {
+	return t.FormatToPartsContext(context.Background(), input, options...)
+}
+
+// FormatToPartsContext is like [Template.FormatToParts], the [Values] in ctx are the defaults of the function options.
+func (t *Template) FormatToPartsContext(
+	ctx context.Context,
+	input any,
+	options ...ExecuteOption,
+) (_ []Part, err error) {
+	defer t.executed(time.Now(), &err)
+
+	executer, err := t.newExecuter(ctx, nil, input, options)
+	if err != nil {
+		return nil, fmt.Errorf("format to parts: %w", err)
+	}
+
+	defer executer.release()
+
+	parts := []Part{}
+	executer.parts = &parts
+
+	if err := executer.result(executer.execute()); err != nil {
+		if executer.errorPolicy == StrictErrors {
+			return nil, fmt.Errorf("format to parts: %w", err)
+		}
+
+		return parts, fmt.Errorf("format to parts: %w", err)
+	}
+
+	return parts, nil
+}
+
+// MarshalJSON implements [json.Marshaler].
+func (p *MarkupPart) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct { //nolint:wrapcheck
+		Type       string            `json:"type"`
+		Kind       string            `json:"kind"`
+		Name       string            `json:"name"`
+		Options    map[string]any    `json:"options,omitempty"`
+		Attributes map[string]string `json:"attributes,omitempty"`
+	}{
+		Type:       "markup",
+		Kind:       p.Kind,
+		Name:       p.Name,
+		Options:    p.Options,
+		Attributes: p.Attributes,
+	})
+}
+
+// addMarkup appends the markup part with the resolved options.
+func (e *executer) addMarkup(markup ast.Markup) error {
+	part, err := e.markupPart(markup)
+
+	*e.parts = append(*e.parts, part)
+
+	return err
+}
+
+// renderMarkup writes the markup rendered by the [MarkupRenderer], if any.
+func (e *executer) renderMarkup(markup ast.Markup) error {
+	if e.markup == nil {
+		return nil
+	}
+
+	part, err := e.markupPart(markup)
+
+	if _, writeErr := io.WriteString(e.w, e.markup(part)); writeErr != nil {
+		return errors.Join(err, fmt.Errorf("write markup: %w", writeErr))
+	}
+
+	return err
+}
+
+// markupPart returns the markup part with the resolved options.
+func (e *executer) markupPart(markup ast.Markup) (*MarkupPart, error) {
+	var resolutionErr error
+
+	part := &MarkupPart{Name: markup.Identifier.String(), Attributes: attributes(markup.Attributes)}
+
+	switch markup.Typ {
+	case ast.Open:
+		part.Kind = "open"
+	case ast.Close:
+		part.Kind = "close"
+	case ast.SelfClose:
+		part.Kind = "standalone"
+	case ast.Unspecified:
+	}
+
+	if len(markup.Options) > 0 && markup.Typ != ast.Close {
+		part.Options = make(map[string]any, len(markup.Options))
+
+		for _, option := range markup.Options {
+			value, err := e.resolveValue(option.Value)
+			if err != nil {
+				resolutionErr = errors.Join(resolutionErr, fmt.Errorf("markup option: %w", err))
+				continue
+			}
+
+			if v, ok := value.(*ResolvedValue); ok {
+				value = v.value
+			}
+
+			part.Options[option.Identifier.String()] = value
+		}
+	}
+
+	return part, resolutionErr
+}
+
+// attributes returns the attribute values by name, nil if there are no attributes.
+// Attributes do not affect formatting, the variable values are not resolved, e.g. "$id".
+func attributes(attrs []ast.Attribute) map[string]string {
+	if len(attrs) == 0 {
+		return nil
+	}
+
+	m := make(map[string]string, len(attrs))
+
+	for _, attr := range attrs {
+		var value string
+
+		switch v := attr.Value.(type) {
+		case ast.QuotedLiteral:
+			value = string(v)
+		case ast.NameLiteral:
+			value = string(v)
+		case ast.NumberLiteral, ast.Variable:
+			value = v.String()
+		}
+
+		m[attr.Identifier.String()] = value
+	}
+
+	return m
+}
+
+// addText appends the text to the last text part, or adds a new one.
+func (e *executer) addText(s string) {
+	parts := *e.parts
+
+	if i := len(parts) - 1; i >= 0 {
+		if text, ok := parts[i].(*TextPart); ok {
+			text.Value += s
+			return
+		}
+	}
+
+	*e.parts = append(parts, &TextPart{Value: s})
+}
+
+// expressionSource returns the source of the expression, the operand or the annotation.
+func expressionSource(expr ast.Expression) string {
+	if expr.Operand != nil {
+		return expr.Operand.String()
+	}
+
+	if expr.Annotation != nil {
+		return expr.Annotation.String()
+	}
+
+	return ""
+}
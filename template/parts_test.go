@@ -0,0 +1,92 @@
+package template
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"go.expect.digital/mf2"
+)
+
+func TestFormatToParts(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		input   map[string]any
+		wantErr error
+		name    string
+		text    string
+		want    string
+	}{
+		{
+			name: "empty message",
+			want: `[]`,
+		},
+		{
+			name: "text",
+			text: "Hello, World!",
+			want: `[{"type":"text","value":"Hello, World!"}]`,
+		},
+		{
+			name:  "expressions",
+			text:  "Hello, { $name }{ |!| } { $count :number } { #b }items{ /b }",
+			input: map[string]any{"name": "World", "count": 5},
+			want: `[{"type":"text","value":"Hello, "},{"type":"expression","source":"$name","value":"World"},` +
+				`{"type":"expression","source":"|!|","value":"!"},{"type":"text","value":" "},` +
+				`{"type":"expression","source":"$count","value":"5"},{"type":"text","value":" "},` +
+				`{"type":"markup","kind":"open","name":"b"},{"type":"text","value":"items"},` +
+				`{"type":"markup","kind":"close","name":"b"}]`,
+		},
+		{
+			name:  "attributes",
+			text:  "{ $price :number @u:id=price @translate=no @empty }{ #b @u:id=|bold| }!{ /b }",
+			input: map[string]any{"price": 5},
+			want: `[{"type":"expression","source":"$price","value":"5","attributes":{"empty":"","translate":"no","u:id":"price"}},` +
+				`{"type":"markup","kind":"open","name":"b","attributes":{"u:id":"bold"}},` +
+				`{"type":"text","value":"!"},{"type":"markup","kind":"close","name":"b"}]`,
+		},
+		{
+			name:  "markup",
+			text:  "{ #link href=$url target=|_blank| }site{ /link }{ #html:br /}",
+			input: map[string]any{"url": "https://example.com"},
+			want: `[{"type":"markup","kind":"open","name":"link","options":{"href":"https://example.com","target":"_blank"}},` +
+				`{"type":"text","value":"site"},{"type":"markup","kind":"close","name":"link"},` +
+				`{"type":"markup","kind":"standalone","name":"html:br"}]`,
+		},
+		{
+			name:  "matcher",
+			text:  ".input { $count :number } .match { $count } one {{{ $count } item}} * {{{ $count } items}}",
+			input: map[string]any{"count": 1},
+			want:  `[{"type":"expression","source":"$count","value":"1"},{"type":"text","value":" item"}]`,
+		},
+		{
+			name:    "fallback",
+			text:    "Hello, { $name }!",
+			want:    `[{"type":"text","value":"Hello, "},{"type":"expression","source":"$name","value":"{$name}"},{"type":"text","value":"!"}]`,
+			wantErr: mf2.ErrUnresolvedVariable,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			template, err := New().Parse(test.text)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			parts, err := template.FormatToParts(test.input)
+			if !errors.Is(err, test.wantErr) {
+				t.Errorf("want error '%v', got '%v'", test.wantErr, err)
+			}
+
+			got, err := json.Marshal(parts)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if test.want != string(got) {
+				t.Errorf("want '%s', got '%s'", test.want, got)
+			}
+		})
+	}
+}
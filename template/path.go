@@ -0,0 +1,117 @@
+package template
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// WithDottedPaths resolves the variables with dots, e.g. "$user.name", by traversing the fields of
+// the structs and the keys of the maps in the input, unless the input has the variable of the exact name.
+//
+// Example:
+//
+//	tmpl, _ := template.New(template.WithDottedPaths()).Parse("Hello, { $user.name }!")
+//	tmpl.Sprint(map[string]any{"user": User{Name: "Jānis"}}) // Hello, Jānis!
+func WithDottedPaths() Option {
+	return func(t *Template) {
+		t.dottedPaths = true
+	}
+}
+
+// resolvePath resolves the variable with the dotted path, the longest variable prefix is traversed,
+// e.g. "user" of "$user.address.city". The resolved value is cached in the variables.
+func (e *executer) resolvePath(name string) (*ResolvedValue, bool, error) {
+	if !e.template.dottedPaths {
+		return nil, false, nil
+	}
+
+	for i := strings.LastIndexByte(name, '.'); i > 0; i = strings.LastIndexByte(name[:i], '.') {
+		root, ok := e.variables[name[:i]]
+		if !ok {
+			continue
+		}
+
+		if root.err != nil {
+			return nil, true, root.err
+		}
+
+		value, ok := traverse(root.value, strings.Split(name[i+1:], "."))
+		if !ok {
+			return nil, false, nil
+		}
+
+		r, err := e.newInput(value)
+		if err != nil {
+			return nil, true, fmt.Errorf(`resolve "%s": %w`, name, err)
+		}
+
+		e.variables[name] = r
+
+		return r, true, nil
+	}
+
+	return nil, false, nil
+}
+
+// traverse returns the value at the path of the map keys and the struct fields, see [fieldsOf].
+func traverse(value any, path []string) (any, bool) {
+	for _, key := range path {
+		if m, ok := value.(map[string]any); ok {
+			if value, ok = m[key]; !ok {
+				return nil, false
+			}
+
+			continue
+		}
+
+		v := reflect.ValueOf(value)
+
+		for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+			if v.IsNil() {
+				return nil, false
+			}
+
+			v = v.Elem()
+		}
+
+		switch v.Kind() { //nolint:exhaustive
+		default:
+			return nil, false
+		case reflect.Map:
+			if v.Type().Key().Kind() != reflect.String {
+				return nil, false
+			}
+
+			field := v.MapIndex(reflect.ValueOf(key).Convert(v.Type().Key()))
+			if !field.IsValid() {
+				return nil, false
+			}
+
+			value = field.Interface()
+		case reflect.Struct:
+			var found bool
+
+			for _, f := range fieldsOf(v.Type()) {
+				if f.name != key {
+					continue
+				}
+
+				field, err := v.FieldByIndexErr(f.index)
+				if err != nil {
+					return nil, false
+				}
+
+				value, found = field.Interface(), true
+
+				break
+			}
+
+			if !found {
+				return nil, false
+			}
+		}
+	}
+
+	return value, true
+}
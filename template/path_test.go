@@ -0,0 +1,98 @@
+package template
+
+import (
+	"errors"
+	"testing"
+
+	"go.expect.digital/mf2"
+)
+
+func TestDottedPaths(t *testing.T) {
+	t.Parallel()
+
+	type Address struct {
+		City string `mf2:"city"`
+	}
+
+	type User struct {
+		Address *Address
+		Tags    map[string]string
+		Name    string
+		Visits  int
+	}
+
+	user := User{Name: "Jānis", Visits: 1000, Address: &Address{City: "Rīga"}, Tags: map[string]string{"role": "admin"}}
+
+	for _, test := range []struct {
+		wantErr error
+		input   any
+		name    string
+		in      string
+		want    string
+		options []Option
+	}{
+		{
+			name:    "struct",
+			in:      "{ $user.Name } { $user.Visits } { $user.Address.city } { $user.Tags.role }",
+			input:   map[string]any{"user": user},
+			options: []Option{WithDottedPaths()},
+			want:    "Jānis 1,000 Rīga admin",
+		},
+		{
+			name:    "map",
+			in:      "{ $a.b.c :string }",
+			input:   map[string]any{"a": map[string]any{"b": map[string]any{"c": "x"}}},
+			options: []Option{WithDottedPaths()},
+			want:    "x",
+		},
+		{
+			name:    "exact name",
+			in:      "{ $a.b }",
+			input:   map[string]any{"a.b": "exact", "a": map[string]any{"b": "path"}},
+			options: []Option{WithDottedPaths()},
+			want:    "exact",
+		},
+		{
+			name: "declaration",
+			in:   ".input { $user.Visits :number } .match { $user.Visits } one {{one}} * {{{ $user.Visits }}}",
+			input: &struct {
+				User User `mf2:"user"`
+			}{User: user},
+			options: []Option{WithDottedPaths()},
+			want:    "1,000",
+		},
+		{
+			name:    "missing",
+			in:      "{ $user.Email }",
+			input:   map[string]any{"user": user},
+			options: []Option{WithDottedPaths()},
+			want:    "{$user.Email}",
+			wantErr: mf2.ErrUnresolvedVariable,
+		},
+		{
+			name:    "disabled",
+			in:      "{ $user.Name }",
+			input:   map[string]any{"user": user},
+			want:    "{$user.Name}",
+			wantErr: mf2.ErrUnresolvedVariable,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			tmpl, err := New(test.options...).Parse(test.in)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := tmpl.Sprint(test.input)
+			if !errors.Is(err, test.wantErr) {
+				t.Errorf("want '%v', got '%v'", test.wantErr, err)
+			}
+
+			if test.want != got {
+				t.Errorf("want '%s', got '%s'", test.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package template
+
+import (
+	ast "go.expect.digital/mf2/parse"
+)
+
+// WithAllowedFuncs restricts the functions the message may call, e.g. the messages of semi-trusted authors.
+// The names are the function names, e.g. "number", or the namespaces, e.g. "ns:*". The call of
+// another function resolves to the fallback representation with [mf2.ErrUnsupportedExpression].
+// Without the option all functions of the registry are allowed. The formatting of the operands without
+// the function, e.g. "{ $name }", is always allowed.
+//
+// The option can be given several times, the names are added.
+func WithAllowedFuncs(names ...string) Option {
+	return func(t *Template) {
+		if t.allowedFuncs == nil {
+			t.allowedFuncs = make(map[string]struct{}, len(names))
+		}
+
+		for _, name := range names {
+			t.allowedFuncs[name] = struct{}{}
+		}
+	}
+}
+
+// allowedFunc reports whether the message may call the function, see [WithAllowedFuncs].
+func (t *Template) allowedFunc(function ast.Identifier) bool {
+	if t.allowedFuncs == nil {
+		return true
+	}
+
+	if _, ok := t.allowedFuncs[function.String()]; ok {
+		return true
+	}
+
+	_, ok := t.allowedFuncs[function.Namespace+":*"]
+
+	return ok && function.Namespace != ""
+}
@@ -0,0 +1,60 @@
+package template
+
+import (
+	"errors"
+	"testing"
+
+	"golang.org/x/text/language"
+
+	"go.expect.digital/mf2"
+)
+
+func TestAllowedFuncs(t *testing.T) {
+	t.Parallel()
+
+	echo := func(operand *ResolvedValue, _ Options, _ language.Tag) (*ResolvedValue, error) {
+		return operand, nil
+	}
+
+	for _, test := range []struct {
+		wantErr error
+		in      string
+		want    string
+		options []Option
+	}{
+		{in: "{ $n :number } { $s }", options: []Option{WithAllowedFuncs("number")}, want: "1 a"},
+		{in: "{ $s :upper }", options: []Option{WithAllowedFuncs("number")}, want: "{$s}", wantErr: mf2.ErrUnsupportedExpression},
+		{in: "{ $s :upper }", options: []Option{WithAllowedFuncs()}, want: "{$s}", wantErr: mf2.ErrUnsupportedExpression},
+		{in: "{ $s :upper }", want: "A"},
+		{in: "{ $s :x:echo }", options: []Option{WithAllowedFuncs("x:*")}, want: "a"},
+		{in: "{ $s :x:echo }", options: []Option{WithAllowedFuncs("x:echo")}, want: "a"},
+		{in: "{ $s :x:echo }", options: []Option{WithAllowedFuncs("echo")}, want: "{$s}", wantErr: mf2.ErrUnsupportedExpression},
+		{
+			in:      ".match { $s :string } a {{a}} * {{other}}",
+			options: []Option{WithAllowedFuncs("number")},
+			want:    "",
+			wantErr: mf2.ErrUnsupportedExpression,
+		},
+		{
+			in:      ".local $u = { $s :upper } {{{ $u }}}",
+			options: []Option{WithAllowedFuncs("number"), WithAllowedFuncs("string")},
+			want:    "{$s}",
+			wantErr: mf2.ErrUnsupportedExpression,
+		},
+	} {
+		t.Run(test.in, func(t *testing.T) {
+			t.Parallel()
+
+			options := append([]Option{WithFunc("echo", echo)}, test.options...)
+
+			got, err := MustParse(test.in, options...).Sprint(map[string]any{"n": 1, "s": "a"})
+			if !errors.Is(err, test.wantErr) {
+				t.Errorf("want '%v', got '%v'", test.wantErr, err)
+			}
+
+			if test.want != got {
+				t.Errorf("want '%s', got '%s'", test.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,336 @@
+package template
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"golang.org/x/text/feature/plural"
+	"golang.org/x/text/language"
+
+	"go.expect.digital/mf2"
+	ast "go.expect.digital/mf2/parse"
+)
+
+// UnreachableVariant is the variant of the matcher that can never be selected.
+type UnreachableVariant struct {
+	// Reason describes why the key never matches, e.g. `plural category "many" is not used in "en"`.
+	Reason string
+	// Keys are the keys of the variant as written in the message.
+	Keys []string
+	// Index is the position of the variant in the matcher.
+	Index int
+	// Selector is the position of the selector that never selects the key.
+	Selector int
+}
+
+// Diagnostic returns the warning of the unreachable variant, the code is "unreachable-variant".
+func (v UnreachableVariant) Diagnostic(span mf2.Span) mf2.Diagnostic {
+	return mf2.Diagnostic{
+		Severity: mf2.SeverityWarning,
+		Code:     "unreachable-variant",
+		Message:  fmt.Sprintf("unreachable variant %s: %s", strings.Join(v.Keys, " "), v.Reason),
+		Span:     span,
+	}
+}
+
+// Unreachable returns the variants of the parsed message that can never be selected
+// in the template locale, see [UnreachableVariants].
+func (t *Template) Unreachable() []UnreachableVariant {
+	if t.ast == nil {
+		return nil
+	}
+
+	return UnreachableVariants(*t.ast, t.locale, t.possibleKeys)
+}
+
+// UnreachableVariants returns the variants that can never be selected given the possible results
+// of the selector functions of the default registry in the locale:
+//   - the plural categories not used by the locale, e.g. `many` in English, or not possible for
+//     integers, e.g. `other` in Russian for :integer;
+//   - the plural categories and other non-numeric keys of :number and :integer with select=exact;
+//   - the numeric keys shadowed by an earlier key of the same value, e.g. |1.0| after 1;
+//   - the keys :boolean, or the custom functions declared in possible keys, never select.
+//
+// The selectors with the undeclared custom functions, or the options set by variables, are not analysed.
+// If the locale is [language.Und], every plural category is considered possible.
+func UnreachableVariants(tree ast.AST, locale language.Tag, possible ...PossibleKeys) []UnreachableVariant {
+	message, ok := tree.Message.(ast.ComplexMessage)
+	if !ok {
+		return nil
+	}
+
+	matcher, ok := message.ComplexBody.(ast.Matcher)
+	if !ok {
+		return nil
+	}
+
+	declarations := make(map[ast.Variable]ast.Expression, len(message.Declarations))
+
+	for _, declaration := range message.Declarations {
+		switch d := declaration.(type) {
+		case ast.InputDeclaration:
+			if v, ok := d.Operand.(ast.Variable); ok {
+				declarations[v] = ast.Expression(d)
+			}
+		case ast.LocalDeclaration:
+			declarations[d.Variable] = d.Expression
+		}
+	}
+
+	reachable := make([]func(key string, earlier []string) (string, bool), len(matcher.Selectors))
+	for i, selector := range matcher.Selectors {
+		if reachable[i] = numberKeys(selector, declarations, locale); reachable[i] == nil {
+			reachable[i] = declaredKeys(selector, declarations, possible)
+		}
+	}
+
+	var unreachable []UnreachableVariant
+
+	plan := newSelectionPlan(matcher)
+
+	for i, variant := range plan.variants {
+		for j, key := range variant.keys {
+			if j >= len(reachable) || reachable[j] == nil || variant.catchAll[j] {
+				continue
+			}
+
+			if reason, ok := reachable[j](key, plan.keys[j][:slices.Index(plan.keys[j], key)]); !ok {
+				keys := make([]string, len(matcher.Variants[i].Keys))
+				for k, key := range matcher.Variants[i].Keys {
+					keys[k] = key.String()
+				}
+
+				unreachable = append(unreachable, UnreachableVariant{Index: i, Selector: j, Keys: keys, Reason: reason})
+
+				break
+			}
+		}
+	}
+
+	return unreachable
+}
+
+// numberKeys returns the check of the selector keys if the selector is :number or :integer,
+// otherwise nil. The check gets the key and the unique keys of the selector before it.
+func numberKeys(
+	selector ast.Expression,
+	declarations map[ast.Variable]ast.Expression,
+	locale language.Tag,
+) func(key string, earlier []string) (string, bool) {
+	name, options, ok := selectorFunction(selector, declarations)
+	if !ok || (name != "number" && name != "integer") {
+		return nil
+	}
+
+	literal := func(name string) (string, bool) {
+		v, ok := options[name]
+		if !ok {
+			return "", true
+		}
+
+		switch v := v.(type) {
+		default: // variable
+			return "", false
+		case ast.NameLiteral:
+			return string(v), true
+		case ast.QuotedLiteral:
+			return string(v), true
+		case ast.NumberLiteral:
+			return v.String(), true
+		}
+	}
+
+	selection, ok := literal("select")
+	if !ok {
+		return nil
+	}
+
+	digits, ok := literal("maximumFractionDigits")
+	if !ok {
+		return nil
+	}
+
+	ordinal := selection == "ordinal"
+	integer := name == "integer" || digits == "0" || ordinal
+	categories := pluralCategories(locale, ordinal, integer)
+
+	return func(key string, earlier []string) (string, bool) {
+		if v, err := strconv.ParseFloat(key, 64); err == nil && !math.IsInf(v, 0) {
+			for _, k := range earlier {
+				if w, err := strconv.ParseFloat(k, 64); err == nil && v == w {
+					return fmt.Sprintf(`key "%s" is shadowed by the equal key "%s"`, key, k), false
+				}
+			}
+
+			return "", true
+		}
+
+		if selection == "exact" {
+			return fmt.Sprintf(`key "%s" is not a number, :%s selects only the exact value`, key, name), false
+		}
+
+		if _, ok := categories[key]; ok {
+			return "", true
+		}
+
+		if _, ok := allPluralCategories[key]; !ok {
+			return fmt.Sprintf(`key "%s" is neither a number nor a plural category`, key), false
+		}
+
+		kind := "integer "
+		if !integer {
+			kind = ""
+		}
+
+		return fmt.Sprintf(`plural category "%s" is not used for %snumbers in "%s"`, key, kind, locale), false
+	}
+}
+
+// declaredKeys returns the check of the selector keys if the selector function
+// declares its possible keys, otherwise nil. The later declarations win.
+func declaredKeys(
+	selector ast.Expression,
+	declarations map[ast.Variable]ast.Expression,
+	possible []PossibleKeys,
+) func(key string, earlier []string) (string, bool) {
+	name, _, ok := selectorFunction(selector, declarations)
+	if !ok {
+		return nil
+	}
+
+	keys, ok := builtinKeys[name]
+
+	for _, p := range possible {
+		if k, found := p[name]; found {
+			keys, ok = k, true
+		}
+	}
+
+	if !ok {
+		return nil
+	}
+
+	return func(key string, _ []string) (string, bool) {
+		for _, k := range keys {
+			if NormalizeKey(k) == key {
+				return "", true
+			}
+		}
+
+		return fmt.Sprintf(`key "%s" is never selected by :%s`, key, name), false
+	}
+}
+
+// selectorFunction returns the name and literal options of the selector function, following the
+// variable through its declarations. The options of the nearer expression win.
+func selectorFunction(
+	expr ast.Expression,
+	declarations map[ast.Variable]ast.Expression,
+) (string, map[string]ast.Value, bool) {
+	var (
+		name    string
+		options = make(map[string]ast.Value)
+	)
+
+	// the input declaration refers to its own variable, the chain is not longer than the declarations
+	for range len(declarations) + 1 {
+		if f, ok := expr.Annotation.(ast.Function); ok {
+			if name == "" {
+				name = f.Identifier.String() // the namespaced function is not a built-in
+			}
+
+			for _, o := range f.Options {
+				if _, ok := options[o.Identifier.String()]; !ok {
+					options[o.Identifier.String()] = o.Value
+				}
+			}
+		} else if expr.Annotation != nil {
+			return "", nil, false // reserved or private-use
+		}
+
+		v, ok := expr.Operand.(ast.Variable)
+		if !ok {
+			break
+		}
+
+		next, ok := declarations[v]
+		if !ok {
+			break
+		}
+
+		expr = next
+	}
+
+	return name, options, name != ""
+}
+
+var allPluralCategories = map[string]struct{}{
+	"zero": {}, "one": {}, "two": {}, "few": {}, "many": {}, "other": {},
+}
+
+type pluralKey struct {
+	locale           language.Tag
+	ordinal, integer bool
+}
+
+// pluralCache caches the plural categories by pluralKey.
+var pluralCache sync.Map
+
+// pluralCategories returns the plural categories used by the locale, all categories if the locale is und.
+// The categories are collected by matching the sample numbers, CLDR rules do not list them.
+func pluralCategories(locale language.Tag, ordinal, integer bool) map[string]struct{} {
+	if locale == language.Und {
+		return allPluralCategories
+	}
+
+	key := pluralKey{locale: locale, ordinal: ordinal, integer: integer}
+
+	if v, ok := pluralCache.Load(key); ok {
+		return v.(map[string]struct{}) //nolint:forcetypeassert
+	}
+
+	rules := plural.Cardinal
+	if ordinal {
+		rules = plural.Ordinal
+	}
+
+	categories := make(map[string]struct{})
+	add := func(i, v, w, f, t int) {
+		categories[pluralFormString(rules.MatchPlural(locale, i, v, w, f, t))] = struct{}{}
+	}
+
+	integers := []int{10_000, 100_000, 1_000_000, 10_000_000, 100_000_000}
+	for i := range 1001 {
+		integers = append(integers, i)
+	}
+
+	for _, i := range integers {
+		add(i, 0, 0, 0, 0)
+	}
+
+	if !integer {
+		// v and f are the visible fraction digits, w and t are without the trailing zeros
+		for i := range 111 {
+			for f := range 100 {
+				t, w := f, 2
+				for ; w > 0 && t%10 == 0; w-- {
+					t /= 10
+				}
+
+				add(i, 2, w, f, t) //nolint:mnd
+
+				if f%10 == 0 {
+					add(i, 1, w, f/10, t) //nolint:mnd
+				}
+			}
+		}
+	}
+
+	v, _ := pluralCache.LoadOrStore(key, categories)
+
+	return v.(map[string]struct{}) //nolint:forcetypeassert
+}
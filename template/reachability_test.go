@@ -0,0 +1,111 @@
+package template
+
+import (
+	"testing"
+
+	"golang.org/x/text/language"
+)
+
+func TestUnreachable(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		name   string
+		in     string
+		locale language.Tag
+		want   []string // reasons
+	}{
+		{
+			name:   "all reachable",
+			locale: language.English,
+			in:     ".match {$n :number} 0 {{zero}} one {{one}} * {{other}}",
+		},
+		{
+			name:   "category not in locale",
+			locale: language.English,
+			in:     ".match {$n :number} one {{one}} many {{many}} * {{other}}",
+			want:   []string{`plural category "many" is not used for numbers in "en"`},
+		},
+		{
+			name:   "category in locale",
+			locale: language.Latvian,
+			in:     ".match {$n :number} zero {{zero}} one {{one}} * {{other}}",
+		},
+		{
+			name:   "integer",
+			locale: language.Russian,
+			in:     ".match {$n :integer} one {{one}} few {{few}} many {{many}} * {{other}}",
+		},
+		{
+			name:   "integer only category",
+			locale: language.Russian,
+			in:     ".input {$n :integer} .match {$n} one {{one}} other {{other}} * {{*}}",
+			want:   []string{`plural category "other" is not used for integer numbers in "ru"`},
+		},
+		{
+			name:   "ordinal",
+			locale: language.English,
+			in:     ".match {$n :number select=ordinal} one {{st}} two {{nd}} few {{rd}} many {{}} * {{th}}",
+			want:   []string{`plural category "many" is not used for integer numbers in "en"`},
+		},
+		{
+			name:   "exact",
+			locale: language.English,
+			in:     ".local $x = {$n :number select=exact} .match {$x} 1 {{1}} one {{one}} * {{other}}",
+			want:   []string{`key "one" is not a number, :number selects only the exact value`},
+		},
+		{
+			name:   "shadowed",
+			locale: language.English,
+			in:     ".match {$n :number} {$s :string} 1 a {{1}} |1.0| b {{1.0}} foo * {{foo}} * * {{other}}",
+			want: []string{
+				`key "1.0" is shadowed by the equal key "1"`,
+				`key "foo" is neither a number nor a plural category`,
+			},
+		},
+		{
+			name: "any locale",
+			in:   ".match {$n :number} many {{many}} * {{other}}",
+		},
+		{
+			name:   "variable option",
+			locale: language.English,
+			in:     ".input {$s} .match {$n :number select=$s} many {{many}} * {{other}}",
+		},
+		{
+			name: "boolean",
+			in:   ".input {$b :boolean} .match {$b} true {{yes}} yes {{yes}} * {{other}}",
+			want: []string{`key "yes" is never selected by :boolean`},
+		},
+		{
+			name: "declared keys",
+			in:   ".match {$c :color} {$b :x:color} red green {{red}} blue * {{blue}} * * {{other}}",
+			want: []string{`key "blue" is never selected by :color`},
+		},
+		{
+			name: "undeclared keys",
+			in:   ".match {$c :shape} circle {{circle}} * {{other}}",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			tmpl, err := New(WithLocale(test.locale), WithPossibleKeys("color", "red", "green")).Parse(test.in)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := tmpl.Unreachable()
+
+			if len(test.want) != len(got) {
+				t.Fatalf("want %d unreachable variants, got %v", len(test.want), got)
+			}
+
+			for i, want := range test.want {
+				if got[i].Reason != want {
+					t.Errorf("want '%s', got '%s'", want, got[i].Reason)
+				}
+			}
+		})
+	}
+}
@@ -1,15 +1,23 @@
 package template
 
 import (
+	"errors"
 	"fmt"
+	"maps"
+	"math"
 	"reflect"
 	"slices"
 	"strconv"
+	"sync"
 	"time"
 
 	"golang.org/x/exp/constraints"
 	"golang.org/x/text/feature/plural"
 	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+
+	"go.expect.digital/mf2"
+	"go.expect.digital/mf2/internal/mapkeys"
 )
 
 // See ".message-format-wg/spec/registry.xml".
@@ -21,12 +29,33 @@ type Registry map[string]Func
 // Options are a possible options for the function.
 type Options map[string]*ResolvedValue
 
+// Names returns the sorted option names. The functions iterate the options in this order
+// to report the same error for the same options on every execution.
+func (o Options) Names() []string {
+	return mapkeys.Sorted(o)
+}
+
+// optionErrorf returns the error of the option getters, it wraps [mf2.ErrBadOption].
+func optionErrorf(typ, name, format string, args ...any) error {
+	return badOption(fmt.Errorf(`get %s option "%s": `+format, append([]any{typ, name}, args...)...))
+}
+
+// badOption wraps the error with [mf2.ErrBadOption], unless it is wrapped already,
+// e.g. the error of the option getters or the validator.
+func badOption(err error) error {
+	if errors.Is(err, mf2.ErrBadOption) {
+		return err
+	}
+
+	return fmt.Errorf("%w: %w", mf2.ErrBadOption, err)
+}
+
 // GetString returns the value by name.
 // If the value is not found, returns the fallback value.
 // If the value is not in allowed list, return error.
 func (o Options) GetString(name, fallback string, validate ...Validate[string]) (string, error) {
 	errorf := func(format string, args ...any) (string, error) {
-		return "", fmt.Errorf(`get string option "%s": `+format, append([]any{name}, args...)...)
+		return "", optionErrorf("string", name, format, args...)
 	}
 
 	v, ok := o[name]
@@ -53,7 +82,7 @@ func (o Options) GetString(name, fallback string, validate ...Validate[string])
 // If the value is not in allowed list, return error.
 func (o Options) GetInt(name string, fallback int, validate ...Validate[int]) (int, error) {
 	errorf := func(format string, args ...any) (int, error) {
-		return 0, fmt.Errorf(`get int option "%s": `+format, append([]any{name}, args...)...)
+		return 0, optionErrorf("int", name, format, args...)
 	}
 
 	v, ok := o[name]
@@ -74,7 +103,7 @@ func (o Options) GetInt(name string, fallback int, validate ...Validate[int]) (i
 	case string:
 		n64, err := strconv.ParseInt(n, 10, 64)
 		if err != nil {
-			return 0, fmt.Errorf(`parse integer from string "%s": %w`, n, err)
+			return errorf(`parse integer from string "%s": %w`, n, err)
 		}
 
 		i = int(n64)
@@ -91,18 +120,168 @@ func (o Options) GetInt(name string, fallback int, validate ...Validate[int]) (i
 	return i, nil
 }
 
+// GetFloat returns the value by name, the number or the string of the number.
+// If the value is not found, returns the fallback value.
+// If the value is not in allowed list, return error.
+func (o Options) GetFloat(name string, fallback float64, validate ...Validate[float64]) (float64, error) {
+	errorf := func(format string, args ...any) (float64, error) {
+		return 0, optionErrorf("float", name, format, args...)
+	}
+
+	v, ok := o[name]
+	if !ok {
+		return fallback, nil
+	}
+
+	var (
+		number float64
+		err    error
+	)
+
+	switch n := v.value.(type) {
+	default:
+		if number, err = castAs[float64](n); err != nil {
+			return errorf("%w", err)
+		}
+	case string:
+		number, err = strconv.ParseFloat(n, 64)
+		if err != nil || math.IsNaN(number) || math.IsInf(number, 0) {
+			return errorf(`want number, got "%s"`, n)
+		}
+	}
+
+	for _, f := range validate {
+		if err := f(number); err != nil {
+			return errorf("%w", err)
+		}
+	}
+
+	return number, nil
+}
+
+// GetBool returns the value by name, the bool or the string "true" or "false".
+// If the value is not found, returns the fallback value.
+func (o Options) GetBool(name string, fallback bool) (bool, error) {
+	v, ok := o[name]
+	if !ok {
+		return fallback, nil
+	}
+
+	switch b := v.value.(type) {
+	default:
+		return false, optionErrorf("bool", name, "got %T", b)
+	case bool:
+		return b, nil
+	case string:
+		switch b {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		}
+
+		return false, optionErrorf("bool", name, `want "true" or "false", got "%s"`, b)
+	}
+}
+
+// GetDuration returns the value by name, the [time.Duration] or the string parsed by [time.ParseDuration],
+// e.g. "1h30m". If the value is not found, returns the fallback value.
+// If the value is not in allowed list, return error.
+func (o Options) GetDuration(
+	name string, fallback time.Duration, validate ...Validate[time.Duration],
+) (time.Duration, error) {
+	errorf := func(format string, args ...any) (time.Duration, error) {
+		return 0, optionErrorf("duration", name, format, args...)
+	}
+
+	v, ok := o[name]
+	if !ok {
+		return fallback, nil
+	}
+
+	var d time.Duration
+
+	switch t := v.value.(type) {
+	default:
+		return errorf("got %T", t)
+	case time.Duration:
+		d = t
+	case string:
+		var err error
+
+		if d, err = time.ParseDuration(t); err != nil {
+			return errorf("%w", err)
+		}
+	}
+
+	for _, f := range validate {
+		if err := f(d); err != nil {
+			return errorf("%w", err)
+		}
+	}
+
+	return d, nil
+}
+
+// GetTag returns the value by name, the [language.Tag] or the string of the BCP 47 language tag.
+// If the value is not found, returns the fallback value.
+func (o Options) GetTag(name string, fallback language.Tag) (language.Tag, error) {
+	v, ok := o[name]
+	if !ok {
+		return fallback, nil
+	}
+
+	switch t := v.value.(type) {
+	default:
+		return language.Und, optionErrorf("tag", name, "got %T", t)
+	case language.Tag:
+		return t, nil
+	case string:
+		tag, err := language.Parse(t)
+		if err != nil {
+			return language.Und, optionErrorf("tag", name, "%w", err)
+		}
+
+		return tag, nil
+	}
+}
+
+// GetEnum returns the value by name, one of the values.
+// If the value is not found, returns the fallback value.
+func (o Options) GetEnum(name, fallback string, values ...string) (string, error) {
+	return o.GetString(name, fallback, oneOf(values...))
+}
+
 // NewRegistry returns a new registry with default functions.
 func NewRegistry() Registry {
 	return Registry{
+		"boolean":  booleanFunc,
+		"casing":   casingFunc,
+		"count":    countFunc,
 		"date":     dateFunc,
 		"datetime": datetimeFunc,
 		"integer":  integerFunc,
+		"lower":    lowerFunc,
 		"number":   numberFunc,
+		"percent":  percentFunc,
+		"range":    rangeFunc,
 		"string":   stringFunc,
 		"time":     timeFunc,
+		"upper":    upperFunc,
 	}
 }
 
+// setFunc sets the function of the template registry, the registry is copied if shared
+// with the compiled message, see [Template.Parse].
+func (t *Template) setFunc(name string, f Func) {
+	if t.sharedRegistry {
+		t.registry = maps.Clone(t.registry)
+		t.sharedRegistry = false
+	}
+
+	t.registry[name] = f
+}
+
 type Validate[T any] func(T) error
 
 func oneOf[T comparable](values ...T) func(T) error {
@@ -135,8 +314,8 @@ func castAs[T any](val any) (T, error) {
 	typ := reflect.TypeOf(zeroVal)
 
 	v := (reflect.ValueOf(val))
-	if !v.Type().ConvertibleTo(typ) {
-		return zeroVal, fmt.Errorf("convert %v to %T", v.Type(), zeroVal)
+	if !v.IsValid() || !v.Type().ConvertibleTo(typ) {
+		return zeroVal, fmt.Errorf("convert %T to %T", val, zeroVal)
 	}
 
 	v = v.Convert(typ)
@@ -157,13 +336,43 @@ func getTZ(options Options) (*time.Location, error) {
 	case *time.Location:
 		return tz, nil
 	case string:
-		timezone, err := time.LoadLocation(tz)
-		if err != nil {
-			return nil, fmt.Errorf("load TZ data for %s: %w", tz, err)
-		}
+		return loadLocation(tz)
+	}
+}
+
+// Printers and time zones are constructed once and shared by all templates,
+// constructing them on every placeholder is expensive.
+var (
+	printers  sync.Map // language.Tag -> *message.Printer
+	locations sync.Map // string -> *time.Location
+)
 
-		return timezone, nil
+// printer returns the shared printer of the locale.
+func printer(locale language.Tag) *message.Printer {
+	if p, ok := printers.Load(locale); ok {
+		return p.(*message.Printer) //nolint:forcetypeassert
 	}
+
+	p, _ := printers.LoadOrStore(locale, message.NewPrinter(locale))
+
+	return p.(*message.Printer) //nolint:forcetypeassert
+}
+
+// loadLocation returns the shared time zone by name, see [time.LoadLocation].
+// Unknown time zones are not cached.
+func loadLocation(name string) (*time.Location, error) {
+	if loc, ok := locations.Load(name); ok {
+		return loc.(*time.Location), nil //nolint:forcetypeassert
+	}
+
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("load TZ data for %s: %w", name, err)
+	}
+
+	locations.Store(name, loc)
+
+	return loc, nil
 }
 
 // pluralFormString formats plural.Form as string.
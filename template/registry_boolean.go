@@ -0,0 +1,68 @@
+package template
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/text/language"
+
+	"go.expect.digital/mf2"
+)
+
+// booleanFunc is the implementation of the boolean function, e.g. ".match {$isAdmin :boolean}".
+// Normalizes boolean-ish operands - bool, "true"/"false", "yes"/"no", "on"/"off" and 1/0 -
+// to a bool that formats and selects as "true" or "false".
+func booleanFunc(operand *ResolvedValue, options Options, _ language.Tag) (*ResolvedValue, error) {
+	errorf := func(format string, args ...any) (*ResolvedValue, error) {
+		return nil, fmt.Errorf("exec boolean func: "+format, args...)
+	}
+
+	if operand.value == nil {
+		return errorf("operand is required: %w", mf2.ErrBadOperand)
+	}
+
+	if len(options) > 0 {
+		return errorf("%w: want no options", mf2.ErrBadOption)
+	}
+
+	b, err := parseBool(operand)
+	if err != nil {
+		return errorf("%w: %w", mf2.ErrBadOperand, err)
+	}
+
+	return NewResolvedValue(b), nil
+}
+
+// parseBool normalizes the operand to a bool.
+func parseBool(operand *ResolvedValue) (bool, error) {
+	switch v := operand.value.(type) {
+	case bool:
+		return v, nil
+	case string:
+		return parseBoolString(v)
+	}
+
+	if n, err := castAs[float64](operand.value); err == nil {
+		switch n {
+		case 0:
+			return false, nil
+		case 1:
+			return true, nil
+		}
+
+		return false, fmt.Errorf("want 0 or 1, got %v", n)
+	}
+
+	return parseBoolString(operand.String())
+}
+
+func parseBoolString(s string) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "true", "yes", "on", "1":
+		return true, nil
+	case "false", "no", "off", "0":
+		return false, nil
+	}
+
+	return false, fmt.Errorf(`want boolean, got "%s"`, s)
+}
@@ -0,0 +1,58 @@
+package template
+
+import (
+	"testing"
+
+	"golang.org/x/text/language"
+)
+
+func Test_Boolean(t *testing.T) {
+	t.Parallel()
+
+	const match = ".match { $x :boolean } true {{yes}} false {{no}} * {{other}}"
+
+	for _, test := range []struct {
+		input   map[string]any
+		name    string
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{name: "format", in: "{ $x :boolean }", input: map[string]any{"x": true}, want: "true"},
+		{name: "format literal", in: "{ |Yes| :boolean }", want: "true"},
+		{name: "bool", in: match, input: map[string]any{"x": false}, want: "no"},
+		{name: "true", in: match, input: map[string]any{"x": "TRUE"}, want: "yes"},
+		{name: "yes", in: match, input: map[string]any{"x": "yes"}, want: "yes"},
+		{name: "off", in: match, input: map[string]any{"x": "off"}, want: "no"},
+		{name: "one", in: match, input: map[string]any{"x": 1}, want: "yes"},
+		{name: "zero", in: match, input: map[string]any{"x": 0.0}, want: "no"},
+		{name: "string zero", in: match, input: map[string]any{"x": "0"}, want: "no"},
+		{
+			name:  "local",
+			in:    ".local $y = { $x :boolean } .match { $y :string } true {{yes}} * {{other}}",
+			input: map[string]any{"x": "on"},
+			want:  "yes",
+		},
+		{name: "invalid", in: match, input: map[string]any{"x": "maybe"}, wantErr: true},
+		{name: "invalid number", in: match, input: map[string]any{"x": 2}, wantErr: true},
+		{name: "missing operand", in: "{ :boolean }", want: "{:boolean}", wantErr: true},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			template, err := New(WithLocale(language.English)).Parse(test.in)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := template.Sprint(test.input)
+			if test.wantErr != (err != nil) {
+				t.Errorf("want error %t, got '%v'", test.wantErr, err)
+			}
+
+			if test.want != got {
+				t.Errorf("want '%s', got '%s'", test.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package template
+
+import (
+	"fmt"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+
+	"go.expect.digital/mf2"
+)
+
+// casingFunc is the implementation of the casing function, e.g. ":u:casing style=upper".
+// Locale-sensitive case transformation of the formatted operand, it formats
+// and selects as a string, and composes with other functions, e.g. ".local $n = {$x :number}".
+func casingFunc(operand *ResolvedValue, options Options, locale language.Tag) (*ResolvedValue, error) {
+	errorf := func(format string, args ...any) (*ResolvedValue, error) {
+		return nil, fmt.Errorf("exec casing func: "+format, args...)
+	}
+
+	if operand.value == nil {
+		return errorf("operand is required: %w", mf2.ErrBadOperand)
+	}
+
+	style, err := options.GetEnum("style", "", "upper", "lower", "title")
+	if err != nil {
+		return errorf("%w", err)
+	}
+
+	var caser cases.Caser // not safe for concurrent use, created on every call
+
+	switch style {
+	default:
+		return errorf(`%w: want option "style"`, mf2.ErrBadOption)
+	case "upper":
+		caser = cases.Upper(locale)
+	case "lower":
+		caser = cases.Lower(locale)
+	case "title":
+		caser = cases.Title(locale)
+	}
+
+	return NewResolvedValue(caser.String(operand.String())), nil
+}
+
+// upperFunc is the implementation of the upper function, the same as ":u:casing style=upper".
+func upperFunc(operand *ResolvedValue, _ Options, locale language.Tag) (*ResolvedValue, error) {
+	return casingFunc(operand, Options{"style": NewResolvedValue("upper")}, locale)
+}
+
+// lowerFunc is the implementation of the lower function, the same as ":u:casing style=lower".
+func lowerFunc(operand *ResolvedValue, _ Options, locale language.Tag) (*ResolvedValue, error) {
+	return casingFunc(operand, Options{"style": NewResolvedValue("lower")}, locale)
+}
@@ -0,0 +1,61 @@
+package template
+
+import (
+	"testing"
+
+	"golang.org/x/text/language"
+)
+
+func Test_Casing(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		input   map[string]any
+		locale  language.Tag
+		name    string
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{name: "upper", in: "{ |hello| :u:casing style=upper }", want: "HELLO"},
+		{name: "lower", in: "{ |HELLO| :lower }", want: "hello"},
+		{name: "title", in: "{ |hello world| :casing style=title }", want: "Hello World"},
+		{name: "locale", in: "{ |istanbul| :upper }", locale: language.Turkish, want: "İSTANBUL"},
+		{
+			name:  "composed",
+			in:    ".local $date = { $d :datetime dateStyle=full } {{{ $date :upper }}}",
+			input: map[string]any{"d": testDate},
+			want:  "SATURDAY, 02 JANUARY 2021",
+		},
+		{
+			name: "select",
+			in:   ".local $x = { |a| :upper } .match { $x :string } A {{upper}} * {{other}}",
+			want: "upper",
+		},
+		{name: "missing style", in: "{ |hello| :casing }", want: "{|hello|}", wantErr: true},
+		{name: "missing operand", in: "{ :upper }", want: "{:upper}", wantErr: true},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			locale := test.locale
+			if locale == language.Und {
+				locale = language.English
+			}
+
+			template, err := New(WithLocale(locale)).Parse(test.in)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := template.Sprint(test.input)
+			if test.wantErr != (err != nil) {
+				t.Errorf("want error %t, got '%v'", test.wantErr, err)
+			}
+
+			if test.want != got {
+				t.Errorf("want '%s', got '%s'", test.want, got)
+			}
+		})
+	}
+}
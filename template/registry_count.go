@@ -0,0 +1,50 @@
+package template
+
+import (
+	"fmt"
+	"reflect"
+
+	"golang.org/x/text/language"
+
+	"go.expect.digital/mf2"
+)
+
+// countFunc is the implementation of the count function, e.g. ".match {$items :count}".
+// The operand is a slice, an array or a map, it formats and selects as the integer of its length,
+// so "no items / one item / N items" messages select on the collection itself:
+//
+//	.match {$items :count}
+//	0 {{No items}}
+//	one {{{$items :count} item}}
+//	* {{{$items :count} items}}
+//
+// The options are the options of :integer, e.g. "{$items :count select=exact}".
+func countFunc(operand *ResolvedValue, options Options, locale language.Tag) (*ResolvedValue, error) {
+	return formatCount(operand, options, locale, numberFunc)
+}
+
+// formatCount formats the length of the collection with the number function, see [countFunc].
+func formatCount(operand *ResolvedValue, options Options, locale language.Tag, number Func) (*ResolvedValue, error) {
+	errorf := func(format string, args ...any) (*ResolvedValue, error) {
+		return nil, fmt.Errorf("exec count func: "+format, args...)
+	}
+
+	if operand.value == nil {
+		return errorf("operand is required: %w", mf2.ErrBadOperand)
+	}
+
+	v := reflect.ValueOf(operand.value)
+
+	switch v.Kind() { //nolint:exhaustive
+	default:
+		return errorf("want slice, array or map, got %T: %w", operand.value, mf2.ErrBadOperand)
+	case reflect.Slice, reflect.Array, reflect.Map:
+	}
+
+	value, err := formatInteger(NewResolvedValue(v.Len()), options, locale, number)
+	if err != nil {
+		return errorf("%w", err)
+	}
+
+	return value, nil
+}
@@ -0,0 +1,68 @@
+package template
+
+import (
+	"testing"
+
+	"golang.org/x/text/language"
+)
+
+func Test_Count(t *testing.T) {
+	t.Parallel()
+
+	const match = ".match {$items :count} 0 {{No items}} one {{{$items :count} item}} * {{{$items :count} items}}"
+
+	for _, test := range []struct {
+		input   map[string]any
+		locale  language.Tag
+		name    string
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{name: "format", in: "{ $items :count }", input: map[string]any{"items": []string{"a", "b"}}, want: "2"},
+		{name: "empty", in: match, input: map[string]any{"items": []string{}}, want: "No items"},
+		{name: "nil slice", in: match, input: map[string]any{"items": []int(nil)}, want: "No items"},
+		{name: "one", in: match, input: map[string]any{"items": []any{1}}, want: "1 item"},
+		{name: "array", in: match, input: map[string]any{"items": [3]int{}}, want: "3 items"},
+		{name: "map", in: match, input: map[string]any{"items": map[string]int{"a": 1, "b": 2}}, want: "2 items"},
+		{name: "grouping", in: "{ $items :count }", input: map[string]any{"items": make([]byte, 1000)}, want: "1,000"},
+		{
+			name:   "locale",
+			in:     ".match {$items :count} one {{{$items :count} ābols}} * {{{$items :count} āboli}}",
+			input:  map[string]any{"items": make([]int, 21)},
+			locale: language.Latvian,
+			want:   "21 ābols",
+		},
+		{
+			name:  "local",
+			in:    ".local $n = {$items :count} .match {$n} one {{one}} * {{{$n} other}}",
+			input: map[string]any{"items": []int{1, 2}},
+			want:  "2 other",
+		},
+		{name: "string", in: match, input: map[string]any{"items": "abc"}, wantErr: true},
+		{name: "missing operand", in: "{ :count }", want: "{:count}", wantErr: true},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			locale := test.locale
+			if locale == language.Und {
+				locale = language.English
+			}
+
+			template, err := New(WithLocale(locale)).Parse(test.in)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := template.Sprint(test.input)
+			if test.wantErr != (err != nil) {
+				t.Errorf("want error %t, got '%v'", test.wantErr, err)
+			}
+
+			if test.want != got {
+				t.Errorf("want '%s', got '%s'", test.want, got)
+			}
+		})
+	}
+}
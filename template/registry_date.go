@@ -42,7 +42,17 @@ func parseDateOptions(options Options) (*dateOptions, error) {
 }
 
 // dateFunc is the implementation of the date function. Locale-sensitive date formatting.
-func dateFunc(operand *ResolvedValue, options Options, _ language.Tag) (*ResolvedValue, error) {
+func dateFunc(operand *ResolvedValue, options Options, locale language.Tag) (*ResolvedValue, error) {
+	return formatDate(operand, options, locale, XText{})
+}
+
+// formatDate formats the date with the formatter, see [dateFunc].
+func formatDate(
+	operand *ResolvedValue,
+	options Options,
+	locale language.Tag,
+	formatter DateTimeFormatter,
+) (*ResolvedValue, error) {
 	errorf := func(format string, args ...any) (*ResolvedValue, error) {
 		return nil, fmt.Errorf("exec date function: "+format, args...)
 	}
@@ -53,28 +63,15 @@ func dateFunc(operand *ResolvedValue, options Options, _ language.Tag) (*Resolve
 		return errorf("%w", err)
 	}
 
-	opts, err := parseDateOptions(options)
+	opts, err := cachedOptions(options, "date", func() (*dateOptions, error) { return parseDateOptions(options) })
 	if err != nil {
 		return errorf("%w", err)
 	}
 
 	format := func() string {
-		var layout string
-
-		switch opts.Style {
-		case "full":
-			layout = "Monday, 02 January 2006"
-		case "long":
-			layout = "02 January 2006"
-		case "medium":
-			layout = "02 Jan 2006"
-		case "short":
-			layout = "02/01/06"
-		}
-
 		value = value.In(opts.TimeZone)
 
-		return value.Format(layout)
+		return formatter.FormatDateTime(value, DateTimeFormat{DateStyle: opts.Style}, locale)
 	}
 
 	return NewResolvedValue(value, WithFormat(format)), nil
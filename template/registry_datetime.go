@@ -19,7 +19,7 @@ type datetimeOptions struct {
 	DateStyle string
 	// The predefined time formatting style to use (full, long, medium, short).
 	TimeStyle string
-	// The hour cycle to use (h11, h12, h23, h24).
+	// The hour cycle to use (h11, h12, h23, h24) or the "hour12" option, h23 by default.
 	HourCycle string
 	// DayPeriod is mentioned in registry.xml, but NOT in registry.md.
 	// See https://github.com/unicode-org/message-format-wg/issues/596
@@ -28,7 +28,7 @@ type datetimeOptions struct {
 	DayPeriod string
 	// The representation of the weekday (long, short, narrow).
 	Weekday string
-	// The representation of the era (long, short, narrow).
+	// The representation of the era (long, short, narrow), formatted after the date.
 	Era string
 	// The representation of the year (numeric, 2-digit).
 	Year string
@@ -44,9 +44,14 @@ type datetimeOptions struct {
 	Second string
 	// The localized representation of the time zone name
 	// (long, short, shortOffset, longOffset, shortGeneric, longGeneric).
+	// It replaces the time zone of the time style, see [timeZoneName].
 	TimeZoneName string
-	// The number of fractional seconds to display (1, 2, 3).
+	// The number of fractional seconds to display (1, 2, 3), truncated as in ECMA-402.
 	FractionalSecondDigits int
+	// The CLDR date-time skeleton, e.g. "yMMMd Hm", instead of the styles, see [skeletonPattern].
+	//
+	// NOTE: The option is not part of the default registry.
+	Skeleton string
 }
 
 // parseDatetimeOperand parses resolved operand value.
@@ -66,7 +71,7 @@ func parseDatetimeOperand(operand *ResolvedValue) (time.Time, error) {
 		return errorf("unsupported operand type %T", value)
 	case string:
 		// layout is quick and dirty, does not conform with ISO 8601 fully as required
-		t, err := time.Parse(time.RFC3339[:len(v)], v)
+		t, err := time.Parse(time.RFC3339[:min(len(v), len(time.RFC3339))], v)
 		if err != nil {
 			return errorf(`parse operand "%s"`, v)
 		}
@@ -77,20 +82,48 @@ func parseDatetimeOperand(operand *ResolvedValue) (time.Time, error) {
 	}
 }
 
-// parseDatetimeOptions parses :datetime options.
-func parseDatetimeOptions(options Options) (*datetimeOptions, error) {
-	errorf := func(format string, args ...any) (*datetimeOptions, error) {
-		return nil, fmt.Errorf("parse options: "+format, args...)
+// datetimeStyles are the date and time styles of :datetime without the style and field options.
+type datetimeStyles struct {
+	dateStyle, timeStyle string
+}
+
+// defaultDatetimeStyles are the default styles, {$d :datetime} is {$d :datetime dateStyle=medium timeStyle=short}.
+var defaultDatetimeStyles = datetimeStyles{dateStyle: "medium", timeStyle: "short"}
+
+// WithDateTimeDefaults sets the date and time styles of :datetime used when the expression has neither
+// the style nor the field options, e.g. the house style "long" and "short" instead of "medium" and "short".
+// The styles are "full", "long", "medium", "short", or empty to omit the date or the time.
+//
+// The option replaces the "datetime" function, the functions of [WithFuncs] set after it win.
+func WithDateTimeDefaults(dateStyle, timeStyle string) Option {
+	styles := datetimeStyles{dateStyle: dateStyle, timeStyle: timeStyle}
+
+	return func(t *Template) {
+		t.datetimeStyles = styles
+		t.setFunc("datetime", t.datetimeFunc())
 	}
+}
+
+// datetimeFunc returns the datetime function with the current styles and formatter of the template,
+// see [WithDateTimeDefaults] and [WithDateTimeFormatter].
+func (t *Template) datetimeFunc() Func {
+	styles, formatter := t.datetimeStyles, t.datetimeFormatter
 
-	if len(options) == 0 {
-		return &datetimeOptions{DateStyle: "medium", TimeStyle: "short"}, nil
+	return func(operand *ResolvedValue, options Options, locale language.Tag) (*ResolvedValue, error) {
+		return formatDatetime(operand, options, locale, styles, formatter)
+	}
+}
+
+// parseDatetimeOptions parses :datetime options, the styles are used without the style and field options.
+func parseDatetimeOptions(options Options, styles datetimeStyles) (*datetimeOptions, error) {
+	errorf := func(format string, args ...any) (*datetimeOptions, error) {
+		return nil, fmt.Errorf("parse options: "+format, args...)
 	}
 
-	for opt := range options {
+	for _, opt := range options.Names() {
 		switch opt {
-		case "calendar", "numberingSystem", "hourCycle", "dayPeriod", "weekday", "era",
-			"year", "month", "day", "hour", "minute", "second", "fractionalSecondDigits":
+		case "calendar", "numberingSystem", "dayPeriod", "weekday",
+			"year", "month", "day", "hour", "minute", "second":
 			return errorf(`option "%s" is not implemented`, opt)
 		}
 	}
@@ -114,8 +147,7 @@ func parseDatetimeOptions(options Options) (*datetimeOptions, error) {
 		return errorf("%w", err)
 	}
 
-	hourCycles := oneOf("h11", "h12", "h23", "h24")
-	if opts.HourCycle, err = options.GetString("hourCycle", "", hourCycles); err != nil {
+	if opts.HourCycle, err = parseHourCycle(options); err != nil {
 		return errorf("%w", err)
 	}
 
@@ -174,11 +206,40 @@ func parseDatetimeOptions(options Options) (*datetimeOptions, error) {
 		return errorf("%w", err)
 	}
 
+	if opts.Skeleton, err = options.GetString("skeleton", ""); err != nil {
+		return errorf("%w", err)
+	}
+
+	if opts.Skeleton != "" && (opts.DateStyle != "" || opts.TimeStyle != "" ||
+		opts.Era != "" || opts.FractionalSecondDigits != 0 || opts.TimeZoneName != "") {
+		return errorf(`%w: option "skeleton" excludes the style and field options`, mf2.ErrBadOption)
+	}
+
+	// the other field options are not implemented
+	if opts.DateStyle == "" && opts.TimeStyle == "" && opts.Skeleton == "" &&
+		opts.Era == "" && opts.FractionalSecondDigits == 0 && opts.TimeZoneName == "" {
+		opts.DateStyle, opts.TimeStyle = styles.dateStyle, styles.timeStyle
+	}
+
 	return &opts, nil
 }
 
 // datetimeFunc is the implementation of the datetime function. Locale-sensitive date and time formatting.
-func datetimeFunc(operand *ResolvedValue, options Options, _ language.Tag) (*ResolvedValue, error) {
+//
+// Without the style and field options the default styles are used, see [WithDateTimeDefaults].
+// The "skeleton" option is the CLDR date-time skeleton formatted with the locale's best pattern,
+// e.g. "{$d :datetime skeleton=|yMMMd jm|}" is "Jan 2, 2021, 3:04 AM" in American English.
+func datetimeFunc(operand *ResolvedValue, options Options, locale language.Tag) (*ResolvedValue, error) {
+	return formatDatetime(operand, options, locale, defaultDatetimeStyles, XText{})
+}
+
+func formatDatetime(
+	operand *ResolvedValue,
+	options Options,
+	locale language.Tag,
+	styles datetimeStyles,
+	formatter DateTimeFormatter,
+) (*ResolvedValue, error) {
 	errorf := func(format string, args ...any) (*ResolvedValue, error) {
 		return nil, fmt.Errorf("exec datetime function: "+format, args...)
 	}
@@ -188,47 +249,54 @@ func datetimeFunc(operand *ResolvedValue, options Options, _ language.Tag) (*Res
 		return errorf("%w", err)
 	}
 
-	opts, err := parseDatetimeOptions(options)
+	// the parsing depends on the styles
+	opts, err := cachedOptions(options, styles, func() (*datetimeOptions, error) {
+		return parseDatetimeOptions(options, styles)
+	})
 	if err != nil {
 		return errorf("%w", err)
 	}
 
-	format := func() string {
-		var layout string
-
-		switch opts.DateStyle {
-		case "full":
-			layout = "Monday, 02 January 2006"
-		case "long":
-			layout = "02 January 2006"
-		case "medium":
-			layout = "02 Jan 2006"
-		case "short":
-			layout = "02/01/06"
-		}
-
-		if len(opts.TimeStyle) > 0 {
-			if len(layout) > 0 {
-				layout += " "
+	// the skeletons and eras of the other formatters are resolved by the formatter
+	if _, ok := formatter.(XText); ok {
+		if opts.Skeleton != "" {
+			if _, err := skeletonPattern(opts.Skeleton, opts.HourCycle, locale); err != nil {
+				return errorf("%w: %w", mf2.ErrBadOption, err)
 			}
+		}
 
-			switch opts.TimeStyle {
-			case "full":
-				layout += "15:04:05 MST"
-			case "long":
-				layout += "15:04:05 -0700"
-			case "medium":
-				layout += "15:04:05"
-			case "short":
-				layout += "15:04"
-			}
+		if _, ok := localeEras(locale); opts.Era != "" && !ok {
+			return errorf(`%w: option "era" is not supported in locale %s`, mf2.ErrBadOption, locale)
 		}
+	}
 
+	// the era is formatted after the date, the fractional seconds after the seconds
+	if opts.Era != "" && opts.DateStyle == "" {
+		opts.DateStyle = "medium"
+	}
+
+	if opts.FractionalSecondDigits > 0 && (opts.TimeStyle == "" || opts.TimeStyle == "short") {
+		opts.TimeStyle = "medium"
+	}
+
+	if opts.TimeZoneName != "" && opts.DateStyle == "" && opts.TimeStyle == "" {
+		opts.DateStyle = "medium"
+	}
+
+	format := func() string {
 		if opts.TimeZone != nil {
 			value = value.In(opts.TimeZone)
 		}
 
-		return value.Format(layout)
+		return formatter.FormatDateTime(value, DateTimeFormat{
+			DateStyle:              opts.DateStyle,
+			TimeStyle:              opts.TimeStyle,
+			HourCycle:              opts.HourCycle,
+			Era:                    opts.Era,
+			TimeZoneName:           opts.TimeZoneName,
+			FractionalSecondDigits: opts.FractionalSecondDigits,
+			Skeleton:               opts.Skeleton,
+		}, locale)
 	}
 
 	return NewResolvedValue(value, WithFormat(format)), nil
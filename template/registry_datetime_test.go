@@ -1,10 +1,14 @@
 package template
 
 import (
+	"errors"
+	"strings"
 	"testing"
 	"time"
 
 	"golang.org/x/text/language"
+
+	"go.expect.digital/mf2"
 )
 
 var testDate = time.Date(2021, 1, 2, 3, 4, 5, 6, time.UTC)
@@ -97,3 +101,155 @@ func Test_Datetime(t *testing.T) {
 		})
 	}
 }
+
+func Test_DatetimeEraAndFractionalSeconds(t *testing.T) {
+	t.Parallel()
+
+	value := time.Date(2021, 1, 2, 15, 4, 5, 129_999_999, time.UTC)
+	bc := time.Date(-43, 3, 15, 0, 0, 0, 0, time.UTC) // 44 BC
+
+	for _, test := range []struct {
+		input   time.Time
+		options map[string]any
+		locale  language.Tag
+		want    string
+		wantErr bool
+	}{
+		{input: value, locale: language.BritishEnglish, options: map[string]any{"era": "short"}, want: "02 Jan 2021 AD"},
+		{input: bc, locale: language.BritishEnglish, options: map[string]any{"era": "long"}, want: "15 Mar 0044 Before Christ"},
+		{input: bc, locale: language.BritishEnglish, options: map[string]any{"era": "narrow"}, want: "15 Mar 0044 B"},
+		{
+			input:   value,
+			locale:  language.Latvian,
+			options: map[string]any{"era": "long", "dateStyle": "long"},
+			want:    "02 January 2021 mūsu ērā",
+		},
+		{input: value, locale: language.Japanese, options: map[string]any{"era": "short"}, wantErr: true},
+		{
+			input:   value,
+			locale:  language.BritishEnglish,
+			options: map[string]any{"fractionalSecondDigits": 3},
+			want:    "15:04:05.129", // truncated
+		},
+		{
+			input:   value,
+			locale:  language.Latvian,
+			options: map[string]any{"fractionalSecondDigits": 1, "timeStyle": "long"},
+			want:    "15:04:05,1 +0000",
+		},
+		{
+			input:   value,
+			locale:  language.AmericanEnglish,
+			options: map[string]any{"fractionalSecondDigits": 2, "dateStyle": "short"},
+			want:    "02/01/21 15:04:05.12",
+		},
+	} {
+		opts := make(Options, len(test.options))
+		for k, v := range test.options {
+			opts[k] = NewResolvedValue(v)
+		}
+
+		v, err := datetimeFunc(NewResolvedValue(test.input), opts, test.locale)
+		if test.wantErr {
+			if !errors.Is(err, mf2.ErrBadOption) {
+				t.Errorf("%s %v: want '%s', got '%v'", test.locale, test.options, mf2.ErrBadOption, err)
+			}
+
+			continue
+		}
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got := v.format(); test.want != got {
+			t.Errorf("%s %v: want '%s', got '%s'", test.locale, test.options, test.want, got)
+		}
+	}
+}
+
+func Test_DatetimeTimeZoneName(t *testing.T) {
+	t.Parallel()
+
+	winter := time.Date(2021, 1, 2, 15, 4, 5, 0, time.UTC)
+	summer := time.Date(2021, 7, 2, 15, 4, 5, 0, time.UTC)
+
+	for _, test := range []struct {
+		input    time.Time
+		locale   language.Tag
+		timeZone string
+		style    string
+		want     string
+	}{
+		{input: winter, locale: language.BritishEnglish, timeZone: "Europe/Riga", style: "short", want: "EET"},
+		{input: summer, locale: language.BritishEnglish, timeZone: "Europe/Riga", style: "long", want: "Eastern European Summer Time"},
+		{input: winter, locale: language.BritishEnglish, timeZone: "Asia/Kolkata", style: "shortOffset", want: "GMT+5:30"},
+		{input: winter, locale: language.BritishEnglish, timeZone: "America/New_York", style: "longOffset", want: "GMT-05:00"},
+		{input: winter, locale: language.BritishEnglish, timeZone: "UTC", style: "shortOffset", want: "GMT"},
+		{input: winter, locale: language.BritishEnglish, timeZone: "America/Los_Angeles", style: "shortGeneric", want: "PT"},
+		{input: summer, locale: language.BritishEnglish, timeZone: "America/Los_Angeles", style: "longGeneric", want: "Pacific Time"},
+		{input: winter, locale: language.BritishEnglish, timeZone: "Europe/Riga", style: "longGeneric", want: "Riga Time"},
+		{input: winter, locale: language.BritishEnglish, timeZone: "Asia/Dubai", style: "short", want: "GMT+4"}, // "+04" in tzdata
+		{input: winter, locale: language.Latvian, timeZone: "Europe/Riga", style: "long", want: "GMT+02:00"},
+	} {
+		opts := Options{
+			"timeStyle":    NewResolvedValue("short"),
+			"timeZone":     NewResolvedValue(test.timeZone),
+			"timeZoneName": NewResolvedValue(test.style),
+		}
+
+		v, err := datetimeFunc(NewResolvedValue(test.input), opts, test.locale)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		got := v.format()
+		if _, zone, _ := strings.Cut(got, " "); test.want != zone {
+			t.Errorf("%s %s %s: want '%s', got '%s'", test.locale, test.timeZone, test.style, test.want, got)
+		}
+	}
+
+	// the zone name replaces the zone of the time style
+	opts := Options{"timeStyle": NewResolvedValue("long"), "timeZoneName": NewResolvedValue("shortOffset")}
+
+	v, err := datetimeFunc(NewResolvedValue(winter), opts, language.BritishEnglish)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, got := "15:04:05 GMT", v.format(); want != got {
+		t.Errorf("want '%s', got '%s'", want, got)
+	}
+}
+
+func Test_DateTimeDefaults(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		in      string
+		want    string
+		options []Option
+	}{
+		{in: "{ $d :datetime }", want: "02 Jan 2021 03:04"},
+		{in: "{ $d :datetime timeZone=EET }", want: "02 Jan 2021 05:04"},
+		{in: "{ $d :datetime hourCycle=h23 }", want: "02 Jan 2021 03:04"},
+		{in: "{ $d :datetime dateStyle=short }", want: "02/01/21"},
+		{in: "{ $d :datetime }", options: []Option{WithDateTimeDefaults("long", "")}, want: "02 January 2021"},
+		{in: "{ $d :datetime }", options: []Option{WithDateTimeDefaults("", "medium")}, want: "03:04:05"},
+		{in: "{ $d :datetime timeStyle=short }", options: []Option{WithDateTimeDefaults("long", "")}, want: "03:04"},
+	} {
+		tmpl, err := New(test.options...).Parse(test.in)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := tmpl.Sprint(map[string]any{"d": testDate})
+		if err != nil {
+			t.Error(err)
+		}
+
+		if test.want != got {
+			t.Errorf("%s: want '%s', got '%s'", test.in, test.want, got)
+		}
+	}
+}
@@ -8,13 +8,18 @@ import (
 
 // integerFunc is the implementation of the integer function. Locale-sensitive integer formatting.
 func integerFunc(operand *ResolvedValue, options Options, locale language.Tag) (*ResolvedValue, error) {
+	return formatInteger(operand, options, locale, numberFunc)
+}
+
+// formatInteger formats the integer with the number function, see [integerFunc].
+func formatInteger(operand *ResolvedValue, options Options, locale language.Tag, number Func) (*ResolvedValue, error) {
 	if options == nil {
 		options = Options{"maximumFractionDigits": NewResolvedValue(0)}
 	} else {
 		options["maximumFractionDigits"] = NewResolvedValue(0)
 	}
 
-	value, err := numberFunc(operand, options, locale)
+	value, err := number(operand, options, locale)
 	if err != nil {
 		return nil, fmt.Errorf("exec integer func: %w", err)
 	}
@@ -3,13 +3,13 @@ package template
 import (
 	"encoding/json"
 	"fmt"
+	"math"
+	"slices"
 
 	"go.expect.digital/mf2"
 	"golang.org/x/text/currency"
 	"golang.org/x/text/feature/plural"
 	"golang.org/x/text/language"
-	"golang.org/x/text/message"
-	"golang.org/x/text/number"
 )
 
 // parseNumberOperand parses resolved operand value.
@@ -40,6 +40,11 @@ func parseNumberOperand(operand *ResolvedValue) (float64, error) {
 		}
 	}
 
+	// negative zero is zero, it is formatted and selected without the sign
+	if number == 0 {
+		number = 0
+	}
+
 	return number, nil
 }
 
@@ -121,27 +126,37 @@ type numberOptions struct {
 	MinimumSignificantDigits int
 	// The maximum number of significant digits to use.
 	MaximumSignificantDigits int
+	// The minimum number of digits in the most significant group to use the grouping separator,
+	// e.g. with 2 the numbers are grouped from 10,000. The default is the locale's CLDR value.
+	//
+	// NOTE: The option is not part of the default registry.
+	MinimumGroupingDigits int
 }
 
 func parseNumberOptions(opts Options) (*numberOptions, error) {
 	errorf := func(format string, args ...any) (*numberOptions, error) {
-		return nil, fmt.Errorf("%w: "+format, append([]any{mf2.ErrBadOption}, args...)...)
+		return nil, badOption(fmt.Errorf(format, args...))
 	}
 
+	opts, err := expandSkeleton(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// the first unsupported option in name order is reported, the map order is random
+	var unsupported string
+
 	for k := range opts {
-		switch k {
-		default:
-			return errorf("unsupported option: %s", k)
-		case "compactDisplay", "currency", "currencyDisplay", "currencySign", "notation", "numberingSystem",
-			"signDisplay", "style", "unit", "unitDisplay", "minimumIntegerDigits", "minimumFractionDigits",
-			"maximumFractionDigits", "minimumSignificantDigits", "maximumSignificantDigits", "select", "useGrouping": // noop
+		if !slices.Contains(numberOptionNames, k) && (unsupported == "" || k < unsupported) {
+			unsupported = k
 		}
 	}
 
-	var (
-		err     error
-		options numberOptions
-	)
+	if unsupported != "" {
+		return errorf("unsupported option: %s", unsupported)
+	}
+
+	var options numberOptions
 
 	selects := oneOf("plural", "ordinal", "exact")
 	if options.Select, err = opts.GetString("select", "plural", selects); err != nil {
@@ -225,17 +240,19 @@ func parseNumberOptions(opts Options) (*numberOptions, error) {
 		return errorf("%w", err)
 	}
 
-	var maxFractionDigits int // percent default
-
-	if options.Style == "decimal" {
-		maxFractionDigits = 3 // decimal default
-	}
+	// See ECMA-402 SetNumberFormatDigitOptions, the default maximum is at least the minimum.
+	maxFractionDigits := max(options.MinimumFractionDigits, defaultMaximumFractionDigits(options.Style))
 
 	options.MaximumFractionDigits, err = opts.GetInt("maximumFractionDigits", maxFractionDigits, eqOrGreaterThan(0))
 	if err != nil {
 		return errorf("%w", err)
 	}
 
+	if options.MaximumFractionDigits < options.MinimumFractionDigits {
+		return errorf("maximumFractionDigits %d is less than minimumFractionDigits %d",
+			options.MaximumFractionDigits, options.MinimumFractionDigits)
+	}
+
 	if options.MinimumSignificantDigits, err = opts.GetInt("minimumSignificantDigits", 1, eqOrGreaterThan(1)); err != nil {
 		return errorf("%w", err)
 	}
@@ -245,46 +262,160 @@ func parseNumberOptions(opts Options) (*numberOptions, error) {
 		return errorf("%w", err)
 	}
 
+	// 0 is the locale default
+	if options.MinimumGroupingDigits, err = opts.GetInt("minimumGroupingDigits", 0, eqOrGreaterThan(1)); err != nil {
+		return errorf("%w", err)
+	}
+
 	return &options, nil
 }
 
+// numberOptionNames are the options of :number, also of :integer, :count and :range.
+var numberOptionNames = []string{
+	"compactDisplay", "currency", "currencyDisplay", "currencySign", "notation", "numberingSystem",
+	"signDisplay", "style", "unit", "unitDisplay", "minimumIntegerDigits", "minimumFractionDigits",
+	"maximumFractionDigits", "minimumSignificantDigits", "maximumSignificantDigits", "select", "useGrouping",
+	"minimumGroupingDigits",
+}
+
+// defaultMaximumFractionDigits returns the default maximum fraction digits of the style,
+// 0 for "percent" and 3 for "decimal" as in ECMA-402.
+func defaultMaximumFractionDigits(style string) int {
+	if style == "percent" {
+		return 0
+	}
+
+	return 3 //nolint:mnd
+}
+
+// minimumGroupingDigits returns the CLDR minimumGroupingDigits of the locale, x/text does not provide it.
+func minimumGroupingDigits(locale language.Tag) int {
+	base, _ := locale.Base()
+	region, _ := locale.Region()
+
+	switch base.String() {
+	case "es", "pl":
+		return 2 //nolint:mnd
+	case "pt":
+		if region.String() == "PT" {
+			return 2 //nolint:mnd
+		}
+	}
+
+	return 1
+}
+
+// grouped reports whether the integer digits of the value are grouped, see [numberOptions.UseGrouping].
+// The value is rounded to the maximum fraction digits, e.g. 9999.9 has 5 integer digits without fraction.
+func (o *numberOptions) grouped(value float64, locale language.Tag) bool {
+	minGrouping := o.MinimumGroupingDigits
+	if minGrouping == 0 {
+		minGrouping = minimumGroupingDigits(locale)
+	}
+
+	switch o.UseGrouping {
+	case "never":
+		return false
+	case "always":
+		if o.MinimumGroupingDigits == 0 {
+			minGrouping = 1
+		}
+	case "min2":
+		minGrouping = max(minGrouping, 2) //nolint:mnd
+	}
+
+	if o.Style == "percent" {
+		value *= 100
+	}
+
+	scale := math.Pow10(o.MaximumFractionDigits)
+	integer := math.Abs(math.Round(value*scale) / scale)
+
+	// the separator is used if the most significant group has at least minGrouping digits
+	return integer >= math.Pow10(3+minGrouping-1) //nolint:mnd
+}
+
+// resolved returns the effective options used for formatting and selection, including the defaults.
+func (o *numberOptions) resolved() Options {
+	options := Options{
+		"select":                NewResolvedValue(o.Select),
+		"signDisplay":           NewResolvedValue(o.SignDisplay),
+		"style":                 NewResolvedValue(o.Style),
+		"useGrouping":           NewResolvedValue(o.UseGrouping),
+		"minimumIntegerDigits":  NewResolvedValue(o.MinimumIntegerDigits),
+		"minimumFractionDigits": NewResolvedValue(o.MinimumFractionDigits),
+		"maximumFractionDigits": NewResolvedValue(o.MaximumFractionDigits),
+	}
+
+	if o.MaximumSignificantDigits >= 0 {
+		options["maximumSignificantDigits"] = NewResolvedValue(o.MaximumSignificantDigits)
+	}
+
+	if o.MinimumGroupingDigits > 0 {
+		options["minimumGroupingDigits"] = NewResolvedValue(o.MinimumGroupingDigits)
+	}
+
+	return options
+}
+
 // numberFunc is the implementation of the number function. Locale-sensitive number formatting.
+//
+// The non-finite operands are not errors: ±Infinity is formatted with the locale's infinity symbol,
+// e.g. "-∞", and NaN with the locale's NaN symbol, e.g. "NaN" in English.
+// They select the "other" category and never match the exact keys.
+// Negative zero is formatted and selected as zero.
+// The [Range] operand is formatted and selected as the range, see [rangeFunc].
+//
+// The "skeleton" option is the ICU number skeleton expanded to the equivalent options,
+// e.g. "{$n :number skeleton=|.00 group-off|}", the other options take precedence.
 func numberFunc(operand *ResolvedValue, options Options, locale language.Tag) (*ResolvedValue, error) {
+	return formatNumber(operand, options, locale, XText{})
+}
+
+// formatNumber formats the number with the formatter, see [numberFunc].
+func formatNumber(
+	operand *ResolvedValue,
+	options Options,
+	locale language.Tag,
+	formatter NumberFormatter,
+) (*ResolvedValue, error) {
 	errorf := func(format string, args ...any) (*ResolvedValue, error) {
 		return nil, fmt.Errorf("exec number function: "+format, args...)
 	}
 
+	if _, ok := operand.value.(Range); ok {
+		return formatRange(operand, options, locale, func(operand *ResolvedValue, options Options, locale language.Tag) (*ResolvedValue, error) {
+			return formatNumber(operand, options, locale, formatter)
+		})
+	}
+
 	value, err := parseNumberOperand(operand)
 	if err != nil {
 		return errorf("%w", err)
 	}
 
-	opts, err := parseNumberOptions(options)
+	opts, err := cachedOptions(options, "number", func() (*numberOptions, error) { return parseNumberOptions(options) })
 	if err != nil {
 		return errorf("%w", err)
 	}
 
-	p := message.NewPrinter(locale)
-	numberOpts := []number.Option{
-		number.MinFractionDigits(opts.MinimumFractionDigits),
-		number.MaxFractionDigits(opts.MaximumFractionDigits),
-		number.MinIntegerDigits(opts.MinimumIntegerDigits),
-		number.Precision(opts.MaximumSignificantDigits),
-	}
-
-	var num number.Formatter
-
 	switch opts.Style {
 	default:
 		return errorf(`option style "%s" is not implemented`, opts.Style)
-	case "decimal":
-		num = number.Decimal(value, numberOpts...)
-	case "percent":
-		num = number.Percent(value, numberOpts...)
+	case "decimal", "percent":
+	}
+
+	numberFormat := NumberFormat{
+		Style:                    opts.Style,
+		MinimumIntegerDigits:     opts.MinimumIntegerDigits,
+		MinimumFractionDigits:    opts.MinimumFractionDigits,
+		MaximumFractionDigits:    opts.MaximumFractionDigits,
+		MaximumSignificantDigits: opts.MaximumSignificantDigits,
+		Grouping:                 !math.IsNaN(value) && !math.IsInf(value, 0) && opts.grouped(value, locale),
 	}
 
 	format := func() string {
-		result := p.Sprint(num)
+		result := formatter.FormatNumber(value, numberFormat, locale)
 
 		switch opts.SignDisplay {
 		case "auto":
@@ -306,9 +437,22 @@ func numberFunc(operand *ResolvedValue, options Options, locale language.Tag) (*
 		return result
 	}
 
-	selectKey := func(keys []string) string {
-		if hasExactKey(keys) {
-			return format()
+	// See ".message-format-wg/spec/registry.md#number-selection".
+	match := func(keys []string) []string {
+		if math.IsNaN(value) || math.IsInf(value, 0) {
+			return []string{pluralFormString(plural.Other)}
+		}
+
+		// the exact match is preferred to the plural category
+		matches := numericKeys(value, keys)
+
+		rules := plural.Cardinal
+
+		switch opts.Select {
+		case "exact":
+			return matches
+		case "ordinal":
+			rules = plural.Ordinal
 		}
 
 		scale := -1
@@ -317,24 +461,11 @@ func numberFunc(operand *ResolvedValue, options Options, locale language.Tag) (*
 			scale = 0
 		}
 
-		digits := num.Digits(nil, locale, scale)
-		form := plural.Cardinal.MatchDigits(locale, digits.Digits, int(digits.Exp), int(digits.End-digits.Exp))
-
-		return pluralFormString(form)
-	}
-
-	return NewResolvedValue(value, WithFormat(format), WithSelectKey(selectKey)), nil
-}
+		digits := xtextNumber(value, numberFormat).Digits(nil, locale, scale)
+		form := rules.MatchDigits(locale, digits.Digits, int(digits.Exp), int(digits.End-digits.Exp))
 
-// hasExactKey returns true if the variant keys contain exact value besides the plural categories.
-func hasExactKey(keys []string) bool {
-	for _, key := range keys {
-		switch key {
-		default:
-			return true
-		case "zero", "one", "two", "few", "many", "other": // check next key
-		}
+		return append(matches, pluralFormString(form))
 	}
 
-	return false
+	return NewResolvedValue(value, WithFormat(format), WithMatch(match), WithOptions(opts.resolved())), nil
 }
@@ -1,9 +1,13 @@
 package template
 
 import (
+	"errors"
+	"math"
 	"testing"
 
 	"golang.org/x/text/language"
+
+	"go.expect.digital/mf2"
 )
 
 func Test_Number(t *testing.T) {
@@ -105,3 +109,229 @@ func Test_Number(t *testing.T) {
 	assert = assertFormat(t, numberFunc, map[string]any{}, language.Latvian)
 	assert("0.1", "0,1")
 }
+
+func Test_NumberSelect(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		locale language.Tag
+		in     string
+		want   string
+		input  any
+	}{
+		{in: ".match { $n :number } one {{one}} * {{other}}", input: 1, want: "one"},
+		{in: ".match { $n :number select=plural } 1 {{exact}} one {{one}} * {{other}}", input: 1, want: "exact"},
+		{in: ".match { $n :number } |1.0| {{exact}} * {{other}}", input: 1, want: "exact"},
+		// exact key does not hide the plural categories
+		{
+			in:     ".match { $n :number } 1 {{exact}} one {{one}} * {{other}}",
+			input:  21,
+			locale: language.Latvian,
+			want:   "one",
+		},
+		{in: ".match { $n :number select=ordinal } one {{st}} two {{nd}} few {{rd}} * {{th}}", input: 2, want: "nd"},
+		{in: ".match { $n :integer select=ordinal } one {{st}} two {{nd}} few {{rd}} * {{th}}", input: 23, want: "rd"},
+		{in: ".match { $n :number select=exact } one {{one}} * {{other}}", input: 1, want: "other"},
+		{in: ".match { $n :number select=exact } 1 {{exact}} * {{other}}", input: 1, want: "exact"},
+	} {
+		t.Run(test.in, func(t *testing.T) {
+			t.Parallel()
+
+			locale := test.locale
+			if locale == language.Und {
+				locale = language.English
+			}
+
+			template, err := New(WithLocale(locale)).Parse(test.in)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := template.Sprint(map[string]any{"n": test.input})
+			if err != nil {
+				t.Error(err)
+			}
+
+			if test.want != got {
+				t.Errorf("want '%s', got '%s'", test.want, got)
+			}
+		})
+	}
+}
+
+func Test_NumberRounding(t *testing.T) {
+	t.Parallel()
+
+	// the values rounded up to the next power of ten
+	for _, test := range []struct {
+		in    string
+		want  string
+		input float64
+	}{
+		{in: "{ $n :number style=percent }", input: 0.007, want: "1%"},
+		{in: "{ $n :number style=percent maximumFractionDigits=1 }", input: 0.00996, want: "1%"},
+		{in: "{ $n :number maximumFractionDigits=0 }", input: 0.7, want: "1"},
+		{in: "{ $n :number maximumFractionDigits=1 }", input: 0.96, want: "1"},
+		{in: "{ $n :number maximumFractionDigits=1 }", input: 9.96, want: "10"},
+		{in: "{ $n :number maximumFractionDigits=2 }", input: -0.999, want: "-1"},
+		{in: "{ $n :integer }", input: 0.5, want: "1"},
+		{in: ".input {$n :number maximumFractionDigits=0} .match {$n} one {{one}} * {{other}}", input: 0.7, want: "one"},
+	} {
+		t.Run(test.in, func(t *testing.T) {
+			t.Parallel()
+
+			template, err := New(WithLocale(language.AmericanEnglish)).Parse(test.in)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := template.Sprint(map[string]any{"n": test.input})
+			if err != nil {
+				t.Error(err)
+			}
+
+			if test.want != got {
+				t.Errorf("want '%s', got '%s'", test.want, got)
+			}
+		})
+	}
+}
+
+func Test_NumberNonFinite(t *testing.T) {
+	t.Parallel()
+
+	const selectMessage = ".match { $n :number } 0 {{exact}} |inf| {{inf}} zero {{zero}} one {{one}} * {{other}}"
+
+	for _, test := range []struct {
+		in    string
+		want  string
+		input float64
+	}{
+		{in: "{ $n :number }", input: math.Inf(1), want: "∞"},
+		{in: "{ $n :number signDisplay=always }", input: math.Inf(1), want: "+∞"},
+		{in: "{ $n :integer }", input: math.Inf(-1), want: "-∞"},
+		{in: "{ $n :number style=percent }", input: math.NaN(), want: "NS"}, // "NaN" in English
+		{in: "{ $n :number }", input: math.Copysign(0, -1), want: "0"},
+		{in: "{ $n :number signDisplay=always }", input: math.Copysign(0, -1), want: "+0"},
+		{in: selectMessage, input: math.Inf(1), want: "other"},
+		{in: selectMessage, input: math.NaN(), want: "other"},
+		{in: selectMessage, input: math.Copysign(0, -1), want: "exact"},
+	} {
+		t.Run(test.in, func(t *testing.T) {
+			t.Parallel()
+
+			template, err := New(WithLocale(language.Latvian)).Parse(test.in)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := template.Sprint(map[string]any{"n": test.input})
+			if err != nil {
+				t.Error(err)
+			}
+
+			if test.want != got {
+				t.Errorf("want '%s', got '%s'", test.want, got)
+			}
+		})
+	}
+}
+
+func Test_NumberResolvedOptions(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		options map[string]any
+		want    map[string]int
+		wantErr bool
+	}{
+		{
+			options: map[string]any{},
+			want:    map[string]int{"minimumFractionDigits": 0, "maximumFractionDigits": 3},
+		},
+		{
+			options: map[string]any{"style": "percent"},
+			want:    map[string]int{"minimumFractionDigits": 0, "maximumFractionDigits": 0},
+		},
+		{
+			options: map[string]any{"style": "percent", "minimumFractionDigits": 2},
+			want:    map[string]int{"minimumFractionDigits": 2, "maximumFractionDigits": 2},
+		},
+		{
+			options: map[string]any{"minimumFractionDigits": 5},
+			want:    map[string]int{"minimumFractionDigits": 5, "maximumFractionDigits": 5},
+		},
+		{
+			options: map[string]any{"minimumFractionDigits": 2, "maximumFractionDigits": 1},
+			wantErr: true,
+		},
+	} {
+		opts := make(Options, len(test.options))
+		for k, v := range test.options {
+			opts[k] = NewResolvedValue(v)
+		}
+
+		v, err := numberFunc(NewResolvedValue(1), opts, language.AmericanEnglish)
+		if test.wantErr {
+			if !errors.Is(err, mf2.ErrBadOption) {
+				t.Errorf("%v: want '%s', got '%v'", test.options, mf2.ErrBadOption, err)
+			}
+
+			continue
+		}
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for name, want := range test.want {
+			got, err := v.Options().GetInt(name, -1)
+			if err != nil || want != got {
+				t.Errorf("%v: want %s %d, got %d (%v)", test.options, name, want, got, err)
+			}
+		}
+	}
+}
+
+func Test_NumberGrouping(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		locale language.Tag
+		in     string
+		want   string
+		input  float64
+	}{
+		{locale: language.English, in: "{ $n :number }", input: 1234, want: "1,234"},
+		{locale: language.English, in: "{ $n :number useGrouping=never }", input: 1234567, want: "1234567"},
+		{locale: language.English, in: "{ $n :number useGrouping=min2 }", input: 1234, want: "1234"},
+		{locale: language.English, in: "{ $n :number useGrouping=min2 }", input: 12345, want: "12,345"},
+		{locale: language.English, in: "{ $n :number minimumGroupingDigits=3 }", input: 12345, want: "12345"},
+		{locale: language.English, in: "{ $n :number style=percent }", input: 12.34, want: "1,234%"},
+		{locale: language.Spanish, in: "{ $n :number }", input: 1234, want: "1234"},
+		{locale: language.Spanish, in: "{ $n :integer }", input: 12345.4, want: "12.345"},
+		{locale: language.Spanish, in: "{ $n :number }", input: -12345, want: "-12.345"},
+		{locale: language.Spanish, in: "{ $n :number useGrouping=always }", input: 1234, want: "1.234"},
+		{locale: language.Polish, in: "{ $n :number }", input: 1234, want: "1234"},
+		{locale: language.EuropeanPortuguese, in: "{ $n :number }", input: 1234, want: "1234"},
+		{locale: language.BrazilianPortuguese, in: "{ $n :number }", input: 1234, want: "1.234"},
+	} {
+		t.Run(test.locale.String()+" "+test.in, func(t *testing.T) {
+			t.Parallel()
+
+			template, err := New(WithLocale(test.locale)).Parse(test.in)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := template.Sprint(map[string]any{"n": test.input})
+			if err != nil {
+				t.Error(err)
+			}
+
+			if test.want != got {
+				t.Errorf("want '%s', got '%s'", test.want, got)
+			}
+		})
+	}
+}
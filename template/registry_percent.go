@@ -0,0 +1,61 @@
+package template
+
+import (
+	"fmt"
+	"slices"
+
+	"golang.org/x/text/language"
+
+	"go.expect.digital/mf2"
+)
+
+// percentOptionNames are the options of :percent, the options of :number except "style".
+var percentOptionNames = append(
+	slices.DeleteFunc(slices.Clone(numberOptionNames), func(name string) bool { return name == "style" }),
+	"scale",
+)
+
+// percentFunc is the implementation of the percent function, e.g. "{$p :percent}" formats 45 as "45%".
+// The operand is in percent points, the option "scale" multiplies it, e.g. "{$ratio :percent scale=100}"
+// formats 0.45 as "45%". The other options are the options of :number, except "style".
+func percentFunc(operand *ResolvedValue, options Options, locale language.Tag) (*ResolvedValue, error) {
+	return formatPercent(operand, options, locale, numberFunc)
+}
+
+// formatPercent formats the percentage with the number function, see [percentFunc].
+func formatPercent(operand *ResolvedValue, options Options, locale language.Tag, number Func) (*ResolvedValue, error) {
+	errorf := func(format string, args ...any) (*ResolvedValue, error) {
+		return nil, fmt.Errorf("exec percent func: "+format, args...)
+	}
+
+	value, err := parseNumberOperand(operand)
+	if err != nil {
+		return errorf("%w", err)
+	}
+
+	scale := 1.0
+
+	numberOptions := make(Options, len(options)+1)
+
+	for _, k := range options.Names() {
+		switch v := options[k]; k {
+		default:
+			numberOptions[k] = v
+		case "style":
+			return errorf(`%w: option "style" is not supported, the style is percent`, mf2.ErrBadOption)
+		case "scale":
+			if scale, err = options.GetFloat(k, 1); err != nil {
+				return errorf("%w", err)
+			}
+		}
+	}
+
+	numberOptions["style"] = NewResolvedValue("percent")
+
+	result, err := number(NewResolvedValue(value*scale/100), numberOptions, locale) //nolint:mnd
+	if err != nil {
+		return errorf("%w", err)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,61 @@
+package template
+
+import (
+	"testing"
+
+	"golang.org/x/text/language"
+)
+
+func Test_Percent(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		input   map[string]any
+		locale  language.Tag
+		name    string
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{name: "points", in: "{ $p :percent }", input: map[string]any{"p": 45}, want: "45%"},
+		{name: "ratio", in: "{ $p :percent scale=100 }", input: map[string]any{"p": 0.07}, want: "7%"},
+		{name: "literal", in: "{ 12.5 :percent minimumFractionDigits=1 }", want: "12.5%"},
+		{name: "half point", in: "{ 0.5 :percent }", want: "1%"},
+		{name: "sub point", in: "{ 0.9 :percent }", want: "1%"},
+		{name: "sub point fraction", in: "{ 0.96 :percent maximumFractionDigits=1 }", want: "1%"},
+		{name: "small ratio", in: "{ $p :percent scale=100 }", input: map[string]any{"p": 0.007}, want: "1%"},
+		{name: "locale", in: "{ 45 :percent }", locale: language.German, want: "45\u00a0%"},
+		{
+			name:  "select",
+			in:    ".input {$p :percent} .match {$p} one {{one}} * {{{$p}}}",
+			input: map[string]any{"p": 1},
+			want:  "one",
+		},
+		{name: "style", in: "{ 45 :percent style=decimal }", want: "{|45|}", wantErr: true},
+		{name: "bad scale", in: "{ 45 :percent scale=x }", want: "{|45|}", wantErr: true},
+		{name: "missing operand", in: "{ :percent }", want: "{:percent}", wantErr: true},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			locale := test.locale
+			if locale == language.Und {
+				locale = language.English
+			}
+
+			template, err := New(WithLocale(locale)).Parse(test.in)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := template.Sprint(test.input)
+			if test.wantErr != (err != nil) {
+				t.Errorf("want error %t, got '%v'", test.wantErr, err)
+			}
+
+			if test.want != got {
+				t.Errorf("want '%s', got '%s'", test.want, got)
+			}
+		})
+	}
+}
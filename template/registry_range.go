@@ -0,0 +1,188 @@
+package template
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"golang.org/x/text/feature/plural"
+	"golang.org/x/text/language"
+
+	"go.expect.digital/mf2"
+)
+
+// Range is the operand of the range function, the interval from Start to End, e.g. Range{Start: 2, End: 4}.
+type Range struct {
+	Start, End any
+}
+
+// rangePatterns are the CLDR range patterns by language, the default is "{0}–{1}".
+var rangePatterns = map[string]string{
+	"es": "{0}-{1}",
+	"ja": "{0}～{1}",
+	"zh": "{0}-{1}",
+}
+
+// rangePattern returns the CLDR range pattern of the locale, e.g. "{0}–{1}".
+func rangePattern(locale language.Tag) string {
+	base, _ := locale.Base()
+
+	if pattern, ok := rangePatterns[base.String()]; ok {
+		return pattern
+	}
+
+	return "{0}–{1}"
+}
+
+// pluralRanges are the CLDR plural ranges by language, the plural category of the range
+// by the categories of its start and end. Only the ranges not of the end category are listed,
+// the range of the languages not listed is of the end category, e.g. "2–4" is "few" in Russian.
+var pluralRanges = map[string]map[[2]string]string{
+	"ar": {
+		{"zero", "one"}: "zero", {"zero", "two"}: "zero", {"one", "two"}: "other",
+		{"other", "one"}: "other", {"other", "two"}: "other",
+	},
+	"bg": {{"other", "one"}: "other"},
+	"ca": {{"other", "one"}: "other"},
+	"en": {{"other", "one"}: "other"},
+	"es": {{"other", "one"}: "other"},
+	"et": {{"other", "one"}: "other"},
+	"eu": {{"other", "one"}: "other"},
+	"fi": {{"other", "one"}: "other"},
+	"he": {
+		{"one", "two"}: "other", {"two", "many"}: "other", {"many", "other"}: "many",
+		{"other", "one"}: "other", {"other", "two"}: "other",
+	},
+	"ka": {{"one", "other"}: "one", {"other", "one"}: "other"},
+	"lv": {{"zero", "zero"}: "other", {"one", "zero"}: "other", {"other", "zero"}: "other"},
+	"mk": {{"one", "one"}: "other", {"other", "one"}: "other"},
+	"nb": {{"other", "one"}: "other"},
+	"ro": {{"few", "one"}: "few"},
+	"si": {{"other", "one"}: "other"},
+	"sl": {{"one", "one"}: "few", {"two", "one"}: "few", {"few", "one"}: "few", {"other", "one"}: "few"},
+	"sv": {{"other", "one"}: "other"},
+	"ur": {{"other", "one"}: "other"},
+}
+
+// pluralRange returns the CLDR plural category of the range by the categories of its start and end,
+// e.g. "few" for "one" and "few" in Russian.
+func pluralRange(locale language.Tag, start, end string) string {
+	base, _ := locale.Base()
+
+	if category, ok := pluralRanges[base.String()][[2]string{start, end}]; ok {
+		return category
+	}
+
+	return end
+}
+
+// rangeFunc is the implementation of the range function, e.g. "{$r :range}" formats Range{2, 4} as "2–4".
+// The range is the operand - [Range] or a two-element slice or array - or the "start" and "end" options.
+// The other options are the options of :number applied to both ends, e.g. "{$r :range style=percent}".
+// The ends are formatted with the locale range pattern, and the equal ends as one number.
+// The range selects the CLDR plural category of the range, e.g. "2–4" selects "few" in Russian,
+// and the equal ends select as the number. The operand of :number and :integer may be the range too,
+// e.g. the ".local $r = {$x :range} .match {$r :number}" selects the plural category of the range.
+func rangeFunc(operand *ResolvedValue, options Options, locale language.Tag) (*ResolvedValue, error) {
+	return formatRange(operand, options, locale, numberFunc)
+}
+
+// formatRange formats the range with the number function, see [rangeFunc].
+func formatRange(operand *ResolvedValue, options Options, locale language.Tag, number Func) (*ResolvedValue, error) {
+	errorf := func(format string, args ...any) (*ResolvedValue, error) {
+		return nil, fmt.Errorf("exec range func: "+format, args...)
+	}
+
+	start, end, err := parseRange(operand, options)
+	if err != nil {
+		return errorf("%w", err)
+	}
+
+	numberOptions := make(Options, len(options))
+
+	for k, v := range options {
+		if k != "start" && k != "end" {
+			numberOptions[k] = v
+		}
+	}
+
+	from, err := number(start, numberOptions, locale)
+	if err != nil {
+		return errorf("start: %w", err)
+	}
+
+	to, err := number(end, numberOptions, locale)
+	if err != nil {
+		return errorf("end: %w", err)
+	}
+
+	format := func() string {
+		first, last := from.String(), to.String()
+		if first == last {
+			return first
+		}
+
+		return strings.NewReplacer("{0}", first, "{1}", last).Replace(rangePattern(locale))
+	}
+
+	// See https://www.unicode.org/reports/tr35/tr35-numbers.html#Plural_Ranges.
+	match := func(keys []string) []string {
+		if from.String() == to.String() {
+			return to.matches(keys)
+		}
+
+		// the ranges of the ordinal numbers are not defined, the end is selected
+		if s, _ := to.options.GetString("select", "plural"); s != "plural" {
+			return to.matches(keys)
+		}
+
+		return []string{pluralRange(locale, pluralCategory(from), pluralCategory(to))}
+	}
+
+	return NewResolvedValue(
+		Range{Start: from.value, End: to.value},
+		WithFormat(format),
+		WithMatch(match),
+		WithOptions(to.options),
+	), nil
+}
+
+// pluralCategory returns the plural category of the number, the last of its matches without the keys.
+func pluralCategory(v *ResolvedValue) string {
+	if matches := v.match(nil); len(matches) > 0 {
+		return matches[len(matches)-1]
+	}
+
+	return pluralFormString(plural.Other)
+}
+
+// parseRange returns the start and end of the range from the operand or the options.
+func parseRange(operand *ResolvedValue, options Options) (*ResolvedValue, *ResolvedValue, error) {
+	start, hasStart := options["start"]
+	end, hasEnd := options["end"]
+
+	switch {
+	case hasStart && hasEnd:
+		return start, end, nil
+	case hasStart || hasEnd:
+		return nil, nil, fmt.Errorf(`%w: want both "start" and "end"`, mf2.ErrBadOption)
+	}
+
+	switch v := operand.value.(type) {
+	case nil:
+		return nil, nil, fmt.Errorf(`operand or options "start" and "end" are required: %w`, mf2.ErrBadOperand)
+	case Range:
+		return NewResolvedValue(v.Start), NewResolvedValue(v.End), nil
+	}
+
+	rv := reflect.ValueOf(operand.value)
+
+	switch rv.Kind() { //nolint:exhaustive
+	case reflect.Slice, reflect.Array:
+		if rv.Len() == 2 { //nolint:mnd
+			return NewResolvedValue(rv.Index(0).Interface()), NewResolvedValue(rv.Index(1).Interface()), nil
+		}
+	}
+
+	return nil, nil, fmt.Errorf("want range or two-element slice, got %T: %w", operand.value, mf2.ErrBadOperand)
+}
@@ -0,0 +1,103 @@
+package template
+
+import (
+	"testing"
+
+	"golang.org/x/text/language"
+)
+
+func Test_Range(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		input   map[string]any
+		locale  language.Tag
+		name    string
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{name: "range", in: "{ $r :range } items", input: map[string]any{"r": Range{Start: 2, End: 4}}, want: "2–4 items"},
+		{name: "slice", in: "{ $r :range }", input: map[string]any{"r": []float64{1.5, 2.25}}, want: "1.5–2.25"},
+		{name: "array", in: "{ $r :range }", input: map[string]any{"r": [2]int{1000, 2000}}, want: "1,000–2,000"},
+		{name: "options", in: "{ :range start=10 end=20 }", want: "10–20"},
+		{name: "variable options", in: "{ :range start=$a end=$b }", input: map[string]any{"a": 1, "b": 3}, want: "1–3"},
+		{name: "equal", in: "{ :range start=3 end=3.0 }", want: "3"},
+		{name: "number options", in: "{ :range start=0.1 end=0.25 style=percent }", want: "10%–25%"},
+		{name: "locale", in: "{ :range start=1.5 end=2 }", locale: language.Latvian, want: "1,5–2"},
+		{name: "locale pattern", in: "{ :range start=5 end=10 }", locale: language.Spanish, want: "5-10"},
+		{
+			name:   "select",
+			in:     ".input {$r :range} .match {$r} one {{{$r} товар}} few {{{$r} товара}} * {{{$r} товаров}}",
+			input:  map[string]any{"r": []int{2, 4}},
+			locale: language.Russian,
+			want:   "2–4 товара",
+		},
+		{
+			name:   "select range category",
+			in:     ".input {$r :range} .match {$r} one {{one}} zero {{zero}} * {{other}}",
+			input:  map[string]any{"r": []int{1, 10}},
+			locale: language.Latvian,
+			want:   "other",
+		},
+		{
+			name:  "select range not end",
+			in:    ".input {$r :range} .match {$r} one {{{$r} item}} * {{{$r} items}}",
+			input: map[string]any{"r": []int{0, 1}},
+			want:  "0–1 items",
+		},
+		{
+			name:   "select range not exact",
+			in:     ".match {$r :range} 4 {{four}} few {{few}} * {{other}}",
+			input:  map[string]any{"r": []int{2, 4}},
+			locale: language.Russian,
+			want:   "few",
+		},
+		{
+			name:  "select equal ends exact",
+			in:    ".match {$r :range} 4 {{four}} * {{other}}",
+			input: map[string]any{"r": []int{4, 4}},
+			want:  "four",
+		},
+		{
+			name:   "select number of range",
+			in:     ".local $r = {$x :range} .match {$r :number} one {{one}} few {{{$r} few}} * {{other}}",
+			input:  map[string]any{"x": []int{2, 101}},
+			locale: language.Slovenian,
+			want:   "2–101 few",
+		},
+		{
+			name:   "select integer of range",
+			in:     ".input {$r :integer} .match {$r} one {{{$r} товар}} few {{{$r} товара}} * {{{$r} товаров}}",
+			input:  map[string]any{"r": Range{Start: 1.5, End: 4}},
+			locale: language.Russian,
+			want:   "2–4 товара",
+		},
+		{name: "missing end", in: "{ :range start=1 }", want: "{:range start = 1}", wantErr: true},
+		{name: "missing operand", in: "{ :range }", want: "{:range}", wantErr: true},
+		{name: "bad operand", in: "{ $r :range }", input: map[string]any{"r": []int{1}}, want: "{$r}", wantErr: true},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			locale := test.locale
+			if locale == language.Und {
+				locale = language.English
+			}
+
+			template, err := New(WithLocale(locale)).Parse(test.in)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := template.Sprint(test.input)
+			if test.wantErr != (err != nil) {
+				t.Errorf("want error %t, got '%v'", test.wantErr, err)
+			}
+
+			if test.want != got {
+				t.Errorf("want '%s', got '%s'", test.want, got)
+			}
+		})
+	}
+}
@@ -2,13 +2,25 @@ package template
 
 import (
 	"fmt"
+	"reflect"
 
 	"golang.org/x/text/language"
 )
 
 // stringFunc is the implementation of the string function.
 // Formatting of strings as a literal and selection based on string equality.
-func stringFunc(operand *ResolvedValue, options Options, _ language.Tag) (*ResolvedValue, error) {
+// The slice operand is formatted as a list, e.g. "a, b, and c".
+func stringFunc(operand *ResolvedValue, options Options, locale language.Tag) (*ResolvedValue, error) {
+	return formatString(operand, options, locale, XText{})
+}
+
+// formatString formats the string, and the slice operand with the formatter, see [stringFunc].
+func formatString(
+	operand *ResolvedValue,
+	options Options,
+	locale language.Tag,
+	formatter ListFormatter,
+) (*ResolvedValue, error) {
 	errorf := func(format string, args ...any) (*ResolvedValue, error) {
 		return nil, fmt.Errorf("exec string function: "+format, args...)
 	}
@@ -21,5 +33,35 @@ func stringFunc(operand *ResolvedValue, options Options, _ language.Tag) (*Resol
 		return errorf("want no options")
 	}
 
-	return operand, nil
+	items, ok := listItems(operand.value)
+	if !ok {
+		return operand, nil
+	}
+
+	format := func() string {
+		return formatter.FormatList(items, ListFormat{Type: "conjunction"}, locale)
+	}
+
+	return NewResolvedValue(operand.value, WithFormat(format)), nil
+}
+
+// listItems returns the formatted items of the slice or array, except the strings as []byte and []rune.
+func listItems(value any) ([]string, bool) {
+	switch value.(type) {
+	case []byte, []rune:
+		return nil, false
+	}
+
+	v := reflect.ValueOf(value)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return nil, false
+	}
+
+	items := make([]string, v.Len())
+
+	for i := range items {
+		items[i] = defaultFormat(v.Index(i).Interface())
+	}
+
+	return items, true
 }
@@ -1,9 +1,16 @@
 package template
 
 import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
 	"golang.org/x/text/language"
+
+	"go.expect.digital/mf2"
 )
 
 func assertFormat(t *testing.T, f Func, options map[string]any, locale language.Tag) func(in any, want string) {
@@ -29,3 +36,152 @@ func assertFormat(t *testing.T, f Func, options map[string]any, locale language.
 		}
 	}
 }
+
+func TestSharedPrintersAndLocations(t *testing.T) {
+	t.Parallel()
+
+	if printer(language.Latvian) != printer(language.Latvian) {
+		t.Error("want shared printer")
+	}
+
+	riga, err := loadLocation("Europe/Riga")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if loc, _ := loadLocation("Europe/Riga"); loc != riga {
+		t.Error("want shared location")
+	}
+
+	if _, err := loadLocation("Unknown/Zone"); err == nil {
+		t.Error("want error, got nil")
+	}
+}
+
+func TestRegistrySnapshot(t *testing.T) {
+	t.Parallel()
+
+	shout := func(operand *ResolvedValue, _ Options, _ language.Tag) (*ResolvedValue, error) {
+		return NewResolvedValue(strings.ToUpper(operand.String()) + "!"), nil
+	}
+
+	reg := Registry{"shout": shout}
+
+	tmpl, err := New(WithFuncs(reg)).Parse("{ $s :shout }")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// modifications after the compilation
+	delete(reg, "shout")
+	WithFunc("shout", stringFunc)(tmpl)
+
+	if got, err := tmpl.Sprint(map[string]any{"s": "hi"}); err != nil || got != "HI!" {
+		t.Errorf("want 'HI!', got '%s' (%v)", got, err)
+	}
+
+	// the next compilation uses the modified registry
+	if _, err := tmpl.Parse("{ $s :shout }"); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, err := tmpl.Sprint(map[string]any{"s": "hi"}); err != nil || got != "hi" {
+		t.Errorf("want 'hi', got '%s' (%v)", got, err)
+	}
+}
+
+func TestOptionsNames(t *testing.T) {
+	t.Parallel()
+
+	options := Options{"b": NewResolvedValue(1), "c": NewResolvedValue(2), "a": NewResolvedValue(3)}
+
+	if want, got := []string{"a", "b", "c"}, options.Names(); !slices.Equal(want, got) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+
+	// the first unsupported option in the name order is reported
+	tmpl, err := New().Parse("{ 1 :number z=1 y=2 x=3 }")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for range 10 {
+		if _, err := tmpl.Sprint(nil); err == nil || !strings.Contains(err.Error(), "unsupported option: x") {
+			t.Fatalf("want unsupported option x, got '%v'", err)
+		}
+	}
+}
+
+func TestOptionsGetters(t *testing.T) {
+	t.Parallel()
+
+	options := Options{
+		"bool":      NewResolvedValue(true),
+		"boolStr":   NewResolvedValue("false"),
+		"float":     NewResolvedValue(2),
+		"floatStr":  NewResolvedValue("1.5"),
+		"duration":  NewResolvedValue("1h30m"),
+		"tag":       NewResolvedValue("lv-LV"),
+		"enum":      NewResolvedValue("short"),
+		"bad":       NewResolvedValue("x"),
+		"badNumber": NewResolvedValue(1),
+	}
+
+	assert := func(want, got any, err error) {
+		t.Helper()
+
+		if err != nil {
+			t.Error(err)
+		} else if want != got {
+			t.Errorf("want '%v', got '%v'", want, got)
+		}
+	}
+
+	b, err := options.GetBool("bool", false)
+	assert(true, b, err)
+
+	b, err = options.GetBool("boolStr", true)
+	assert(false, b, err)
+
+	b, err = options.GetBool("missing", true)
+	assert(true, b, err)
+
+	f, err := options.GetFloat("float", 0)
+	assert(2.0, f, err)
+
+	f, err = options.GetFloat("floatStr", 0)
+	assert(1.5, f, err)
+
+	d, err := options.GetDuration("duration", 0)
+	assert(90*time.Minute, d, err)
+
+	tag, err := options.GetTag("tag", language.Und)
+	assert(language.MustParse("lv-LV"), tag, err)
+
+	s, err := options.GetEnum("enum", "long", "short", "long")
+	assert("short", s, err)
+
+	s, err = options.GetEnum("missing", "long", "short", "long")
+	assert("long", s, err)
+
+	for name, get := range map[string]func() error{
+		"bool":          func() error { _, err := options.GetBool("bad", false); return err },
+		"bool type":     func() error { _, err := options.GetBool("badNumber", false); return err },
+		"float":         func() error { _, err := options.GetFloat("bad", 0); return err },
+		"float min":     func() error { _, err := options.GetFloat("float", 0, eqOrGreaterThan(3.0)); return err },
+		"duration":      func() error { _, err := options.GetDuration("bad", 0); return err },
+		"duration type": func() error { _, err := options.GetDuration("badNumber", 0); return err },
+		"tag":           func() error { _, err := options.GetTag("bad", language.Und); return err },
+		"enum":          func() error { _, err := options.GetEnum("bad", "", "short", "long"); return err },
+		"int":           func() error { _, err := options.GetInt("bad", 0); return err },
+		"validator": func() error {
+			_, err := options.GetString("bad", "", func(string) error { return fmt.Errorf("%w: custom", mf2.ErrBadOption) })
+			return err
+		},
+	} {
+		// the error is wrapped once
+		if err := get(); !errors.Is(err, mf2.ErrBadOption) || strings.Count(err.Error(), mf2.ErrBadOption.Error()) != 1 {
+			t.Errorf("%s: want '%v' once, got '%v'", name, mf2.ErrBadOption, err)
+		}
+	}
+}
@@ -16,6 +16,8 @@ type timeOptions struct {
 	TimeZone *time.Location
 	// The predefined time formatting style to use (full, long, medium, short).
 	Style string
+	// The hour cycle to use (h11, h12, h23, h24) or the "hour12" option, h23 by default.
+	HourCycle string
 }
 
 // parseTimeOptions parses :time options.
@@ -38,11 +40,25 @@ func parseTimeOptions(options Options) (*timeOptions, error) {
 		return errorf("%w", err)
 	}
 
+	if opts.HourCycle, err = parseHourCycle(options); err != nil {
+		return errorf("%w", err)
+	}
+
 	return &opts, nil
 }
 
 // timeFunc is the implementation of the time function. Locale-sensitive time formatting.
-func timeFunc(operand *ResolvedValue, options Options, _ language.Tag) (*ResolvedValue, error) {
+func timeFunc(operand *ResolvedValue, options Options, locale language.Tag) (*ResolvedValue, error) {
+	return formatTime(operand, options, locale, XText{})
+}
+
+// formatTime formats the time with the formatter, see [timeFunc].
+func formatTime(
+	operand *ResolvedValue,
+	options Options,
+	locale language.Tag,
+	formatter DateTimeFormatter,
+) (*ResolvedValue, error) {
 	errorf := func(format string, args ...any) (*ResolvedValue, error) {
 		return nil, fmt.Errorf("exec time function: "+format, args...)
 	}
@@ -53,30 +69,15 @@ func timeFunc(operand *ResolvedValue, options Options, _ language.Tag) (*Resolve
 		return errorf("%w", err)
 	}
 
-	opts, err := parseTimeOptions(options)
+	opts, err := cachedOptions(options, "time", func() (*timeOptions, error) { return parseTimeOptions(options) })
 	if err != nil {
 		return errorf("%w", err)
 	}
 
 	format := func() string {
-		var layout string
-
-		// time styles as per Intl.DateTimeFormat
-		// https://developer.mozilla.org/en-US/docs/Web/JavaScript/Reference/Global_Objects/Intl/DateTimeFormat
-		switch opts.Style {
-		case "full":
-			layout = "15:04:05 MST"
-		case "long":
-			layout = "15:04:05 -0700"
-		case "medium":
-			layout = "15:04:05"
-		case "short":
-			layout = "15:04"
-		}
-
 		value = value.In(opts.TimeZone)
 
-		return value.Format(layout)
+		return formatter.FormatDateTime(value, DateTimeFormat{TimeStyle: opts.Style, HourCycle: opts.HourCycle}, locale)
 	}
 
 	return NewResolvedValue(value, WithFormat(format)), nil
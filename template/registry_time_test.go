@@ -2,6 +2,7 @@ package template
 
 import (
 	"testing"
+	"time"
 
 	"golang.org/x/text/language"
 )
@@ -83,3 +84,74 @@ func Test_Time(t *testing.T) {
 		})
 	}
 }
+
+func Test_TimeHourCycle(t *testing.T) {
+	t.Parallel()
+
+	midnight := time.Date(2021, 1, 2, 0, 30, 0, 0, time.UTC)
+	noon := time.Date(2021, 1, 2, 12, 30, 0, 0, time.UTC)
+
+	for _, test := range []struct {
+		input   time.Time
+		options map[string]any
+		locale  language.Tag
+		want    string
+		wantErr bool
+	}{
+		{input: midnight, locale: language.AmericanEnglish, want: "00:30"},
+		{input: midnight, locale: language.Latvian, want: "00:30"},
+		{input: midnight, locale: language.BritishEnglish, want: "00:30"},
+		{input: midnight, locale: language.Latvian, options: map[string]any{"hourCycle": "h11"}, want: "0:30 AM"},
+		{input: noon, locale: language.Latvian, options: map[string]any{"hourCycle": "h11"}, want: "0:30 PM"},
+		{input: noon, locale: language.Latvian, options: map[string]any{"hourCycle": "h12"}, want: "12:30 PM"},
+		{input: midnight, locale: language.Latvian, options: map[string]any{"hourCycle": "h24"}, want: "24:30"},
+		{input: noon, locale: language.AmericanEnglish, options: map[string]any{"hourCycle": "h23"}, want: "12:30"},
+		{input: midnight, locale: language.AmericanEnglish, options: map[string]any{"hour12": "false"}, want: "00:30"},
+		{
+			input:   midnight,
+			locale:  language.Latvian,
+			options: map[string]any{"hour12": true, "hourCycle": "h23", "style": "medium"},
+			want:    "12:30:00 AM",
+		},
+		{input: midnight, locale: language.Latvian, options: map[string]any{"hour12": "yes"}, wantErr: true},
+		{input: midnight, locale: language.Latvian, options: map[string]any{"hourCycle": "h10"}, wantErr: true},
+	} {
+		opts := make(Options, len(test.options))
+		for k, v := range test.options {
+			opts[k] = NewResolvedValue(v)
+		}
+
+		v, err := timeFunc(NewResolvedValue(test.input), opts, test.locale)
+		if test.wantErr {
+			if err == nil {
+				t.Errorf("%s %v: want error, got nil", test.locale, test.options)
+			}
+
+			continue
+		}
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got := v.format(); test.want != got {
+			t.Errorf("%s %v: want '%s', got '%s'", test.locale, test.options, test.want, got)
+		}
+	}
+
+	// :datetime
+	opts := Options{
+		"dateStyle": NewResolvedValue("medium"),
+		"timeStyle": NewResolvedValue("short"),
+		"hourCycle": NewResolvedValue("h23"),
+	}
+
+	v, err := datetimeFunc(NewResolvedValue(noon), opts, language.AmericanEnglish)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, got := "02 Jan 2021 12:30", v.format(); want != got {
+		t.Errorf("want '%s', got '%s'", want, got)
+	}
+}
@@ -0,0 +1,77 @@
+package template
+
+import (
+	"context"
+	"io"
+
+	"golang.org/x/text/language"
+
+	"go.expect.digital/mf2/internal/lru"
+)
+
+// renderCacheSize is the maximum number of templates cached by [Render].
+const renderCacheSize = 1024
+
+// renderCache is the LRU cache of the templates of [Render].
+var renderCache = newTemplateCache(renderCacheSize)
+
+// Render parses the message src and writes the result to w in the locale, see [Template.Execute].
+// The parsed templates, and the parse errors, are cached by the source, the repeated messages,
+// e.g. in the request handlers, are parsed once. The templates have the default registry,
+// the options customize the execution, e.g. [WithExtraFuncs].
+//
+// Render is safe for concurrent use.
+func Render(w io.Writer, locale language.Tag, src string, input any, options ...ExecuteOption) error {
+	return RenderContext(context.Background(), w, locale, src, input, options...)
+}
+
+// RenderContext is like [Render], the [Values] in ctx are the defaults of the function options.
+func RenderContext(
+	ctx context.Context,
+	w io.Writer,
+	locale language.Tag,
+	src string,
+	input any,
+	options ...ExecuteOption,
+) error {
+	t, err := renderCache.get(src)
+	if err != nil {
+		return err
+	}
+
+	return t.ExecuteContext(ctx, w, input, append([]ExecuteOption{InLocale(locale)}, options...)...)
+}
+
+// templateCache is the LRU cache of the parsed templates by source, see [ast.Cache].
+type templateCache struct {
+	templates *lru.Cache[string, templateEntry]
+}
+
+// templateEntry is the result of [Template.Parse].
+type templateEntry struct {
+	err      error
+	template *Template
+}
+
+func newTemplateCache(size int) *templateCache {
+	return &templateCache{templates: lru.New[string, templateEntry](size, nil)}
+}
+
+// get returns the cached template of the source, or parses the source and caches the result.
+func (c *templateCache) get(src string) (*Template, error) {
+	if entry, ok := c.templates.Get(src); ok {
+		return entry.template, entry.err
+	}
+
+	// parse outside the lock, concurrent misses of the same source are parsed more than once
+	t, err := New().Parse(src)
+
+	c.templates.Add(src, templateEntry{template: t, err: err})
+
+	return t, err
+}
+
+// len returns the number of cached templates.
+func (c *templateCache) len() int {
+	return c.templates.Len()
+}
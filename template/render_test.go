@@ -0,0 +1,72 @@
+package template
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"golang.org/x/text/language"
+
+	"go.expect.digital/mf2"
+)
+
+func TestRender(t *testing.T) {
+	t.Parallel()
+
+	const src = "{ $n :number } { $x :shout }"
+
+	shout := func(operand *ResolvedValue, _ Options, _ language.Tag) (*ResolvedValue, error) {
+		return NewResolvedValue(operand.String() + "!"), nil
+	}
+
+	for _, test := range []struct {
+		locale language.Tag
+		want   string
+	}{
+		{locale: language.English, want: "1,000.5 hi!"},
+		{locale: language.German, want: "1.000,5 hi!"},
+	} {
+		var buf bytes.Buffer
+
+		err := Render(&buf, test.locale, src, map[string]any{"n": 1000.5, "x": "hi"}, WithExtraFuncs(Registry{"shout": shout}))
+		if err != nil {
+			t.Error(err)
+		}
+
+		if got := buf.String(); test.want != got {
+			t.Errorf("want '%s', got '%s'", test.want, got)
+		}
+	}
+
+	first, _ := renderCache.get(src)
+	if second, _ := renderCache.get(src); first != second {
+		t.Error("want cached template, got parsed")
+	}
+
+	var buf bytes.Buffer
+
+	if err := Render(&buf, language.English, "{ $x", nil); !errors.Is(err, mf2.ErrSyntax) {
+		t.Errorf("want '%s', got '%v'", mf2.ErrSyntax, err)
+	}
+}
+
+func TestTemplateCache(t *testing.T) {
+	t.Parallel()
+
+	cache := newTemplateCache(2)
+
+	for _, src := range []string{"a", "b", "a", "c"} {
+		if _, err := cache.get(src); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if got := cache.len(); got != 2 {
+		t.Errorf("want 2 cached templates, got %d", got)
+	}
+
+	// "b" is the least recently used
+	if cache.templates.Contains("b") {
+		t.Error("want evicted template, got cached")
+	}
+}
@@ -0,0 +1,197 @@
+package template
+
+import (
+	"math"
+	"slices"
+	"strconv"
+
+	"golang.org/x/text/unicode/norm"
+
+	"go.expect.digital/mf2"
+	ast "go.expect.digital/mf2/parse"
+)
+
+// fallbackMessage is the output of the matcher without the selected variant, the fallback
+// representation of the value without the source.
+const fallbackMessage = "{\uFFFD}"
+
+// selectionPlan is the matcher with the variant keys precomputed when the template is parsed,
+// the keys are not stringified and scanned again on every execution.
+type selectionPlan struct {
+	// keys are the unique keys of every selector, catch-all keys excluded.
+	keys     [][]string
+	variants []plannedVariant
+}
+
+// plannedVariant is the variant with the precomputed keys.
+type plannedVariant struct {
+	// keys are raw string values of the keys, e.g. "1" for both `1` and `|1|`.
+	keys []string
+	// catchAll is true for the catch-all key "*".
+	catchAll []bool
+	pattern  ast.QuotedPattern
+	// options are the compiled options of the pattern expressions, see [expressionPlan].
+	options []*compiledOptions
+}
+
+func newSelectionPlan(m ast.Matcher) *selectionPlan {
+	plan := &selectionPlan{
+		keys:     make([][]string, len(m.Selectors)),
+		variants: make([]plannedVariant, 0, len(m.Variants)),
+	}
+
+	for _, variant := range m.Variants {
+		v := plannedVariant{
+			keys:     make([]string, len(variant.Keys)),
+			catchAll: make([]bool, len(variant.Keys)),
+			pattern:  variant.QuotedPattern,
+			options:  compilePattern(variant.QuotedPattern),
+		}
+
+		for i, key := range variant.Keys {
+			// NOTE(mvilks): since collected keys will be compared to the selector,
+			//	we need the keys's raw string value, not the representation of it
+			//  e.g. the `1` should be equal to `|1|`
+			switch key := key.(type) {
+			case ast.CatchAllKey:
+				v.catchAll[i] = true
+				continue
+			case ast.QuotedLiteral:
+				v.keys[i] = norm.NFC.String(string(key))
+			case ast.NameLiteral:
+				v.keys[i] = norm.NFC.String(string(key))
+			case ast.NumberLiteral:
+				v.keys[i] = key.String()
+			}
+
+			if i < len(plan.keys) && !slices.Contains(plan.keys[i], v.keys[i]) {
+				plan.keys[i] = append(plan.keys[i], v.keys[i])
+			}
+		}
+
+		plan.variants = append(plan.variants, v)
+	}
+
+	return plan
+}
+
+// resolvePreferences returns the matching keys of every selector in order of preference.
+func (p *selectionPlan) resolvePreferences(res []any) [][]string {
+	// Step 2: Resolve Preferences
+	pref := make([][]string, 0, len(res))
+
+	for i, rv := range res {
+		var keys []string
+		if i < len(p.keys) {
+			keys = p.keys[i]
+		}
+
+		pref = append(pref, matchSelectorKeys(rv, keys))
+	}
+
+	return pref
+}
+
+// filterVariants returns the variants with the keys matching all selectors.
+func (p *selectionPlan) filterVariants(pref [][]string) []plannedVariant {
+	// Step 3: Filter Variants
+	var filteredVariants []plannedVariant
+
+	for _, variant := range p.variants {
+		matchesAllSelectors := true
+
+		for i, keyOrder := range pref {
+			if variant.catchAll[i] {
+				continue
+			}
+
+			if !slices.Contains(keyOrder, variant.keys[i]) {
+				matchesAllSelectors = false
+				break
+			}
+		}
+
+		if matchesAllSelectors {
+			filteredVariants = append(filteredVariants, variant)
+		}
+	}
+
+	return filteredVariants
+}
+
+// bestMatchedVariant returns the most preferred variant. No variant matches only if
+// the fallback variant is missing, e.g. the message parsed with [ast.WithoutValidation].
+func (p *selectionPlan) bestMatchedVariant(filteredVariants []plannedVariant, pref [][]string) (plannedVariant, error) {
+	if len(filteredVariants) == 0 {
+		return plannedVariant{}, mf2.ErrMissingFallbackVariant
+	}
+
+	// Step 4: Sort Variants
+	sortable := make([]sortableVariant, 0, len(filteredVariants))
+
+	for _, variant := range filteredVariants {
+		sortable = append(sortable, sortableVariant{Score: -1, Variant: variant})
+	}
+
+	for i := len(pref) - 1; i >= 0; i-- {
+		matches := pref[i]
+
+		for tupleIndex, tuple := range sortable {
+			if tuple.Variant.catchAll[i] {
+				sortable[tupleIndex].Score = len(matches)
+				continue
+			}
+
+			sortable[tupleIndex].Score = slices.Index(matches, tuple.Variant.keys[i])
+		}
+
+		// the sort must be stable, the order of the previous selectors is kept for equal scores
+		slices.SortStableFunc(sortable, func(a, b sortableVariant) int { return a.Score - b.Score })
+	}
+
+	return sortable[0].Variant, nil
+}
+
+// matchSelectorKeys returns the keys matching the resolved selector in order of preference.
+func matchSelectorKeys(rv any, keys []string) []string {
+	if v, ok := rv.(*ResolvedValue); ok {
+		return v.matches(keys)
+	}
+
+	return defaultMatch(rv, keys)
+}
+
+// filterKeys returns the normalized preferred keys found in the keys, without the duplicates
+// and the catch-all key.
+func filterKeys(preferred, keys []string) []string {
+	var matches []string
+
+	for _, p := range preferred {
+		p = NormalizeKey(p)
+
+		if !IsCatchAll(p) && slices.Contains(keys, p) && !slices.Contains(matches, p) {
+			matches = append(matches, p)
+		}
+	}
+
+	return matches
+}
+
+// numericKeys returns the keys numerically equal to the value, e.g. "1" and "1.0" for 1.
+func numericKeys(value float64, keys []string) []string {
+	var matches []string
+
+	for _, key := range keys {
+		// ParseFloat accepts "Inf" and "NaN", they are not number literals
+		if v, err := strconv.ParseFloat(key, 64); err == nil && !math.IsInf(v, 0) && v == value {
+			matches = append(matches, key)
+		}
+	}
+
+	return matches
+}
+
+type sortableVariant struct {
+	Variant plannedVariant
+	Score   int
+}
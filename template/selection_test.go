@@ -0,0 +1,55 @@
+package template
+
+import (
+	"reflect"
+	"testing"
+
+	ast "go.expect.digital/mf2/parse"
+)
+
+func TestNewSelectionPlan(t *testing.T) {
+	t.Parallel()
+
+	tree, err := ast.Parse(".match { $a :string } { $b :number } a |1| {{}} |a| 1 {{}} b * {{}} * 2 {{}} * * {{}}")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	plan := newSelectionPlan(tree.Message.(ast.ComplexMessage).ComplexBody.(ast.Matcher)) //nolint:forcetypeassert
+
+	if want := [][]string{{"a", "b"}, {"1", "2"}}; !reflect.DeepEqual(want, plan.keys) {
+		t.Errorf("want %v, got %v", want, plan.keys)
+	}
+
+	if want := []bool{true, false}; !reflect.DeepEqual(want, plan.variants[3].catchAll) {
+		t.Errorf("want %v, got %v", want, plan.variants[3].catchAll)
+	}
+}
+
+func TestSelectionPlan_bestMatchedVariant(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		input map[string]any
+		want  string
+	}{
+		{input: map[string]any{"a": "a", "b": "b"}, want: "a b"},
+		{input: map[string]any{"a": "a", "b": "x"}, want: "a *"},
+		{input: map[string]any{"a": "x", "b": "b"}, want: "* b"},
+		{input: map[string]any{"a": "x", "b": "x"}, want: "* *"},
+	} {
+		template, err := New().Parse(".match { $a :string } { $b :string } * * {{* *}} * b {{* b}} a * {{a *}} a b {{a b}}")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := template.Sprint(test.input)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if test.want != got {
+			t.Errorf("want '%s', got '%s'", test.want, got)
+		}
+	}
+}
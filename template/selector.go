@@ -0,0 +1,72 @@
+package template
+
+import (
+	"golang.org/x/text/unicode/norm"
+)
+
+// CatchAllKey is the key "*" of the variant that matches any value, see [SelectKey].
+const CatchAllKey = "*"
+
+// NormalizeKey returns the key in the form the variant keys are compared in.
+// The parser normalizes the keys to NFC, the values selected by the custom functions must be too.
+func NormalizeKey(key string) string {
+	return norm.NFC.String(key)
+}
+
+// IsCatchAll reports whether the key is the catch-all key "*".
+func IsCatchAll(key string) bool {
+	return key == CatchAllKey
+}
+
+// SelectKey returns the first of the preferred keys found in the variant keys,
+// or [CatchAllKey] if none is found. The preferred keys are normalized, see [NormalizeKey].
+//
+// The custom select functions use it to match the keys the same way as the built-ins,
+// e.g. :number prefers the exact value to the plural category:
+//
+//	WithSelectKey(func(keys []string) string {
+//		return SelectKey(keys, "1", "one")
+//	})
+func SelectKey(keys []string, preferred ...string) string {
+	for _, p := range preferred {
+		p = NormalizeKey(p)
+
+		for _, key := range keys {
+			if key == p && !IsCatchAll(key) {
+				return key
+			}
+		}
+	}
+
+	return CatchAllKey
+}
+
+// WithPreferredKeys sets the selection of the ResolvedValue to the preferred keys found in the variant keys,
+// the first found is selected, see [SelectKey] and [WithMatch].
+func WithPreferredKeys(preferred ...string) ResolvedValueOpt {
+	return WithMatch(func([]string) []string {
+		return preferred
+	})
+}
+
+// PossibleKeys declares the keys the select functions can select by the function name, the catch-all
+// key excluded, e.g. {"boolean": {"true", "false"}}. The other keys of the selector are reported
+// as unreachable, see [UnreachableVariants].
+type PossibleKeys map[string][]string
+
+// builtinKeys are the possible keys of the select functions of the default registry,
+// the keys of :number and :integer depend on the locale and options.
+var builtinKeys = PossibleKeys{
+	"boolean": {"true", "false"},
+}
+
+// WithPossibleKeys declares the keys the custom select function can select, see [PossibleKeys].
+func WithPossibleKeys(name string, keys ...string) Option {
+	return func(t *Template) {
+		if t.possibleKeys == nil {
+			t.possibleKeys = make(PossibleKeys)
+		}
+
+		t.possibleKeys[name] = keys
+	}
+}
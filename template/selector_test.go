@@ -0,0 +1,162 @@
+package template
+
+import (
+	"slices"
+	"testing"
+
+	"golang.org/x/text/language"
+)
+
+func TestSelectKey(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		name      string
+		keys      []string
+		preferred []string
+		want      string
+	}{
+		{name: "first preferred", keys: []string{"one", "1", "*"}, preferred: []string{"1", "one"}, want: "1"},
+		{name: "next preferred", keys: []string{"one", "*"}, preferred: []string{"1", "one"}, want: "one"},
+		{name: "catch-all", keys: []string{"other"}, preferred: []string{"1", "one"}, want: "*"},
+		{name: "catch-all preferred", keys: []string{"*"}, preferred: []string{"*"}, want: "*"},
+		{name: "normalized", keys: []string{"\u00e9"}, preferred: []string{"e\u0301"}, want: "\u00e9"},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := SelectKey(test.keys, test.preferred...); test.want != got {
+				t.Errorf("want '%s', got '%s'", test.want, got)
+			}
+		})
+	}
+}
+
+func TestWithPreferredKeys(t *testing.T) {
+	t.Parallel()
+
+	// size selects the exact size, then its group
+	size := func(operand *ResolvedValue, _ Options, _ language.Tag) (*ResolvedValue, error) {
+		s := operand.String()
+
+		group := "large"
+		if s == "XS" || s == "S" {
+			group = "small"
+		}
+
+		return NewResolvedValue(s, WithPreferredKeys(s, group)), nil
+	}
+
+	template, err := New(WithFunc("size", size)).Parse(".match {$s :size} S {{S}} small {{small}} * {{other}}")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for in, want := range map[string]string{"S": "S", "XS": "small", "L": "other"} {
+		if got, err := template.Sprint(map[string]any{"s": in}); err != nil || want != got {
+			t.Errorf("want '%s', got '%s' (%v)", want, got, err)
+		}
+	}
+}
+
+func TestWithMatch(t *testing.T) {
+	t.Parallel()
+
+	// value outputs the operand value, the keys are matched by its type
+	value := func(operand *ResolvedValue, _ Options, _ language.Tag) (*ResolvedValue, error) {
+		return NewResolvedValue(operand.value), nil
+	}
+
+	// tier matches the tier and the lower tiers, the nearest first
+	tier := func(operand *ResolvedValue, _ Options, _ language.Tag) (*ResolvedValue, error) {
+		tiers := []string{"gold", "silver", "bronze"}
+		i := slices.Index(tiers, operand.String())
+
+		return NewResolvedValue(operand, WithMatch(func([]string) []string {
+			if i < 0 {
+				return nil
+			}
+
+			return tiers[i:]
+		})), nil
+	}
+
+	for _, test := range []struct {
+		name, text string
+		input      map[string]any
+		want       string
+	}{
+		{
+			name:  "int",
+			text:  ".match {$v :value} 1.0 {{one}} * {{other}}",
+			input: map[string]any{"v": 1},
+			want:  "one",
+		},
+		{
+			name:  "float",
+			text:  ".match {$v :value} 2 {{two}} * {{other}}",
+			input: map[string]any{"v": float32(2)},
+			want:  "two",
+		},
+		{
+			name:  "keys in order",
+			text:  ".match {$v :value} a {{a}} b {{b}} * {{other}}",
+			input: map[string]any{"v": []string{"c", "b", "a"}},
+			want:  "b",
+		},
+		{
+			name:  "bool",
+			text:  ".match {$v :value} true {{yes}} * {{no}}",
+			input: map[string]any{"v": true},
+			want:  "yes",
+		},
+		{
+			name:  "no match",
+			text:  ".match {$v :value} a {{a}} * {{other}}",
+			input: map[string]any{"v": []string(nil)},
+			want:  "other",
+		},
+		{
+			name:  "custom",
+			text:  ".match {$t :tier} {$n :number} silver 1 {{silver one}} bronze 2 {{bronze two}} * * {{other}}",
+			input: map[string]any{"t": "gold", "n": 2},
+			want:  "bronze two",
+		},
+		{
+			name:  "custom catch-all",
+			text:  ".match {$t :tier} gold {{gold}} * {{other}}",
+			input: map[string]any{"t": "tin"},
+			want:  "other",
+		},
+		{
+			name:  "number exact and category",
+			text:  ".match {$n :number} {$s :string} 1 b {{exact}} one a {{one}} * * {{other}}",
+			input: map[string]any{"n": 1, "s": "a"},
+			want:  "one",
+		},
+		{
+			name:  "declared selector",
+			text:  ".input {$n :number} .match {$n} 1 {{exact}} one {{one}} * {{other}}",
+			input: map[string]any{"n": 1},
+			want:  "exact",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			template, err := New(WithFunc("value", value), WithFunc("tier", tier)).Parse(test.text)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := template.Sprint(test.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if test.want != got {
+				t.Errorf("want '%s', got '%s'", test.want, got)
+			}
+		})
+	}
+}
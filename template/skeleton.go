@@ -0,0 +1,184 @@
+package template
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.expect.digital/mf2"
+)
+
+// skeletonOption is the option of the number functions with the ICU number skeleton, see [expandSkeleton].
+const skeletonOption = "skeleton"
+
+// expandSkeleton returns the options with the ICU number skeleton of the "skeleton" option replaced
+// by the equivalent options, e.g. "skeleton=|.00 group-off|" is "minimumFractionDigits=2
+// maximumFractionDigits=2 useGrouping=never". The other options of the expression take precedence.
+//
+// See https://unicode-org.github.io/icu/userguide/format_parse/numbers/skeletons.html.
+func expandSkeleton(opts Options) (Options, error) {
+	v, ok := opts[skeletonOption]
+	if !ok {
+		return opts, nil
+	}
+
+	s, ok := v.value.(string)
+	if !ok {
+		return nil, fmt.Errorf(`%w: want "%s" as string, got %T`, mf2.ErrBadOption, skeletonOption, v.value)
+	}
+
+	skeleton, err := parseSkeleton(s)
+	if err != nil {
+		return nil, fmt.Errorf(`%w: %s "%s": %w`, mf2.ErrBadOption, skeletonOption, s, err)
+	}
+
+	expanded := make(Options, len(opts)+len(skeleton))
+
+	for k, v := range skeleton {
+		expanded[k] = NewResolvedValue(v)
+	}
+
+	for k, v := range opts {
+		if k != skeletonOption {
+			expanded[k] = v
+		}
+	}
+
+	return expanded, nil
+}
+
+// skeletonMaxFractionDigits is the maximum fraction digits of the unlimited fraction precision, e.g. ".00*",
+// the limit of ECMA-402.
+const skeletonMaxFractionDigits = 100
+
+// skeletonStems are the ICU number skeleton stems without the stem options, long and concise,
+// by the equivalent options. The stems of the options not implemented by the formatting,
+// e.g. "compact-short", "scientific" or "currency/EUR", are unsupported.
+var skeletonStems = map[string]map[string]any{
+	"notation-simple":   {"notation": "standard"},
+	"%x100":             {"style": "percent"},
+	"precision-integer": {"maximumFractionDigits": 0},
+	".":                 {"maximumFractionDigits": 0},
+	"group-off":         {"useGrouping": "never"},
+	",_":                {"useGrouping": "never"},
+	"group-min2":        {"useGrouping": "min2"},
+	",?":                {"useGrouping": "min2"},
+	"group-auto":        {"useGrouping": "auto"},
+	"group-on-aligned":  {"useGrouping": "always"},
+	",!":                {"useGrouping": "always"},
+	"group-thousands":   {"useGrouping": "always"},
+	",=":                {"useGrouping": "always"},
+	"sign-auto":         {"signDisplay": "auto"},
+	"sign-always":       {"signDisplay": "always"},
+	"+!":                {"signDisplay": "always"},
+	"sign-never":        {"signDisplay": "never"},
+	"+_":                {"signDisplay": "never"},
+	"sign-except-zero":  {"signDisplay": "exceptZero"},
+	"+?":                {"signDisplay": "exceptZero"},
+	"sign-negative":     {"signDisplay": "negative"},
+	"+-":                {"signDisplay": "negative"},
+}
+
+// parseSkeleton returns the options equivalent to the ICU number skeleton, e.g. ".00 sign-always".
+// The percent is the ratio as in "style=percent", "percent scale/100" or "%x100".
+func parseSkeleton(skeleton string) (map[string]any, error) {
+	options := make(map[string]any)
+
+	set := func(values map[string]any) {
+		for k, v := range values {
+			options[k] = v
+		}
+	}
+
+	var percent, scaled bool
+
+	for _, token := range strings.Fields(skeleton) {
+		if values, ok := skeletonStems[token]; ok {
+			set(values)
+			continue
+		}
+
+		stem, option, hasOption := strings.Cut(token, "/")
+
+		switch {
+		default:
+			return nil, fmt.Errorf(`unsupported stem "%s"`, token)
+		case stem == "percent" || stem == "%":
+			percent = true
+		case stem == "scale" && option == "100":
+			scaled = true
+		case stem == "integer-width" && hasOption:
+			digits, ok := skeletonIntegerWidth(strings.TrimLeft(option, "*+"))
+			if !ok {
+				return nil, fmt.Errorf(`invalid integer width "%s"`, token)
+			}
+
+			options["minimumIntegerDigits"] = digits
+		case strings.Trim(token, "0") == "":
+			options["minimumIntegerDigits"] = len(token)
+		case strings.HasPrefix(token, "."):
+			minimum, maximum, ok := skeletonDigits(token[1:], '0')
+			if !ok {
+				return nil, fmt.Errorf(`invalid fraction precision "%s"`, token)
+			}
+
+			options["minimumFractionDigits"] = minimum
+
+			if maximum < 0 {
+				maximum = skeletonMaxFractionDigits
+			}
+
+			options["maximumFractionDigits"] = maximum
+		case strings.HasPrefix(token, "@"):
+			minimum, maximum, ok := skeletonDigits(token, '@')
+			if !ok {
+				return nil, fmt.Errorf(`invalid significant precision "%s"`, token)
+			}
+
+			options["minimumSignificantDigits"] = minimum
+
+			if maximum >= 0 {
+				options["maximumSignificantDigits"] = maximum
+			}
+		}
+	}
+
+	switch {
+	case percent && scaled:
+		options["style"] = "percent"
+	case percent:
+		return nil, errors.New(`want "percent scale/100", the percent is the ratio`)
+	case scaled:
+		return nil, errors.New(`want "percent scale/100", the scale is only supported for percent`)
+	}
+
+	return options, nil
+}
+
+// skeletonIntegerWidth returns the minimum integer digits of the integer width, e.g. 2 for "##00".
+func skeletonIntegerWidth(width string) (int, bool) {
+	digits := strings.TrimLeft(width, "#")
+	if digits == "" || strings.Trim(digits, "0") != "" {
+		return 0, false
+	}
+
+	return len(digits), true
+}
+
+// skeletonDigits returns the minimum and maximum digits of the precision, e.g. 2 and 3 for "00#"
+// with the required digit '0', or "@@#" with '@'. The maximum is -1 if unlimited, e.g. "00*" or "00+".
+func skeletonDigits(precision string, required byte) (int, int, bool) {
+	minimum := len(precision) - len(strings.TrimLeft(precision, string(required)))
+	rest := precision[minimum:]
+
+	if rest == "*" || rest == "+" {
+		return minimum, -1, true
+	}
+
+	optional := len(rest) - len(strings.TrimLeft(rest, "#"))
+	if optional != len(rest) {
+		return 0, 0, false
+	}
+
+	return minimum, minimum + optional, true
+}
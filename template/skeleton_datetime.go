@@ -0,0 +1,566 @@
+package template
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"golang.org/x/text/language"
+)
+
+// dateTimeData are the names and the skeleton patterns of the locale, a subset of CLDR,
+// x/text does not provide them.
+type dateTimeData struct {
+	// formats are the availableFormats patterns by skeleton.
+	formats map[string]string
+	// glue is the pattern joining the date "{1}" and the time "{0}".
+	glue string
+	// months and shortMonths are the month names of the format context, e.g. "15 June".
+	months, shortMonths [12]string
+	// standaloneMonths and standaloneShortMonths are the month names of the stand-alone context,
+	// e.g. "June 2024", empty if the same as the format context.
+	standaloneMonths, standaloneShortMonths [12]string
+	// weekdays and shortWeekdays are the weekday names from Sunday.
+	weekdays, shortWeekdays [7]string
+	// dayPeriods are the "am" and "pm" names.
+	dayPeriods [2]string
+}
+
+// timeFormats are the availableFormats time patterns of the locales using the 24-hour "HH:mm".
+var timeFormats = map[string]string{
+	"H": "HH", "Hm": "HH:mm", "Hms": "HH:mm:ss", "h": "h a", "hm": "h:mm a", "hms": "h:mm:ss a", "ms": "mm:ss",
+}
+
+// withFormats returns the formats with the time formats, the given formats win.
+func withFormats(formats map[string]string) map[string]string {
+	for k, v := range timeFormats {
+		if _, ok := formats[k]; !ok {
+			formats[k] = v
+		}
+	}
+
+	return formats
+}
+
+var dateTimeLocales = map[string]*dateTimeData{
+	"en": {
+		formats: withFormats(map[string]string{
+			"y": "y", "yM": "M/y", "yMd": "M/d/y", "yMEd": "E, M/d/y", "yMMM": "MMM y", "yMMMd": "MMM d, y",
+			"yMMMEd": "E, MMM d, y", "yMMMM": "MMMM y", "M": "L", "Md": "M/d", "MEd": "E, M/d", "MMM": "LLL",
+			"MMMd": "MMM d", "MMMEd": "E, MMM d", "MMMMd": "MMMM d", "d": "d", "Ed": "d E", "E": "ccc",
+		}),
+		glue: "{1}, {0}",
+		months: [12]string{
+			"January", "February", "March", "April", "May", "June",
+			"July", "August", "September", "October", "November", "December",
+		},
+		shortMonths:   [12]string{"Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sep", "Oct", "Nov", "Dec"},
+		weekdays:      [7]string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"},
+		shortWeekdays: [7]string{"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"},
+		dayPeriods:    [2]string{"AM", "PM"},
+	},
+	"de": {
+		formats: withFormats(map[string]string{
+			"y": "y", "yM": "M/y", "yMd": "d.M.y", "yMEd": "E, d.M.y", "yMMM": "MMM y", "yMMMd": "d. MMM y",
+			"yMMMEd": "E, d. MMM y", "yMMMM": "MMMM y", "M": "L", "Md": "d.M.", "MEd": "E, d.M.", "MMM": "LLL",
+			"MMMd": "d. MMM", "MMMEd": "E, d. MMM", "MMMMd": "d. MMMM", "d": "d", "Ed": "E, d.", "E": "ccc",
+			"H": "HH 'Uhr'", "h": "h 'Uhr' a",
+		}),
+		glue: "{1}, {0}",
+		months: [12]string{
+			"Januar", "Februar", "März", "April", "Mai", "Juni",
+			"Juli", "August", "September", "Oktober", "November", "Dezember",
+		},
+		shortMonths: [12]string{
+			"Jan.", "Feb.", "März", "Apr.", "Mai", "Juni", "Juli", "Aug.", "Sept.", "Okt.", "Nov.", "Dez.",
+		},
+		weekdays:      [7]string{"Sonntag", "Montag", "Dienstag", "Mittwoch", "Donnerstag", "Freitag", "Samstag"},
+		shortWeekdays: [7]string{"So.", "Mo.", "Di.", "Mi.", "Do.", "Fr.", "Sa."},
+		dayPeriods:    [2]string{"AM", "PM"},
+	},
+	"es": {
+		formats: withFormats(map[string]string{
+			"y": "y", "yM": "M/y", "yMd": "d/M/y", "yMEd": "EEE, d/M/y", "yMMM": "MMM y", "yMMMd": "d MMM y",
+			"yMMMEd": "EEE, d MMM y", "yMMMM": "MMMM 'de' y", "M": "L", "Md": "d/M", "MEd": "E, d/M", "MMM": "LLL",
+			"MMMd": "d MMM", "MMMEd": "E, d MMM", "MMMMd": "d 'de' MMMM", "d": "d", "Ed": "E d", "E": "ccc",
+			"H": "H", "Hm": "H:mm", "Hms": "H:mm:ss",
+		}),
+		glue: "{1}, {0}",
+		months: [12]string{
+			"enero", "febrero", "marzo", "abril", "mayo", "junio",
+			"julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre",
+		},
+		shortMonths: [12]string{
+			"ene", "feb", "mar", "abr", "may", "jun", "jul", "ago", "sept", "oct", "nov", "dic",
+		},
+		weekdays:      [7]string{"domingo", "lunes", "martes", "miércoles", "jueves", "viernes", "sábado"},
+		shortWeekdays: [7]string{"dom", "lun", "mar", "mié", "jue", "vie", "sáb"},
+		dayPeriods:    [2]string{"a. m.", "p. m."},
+	},
+	"fr": {
+		formats: withFormats(map[string]string{
+			"y": "y", "yM": "MM/y", "yMd": "dd/MM/y", "yMEd": "E dd/MM/y", "yMMM": "MMM y", "yMMMd": "d MMM y",
+			"yMMMEd": "E d MMM y", "yMMMM": "MMMM y", "M": "L", "Md": "dd/MM", "MEd": "E dd/MM", "MMM": "LLL",
+			"MMMd": "d MMM", "MMMEd": "E d MMM", "MMMMd": "d MMMM", "d": "d", "Ed": "E d", "E": "E",
+			"H": "HH 'h'",
+		}),
+		glue: "{1} {0}",
+		months: [12]string{
+			"janvier", "février", "mars", "avril", "mai", "juin",
+			"juillet", "août", "septembre", "octobre", "novembre", "décembre",
+		},
+		shortMonths: [12]string{
+			"janv.", "févr.", "mars", "avr.", "mai", "juin", "juil.", "août", "sept.", "oct.", "nov.", "déc.",
+		},
+		weekdays:      [7]string{"dimanche", "lundi", "mardi", "mercredi", "jeudi", "vendredi", "samedi"},
+		shortWeekdays: [7]string{"dim.", "lun.", "mar.", "mer.", "jeu.", "ven.", "sam."},
+		dayPeriods:    [2]string{"AM", "PM"},
+	},
+	"lv": {
+		formats: withFormats(map[string]string{
+			"y": "y. 'g'.", "yM": "MM.y.", "yMd": "d.MM.y.", "yMEd": "EEE, d.M.y.", "yMMM": "y. 'g'. MMM",
+			"yMMMd": "y. 'gada' d. MMM", "yMMMEd": "EEE, y. 'gada' d. MMM", "yMMMM": "y. 'g'. MMMM",
+			"M": "L", "Md": "dd.MM.", "MEd": "E, dd.MM.", "MMM": "LLL", "MMMd": "d. MMM", "MMMEd": "E, d. MMM",
+			"MMMMd": "d. MMMM", "d": "d", "Ed": "E, d.", "E": "ccc",
+		}),
+		glue: "{1} {0}",
+		months: [12]string{
+			"janvāris", "februāris", "marts", "aprīlis", "maijs", "jūnijs",
+			"jūlijs", "augusts", "septembris", "oktobris", "novembris", "decembris",
+		},
+		shortMonths: [12]string{
+			"janv.", "febr.", "marts", "apr.", "maijs", "jūn.", "jūl.", "aug.", "sept.", "okt.", "nov.", "dec.",
+		},
+		weekdays: [7]string{
+			"svētdiena", "pirmdiena", "otrdiena", "trešdiena", "ceturtdiena", "piektdiena", "sestdiena",
+		},
+		shortWeekdays: [7]string{"svētd.", "pirmd.", "otrd.", "trešd.", "ceturtd.", "piektd.", "sestd."},
+		dayPeriods:    [2]string{"priekšpusdienā", "pēcpusdienā"},
+	},
+	"ru": {
+		formats: withFormats(map[string]string{
+			"y": "y", "yM": "MM.y", "yMd": "dd.MM.y", "yMEd": "ccc, dd.MM.y 'г'.", "yMMM": "LLL y 'г'.",
+			"yMMMd": "d MMM y 'г'.", "yMMMEd": "E, d MMM y 'г'.", "yMMMM": "LLLL y 'г'.", "M": "L", "Md": "dd.MM",
+			"MEd": "E, dd.MM", "MMM": "LLL", "MMMd": "d MMM", "MMMEd": "ccc, d MMM", "MMMMd": "d MMMM", "d": "d",
+			"Ed": "ccc, d", "E": "ccc",
+		}),
+		glue: "{1}, {0}",
+		months: [12]string{
+			"января", "февраля", "марта", "апреля", "мая", "июня",
+			"июля", "августа", "сентября", "октября", "ноября", "декабря",
+		},
+		shortMonths: [12]string{
+			"янв.", "февр.", "мар.", "апр.", "мая", "июн.", "июл.", "авг.", "сент.", "окт.", "нояб.", "дек.",
+		},
+		standaloneMonths: [12]string{
+			"январь", "февраль", "март", "апрель", "май", "июнь",
+			"июль", "август", "сентябрь", "октябрь", "ноябрь", "декабрь",
+		},
+		standaloneShortMonths: [12]string{
+			"янв.", "февр.", "март", "апр.", "май", "июнь", "июль", "авг.", "сент.", "окт.", "нояб.", "дек.",
+		},
+		weekdays: [7]string{
+			"воскресенье", "понедельник", "вторник", "среда", "четверг", "пятница", "суббота",
+		},
+		shortWeekdays: [7]string{"вс", "пн", "вт", "ср", "чт", "пт", "сб"},
+		dayPeriods:    [2]string{"AM", "PM"},
+	},
+}
+
+// localeDateTimeData returns the date and time data of the locale, English if the locale has no data.
+func localeDateTimeData(locale language.Tag) *dateTimeData {
+	base, _ := locale.Base()
+
+	if data, ok := dateTimeLocales[base.String()]; ok {
+		return data
+	}
+
+	return dateTimeLocales["en"]
+}
+
+// skeletonField is the field of the date-time skeleton or pattern, e.g. "MMM" is 'M' of width 3.
+type skeletonField struct {
+	letter byte
+	width  int
+}
+
+func (f skeletonField) String() string {
+	return strings.Repeat(string(f.letter), f.width)
+}
+
+// skeletonPattern returns the best pattern of the locale for the CLDR date-time skeleton, e.g. "MMM d, y, HH:mm"
+// for "yMMMd Hm" in English. The skeleton fields are the year "y", the month "M" or "L", the day "d",
+// the weekday "E" or "c", the hour "j" of the hour cycle, "h", "H", "K" or "k", the minute "m", the second "s",
+// and the time zone "z", "zzzz", "O", "OOOO", "v" or "vvvv". The whitespace is ignored.
+//
+// The hour cycle h11 or h24 replaces the hours of the 12-hour or 24-hour clock of "j", e.g. "K" for h11,
+// "j" is the locale's preferred clock without the hour cycle.
+func skeletonPattern(skeleton, hourCycle string, locale language.Tag) (string, error) {
+	errorf := func(format string, args ...any) (string, error) {
+		return "", fmt.Errorf("skeleton \"%s\": "+format, append([]any{skeleton}, args...)...)
+	}
+
+	fields := make(map[byte]skeletonField)
+
+	for _, f := range parsePatternFields(strings.Join(strings.Fields(skeleton), "")) {
+		key := f.letter // the field of the same meaning, e.g. 'M' for "MMM" and "LLL"
+
+		switch f.letter {
+		default:
+			return errorf(`unsupported field "%s"`, f)
+		case 0:
+			return errorf(`unexpected literal "%s"`, f.literal)
+		case 'y', 'M', 'd', 'E', 'm', 's':
+		case 'L':
+			key = 'M'
+		case 'c':
+			key = 'E'
+		case 'j':
+			if hourCycle == "" {
+				hourCycle = defaultHourCycle(locale)
+			}
+
+			f.letter = 'H'
+			if hourCycle == "h11" || hourCycle == "h12" {
+				f.letter = 'h'
+			}
+
+			f.letter, key = hourLetter(f.letter, hourCycle), 'H'
+		case 'h', 'H', 'K', 'k':
+			key = 'H'
+		case 'z', 'O', 'v':
+			key = 'z'
+		}
+
+		if _, ok := fields[key]; ok {
+			return errorf(`duplicate field "%s"`, f)
+		}
+
+		fields[key] = f.skeletonField
+	}
+
+	data := localeDateTimeData(locale)
+
+	date, err := skeletonDate(fields, data)
+	if err != nil {
+		return errorf("%w", err)
+	}
+
+	clock, err := skeletonTime(fields, data)
+	if err != nil {
+		return errorf("%w", err)
+	}
+
+	switch {
+	case date == "" && clock == "":
+		return errorf("no fields")
+	case date == "":
+		return clock, nil
+	case clock == "":
+		return date, nil
+	default:
+		return strings.NewReplacer("{1}", date, "{0}", clock).Replace(data.glue), nil
+	}
+}
+
+// hourLetter returns the hour field letter of the hour cycle, e.g. 'K' for 'h' and h11.
+func hourLetter(letter byte, hourCycle string) byte {
+	switch {
+	case letter == 'h' && hourCycle == "h11":
+		return 'K'
+	case letter == 'H' && hourCycle == "h24":
+		return 'k'
+	default:
+		return letter
+	}
+}
+
+// skeletonDate returns the date pattern of the date fields, empty if there are no date fields.
+func skeletonDate(fields map[byte]skeletonField, data *dateTimeData) (string, error) {
+	var key strings.Builder
+
+	for _, letter := range []byte("yMEd") {
+		f, ok := fields[letter]
+		if !ok {
+			continue
+		}
+
+		switch {
+		case letter == 'M' && f.width >= 3: //nolint:mnd
+			key.WriteString(strings.Repeat("M", min(f.width, 4))) //nolint:mnd
+		default:
+			key.WriteByte(letter)
+		}
+	}
+
+	if key.Len() == 0 {
+		return "", nil
+	}
+
+	pattern, ok := data.formats[key.String()]
+	if !ok {
+		// "MMMM" is the wide "MMM", e.g. "yMMMMd" is the "yMMMd" pattern with the wide month
+		pattern, ok = data.formats[strings.Replace(key.String(), "MMMM", "MMM", 1)]
+	}
+
+	if !ok {
+		return "", fmt.Errorf(`no date pattern for "%s"`, key.String())
+	}
+
+	return adjustPattern(pattern, fields), nil
+}
+
+// skeletonTime returns the time pattern of the time fields, empty if there are no time fields.
+func skeletonTime(fields map[byte]skeletonField, data *dateTimeData) (string, error) {
+	var key strings.Builder
+
+	hour, hasHour := fields['H']
+	if hasHour {
+		if hour.letter == 'h' || hour.letter == 'K' {
+			key.WriteByte('h')
+		} else {
+			key.WriteByte('H')
+		}
+	}
+
+	for _, letter := range []byte("ms") {
+		if _, ok := fields[letter]; ok {
+			key.WriteByte(letter)
+		}
+	}
+
+	zone, hasZone := fields['z']
+
+	if key.Len() == 0 {
+		if hasZone {
+			return "", fmt.Errorf(`want time fields with "%s"`, zone)
+		}
+
+		return "", nil
+	}
+
+	pattern, ok := data.formats[key.String()]
+	if !ok {
+		return "", fmt.Errorf(`no time pattern for "%s"`, key.String())
+	}
+
+	pattern = adjustPattern(pattern, fields)
+
+	if hasZone {
+		pattern += " " + zone.String()
+	}
+
+	return pattern, nil
+}
+
+// adjustPattern returns the pattern with the field widths and the hour letter of the skeleton fields,
+// e.g. "dd.MM.y" for "d.M.y" and "yMMdd". The numeric fields are not narrowed.
+func adjustPattern(pattern string, fields map[byte]skeletonField) string {
+	var sb strings.Builder
+
+	for _, f := range parsePatternFields(pattern) {
+		letter := f.letter
+
+		switch letter {
+		case 'L':
+			letter = 'M'
+		case 'c':
+			letter = 'E'
+		case 'h', 'K', 'k':
+			letter = 'H'
+		}
+
+		want, ok := fields[letter]
+
+		switch {
+		case f.letter == 0 || !ok:
+		case letter == 'H':
+			f.letter, f.width = want.letter, max(f.width, want.width)
+		case letter == 'M' && want.width >= 3 && f.width >= 3, //nolint:mnd
+			letter == 'E' && want.width > 3: //nolint:mnd
+			f.width = want.width
+		case letter == 'M' && want.width >= 3: //nolint:mnd
+			f.letter, f.width = want.letter, want.width
+		case letter == 'y' && want.width == 2, //nolint:mnd
+			letter == 'M' || letter == 'd' || letter == 'm' || letter == 's':
+			f.width = max(f.width, want.width)
+		}
+
+		if f.letter == 0 {
+			sb.WriteString(quotePattern(f.literal))
+			continue
+		}
+
+		sb.WriteString(f.String())
+	}
+
+	return sb.String()
+}
+
+// patternField is the field or the literal text of the pattern.
+type patternField struct {
+	literal string
+	skeletonField
+}
+
+// parsePatternFields returns the fields and the literals of the LDML date-time pattern,
+// the quoted text is the literal, e.g. "'at' h" is the literal "at " and the field "h".
+func parsePatternFields(pattern string) []patternField {
+	var (
+		fields  []patternField
+		literal strings.Builder
+	)
+
+	flush := func() {
+		if literal.Len() > 0 {
+			fields = append(fields, patternField{literal: literal.String()})
+			literal.Reset()
+		}
+	}
+
+	for i := 0; i < len(pattern); {
+		c := pattern[i]
+
+		switch {
+		case c == '\'':
+			end := strings.IndexByte(pattern[i+1:], '\'')
+
+			switch {
+			case end == 0: // "''" is the quote
+				literal.WriteByte('\'')
+				i += 2
+			case end < 0: // unterminated, the rest is the literal
+				literal.WriteString(pattern[i+1:])
+				i = len(pattern)
+			default:
+				literal.WriteString(strings.ReplaceAll(pattern[i+1:i+1+end], "''", "'"))
+				i += end + 2 //nolint:mnd
+			}
+		case 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z':
+			flush()
+
+			width := 1
+			for i+width < len(pattern) && pattern[i+width] == c {
+				width++
+			}
+
+			fields = append(fields, patternField{skeletonField: skeletonField{letter: c, width: width}})
+			i += width
+		default:
+			_, size := utf8.DecodeRuneInString(pattern[i:])
+			literal.WriteString(pattern[i : i+size])
+			i += size
+		}
+	}
+
+	flush()
+
+	return fields
+}
+
+// quotePattern returns the literal of the pattern, the letters are quoted, e.g. "'г'." for "г.".
+func quotePattern(literal string) string {
+	if !strings.ContainsFunc(literal, func(r rune) bool { return 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || r == '\'' }) {
+		return literal
+	}
+
+	return "'" + strings.ReplaceAll(literal, "'", "''") + "'"
+}
+
+// formatPattern formats the time with the LDML date-time pattern of the locale, see [skeletonPattern].
+func formatPattern(t time.Time, pattern string, locale language.Tag) string {
+	data := localeDateTimeData(locale)
+
+	var sb strings.Builder
+
+	pad := func(n, width int) {
+		s := strconv.Itoa(n)
+		sb.WriteString(strings.Repeat("0", max(width-len(s), 0)) + s)
+	}
+
+	name := func(names []string, i, width int) {
+		switch {
+		case width == 4: //nolint:mnd
+			sb.WriteString(names[i])
+		case width >= 5: //nolint:mnd
+			r, _ := utf8.DecodeRuneInString(names[i])
+			sb.WriteString(strings.ToUpper(string(r)))
+		}
+	}
+
+	for _, f := range parsePatternFields(pattern) {
+		switch f.letter {
+		default: // unsupported fields are literals
+			sb.WriteString(f.String())
+		case 0:
+			sb.WriteString(f.literal)
+		case 'y':
+			if f.width == 2 { //nolint:mnd
+				pad(t.Year()%100, 2) //nolint:mnd
+			} else {
+				pad(t.Year(), f.width)
+			}
+		case 'M', 'L':
+			months, short := data.months, data.shortMonths
+			if f.letter == 'L' && data.standaloneMonths[0] != "" {
+				months, short = data.standaloneMonths, data.standaloneShortMonths
+			}
+
+			switch i := int(t.Month()) - 1; {
+			case f.width <= 2: //nolint:mnd
+				pad(i+1, f.width)
+			case f.width == 3: //nolint:mnd
+				sb.WriteString(short[i])
+			default:
+				name(months[:], i, f.width)
+			}
+		case 'd':
+			pad(t.Day(), f.width)
+		case 'E', 'c':
+			if f.width <= 3 { //nolint:mnd
+				sb.WriteString(data.shortWeekdays[t.Weekday()])
+			} else {
+				name(data.weekdays[:], int(t.Weekday()), f.width)
+			}
+		case 'a':
+			sb.WriteString(data.dayPeriods[t.Hour()/12]) //nolint:mnd
+		case 'h':
+			pad((t.Hour()+11)%12+1, f.width) //nolint:mnd
+		case 'K':
+			pad(t.Hour()%12, f.width) //nolint:mnd
+		case 'H':
+			pad(t.Hour(), f.width)
+		case 'k':
+			pad((t.Hour()+23)%24+1, f.width) //nolint:mnd
+		case 'm':
+			pad(t.Minute(), f.width)
+		case 's':
+			pad(t.Second(), f.width)
+		case 'z':
+			style := "short"
+			if f.width == 4 { //nolint:mnd
+				style = "long"
+			}
+
+			sb.WriteString(timeZoneName(t, style, locale))
+		case 'O':
+			style := "shortOffset"
+			if f.width == 4 { //nolint:mnd
+				style = "longOffset"
+			}
+
+			sb.WriteString(timeZoneName(t, style, locale))
+		case 'v':
+			style := "shortGeneric"
+			if f.width == 4 { //nolint:mnd
+				style = "longGeneric"
+			}
+
+			sb.WriteString(timeZoneName(t, style, locale))
+		}
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,109 @@
+package template
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"golang.org/x/text/language"
+
+	"go.expect.digital/mf2"
+)
+
+func Test_SkeletonPattern(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		locale    language.Tag
+		skeleton  string
+		hourCycle string
+		want      string
+		wantErr   bool
+	}{
+		{locale: language.English, skeleton: "yMMMd", want: "MMM d, y"},
+		{locale: language.English, skeleton: "yMMMd Hm", want: "MMM d, y, HH:mm"},
+		{locale: language.English, skeleton: "yMMMMd", want: "MMMM d, y"},
+		{locale: language.English, skeleton: "yMMdd", want: "MM/dd/y"},
+		{locale: language.English, skeleton: "yMMMEEEEd", want: "EEEE, MMM d, y"},
+		{locale: language.English, skeleton: "jm", hourCycle: "h12", want: "h:mm a"},
+		{locale: language.English, skeleton: "jm", hourCycle: "h23", want: "HH:mm"},
+		{locale: language.English, skeleton: "jm", hourCycle: "h11", want: "K:mm a"},
+		{locale: language.English, skeleton: "Hmz", want: "HH:mm z"},
+		{locale: language.German, skeleton: "yMMMMd", want: "d. MMMM y"},
+		{locale: language.German, skeleton: "H", want: "HH' Uhr'"},
+		{locale: language.Latvian, skeleton: "yMMMd", want: "y'. gada 'd. MMM"},
+		{locale: language.Russian, skeleton: "yMMMM", want: "LLLL y г."},
+		{locale: language.English, skeleton: "Gy", wantErr: true},
+		{locale: language.English, skeleton: "yy-MM", wantErr: true},
+		{locale: language.English, skeleton: "dd", want: "dd"},
+		{locale: language.English, skeleton: "yMd yMd", wantErr: true},
+		{locale: language.English, skeleton: "yd", wantErr: true},
+		{locale: language.English, skeleton: "z", wantErr: true},
+		{locale: language.English, skeleton: "", wantErr: true},
+	} {
+		got, err := skeletonPattern(test.skeleton, test.hourCycle, test.locale)
+		if test.wantErr != (err != nil) {
+			t.Errorf(`%s "%s": want error %t, got '%v'`, test.locale, test.skeleton, test.wantErr, err)
+			continue
+		}
+
+		if test.want != got {
+			t.Errorf(`%s "%s": want '%s', got '%s'`, test.locale, test.skeleton, test.want, got)
+		}
+	}
+}
+
+func Test_DatetimeSkeleton(t *testing.T) {
+	t.Parallel()
+
+	date := time.Date(2024, 6, 15, 14, 5, 9, 0, time.UTC)
+
+	for _, test := range []struct {
+		wantErr error
+		locale  language.Tag
+		in      string
+		want    string
+	}{
+		{locale: language.AmericanEnglish, in: "{ $d :datetime skeleton=|yMMMd jm| }", want: "Jun 15, 2024, 2:05 PM"},
+		{locale: language.AmericanEnglish, in: "{ $d :datetime skeleton=|yMMMd Hm| }", want: "Jun 15, 2024, 14:05"},
+		{locale: language.AmericanEnglish, in: "{ $d :datetime skeleton=|MMMEd| }", want: "Sat, Jun 15"},
+		{locale: language.AmericanEnglish, in: "{ $d :datetime skeleton=|jms| hourCycle=h23 }", want: "14:05:09"},
+		{locale: language.AmericanEnglish, in: "{ $d :datetime skeleton=|jmz| timeZone=EET }", want: "5:05 PM EEST"},
+		{locale: language.German, in: "{ $d :datetime skeleton=|yMMMMEEEEd| }", want: "Samstag, 15. Juni 2024"},
+		{locale: language.MustParse("de-DE"), in: "{ $d :datetime skeleton=|yMd jm| }", want: "15.6.2024, 14:05"},
+		{locale: language.Latvian, in: "{ $d :datetime skeleton=|yMMMd| }", want: "2024. gada 15. jūn."},
+		{locale: language.Russian, in: "{ $d :datetime skeleton=|yMMMMd| }", want: "15 июня 2024 г."},
+		{locale: language.Russian, in: "{ $d :datetime skeleton=|yMMMM| }", want: "июнь 2024 г."},
+		{locale: language.Spanish, in: "{ $d :datetime skeleton=|MMMMd| }", want: "15 de junio"},
+		{
+			locale:  language.AmericanEnglish,
+			in:      "{ $d :datetime skeleton=|Gy| }",
+			want:    "{$d}",
+			wantErr: mf2.ErrBadOption,
+		},
+		{
+			locale:  language.AmericanEnglish,
+			in:      "{ $d :datetime skeleton=|yMd| dateStyle=short }",
+			want:    "{$d}",
+			wantErr: mf2.ErrBadOption,
+		},
+	} {
+		t.Run(test.in, func(t *testing.T) {
+			t.Parallel()
+
+			template, err := New(WithLocale(test.locale)).Parse(test.in)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := template.Sprint(map[string]any{"d": date})
+			if !errors.Is(err, test.wantErr) {
+				t.Errorf("want error '%v', got '%v'", test.wantErr, err)
+			}
+
+			if test.want != got {
+				t.Errorf("want '%s', got '%s'", test.want, got)
+			}
+		})
+	}
+}
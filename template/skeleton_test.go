@@ -0,0 +1,73 @@
+package template
+
+import (
+	"errors"
+	"testing"
+
+	"golang.org/x/text/language"
+
+	"go.expect.digital/mf2"
+)
+
+func Test_NumberSkeleton(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		input   map[string]any
+		wantErr error
+		name    string
+		in      string
+		want    string
+	}{
+		{name: "empty", in: "{ 12345 :number skeleton=|| }", want: "12,345"},
+		{name: "fraction", in: "{ 1234.5 :number skeleton=|.00 group-off| }", want: "1234.50"},
+		{name: "optional fraction", in: "{ 1.5 :number skeleton=|.0##| }", want: "1.5"},
+		{name: "unlimited fraction", in: "{ 1.23456 :number skeleton=|.00*| }", want: "1.23456"},
+		{name: "unlimited fraction minimum", in: "{ 0.1 :number skeleton=|.00*| }", want: "0.10"},
+		{name: "precision integer", in: "{ 1234.4 :number skeleton=|precision-integer| }", want: "1,234"},
+		{name: "significant", in: "{ 1234.5 :number skeleton=|@@#| }", want: "1,230"},
+		{name: "option wins", in: "{ 1234.5 :number skeleton=|.00 group-off| useGrouping=always }", want: "1,234.50"},
+		{name: "percent", in: "{ 0.25 :number skeleton=|percent scale/100| }", want: "25%"},
+		{name: "percent concise", in: "{ 0.25 :number skeleton=|%x100 +!| }", want: "+25%"},
+		{name: "integer width", in: "{ 5 :integer skeleton=|integer-width/*000| }", want: "005"},
+		{name: "integer width optional", in: "{ 5 :integer skeleton=|integer-width/##00| }", want: "05"},
+		{name: "integer width concise", in: "{ 5 :integer skeleton=|0000| }", want: "0005"},
+		{name: "variable", in: "{ $n :number skeleton=$s }", input: map[string]any{"n": 2, "s": "sign-always"}, want: "+2"},
+		{name: "compact", in: "{ 12345 :number skeleton=|compact-short| }", want: "{|12345|}", wantErr: mf2.ErrBadOption},
+		{name: "scientific", in: "{ 12345 :number skeleton=|scientific| }", want: "{|12345|}", wantErr: mf2.ErrBadOption},
+		{
+			name:    "currency",
+			in:      "{ 12345 :number skeleton=|compact-short currency/EUR| }",
+			want:    "{|12345|}",
+			wantErr: mf2.ErrBadOption,
+		},
+		{
+			name:    "unsupported",
+			in:      "{ 1 :number skeleton=|measure-unit/length-meter| }",
+			want:    "{|1|}",
+			wantErr: mf2.ErrBadOption,
+		},
+		{name: "percent without scale", in: "{ 1 :number skeleton=|percent| }", want: "{|1|}", wantErr: mf2.ErrBadOption},
+		{name: "scale without percent", in: "{ 1 :number skeleton=|scale/100| }", want: "{|1|}", wantErr: mf2.ErrBadOption},
+		{name: "invalid fraction", in: "{ 1 :number skeleton=|.0#0| }", want: "{|1|}", wantErr: mf2.ErrBadOption},
+		{name: "invalid integer width", in: "{ 1 :number skeleton=|integer-width/#| }", want: "{|1|}", wantErr: mf2.ErrBadOption},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			template, err := New(WithLocale(language.English)).Parse(test.in)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := template.Sprint(test.input)
+			if !errors.Is(err, test.wantErr) {
+				t.Errorf("want error '%v', got '%v'", test.wantErr, err)
+			}
+
+			if test.want != got {
+				t.Errorf("want '%s', got '%s'", test.want, got)
+			}
+		})
+	}
+}
@@ -1,16 +1,21 @@
 package template
 
 import (
+	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io"
-	"sort"
-	"strings"
+	"log/slog"
+	"strconv"
+	"sync"
+	"time"
 
-	"golang.org/x/exp/slices"
 	"golang.org/x/text/language"
+	"golang.org/x/text/unicode/norm"
 
 	"go.expect.digital/mf2"
+	"go.expect.digital/mf2/internal/mapkeys"
 	ast "go.expect.digital/mf2/parse"
 )
 
@@ -25,16 +30,65 @@ type Template struct {
 	//  - "lv-LV" -> 2.1.2023
 	ast      *ast.AST
 	registry Registry
-	locale   language.Tag
+	// funcs is the immutable snapshot of the registry when the message is compiled, the executions use it.
+	funcs Registry
+	// sharedRegistry is set when the registry is the snapshot, the registry is copied on write.
+	sharedRegistry bool
+	// plan is the precomputed selection plan of the matcher, nil if the message has no matcher.
+	plan *selectionPlan
+	// expressions are the function options with the literal values resolved on parse, see [newExpressionPlan].
+	expressions *expressionPlan
+	parseCache  *ast.Cache
+	// defaults are the values of the missing variables, see [WithDefaults].
+	defaults map[string]any
+	// logger logs the fallback output, see [WithLogger].
+	logger       *slog.Logger
+	parseOptions []ast.ParseOption
+	// id is the message ID in the log records, see [WithID].
+	id     string
+	locale language.Tag
+	// sourceLocale is the locale the message is written in, see [WithSourceLocale].
+	sourceLocale language.Tag
+	// hasLocale is set by [WithLocale], otherwise the message is formatted in the source locale.
+	hasLocale bool
+	// rawDurations disables the localized formatting of durations, see [WithoutDurationFormatting].
+	rawDurations bool
+	// datetimeFormatter is the formatting backend of :datetime, see [WithDateTimeFormatter].
+	datetimeFormatter DateTimeFormatter
+	// datetimeStyles are the default styles of :datetime, see [WithDateTimeDefaults].
+	datetimeStyles datetimeStyles
+	// funcBudgets are the time budgets of the functions by name, see [WithFuncBudget].
+	funcBudgets map[string]time.Duration
+	// funcBudget is the time budget of the other functions, see [WithFuncBudget].
+	funcBudget time.Duration
+	// metrics receives the events of the template, see [WithMetrics].
+	metrics Metrics
+	// onError is called on the fallback output, see [WithErrorHandler].
+	onError func(ctx context.Context, event FallbackEvent)
+	// allowedFuncs are the functions the message may call, nil allows all, see [WithAllowedFuncs].
+	allowedFuncs map[string]struct{}
+	// possibleKeys are the keys of the custom select functions, see [WithPossibleKeys].
+	possibleKeys PossibleKeys
+	// funcOptions are the options of the custom functions, see [WithFuncOptions].
+	funcOptions map[string][]string
+	// dottedPaths resolves the variables like "$user.name" in the input, see [WithDottedPaths].
+	dottedPaths bool
 }
 
 // ResolvedValue keeps the result of the Expression resolution with optionally
-// defined format() and selectKey() functions for Format and Select contexts.
+// defined format() and selectKey() or match() functions for Format and Select contexts.
 type ResolvedValue struct {
 	value     any
 	selectKey func(keys []string) string
-	format    func() string
-	err       error
+	// match returns the matching keys in order of preference, see [WithMatch].
+	match  func(keys []string) []string
+	format func() string
+	err    error
+	// options are the effective options of the function, see [ResolvedValue.Options].
+	options Options
+	// literal are the compiled options of the expression the value is the literal option of,
+	// see [cachedOptions].
+	literal *compiledOptions
 }
 
 func defaultFormat(value any) string {
@@ -53,17 +107,35 @@ func defaultFormat(value any) string {
 }
 
 func defaultSelectKey(value any, keys []string) string {
-	v := defaultFormat(value)
-
-	for _, k := range keys {
-		if v == k {
-			return k
-		}
+	if matches := defaultMatch(value, keys); len(matches) > 0 {
+		return matches[0]
 	}
 
 	return ast.CatchAllKey{}.String()
 }
 
+// defaultMatch returns the keys matching the value of the custom function, see [WithMatch]
+// for the supported values.
+func defaultMatch(value any, keys []string) []string {
+	switch v := value.(type) {
+	case string:
+		return filterKeys([]string{v}, keys)
+	case []string:
+		return filterKeys(v, keys)
+	case bool:
+		return filterKeys([]string{strconv.FormatBool(v)}, keys)
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		f, err := castAs[float64](v)
+		if err != nil {
+			return nil
+		}
+
+		return numericKeys(f, keys)
+	default:
+		return filterKeys([]string{defaultFormat(value)}, keys)
+	}
+}
+
 // String makes the ResolvedValue implement the fmt.Stringer interface.
 func (r *ResolvedValue) String() string {
 	if r.format != nil {
@@ -83,11 +155,66 @@ func WithFormat(format func() string) ResolvedValueOpt {
 	}
 }
 
-// WithSelectKey applies a custom format() function to the ResolvedValue.
+// WithSelectKey applies a custom selectKey() function to the ResolvedValue.
+// It replaces the function set by [WithMatch].
 func WithSelectKey(selectKey func(keys []string) string) ResolvedValueOpt {
 	return func(r *ResolvedValue) {
 		r.selectKey = selectKey
+		r.match = nil
+	}
+}
+
+// WithMatch applies a custom match() function to the ResolvedValue. The function returns
+// the keys matching the selector in order of preference, the most preferred first, e.g.
+// the exact value and the plural category of a number: []string{"1", "one"}.
+//
+// The variant keys are passed to the function without the catch-all key "*". The returned keys
+// are normalized, see [NormalizeKey], the keys not found in the variant keys and the duplicates
+// are ignored. Nil matches only the catch-all key. It replaces the function set by [WithSelectKey].
+//
+// Without the custom function, the value of the custom function output is matched by its type:
+//   - string - the equal key;
+//   - []string - the equal keys in order of the slice;
+//   - bool - the key "true" or "false";
+//   - integer and float - the numerically equal keys, e.g. "1" and "1.0" for 1;
+//   - other - the key equal to the formatted value.
+func WithMatch(match func(keys []string) []string) ResolvedValueOpt {
+	return func(r *ResolvedValue) {
+		r.match = match
+		r.selectKey = func(keys []string) string {
+			if matches := filterKeys(match(keys), keys); len(matches) > 0 {
+				return matches[0]
+			}
+
+			return ast.CatchAllKey{}.String()
+		}
+	}
+}
+
+// matches returns the keys matching the value in order of preference.
+func (r *ResolvedValue) matches(keys []string) []string {
+	if r.match != nil {
+		return filterKeys(r.match(keys), keys)
+	}
+
+	if r.selectKey != nil {
+		return filterKeys([]string{r.selectKey(keys)}, keys)
 	}
+
+	return defaultMatch(r.value, keys)
+}
+
+// WithOptions sets the effective options of the function that resolved the value, including the defaults.
+func WithOptions(options Options) ResolvedValueOpt {
+	return func(r *ResolvedValue) {
+		r.options = options
+	}
+}
+
+// Options returns the effective options of the function that resolved the value, e.g. the default
+// maximumFractionDigits of :number, for debugging. It is nil if the function does not report them.
+func (r *ResolvedValue) Options() Options {
+	return r.options
 }
 
 // NewResolvedValue creates a new variable of type *ResolvedValue.
@@ -99,6 +226,7 @@ func NewResolvedValue(value any, options ...ResolvedValueOpt) *ResolvedValue {
 			value:     value,
 			format:    func() string { return defaultFormat(value) },
 			selectKey: func(keys []string) string { return defaultSelectKey(value, keys) },
+			match:     func(keys []string) []string { return defaultMatch(value, keys) },
 		}
 	}
 
@@ -112,14 +240,20 @@ func NewResolvedValue(value any, options ...ResolvedValueOpt) *ResolvedValue {
 // New returns a new Template.
 func New(options ...Option) *Template {
 	t := &Template{
-		registry: NewRegistry(),
-		locale:   language.AmericanEnglish,
+		registry:          NewRegistry(),
+		locale:            language.AmericanEnglish,
+		datetimeFormatter: XText{},
+		datetimeStyles:    defaultDatetimeStyles,
 	}
 
 	for _, o := range options {
 		o(t)
 	}
 
+	if !t.hasLocale && t.sourceLocale != language.Und {
+		t.locale = t.sourceLocale
+	}
+
 	return t
 }
 
@@ -129,15 +263,17 @@ type Option func(t *Template)
 // WithFunc adds a single function to function registry.
 func WithFunc(name string, f Func) Option {
 	return func(t *Template) {
-		t.registry[name] = f
+		t.setFunc(name, f)
 	}
 }
 
-// WithFuncs adds functions to function registry.
+// WithFuncs adds functions to function registry. The functions are copied, the later modifications
+// of reg do not change the template. The compiled message keeps the registry it was compiled with,
+// the option applied after [Template.Parse] takes effect on the next parse.
 func WithFuncs(reg Registry) Option {
 	return func(t *Template) {
 		for k, f := range reg {
-			t.registry[k] = f
+			t.setFunc(k, f)
 		}
 	}
 }
@@ -146,69 +282,310 @@ func WithFuncs(reg Registry) Option {
 func WithLocale(locale language.Tag) Option {
 	return func(t *Template) {
 		t.locale = locale
+		t.hasLocale = true
+	}
+}
+
+// WithSourceLocale declares the locale the message is written in, e.g. the untranslated message
+// of the source language. Without [WithLocale] the message is formatted in the source locale.
+func WithSourceLocale(locale language.Tag) Option {
+	return func(t *Template) {
+		t.sourceLocale = locale
+	}
+}
+
+// Locale returns the locale the template formats the message in, see [WithLocale].
+func (t *Template) Locale() language.Tag {
+	return t.locale
+}
+
+// SourceLocale returns the locale the message is written in, [language.Und] if not declared,
+// see [WithSourceLocale].
+func (t *Template) SourceLocale() language.Tag {
+	return t.sourceLocale
+}
+
+// WithDefaults sets the default values of the variables missing in the input,
+// e.g. the optional arguments are formatted with the default instead of the fallback "{$var}".
+func WithDefaults(defaults map[string]any) Option {
+	return func(t *Template) {
+		t.defaults = make(map[string]any, len(defaults))
+
+		// variable names are compared NFC normalized, the parsed names are normalized,
+		// the keys equal when normalized are set in the sorted order, see eachInput
+		for _, k := range mapkeys.Sorted(defaults) {
+			t.defaults[norm.NFC.String(k)] = defaults[k]
+		}
+	}
+}
+
+// WithParseOptions sets the options of [ast.Parse] used by [Template.Parse],
+// e.g. [ast.WithoutValidation] for trusted messages or [ast.WithSpecVersion] for messages of older grammar.
+func WithParseOptions(options ...ast.ParseOption) Option {
+	return func(t *Template) {
+		t.parseOptions = options
+	}
+}
+
+// WithParseCache parses the messages with the cache, the parse options of the cache
+// are used instead of [WithParseOptions].
+func WithParseCache(cache *ast.Cache) Option {
+	return func(t *Template) {
+		t.parseCache = cache
 	}
 }
 
 // Parse parses the MessageFormat2 string and returns the template.
 func (t *Template) Parse(input string) (*Template, error) {
-	ast, err := ast.Parse(input)
+	parse := func() (ast.AST, error) { return ast.Parse(input, t.parseOptions...) }
+	if t.parseCache != nil {
+		parse = func() (ast.AST, error) { return t.parseCache.Parse(input) }
+	}
+
+	start := time.Now()
+
+	tree, err := parse()
+
+	t.parsed(start, err)
+
 	if err != nil {
 		return nil, err //nolint:wrapcheck
 	}
 
-	t.ast = &ast
+	t.compile(tree)
 
 	return t, nil
 }
 
-// Execute writes the result of the template to the given writer.
-func (t *Template) Execute(w io.Writer, input map[string]any) error {
+// ParseBinary decodes the message encoded by [Template.MarshalBinary] or [ast.AST.MarshalBinary],
+// e.g. the messages compiled at build time. The decoded message is not parsed again, but it is
+// validated as by [Template.Parse], the invalid data is rejected instead of failing on execution.
+func (t *Template) ParseBinary(data []byte) (*Template, error) {
+	var tree ast.AST
+
+	if err := tree.UnmarshalBinary(data); err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	t.compile(tree)
+
+	return t, nil
+}
+
+// MarshalBinary encodes the parsed message, see [Template.ParseBinary].
+// It implements [encoding.BinaryMarshaler].
+func (t *Template) MarshalBinary() ([]byte, error) {
 	if t.ast == nil {
-		return errors.New("execute template: AST is nil")
+		return nil, errors.New("marshal template: message not parsed")
 	}
 
-	executer := &executer{template: t, w: w, variables: make(map[string]*ResolvedValue, len(input))}
+	return t.ast.MarshalBinary() //nolint:wrapcheck
+}
 
-	for k, v := range input {
-		var f Func
+// compile sets the parsed message and precomputes the selection plan and function options.
+func (t *Template) compile(tree ast.AST) {
+	// the executions use the snapshot, the options applied later copy the registry, see [Template.setFunc]
+	t.funcs = t.registry
+	t.sharedRegistry = true
 
-		switch v.(type) {
-		default:
-			executer.variables[k] = NewResolvedValue(v, WithFormat(func() string { return defaultFormat(v) }))
-			continue
-		case string:
-			f = stringFunc
-		case float64, int:
-			f = numberFunc
-		}
+	t.ast = &tree
+	t.plan = nil
+	t.expressions = newExpressionPlan(tree)
 
-		r, err := f(NewResolvedValue(v), nil, t.locale)
-		if err != nil {
-			return fmt.Errorf("execute template: %w", err)
+	if m, ok := tree.Message.(ast.ComplexMessage); ok {
+		if matcher, ok := m.ComplexBody.(ast.Matcher); ok {
+			t.plan = newSelectionPlan(matcher)
 		}
+	}
+}
+
+// Execute writes the result of the template to the given writer.
+//
+// The input variables are either map[string]any, a map with string keys, or a struct (or a pointer to a struct).
+// The variables of the struct are the exported fields, the name of the variable is the field name or
+// the name in the `mf2:"name"` tag. The fields tagged `mf2:"-"` are skipped.
+//
+// The result is written to w at once, after the template is executed.
+func (t *Template) Execute(w io.Writer, input any, options ...ExecuteOption) error {
+	return t.ExecuteContext(context.Background(), w, input, options...)
+}
+
+// ExecuteContext is like [Template.Execute], the [Values] in ctx are the defaults of the function options.
+func (t *Template) ExecuteContext(ctx context.Context, w io.Writer, input any, options ...ExecuteOption) error {
+	buf := getBuffer()
+	defer putBuffer(buf)
+
+	err := t.execute(ctx, buf, input, options)
+
+	// the result is written also on resolution errors, failed expressions are in fallback representation
+	if _, writeErr := w.Write(buf.Bytes()); writeErr != nil {
+		return errors.Join(err, fmt.Errorf("execute template: %w", writeErr))
+	}
+
+	return err
+}
+
+// Sprint wraps Execute and returns the result as a string.
+func (t *Template) Sprint(input any, options ...ExecuteOption) (string, error) {
+	return t.SprintContext(context.Background(), input, options...)
+}
+
+// SprintContext wraps ExecuteContext and returns the result as a string.
+func (t *Template) SprintContext(ctx context.Context, input any, options ...ExecuteOption) (string, error) {
+	buf := getBuffer()
+	defer putBuffer(buf)
+
+	err := t.execute(ctx, buf, input, options)
+
+	return buf.String(), err
+}
+
+func (t *Template) execute(ctx context.Context, buf *bytes.Buffer, input any, options []ExecuteOption) (err error) {
+	defer t.executed(time.Now(), &err)
 
-		executer.variables[k] = r
+	executer, err := t.newExecuter(ctx, buf, input, options)
+	if err != nil {
+		return fmt.Errorf("execute template: %w", err)
 	}
 
-	if err := executer.execute(); err != nil {
+	defer executer.release()
+
+	if err := executer.result(executer.execute()); err != nil {
+		if executer.errorPolicy == StrictErrors || errors.Is(err, ErrMaxOutput) {
+			buf.Reset()
+		}
+
 		return fmt.Errorf("execute template: %w", err)
 	}
 
 	return nil
 }
 
-// Sprint wraps Execute and returns the result as a string.
-func (t *Template) Sprint(input map[string]any) (string, error) {
-	sb := new(strings.Builder)
-	err := t.Execute(sb, input)
+// maxPooledBufferSize is the capacity limit of the buffers returned to the pool,
+// the rare large results must not keep the memory.
+const maxPooledBufferSize = 64 << 10
+
+var (
+	executerPool = sync.Pool{
+		New: func() any { return &executer{variables: make(map[string]*ResolvedValue)} },
+	}
+	bufferPool = sync.Pool{
+		New: func() any { return new(bytes.Buffer) },
+	}
+)
+
+func getBuffer() *bytes.Buffer {
+	return bufferPool.Get().(*bytes.Buffer) //nolint:forcetypeassert // always *bytes.Buffer
+}
+
+func putBuffer(buf *bytes.Buffer) {
+	if buf.Cap() > maxPooledBufferSize {
+		return
+	}
+
+	buf.Reset()
+	bufferPool.Put(buf)
+}
 
-	return sb.String(), err
+// newExecuter returns the pooled executer with the input variables resolved.
+// The executer must be released after the execution.
+func (t *Template) newExecuter(
+	ctx context.Context,
+	w io.Writer,
+	input any,
+	options []ExecuteOption,
+) (*executer, error) {
+	if t.ast == nil {
+		return nil, errors.New("AST is nil")
+	}
+
+	executer := executerPool.Get().(*executer) //nolint:forcetypeassert // always *executer
+	executer.template = t
+	executer.w = w
+	executer.ctx = ctx
+	executer.values = ValuesFromContext(ctx)
+	executer.apply(options)
+
+	err := eachInput(input, func(k string, v any) error {
+		r, err := executer.newInput(v)
+		if err != nil {
+			return err
+		}
+
+		// variable names are compared NFC normalized, the parsed names are normalized
+		executer.variables[norm.NFC.String(k)] = r
+
+		return nil
+	})
+	if err != nil {
+		executer.release()
+		return nil, err
+	}
+
+	return executer, nil
+}
+
+// newInput returns the resolved value of the input variable, strings and numbers
+// are resolved with the :string and :number functions.
+func (e *executer) newInput(v any) (*ResolvedValue, error) {
+	var f Func
+
+	switch d := v.(type) {
+	default:
+		return NewResolvedValue(v, WithFormat(func() string { return defaultFormat(v) })), nil
+	case time.Duration:
+		format := func() string { return formatDuration(d, e.locale) }
+		if e.template.rawDurations {
+			format = func() string { return defaultFormat(v) }
+		}
+
+		return NewResolvedValue(v, WithFormat(format)), nil
+	case string:
+		f = stringFunc
+	case float64, int:
+		f = numberFunc
+	}
+
+	return f(NewResolvedValue(v), nil, e.locale)
+}
+
+// release returns the executer to the pool.
+func (e *executer) release() {
+	clear(e.variables)
+	clear(e.unresolved)
+
+	e.template = nil
+	e.w = nil
+	e.parts = nil
+	e.values = nil
+	e.ctx = nil
+	e.registry = nil
+	e.markup = nil
+	e.resolver = nil
+
+	executerPool.Put(e)
 }
 
 type executer struct {
+	ctx       context.Context //nolint:containedctx // the executer lives for one execution
 	template  *Template
 	w         io.Writer
 	variables map[string]*ResolvedValue
+	// registry is the template registry with the functions of [WithExtraFuncs].
+	registry Registry
+	// markup renders the markup when formatting to a string, see [WithMarkupRenderer].
+	markup MarkupRenderer
+	// resolver resolves the variables missing in the input, see [WithVariableResolver].
+	resolver VariableResolver
+	// unresolved are the variables the resolver did not resolve, the resolver is not called again.
+	unresolved map[string]bool
+	// values are the request-scoped defaults of the function options, see [WithValues].
+	values Values
+	// parts collects the formatted parts instead of writing to w, see [Template.FormatToParts].
+	parts *[]Part
+	// locale is the template locale, or the locale of [InLocale].
+	locale      language.Tag
+	errorPolicy ErrorPolicy
 }
 
 func (e *executer) execute() error {
@@ -218,7 +595,7 @@ func (e *executer) execute() error {
 	case nil:
 		return nil
 	case ast.SimpleMessage:
-		return e.resolvePattern(message)
+		return e.resolvePattern(message, e.template.expressions.pattern)
 	case ast.ComplexMessage:
 		return e.resolveComplexMessage(message)
 	}
@@ -243,7 +620,7 @@ func (e *executer) resolveComplexMessage(message ast.ComplexMessage) error {
 	case ast.Matcher:
 		err = e.resolveMatcher(b)
 	case ast.QuotedPattern:
-		err = e.resolvePattern(b)
+		err = e.resolvePattern(b, e.template.expressions.pattern)
 	}
 
 	if err != nil {
@@ -254,62 +631,96 @@ func (e *executer) resolveComplexMessage(message ast.ComplexMessage) error {
 }
 
 func (e *executer) resolveDeclarations(declarations []ast.Declaration) error {
-	for _, decl := range declarations {
+	for i, decl := range declarations {
+		compiled := optionsAt(e.template.expressions.declarations, i)
+
 		switch d := decl.(type) {
 		case ast.ReservedStatement:
 			return fmt.Errorf("%w", mf2.ErrUnsupportedStatement)
 		case ast.LocalDeclaration:
-			r, err := e.resolveExpression(d.Expression)
+			r, err := e.resolveExpression(d.Expression, compiled)
 			if err != nil {
 				r.err = errors.Join(r.err, fmt.Errorf("resolve local %s: %w", d.Variable, err))
 			}
 
 			e.variables[string(d.Variable)] = r // newVariable(d.Expression, nil)
 		case ast.InputDeclaration:
-			r, err := e.resolveExpression(ast.Expression(d))
+			variable, ok := d.Operand.(ast.Variable)
+			if !ok { // only with the message built without the parser
+				return fmt.Errorf("%w: input %s, want variable", mf2.ErrBadOperand, d.Operand)
+			}
+
+			r, err := e.resolveExpression(ast.Expression(d), compiled)
 			if err != nil {
 				r.err = errors.Join(r.err, fmt.Errorf("resolve input %s: %w", d.Operand, err))
 			}
 
-			e.variables[string(d.Operand.(ast.Variable))] = r //nolint: forcetypeassert // always ast.Variable
+			e.variables[string(variable)] = r
 		}
 	}
 
 	return nil
 }
 
-func (e *executer) resolvePattern(pattern []ast.PatternPart) error {
+// resolvePattern resolves the pattern parts, the compiled options are by the part, see [expressionPlan].
+func (e *executer) resolvePattern(pattern []ast.PatternPart, compiled []*compiledOptions) error {
 	var resolutionErr error
 
 	errorf := func(format string, args ...any) error {
 		return errors.Join(resolutionErr, fmt.Errorf("pattern: "+format, args...))
 	}
 
-	for _, part := range pattern {
+	for i, part := range pattern {
 		switch v := part.(type) {
 		case ast.Text:
-			if _, err := e.w.Write([]byte(v)); err != nil {
+			if e.parts != nil {
+				e.addText(string(v))
+				continue
+			}
+
+			if _, err := io.WriteString(e.w, string(v)); err != nil {
 				return errorf("write text: %w", err)
 			}
 		case ast.Expression:
-			resolved, err := e.resolveExpression(v)
+			resolved, err := e.resolveExpression(v, optionsAt(compiled, i))
 			if err != nil {
 				resolutionErr = errors.Join(resolutionErr, err)
+				e.warn(v, err)
+			}
+
+			if e.parts != nil {
+				*e.parts = append(*e.parts, &ExpressionPart{
+					Source:     expressionSource(v),
+					Value:      resolved.String(),
+					Attributes: attributes(v.Attributes),
+				})
+				continue
 			}
 
-			if _, err := e.w.Write([]byte(resolved.String())); err != nil {
+			if _, err := io.WriteString(e.w, resolved.String()); err != nil {
 				return errorf("write resolved expression: %w", err)
 			}
 		// When formatting to a string, markup placeholders format to an empty string by default.
 		// See ".message-format-wg/exploration/open-close-placeholders.md#formatting-to-a-string"
 		case ast.Markup:
+			if e.parts == nil {
+				if err := e.renderMarkup(v); err != nil {
+					resolutionErr = errors.Join(resolutionErr, fmt.Errorf("pattern: %w", err))
+				}
+
+				continue
+			}
+
+			if err := e.addMarkup(v); err != nil {
+				resolutionErr = errors.Join(resolutionErr, fmt.Errorf("pattern: %w", err))
+			}
 		}
 	}
 
 	return resolutionErr
 }
 
-func (e *executer) resolveExpression(expr ast.Expression) (*ResolvedValue, error) {
+func (e *executer) resolveExpression(expr ast.Expression, compiled *compiledOptions) (*ResolvedValue, error) {
 	var (
 		funcName      string
 		options       Options
@@ -327,7 +738,7 @@ func (e *executer) resolveExpression(expr ast.Expression) (*ResolvedValue, error
 	case ast.Function:
 		funcName = v.Identifier.Name
 
-		if options, err = e.resolveOptions(v.Options); err != nil {
+		if options, err = e.resolveOptions(funcName, v.Options, compiled); err != nil {
 			return NewResolvedValue(""), fmt.Errorf("expression: %w", err)
 		}
 	case ast.PrivateUseAnnotation:
@@ -370,6 +781,15 @@ func (e *executer) resolveExpression(expr ast.Expression) (*ResolvedValue, error
 		default: // TODO(jhorsts): how is unknown type formatted?
 			return fmtErroredExpr(), resolutionErr
 		case *ResolvedValue:
+			if f, ok := t.value.(mf2.Formattable); ok {
+				result, err := e.formatFormattable(f)
+				if err != nil {
+					return fmtErroredExpr(), errors.Join(resolutionErr, fmt.Errorf("expression: %w", err))
+				}
+
+				return result, resolutionErr
+			}
+
 			// the expression has already been resolved before
 			return t, resolutionErr
 		case string:
@@ -379,13 +799,18 @@ func (e *executer) resolveExpression(expr ast.Expression) (*ResolvedValue, error
 		}
 	}
 
-	f, ok := e.template.registry[funcName] // TODO(jhorsts): lookup by namespace and name
+	if function, ok := expr.Annotation.(ast.Function); ok && !e.template.allowedFunc(function.Identifier) {
+		err = fmt.Errorf(`expression: function "%s" is not allowed: %w`, function.Identifier, mf2.ErrUnsupportedExpression)
+		return fmtErroredExpr(), errors.Join(resolutionErr, err)
+	}
+
+	f, ok := e.registry[funcName] // TODO(jhorsts): lookup by namespace and name
 	if !ok {
 		err = fmt.Errorf(`expression: %w "%s"`, mf2.ErrUnknownFunction, funcName)
 		return fmtErroredExpr(), errors.Join(resolutionErr, err)
 	}
 
-	result, err := f(NewResolvedValue(value), options, e.template.locale)
+	result, err := e.call(funcName, f, value, options)
 	if err != nil {
 		return fmtErroredExpr(), errors.Join(resolutionErr, fmt.Errorf("expression: %w", err))
 	}
@@ -393,6 +818,31 @@ func (e *executer) resolveExpression(expr ast.Expression) (*ResolvedValue, error
 	return result, resolutionErr
 }
 
+// call calls the function with the execution locale, or the locale of the "u:locale" option,
+// within the time budget of the function, see [WithFuncBudget].
+//
+// See ".message-format-wg/spec/u-namespace.md#ulocale".
+func (e *executer) call(name string, f Func, operand any, options Options) (*ResolvedValue, error) {
+	locale := e.locale
+
+	if _, ok := options["u:locale"]; ok {
+		tag, err := options.GetTag("u:locale", locale)
+		if err != nil {
+			return nil, err
+		}
+
+		locale = tag
+
+		delete(options, "u:locale") // the options are resolved for every call
+	}
+
+	if budget := e.template.budget(name); budget > 0 {
+		return e.callBudget(f, NewResolvedValue(operand), options, locale, budget)
+	}
+
+	return f(NewResolvedValue(operand), options, locale)
+}
+
 // resolveValue resolves the value of an expression's operand.
 //
 //   - If the operand is a literal, it returns the literal's value.
@@ -411,6 +861,14 @@ func (e *executer) resolveValue(v ast.Value) (any, error) {
 		return float64(v), nil
 	case ast.Variable:
 		val, ok := e.variables[string(v)]
+		if !ok {
+			var err error
+
+			if val, ok, err = e.resolveMissing(string(v)); err != nil {
+				return "{" + v.String() + "}", err
+			}
+		}
+
 		if !ok {
 			return "{" + v.String() + "}", fmt.Errorf(`%w "%s"`, mf2.ErrUnresolvedVariable, v)
 		}
@@ -419,11 +877,30 @@ func (e *executer) resolveValue(v ast.Value) (any, error) {
 	}
 }
 
-func (e *executer) resolveOptions(options []ast.Option) (Options, error) {
-	m := make(Options, len(options))
+// resolveOptions returns the options of the function call, the request-scoped [Values]
+// of the options the function declares included, see [WithFuncOptions]. Only the variable
+// values are resolved if the options are compiled, see [compiledOptions].
+func (e *executer) resolveOptions(funcName string, options []ast.Option, compiled *compiledOptions) (Options, error) {
+	m := make(Options, len(options)+len(e.values))
+	dynamic := options
+
+	if compiled != nil {
+		if compiled.err != nil {
+			return nil, compiled.err
+		}
+
+		for name, value := range compiled.static {
+			// the functions may modify the value, e.g. with [NewResolvedValue]
+			v := *value
+			v.literal = compiled
+			m[name] = &v
+		}
+
+		dynamic = compiled.dynamic
+	}
 
-	for _, opt := range options {
-		name := opt.Identifier.Name
+	for _, opt := range dynamic {
+		name := opt.Identifier.String() // namespaced options keep the namespace, e.g. "u:locale"
 		if _, ok := m[name]; ok {
 			return nil, fmt.Errorf(`%w "%s"`, mf2.ErrDuplicateOptionName, name)
 		}
@@ -436,6 +913,17 @@ func (e *executer) resolveOptions(options []ast.Option) (Options, error) {
 		m[name] = NewResolvedValue(value)
 	}
 
+	if len(e.values) == 0 {
+		return m, nil
+	}
+
+	// the message options win over the values
+	for _, name := range e.template.declaredOptions(funcName) {
+		if value, ok := e.values[name]; ok && m[name] == nil {
+			m[name] = NewResolvedValue(value)
+		}
+	}
+
 	return m, nil
 }
 
@@ -449,12 +937,21 @@ func (e *executer) resolveMatcher(m ast.Matcher) error {
 		return fmt.Errorf("matcher: %w", matcherErr)
 	}
 
-	pref := e.resolvePreferences(m, res)
+	plan := e.template.plan
+	if plan == nil {
+		plan = newSelectionPlan(m)
+	}
 
-	filteredVariants := e.filterVariants(m, pref)
+	pref := plan.resolvePreferences(res)
 
-	err := e.resolvePattern(e.bestMatchedPattern(filteredVariants, pref))
+	variant, err := plan.bestMatchedVariant(plan.filterVariants(pref), pref)
 	if err != nil {
+		// the fallback representation of the message, no pattern is selected
+		matcherErr = errors.Join(matcherErr, fmt.Errorf("matcher: %w", err))
+		variant.pattern = ast.QuotedPattern{ast.Text(fallbackMessage)}
+	}
+
+	if err := e.resolvePattern(variant.pattern, variant.options); err != nil {
 		return errors.Join(matcherErr, fmt.Errorf("matcher: %w", err))
 	}
 
@@ -498,13 +995,14 @@ func (e *executer) resolveSelector(matcher ast.Matcher) ([]any, error) {
 
 	selectors := make([]any, 0, len(matcher.Selectors))
 
-	addErr := func(err error) {
-		selectorErr = errors.Join(selectorErr, fmt.Errorf("selector: %w", err))
+	for i, selector := range matcher.Selectors {
+		addErr := func(err error) {
+			selectorErr = errors.Join(selectorErr, fmt.Errorf("selector: %w", err))
+			e.warn(selector, err)
 
-		selectors = append(selectors, ast.CatchAllKey{})
-	}
+			selectors = append(selectors, ast.CatchAllKey{})
+		}
 
-	for _, selector := range matcher.Selectors {
 		var function ast.Function
 
 		switch annotation := selector.Annotation.(type) {
@@ -522,9 +1020,11 @@ func (e *executer) resolveSelector(matcher ast.Matcher) ([]any, error) {
 			v, ok := input.(*ResolvedValue)
 			if !ok {
 				addErr(mf2.ErrBadOperand)
+				continue
 			}
 
-			selectors = append(selectors, v.selectKey([]string{}))
+			// the keys are matched by the annotation of the declaration
+			selectors = append(selectors, v)
 
 			continue
 		case ast.ReservedAnnotation, ast.PrivateUseAnnotation:
@@ -533,13 +1033,19 @@ func (e *executer) resolveSelector(matcher ast.Matcher) ([]any, error) {
 			function = annotation
 		}
 
-		f, ok := e.template.registry[function.Identifier.Name]
+		if !e.template.allowedFunc(function.Identifier) {
+			addErr(fmt.Errorf(`function "%s" is not allowed: %w`, function.Identifier, mf2.ErrUnsupportedExpression))
+			continue
+		}
+
+		f, ok := e.registry[function.Identifier.Name]
 		if !ok {
 			addErr(fmt.Errorf(`%w "%s"`, mf2.ErrUnknownFunction, function.Identifier.Name))
 			continue
 		}
 
-		opts, err := e.resolveOptions(function.Options)
+		opts, err := e.resolveOptions(function.Identifier.Name, function.Options,
+			optionsAt(e.template.expressions.selectors, i))
 		if err != nil {
 			addErr(err)
 			continue
@@ -551,7 +1057,7 @@ func (e *executer) resolveSelector(matcher ast.Matcher) ([]any, error) {
 			continue
 		}
 
-		rslt, err := f(NewResolvedValue(input), opts, e.template.locale)
+		rslt, err := e.call(function.Identifier.Name, f, input, opts)
 		if err != nil {
 			addErr(err)
 			continue
@@ -562,160 +1068,3 @@ func (e *executer) resolveSelector(matcher ast.Matcher) ([]any, error) {
 
 	return selectors, selectorErr
 }
-
-func (e *executer) resolvePreferences(m ast.Matcher, res []any) [][]string {
-	// Step 2: Resolve Preferences
-	pref := make([][]string, 0, len(res))
-
-	for i := range res {
-		var keys []string
-
-		for _, variant := range m.Variants {
-			for _, vKey := range variant.Keys {
-				// NOTE(mvilks): since collected keys will be compared to the selector,
-				//	we need the keys's raw string value, not the representation of it
-				//  e.g. the `1` should be equal to `|1|`
-				switch key := vKey.(type) {
-				case ast.CatchAllKey:
-					continue
-				case ast.QuotedLiteral:
-					keys = append(keys, string(key))
-				case ast.NameLiteral:
-					keys = append(keys, string(key))
-				case ast.NumberLiteral:
-					keys = append(keys, key.String())
-				}
-			}
-		}
-
-		rv := res[i]
-
-		matches := matchSelectorKeys(rv, keys)
-		pref = append(pref, matches)
-	}
-
-	return pref
-}
-
-func (e *executer) filterVariants(m ast.Matcher, pref [][]string) []ast.Variant {
-	// Step 3: Filter Variants
-	var filteredVariants []ast.Variant
-
-	for _, variant := range m.Variants {
-		matchesAllSelectors := true
-
-		for i, keyOrder := range pref {
-			key := variant.Keys[i]
-
-			var ks string
-
-			// NOTE(mvilks): since collected keys will be compared to the selector,
-			//	we need the keys's raw string value, not the representation of it
-			//  e.g. the `1` should be equal to `|1|`
-			switch key := key.(type) {
-			case ast.CatchAllKey:
-				continue
-			case ast.QuotedLiteral:
-				ks = string(key)
-			case ast.NameLiteral:
-				ks = string(key)
-			case ast.NumberLiteral:
-				ks = key.String()
-			}
-
-			if !slices.Contains(keyOrder, ks) {
-				matchesAllSelectors = false
-				break
-			}
-		}
-
-		if matchesAllSelectors {
-			filteredVariants = append(filteredVariants, variant)
-		}
-	}
-
-	return filteredVariants
-}
-
-func (e *executer) bestMatchedPattern(filteredVariants []ast.Variant, pref [][]string) ast.QuotedPattern {
-	// Step 4: Sort Variants
-	sortable := make([]sortableVariant, 0, len(filteredVariants))
-
-	for _, variant := range filteredVariants {
-		sortable = append(sortable, sortableVariant{Score: -1, Variant: variant})
-	}
-
-	for i := len(pref) - 1; i >= 0; i-- {
-		matches := pref[i]
-
-		for tupleIndex, tuple := range sortable {
-			key := tuple.Variant.Keys[i]
-			currentScore := len(matches)
-
-			var ks string
-
-			// NOTE(mvilks): since collected keys will be compared to the selector,
-			//	we need the keys's raw string value, not the representation of it
-			//  e.g. the `1` should be equal to `|1|`
-			switch key := key.(type) {
-			case ast.CatchAllKey:
-				sortable[tupleIndex].Score = currentScore
-				continue
-			case ast.QuotedLiteral:
-				ks = string(key)
-			case ast.NameLiteral:
-				ks = string(key)
-			case ast.NumberLiteral:
-				ks = key.String()
-			}
-
-			currentScore = slices.Index(matches, ks)
-
-			sortable[tupleIndex].Score = currentScore
-		}
-
-		sort.Sort(sortableVariants(sortable))
-	}
-
-	return sortable[0].Variant.QuotedPattern
-}
-
-func matchSelectorKeys(rv any, keys []string) []string {
-	if v, ok := rv.(*ResolvedValue); ok {
-		rv = v.selectKey(keys)
-	}
-
-	value, ok := rv.(string)
-	if !ok {
-		return nil
-	}
-
-	var matches []string
-
-	for _, key := range keys {
-		if key == value {
-			matches = append(matches, key)
-		}
-	}
-
-	return matches
-}
-
-type sortableVariant struct {
-	Variant ast.Variant
-	Score   int
-}
-
-type sortableVariants []sortableVariant
-
-func (s sortableVariants) Len() int {
-	return len(s)
-}
-
-func (s sortableVariants) Less(i, j int) bool {
-	return s[i].Score < s[j].Score
-}
-
-func (s sortableVariants) Swap(i, j int) {
-	s[i], s[j] = s[j], s[i]
-}
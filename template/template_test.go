@@ -2,9 +2,13 @@ package template
 
 import (
 	"errors"
+	"fmt"
+	"io"
+	"strings"
 	"testing"
 
 	"go.expect.digital/mf2"
+	ast "go.expect.digital/mf2/parse"
 	"golang.org/x/text/language"
 )
 
@@ -303,6 +307,72 @@ func Test_ExecuteErrors(t *testing.T) {
 	}
 }
 
+func TestWithoutValidation(t *testing.T) {
+	t.Parallel()
+
+	template, err := New(WithParseOptions(ast.WithoutValidation())).
+		Parse(".input {$n :number} .match {$n} 1 {{one}} 2 {{two}}")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		wantErr error
+		want    string
+		n       int
+	}{
+		{n: 1, want: "one"},
+		{n: 3, want: "{\uFFFD}", wantErr: mf2.ErrMissingFallbackVariant},
+	} {
+		got, err := template.Sprint(map[string]any{"n": test.n})
+		if !errors.Is(err, test.wantErr) {
+			t.Errorf("want '%v', got '%v'", test.wantErr, err)
+		}
+
+		if test.want != got {
+			t.Errorf("want '%s', got '%s'", test.want, got)
+		}
+	}
+}
+
+func TestInputDeclarationLiteral(t *testing.T) {
+	t.Parallel()
+
+	// the parser and the binary decoder reject the message, it is built without them
+	template := New()
+	template.compile(ast.AST{Message: ast.ComplexMessage{
+		Declarations: []ast.Declaration{ast.InputDeclaration{Operand: ast.NameLiteral("x")}},
+		ComplexBody:  ast.QuotedPattern{ast.Text("text")},
+	}})
+
+	if _, err := template.Sprint(nil); !errors.Is(err, mf2.ErrBadOperand) {
+		t.Errorf("want '%s', got '%v'", mf2.ErrBadOperand, err)
+	}
+}
+
+func TestParseCache(t *testing.T) {
+	t.Parallel()
+
+	cache := ast.NewCache(10)
+
+	for _, name := range []string{"World", "MF2"} {
+		template, err := New(WithParseCache(cache)).Parse("Hello, { $name }!")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want := "Hello, " + name + "!"
+
+		if got, err := template.Sprint(map[string]any{"name": name}); err != nil || want != got {
+			t.Errorf("want '%s', got '%s' (%v)", want, got, err)
+		}
+	}
+
+	if cache.Len() != 1 {
+		t.Errorf("want 1 cached message, got %d", cache.Len())
+	}
+}
+
 func BenchmarkTemplate_Sprint(b *testing.B) {
 	//nolint:dupword
 	tmpl, err := New().Parse(".match {$foo :string} {$bar :number} one one {{one one}} one * {{one other}} * * {{other}}")
@@ -323,3 +393,206 @@ func BenchmarkTemplate_Sprint(b *testing.B) {
 
 	_ = result
 }
+
+// benchmarkTemplates are templates of different shapes and their input for benchmarks.
+func benchmarkTemplates(b *testing.B) []struct {
+	input    map[string]any
+	template *Template
+	name     string
+} {
+	b.Helper()
+
+	var large strings.Builder
+
+	largeInput := make(map[string]any, 50) //nolint:mnd
+
+	for i := range 50 {
+		fmt.Fprintf(&large, "Lorem ipsum dolor sit amet { $var%d } consectetur { |literal %d| :string } ", i, i)
+		largeInput[fmt.Sprintf("var%d", i)] = i
+	}
+
+	// 4 selectors with 2 keys each, 16 variants
+	deep := ".input { $a :number } .input { $b :number } .input { $c :string } .input { $d :string }\n.match { $a } { $b } { $c } { $d }\n"
+
+	for _, a := range []string{"one", "*"} {
+		for _, b := range []string{"one", "*"} {
+			for _, c := range []string{"x", "*"} {
+				for _, d := range []string{"y", "*"} {
+					deep += fmt.Sprintf("%s %s %s %s {{%s %s { $a } { $b }}}\n", a, b, c, d, c, d)
+				}
+			}
+		}
+	}
+
+	templates := []struct {
+		input    map[string]any
+		template *Template
+		name     string
+	}{
+		{name: "small", template: mustParse(b, "Hello, { $name }!"), input: map[string]any{"name": "World"}},
+		{name: "large", template: mustParse(b, large.String()), input: largeInput},
+		{
+			name:     "deep matcher",
+			template: mustParse(b, deep),
+			input:    map[string]any{"a": 1, "b": 2, "c": "x", "d": "z"},
+		},
+		{
+			name: "many options",
+			template: mustParse(b, "{ $n :number minimumIntegerDigits=2 minimumFractionDigits=1 maximumFractionDigits=3 "+
+				"signDisplay=always useGrouping=always notation=standard style=decimal select=plural }"),
+			input: map[string]any{"n": 1234.5678},
+		},
+		{name: "markup", template: mustParse(b, "{ #a href=|https://example.com| }link{ /a } { #b }bold{ /b } { #br /}")},
+	}
+
+	for _, v := range templates {
+		if err := v.template.Execute(io.Discard, v.input); err != nil {
+			b.Fatal(err)
+		}
+	}
+
+	return templates
+}
+
+func mustParse(b *testing.B, text string) *Template {
+	b.Helper()
+
+	template, err := New().Parse(text)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	return template
+}
+
+func BenchmarkTemplate_Execute(b *testing.B) {
+	for _, test := range benchmarkTemplates(b) {
+		b.Run(test.name, func(b *testing.B) {
+			b.ReportAllocs()
+
+			for range b.N {
+				_ = test.template.Execute(io.Discard, test.input)
+			}
+		})
+	}
+}
+
+func BenchmarkTemplate_Execute_parallel(b *testing.B) {
+	for _, test := range benchmarkTemplates(b) {
+		b.Run(test.name, func(b *testing.B) {
+			b.ReportAllocs()
+
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					_ = test.template.Execute(io.Discard, test.input)
+				}
+			})
+		})
+	}
+}
+
+func BenchmarkTemplate_FormatToParts(b *testing.B) {
+	for _, test := range benchmarkTemplates(b) {
+		b.Run(test.name, func(b *testing.B) {
+			b.ReportAllocs()
+
+			for range b.N {
+				_, _ = test.template.FormatToParts(test.input)
+			}
+		})
+	}
+}
+
+func TestNormalization(t *testing.T) {
+	t.Parallel()
+
+	const (
+		composed   = "café"  // "café" with "é" as a single code point
+		decomposed = "café" // "café" with "e" and a combining acute accent
+	)
+
+	for _, test := range []struct {
+		in    string
+		input map[string]any
+	}{
+		{in: "{ $" + composed + " }", input: map[string]any{decomposed: "ok"}},
+		{in: "{ $" + decomposed + " }", input: map[string]any{composed: "ok"}},
+		{in: ".match { $x :string } " + decomposed + " {{ok}} * {{fail}}", input: map[string]any{"x": composed}},
+		{in: ".match { $x :string } |" + composed + "| {{ok}} * {{fail}}", input: map[string]any{"x": decomposed}},
+	} {
+		t.Run(test.in, func(t *testing.T) {
+			t.Parallel()
+
+			template, err := New().Parse(test.in)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got, err := template.Sprint(test.input); err != nil || got != "ok" {
+				t.Errorf("want 'ok', got '%s' (%v)", got, err)
+			}
+		})
+	}
+}
+
+func TestLocaleOption(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		in, want string
+		input    float64
+		wantErr  bool
+	}{
+		{in: "{ $n :number } { $n :number u:locale=de-DE } { $n :number }", input: 1234.5, want: "1,234.5 1.234,5 1,234.5"},
+		{in: ".match { $n :integer u:locale=lv } one {{one}} * {{other}}", input: 21, want: "one"},
+		{in: "{ $n :number u:locale=|not a locale| }", input: 1, want: "{$n}", wantErr: true},
+	} {
+		t.Run(test.in, func(t *testing.T) {
+			t.Parallel()
+
+			template, err := New(WithLocale(language.English)).Parse(test.in)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := template.Sprint(map[string]any{"n": test.input})
+			if test.wantErr != (err != nil) {
+				t.Errorf("want error %t, got '%v'", test.wantErr, err)
+			}
+
+			if test.want != got {
+				t.Errorf("want '%s', got '%s'", test.want, got)
+			}
+		})
+	}
+}
+
+func TestSourceLocaleOption(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		name, want string
+		options    []Option
+	}{
+		{name: "source locale", options: []Option{WithSourceLocale(language.German)}, want: "1.234,5"},
+		{
+			name:    "locale",
+			options: []Option{WithSourceLocale(language.German), WithLocale(language.Latvian)},
+			want:    "1\u00a0234,5",
+		},
+		{name: "none", want: "1,234.5"},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := MustParse("{ 1234.5 :number }", test.options...).Sprint(nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if test.want != got {
+				t.Errorf("want '%s', got '%s'", test.want, got)
+			}
+		})
+	}
+}
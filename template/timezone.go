@@ -0,0 +1,126 @@
+package template
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"golang.org/x/text/language"
+)
+
+// zoneNames are the English names of the time zone abbreviations, a subset of CLDR metazones.
+var zoneNames = map[string]string{
+	"UTC":  "Coordinated Universal Time",
+	"GMT":  "Greenwich Mean Time",
+	"BST":  "British Summer Time",
+	"WET":  "Western European Standard Time",
+	"WEST": "Western European Summer Time",
+	"CET":  "Central European Standard Time",
+	"CEST": "Central European Summer Time",
+	"EET":  "Eastern European Standard Time",
+	"EEST": "Eastern European Summer Time",
+	"MSK":  "Moscow Standard Time",
+	"IST":  "India Standard Time",
+	"JST":  "Japan Standard Time",
+	"EST":  "Eastern Standard Time",
+	"EDT":  "Eastern Daylight Time",
+	"CST":  "Central Standard Time",
+	"CDT":  "Central Daylight Time",
+	"MST":  "Mountain Standard Time",
+	"MDT":  "Mountain Daylight Time",
+	"PST":  "Pacific Standard Time",
+	"PDT":  "Pacific Daylight Time",
+	"AKST": "Alaska Standard Time",
+	"AKDT": "Alaska Daylight Time",
+	"HST":  "Hawaii-Aleutian Standard Time",
+}
+
+// genericZoneNames are the English generic names, short and long, of the time zones
+// without the daylight saving distinction, a subset of CLDR metazones.
+var genericZoneNames = map[string][2]string{
+	"America/New_York":    {"ET", "Eastern Time"},
+	"America/Detroit":     {"ET", "Eastern Time"},
+	"America/Toronto":     {"ET", "Eastern Time"},
+	"America/Chicago":     {"CT", "Central Time"},
+	"America/Winnipeg":    {"CT", "Central Time"},
+	"America/Denver":      {"MT", "Mountain Time"},
+	"America/Phoenix":     {"MT", "Mountain Time"},
+	"America/Edmonton":    {"MT", "Mountain Time"},
+	"America/Los_Angeles": {"PT", "Pacific Time"},
+	"America/Vancouver":   {"PT", "Pacific Time"},
+	"America/Anchorage":   {"AKT", "Alaska Time"},
+	"Pacific/Honolulu":    {"HST", "Hawaii-Aleutian Time"},
+}
+
+// timeZoneName returns the name of the time zone of the time in the style of the "timeZoneName" option.
+// The names are localized in English, other locales and unknown zones use the GMT offset, e.g. "GMT+2".
+func timeZoneName(t time.Time, style string, locale language.Tag) string {
+	base, _ := locale.Base()
+	english := base.String() == "en"
+
+	abbreviation, _ := t.Zone()
+	location := t.Location().String()
+
+	switch style {
+	default: // shortOffset
+		return gmtOffset(t, false)
+	case "longOffset":
+		return gmtOffset(t, true)
+	case "short":
+		// the abbreviations of some zones are offsets in the time zone database, e.g. "+03"
+		if english && abbreviation != "" && strings.Trim(abbreviation, "+-0123456789") == abbreviation {
+			return abbreviation
+		}
+
+		return gmtOffset(t, false)
+	case "long":
+		if name, ok := zoneNames[abbreviation]; ok && english {
+			return name
+		}
+
+		return gmtOffset(t, true)
+	case "shortGeneric":
+		if names, ok := genericZoneNames[location]; ok && english {
+			return names[0]
+		}
+
+		return timeZoneName(t, "longGeneric", locale)
+	case "longGeneric":
+		if names, ok := genericZoneNames[location]; ok && english {
+			return names[1]
+		}
+
+		// the location format, e.g. "Riga Time" for "Europe/Riga"
+		if i := strings.LastIndexByte(location, '/'); i >= 0 && english {
+			return strings.ReplaceAll(location[i+1:], "_", " ") + " Time"
+		}
+
+		return timeZoneName(t, "long", locale)
+	}
+}
+
+// gmtOffset returns the offset of the time zone in the GMT format, e.g. "GMT+2" or "GMT+05:30" if long.
+// The offset of UTC is "GMT".
+func gmtOffset(t time.Time, long bool) string {
+	_, offset := t.Zone()
+	if offset == 0 {
+		return "GMT"
+	}
+
+	sign := "+"
+	if offset < 0 {
+		sign = "-"
+		offset = -offset
+	}
+
+	hours, minutes := offset/3600, offset%3600/60 //nolint:mnd
+
+	switch {
+	case long:
+		return fmt.Sprintf("GMT%s%02d:%02d", sign, hours, minutes)
+	case minutes != 0:
+		return fmt.Sprintf("GMT%s%d:%02d", sign, hours, minutes)
+	default:
+		return fmt.Sprintf("GMT%s%d", sign, hours)
+	}
+}
@@ -0,0 +1,57 @@
+// Formats the MF2 WG conformance tests with messageformat.js and prints a single
+// conformance JSON file with the results for the differential test, see differential_test.go.
+//
+// Usage:
+//
+//	npm install messageformat@next
+//	node testdata/messageformat.mjs .message-format-wg/test/tests > messageformat.json
+import { readdirSync, readFileSync, statSync } from "node:fs";
+import { join } from "node:path";
+import { MessageFormat } from "messageformat";
+
+const files = (dir) =>
+  readdirSync(dir).flatMap((name) => {
+    const path = join(dir, name);
+    return statSync(path).isDirectory() ? files(path) : path.endsWith(".json") ? [path] : [];
+  });
+
+const errorTypes = {
+  "bad-operand": "bad-operand",
+  "bad-option": "bad-option",
+  "bad-selector": "bad-operand",
+  "duplicate-declaration": "duplicate-declaration",
+  "duplicate-option-name": "duplicate-option-name",
+  "missing-fallback": "missing-fallback-variant",
+  "missing-selector-annotation": "missing-selector-annotation",
+  "parse-error": "syntax-error",
+  "unresolved-variable": "unresolved-variable",
+  "unsupported-expression": "unsupported-expression",
+  "unsupported-statement": "unsupported-statement",
+  "key-mismatch": "variant-key-mismatch",
+};
+
+const tests = [];
+
+for (const file of files(process.argv[2])) {
+  const suite = JSON.parse(readFileSync(file, "utf8"));
+  const defaults = suite.defaultTestProperties ?? {};
+
+  for (const test of suite.tests ?? []) {
+    const locale = test.locale ?? defaults.locale ?? "en-US";
+    const params = Object.fromEntries((test.params ?? []).map((p) => [p.name, p.value]));
+    const errors = [];
+    const result = { src: test.src, locale, params: test.params ?? [] };
+
+    try {
+      const mf = new MessageFormat(locale, test.src);
+      result.exp = mf.format(params, (error) => errors.push({ type: errorTypes[error.type] ?? error.type }));
+    } catch (error) {
+      errors.push({ type: errorTypes[error.type] ?? "syntax-error" });
+    }
+
+    result.expErrors = errors;
+    tests.push(result);
+  }
+}
+
+console.log(JSON.stringify({ tests }, null, 2));
@@ -0,0 +1,137 @@
+package tms
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// csvHeader is the header of the CSV file, the columns of [Entry].
+var csvHeader = []string{"key", "source", "target", "context", "maxLength"}
+
+// EncodeJSON writes the entries as a JSON array of objects with the fields
+// "key", "source", "target", "context" and "maxLength", see [Entry].
+func EncodeJSON(w io.Writer, entries []Entry) error {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+
+	if entries == nil {
+		entries = []Entry{}
+	}
+
+	if err := enc.Encode(entries); err != nil {
+		return fmt.Errorf("encode TMS JSON: %w", err)
+	}
+
+	return nil
+}
+
+// DecodeJSON reads the entries written by [EncodeJSON].
+func DecodeJSON(r io.Reader) ([]Entry, error) {
+	var entries []Entry
+
+	if err := json.NewDecoder(r).Decode(&entries); err != nil {
+		return nil, fmt.Errorf("decode TMS JSON: %w", err)
+	}
+
+	return entries, nil
+}
+
+// EncodeCSV writes the entries as CSV with the header "key,source,target,context,maxLength".
+func EncodeCSV(w io.Writer, entries []Entry) error {
+	errorf := func(format string, args ...any) error {
+		return fmt.Errorf("encode TMS CSV: "+format, args...)
+	}
+
+	cw := csv.NewWriter(w)
+
+	if err := cw.Write(csvHeader); err != nil {
+		return errorf("%w", err)
+	}
+
+	for _, e := range entries {
+		maxLength := ""
+		if e.MaxLength > 0 {
+			maxLength = strconv.Itoa(e.MaxLength)
+		}
+
+		if err := cw.Write([]string{e.Key, e.Source, e.Target, e.Context, maxLength}); err != nil {
+			return errorf("%w", err)
+		}
+	}
+
+	cw.Flush()
+
+	if err := cw.Error(); err != nil {
+		return errorf("%w", err)
+	}
+
+	return nil
+}
+
+// DecodeCSV reads the entries of the CSV file with the header row. The columns are matched by name
+// in any order, see [EncodeCSV], the "key" and "target" columns are required, the unknown columns are ignored.
+func DecodeCSV(r io.Reader) ([]Entry, error) {
+	errorf := func(format string, args ...any) ([]Entry, error) {
+		return nil, fmt.Errorf("decode TMS CSV: "+format, args...)
+	}
+
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+
+	header, err := cr.Read()
+	if err != nil {
+		return errorf("header: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[name] = i
+	}
+
+	for _, name := range []string{"key", "target"} {
+		if _, ok := columns[name]; !ok {
+			return errorf(`missing column "%s"`, name)
+		}
+	}
+
+	var entries []Entry
+
+	for {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			return entries, nil
+		}
+
+		if err != nil {
+			return errorf("%w", err)
+		}
+
+		field := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return record[i]
+			}
+
+			return ""
+		}
+
+		entry := Entry{
+			Key:     field("key"),
+			Source:  field("source"),
+			Target:  field("target"),
+			Context: field("context"),
+		}
+
+		if s := field("maxLength"); s != "" {
+			if entry.MaxLength, err = strconv.Atoi(s); err != nil {
+				return errorf(`entry "%s": maxLength: %w`, entry.Key, err)
+			}
+		}
+
+		entries = append(entries, entry)
+	}
+}
@@ -0,0 +1,522 @@
+/*
+Package tms exports the messages of a bundle to the upload formats of translation management systems (TMS)
+and imports the translated downloads, see [EncodeJSON] and [EncodeCSV].
+
+Each translatable pattern is an [Entry] with the source text of the default locale of the bundle,
+the translation, the description and the notes of the message as the context, and the length limit from
+the "maxLength" metadata of the message, see [MaxLengthKey].
+
+Placeholders (expressions and markup) are protected as numbered tokens, e.g. "Hello, {0}!" for
+"Hello, { $name }!", so the translators and the TMS quality checks can not break them.
+The literal braces of the text are doubled, e.g. "{{" for "{". The tokens are numbered in order
+of the first appearance in the message, the same placeholder has the same token in all variants.
+
+Each variant of a matcher is a separate entry with the key "id#keys", e.g. "apples#one".
+Declarations and selectors are not translatable, they are kept from the translated message
+or, if the message is not translated yet, from the source message.
+
+Example:
+
+	entries, err := tms.Export(b, language.Latvian)
+	...
+	err = tms.EncodeCSV(w, entries)
+	...
+	entries, err = tms.DecodeCSV(r) // the download of the TMS
+	...
+	c, err := tms.Import(b, language.Latvian, entries)
+*/
+package tms
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"golang.org/x/text/language"
+
+	"go.expect.digital/mf2/bundle"
+	"go.expect.digital/mf2/catalog"
+	"go.expect.digital/mf2/parse"
+)
+
+// MaxLengthKey is the metadata key of the length limit of the translation, e.g. "20".
+const MaxLengthKey = "maxLength"
+
+// Entry is a translatable pattern.
+type Entry struct {
+	// Key is the message ID, or "id#keys" for the variant of a matcher, e.g. "apples#one".
+	Key string `json:"key"`
+	// Source is the protected pattern of the source message.
+	Source string `json:"source"`
+	// Target is the protected translation, empty if not translated.
+	Target string `json:"target,omitempty"`
+	// Context is the description and the notes of the message, one per line.
+	Context string `json:"context,omitempty"`
+	// MaxLength is the length limit of the translation, zero if not limited.
+	MaxLength int `json:"maxLength,omitempty"`
+}
+
+// Export returns the entries of the messages of the default locale of the bundle, sorted by ID,
+// with the translations of the locale. The messages added by [catalog.Catalog.Merge] and not translated
+// yet have no translation.
+func Export(b *bundle.Bundle, locale language.Tag) ([]Entry, error) {
+	errorf := func(format string, args ...any) ([]Entry, error) {
+		return nil, fmt.Errorf("export TMS entries: "+format, args...)
+	}
+
+	src := b.Catalog(b.DefaultLocale())
+	if src == nil {
+		return errorf("missing catalog of the default locale %s", b.DefaultLocale())
+	}
+
+	dst := b.Catalog(locale)
+
+	var entries []Entry
+
+	for _, id := range src.IDs() {
+		m, err := newMessage(src, dst, id)
+		if err != nil {
+			return errorf(`message "%s": %w`, id, err)
+		}
+
+		entries = append(entries, m.entries()...)
+	}
+
+	return entries, nil
+}
+
+// Import sets the translations of the entries in the catalog of the locale, the catalog is added
+// to the bundle if missing. The entries without the translation are skipped. The imported messages
+// are translated, see [catalog.Message.Status].
+//
+// The invalid entries are reported together, the other entries are imported.
+func Import(b *bundle.Bundle, locale language.Tag, entries []Entry) (*catalog.Catalog, error) {
+	errorf := func(format string, args ...any) (*catalog.Catalog, error) {
+		return nil, fmt.Errorf("import TMS entries: "+format, args...)
+	}
+
+	src := b.Catalog(b.DefaultLocale())
+	if src == nil {
+		return errorf("missing catalog of the default locale %s", b.DefaultLocale())
+	}
+
+	dst := b.Catalog(locale)
+	if dst == nil {
+		dst = catalog.New(locale)
+		b.Add(dst)
+	}
+
+	// the translated patterns by message ID and variant keys in order of appearance
+	var ids []string
+
+	targets := make(map[string][]Entry)
+
+	var errs []error
+
+	for _, entry := range entries {
+		if entry.Target == "" {
+			continue
+		}
+
+		id := entry.Key
+		if _, ok := src.Message(id); !ok {
+			id, _ = splitKey(entry.Key)
+		}
+
+		if _, ok := src.Message(id); !ok {
+			errs = append(errs, fmt.Errorf(`entry "%s": %w "%s"`, entry.Key, catalog.ErrMissingMessage, id))
+			continue
+		}
+
+		if _, ok := targets[id]; !ok {
+			ids = append(ids, id)
+		}
+
+		targets[id] = append(targets[id], entry)
+	}
+
+	for _, id := range ids {
+		m, err := newMessage(src, dst, id)
+		if err != nil {
+			errs = append(errs, fmt.Errorf(`message "%s": %w`, id, err))
+			continue
+		}
+
+		msg, err := m.translate(targets[id])
+		if err != nil {
+			errs = append(errs, fmt.Errorf(`message "%s": %w`, id, err))
+			continue
+		}
+
+		dst.SetMessage(msg)
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return dst, fmt.Errorf("import TMS entries: %w", err)
+	}
+
+	return dst, nil
+}
+
+// message is the source message and its translation.
+type message struct {
+	source     catalog.Message
+	sourceTree parse.AST
+	// structure is the translation, if translated, otherwise the source message.
+	// It has the declarations and the selectors of the entries.
+	structure parse.AST
+	// target is the translated message, if translated.
+	target *catalog.Message
+	// placeholders are the sources of the placeholders of the structure and the source message,
+	// the index is the token.
+	placeholders []string
+}
+
+// newMessage returns the message with the source from src and the translation from dst, if any.
+func newMessage(src, dst *catalog.Catalog, id string) (*message, error) {
+	source, _ := src.Message(id)
+
+	sourceTree, err := parse.Parse(source.Text)
+	if err != nil {
+		return nil, fmt.Errorf("source: %w", err)
+	}
+
+	m := &message{source: source, sourceTree: sourceTree, structure: sourceTree}
+
+	if dst != nil {
+		if target, ok := dst.Message(id); ok && target.Status != catalog.StatusNew {
+			tree, err := parse.Parse(target.Text)
+			if err != nil {
+				return nil, fmt.Errorf("target: %w", err)
+			}
+
+			m.target = &target
+			m.structure = tree
+		}
+	}
+
+	seen := make(map[string]bool)
+
+	for _, tree := range []parse.AST{m.structure, sourceTree} {
+		for _, pattern := range patterns(tree) {
+			for _, part := range pattern {
+				if _, ok := part.(parse.Text); ok {
+					continue
+				}
+
+				if s := part.String(); !seen[s] {
+					seen[s] = true
+					m.placeholders = append(m.placeholders, s)
+				}
+			}
+		}
+	}
+
+	return m, nil
+}
+
+// entries returns the entries of the patterns of the message.
+func (m *message) entries() []Entry {
+	maxLength, _ := strconv.Atoi(m.source.Metadata[MaxLengthKey])
+
+	entry := func(key string, source, target []parse.PatternPart) Entry {
+		e := Entry{
+			Key:       key,
+			Source:    m.protect(source),
+			Context:   context(m.source),
+			MaxLength: maxLength,
+		}
+
+		if m.target != nil {
+			e.Target = m.protect(target)
+		}
+
+		return e
+	}
+
+	matcher, ok := body(m.structure).(parse.Matcher)
+	if !ok {
+		return []Entry{entry(m.source.ID, patterns(m.sourceTree)[0], patterns(m.structure)[0])}
+	}
+
+	entries := make([]Entry, 0, len(matcher.Variants))
+
+	for _, variant := range matcher.Variants {
+		keys := variantKeys(variant)
+
+		entries = append(entries, entry(
+			m.source.ID+"#"+keys,
+			sourceVariant(m.sourceTree, keys),
+			variant.QuotedPattern,
+		))
+	}
+
+	return entries
+}
+
+// translate returns the translated message of the entries.
+func (m *message) translate(entries []Entry) (catalog.Message, error) {
+	msg := catalog.Message{
+		ID:          m.source.ID,
+		Description: m.source.Description,
+		Notes:       m.source.Notes,
+		Metadata:    m.source.Metadata,
+		Source:      m.source.Text,
+	}
+
+	if m.target != nil {
+		msg.Metadata = m.target.Metadata
+	}
+
+	var tree parse.AST
+
+	switch v := m.structure.Message.(type) {
+	case parse.SimpleMessage:
+		pattern, err := m.unprotect(entries[0].Target)
+		if err != nil {
+			return catalog.Message{}, fmt.Errorf(`entry "%s": %w`, entries[0].Key, err)
+		}
+
+		tree.Message = simpleMessage(pattern)
+	case parse.ComplexMessage:
+		matcher, ok := v.ComplexBody.(parse.Matcher)
+		if !ok {
+			pattern, err := m.unprotect(entries[0].Target)
+			if err != nil {
+				return catalog.Message{}, fmt.Errorf(`entry "%s": %w`, entries[0].Key, err)
+			}
+
+			tree.Message = parse.ComplexMessage{Declarations: v.Declarations, ComplexBody: parse.QuotedPattern(pattern)}
+
+			break
+		}
+
+		variants := make([]parse.Variant, 0, len(entries))
+
+		for _, entry := range entries {
+			_, keys := splitKey(entry.Key)
+
+			variantKeys, err := parseKeys(matcher.Selectors, keys)
+			if err != nil {
+				return catalog.Message{}, fmt.Errorf(`entry "%s": %w`, entry.Key, err)
+			}
+
+			pattern, err := m.unprotect(entry.Target)
+			if err != nil {
+				return catalog.Message{}, fmt.Errorf(`entry "%s": %w`, entry.Key, err)
+			}
+
+			variants = append(variants, parse.Variant{Keys: variantKeys, QuotedPattern: pattern})
+		}
+
+		tree.Message = parse.ComplexMessage{
+			Declarations: v.Declarations,
+			ComplexBody:  parse.Matcher{Selectors: matcher.Selectors, Variants: variants},
+		}
+	}
+
+	msg.Text = tree.String()
+
+	// the translation must be a valid message, e.g. the matcher must have the catch-all variant
+	if _, err := parse.Parse(msg.Text); err != nil {
+		return catalog.Message{}, fmt.Errorf("translation: %w", err)
+	}
+
+	return msg, nil
+}
+
+// context returns the context of the message for translators, the description and the notes on separate lines.
+func context(msg catalog.Message) string {
+	lines := make([]string, 0, len(msg.Notes)+1)
+
+	if msg.Description != "" {
+		lines = append(lines, msg.Description)
+	}
+
+	return strings.Join(append(lines, msg.Notes...), "\n")
+}
+
+// protect returns the pattern with the placeholders as tokens and the braces of the text doubled.
+func (m *message) protect(pattern []parse.PatternPart) string {
+	var sb strings.Builder
+
+	for _, part := range pattern {
+		if text, ok := part.(parse.Text); ok {
+			sb.WriteString(strings.NewReplacer("{", "{{", "}", "}}").Replace(string(text)))
+			continue
+		}
+
+		for i, s := range m.placeholders {
+			if s == part.String() {
+				sb.WriteString("{" + strconv.Itoa(i) + "}")
+				break
+			}
+		}
+	}
+
+	return sb.String()
+}
+
+// unprotect returns the pattern of the protected text, see [message.protect].
+func (m *message) unprotect(s string) (parse.QuotedPattern, error) {
+	var (
+		pattern parse.QuotedPattern
+		text    strings.Builder
+	)
+
+	flush := func() {
+		if text.Len() > 0 {
+			pattern = append(pattern, parse.Text(text.String()))
+			text.Reset()
+		}
+	}
+
+	for i := 0; i < len(s); i++ {
+		switch {
+		case strings.HasPrefix(s[i:], "{{"), strings.HasPrefix(s[i:], "}}"):
+			text.WriteByte(s[i])
+			i++
+		case s[i] == '{':
+			end := strings.IndexByte(s[i:], '}')
+			if end < 0 {
+				return nil, fmt.Errorf(`unclosed placeholder at %d in "%s"`, i, s)
+			}
+
+			n, err := strconv.Atoi(s[i+1 : i+end])
+			if err != nil || n < 0 || n >= len(m.placeholders) {
+				return nil, fmt.Errorf(`unknown placeholder "%s"`, s[i:i+end+1])
+			}
+
+			tree, err := parse.Parse(m.placeholders[n], parse.WithoutValidation())
+			if err != nil {
+				return nil, fmt.Errorf(`placeholder "%s": %w`, m.placeholders[n], err)
+			}
+
+			flush()
+
+			pattern = append(pattern, patterns(tree)[0]...)
+			i += end
+		case s[i] == '}':
+			return nil, fmt.Errorf(`unopened placeholder at %d in "%s"`, i, s)
+		default:
+			text.WriteByte(s[i])
+		}
+	}
+
+	flush()
+
+	return pattern, nil
+}
+
+// simpleMessage returns the simple message of the pattern, or the complex message if the pattern
+// can not start the simple message, e.g. the text starting with ".".
+func simpleMessage(pattern parse.QuotedPattern) parse.Message { //nolint:ireturn
+	if text, ok := firstText(pattern); ok && (strings.HasPrefix(text, ".") || strings.TrimLeft(text, " \t\r\n") != text) {
+		return parse.ComplexMessage{ComplexBody: pattern}
+	}
+
+	return parse.SimpleMessage(pattern)
+}
+
+func firstText(pattern parse.QuotedPattern) (string, bool) {
+	if len(pattern) == 0 {
+		return "", false
+	}
+
+	text, ok := pattern[0].(parse.Text)
+
+	return string(text), ok
+}
+
+// body returns the body of the message, nil for the simple message.
+func body(tree parse.AST) parse.ComplexBody { //nolint:ireturn
+	if m, ok := tree.Message.(parse.ComplexMessage); ok {
+		return m.ComplexBody
+	}
+
+	return nil
+}
+
+// patterns returns the patterns of the message, the pattern of each variant of the matcher.
+func patterns(tree parse.AST) [][]parse.PatternPart {
+	switch v := tree.Message.(type) {
+	case parse.SimpleMessage:
+		return [][]parse.PatternPart{v}
+	case parse.ComplexMessage:
+		switch b := v.ComplexBody.(type) {
+		case parse.QuotedPattern:
+			return [][]parse.PatternPart{b}
+		case parse.Matcher:
+			result := make([][]parse.PatternPart, 0, len(b.Variants))
+
+			for _, variant := range b.Variants {
+				result = append(result, variant.QuotedPattern)
+			}
+
+			return result
+		}
+	}
+
+	return [][]parse.PatternPart{nil}
+}
+
+// variantKeys returns the keys of the variant in MF2 syntax, e.g. "one *".
+func variantKeys(variant parse.Variant) string {
+	keys := make([]string, len(variant.Keys))
+
+	for i, key := range variant.Keys {
+		keys[i] = key.String()
+	}
+
+	return strings.Join(keys, " ")
+}
+
+// sourceVariant returns the pattern of the source variant with the keys, or the pattern
+// of the catch-all variant, or the pattern of the message without the matcher.
+func sourceVariant(source parse.AST, keys string) []parse.PatternPart {
+	matcher, ok := body(source).(parse.Matcher)
+	if !ok {
+		return patterns(source)[0]
+	}
+
+	var fallback []parse.PatternPart
+
+	for _, variant := range matcher.Variants {
+		variantKeys := variantKeys(variant)
+
+		if variantKeys == keys {
+			return variant.QuotedPattern
+		}
+
+		if strings.Trim(variantKeys, "* ") == "" {
+			fallback = variant.QuotedPattern
+		}
+	}
+
+	return fallback
+}
+
+// splitKey returns the message ID and the variant keys of the entry key "id#keys".
+func splitKey(key string) (string, string) {
+	i := strings.LastIndexByte(key, '#')
+	if i < 0 {
+		return key, ""
+	}
+
+	return key[:i], key[i+1:]
+}
+
+// parseKeys parses the variant keys in MF2 syntax, e.g. "one *", for the selectors.
+func parseKeys(selectors []parse.Expression, keys string) ([]parse.VariantKey, error) {
+	matcher := parse.Matcher{Selectors: selectors}
+
+	tree, err := parse.Parse(matcher.String()+keys+" {{}}", parse.WithoutValidation())
+	if err != nil {
+		return nil, fmt.Errorf(`variant keys "%s": %w`, keys, err)
+	}
+
+	variants := body(tree).(parse.Matcher).Variants //nolint:forcetypeassert // always matcher
+
+	return variants[0].Keys, nil
+}
@@ -0,0 +1,211 @@
+package tms
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"golang.org/x/text/language"
+
+	"go.expect.digital/mf2/bundle/bundletest"
+	"go.expect.digital/mf2/catalog"
+)
+
+// messages are the messages of the tests by locale, see [bundletest.New].
+var messages = map[language.Tag][]catalog.Message{
+	language.English: {
+		{
+			ID:          "greeting",
+			Text:        "Hello, { $name }! {#b}\\{braces\\}{/b}",
+			Description: "Home page",
+			Notes:       []string{"Informal"},
+			Metadata:    map[string]string{MaxLengthKey: "30"},
+		},
+		{ID: "apples", Text: ".input { $count :number }\n.match { $count }\none {{{ $count } apple}}\n* {{{ $count } apples}}"},
+		{ID: "farewell", Text: "Goodbye!"},
+	},
+	language.Latvian: {
+		{ID: "greeting", Text: "Sveiki, { $name }! {#b}\\{iekavas\\}{/b}"},
+		{ID: "farewell", Text: "Goodbye!", Status: catalog.StatusNew},
+	},
+}
+
+func TestExport(t *testing.T) {
+	t.Parallel()
+
+	got, err := Export(bundletest.New(language.English, messages), language.Latvian)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Entry{
+		{Key: "apples#one", Source: "{0} apple"},
+		{Key: "apples#*", Source: "{0} apples"},
+		{Key: "farewell", Source: "Goodbye!"},
+		{
+			Key:       "greeting",
+			Source:    "Hello, {0}! {1}{{braces}}{2}",
+			Target:    "Sveiki, {0}! {1}{{iekavas}}{2}",
+			Context:   "Home page\nInformal",
+			MaxLength: 30,
+		},
+	}
+
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("want %+v, got %+v", want, got)
+	}
+}
+
+func TestImport(t *testing.T) {
+	t.Parallel()
+
+	b := bundletest.New(language.English, messages)
+
+	c, err := Import(b, language.Latvian, []Entry{
+		{Key: "apples#one", Target: "{0} ābols"},
+		{Key: "apples#zero", Target: "{0} ābolu"},
+		{Key: "apples#*", Target: "{0} āboli"},
+		{Key: "farewell", Target: ".. Ardievu!"},
+		{Key: "greeting", Target: "{1}Čau{2}, {0}!"},
+		{Key: "untranslated"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		input map[string]any
+		id    string
+		want  string
+	}{
+		{id: "apples", input: map[string]any{"count": 1}, want: "1 ābols"},
+		{id: "apples", input: map[string]any{"count": 10}, want: "10 ābolu"},
+		{id: "apples", input: map[string]any{"count": 2}, want: "2 āboli"},
+		{id: "farewell", want: ".. Ardievu!"},
+		{id: "greeting", input: map[string]any{"name": "Jānis"}, want: "Čau, Jānis!"},
+	} {
+		if got, err := b.Sprint(language.Latvian, test.id, test.input); err != nil || test.want != got {
+			t.Errorf("want '%s', got '%s' (%v)", test.want, got, err)
+		}
+	}
+
+	msg, _ := c.Message("farewell")
+	if msg.Status != "" || msg.Source != "Goodbye!" {
+		t.Errorf("want translated message, got %+v", msg)
+	}
+
+	// the imported messages round-trip
+	entries, err := Export(b, language.Latvian)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := "{0} ābolu"; entries[1].Key != "apples#zero" || entries[1].Target != want {
+		t.Errorf("want '%s', got %+v", want, entries[1])
+	}
+}
+
+func TestImportErrors(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		wantErr error
+		entry   Entry
+	}{
+		{entry: Entry{Key: "missing", Target: "x"}, wantErr: catalog.ErrMissingMessage},
+		{entry: Entry{Key: "greeting", Target: "Sveiki, {9}!"}},
+		{entry: Entry{Key: "greeting", Target: "Sveiki, {0!"}},
+		{entry: Entry{Key: "greeting", Target: "Sveiki, }"}},
+		{entry: Entry{Key: "apples#one", Target: "{0} ābols"}}, // missing catch-all variant
+	} {
+		_, err := Import(bundletest.New(language.English, messages), language.Latvian, []Entry{test.entry})
+		if err == nil || test.wantErr != nil && !errors.Is(err, test.wantErr) {
+			t.Errorf("%s: want error '%v', got '%v'", test.entry.Target, test.wantErr, err)
+		}
+	}
+}
+
+func TestEncodeDecode(t *testing.T) {
+	t.Parallel()
+
+	entries := []Entry{
+		{Key: "greeting", Source: "Hello, {0}!", Target: "Sveiki, {0}!", Context: "Home, \"page\"", MaxLength: 30},
+		{Key: "apples#*", Source: "{0} apples"},
+	}
+
+	for _, test := range []struct {
+		encode func(w *strings.Builder, entries []Entry) error
+		decode func(r *strings.Reader) ([]Entry, error)
+		name   string
+		want   string
+	}{
+		{
+			name:   "csv",
+			encode: func(w *strings.Builder, entries []Entry) error { return EncodeCSV(w, entries) },
+			decode: func(r *strings.Reader) ([]Entry, error) { return DecodeCSV(r) },
+			want: "key,source,target,context,maxLength\n" +
+				"greeting,\"Hello, {0}!\",\"Sveiki, {0}!\",\"Home, \"\"page\"\"\",30\n" +
+				"apples#*,{0} apples,,,\n",
+		},
+		{
+			name:   "json",
+			encode: func(w *strings.Builder, entries []Entry) error { return EncodeJSON(w, entries) },
+			decode: func(r *strings.Reader) ([]Entry, error) { return DecodeJSON(r) },
+			want: `[
+  {
+    "key": "greeting",
+    "source": "Hello, {0}!",
+    "target": "Sveiki, {0}!",
+    "context": "Home, \"page\"",
+    "maxLength": 30
+  },
+  {
+    "key": "apples#*",
+    "source": "{0} apples"
+  }
+]
+`,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			var sb strings.Builder
+
+			if err := test.encode(&sb, entries); err != nil {
+				t.Fatal(err)
+			}
+
+			if test.want != sb.String() {
+				t.Errorf("want '%s', got '%s'", test.want, sb.String())
+			}
+
+			got, err := test.decode(strings.NewReader(sb.String()))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(entries, got) {
+				t.Errorf("want %+v, got %+v", entries, got)
+			}
+		})
+	}
+}
+
+func TestDecodeCSVColumns(t *testing.T) {
+	t.Parallel()
+
+	got, err := DecodeCSV(strings.NewReader("target,notes,key\nSveiki!,x,greeting\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []Entry{{Key: "greeting", Target: "Sveiki!"}}; !reflect.DeepEqual(want, got) {
+		t.Errorf("want %+v, got %+v", want, got)
+	}
+
+	if _, err := DecodeCSV(strings.NewReader("key,source\ngreeting,Hello!\n")); err == nil {
+		t.Error("want missing column error, got nil")
+	}
+}
@@ -0,0 +1,755 @@
+// Package xliff converts MF2 messages to and from XLIFF 2 documents.
+//
+// Placeholders (expressions and standalone markup) are written as <ph> elements,
+// paired open/close markup as <pc> elements and unpaired markup as isolated
+// <sc>/<ec> elements. The MF2 source of every placeholder is kept in the unit's
+// <originalData>, so translation tools can move placeholders around
+// without destroying expressions.
+//
+// Declarations and selectors of complex messages are not translatable and are kept
+// in the unit's metadata (XLIFF 2 Metadata module). Each variant of a matcher is a
+// separate <segment>, source and target variants are aligned by variant keys.
+//
+// The notes for translators are the unit's <notes>.
+package xliff
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"golang.org/x/text/language"
+
+	"go.expect.digital/mf2/parse"
+)
+
+// Metadata categories and types.
+const (
+	categoryMessage = "mf2"
+	categoryVariant = "mf2:variant"
+
+	metaDeclarations = "declarations"
+	metaSelectors    = "selectors"
+	metaKeys         = "keys"
+)
+
+// Document is a XLIFF 2 document containing MF2 messages.
+type Document struct {
+	SrcLang language.Tag
+	TrgLang language.Tag // Optional
+	Units   []Unit
+}
+
+// Unit is a single message with the source and optional target (translation).
+type Unit struct {
+	ID     string
+	Source parse.AST
+	Target parse.AST // Optional
+	// Notes are the notes for translators, e.g. the description of the message.
+	Notes []string
+}
+
+// Encode writes the document as XLIFF 2 to the writer.
+func Encode(w io.Writer, doc Document) error {
+	errorf := func(format string, args ...any) error {
+		return fmt.Errorf("encode XLIFF: "+format, args...)
+	}
+
+	root := xmlXLIFF{
+		Version: "2.0",
+		SrcLang: doc.SrcLang.String(),
+		File:    []xmlFile{{ID: "f1"}},
+	}
+
+	if doc.TrgLang != language.Und {
+		root.TrgLang = doc.TrgLang.String()
+	}
+
+	for _, unit := range doc.Units {
+		u, err := encodeUnit(unit)
+		if err != nil {
+			return errorf("unit %s: %w", unit.ID, err)
+		}
+
+		root.File[0].Units = append(root.File[0].Units, u)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return errorf("%w", err)
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+
+	if err := enc.Encode(root); err != nil {
+		return errorf("%w", err)
+	}
+
+	return nil
+}
+
+// Decode reads XLIFF 2 document from the reader.
+func Decode(r io.Reader) (Document, error) {
+	errorf := func(format string, args ...any) (Document, error) {
+		return Document{}, fmt.Errorf("decode XLIFF: "+format, args...)
+	}
+
+	var root xmlXLIFF
+
+	if err := xml.NewDecoder(r).Decode(&root); err != nil {
+		return errorf("%w", err)
+	}
+
+	if root.Version != "2.0" && !strings.HasPrefix(root.Version, "2.") {
+		return errorf(`want version 2.x, got "%s"`, root.Version)
+	}
+
+	var (
+		doc Document
+		err error
+	)
+
+	if doc.SrcLang, err = language.Parse(root.SrcLang); err != nil {
+		return errorf("source language: %w", err)
+	}
+
+	if root.TrgLang != "" {
+		if doc.TrgLang, err = language.Parse(root.TrgLang); err != nil {
+			return errorf("target language: %w", err)
+		}
+	}
+
+	for _, file := range root.File {
+		for _, u := range file.Units {
+			unit, err := decodeUnit(u)
+			if err != nil {
+				return errorf("unit %s: %w", u.ID, err)
+			}
+
+			doc.Units = append(doc.Units, unit)
+		}
+	}
+
+	return doc, nil
+}
+
+// --------------------------------Encoding------------------------------------
+
+// side is a source or target message split to parts stored in XLIFF.
+type side struct {
+	declarations []parse.Declaration
+	selectors    []parse.Expression
+	variants     []parse.Variant // a single variant without keys for pattern messages
+	isComplex    bool
+}
+
+func newSide(tree parse.AST) (side, error) {
+	switch m := tree.Message.(type) {
+	default:
+		return side{}, fmt.Errorf("unsupported message type %T", m)
+	case nil:
+		return side{variants: []parse.Variant{{}}}, nil
+	case parse.SimpleMessage:
+		return side{variants: []parse.Variant{{QuotedPattern: parse.QuotedPattern(m)}}}, nil
+	case parse.ComplexMessage:
+		s := side{declarations: m.Declarations, isComplex: true}
+
+		switch body := m.ComplexBody.(type) {
+		case parse.QuotedPattern:
+			s.variants = []parse.Variant{{QuotedPattern: body}}
+		case parse.Matcher:
+			s.selectors = body.Selectors
+			s.variants = body.Variants
+		}
+
+		return s, nil
+	}
+}
+
+// metaGroup returns metadata describing non-translatable parts of the message.
+func (s side) metaGroup(appliesTo string) (xmlMetaGroup, bool) {
+	if !s.isComplex {
+		return xmlMetaGroup{}, false
+	}
+
+	group := xmlMetaGroup{Category: categoryMessage, AppliesTo: appliesTo}
+
+	if len(s.declarations) > 0 {
+		group.Metas = append(group.Metas, xmlMeta{Type: metaDeclarations, Value: joinNodes(s.declarations, "\n")})
+	}
+
+	if len(s.selectors) > 0 {
+		group.Metas = append(group.Metas, xmlMeta{Type: metaSelectors, Value: joinNodes(s.selectors, " ")})
+	}
+
+	return group, true
+}
+
+func (s side) isMatcher() bool { return len(s.selectors) > 0 }
+
+// segment is source and target patterns aligned by variant keys.
+type segment struct {
+	source, target *parse.Variant
+}
+
+func (s segment) keys() string {
+	if s.source != nil {
+		return joinNodes(s.source.Keys, " ")
+	}
+
+	return joinNodes(s.target.Keys, " ")
+}
+
+// align aligns source and target variants by keys.
+func align(source, target side, hasTarget bool) []segment {
+	if !hasTarget {
+		segments := make([]segment, 0, len(source.variants))
+		for i := range source.variants {
+			segments = append(segments, segment{source: &source.variants[i]})
+		}
+
+		return segments
+	}
+
+	if !source.isMatcher() && !target.isMatcher() {
+		return []segment{{source: &source.variants[0], target: &target.variants[0]}}
+	}
+
+	segments := make([]segment, 0, len(source.variants))
+	used := make([]bool, len(target.variants))
+	next := 0 // next target variant not yet added
+
+	// addTargets adds unpaired target variants before index end, preserving the order of target variants.
+	addTargets := func(end int) {
+		for ; next < end; next++ {
+			if !used[next] {
+				segments = append(segments, segment{target: &target.variants[next]})
+				used[next] = true
+			}
+		}
+	}
+
+	for i := range source.variants {
+		seg := segment{source: &source.variants[i]}
+
+		if source.isMatcher() && target.isMatcher() {
+			keys := seg.keys()
+
+			for j := range target.variants {
+				if !used[j] && joinNodes(target.variants[j].Keys, " ") == keys {
+					addTargets(j)
+
+					seg.target = &target.variants[j]
+					used[j] = true
+
+					break
+				}
+			}
+		}
+
+		segments = append(segments, seg)
+	}
+
+	addTargets(len(target.variants))
+
+	return segments
+}
+
+func encodeUnit(unit Unit) (xmlUnit, error) {
+	source, err := newSide(unit.Source)
+	if err != nil {
+		return xmlUnit{}, fmt.Errorf("source: %w", err)
+	}
+
+	hasTarget := unit.Target.Message != nil
+
+	target, err := newSide(unit.Target)
+	if err != nil {
+		return xmlUnit{}, fmt.Errorf("target: %w", err)
+	}
+
+	u := xmlUnit{ID: unit.ID}
+
+	if len(unit.Notes) > 0 {
+		u.Notes = &xmlNotes{Notes: unit.Notes}
+	}
+	metadata := new(xmlMetadata)
+
+	if group, ok := source.metaGroup("source"); ok {
+		metadata.Groups = append(metadata.Groups, group)
+	}
+
+	if group, ok := target.metaGroup("target"); ok && hasTarget {
+		metadata.Groups = append(metadata.Groups, group)
+	}
+
+	enc := &inlineEncoder{data: make(map[string]string)}
+
+	for i, seg := range align(source, target, hasTarget) {
+		id := "s" + strconv.Itoa(i+1)
+		xmlSeg := xmlSegment{ID: id}
+
+		// Describe the segment only when it is not a plain source/target pair.
+		group := xmlMetaGroup{ID: id, Category: categoryVariant}
+
+		switch {
+		case seg.target == nil && hasTarget:
+			group.AppliesTo = "source"
+		case seg.source == nil:
+			group.AppliesTo = "target"
+		}
+
+		if source.isMatcher() && seg.source != nil || target.isMatcher() && seg.target != nil {
+			group.Metas = append(group.Metas, xmlMeta{Type: metaKeys, Value: seg.keys()})
+		}
+
+		if group.AppliesTo != "" || len(group.Metas) > 0 {
+			metadata.Groups = append(metadata.Groups, group)
+		}
+
+		if seg.source != nil {
+			xmlSeg.Source.Inner = enc.encode(seg.source.QuotedPattern)
+		}
+
+		if seg.target != nil {
+			xmlSeg.Target = &xmlContent{Inner: enc.encode(seg.target.QuotedPattern)}
+		}
+
+		u.Segments = append(u.Segments, xmlSeg)
+	}
+
+	if len(metadata.Groups) > 0 {
+		u.Metadata = metadata
+	}
+
+	if len(enc.dataOrder) > 0 {
+		u.OriginalData = new(xmlOriginalData)
+
+		for _, s := range enc.dataOrder {
+			u.OriginalData.Data = append(u.OriginalData.Data, xmlData{ID: enc.data[s], Value: s})
+		}
+	}
+
+	return u, nil
+}
+
+// inlineEncoder encodes pattern parts as XLIFF inline content.
+// Placeholder MF2 sources are collected as original data, shared across all segments of the unit.
+type inlineEncoder struct {
+	data      map[string]string // MF2 source -> data ID
+	dataOrder []string
+	sb        strings.Builder
+	id        int
+}
+
+// dataRef returns the original data ID of the node.
+func (e *inlineEncoder) dataRef(node parse.Node) string {
+	s := node.String()
+
+	if id, ok := e.data[s]; ok {
+		return id
+	}
+
+	id := "d" + strconv.Itoa(len(e.dataOrder)+1)
+	e.data[s] = id
+	e.dataOrder = append(e.dataOrder, s)
+
+	return id
+}
+
+func (e *inlineEncoder) nextID() string {
+	e.id++
+
+	return strconv.Itoa(e.id)
+}
+
+// encode returns the inline content of the pattern.
+func (e *inlineEncoder) encode(pattern []parse.PatternPart) string {
+	e.sb.Reset()
+	e.id = 0
+	e.pattern(pattern)
+
+	return e.sb.String()
+}
+
+func (e *inlineEncoder) pattern(pattern []parse.PatternPart) {
+	for i := 0; i < len(pattern); i++ {
+		switch v := pattern[i].(type) {
+		case parse.Text:
+			_ = xml.EscapeText(&e.sb, []byte(v))
+		case parse.Expression:
+			fmt.Fprintf(&e.sb, `<ph id="%s" dataRef="%s"/>`, e.nextID(), e.dataRef(v))
+		case parse.Markup:
+			switch v.Typ {
+			case parse.Unspecified, parse.SelfClose:
+				fmt.Fprintf(&e.sb, `<ph id="%s" dataRef="%s"/>`, e.nextID(), e.dataRef(v))
+			case parse.Open:
+				end := closingMarkup(pattern, i)
+				if end == -1 {
+					fmt.Fprintf(&e.sb, `<sc id="%s" dataRef="%s" isolated="yes"/>`, e.nextID(), e.dataRef(v))
+					continue
+				}
+
+				fmt.Fprintf(&e.sb, `<pc id="%s" dataRefStart="%s" dataRefEnd="%s">`,
+					e.nextID(), e.dataRef(v), e.dataRef(pattern[end]))
+				e.pattern(pattern[i+1 : end])
+				e.sb.WriteString("</pc>")
+
+				i = end
+			case parse.Close:
+				fmt.Fprintf(&e.sb, `<ec id="%s" dataRef="%s" isolated="yes"/>`, e.nextID(), e.dataRef(v))
+			}
+		}
+	}
+}
+
+// closingMarkup returns the index of the close markup matching the open markup at index i,
+// or -1 if the markup is not closed.
+func closingMarkup(pattern []parse.PatternPart, i int) int {
+	open, _ := pattern[i].(parse.Markup)
+	depth := 0
+
+	for j := i + 1; j < len(pattern); j++ {
+		m, ok := pattern[j].(parse.Markup)
+		if !ok || m.Identifier != open.Identifier {
+			continue
+		}
+
+		switch m.Typ { //nolint:exhaustive
+		case parse.Open:
+			depth++
+		case parse.Close:
+			if depth == 0 {
+				return j
+			}
+
+			depth--
+		}
+	}
+
+	return -1
+}
+
+// --------------------------------Decoding------------------------------------
+
+func decodeUnit(u xmlUnit) (Unit, error) {
+	unit := Unit{ID: u.ID}
+
+	if u.Notes != nil {
+		unit.Notes = u.Notes.Notes
+	}
+
+	data := make(map[string]string)
+
+	if u.OriginalData != nil {
+		for _, d := range u.OriginalData.Data {
+			data[d.ID] = d.Value
+		}
+	}
+
+	var (
+		groups   = make(map[string]xmlMetaGroup)
+		messages = make(map[string]xmlMetaGroup) // appliesTo -> message metadata
+	)
+
+	if u.Metadata != nil {
+		for _, group := range u.Metadata.Groups {
+			switch group.Category {
+			case categoryMessage:
+				messages[group.AppliesTo] = group
+			case categoryVariant:
+				groups[group.ID] = group
+			}
+		}
+	}
+
+	var (
+		source, target []decodedVariant
+		hasTarget      bool
+	)
+
+	for _, seg := range u.Segments {
+		group := groups[seg.ID]
+		keys := group.meta(metaKeys)
+
+		if group.AppliesTo != "target" {
+			pattern, err := decodeInline(seg.Source.Inner, data)
+			if err != nil {
+				return Unit{}, fmt.Errorf("segment %s source: %w", seg.ID, err)
+			}
+
+			source = append(source, decodedVariant{keys: keys, pattern: pattern})
+		}
+
+		if group.AppliesTo != "source" && seg.Target != nil {
+			pattern, err := decodeInline(seg.Target.Inner, data)
+			if err != nil {
+				return Unit{}, fmt.Errorf("segment %s target: %w", seg.ID, err)
+			}
+
+			hasTarget = true
+			target = append(target, decodedVariant{keys: keys, pattern: pattern})
+		}
+	}
+
+	var err error
+
+	sourceGroup, sourceComplex := messages["source"]
+	if unit.Source, err = buildMessage(sourceGroup, sourceComplex, source); err != nil {
+		return Unit{}, fmt.Errorf("source: %w", err)
+	}
+
+	if !hasTarget {
+		return unit, nil
+	}
+
+	targetGroup, targetComplex := messages["target"]
+	if unit.Target, err = buildMessage(targetGroup, targetComplex, target); err != nil {
+		return Unit{}, fmt.Errorf("target: %w", err)
+	}
+
+	return unit, nil
+}
+
+type decodedVariant struct {
+	keys    string
+	pattern parse.QuotedPattern
+}
+
+// buildMessage builds the message from the decoded metadata and variants.
+func buildMessage(group xmlMetaGroup, isComplex bool, variants []decodedVariant) (parse.AST, error) {
+	if !isComplex {
+		if len(variants) != 1 {
+			return parse.AST{}, fmt.Errorf("want single segment for simple message, got %d", len(variants))
+		}
+
+		if len(variants[0].pattern) == 0 {
+			return parse.AST{}, nil
+		}
+
+		return parse.AST{Message: parse.SimpleMessage(variants[0].pattern)}, nil
+	}
+
+	var sb strings.Builder
+
+	if declarations := group.meta(metaDeclarations); declarations != "" {
+		sb.WriteString(declarations + "\n")
+	}
+
+	selectors := group.meta(metaSelectors)
+
+	switch {
+	case selectors == "" && len(variants) != 1:
+		return parse.AST{}, fmt.Errorf("want single segment for quoted pattern, got %d", len(variants))
+	case selectors == "":
+		sb.WriteString(variants[0].pattern.String())
+	default:
+		sb.WriteString(".match " + selectors)
+
+		for _, v := range variants {
+			if v.keys == "" {
+				return parse.AST{}, errors.New("missing variant keys")
+			}
+
+			sb.WriteString("\n" + v.keys + " " + v.pattern.String())
+		}
+	}
+
+	tree, err := parse.Parse(sb.String())
+	if err != nil {
+		return parse.AST{}, fmt.Errorf("restore message: %w", err)
+	}
+
+	return tree, nil
+}
+
+// decodeInline decodes XLIFF inline content to the pattern.
+func decodeInline(inner string, data map[string]string) (parse.QuotedPattern, error) {
+	var pattern parse.QuotedPattern
+
+	node := func(ref string) (parse.PatternPart, error) {
+		s, ok := data[ref]
+		if !ok {
+			return nil, fmt.Errorf(`missing original data "%s"`, ref)
+		}
+
+		tree, err := parse.Parse(s)
+		if err != nil {
+			return nil, fmt.Errorf(`original data "%s": %w`, ref, err)
+		}
+
+		if m, ok := tree.Message.(parse.SimpleMessage); ok && len(m) == 1 {
+			switch part := m[0].(type) {
+			case parse.Expression, parse.Markup:
+				return part, nil
+			}
+		}
+
+		return nil, fmt.Errorf(`original data "%s": want expression or markup, got "%s"`, ref, s)
+	}
+
+	add := func(part parse.PatternPart) {
+		// merge adjacent text parts
+		if text, ok := part.(parse.Text); ok && len(pattern) > 0 {
+			if last, ok := pattern[len(pattern)-1].(parse.Text); ok {
+				pattern[len(pattern)-1] = last + text
+				return
+			}
+		}
+
+		pattern = append(pattern, part)
+	}
+
+	dec := xml.NewDecoder(strings.NewReader(inner))
+
+	// endRefs is a stack of closing markup data references of <pc> elements.
+	var endRefs []string
+
+	for {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("inline content: %w", err)
+		}
+
+		switch t := tok.(type) {
+		case xml.CharData:
+			add(parse.Text(t))
+		case xml.StartElement:
+			ref := attr(t, "dataRef")
+
+			switch t.Name.Local {
+			default:
+				return nil, fmt.Errorf(`unsupported inline element <%s>`, t.Name.Local)
+			case "pc":
+				ref = attr(t, "dataRefStart")
+				endRefs = append(endRefs, attr(t, "dataRefEnd"))
+			case "ph", "sc", "ec":
+			}
+
+			part, err := node(ref)
+			if err != nil {
+				return nil, err
+			}
+
+			add(part)
+		case xml.EndElement:
+			if t.Name.Local != "pc" {
+				continue
+			}
+
+			ref := endRefs[len(endRefs)-1]
+			endRefs = endRefs[:len(endRefs)-1]
+
+			part, err := node(ref)
+			if err != nil {
+				return nil, err
+			}
+
+			add(part)
+		}
+	}
+
+	return pattern, nil
+}
+
+func attr(el xml.StartElement, name string) string {
+	for _, a := range el.Attr {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+
+	return ""
+}
+
+// ---------------------------------XML----------------------------------------
+
+type xmlXLIFF struct {
+	XMLName xml.Name  `xml:"urn:oasis:names:tc:xliff:document:2.0 xliff"`
+	Version string    `xml:"version,attr"`
+	SrcLang string    `xml:"srcLang,attr"`
+	TrgLang string    `xml:"trgLang,attr,omitempty"`
+	File    []xmlFile `xml:"file"`
+}
+
+type xmlFile struct {
+	ID    string    `xml:"id,attr"`
+	Units []xmlUnit `xml:"unit"`
+}
+
+type xmlUnit struct {
+	Metadata     *xmlMetadata     `xml:"urn:oasis:names:tc:xliff:metadata:2.0 metadata"`
+	Notes        *xmlNotes        `xml:"notes"`
+	OriginalData *xmlOriginalData `xml:"originalData"`
+	ID           string           `xml:"id,attr"`
+	Segments     []xmlSegment     `xml:"segment"`
+}
+
+type xmlNotes struct {
+	Notes []string `xml:"note"`
+}
+
+type xmlMetadata struct {
+	Groups []xmlMetaGroup `xml:"metaGroup"`
+}
+
+type xmlMetaGroup struct {
+	ID        string    `xml:"id,attr,omitempty"`
+	Category  string    `xml:"category,attr"`
+	AppliesTo string    `xml:"appliesTo,attr,omitempty"`
+	Metas     []xmlMeta `xml:"meta"`
+}
+
+// meta returns the value of the meta by type.
+func (g xmlMetaGroup) meta(typ string) string {
+	for _, m := range g.Metas {
+		if m.Type == typ {
+			return m.Value
+		}
+	}
+
+	return ""
+}
+
+type xmlMeta struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+type xmlOriginalData struct {
+	Data []xmlData `xml:"data"`
+}
+
+type xmlData struct {
+	ID    string `xml:"id,attr"`
+	Value string `xml:",chardata"`
+}
+
+type xmlSegment struct {
+	ID     string      `xml:"id,attr,omitempty"`
+	Source xmlContent  `xml:"source"`
+	Target *xmlContent `xml:"target"` // must follow source
+}
+
+type xmlContent struct {
+	Inner string `xml:",innerxml"`
+}
+
+// helpers
+
+// joinNodes converts a slice of Nodes to a string, separated by sep.
+func joinNodes[T parse.Node](nodes []T, sep string) string {
+	s := make([]string, 0, len(nodes))
+	for _, v := range nodes {
+		s = append(s, v.String())
+	}
+
+	return strings.Join(s, sep)
+}
@@ -0,0 +1,177 @@
+package xliff
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+
+	"golang.org/x/text/language"
+
+	"go.expect.digital/mf2/parse"
+)
+
+func TestRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		name, source, target string
+		notes                []string
+	}{
+		{
+			name:   "empty",
+			source: "",
+		},
+		{
+			name:   "text only",
+			source: "Hello, World!",
+			target: "Sveika, pasaule!",
+			notes:  []string{"Greeting", "Shown on the home page"},
+		},
+		{
+			name:   "expressions",
+			source: "Hello, { $name :string }! You have { $count :number } <messages>.",
+			target: "Sveiki, { $name :string }! Jums ir { $count :number } <ziņas>.",
+		},
+		{
+			name:   "paired and unpaired markup",
+			source: "{ #b }Bold { #i }and italic{ /i }{ /b } { #br /} { /x }",
+			target: "{ #b }Trekns { #i }un kursīvs{ /i }{ /b } { #br /} { /x }",
+		},
+		{
+			name:   "declarations",
+			source: ".input { $name :string }\n.local $x = { |a b| }\n{{Hello, { $name } { $x }!}}",
+			target: ".input { $name :string }\n{{Sveiki, { $name }!}}",
+		},
+		{
+			name:   "matcher without target",
+			source: ".match { $count :number }\none {{{ $count } file}}\n* {{{ $count } files}}",
+		},
+		{
+			name:   "matcher with different target variants",
+			source: ".match { $count :number }\none {{{ $count } file}}\n* {{{ $count } files}}",
+			target: ".match { $count :number }\nzero {{{ $count } failu}}\none {{{ $count } fails}}\n* {{{ $count } faili}}",
+		},
+		{
+			name:   "matcher source, pattern target",
+			source: ".match { $a :string } { $b :string }\nx |y z| {{X}}\n* * {{Other}}",
+			target: "{{Tulkots}}",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			unit := Unit{ID: "msg", Source: mustParse(t, test.source), Notes: test.notes}
+			if test.target != "" {
+				unit.Target = mustParse(t, test.target)
+			}
+
+			doc := Document{SrcLang: language.English, TrgLang: language.Latvian, Units: []Unit{unit}}
+
+			var buf bytes.Buffer
+
+			if err := Encode(&buf, doc); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := Decode(&buf)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got.SrcLang != doc.SrcLang || got.TrgLang != doc.TrgLang {
+				t.Errorf("want languages %s/%s, got %s/%s", doc.SrcLang, doc.TrgLang, got.SrcLang, got.TrgLang)
+			}
+
+			if len(got.Units) != 1 {
+				t.Fatalf("want 1 unit, got %d", len(got.Units))
+			}
+
+			if want, got := unit.Source.String(), got.Units[0].Source.String(); want != got {
+				t.Errorf("source: want '%s', got '%s'", want, got)
+			}
+
+			if want, got := unit.Target.String(), got.Units[0].Target.String(); want != got {
+				t.Errorf("target: want '%s', got '%s'", want, got)
+			}
+
+			if want, got := unit.Notes, got.Units[0].Notes; !slices.Equal(want, got) {
+				t.Errorf("notes: want %v, got %v", want, got)
+			}
+		})
+	}
+}
+
+func TestEncode(t *testing.T) {
+	t.Parallel()
+
+	doc := Document{
+		SrcLang: language.English,
+		Units: []Unit{
+			{
+				ID:     "greeting",
+				Source: mustParse(t, "Hello, { $name }! { #b }Welcome{ /b }"),
+				Notes:  []string{"Home page", "Informal & short"},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+
+	if err := Encode(&buf, doc); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{
+		`<source>Hello, <ph id="1" dataRef="d1"/>! <pc id="2" dataRefStart="d2" dataRefEnd="d3">Welcome</pc></source>`,
+		`<data id="d1">{ $name }</data>`,
+		`<data id="d2">{ #b }</data>`,
+		`<data id="d3">{ /b }</data>`,
+		`<note>Informal &amp; short</note>`,
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("want '%s' in\n%s", want, buf.String())
+		}
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		name, input, wantErr string
+	}{
+		{
+			name:    "version",
+			input:   `<xliff xmlns="urn:oasis:names:tc:xliff:document:2.0" version="1.2" srcLang="en"></xliff>`,
+			wantErr: `decode XLIFF: want version 2.x, got "1.2"`,
+		},
+		{
+			name: "missing original data",
+			input: `<xliff xmlns="urn:oasis:names:tc:xliff:document:2.0" version="2.0" srcLang="en">
+<file id="f1"><unit id="u1"><segment><source>Hi <ph id="1" dataRef="d1"/></source></segment></unit></file>
+</xliff>`,
+			wantErr: `decode XLIFF: unit u1: segment  source: missing original data "d1"`,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := Decode(strings.NewReader(test.input))
+			if err == nil || err.Error() != test.wantErr {
+				t.Errorf("want '%s', got '%v'", test.wantErr, err)
+			}
+		})
+	}
+}
+
+func mustParse(t *testing.T, s string) parse.AST {
+	t.Helper()
+
+	tree, err := parse.Parse(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return tree
+}
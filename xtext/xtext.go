@@ -0,0 +1,365 @@
+/*
+Package xtext registers MF2 messages in a golang.org/x/text/message/catalog.Builder,
+so code using message.Printer can use MF2 messages without changes.
+
+message.Printer passes arguments by position. The position of the MF2 variable is the order
+of .input declarations, followed by the remaining variables in order of their first appearance.
+
+Example:
+
+	.input { $count :number }
+	.input { $name :string }
+	.match { $count }
+	one {{{ $name } has one apple}}
+	*   {{{ $name } has { $count } apples}}
+
+	p.Sprintf("apples", 3, "John") // John has 3 apples
+
+Only the subset of MF2 expressible by x/text is supported: variables formatted with
+:number, :integer or :string without options, and matchers on :number or :integer selectors
+with plural category or integer keys.
+*/
+package xtext
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"golang.org/x/text/feature/plural"
+	"golang.org/x/text/language"
+	xcatalog "golang.org/x/text/message/catalog"
+
+	"go.expect.digital/mf2"
+	"go.expect.digital/mf2/catalog"
+	"go.expect.digital/mf2/internal/literal"
+	"go.expect.digital/mf2/parse"
+)
+
+// Register converts all messages of the catalog and adds them to the x/text catalog builder
+// in the locale of the catalog.
+func Register(b *xcatalog.Builder, c *catalog.Catalog) error {
+	for _, id := range c.IDs() {
+		msg, _ := c.Message(id)
+
+		if err := Set(b, c.Locale(), id, msg.Text); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Set converts the MF2 message and adds it to the x/text catalog builder.
+func Set(b *xcatalog.Builder, locale language.Tag, key, text string) error {
+	tree, err := parse.Parse(text)
+	if err != nil {
+		return fmt.Errorf(`convert message "%s": %w`, key, err)
+	}
+
+	msg, err := Message(tree)
+	if err != nil {
+		return fmt.Errorf(`convert message "%s": %w`, key, err)
+	}
+
+	if err := b.Set(locale, key, msg); err != nil {
+		return fmt.Errorf(`set message "%s": %w`, key, err)
+	}
+
+	return nil
+}
+
+// Message converts the MF2 message to the x/text catalog message.
+func Message(tree parse.AST) (xcatalog.Message, error) { //nolint:ireturn
+	c := &converter{functions: make(map[string]string)}
+
+	switch m := tree.Message.(type) {
+	default:
+		return nil, fmt.Errorf("unsupported message type %T", m)
+	case nil:
+		return xcatalog.String(""), nil
+	case parse.SimpleMessage:
+		c.collect(m)
+
+		s, err := c.pattern(m)
+		if err != nil {
+			return nil, err
+		}
+
+		return xcatalog.String(s), nil
+	case parse.ComplexMessage:
+		if err := c.declarations(m.Declarations); err != nil {
+			return nil, err
+		}
+
+		c.collect(m)
+
+		switch body := m.ComplexBody.(type) {
+		case parse.QuotedPattern:
+			s, err := c.pattern(body)
+			if err != nil {
+				return nil, err
+			}
+
+			return xcatalog.String(s), nil
+		case parse.Matcher:
+			return c.matcher(body.Selectors, body.Variants)
+		}
+	}
+
+	return nil, errors.New("missing complex body")
+}
+
+// converter converts MF2 message nodes to x/text format strings and messages.
+type converter struct {
+	functions map[string]string // variable -> function name from .input declaration
+	args      []string          // argument names by position
+}
+
+// arg returns the position of the variable in arguments, starting from 1.
+func (c *converter) arg(v parse.Variable) int {
+	if i := slices.Index(c.args, string(v)); i >= 0 {
+		return i + 1
+	}
+
+	c.args = append(c.args, string(v))
+
+	return len(c.args)
+}
+
+func (c *converter) declarations(declarations []parse.Declaration) error {
+	for _, decl := range declarations {
+		switch d := decl.(type) {
+		default:
+			return fmt.Errorf("%w: %s", mf2.ErrUnsupportedStatement, d)
+		case parse.InputDeclaration:
+			v, _ := d.Operand.(parse.Variable)
+			c.arg(v)
+
+			if d.Annotation == nil {
+				continue
+			}
+
+			f, ok := d.Annotation.(parse.Function)
+			if !ok || len(f.Options) > 0 || len(d.Attributes) > 0 {
+				return fmt.Errorf("%w: %s", mf2.ErrUnsupportedExpression, d)
+			}
+
+			c.functions[string(v)] = f.Identifier.Name
+		}
+	}
+
+	return nil
+}
+
+// collect assigns argument positions to variables in order of their appearance.
+func (c *converter) collect(node parse.Node) {
+	switch n := node.(type) {
+	case parse.SimpleMessage:
+		for _, part := range n {
+			c.collect(part)
+		}
+	case parse.ComplexMessage:
+		c.collect(n.ComplexBody)
+	case parse.QuotedPattern:
+		for _, part := range n {
+			c.collect(part)
+		}
+	case parse.Matcher:
+		for _, selector := range n.Selectors {
+			c.collect(selector)
+		}
+
+		for _, variant := range n.Variants {
+			c.collect(variant.QuotedPattern)
+		}
+	case parse.Expression:
+		if v, ok := n.Operand.(parse.Variable); ok {
+			c.arg(v)
+		}
+	}
+}
+
+// function returns the function name and argument position of the expression.
+func (c *converter) function(expr parse.Expression) (string, int, error) {
+	v, ok := expr.Operand.(parse.Variable)
+	if !ok {
+		return "", 0, fmt.Errorf("%w: want variable operand: %s", mf2.ErrUnsupportedExpression, expr)
+	}
+
+	switch a := expr.Annotation.(type) {
+	default:
+		return "", 0, fmt.Errorf("%w: %s", mf2.ErrUnsupportedExpression, expr)
+	case nil:
+		return c.functions[string(v)], c.arg(v), nil
+	case parse.Function:
+		if len(a.Options) > 0 {
+			return "", 0, fmt.Errorf("%w: options are not supported: %s", mf2.ErrUnsupportedExpression, expr)
+		}
+
+		return a.Identifier.Name, c.arg(v), nil
+	}
+}
+
+// pattern converts the pattern to the x/text format string.
+func (c *converter) pattern(pattern []parse.PatternPart) (string, error) {
+	var sb strings.Builder
+
+	for _, part := range pattern {
+		switch p := part.(type) {
+		case parse.Text:
+			sb.WriteString(strings.ReplaceAll(string(p), "%", "%%"))
+		case parse.Markup: // markup is formatted as empty string
+		case parse.Expression:
+			if l, ok := p.Operand.(parse.Literal); ok && p.Annotation == nil {
+				sb.WriteString(strings.ReplaceAll(literal.Value(l), "%", "%%"))
+				continue
+			}
+
+			function, arg, err := c.function(p)
+			if err != nil {
+				return "", err
+			}
+
+			var verb string
+
+			switch function {
+			default:
+				return "", fmt.Errorf(`%w "%s"`, mf2.ErrUnknownFunction, function)
+			case "", "number":
+				verb = "v"
+			case "integer":
+				verb = "d"
+			case "string":
+				verb = "s"
+			}
+
+			sb.WriteString("%[" + strconv.Itoa(arg) + "]" + verb)
+		}
+	}
+
+	return sb.String(), nil
+}
+
+// matcher converts the matcher to nested plural selections, one per selector.
+func (c *converter) matcher(selectors []parse.Expression, variants []parse.Variant) (xcatalog.Message, error) { //nolint:ireturn,lll
+	if len(selectors) == 0 {
+		s, err := c.pattern(variants[0].QuotedPattern)
+		if err != nil {
+			return nil, err
+		}
+
+		return xcatalog.String(s), nil
+	}
+
+	function, arg, err := c.function(selectors[0])
+	if err != nil {
+		return nil, fmt.Errorf("selector: %w", err)
+	}
+
+	var format string
+
+	switch function {
+	default:
+		return nil, fmt.Errorf(`selector: %w: only :number and :integer selectors are supported, got "%s"`,
+			mf2.ErrUnsupportedExpression, function)
+	case "number":
+	case "integer":
+		format = "%d"
+	}
+
+	// Variants with the catch-all key are added to every case as the fallback.
+	var keys []string
+
+	for _, variant := range variants {
+		key, err := pluralKey(variant.Keys[0])
+		if err != nil {
+			return nil, err
+		}
+
+		if !slices.Contains(keys, key) {
+			keys = append(keys, key)
+		}
+	}
+
+	// exact matches first, catch-all last
+	slices.SortStableFunc(keys, func(a, b string) int { return keyOrder(a) - keyOrder(b) })
+
+	cases := make([]any, 0, len(keys)*2) //nolint:mnd
+
+	for _, key := range keys {
+		var matched []parse.Variant
+
+		for _, variant := range variants {
+			if k, _ := pluralKey(variant.Keys[0]); k == key {
+				matched = append(matched, parse.Variant{Keys: variant.Keys[1:], QuotedPattern: variant.QuotedPattern})
+			}
+		}
+
+		if key != catchAll {
+			for _, variant := range variants {
+				if _, ok := variant.Keys[0].(parse.CatchAllKey); ok {
+					matched = append(matched, parse.Variant{Keys: variant.Keys[1:], QuotedPattern: variant.QuotedPattern})
+				}
+			}
+		}
+
+		msg, err := c.matcher(selectors[1:], matched)
+		if err != nil {
+			return nil, err
+		}
+
+		if key == catchAll {
+			key = "other"
+		}
+
+		cases = append(cases, key, msg)
+	}
+
+	return plural.Selectf(arg, format, cases...), nil
+}
+
+const catchAll = "*"
+
+// pluralKey converts the variant key to the x/text plural selector.
+func pluralKey(key parse.VariantKey) (string, error) {
+	switch k := key.(type) {
+	case parse.CatchAllKey:
+		return catchAll, nil
+	case parse.NumberLiteral:
+		if float64(k) != float64(int(k)) {
+			return "", fmt.Errorf("%w: want integer key, got %s", mf2.ErrBadVariantKey, k)
+		}
+
+		return "=" + k.String(), nil
+	case parse.Literal:
+		s := literal.Value(k)
+
+		switch s {
+		case "zero", "one", "two", "few", "many", "other":
+			return s, nil
+		}
+
+		if _, err := strconv.Atoi(s); err == nil {
+			return "=" + s, nil
+		}
+
+		return "", fmt.Errorf(`%w: want plural category or integer, got "%s"`, mf2.ErrBadVariantKey, s)
+	}
+
+	return "", fmt.Errorf("%w: %s", mf2.ErrBadVariantKey, key)
+}
+
+// keyOrder sorts exact keys before plural categories and the catch-all key.
+func keyOrder(key string) int {
+	switch {
+	case strings.HasPrefix(key, "="):
+		return 0
+	case key == catchAll:
+		return 2 //nolint:mnd
+	default:
+		return 1
+	}
+}
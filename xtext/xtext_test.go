@@ -0,0 +1,78 @@
+package xtext
+
+import (
+	"errors"
+	"testing"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	xcatalog "golang.org/x/text/message/catalog"
+
+	"go.expect.digital/mf2"
+	"go.expect.digital/mf2/catalog"
+)
+
+func TestRegister(t *testing.T) {
+	t.Parallel()
+
+	c := catalog.New(language.English)
+	c.Set("greeting", "Hello, { $name }! 100% { |sure| }.")
+	c.Set("apples", `.input { $count :integer }
+.input { $name :string }
+.match { $count }
+0 {{{ $name } has no apples}}
+one {{{ $name } has one apple}}
+* {{{ $name } has { $count } apples}}`)
+	c.Set("pets", `.match { $cats :integer } { $dogs :integer }
+one one {{one cat and one dog}}
+one * {{one cat and { $dogs } dogs}}
+* one {{{ $cats } cats and one dog}}
+* * {{{ $cats } cats and { $dogs } dogs}}`)
+
+	b := xcatalog.NewBuilder()
+
+	if err := Register(b, c); err != nil {
+		t.Fatal(err)
+	}
+
+	p := message.NewPrinter(language.English, message.Catalog(b))
+
+	for _, test := range []struct {
+		key, want string
+		args      []any
+	}{
+		{key: "greeting", args: []any{"World"}, want: "Hello, World! 100% sure."},
+		{key: "apples", args: []any{0, "John"}, want: "John has no apples"},
+		{key: "apples", args: []any{1, "John"}, want: "John has one apple"},
+		{key: "apples", args: []any{1000, "John"}, want: "John has 1,000 apples"},
+		{key: "pets", args: []any{1, 1}, want: "one cat and one dog"},
+		{key: "pets", args: []any{1, 3}, want: "one cat and 3 dogs"},
+		{key: "pets", args: []any{2, 1}, want: "2 cats and one dog"},
+		{key: "pets", args: []any{2, 3}, want: "2 cats and 3 dogs"},
+	} {
+		if got := p.Sprintf(test.key, test.args...); test.want != got {
+			t.Errorf("%s %v: want '%s', got '%s'", test.key, test.args, test.want, got)
+		}
+	}
+}
+
+func TestSetErrors(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		text    string
+		wantErr error
+	}{
+		{text: "Hello { $name", wantErr: mf2.ErrSyntax},
+		{text: ".local $x = { 1 } {{{ $x }}}", wantErr: mf2.ErrUnsupportedStatement},
+		{text: "{ $x :number style=percent }", wantErr: mf2.ErrUnsupportedExpression},
+		{text: "{ $x :datetime }", wantErr: mf2.ErrUnknownFunction},
+		{text: ".match { $x :string } a {{a}} * {{other}}", wantErr: mf2.ErrUnsupportedExpression},
+		{text: ".match { $x :number } 1.5 {{a}} * {{other}}", wantErr: mf2.ErrBadVariantKey},
+	} {
+		err := Set(xcatalog.NewBuilder(), language.English, "key", test.text)
+		if !errors.Is(err, test.wantErr) {
+			t.Errorf("%s: want '%s', got '%s'", test.text, test.wantErr, err)
+		}
+	}
+}